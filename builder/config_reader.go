@@ -26,6 +26,9 @@ type Config struct {
 	Run             RunConfig        `toml:"run"`
 	Build           BuildConfig      `toml:"build"`
 	Targets         []dist.Target    `toml:"targets"`
+
+	// Architectures overrides the build image and buildpacks for the targets of an architecture
+	Architectures []ArchitectureConfig `toml:"architectures"`
 }
 
 // ModuleCollection is a list of ModuleConfigs
@@ -70,6 +73,25 @@ type RunImageConfig struct {
 	Mirrors []string `toml:"mirrors,omitempty"`
 }
 
+// ArchitectureConfig is the build image and buildpacks of the builder for the targets of an architecture, e.g. when
+// the build image or a buildpack isn't a multi-platform image but is published for each architecture
+type ArchitectureConfig struct {
+	Arch        string `toml:"arch"`
+	ArchVariant string `toml:"variant,omitempty"`
+
+	// BuildImage replaces build.image for the architecture
+	BuildImage string `toml:"build-image,omitempty"`
+
+	// Buildpacks replace the buildpacks with the same ID, and are added when no buildpack has their ID
+	Buildpacks ModuleCollection `toml:"buildpacks,omitempty"`
+}
+
+// matches is true when the architecture config applies to target, i.e. it has the architecture of target, and its
+// variant unless it applies to every variant
+func (a ArchitectureConfig) matches(target dist.Target) bool {
+	return a.Arch == target.Arch && (a.ArchVariant == "" || a.ArchVariant == target.ArchVariant)
+}
+
 // BuildConfig build image configuration
 type BuildConfig struct {
 	Image string           `toml:"image"`
@@ -138,9 +160,61 @@ func ValidateConfig(c Config) error {
 		return errors.New("run.images and stack.run-image do not match")
 	}
 
+	architectures := map[string]bool{}
+	for _, arch := range c.Architectures {
+		if arch.Arch == "" {
+			return errors.New("architectures.arch is required")
+		}
+		platform := strings.TrimSuffix(arch.Arch+"/"+arch.ArchVariant, "/")
+		if architectures[platform] {
+			return errors.Errorf("architecture %s is configured more than once", style.Symbol(platform))
+		}
+		architectures[platform] = true
+
+		if arch.BuildImage == "" && len(arch.Buildpacks) == 0 {
+			return errors.Errorf("architecture %s requires a build-image or buildpacks", style.Symbol(platform))
+		}
+		for _, bp := range arch.Buildpacks {
+			if bp.ID == "" {
+				return errors.Errorf("buildpacks of architecture %s require an id", style.Symbol(platform))
+			}
+		}
+	}
+
 	return nil
 }
 
+// ForTarget returns the config of the builder for target, with the build image and buildpacks of the architecture
+// configs that apply to it, in order
+func (c Config) ForTarget(target dist.Target) Config {
+	buildpacks := append(ModuleCollection{}, c.Buildpacks...)
+	for _, arch := range c.Architectures {
+		if !arch.matches(target) {
+			continue
+		}
+
+		if arch.BuildImage != "" {
+			c.Build.Image = arch.BuildImage
+			if c.Stack.BuildImage != "" {
+				c.Stack.BuildImage = arch.BuildImage
+			}
+		}
+
+	archBuildpacks:
+		for _, bp := range arch.Buildpacks {
+			for i := range buildpacks {
+				if buildpacks[i].ID == bp.ID {
+					buildpacks[i] = bp
+					continue archBuildpacks
+				}
+			}
+			buildpacks = append(buildpacks, bp)
+		}
+	}
+	c.Buildpacks = buildpacks
+	return c
+}
+
 func (c *Config) mergeStackWithImages() {
 	// RFC-0096
 	if c.Build.Image != "" {
@@ -10,6 +10,7 @@ import (
 	"github.com/sclevine/spec/report"
 
 	"github.com/buildpacks/pack/builder"
+	"github.com/buildpacks/pack/pkg/dist"
 	h "github.com/buildpacks/pack/testhelpers"
 )
 
@@ -79,6 +80,39 @@ func testConfig(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("architectures are configured", func() {
+			it.Before(func() {
+				h.AssertNil(t, os.WriteFile(builderConfigPath, []byte(`
+[[buildpacks]]
+  id = "buildpack/1"
+  uri = "https://example.com/buildpack-1.tgz"
+
+[[architectures]]
+  arch = "arm64"
+  build-image = "example.com/build:arm64"
+
+[[architectures.buildpacks]]
+  id = "buildpack/1"
+  uri = "https://example.com/buildpack-1-arm64.tgz"
+
+[[order]]
+[[order.group]]
+  id = "buildpack/1"
+`), 0666))
+			})
+
+			it("returns the build image and buildpacks of each architecture", func() {
+				builderConfig, _, err := builder.ReadConfig(builderConfigPath)
+				h.AssertNil(t, err)
+
+				h.AssertEq(t, len(builderConfig.Architectures), 1)
+				h.AssertEq(t, builderConfig.Architectures[0].Arch, "arm64")
+				h.AssertEq(t, builderConfig.Architectures[0].BuildImage, "example.com/build:arm64")
+				h.AssertEq(t, builderConfig.Architectures[0].Buildpacks[0].ID, "buildpack/1")
+				h.AssertEq(t, builderConfig.Architectures[0].Buildpacks[0].URI, "https://example.com/buildpack-1-arm64.tgz")
+			})
+		})
+
 		when("an error occurs while reading", func() {
 			it("bubbles up the error", func() {
 				_, _, err := builder.ReadConfig(builderConfigPath)
@@ -228,6 +262,91 @@ uri = "noop-buildpack.tgz"
 			config := builder.Config{}
 			h.AssertError(t, builder.ValidateConfig(config), "build.image is required")
 		})
+
+		when("architectures are configured", func() {
+			var config builder.Config
+
+			it.Before(func() {
+				config = builder.Config{
+					Build: builder.BuildConfig{Image: testBuildImage},
+					Run:   builder.RunConfig{Images: []builder.RunImageConfig{{Image: testRunImage}}},
+				}
+			})
+
+			it("returns error if an architecture has no arch", func() {
+				config.Architectures = []builder.ArchitectureConfig{{BuildImage: testBuildImage}}
+				h.AssertError(t, builder.ValidateConfig(config), "architectures.arch is required")
+			})
+
+			it("returns error if an architecture is configured twice", func() {
+				config.Architectures = []builder.ArchitectureConfig{
+					{Arch: "arm64", ArchVariant: "v8", BuildImage: testBuildImage},
+					{Arch: "arm64", ArchVariant: "v8", BuildImage: testBuildImage},
+				}
+				h.AssertError(t, builder.ValidateConfig(config), "architecture 'arm64/v8' is configured more than once")
+			})
+
+			it("returns error if an architecture overrides nothing", func() {
+				config.Architectures = []builder.ArchitectureConfig{{Arch: "arm64"}}
+				h.AssertError(t, builder.ValidateConfig(config), "architecture 'arm64' requires a build-image or buildpacks")
+			})
+
+			it("returns error if a buildpack of an architecture has no id", func() {
+				config.Architectures = []builder.ArchitectureConfig{{
+					Arch:       "arm64",
+					Buildpacks: builder.ModuleCollection{{ImageOrURI: dist.ImageOrURI{BuildpackURI: dist.BuildpackURI{URI: "some-uri"}}}},
+				}}
+				h.AssertError(t, builder.ValidateConfig(config), "buildpacks of architecture 'arm64' require an id")
+			})
+		})
+	})
+
+	when("#ForTarget", func() {
+		var config builder.Config
+
+		module := func(id, uri string) builder.ModuleConfig {
+			return builder.ModuleConfig{
+				ModuleInfo: dist.ModuleInfo{ID: id},
+				ImageOrURI: dist.ImageOrURI{BuildpackURI: dist.BuildpackURI{URI: uri}},
+			}
+		}
+
+		it.Before(func() {
+			config = builder.Config{
+				Build:      builder.BuildConfig{Image: "some/build"},
+				Stack:      builder.StackConfig{BuildImage: "some/build"},
+				Buildpacks: builder.ModuleCollection{module("bp/one", "one.tgz"), module("bp/two", "two.tgz")},
+				Architectures: []builder.ArchitectureConfig{
+					{Arch: "arm64", BuildImage: "some/build:arm64", Buildpacks: builder.ModuleCollection{module("bp/two", "two-arm64.tgz"), module("bp/three", "three-arm64.tgz")}},
+					{Arch: "arm", ArchVariant: "v7", BuildImage: "some/build:armv7"},
+				},
+			}
+		})
+
+		it("uses the build image and buildpacks of the architecture of the target", func() {
+			armConfig := config.ForTarget(dist.Target{OS: "linux", Arch: "arm64"})
+
+			h.AssertEq(t, armConfig.Build.Image, "some/build:arm64")
+			h.AssertEq(t, armConfig.Stack.BuildImage, "some/build:arm64")
+			h.AssertEq(t, armConfig.Buildpacks, builder.ModuleCollection{
+				module("bp/one", "one.tgz"),
+				module("bp/two", "two-arm64.tgz"),
+				module("bp/three", "three-arm64.tgz"),
+			})
+		})
+
+		it("leaves the config of other architectures alone", func() {
+			amdConfig := config.ForTarget(dist.Target{OS: "linux", Arch: "amd64"})
+
+			h.AssertEq(t, amdConfig.Build.Image, "some/build")
+			h.AssertEq(t, amdConfig.Buildpacks, config.Buildpacks)
+			h.AssertEq(t, config.Buildpacks[1], module("bp/two", "two.tgz"))
+		})
+
+		it("matches the variant of the target", func() {
+			h.AssertEq(t, config.ForTarget(dist.Target{OS: "linux", Arch: "arm", ArchVariant: "v7"}).Build.Image, "some/build:armv7")
+			h.AssertEq(t, config.ForTarget(dist.Target{OS: "linux", Arch: "arm", ArchVariant: "v6"}).Build.Image, "some/build")
+		})
 	})
 	when("#ParseBuildConfigEnv()", func() {
 		it("should return an error when name is not defined", func() {
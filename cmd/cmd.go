@@ -1,6 +1,15 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver"
 	"github.com/heroku/color"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -9,9 +18,16 @@ import (
 	builderwriter "github.com/buildpacks/pack/internal/builder/writer"
 	"github.com/buildpacks/pack/internal/commands"
 	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/i18n"
 	imagewriter "github.com/buildpacks/pack/internal/inspectimage/writer"
+	"github.com/buildpacks/pack/internal/logfile"
+	"github.com/buildpacks/pack/internal/plugin"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/internal/telemetry"
 	"github.com/buildpacks/pack/internal/term"
+	"github.com/buildpacks/pack/internal/update"
 	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/errcode"
 	"github.com/buildpacks/pack/pkg/logging"
 )
 
@@ -33,6 +49,10 @@ func NewPackCommand(logger ConfigurableLogger) (*cobra.Command, error) {
 		return nil, err
 	}
 
+	if err := i18n.SetLocale(i18n.DetectLocale(cfg.Locale, os.Getenv)); err != nil {
+		return nil, err
+	}
+
 	packClient, err := initClient(logger, cfg)
 	if err != nil {
 		return nil, err
@@ -40,18 +60,19 @@ func NewPackCommand(logger ConfigurableLogger) (*cobra.Command, error) {
 
 	rootCmd := &cobra.Command{
 		Use:   "pack",
-		Short: "CLI for building apps using Cloud Native Buildpacks",
+		Short: i18n.T(i18n.RootShort),
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
 			if fs := cmd.Flags(); fs != nil {
-				if forceColor, err := fs.GetBool("force-color"); err == nil && !forceColor {
-					if flag, err := fs.GetBool("no-color"); err == nil && flag {
-						color.Disable(flag)
-					}
-
-					_, canDisplayColor := term.IsTerminal(logging.GetWriterForLevel(logger, logging.InfoLevel))
-					if !canDisplayColor {
-						color.Disable(true)
-					}
+				forceColor, _ := fs.GetBool("force-color")
+				noColor, _ := fs.GetBool("no-color")
+				color.Disable(!term.WantColor(logging.GetWriterForLevel(logger, logging.InfoLevel), noColor, forceColor, os.Getenv))
+
+				theme := cfg.ColorTheme
+				if flag, err := fs.GetString("color-theme"); err == nil && flag != "" {
+					theme = flag
+				}
+				if err := style.SetTheme(theme); err != nil {
+					logger.Warnf("%s, using the %s theme", err.Error(), style.Symbol(style.DefaultTheme))
 				}
 				if flag, err := fs.GetBool("quiet"); err == nil {
 					logger.WantQuiet(flag)
@@ -62,21 +83,29 @@ func NewPackCommand(logger ConfigurableLogger) (*cobra.Command, error) {
 				if flag, err := fs.GetBool("timestamps"); err == nil {
 					logger.WantTime(flag)
 				}
+				if flag, _ := fs.GetBool("save-logs"); flag || cfg.SaveLogs {
+					saveLogs(logger, cmd)
+				}
 			}
 		},
 	}
 
-	rootCmd.PersistentFlags().Bool("no-color", false, "Disable color output")
-	rootCmd.PersistentFlags().Bool("force-color", false, "Force color output")
+	rootCmd.PersistentFlags().Bool("no-color", false, "Disable color output (also disabled by setting NO_COLOR)")
+	rootCmd.PersistentFlags().Bool("force-color", false, "Force color output (also forced by setting CLICOLOR_FORCE)")
+	rootCmd.PersistentFlags().String("color-theme", "", fmt.Sprintf("Color theme to use, one of %s (defaults to the theme set by `pack config color-theme`)", strings.Join(style.Themes(), ", ")))
 	rootCmd.PersistentFlags().Bool("timestamps", false, "Enable timestamps in output")
-	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "Show less output")
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "Show less output; build, rebase and package only print the resulting image reference")
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Show more output")
+	rootCmd.PersistentFlags().Bool("save-logs", false, "Also write all output to a timestamped file under PACK_HOME/logs (also enabled by setting save-logs = true in the config)")
 	rootCmd.Flags().Bool("version", false, "Show current 'pack' version")
 
 	commands.AddHelpFlag(rootCmd, "pack")
+	commands.AddErrorFormatFlag(rootCmd)
+	commands.AddWarningsAsErrorsFlag(rootCmd, logger)
 
 	rootCmd.AddCommand(commands.Build(logger, cfg, packClient))
 	rootCmd.AddCommand(commands.NewBuilderCommand(logger, cfg, packClient))
+	rootCmd.AddCommand(commands.NewLifecycleCommand(logger, cfg, packClient))
 	rootCmd.AddCommand(commands.NewBuildpackCommand(logger, cfg, packClient, buildpackage.NewConfigReader()))
 	rootCmd.AddCommand(commands.NewExtensionCommand(logger, cfg, packClient, buildpackage.NewConfigReader()))
 	rootCmd.AddCommand(commands.NewConfigCommand(logger, cfg, cfgPath, packClient))
@@ -84,6 +113,16 @@ func NewPackCommand(logger ConfigurableLogger) (*cobra.Command, error) {
 	rootCmd.AddCommand(commands.NewStackCommand(logger))
 	rootCmd.AddCommand(commands.Rebase(logger, cfg, packClient))
 	rootCmd.AddCommand(commands.NewSBOMCommand(logger, cfg, packClient))
+	rootCmd.AddCommand(commands.NewImageCommand(logger, cfg, packClient))
+	rootCmd.AddCommand(commands.NewBundleCommand(logger, cfg, packClient))
+	rootCmd.AddCommand(commands.Compose(logger, cfg, packClient))
+	rootCmd.AddCommand(commands.Dev(logger, cfg, packClient))
+	rootCmd.AddCommand(commands.Prune(logger, cfg, packClient))
+	rootCmd.AddCommand(commands.NewCacheCommand(logger, packClient))
+	rootCmd.AddCommand(commands.NewProjectCommand(logger, cfg, packClient))
+	rootCmd.AddCommand(commands.NewRegistryCommand(logger, cfg, packClient))
+	rootCmd.AddCommand(commands.NewMigrateCommand(logger))
+	rootCmd.AddCommand(commands.NewPluginCommand(logger))
 
 	rootCmd.AddCommand(commands.InspectBuildpack(logger, cfg, packClient))
 	rootCmd.AddCommand(commands.InspectBuilder(logger, cfg, packClient, builderwriter.NewFactory()))
@@ -113,8 +152,11 @@ func NewPackCommand(logger ConfigurableLogger) (*cobra.Command, error) {
 	}
 
 	rootCmd.AddCommand(commands.CompletionCommand(logger, packHome))
+	rootCmd.AddCommand(commands.Doctor(logger, cfg, packHome, packClient))
+	rootCmd.AddCommand(commands.NewDebugCommand(logger, cfgPath, packClient))
 	rootCmd.AddCommand(commands.Report(logger, packClient.Version(), cfgPath))
 	rootCmd.AddCommand(commands.Version(logger, packClient.Version()))
+	rootCmd.AddCommand(commands.Update(logger, cfg, packClient.Version()))
 
 	rootCmd.Version = packClient.Version()
 	rootCmd.SetVersionTemplate(`{{.Version}}{{"\n"}}`)
@@ -124,6 +166,129 @@ func NewPackCommand(logger ConfigurableLogger) (*cobra.Command, error) {
 	return rootCmd, nil
 }
 
+// saveLogs tees the output of the command to a new log file under PACK_HOME/logs
+func saveLogs(logger ConfigurableLogger, cmd *cobra.Command) {
+	fileLogger, ok := logger.(interface{ WantLogFile(w io.Writer) })
+	if !ok {
+		return
+	}
+
+	packHome, err := config.PackHome()
+	if err != nil {
+		logger.Warnf("Unable to save logs: %s", err)
+		return
+	}
+
+	file, err := logfile.Open(filepath.Join(packHome, "logs"), time.Now())
+	if err != nil {
+		logger.Warnf("Unable to save logs: %s", err)
+		return
+	}
+
+	fmt.Fprintf(file, "%s\n", cmd.CommandPath())
+	fileLogger.WantLogFile(file)
+	logger.Debugf("Saving logs to %s", style.Symbol(file.Name()))
+}
+
+// CloseLogs closes the file the output was saved to with --save-logs, if any. Nothing is written to it afterwards.
+func CloseLogs(logger logging.Logger) {
+	fileLogger, ok := logger.(interface {
+		LogFile() io.Writer
+		WantLogFile(w io.Writer)
+	})
+	if !ok {
+		return
+	}
+
+	if file, ok := fileLogger.LogFile().(io.Closer); ok {
+		fileLogger.WantLogFile(nil)
+		if err := file.Close(); err != nil {
+			logger.Warnf("Unable to save logs: %s", err)
+		}
+	}
+}
+
+// FindPlugin returns the plugin that handles args, when they don't start with a command of pack. Plugins are
+// executables named pack-<name> on PATH.
+func FindPlugin(rootCmd *cobra.Command, args []string) (plugin.Plugin, bool) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") || args[0] == "help" {
+		return plugin.Plugin{}, false
+	}
+	if found, _, err := rootCmd.Find(args); err == nil && found != rootCmd {
+		return plugin.Plugin{}, false
+	}
+	return plugin.Lookup(args[0])
+}
+
+// RunPlugin runs a plugin with the arguments that follow its name, passing it the location of the pack config
+func RunPlugin(ctx context.Context, p plugin.Plugin, args []string, version string) error {
+	cfg, cfgPath, err := initConfig()
+	if err != nil {
+		return err
+	}
+
+	packHome, err := config.PackHome()
+	if err != nil {
+		return err
+	}
+
+	return p.Run(ctx, args, plugin.Context{
+		Version:      version,
+		ConfigPath:   cfgPath,
+		Home:         packHome,
+		Experimental: cfg.Experimental,
+	})
+}
+
+// ReportTelemetry reports anonymous usage data for the executed command when the user opted in with `pack config telemetry`
+func ReportTelemetry(ctx context.Context, logger logging.Logger, executed *cobra.Command, duration time.Duration, err error) {
+	cfg, _, cfgErr := initConfig()
+	if cfgErr != nil || executed == nil || !telemetry.Enabled(cfg.Telemetry, cfg.TelemetryEndpoint, os.Getenv) {
+		return
+	}
+
+	var code, name string
+	if err != nil {
+		code, name = string(errcode.Of(err)), errcode.Of(err).Name()
+	}
+
+	event := telemetry.NewEvent(executed.CommandPath(), duration, code, name, executed.Root().Version)
+	if err := telemetry.NewReporter(cfg.TelemetryEndpoint).Report(ctx, event); err != nil {
+		logger.Debugf("Unable to report telemetry: %s", err)
+	}
+}
+
+// NotifyUpdate prints a notice when a newer version of pack is available, for users who opted in with
+// `pack config update --notify`. Releases are checked at most once a day.
+func NotifyUpdate(ctx context.Context, logger logging.Logger, executed *cobra.Command) {
+	cfg, _, cfgErr := initConfig()
+	if cfgErr != nil || executed == nil || !cfg.Update.Notify || executed.CommandPath() == "pack update" || logging.IsQuiet(logger) {
+		return
+	}
+
+	packHome, err := config.PackHome()
+	if err != nil || !update.CheckDue(packHome, time.Now()) {
+		return
+	}
+
+	channel := cfg.Update.Channel
+	if channel == "" {
+		channel = update.ChannelStable
+	}
+	ctx, cancel := context.WithTimeout(ctx, update.DefaultTimeout)
+	defer cancel()
+	release, err := update.NewUpdater(cfg.Update.Mirror).Latest(ctx, channel)
+	if err != nil {
+		logger.Debugf("Unable to check for a new version of pack: %s", err)
+		return
+	}
+
+	current := strings.TrimSpace(executed.Root().Version)
+	if _, err := semver.NewVersion(current); err == nil && release.Newer(current) {
+		logger.Infof("A new version of pack is available: %s (current version %s). Run `pack update` to install it.", style.Symbol(release.Version.String()), style.Symbol(current))
+	}
+}
+
 func initConfig() (config.Config, string, error) {
 	path, err := config.DefaultConfigPath()
 	if err != nil {
@@ -138,6 +303,10 @@ func initConfig() (config.Config, string, error) {
 }
 
 func initClient(logger logging.Logger, cfg config.Config) (*client.Client, error) {
+	// DOCKER_HOST takes precedence over the docker host of the config, which takes precedence over the docker context
+	if os.Getenv("DOCKER_HOST") == "" && cfg.DockerHost != "" {
+		os.Setenv("DOCKER_HOST", cfg.DockerHost)
+	}
 	if err := client.ProcessDockerContext(logger); err != nil {
 		return nil, err
 	}
@@ -146,5 +315,5 @@ func initClient(logger logging.Logger, cfg config.Config) (*client.Client, error
 	if err != nil {
 		return nil, err
 	}
-	return client.NewClient(client.WithLogger(logger), client.WithExperimental(cfg.Experimental), client.WithRegistryMirrors(cfg.RegistryMirrors), client.WithDockerClient(dc))
+	return client.NewClient(client.WithLogger(logger), client.WithExperimental(cfg.Experimental), client.WithRegistryMirrors(cfg.RegistryMirrors), client.WithDownloadWorkers(cfg.DownloadWorkers), client.WithLifecycleMirror(cfg.LifecycleMirror), client.WithDockerClient(dc))
 }
@@ -1,12 +1,17 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"os"
+	"os/exec"
+	"time"
 
 	"github.com/heroku/color"
 
 	"github.com/buildpacks/pack/cmd"
 	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/errcode"
 
 	"github.com/buildpacks/pack/internal/commands"
 	"github.com/buildpacks/pack/pkg/logging"
@@ -23,10 +28,27 @@ func main() {
 	}
 
 	ctx := commands.CreateCancellableContext()
-	if err := rootCmd.ExecuteContext(ctx); err != nil {
+	if p, ok := cmd.FindPlugin(rootCmd, os.Args[1:]); ok {
+		if err := cmd.RunPlugin(ctx, p, os.Args[2:], rootCmd.Version); err != nil {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				os.Exit(exitErr.ExitCode())
+			}
+			logger.Error(err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+
+	start := time.Now()
+	executed, err := rootCmd.ExecuteContextC(ctx)
+	cmd.ReportTelemetry(context.Background(), logger, executed, time.Since(start), err)
+	cmd.NotifyUpdate(context.Background(), logger, executed)
+	cmd.CloseLogs(logger)
+	if err != nil {
 		if _, isSoftError := err.(client.SoftError); isSoftError {
 			os.Exit(2)
 		}
-		os.Exit(1)
+		os.Exit(errcode.Of(err).ExitCode())
 	}
 }
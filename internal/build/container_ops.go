@@ -1,12 +1,19 @@
 package build
 
 import (
+	"archive/tar"
 	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"runtime"
+	"slices"
+	"sort"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/buildpacks/lifecycle/platform/files"
@@ -14,11 +21,13 @@ import (
 	dcontainer "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/errdefs"
 	darchive "github.com/docker/docker/pkg/archive"
+	"github.com/dustin/go-humanize"
 	"github.com/pkg/errors"
 
 	"github.com/buildpacks/pack/internal/builder"
 	"github.com/buildpacks/pack/internal/container"
 	"github.com/buildpacks/pack/internal/paths"
+	"github.com/buildpacks/pack/internal/style"
 	"github.com/buildpacks/pack/pkg/archive"
 )
 
@@ -113,6 +122,33 @@ func CopyDir(src, dst string, uid, gid int, os string, includeRoot bool, fileFil
 	}
 }
 
+// CopyDirGzip is CopyDir with the archive gzipped on its way to the daemon, which decompresses it, so that less is
+// sent to daemons at the end of a slow link. Windows containers are copied to uncompressed.
+func CopyDirGzip(src, dst string, uid, gid int, os string, includeRoot bool, fileFilter func(string) bool) ContainerOperation {
+	if os == "windows" {
+		return CopyDir(src, dst, uid, gid, os, includeRoot, fileFilter)
+	}
+	return func(ctrClient DockerClient, ctx context.Context, containerID string, stdout, stderr io.Writer) error {
+		reader, err := createReader(src, dst, uid, gid, includeRoot, fileFilter)
+		if err != nil {
+			return errors.Wrapf(err, "create tar archive from '%s'", src)
+		}
+		defer reader.Close()
+
+		pr, pw := io.Pipe()
+		go func() {
+			gw := gzip.NewWriter(pw)
+			_, err := io.Copy(gw, reader)
+			if err == nil {
+				err = gw.Close()
+			}
+			pw.CloseWithError(err)
+		}()
+		defer pr.Close()
+		return copyDir(ctx, ctrClient, containerID, pr)
+	}
+}
+
 func copyDir(ctx context.Context, ctrClient DockerClient, containerID string, appReader io.Reader) error {
 	var clientErr, err error
 
@@ -257,6 +293,114 @@ func WriteRunToml(dstPath string, runImages []builder.RunImageMetadata, os strin
 	}
 }
 
+// cacheMetadataFile holds the layers of each buildpack in a volume cache, committed by the exporter
+const cacheMetadataFile = "io.buildpacks.lifecycle.cache.metadata"
+
+// ForgetCachedBuildpacks removes the buildpacks with the given IDs from the metadata of the volume cache mounted at
+// cacheDir, so that none of their layers are restored from it. The exporter drops their layers from the cache when
+// committing it.
+func ForgetCachedBuildpacks(cacheDir string, ids []string) ContainerOperation {
+	return func(ctrClient DockerClient, ctx context.Context, containerID string, stdout, stderr io.Writer) error {
+		return editCacheMetadata(ctrClient, ctx, containerID, cacheDir, func(metadata []byte) ([]byte, error) {
+			return forgetBuildpacks(metadata, ids)
+		})
+	}
+}
+
+// LimitCacheSize removes the least recently created layers from the metadata of the volume cache mounted at cacheDir,
+// until the layers left take at most limit bytes. Like with ForgetCachedBuildpacks, the evicted layers aren't
+// restored, and the exporter drops them from the cache when committing it.
+func LimitCacheSize(cacheDir string, limit int64) ContainerOperation {
+	return func(ctrClient DockerClient, ctx context.Context, containerID string, stdout, stderr io.Writer) error {
+		return editCacheMetadata(ctrClient, ctx, containerID, cacheDir, func(metadata []byte) ([]byte, error) {
+			return evictLayers(metadata, limit, func(sha string) (int64, time.Time, error) {
+				stat, err := ctrClient.ContainerStatPath(ctx, containerID, path.Join(cacheDir, "committed", sha+".tar"))
+				if err != nil {
+					return 0, time.Time{}, err
+				}
+				return stat.Size, stat.Mtime, nil
+			}, stdout)
+		})
+	}
+}
+
+// editCacheMetadata replaces the metadata of the volume cache mounted at cacheDir with the one edit returns, unless
+// it returns nil
+func editCacheMetadata(ctrClient DockerClient, ctx context.Context, containerID, cacheDir string, edit func(metadata []byte) ([]byte, error)) error {
+	metadataPath := path.Join(cacheDir, "committed", cacheMetadataFile)
+	reader, _, err := ctrClient.CopyFromContainer(ctx, containerID, metadataPath)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			// nothing was cached yet
+			return nil
+		}
+		return errors.Wrap(err, "reading cache metadata")
+	}
+	defer reader.Close()
+
+	tr := tar.NewReader(reader)
+	header, err := tr.Next()
+	if err != nil {
+		return errors.Wrap(err, "reading cache metadata")
+	}
+	metadata, err := io.ReadAll(tr)
+	if err != nil {
+		return errors.Wrap(err, "reading cache metadata")
+	}
+	metadata, err = edit(metadata)
+	if err != nil || metadata == nil {
+		return err
+	}
+
+	// the header keeps the owner and mode of the file
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	header.Size = int64(len(metadata))
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	if _, err := tw.Write(metadata); err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return ctrClient.CopyToContainer(ctx, containerID, path.Dir(metadataPath), buf, types.CopyToContainerOptions{})
+}
+
+// forgetBuildpacks removes the buildpacks with the given IDs from cache metadata, keeping the fields it doesn't know of
+func forgetBuildpacks(metadata []byte, ids []string) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(metadata, &fields); err != nil {
+		return nil, errors.Wrap(err, "parsing cache metadata")
+	}
+	var buildpacks []json.RawMessage
+	if raw, ok := fields["buildpacks"]; ok {
+		if err := json.Unmarshal(raw, &buildpacks); err != nil {
+			return nil, errors.Wrap(err, "parsing cache metadata")
+		}
+	}
+
+	kept := []json.RawMessage{}
+	for _, raw := range buildpacks {
+		var bp struct {
+			ID string `json:"key"`
+		}
+		if err := json.Unmarshal(raw, &bp); err != nil {
+			return nil, errors.Wrap(err, "parsing cache metadata")
+		}
+		if !slices.Contains(ids, bp.ID) {
+			kept = append(kept, raw)
+		}
+	}
+
+	var err error
+	if fields["buildpacks"], err = json.Marshal(kept); err != nil {
+		return nil, err
+	}
+	return json.Marshal(fields)
+}
+
 func createReader(src, dst string, uid, gid int, includeRoot bool, fileFilter func(string) bool) (io.ReadCloser, error) {
 	fi, err := os.Stat(src)
 	if err != nil {
@@ -340,3 +484,160 @@ func EnsureVolumeAccess(uid, gid int, os string, volumeNames ...string) Containe
 		)
 	}
 }
+
+// ChownVolumes gives ownership of the contents of volumes to uid and gid, from a container of the image of the
+// phase running as root in the host user namespace. Daemons that remap user namespaces remap the ownership of
+// files copied into containers, leaving them inaccessible to the phases, which run in the host user namespace.
+func ChownVolumes(uid, gid int, volumeNames ...string) ContainerOperation {
+	return func(ctrClient DockerClient, ctx context.Context, containerID string, stdout, stderr io.Writer) error {
+		containerInfo, err := ctrClient.ContainerInspect(ctx, containerID)
+		if err != nil {
+			return err
+		}
+
+		cmd := []string{"-R", fmt.Sprintf("%d:%d", uid, gid)}
+		binds := []string{}
+		for i, volumeName := range volumeNames {
+			containerPath := fmt.Sprintf("/volume-mnt-%d", i)
+			binds = append(binds, fmt.Sprintf("%s:%s", volumeName, containerPath))
+			cmd = append(cmd, containerPath)
+		}
+
+		ctr, err := ctrClient.ContainerCreate(ctx,
+			&dcontainer.Config{
+				Image:      containerInfo.Image,
+				Entrypoint: []string{"chown"},
+				Cmd:        cmd,
+				WorkingDir: "/",
+				User:       linuxContainerAdmin,
+			},
+			&dcontainer.HostConfig{
+				Binds:      binds,
+				UsernsMode: "host",
+			},
+			nil, nil, "",
+		)
+		if err != nil {
+			return err
+		}
+		defer ctrClient.ContainerRemove(context.Background(), ctr.ID, dcontainer.RemoveOptions{Force: true})
+
+		return container.RunWithHandler(
+			ctx,
+			ctrClient,
+			ctr.ID,
+			container.DefaultHandler(stdout, stderr),
+		)
+	}
+}
+
+// cachedLayer is a layer of a buildpack in cache metadata
+type cachedLayer struct {
+	buildpack int
+	name      string
+	sha       string
+	size      int64
+	created   time.Time
+}
+
+// evictLayers removes the least recently created layers from cache metadata until the layers left take at most limit
+// bytes, returning nil when none is removed. stat gives the size and creation time of the layer with the given diff
+// ID; the layers shared by several buildpacks are counted once.
+func evictLayers(metadata []byte, limit int64, stat func(sha string) (int64, time.Time, error), out io.Writer) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(metadata, &fields); err != nil {
+		return nil, errors.Wrap(err, "parsing cache metadata")
+	}
+	var buildpacks []map[string]json.RawMessage
+	if raw, ok := fields["buildpacks"]; ok {
+		if err := json.Unmarshal(raw, &buildpacks); err != nil {
+			return nil, errors.Wrap(err, "parsing cache metadata")
+		}
+	}
+
+	var (
+		total     int64
+		layers    []cachedLayer
+		bpLayers  = make([]map[string]json.RawMessage, len(buildpacks))
+		layerSize = map[string]int64{}
+	)
+	for i, bp := range buildpacks {
+		if raw, ok := bp["layers"]; ok {
+			if err := json.Unmarshal(raw, &bpLayers[i]); err != nil {
+				return nil, errors.Wrap(err, "parsing cache metadata")
+			}
+		}
+		for name, raw := range bpLayers[i] {
+			var layer struct {
+				SHA string `json:"sha"`
+			}
+			if err := json.Unmarshal(raw, &layer); err != nil {
+				return nil, errors.Wrap(err, "parsing cache metadata")
+			}
+			if layer.SHA == "" {
+				// layers only cached for their metadata take no space
+				continue
+			}
+			size, created, err := stat(layer.SHA)
+			if err != nil {
+				if errdefs.IsNotFound(err) {
+					continue
+				}
+				return nil, errors.Wrapf(err, "reading cached layer %s", style.Symbol(layer.SHA))
+			}
+			if _, ok := layerSize[layer.SHA]; !ok {
+				total += size
+			}
+			layerSize[layer.SHA] = size
+			layers = append(layers, cachedLayer{buildpack: i, name: name, sha: layer.SHA, size: size, created: created})
+		}
+	}
+	if total <= limit {
+		return nil, nil
+	}
+
+	sort.SliceStable(layers, func(i, j int) bool {
+		return layers[i].created.Before(layers[j].created)
+	})
+	var (
+		evicted int
+		freed   int64
+	)
+	for _, layer := range layers {
+		if total <= limit {
+			break
+		}
+		delete(bpLayers[layer.buildpack], layer.name)
+		evicted++
+		if size, ok := layerSize[layer.sha]; ok {
+			total -= size
+			freed += size
+			delete(layerSize, layer.sha)
+		}
+	}
+	// the layers shared with buildpacks whose copy was evicted would be dropped too
+	for _, layer := range layers {
+		if _, ok := layerSize[layer.sha]; !ok {
+			if _, kept := bpLayers[layer.buildpack][layer.name]; kept {
+				delete(bpLayers[layer.buildpack], layer.name)
+				evicted++
+			}
+		}
+	}
+
+	for i := range buildpacks {
+		if bpLayers[i] == nil {
+			continue
+		}
+		var err error
+		if buildpacks[i]["layers"], err = json.Marshal(bpLayers[i]); err != nil {
+			return nil, err
+		}
+	}
+	var err error
+	if fields["buildpacks"], err = json.Marshal(buildpacks); err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(out, "Evicting %d cached layers (%s) to keep the build cache under %s\n", evicted, humanize.Bytes(uint64(freed)), humanize.Bytes(uint64(limit)))
+	return json.Marshal(fields)
+}
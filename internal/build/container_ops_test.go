@@ -11,8 +11,10 @@ import (
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/buildpacks/lifecycle/platform/files"
+	"github.com/docker/docker/api/types"
 	dcontainer "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/client"
@@ -23,6 +25,7 @@ import (
 	"github.com/buildpacks/pack/internal/build"
 	"github.com/buildpacks/pack/internal/builder"
 	"github.com/buildpacks/pack/internal/container"
+	"github.com/buildpacks/pack/pkg/archive"
 	h "github.com/buildpacks/pack/testhelpers"
 )
 
@@ -247,6 +250,26 @@ drwxrwxrwx    2 123      456 (.*) some-vol
 		})
 	})
 
+	when("#CopyDirGzip", func() {
+		it("writes the contents the daemon decompressed", func() {
+			h.SkipIf(t, osType == "windows", "Windows containers are copied to uncompressed")
+
+			ctx := context.Background()
+			ctr, err := createContainer(ctx, imageName, "/some-vol", osType, "ls", "-al", "/some-vol")
+			h.AssertNil(t, err)
+			defer cleanupContainer(ctx, ctr.ID)
+
+			copyDirOp := build.CopyDirGzip(filepath.Join("testdata", "fake-app"), "/some-vol", 123, 456, osType, false, nil)
+
+			var outBuf, errBuf bytes.Buffer
+			h.AssertNil(t, copyDirOp(ctrClient, ctx, ctr.ID, &outBuf, &errBuf))
+
+			h.AssertNil(t, container.RunWithHandler(ctx, ctrClient, ctr.ID, container.DefaultHandler(&outBuf, &errBuf)))
+			h.AssertEq(t, errBuf.String(), "")
+			h.AssertContainsMatch(t, outBuf.String(), `123      456 (.*) fake-app-file`)
+		})
+	})
+
 	when("#CopyOut", func() {
 		it("reads the contents of a container directory", func() {
 			h.SkipIf(t, osType == "windows", "copying directories out of windows containers not yet supported")
@@ -638,6 +661,91 @@ drwxrwxrwx    2 123      456 (.*) some-vol
 		})
 	})
 
+	when("#ForgetCachedBuildpacks", func() {
+		it.Before(func() {
+			h.SkipIf(t, osType == "windows", "the cache of buildpacks is only cleared in Linux containers")
+		})
+
+		it("removes the buildpacks from the cache metadata", func() {
+			ctx := context.Background()
+			ctr, err := createContainer(ctx, imageName, "/cache", osType, "cat", "/cache/committed/io.buildpacks.lifecycle.cache.metadata")
+			h.AssertNil(t, err)
+			defer cleanupContainer(ctx, ctr.ID)
+
+			tarBuilder := archive.TarBuilder{}
+			tarBuilder.AddFile("/cache/committed/io.buildpacks.lifecycle.cache.metadata", 0644, archive.NormalizedDateTime,
+				[]byte(`{"buildpacks":[{"key":"some-buildpack-id","layers":{}},{"key":"other-buildpack-id","layers":{}}]}`))
+			reader := tarBuilder.Reader(archive.DefaultTarWriterFactory())
+			defer reader.Close()
+			h.AssertNil(t, ctrClient.CopyToContainer(ctx, ctr.ID, "/", reader, types.CopyToContainerOptions{}))
+
+			var outBuf, errBuf bytes.Buffer
+			forgetOp := build.ForgetCachedBuildpacks("/cache", []string{"some-buildpack-id"})
+			h.AssertNil(t, forgetOp(ctrClient, ctx, ctr.ID, &outBuf, &errBuf))
+
+			err = container.RunWithHandler(ctx, ctrClient, ctr.ID, container.DefaultHandler(&outBuf, &errBuf))
+			h.AssertNil(t, err)
+			h.AssertEq(t, errBuf.String(), "")
+			h.AssertEq(t, strings.TrimSpace(outBuf.String()), `{"buildpacks":[{"key":"other-buildpack-id","layers":{}}]}`)
+		})
+
+		it("does nothing when nothing is cached", func() {
+			ctx := context.Background()
+			ctr, err := createContainer(ctx, imageName, "/cache", osType, "ls", "/cache")
+			h.AssertNil(t, err)
+			defer cleanupContainer(ctx, ctr.ID)
+
+			var outBuf, errBuf bytes.Buffer
+			forgetOp := build.ForgetCachedBuildpacks("/cache", []string{"some-buildpack-id"})
+			h.AssertNil(t, forgetOp(ctrClient, ctx, ctr.ID, &outBuf, &errBuf))
+		})
+	})
+
+	when("#LimitCacheSize", func() {
+		it.Before(func() {
+			h.SkipIf(t, osType == "windows", "the size of the build cache is only limited in Linux containers")
+		})
+
+		it("evicts the least recently created layers beyond the limit", func() {
+			ctx := context.Background()
+			ctr, err := createContainer(ctx, imageName, "/cache", osType, "cat", "/cache/committed/io.buildpacks.lifecycle.cache.metadata")
+			h.AssertNil(t, err)
+			defer cleanupContainer(ctx, ctr.ID)
+
+			tarBuilder := archive.TarBuilder{}
+			tarBuilder.AddFile("/cache/committed/io.buildpacks.lifecycle.cache.metadata", 0644, archive.NormalizedDateTime,
+				[]byte(`{"buildpacks":[{"key":"some-buildpack-id","layers":{"old":{"sha":"sha256:old"}}},{"key":"other-buildpack-id","layers":{"new":{"sha":"sha256:new"}}}]}`))
+			tarBuilder.AddFile("/cache/committed/sha256:old.tar", 0644, time.Now().Add(-time.Hour), make([]byte, 1024))
+			tarBuilder.AddFile("/cache/committed/sha256:new.tar", 0644, time.Now(), make([]byte, 1024))
+			reader := tarBuilder.Reader(archive.DefaultTarWriterFactory())
+			defer reader.Close()
+			h.AssertNil(t, ctrClient.CopyToContainer(ctx, ctr.ID, "/", reader, types.CopyToContainerOptions{}))
+
+			var outBuf, errBuf bytes.Buffer
+			limitOp := build.LimitCacheSize("/cache", 1536)
+			h.AssertNil(t, limitOp(ctrClient, ctx, ctr.ID, &outBuf, &errBuf))
+			h.AssertContains(t, outBuf.String(), "Evicting 1 cached layers (1.0 kB) to keep the build cache under 1.5 kB")
+
+			outBuf.Reset()
+			err = container.RunWithHandler(ctx, ctrClient, ctr.ID, container.DefaultHandler(&outBuf, &errBuf))
+			h.AssertNil(t, err)
+			h.AssertEq(t, errBuf.String(), "")
+			h.AssertEq(t, strings.TrimSpace(outBuf.String()), `{"buildpacks":[{"key":"some-buildpack-id","layers":{}},{"key":"other-buildpack-id","layers":{"new":{"sha":"sha256:new"}}}]}`)
+		})
+
+		it("does nothing when nothing is cached", func() {
+			ctx := context.Background()
+			ctr, err := createContainer(ctx, imageName, "/cache", osType, "ls", "/cache")
+			h.AssertNil(t, err)
+			defer cleanupContainer(ctx, ctr.ID)
+
+			var outBuf, errBuf bytes.Buffer
+			limitOp := build.LimitCacheSize("/cache", 1536)
+			h.AssertNil(t, limitOp(ctrClient, ctx, ctr.ID, &outBuf, &errBuf))
+			h.AssertEq(t, outBuf.String(), "")
+		})
+	})
+
 	when("#EnsureVolumeAccess", func() {
 		it("changes owner of volume", func() {
 			h.SkipIf(t, osType != "windows", "no-op for linux")
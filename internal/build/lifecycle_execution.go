@@ -30,6 +30,10 @@ const (
 	overrideGID        = 0
 	overrideUID        = 0
 	sourceDateEpochEnv = "SOURCE_DATE_EPOCH"
+	parallelExportEnv  = "CNB_PARALLEL_EXPORT"
+
+	// SSHAgentSocketPath is where the socket of the SSH agent is mounted in the containers running buildpacks
+	SSHAgentSocketPath = "/run/pack/ssh-agent.sock"
 )
 
 type LifecycleExecution struct {
@@ -196,7 +200,7 @@ func (l *LifecycleExecution) Run(ctx context.Context, phaseFactoryCreator PhaseF
 		}
 	}
 
-	if l.opts.ClearCache {
+	if l.opts.ClearCache || l.opts.ClearBuildCache {
 		if err := buildCache.Clear(ctx); err != nil {
 			return errors.Wrap(err, "clearing build cache")
 		}
@@ -208,6 +212,13 @@ func (l *LifecycleExecution) Run(ctx context.Context, phaseFactoryCreator PhaseF
 		return err
 	}
 
+	if l.opts.ClearLaunchCache {
+		if err := launchCache.Clear(ctx); err != nil {
+			return errors.Wrap(err, "clearing launch cache")
+		}
+		l.logger.Debugf("Launch cache %s cleared", style.Symbol(launchCache.Name()))
+	}
+
 	if l.opts.Network == "" {
 		// start an ephemeral bridge network
 		driver := "bridge"
@@ -237,6 +248,12 @@ func (l *LifecycleExecution) Run(ctx context.Context, phaseFactoryCreator PhaseF
 		l.opts.Network = networkName
 	}
 
+	if l.opts.DetectOnly {
+		// the detector runs on its own, even for a trusted builder, as the creator can't stop after detecting
+		l.logger.Info(style.Step("DETECTING"))
+		return l.Detect(ctx, phaseFactory)
+	}
+
 	if !l.opts.UseCreator {
 		if l.platformAPI.LessThan("0.7") {
 			l.logger.Info(style.Step("DETECTING"))
@@ -430,9 +447,15 @@ func (l *LifecycleExecution) Create(ctx context.Context, buildCache, launchCache
 		WithFlags(l.withLogLevel(flags...)...),
 		WithArgs(l.opts.Image.String()),
 		WithNetwork(l.opts.Network),
+		l.withResources(),
+		l.withSSHAgent(),
 		cacheBindOp,
+		l.forgetBuildpackCaches(buildCache),
+		l.limitBuildCache(buildCache),
+		l.withParallelExport(),
 		WithContainerOperations(WriteProjectMetadata(l.mountPaths.projectPath(), l.opts.ProjectMetadata, l.os)),
-		WithContainerOperations(CopyDir(l.opts.AppPath, l.mountPaths.appDir(), l.opts.Builder.UID(), l.opts.Builder.GID(), l.os, true, l.opts.FileFilter)),
+		WithContainerOperations(l.copyApp()),
+		If(l.chownsVolumes(), WithContainerOperations(ChownVolumes(l.opts.Builder.UID(), l.opts.Builder.GID(), l.layersVolume, l.appVolume))),
 		If(l.opts.SBOMDestinationDir != "", WithPostContainerRunOperations(
 			EnsureVolumeAccess(l.opts.Builder.UID(), l.opts.Builder.GID(), l.os, l.layersVolume, l.appVolume),
 			CopyOutTo(l.mountPaths.sbomDir(), l.opts.SBOMDestinationDir))),
@@ -489,16 +512,22 @@ func (l *LifecycleExecution) Detect(ctx context.Context, phaseFactory PhaseFacto
 			l.withLogLevel()...,
 		),
 		WithNetwork(l.opts.Network),
+		l.withResources(),
+		l.withSSHAgent(),
 		WithBinds(l.opts.Volumes...),
 		WithContainerOperations(
 			EnsureVolumeAccess(l.opts.Builder.UID(), l.opts.Builder.GID(), l.os, l.layersVolume, l.appVolume),
-			CopyDir(l.opts.AppPath, l.mountPaths.appDir(), l.opts.Builder.UID(), l.opts.Builder.GID(), l.os, true, l.opts.FileFilter),
+			l.copyApp(),
 		),
+		If(l.chownsVolumes(), WithContainerOperations(ChownVolumes(l.opts.Builder.UID(), l.opts.Builder.GID(), l.layersVolume, l.appVolume))),
 		WithFlags(flags...),
 		If(l.hasExtensions(), WithPostContainerRunOperations(
 			CopyOutToMaybe(filepath.Join(l.mountPaths.layersDir(), "analyzed.toml"), l.tmpDir))),
 		If(l.hasExtensions(), WithPostContainerRunOperations(
 			CopyOutToMaybe(filepath.Join(l.mountPaths.layersDir(), "generated"), l.tmpDir))),
+		If(l.opts.DetectDestinationDir != "", WithPostContainerRunOperations(
+			CopyOutTo(filepath.Join(l.mountPaths.layersDir(), "group.toml"), l.opts.DetectDestinationDir),
+			CopyOutTo(filepath.Join(l.mountPaths.layersDir(), "plan.toml"), l.opts.DetectDestinationDir))),
 		envOp,
 	)
 
@@ -593,6 +622,8 @@ func (l *LifecycleExecution) Restore(ctx context.Context, buildCache Cache, kani
 		),
 		WithNetwork(l.opts.Network),
 		cacheBindOp,
+		l.forgetBuildpackCaches(buildCache),
+		l.limitBuildCache(buildCache),
 		dockerOp,
 		flagsOp,
 		kanikoCacheBindOp,
@@ -760,6 +791,8 @@ func (l *LifecycleExecution) Build(ctx context.Context, phaseFactory PhaseFactor
 		WithLogPrefix("builder"),
 		WithArgs(l.withLogLevel()...),
 		WithNetwork(l.opts.Network),
+		l.withResources(),
+		l.withSSHAgent(),
 		WithBinds(l.opts.Volumes...),
 		WithFlags(flags...),
 	)
@@ -769,6 +802,43 @@ func (l *LifecycleExecution) Build(ctx context.Context, phaseFactory PhaseFactor
 	return build.Run(ctx)
 }
 
+// chownsVolumes is true when the daemon remaps user namespaces, so that the ownership of the app and layers
+// volumes has to be restored after copying the app
+func (l *LifecycleExecution) chownsVolumes() bool {
+	return l.opts.UsernsRemap && l.os != "windows"
+}
+
+// withResources applies the resource limits of the build to the containers running buildpacks
+func (l *LifecycleExecution) withResources() PhaseConfigProviderOperation {
+	return WithResources(l.opts.Memory, l.opts.NanoCPUs, l.opts.PidsLimit)
+}
+
+// withSSHAgent mounts the socket of the forwarded SSH agent in the containers running buildpacks
+func (l *LifecycleExecution) withSSHAgent() PhaseConfigProviderOperation {
+	return If(l.opts.SSHAgentSocket != "", WithBinds(fmt.Sprintf("%s:%s", l.opts.SSHAgentSocket, SSHAgentSocketPath)))
+}
+
+// withParallelExport has the lifecycle export the app image and the build cache at once. Older lifecycles ignore the
+// variable and export them one after the other, whereas they'd fail on the -parallel flag.
+func (l *LifecycleExecution) withParallelExport() PhaseConfigProviderOperation {
+	return If(l.opts.ParallelExport, WithEnv(parallelExportEnv+"=true"))
+}
+
+// forgetBuildpackCaches keeps the layers of the buildpacks whose cache is cleared from being restored from the build
+// cache, when it's mounted in the container
+func (l *LifecycleExecution) forgetBuildpackCaches(buildCache Cache) PhaseConfigProviderOperation {
+	return If(len(l.opts.ClearBuildpackCaches) > 0 && buildCache.Type() != cache.Image, WithContainerOperations(ForgetCachedBuildpacks(l.mountPaths.cacheDir(), l.opts.ClearBuildpackCaches)))
+}
+
+// limitBuildCache evicts the least recently created layers of the build cache beyond its size limit before they're
+// restored, when it's mounted in the container
+func (l *LifecycleExecution) limitBuildCache(buildCache Cache) PhaseConfigProviderOperation {
+	return If(l.opts.CacheSizeLimit > 0 && buildCache.Type() != cache.Image, WithContainerOperations(LimitCacheSize(l.mountPaths.cacheDir(), l.opts.CacheSizeLimit)))
+}
+
+// ExtendBuild runs the build Dockerfiles of the image extensions with kaniko, then the buildpacks. The extenders get no
+// registry credentials, as the restorer already put the base images they extend in the kaniko cache, and run isolated
+// since the Dockerfiles come from the builder, which isn't trusted.
 func (l *LifecycleExecution) ExtendBuild(ctx context.Context, kanikoCache Cache, phaseFactory PhaseFactory, experimental bool) error {
 	flags := []string{"-app", l.mountPaths.appDir()}
 
@@ -776,6 +846,7 @@ func (l *LifecycleExecution) ExtendBuild(ctx context.Context, kanikoCache Cache,
 		"extender",
 		l,
 		WithLogPrefix("extender (build)"),
+		WithIsolation(),
 		WithArgs(l.withLogLevel()...),
 		WithBinds(l.opts.Volumes...),
 		If(experimental, WithEnv("CNB_EXPERIMENTAL_MODE=warn")),
@@ -783,6 +854,7 @@ func (l *LifecycleExecution) ExtendBuild(ctx context.Context, kanikoCache Cache,
 		WithNetwork(l.opts.Network),
 		WithRoot(),
 		WithBinds(fmt.Sprintf("%s:%s", kanikoCache.Name(), l.mountPaths.kanikoCacheDir())),
+		l.withResources(),
 	)
 
 	extend := phaseFactory.New(configProvider)
@@ -790,6 +862,7 @@ func (l *LifecycleExecution) ExtendBuild(ctx context.Context, kanikoCache Cache,
 	return extend.Run(ctx)
 }
 
+// ExtendRun runs the run Dockerfiles of the image extensions with kaniko, in a container of the run image
 func (l *LifecycleExecution) ExtendRun(ctx context.Context, kanikoCache Cache, phaseFactory PhaseFactory, runImageName string, experimental bool) error {
 	flags := []string{"-app", l.mountPaths.appDir(), "-kind", "run"}
 
@@ -797,6 +870,7 @@ func (l *LifecycleExecution) ExtendRun(ctx context.Context, kanikoCache Cache, p
 		"extender",
 		l,
 		WithLogPrefix("extender (run)"),
+		WithIsolation(),
 		WithArgs(l.withLogLevel()...),
 		WithBinds(l.opts.Volumes...),
 		If(experimental, WithEnv("CNB_EXPERIMENTAL_MODE=warn")),
@@ -805,6 +879,7 @@ func (l *LifecycleExecution) ExtendRun(ctx context.Context, kanikoCache Cache, p
 		WithRoot(),
 		WithImage(runImageName),
 		WithBinds(fmt.Sprintf("%s:%s", kanikoCache.Name(), l.mountPaths.kanikoCacheDir())),
+		l.withResources(),
 	)
 
 	extend := phaseFactory.New(configProvider)
@@ -883,6 +958,7 @@ func (l *LifecycleExecution) Export(ctx context.Context, buildCache, launchCache
 		WithNetwork(l.opts.Network),
 		cacheBindOp,
 		kanikoCacheBindOp,
+		l.withParallelExport(),
 		WithContainerOperations(WriteStackToml(l.mountPaths.stackPath(), l.opts.Builder.Stack(), l.os)),
 		WithContainerOperations(WriteRunToml(l.mountPaths.runPath(), l.opts.Builder.RunImages(), l.os)),
 		WithContainerOperations(WriteProjectMetadata(l.mountPaths.projectPath(), l.opts.ProjectMetadata, l.os)),
@@ -936,12 +1012,23 @@ func (l *LifecycleExecution) Export(ctx context.Context, buildCache, launchCache
 }
 
 func (l *LifecycleExecution) withLogLevel(args ...string) []string {
+	if l.opts.LogLevel != "" {
+		return append([]string{"-log-level", l.opts.LogLevel}, args...)
+	}
 	if l.logger.IsVerbose() {
 		return append([]string{"-log-level", "debug"}, args...)
 	}
 	return args
 }
 
+// copyApp copies the app to the app directory of the containers
+func (l *LifecycleExecution) copyApp() ContainerOperation {
+	if l.opts.GzipApp {
+		return CopyDirGzip(l.opts.AppPath, l.mountPaths.appDir(), l.opts.Builder.UID(), l.opts.Builder.GID(), l.os, true, l.opts.FileFilter)
+	}
+	return CopyDir(l.opts.AppPath, l.mountPaths.appDir(), l.opts.Builder.UID(), l.opts.Builder.GID(), l.os, true, l.opts.FileFilter)
+}
+
 func (l *LifecycleExecution) hasExtensions() bool {
 	return len(l.opts.Builder.OrderExtensions()) > 0
 }
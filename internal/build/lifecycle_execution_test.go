@@ -49,15 +49,24 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 
 		// lifecycle options
 		providedClearCache     bool
+		providedClearBPCaches  []string
+		providedCacheSizeLimit int64
 		providedPublish        bool
 		providedUseCreator     bool
 		providedLayout         bool
+		providedLogLevel       string
 		providedDockerHost     string
 		providedNetworkMode    = "some-network-mode"
 		providedRunImage       = "some-run-image"
 		providedTargetImage    = "some-target-image"
 		providedAdditionalTags = []string{"some-additional-tag1", "some-additional-tag2"}
 		providedVolumes        = []string{"some-mount-source:/some-mount-target"}
+		providedMemory         = int64(512 * 1024 * 1024)
+		providedNanoCPUs       = int64(1500000000)
+		providedPidsLimit      = int64(256)
+		providedSSHAgentSocket = "/tmp/some-ssh-agent.sock"
+		providedUsernsRemap    bool
+		providedDetectDestDir  string
 
 		// builder options
 		providedBuilderImage = "some-registry.com/some-namespace/some-builder-name"
@@ -86,13 +95,22 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 		opts.AdditionalTags = providedAdditionalTags
 		opts.BuilderImage = providedBuilderImage
 		opts.ClearCache = providedClearCache
+		opts.ClearBuildpackCaches = providedClearBPCaches
+		opts.CacheSizeLimit = providedCacheSizeLimit
 		opts.DockerHost = providedDockerHost
 		opts.Network = providedNetworkMode
 		opts.Publish = providedPublish
 		opts.RunImage = providedRunImage
 		opts.UseCreator = providedUseCreator
 		opts.Volumes = providedVolumes
+		opts.Memory = providedMemory
+		opts.NanoCPUs = providedNanoCPUs
+		opts.PidsLimit = providedPidsLimit
+		opts.SSHAgentSocket = providedSSHAgentSocket
+		opts.UsernsRemap = providedUsernsRemap
 		opts.Layout = providedLayout
+		opts.LogLevel = providedLogLevel
+		opts.DetectDestinationDir = providedDetectDestDir
 		opts.Keychain = authn.DefaultKeychain
 		opts.UseCreatorWithExtensions = useCreatorWithExtensions
 
@@ -295,6 +313,30 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			fakePhaseFactory = fakes.NewFakePhaseFactory()
 		})
 
+		when("Run detecting only", func() {
+			it("only runs the detector, even with the creator", func() {
+				opts := build.LifecycleOptions{
+					RunImage:   "test",
+					Image:      imageName,
+					Builder:    fakeBuilder,
+					UseCreator: true,
+					DetectOnly: true,
+					Termui:     fakeTermui,
+				}
+
+				lifecycle, err := build.NewLifecycleExecution(logger, docker, "some-temp-dir", opts)
+				h.AssertNil(t, err)
+
+				err = lifecycle.Run(context.Background(), func(execution *build.LifecycleExecution) build.PhaseFactory {
+					return fakePhaseFactory
+				})
+				h.AssertNil(t, err)
+
+				h.AssertEq(t, len(fakePhaseFactory.NewCalledWithProvider), 1)
+				h.AssertEq(t, fakePhaseFactory.NewCalledWithProvider[0].Name(), "detector")
+			})
+		})
+
 		when("Run using creator", func() {
 			it("succeeds", func() {
 				opts := build.LifecycleOptions{
@@ -907,6 +949,16 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			h.AssertEq(t, configProvider.HostConfig().NetworkMode, container.NetworkMode(providedNetworkMode))
 		})
 
+		it("configures the phase with the resource limits", func() {
+			h.AssertEq(t, configProvider.HostConfig().Memory, providedMemory)
+			h.AssertEq(t, configProvider.HostConfig().NanoCPUs, providedNanoCPUs)
+			h.AssertEq(t, *configProvider.HostConfig().PidsLimit, providedPidsLimit)
+		})
+
+		it("mounts the socket of the SSH agent", func() {
+			h.AssertSliceContains(t, configProvider.HostConfig().Binds, providedSSHAgentSocket+":"+build.SSHAgentSocketPath)
+		})
+
 		when("clear cache", func() {
 			providedClearCache = true
 
@@ -927,6 +979,38 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("clearing the cache of buildpacks", func() {
+			providedClearBPCaches = []string{"some-buildpack-id"}
+
+			it("forgets the buildpacks in the build cache before creating", func() {
+				h.AssertFunctionName(t, configProvider.ContainerOps()[0], "ForgetCachedBuildpacks")
+			})
+
+			when("using a cache image", func() {
+				fakeBuildCache = newFakeImageCache()
+
+				it("doesn't change the cache image", func() {
+					h.AssertFunctionName(t, configProvider.ContainerOps()[0], "WriteProjectMetadata")
+				})
+			})
+		})
+
+		when("the build cache has a size limit", func() {
+			providedCacheSizeLimit = 1024
+
+			it("evicts layers from the build cache before creating", func() {
+				h.AssertFunctionName(t, configProvider.ContainerOps()[0], "LimitCacheSize")
+			})
+
+			when("using a cache image", func() {
+				fakeBuildCache = newFakeImageCache()
+
+				it("doesn't change the cache image", func() {
+					h.AssertFunctionName(t, configProvider.ContainerOps()[0], "WriteProjectMetadata")
+				})
+			})
+		})
+
 		when("using a cache image", func() {
 			providedClearCache = true
 			fakeBuildCache = newFakeImageCache()
@@ -1305,6 +1389,22 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("exporting in parallel", func() {
+			lifecycleOps = append(lifecycleOps, func(opts *build.LifecycleOptions) {
+				opts.ParallelExport = true
+			})
+
+			it("configures the phase with env CNB_PARALLEL_EXPORT", func() {
+				h.AssertSliceContains(t, configProvider.ContainerConfig().Env, "CNB_PARALLEL_EXPORT=true")
+			})
+		})
+
+		when("exporting serially", func() {
+			it("doesn't configure the phase with env CNB_PARALLEL_EXPORT", func() {
+				h.AssertSliceNotContains(t, configProvider.ContainerConfig().Env, "CNB_PARALLEL_EXPORT=true")
+			})
+		})
+
 		when("--creation-time", func() {
 			when("platform < 0.9", func() {
 				platformAPI = api.MustParse("0.8")
@@ -1390,6 +1490,16 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			h.AssertEq(t, configProvider.HostConfig().NetworkMode, container.NetworkMode(providedNetworkMode))
 		})
 
+		it("configures the phase with the resource limits", func() {
+			h.AssertEq(t, configProvider.HostConfig().Memory, providedMemory)
+			h.AssertEq(t, configProvider.HostConfig().NanoCPUs, providedNanoCPUs)
+			h.AssertEq(t, *configProvider.HostConfig().PidsLimit, providedPidsLimit)
+		})
+
+		it("mounts the socket of the SSH agent", func() {
+			h.AssertSliceContains(t, configProvider.HostConfig().Binds, providedSSHAgentSocket+":"+build.SSHAgentSocketPath)
+		})
+
 		it("configures the phase to copy app dir", func() {
 			h.AssertSliceContains(t, configProvider.HostConfig().Binds, providedVolumes...)
 			h.AssertEq(t, len(configProvider.ContainerOps()), 2)
@@ -1397,6 +1507,35 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			h.AssertFunctionName(t, configProvider.ContainerOps()[1], "CopyDir")
 		})
 
+		when("the daemon remaps user namespaces", func() {
+			providedUsernsRemap = true
+
+			it("restores the ownership of the volumes after copying the app", func() {
+				h.AssertEq(t, len(configProvider.ContainerOps()), 3)
+				h.AssertFunctionName(t, configProvider.ContainerOps()[1], "CopyDir")
+				h.AssertFunctionName(t, configProvider.ContainerOps()[2], "ChownVolumes")
+			})
+		})
+
+		when("a lifecycle log level is provided", func() {
+			providedLogLevel = "warn"
+
+			it("configures the phase with the provided log level", func() {
+				h.AssertSliceContainsInOrder(t, configProvider.ContainerConfig().Cmd, "-log-level", "warn")
+				h.AssertSliceNotContains(t, configProvider.ContainerConfig().Cmd, "debug")
+			})
+		})
+
+		when("a detect destination dir is provided", func() {
+			providedDetectDestDir = "some-detect-dir"
+
+			it("copies out the group and plan", func() {
+				h.AssertEq(t, len(configProvider.PostContainerRunOps()), 2)
+				h.AssertFunctionName(t, configProvider.PostContainerRunOps()[0], "CopyOut")
+				h.AssertFunctionName(t, configProvider.PostContainerRunOps()[1], "CopyOut")
+			})
+		})
+
 		when("extensions", func() {
 			platformAPI = api.MustParse("0.10")
 
@@ -1820,6 +1959,24 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			h.AssertEq(t, configProvider.Name(), "restorer")
 		})
 
+		when("clearing the cache of buildpacks", func() {
+			providedClearBPCaches = []string{"some-buildpack-id"}
+
+			it("forgets the buildpacks in the build cache before restoring", func() {
+				h.AssertEq(t, len(configProvider.ContainerOps()), 1)
+				h.AssertFunctionName(t, configProvider.ContainerOps()[0], "ForgetCachedBuildpacks")
+			})
+		})
+
+		when("the build cache has a size limit", func() {
+			providedCacheSizeLimit = 1024
+
+			it("evicts layers from the build cache before restoring", func() {
+				h.AssertEq(t, len(configProvider.ContainerOps()), 1)
+				h.AssertFunctionName(t, configProvider.ContainerOps()[0], "LimitCacheSize")
+			})
+		})
+
 		when("lifecycle image", func() {
 			lifecycleOps = append(lifecycleOps, func(options *build.LifecycleOptions) {
 				options.LifecycleImage = "some-lifecycle-image"
@@ -1856,6 +2013,12 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			h.AssertEq(t, configProvider.HostConfig().NetworkMode, container.NetworkMode(providedNetworkMode))
 		})
 
+		it("doesn't limit the resources of the phase", func() {
+			h.AssertEq(t, configProvider.HostConfig().Memory, int64(0))
+			h.AssertEq(t, configProvider.HostConfig().NanoCPUs, int64(0))
+			h.AssertNil(t, configProvider.HostConfig().PidsLimit)
+		})
+
 		it("configures the phase with binds", func() {
 			expectedBind := "some-cache:/cache"
 
@@ -2094,6 +2257,16 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			h.AssertEq(t, configProvider.HostConfig().NetworkMode, container.NetworkMode(providedNetworkMode))
 		})
 
+		it("configures the phase with the resource limits", func() {
+			h.AssertEq(t, configProvider.HostConfig().Memory, providedMemory)
+			h.AssertEq(t, configProvider.HostConfig().NanoCPUs, providedNanoCPUs)
+			h.AssertEq(t, *configProvider.HostConfig().PidsLimit, providedPidsLimit)
+		})
+
+		it("mounts the socket of the SSH agent", func() {
+			h.AssertSliceContains(t, configProvider.HostConfig().Binds, providedSSHAgentSocket+":"+build.SSHAgentSocketPath)
+		})
+
 		it("configures the phase with binds", func() {
 			h.AssertSliceContains(t, configProvider.HostConfig().Binds, providedVolumes...)
 		})
@@ -2142,6 +2315,15 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			h.AssertEq(t, configProvider.ContainerConfig().User, "root")
 		})
 
+		it("isolates the phase", func() {
+			h.AssertSliceContains(t, configProvider.HostConfig().CapDrop, "AUDIT_WRITE", "SYS_CHROOT")
+			h.AssertSliceNotContains(t, configProvider.HostConfig().CapDrop, "MKNOD", "SETFCAP")
+		})
+
+		it("doesn't mount the socket of the SSH agent", func() {
+			h.AssertSliceNotContains(t, configProvider.HostConfig().Binds, providedSSHAgentSocket+":"+build.SSHAgentSocketPath)
+		})
+
 		when("experimental is false", func() {
 			it.Before(func() {
 				experimental = false
@@ -2210,6 +2392,11 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			h.AssertEq(t, configProvider.ContainerConfig().User, "root")
 		})
 
+		it("isolates the phase", func() {
+			h.AssertSliceContains(t, configProvider.HostConfig().CapDrop, "AUDIT_WRITE", "SYS_CHROOT")
+			h.AssertSliceNotContains(t, configProvider.HostConfig().CapDrop, "MKNOD", "SETFCAP")
+		})
+
 		when("experimental is false", func() {
 			it.Before(func() {
 				experimental = false
@@ -2625,6 +2812,22 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("exporting in parallel", func() {
+			lifecycleOps = append(lifecycleOps, func(opts *build.LifecycleOptions) {
+				opts.ParallelExport = true
+			})
+
+			it("configures the phase with env CNB_PARALLEL_EXPORT", func() {
+				h.AssertSliceContains(t, configProvider.ContainerConfig().Env, "CNB_PARALLEL_EXPORT=true")
+			})
+		})
+
+		when("exporting serially", func() {
+			it("doesn't configure the phase with env CNB_PARALLEL_EXPORT", func() {
+				h.AssertSliceNotContains(t, configProvider.ContainerConfig().Env, "CNB_PARALLEL_EXPORT=true")
+			})
+		})
+
 		when("--creation-time", func() {
 			when("platform < 0.9", func() {
 				platformAPI = api.MustParse("0.8")
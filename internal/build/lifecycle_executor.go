@@ -11,6 +11,7 @@ import (
 	"github.com/buildpacks/lifecycle/platform/files"
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
 
 	"github.com/buildpacks/pack/internal/builder"
 	"github.com/buildpacks/pack/internal/container"
@@ -66,6 +67,9 @@ type Termui interface {
 	ReadLayers(reader io.ReadCloser) error
 }
 
+// PhaseObserver is called with the name of a lifecycle phase, such as detector or creator, once its container ran
+type PhaseObserver func(phase string, duration time.Duration)
+
 type LifecycleOptions struct {
 	AppPath                         string
 	Image                           name.Reference
@@ -77,6 +81,11 @@ type LifecycleOptions struct {
 	FetchRunImageWithLifecycleLayer func(name string) (string, error)
 	ProjectMetadata                 files.ProjectMetadata
 	ClearCache                      bool
+	ClearBuildCache                 bool     // optional - clears the volume or bind build cache, reusing the layers of the previous image
+	ClearLaunchCache                bool     // optional - clears the launch cache volume
+	ClearBuildpackCaches            []string // optional - IDs of the buildpacks whose layers aren't restored from the volume or bind build cache
+	CacheSizeLimit                  int64    // optional - bytes the layers of the volume or bind build cache are pruned to before restoring them
+	ParallelExport                  bool     // optional - exports the app image and the build cache at once rather than one after the other
 	Publish                         bool
 	TrustBuilder                    bool
 	UseCreator                      bool
@@ -103,6 +112,25 @@ type LifecycleOptions struct {
 	SBOMDestinationDir              string
 	CreationTime                    *time.Time
 	Keychain                        authn.Keychain
+	LogLevel                        string          // optional - the lifecycle log level, defaults to debug when the logger is verbose
+	LifecycleEnv                    []string        // optional - additional KEY=VALUE platform env set on every lifecycle phase
+	OutputObserver                  io.Writer       // optional - also receives the info output of every lifecycle phase
+	PhaseObserver                   PhaseObserver   // optional - called with the duration of every lifecycle phase container that ran
+	Logger                          logging.Logger  // optional - used instead of the executor's logger
+	Memory                          int64           // optional - memory limit in bytes of the containers running buildpacks
+	NanoCPUs                        int64           // optional - CPU limit in units of 1e-9 CPUs of the containers running buildpacks
+	PidsLimit                       int64           // optional - process limit of the containers running buildpacks
+	ReadOnly                        bool            // optional - runs the containers with a read-only root filesystem
+	CapAdd                          []string        // optional - capabilities added to the containers
+	CapDrop                         []string        // optional - capabilities dropped from the containers, in addition to NET_RAW
+	SecurityOpt                     []string        // optional - security options of the containers, overriding the defaults with the same key
+	UsernsRemap                     bool            // optional - the daemon remaps user namespaces, so the ownership of volumes is restored after copying the app
+	Platform                        *specs.Platform // optional - the platform of the lifecycle containers, when building for another platform than the daemon's
+	GzipApp                         bool            // optional - gzips the app copied to the containers, for daemons at the end of a slow link such as SSH
+	DetectOnly                      bool            // optional - only runs the detect phase, without building the image
+	DetectDestinationDir            string          // optional - where the group.toml and plan.toml of a DetectOnly run are copied to
+	ExtraHosts                      []string        // optional - host:ip entries added to /etc/hosts of the containers, where ip may be host-gateway
+	SSHAgentSocket                  string          // optional - socket of the daemon's host forwarding to the SSH agent, mounted in the containers running buildpacks
 }
 
 func NewLifecycleExecutor(logger logging.Logger, docker DockerClient) *LifecycleExecutor {
@@ -115,7 +143,12 @@ func (l *LifecycleExecutor) Execute(ctx context.Context, opts LifecycleOptions)
 		return err
 	}
 
-	lifecycleExec, err := NewLifecycleExecution(l.logger, l.docker, tmpDir, opts)
+	logger := l.logger
+	if opts.Logger != nil {
+		logger = opts.Logger
+	}
+
+	lifecycleExec, err := NewLifecycleExecution(logger, l.docker, tmpDir, opts)
 	if err != nil {
 		return err
 	}
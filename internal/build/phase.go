@@ -3,8 +3,10 @@ package build
 import (
 	"context"
 	"io"
+	"time"
 
 	dcontainer "github.com/docker/docker/api/types/container"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 
 	"github.com/buildpacks/pack/internal/container"
@@ -24,11 +26,20 @@ type Phase struct {
 	containerOps        []ContainerOperation
 	postContainerRunOps []ContainerOperation
 	fileFilter          func(string) bool
+	platform            *specs.Platform
+	observer            PhaseObserver
 }
 
 func (p *Phase) Run(ctx context.Context) error {
+	if p.observer != nil {
+		started := time.Now()
+		defer func() {
+			p.observer(p.name, time.Since(started))
+		}()
+	}
+
 	var err error
-	p.ctr, err = p.docker.ContainerCreate(ctx, p.ctrConf, p.hostConf, nil, nil, "")
+	p.ctr, err = p.docker.ContainerCreate(ctx, p.ctrConf, p.hostConf, nil, p.platform, "")
 	if err != nil {
 		return errors.Wrapf(err, "failed to create '%s' container", p.name)
 	}
@@ -7,16 +7,27 @@ import (
 	"strings"
 
 	"github.com/docker/docker/api/types/container"
+	"github.com/heroku/color"
 
 	pcontainer "github.com/buildpacks/pack/internal/container"
 	"github.com/buildpacks/pack/internal/style"
 	"github.com/buildpacks/pack/pkg/logging"
 )
 
+// defaultCapDrop are the capabilities dropped from the containers unless they are added back, which builds
+// have no use for
+var defaultCapDrop = []string{"NET_RAW"}
+
+// isolatedCapDrop are the capabilities also dropped from isolated containers, which run the Dockerfiles of image
+// extensions as root and have no use for them either. MKNOD and SETFCAP are kept, as installing packages creates
+// device files and sets file capabilities (e.g. ping).
+var isolatedCapDrop = []string{"AUDIT_WRITE", "SYS_CHROOT"}
+
 const (
 	linuxContainerAdmin   = "root"
 	windowsContainerAdmin = "ContainerAdministrator"
 	platformAPIEnvVar     = "CNB_PLATFORM_API"
+	noColorEnvVar         = "CNB_NO_COLOR"
 )
 
 type PhaseConfigProviderOperation func(*PhaseConfigProvider)
@@ -31,6 +42,7 @@ type PhaseConfigProvider struct {
 	infoWriter          io.Writer
 	errorWriter         io.Writer
 	handler             pcontainer.Handler
+	isolated            bool
 }
 
 func NewPhaseConfigProvider(name string, lifecycleExec *LifecycleExecution, ops ...PhaseConfigProviderOperation) *PhaseConfigProvider {
@@ -48,12 +60,17 @@ func NewPhaseConfigProvider(name string, lifecycleExec *LifecycleExecution, ops
 		errorWriter: logging.GetWriterForLevel(lifecycleExec.logger, logging.ErrorLevel),
 	}
 
+	if lifecycleExec.opts.OutputObserver != nil {
+		provider.infoWriter = io.MultiWriter(provider.infoWriter, lifecycleExec.opts.OutputObserver)
+	}
+
 	provider.ctrConf.Image = lifecycleExec.opts.Builder.Name()
 	provider.ctrConf.Labels = map[string]string{"author": "pack"}
 
 	if lifecycleExec.os == "windows" {
 		provider.hostConf.Isolation = container.IsolationProcess
 	}
+	provider.hostConf.ExtraHosts = lifecycleExec.opts.ExtraHosts
 
 	ops = append(ops,
 		WithEnv(fmt.Sprintf("%s=%s", platformAPIEnvVar, lifecycleExec.platformAPI.String())),
@@ -64,10 +81,28 @@ func NewPhaseConfigProvider(name string, lifecycleExec *LifecycleExecution, ops
 		}...),
 	)
 
+	for _, env := range lifecycleExec.opts.LifecycleEnv {
+		ops = append(ops, WithEnv(env))
+	}
+
+	if !color.Enabled() {
+		// keep the lifecycle output consistent with pack's own output when colors are disabled (e.g. NO_COLOR is set)
+		ops = append(ops, WithEnv(fmt.Sprintf("%s=true", noColorEnvVar)))
+	}
+
 	for _, op := range ops {
 		op(provider)
 	}
 
+	if provider.isolated {
+		// registry credentials may still have been given as lifecycle env
+		provider.ctrConf.Env = withoutRegistryAuth(provider.ctrConf.Env)
+	}
+
+	if lifecycleExec.os != "windows" {
+		harden(provider, lifecycleExec.opts)
+	}
+
 	provider.ctrConf.Entrypoint = []string{""} // override entrypoint in case it is set
 	provider.ctrConf.Cmd = append([]string{"/cnb/lifecycle/" + name}, provider.ctrConf.Cmd...)
 
@@ -82,6 +117,17 @@ func NewPhaseConfigProvider(name string, lifecycleExec *LifecycleExecution, ops
 	lifecycleExec.logger.Debug("Host Settings:")
 	lifecycleExec.logger.Debugf("  Binds: %s", style.Symbol(strings.Join(provider.hostConf.Binds, " ")))
 	lifecycleExec.logger.Debugf("  Network Mode: %s", style.Symbol(string(provider.hostConf.NetworkMode)))
+	if len(provider.hostConf.ExtraHosts) > 0 {
+		lifecycleExec.logger.Debugf("  Extra Hosts: %s", style.Symbol(strings.Join(provider.hostConf.ExtraHosts, " ")))
+	}
+	lifecycleExec.logger.Debugf("  Security Options: %s", style.Symbol(strings.Join(displayedSecurityOpts(provider.hostConf.SecurityOpt), " ")))
+	lifecycleExec.logger.Debugf("  Capabilities: %s", style.Symbol(capabilitiesString(provider.hostConf.CapAdd, provider.hostConf.CapDrop)))
+	if provider.hostConf.ReadonlyRootfs {
+		lifecycleExec.logger.Debug("  Read-only root filesystem")
+	}
+	if provider.hostConf.Memory > 0 || provider.hostConf.NanoCPUs > 0 || provider.hostConf.PidsLimit != nil {
+		lifecycleExec.logger.Debugf("  Resources: %s", style.Symbol(resourcesString(provider.hostConf.Resources)))
+	}
 
 	if lifecycleExec.opts.Interactive {
 		provider.handler = lifecycleExec.opts.Termui.Handler()
@@ -90,6 +136,119 @@ func NewPhaseConfigProvider(name string, lifecycleExec *LifecycleExecution, ops
 	return provider
 }
 
+// harden applies the capabilities, security options and read-only root filesystem of the build to the container,
+// on top of the defaults
+func harden(provider *PhaseConfigProvider, opts LifecycleOptions) {
+	capDrop := defaultCapDrop
+	if provider.isolated {
+		capDrop = append(append([]string{}, defaultCapDrop...), isolatedCapDrop...)
+	}
+	for _, capability := range capDrop {
+		if !containsCapability(opts.CapAdd, capability) && !containsCapability(opts.CapAdd, "ALL") {
+			provider.hostConf.CapDrop = append(provider.hostConf.CapDrop, capability)
+		}
+	}
+	for _, capability := range opts.CapDrop {
+		if !containsCapability(provider.hostConf.CapDrop, capability) {
+			provider.hostConf.CapDrop = append(provider.hostConf.CapDrop, capability)
+		}
+	}
+	provider.hostConf.CapAdd = append(provider.hostConf.CapAdd, opts.CapAdd...)
+
+	for _, securityOpt := range opts.SecurityOpt {
+		provider.hostConf.SecurityOpt = overrideSecurityOpt(provider.hostConf.SecurityOpt, securityOpt)
+	}
+
+	if opts.ReadOnly {
+		provider.hostConf.ReadonlyRootfs = true
+		// the lifecycle and buildpacks still need somewhere to write temporary files
+		provider.hostConf.Tmpfs = map[string]string{"/tmp": ""}
+	}
+}
+
+// containsCapability reports whether capabilities has capability, which may be given with or without the CAP_
+// prefix and in any case as docker accepts
+func containsCapability(capabilities []string, capability string) bool {
+	for _, c := range capabilities {
+		if normalizeCapability(c) == normalizeCapability(capability) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeCapability(capability string) string {
+	return strings.TrimPrefix(strings.ToUpper(capability), "CAP_")
+}
+
+// overrideSecurityOpt adds securityOpt to securityOpts, replacing the option with the same key if there is one
+func overrideSecurityOpt(securityOpts []string, securityOpt string) []string {
+	var result []string
+	for _, existing := range securityOpts {
+		if securityOptKey(existing) != securityOptKey(securityOpt) {
+			result = append(result, existing)
+		}
+	}
+	return append(result, securityOpt)
+}
+
+// securityOptKey is the key of a security option, such as seccomp for seccomp=unconfined, which docker also accepts
+// separated by a colon
+func securityOptKey(securityOpt string) string {
+	if i := strings.IndexAny(securityOpt, "=:"); i >= 0 {
+		return securityOpt[:i]
+	}
+	return securityOpt
+}
+
+// displayedSecurityOpts are the security options as logged, with seccomp profiles, which are passed as their JSON,
+// left out
+func displayedSecurityOpts(securityOpts []string) []string {
+	var displayed []string
+	for _, securityOpt := range securityOpts {
+		if securityOptKey(securityOpt) == "seccomp" && strings.Contains(securityOpt, "{") {
+			securityOpt = "seccomp=<profile>"
+		}
+		displayed = append(displayed, securityOpt)
+	}
+	return displayed
+}
+
+func capabilitiesString(capAdd, capDrop []string) string {
+	var capabilities []string
+	for _, capability := range capAdd {
+		capabilities = append(capabilities, "+"+capability)
+	}
+	for _, capability := range capDrop {
+		capabilities = append(capabilities, "-"+capability)
+	}
+	return strings.Join(capabilities, " ")
+}
+
+func resourcesString(resources container.Resources) string {
+	var limits []string
+	if resources.Memory > 0 {
+		limits = append(limits, fmt.Sprintf("memory=%d", resources.Memory))
+	}
+	if resources.NanoCPUs > 0 {
+		limits = append(limits, fmt.Sprintf("cpus=%g", float64(resources.NanoCPUs)/1e9))
+	}
+	if resources.PidsLimit != nil {
+		limits = append(limits, fmt.Sprintf("pids-limit=%d", *resources.PidsLimit))
+	}
+	return strings.Join(limits, " ")
+}
+
+func withoutRegistryAuth(env []string) []string {
+	var result []string
+	for _, e := range env {
+		if !strings.HasPrefix(e, "CNB_REGISTRY_AUTH=") {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
 func sanitized(origEnv []string) []string {
 	var sanitizedEnv []string
 	for _, env := range origEnv {
@@ -163,6 +322,10 @@ func WithDaemonAccess(dockerHost string) PhaseConfigProviderOperation {
 		if dockerHost == "inherit" {
 			dockerHost = os.Getenv("DOCKER_HOST")
 		}
+		if strings.HasPrefix(dockerHost, "ssh://") {
+			// the containers run on the host at the other end of the connection, next to the socket of its daemon
+			dockerHost = ""
+		}
 		var bind string
 		if dockerHost == "" {
 			bind = "/var/run/docker.sock:/var/run/docker.sock"
@@ -233,12 +396,36 @@ func WithNetwork(networkMode string) PhaseConfigProviderOperation {
 	}
 }
 
+// WithResources limits the memory in bytes, CPUs in units of 1e-9 CPUs and number of processes of the container,
+// leaving the limits that are 0 unset
+func WithResources(memory, nanoCPUs, pidsLimit int64) PhaseConfigProviderOperation {
+	return func(provider *PhaseConfigProvider) {
+		if memory > 0 {
+			provider.hostConf.Memory = memory
+		}
+		if nanoCPUs > 0 {
+			provider.hostConf.NanoCPUs = nanoCPUs
+		}
+		if pidsLimit > 0 {
+			provider.hostConf.PidsLimit = &pidsLimit
+		}
+	}
+}
+
 func WithRegistryAccess(authConfig string) PhaseConfigProviderOperation {
 	return func(provider *PhaseConfigProvider) {
 		provider.ctrConf.Env = append(provider.ctrConf.Env, fmt.Sprintf(`CNB_REGISTRY_AUTH=%s`, authConfig))
 	}
 }
 
+// WithIsolation runs the container without registry credentials and with fewer capabilities, for phases running
+// the Dockerfiles of image extensions, which come from the builder and are run as root
+func WithIsolation() PhaseConfigProviderOperation {
+	return func(provider *PhaseConfigProvider) {
+		provider.isolated = true
+	}
+}
+
 func WithRoot() PhaseConfigProviderOperation {
 	return func(provider *PhaseConfigProvider) {
 		if provider.os == "windows" {
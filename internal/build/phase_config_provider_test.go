@@ -61,6 +61,98 @@ func testPhaseConfigProvider(t *testing.T, when spec.G, it spec.S) {
 			h.AssertEq(t, phaseConfigProvider.HostConfig().Isolation, container.IsolationEmpty)
 			h.AssertEq(t, phaseConfigProvider.HostConfig().UsernsMode, container.UsernsMode("host"))
 			h.AssertSliceContains(t, phaseConfigProvider.HostConfig().SecurityOpt, "no-new-privileges=true")
+			h.AssertEq(t, phaseConfigProvider.HostConfig().CapDrop, strslice.StrSlice{"NET_RAW"})
+			h.AssertEq(t, phaseConfigProvider.HostConfig().ReadonlyRootfs, false)
+		})
+
+		when("hardening options are provided", func() {
+			it("applies them on top of the defaults", func() {
+				lifecycle := newTestLifecycleExec(t, false, "some-temp-dir", func(opts *build.LifecycleOptions) {
+					opts.ReadOnly = true
+					opts.CapAdd = []string{"cap_net_raw"}
+					opts.CapDrop = []string{"SYS_CHROOT"}
+					opts.SecurityOpt = []string{"no-new-privileges=false", "seccomp=some-profile.json"}
+				})
+
+				phaseConfigProvider := build.NewPhaseConfigProvider("some-name", lifecycle)
+
+				h.AssertEq(t, phaseConfigProvider.HostConfig().CapAdd, strslice.StrSlice{"cap_net_raw"})
+				h.AssertEq(t, phaseConfigProvider.HostConfig().CapDrop, strslice.StrSlice{"SYS_CHROOT"})
+				h.AssertEq(t, phaseConfigProvider.HostConfig().SecurityOpt, []string{"no-new-privileges=false", "seccomp=some-profile.json"})
+				h.AssertEq(t, phaseConfigProvider.HostConfig().ReadonlyRootfs, true)
+				h.AssertEq(t, phaseConfigProvider.HostConfig().Tmpfs, map[string]string{"/tmp": ""})
+			})
+
+			it("keeps security options that phases need", func() {
+				lifecycle := newTestLifecycleExec(t, false, "some-temp-dir", func(opts *build.LifecycleOptions) {
+					opts.SecurityOpt = []string{"apparmor=some-profile"}
+				})
+
+				phaseConfigProvider := build.NewPhaseConfigProvider("some-name", lifecycle, build.WithDaemonAccess(""))
+
+				h.AssertEq(t, phaseConfigProvider.HostConfig().SecurityOpt, []string{"label=disable", "apparmor=some-profile"})
+			})
+		})
+
+		when("extra hosts are provided", func() {
+			it("adds them to the hosts of the container", func() {
+				lifecycle := newTestLifecycleExec(t, false, "some-temp-dir", func(opts *build.LifecycleOptions) {
+					opts.ExtraHosts = []string{"host.docker.internal:host-gateway", "db:10.0.0.5"}
+				})
+
+				phaseConfigProvider := build.NewPhaseConfigProvider("some-name", lifecycle)
+
+				h.AssertEq(t, phaseConfigProvider.HostConfig().ExtraHosts, []string{"host.docker.internal:host-gateway", "db:10.0.0.5"})
+			})
+		})
+
+		when("colors are disabled", func() {
+			it("disables colors in the lifecycle output", func() {
+				lifecycle := newTestLifecycleExec(t, false, "some-temp-dir")
+
+				phaseConfigProvider := build.NewPhaseConfigProvider("some-name", lifecycle)
+
+				h.AssertSliceContains(t, phaseConfigProvider.ContainerConfig().Env, "CNB_NO_COLOR=true")
+			})
+		})
+
+		when("colors are enabled", func() {
+			it("leaves colors in the lifecycle output untouched", func() {
+				color.Disable(false)
+				defer color.Disable(true)
+				lifecycle := newTestLifecycleExec(t, false, "some-temp-dir")
+
+				phaseConfigProvider := build.NewPhaseConfigProvider("some-name", lifecycle)
+
+				h.AssertSliceNotContains(t, phaseConfigProvider.ContainerConfig().Env, "CNB_NO_COLOR=true")
+			})
+		})
+
+		when("lifecycle env is provided", func() {
+			it("sets the env on the container", func() {
+				lifecycle := newTestLifecycleExec(t, false, "some-temp-dir", func(opts *build.LifecycleOptions) {
+					opts.LifecycleEnv = []string{"CNB_EXPERIMENTAL_MODE=warn", "SOME_KEY=some-value"}
+				})
+
+				phaseConfigProvider := build.NewPhaseConfigProvider("some-name", lifecycle)
+
+				h.AssertSliceContains(t, phaseConfigProvider.ContainerConfig().Env, "CNB_EXPERIMENTAL_MODE=warn", "SOME_KEY=some-value")
+			})
+		})
+
+		when("an output observer is provided", func() {
+			it("also writes the info output to the observer", func() {
+				var observed bytes.Buffer
+				lifecycle := newTestLifecycleExec(t, false, "some-temp-dir", func(opts *build.LifecycleOptions) {
+					opts.OutputObserver = &observed
+				})
+
+				phaseConfigProvider := build.NewPhaseConfigProvider("some-name", lifecycle)
+
+				_, err := phaseConfigProvider.InfoWriter().Write([]byte("Reusing cache layer 'some/buildpack:layer'\n"))
+				h.AssertNil(t, err)
+				h.AssertEq(t, observed.String(), "Reusing cache layer 'some/buildpack:layer'\n")
+			})
 		})
 
 		when("building for Windows", func() {
@@ -75,6 +167,7 @@ func testPhaseConfigProvider(t *testing.T, when spec.G, it spec.S) {
 
 				h.AssertEq(t, phaseConfigProvider.HostConfig().Isolation, container.IsolationProcess)
 				h.AssertSliceNotContains(t, phaseConfigProvider.HostConfig().SecurityOpt, "no-new-privileges=true")
+				h.AssertEq(t, len(phaseConfigProvider.HostConfig().CapDrop), 0)
 			})
 		})
 
@@ -156,6 +249,21 @@ func testPhaseConfigProvider(t *testing.T, when spec.G, it spec.S) {
 				})
 			})
 
+			when("the daemon is reached over SSH", func() {
+				it("gives the containers the socket of the daemon on its host", func() {
+					lifecycle := newTestLifecycleExec(t, false, "some-temp-dir")
+
+					phaseConfigProvider := build.NewPhaseConfigProvider(
+						"some-name",
+						lifecycle,
+						build.WithDaemonAccess("ssh://user@build-host"),
+					)
+
+					h.AssertSliceContains(t, phaseConfigProvider.HostConfig().Binds, "/var/run/docker.sock:/var/run/docker.sock")
+					h.AssertSliceNotContains(t, phaseConfigProvider.ContainerConfig().Env, "DOCKER_HOST=ssh://user@build-host")
+				})
+			})
+
 			when("building for Windows", func() {
 				it("sets daemon access on the config", func() {
 					fakeBuilderImage := ifakes.NewImage("fake-builder", "", nil)
@@ -223,6 +331,35 @@ func testPhaseConfigProvider(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("called with WithResources", func() {
+			it("sets the resource limits on the host config", func() {
+				lifecycle := newTestLifecycleExec(t, false, "some-temp-dir")
+
+				phaseConfigProvider := build.NewPhaseConfigProvider(
+					"some-name",
+					lifecycle,
+					build.WithResources(1024, 500000000, 64),
+				)
+
+				h.AssertEq(t, phaseConfigProvider.HostConfig().Memory, int64(1024))
+				h.AssertEq(t, phaseConfigProvider.HostConfig().NanoCPUs, int64(500000000))
+				h.AssertEq(t, *phaseConfigProvider.HostConfig().PidsLimit, int64(64))
+			})
+
+			it("leaves the limits that are 0 unset", func() {
+				lifecycle := newTestLifecycleExec(t, false, "some-temp-dir")
+
+				phaseConfigProvider := build.NewPhaseConfigProvider(
+					"some-name",
+					lifecycle,
+					build.WithResources(1024, 0, 0),
+				)
+
+				h.AssertEq(t, phaseConfigProvider.HostConfig().NanoCPUs, int64(0))
+				h.AssertNil(t, phaseConfigProvider.HostConfig().PidsLimit)
+			})
+		})
+
 		when("called with WithRegistryAccess", func() {
 			it("sets registry access on the config", func() {
 				lifecycle := newTestLifecycleExec(t, false, "some-temp-dir")
@@ -242,6 +379,27 @@ func testPhaseConfigProvider(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("called with WithIsolation", func() {
+			it("withholds registry credentials and drops more capabilities", func() {
+				lifecycle := newTestLifecycleExec(t, false, "some-temp-dir", func(opts *build.LifecycleOptions) {
+					opts.LifecycleEnv = []string{"CNB_REGISTRY_AUTH=some-auth-config", "SOME_KEY=some-value"}
+					opts.CapAdd = []string{"SYS_CHROOT"}
+				})
+
+				phaseConfigProvider := build.NewPhaseConfigProvider(
+					"some-name",
+					lifecycle,
+					build.WithRegistryAccess("some-other-auth-config"),
+					build.WithIsolation(),
+				)
+
+				h.AssertSliceContains(t, phaseConfigProvider.ContainerConfig().Env, "SOME_KEY=some-value")
+				h.AssertSliceNotContains(t, phaseConfigProvider.ContainerConfig().Env, "CNB_REGISTRY_AUTH=some-auth-config")
+				h.AssertSliceNotContains(t, phaseConfigProvider.ContainerConfig().Env, "CNB_REGISTRY_AUTH=some-other-auth-config")
+				h.AssertEq(t, phaseConfigProvider.HostConfig().CapDrop, strslice.StrSlice{"NET_RAW", "AUDIT_WRITE"})
+			})
+		})
+
 		when("called with WithRoot", func() {
 			when("building for non-Windows", func() {
 				it("sets root user on the config", func() {
@@ -321,7 +479,7 @@ func testPhaseConfigProvider(t *testing.T, when spec.G, it spec.S) {
 
 				h.AssertContains(t, outBuf.String(), "Running the 'some-name' on OS")
 				h.AssertContains(t, outBuf.String(), "Args: '/cnb/lifecycle/some-name'")
-				h.AssertContains(t, outBuf.String(), "System Envs: 'CNB_PLATFORM_API=0.4'")
+				h.AssertContains(t, outBuf.String(), "System Envs: 'CNB_PLATFORM_API=0.4 CNB_NO_COLOR=true'")
 				h.AssertContains(t, outBuf.String(), "Image: 'some-builder-name'")
 				h.AssertContains(t, outBuf.String(), "User:")
 				h.AssertContains(t, outBuf.String(), "Labels: 'map[author:pack]'")
@@ -356,7 +514,7 @@ func testPhaseConfigProvider(t *testing.T, when spec.G, it spec.S) {
 						build.WithRegistryAccess(authConfig),
 					)
 
-					h.AssertContains(t, outBuf.String(), "System Envs: 'CNB_REGISTRY_AUTH=<redacted> CNB_PLATFORM_API=0.4'")
+					h.AssertContains(t, outBuf.String(), "System Envs: 'CNB_REGISTRY_AUTH=<redacted> CNB_PLATFORM_API=0.4 CNB_NO_COLOR=true'")
 				})
 			})
 		})
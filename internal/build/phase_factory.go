@@ -36,5 +36,7 @@ func (m *DefaultPhaseFactory) New(provider *PhaseConfigProvider) RunnerCleaner {
 		containerOps:        provider.containerOps,
 		postContainerRunOps: provider.postContainerRunOps,
 		fileFilter:          m.lifecycleExec.opts.FileFilter,
+		platform:            m.lifecycleExec.opts.Platform,
+		observer:            m.lifecycleExec.opts.PhaseObserver,
 	}
 }
@@ -0,0 +1,350 @@
+// Package ciconfig writes the configuration of a build as the pipeline of a CI system, a Tekton PipelineRun or a
+// GitHub Actions workflow, so that a build run locally with pack can move to CI as it is.
+package ciconfig
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// Formats of the pipelines a build is exported to
+const (
+	FormatTekton        = "tekton"
+	FormatGitHubActions = "gha"
+)
+
+// Formats are the formats a build can be exported to
+var Formats = []string{FormatTekton, FormatGitHubActions}
+
+// Versions of the tasks and actions the pipelines use
+const (
+	tektonGitCloneVersion         = "0.9"
+	tektonBuildpacksVersion       = "0.6"
+	tektonBuildpacksPhasesVersion = "0.2"
+	checkoutAction                = "actions/checkout@v4"
+	setupPackAction               = "buildpacks/github-actions/setup-pack@v5.0.0"
+	dockerLoginAction             = "docker/login-action@v3"
+)
+
+// gitURLPlaceholder is the git URL of the PipelineRun when the app isn't in a git repository with a remote
+const gitURLPlaceholder = "https://example.com/your/app.git"
+
+// Build is the configuration of a build to reproduce in CI
+type Build struct {
+	// Image is the name of the image, which CI publishes
+	Image string
+
+	// Builder is the builder image
+	Builder string
+
+	// RunImage overrides the run image of the builder, if set
+	RunImage string
+
+	// Buildpacks used instead of the order of the builder, if any
+	Buildpacks []string
+
+	// Env of the build
+	Env map[string]string
+
+	// TrustBuilder runs the creator of the builder in a single container, rather than the phases of the lifecycle in
+	// distinct ones
+	TrustBuilder bool
+
+	// AppPath is the path of the app in its git repository, in the form of a slash separated relative path
+	AppPath string
+
+	// GitURL is the URL of the git repository of the app, if known
+	GitURL string
+}
+
+// Write writes the build as a pipeline in format
+func Write(w io.Writer, format string, b Build) error {
+	var (
+		pipeline interface{}
+		err      error
+	)
+	switch format {
+	case FormatTekton:
+		pipeline, err = tektonPipelineRun(b)
+	case FormatGitHubActions:
+		pipeline, err = gitHubActionsWorkflow(b)
+	default:
+		return errors.Errorf("unknown format %s, must be one of %s", style.Symbol(format), strings.Join(Formats, ", "))
+	}
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("# Generated by 'pack build --export-config %s' for image %s\n", format, b.Image))
+	if len(b.Env) > 0 {
+		// the pipeline is written to stdout, so this is a comment of the pipeline rather than a warning of the logger
+		buf.WriteString("# The env vars of the build are written as they are, move the secret ones to the secrets of your CI system\n")
+	}
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(pipeline); err != nil {
+		return errors.Wrapf(err, "writing %s pipeline", format)
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+type param struct {
+	Name  string      `yaml:"name"`
+	Value interface{} `yaml:"value,omitempty"`
+}
+
+type paramSpec struct {
+	Name    string `yaml:"name"`
+	Type    string `yaml:"type"`
+	Default string `yaml:"default,omitempty"`
+}
+
+type taskRef struct {
+	Resolver string  `yaml:"resolver"`
+	Params   []param `yaml:"params"`
+}
+
+type workspaceBinding struct {
+	Name      string `yaml:"name"`
+	Workspace string `yaml:"workspace"`
+}
+
+type pipelineTask struct {
+	Name       string             `yaml:"name"`
+	RunAfter   []string           `yaml:"runAfter,omitempty"`
+	TaskRef    taskRef            `yaml:"taskRef"`
+	Params     []param            `yaml:"params"`
+	Workspaces []workspaceBinding `yaml:"workspaces"`
+}
+
+// hubTask refers to a task of the Tekton catalog on Tekton Hub
+func hubTask(name, version string) taskRef {
+	return taskRef{
+		Resolver: "hub",
+		Params: []param{
+			{Name: "kind", Value: "task"},
+			{Name: "name", Value: name},
+			{Name: "version", Value: version},
+		},
+	}
+}
+
+// tektonPipelineRun clones the repository of the app and builds it with the buildpacks task of the Tekton catalog,
+// or with its buildpacks-phases task when the builder isn't trusted
+func tektonPipelineRun(b Build) (interface{}, error) {
+	if len(b.Buildpacks) > 0 {
+		return nil, errors.New("tekton pipelines only use the buildpacks of the builder, as the buildpacks tasks of the Tekton catalog do")
+	}
+
+	buildTask := hubTask("buildpacks", tektonBuildpacksVersion)
+	if !b.TrustBuilder {
+		buildTask = hubTask("buildpacks-phases", tektonBuildpacksPhasesVersion)
+	}
+
+	buildParams := []param{
+		{Name: "APP_IMAGE", Value: b.Image},
+		{Name: "BUILDER_IMAGE", Value: b.Builder},
+	}
+	if b.AppPath != "" && b.AppPath != "." {
+		buildParams = append(buildParams, param{Name: "SOURCE_SUBPATH", Value: b.AppPath})
+	}
+	if b.RunImage != "" {
+		buildParams = append(buildParams, param{Name: "RUN_IMAGE", Value: b.RunImage})
+	}
+	if len(b.Env) > 0 {
+		buildParams = append(buildParams, param{Name: "ENV_VARS", Value: envPairs(b.Env)})
+	}
+
+	gitURL := b.GitURL
+	if gitURL == "" {
+		gitURL = gitURLPlaceholder
+	}
+
+	return map[string]interface{}{
+		"apiVersion": "tekton.dev/v1",
+		"kind":       "PipelineRun",
+		"metadata": map[string]interface{}{
+			"generateName": resourceName(b.Image) + "-build-",
+		},
+		"spec": map[string]interface{}{
+			"params": []param{
+				{Name: "git-url", Value: gitURL},
+				{Name: "git-revision", Value: "main"},
+			},
+			"pipelineSpec": map[string]interface{}{
+				"params": []paramSpec{
+					{Name: "git-url", Type: "string"},
+					{Name: "git-revision", Type: "string", Default: "main"},
+				},
+				"workspaces": []map[string]string{{"name": "source"}},
+				"tasks": []pipelineTask{
+					{
+						Name:    "fetch-source",
+						TaskRef: hubTask("git-clone", tektonGitCloneVersion),
+						Params: []param{
+							{Name: "url", Value: "$(params.git-url)"},
+							{Name: "revision", Value: "$(params.git-revision)"},
+						},
+						Workspaces: []workspaceBinding{{Name: "output", Workspace: "source"}},
+					},
+					{
+						Name:       "build",
+						RunAfter:   []string{"fetch-source"},
+						TaskRef:    buildTask,
+						Params:     buildParams,
+						Workspaces: []workspaceBinding{{Name: "source", Workspace: "source"}},
+					},
+				},
+			},
+			"workspaces": []map[string]interface{}{{
+				"name": "source",
+				"volumeClaimTemplate": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"accessModes": []string{"ReadWriteOnce"},
+						"resources":   map[string]interface{}{"requests": map[string]string{"storage": "1Gi"}},
+					},
+				},
+			}},
+		},
+	}, nil
+}
+
+type workflow struct {
+	Name string                 `yaml:"name"`
+	On   map[string]interface{} `yaml:"on"`
+	Jobs map[string]workflowJob `yaml:"jobs"`
+}
+
+type workflowJob struct {
+	RunsOn string         `yaml:"runs-on"`
+	Steps  []workflowStep `yaml:"steps"`
+}
+
+type workflowStep struct {
+	Name string      `yaml:"name,omitempty"`
+	Uses string      `yaml:"uses,omitempty"`
+	With interface{} `yaml:"with,omitempty"`
+	Run  string      `yaml:"run,omitempty"`
+}
+
+type dockerLogin struct {
+	Registry string `yaml:"registry,omitempty"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+// gitHubActionsWorkflow builds the app with pack on each push to main, publishing the image with the credentials of
+// the REGISTRY_USERNAME and REGISTRY_PASSWORD secrets of the repository
+func gitHubActionsWorkflow(b Build) (interface{}, error) {
+	ref, err := name.ParseReference(b.Image, name.WeakValidation)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid image name %s", style.Symbol(b.Image))
+	}
+
+	args := []string{"pack", "build", b.Image, "--builder", b.Builder}
+	if b.AppPath != "" && b.AppPath != "." {
+		args = append(args, "--path", b.AppPath)
+	}
+	if b.RunImage != "" {
+		args = append(args, "--run-image", b.RunImage)
+	}
+	for _, buildpack := range b.Buildpacks {
+		args = append(args, "--buildpack", buildpack)
+	}
+	for _, pair := range envPairs(b.Env) {
+		args = append(args, "--env", pair)
+	}
+	if b.TrustBuilder {
+		args = append(args, "--trust-builder")
+	}
+	args = append(args, "--publish")
+
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = shellQuote(arg)
+	}
+
+	login := dockerLogin{
+		Username: "${{ secrets.REGISTRY_USERNAME }}",
+		Password: "${{ secrets.REGISTRY_PASSWORD }}",
+	}
+	// the action logs in to Docker Hub when no registry is given
+	if registry := ref.Context().RegistryStr(); registry != name.DefaultRegistry {
+		login.Registry = registry
+	}
+
+	return workflow{
+		Name: "Build " + b.Image,
+		On: map[string]interface{}{
+			"push": map[string][]string{"branches": {"main"}},
+		},
+		Jobs: map[string]workflowJob{
+			"build": {
+				RunsOn: "ubuntu-latest",
+				Steps: []workflowStep{
+					{Uses: checkoutAction},
+					{Uses: setupPackAction},
+					{Uses: dockerLoginAction, With: login},
+					{Name: "Build", Run: strings.Join(quoted, " ")},
+				},
+			},
+		},
+	}, nil
+}
+
+// envPairs are the env vars in the form KEY=VALUE, sorted by key
+func envPairs(env map[string]string) []string {
+	var pairs []string
+	for k, v := range env {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return pairs
+}
+
+var (
+	unquotedArg       = regexp.MustCompile(`^[A-Za-z0-9_./:@=,+-]+$`)
+	invalidNameChars  = regexp.MustCompile(`[^a-z0-9-]+`)
+	maxGenerateLength = 40
+)
+
+// shellQuote quotes arg for sh, unless it only has characters sh doesn't interpret
+func shellQuote(arg string) string {
+	if unquotedArg.MatchString(arg) {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// resourceName is a name for the resources of the image, a DNS label made of the last part of its repository
+func resourceName(image string) string {
+	repository := image
+	if ref, err := name.ParseReference(image, name.WeakValidation); err == nil {
+		repository = ref.Context().RepositoryStr()
+	}
+
+	resource := strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(path.Base(repository)), "-"), "-")
+	if len(resource) > maxGenerateLength {
+		resource = strings.TrimRight(resource[:maxGenerateLength], "-")
+	}
+	if resource == "" {
+		return "pack"
+	}
+	return resource
+}
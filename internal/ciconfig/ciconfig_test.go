@@ -0,0 +1,122 @@
+package ciconfig_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"gopkg.in/yaml.v3"
+
+	"github.com/buildpacks/pack/internal/ciconfig"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestCIConfig(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "CIConfig", testCIConfig, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testCIConfig(t *testing.T, when spec.G, it spec.S) {
+	var (
+		out bytes.Buffer
+		b   ciconfig.Build
+	)
+
+	it.Before(func() {
+		out.Reset()
+		b = ciconfig.Build{
+			Image:   "registry.example.com/team/my_app:latest",
+			Builder: "paketobuildpacks/builder-jammy-base",
+			Env:     map[string]string{"BP_JVM_VERSION": "21", "BP_GREETING": "hello world"},
+			AppPath: "services/api",
+			GitURL:  "https://github.com/example/app.git",
+		}
+	})
+
+	when("#Write", func() {
+		when("tekton", func() {
+			it("writes a PipelineRun building the app with the buildpacks-phases task for an untrusted builder", func() {
+				h.AssertNil(t, ciconfig.Write(&out, ciconfig.FormatTekton, b))
+
+				h.AssertContains(t, out.String(), "# Generated by 'pack build --export-config tekton' for image registry.example.com/team/my_app:latest\n")
+				h.AssertContains(t, out.String(), "kind: PipelineRun\n")
+				h.AssertContains(t, out.String(), "generateName: my-app-build-\n")
+				h.AssertContains(t, out.String(), "value: https://github.com/example/app.git\n")
+				h.AssertContains(t, out.String(), "value: buildpacks-phases\n")
+				h.AssertContains(t, out.String(), `
+          - name: APP_IMAGE
+            value: registry.example.com/team/my_app:latest
+          - name: BUILDER_IMAGE
+            value: paketobuildpacks/builder-jammy-base
+          - name: SOURCE_SUBPATH
+            value: services/api
+          - name: ENV_VARS
+            value:
+              - BP_GREETING=hello world
+              - BP_JVM_VERSION=21
+`)
+
+				var pipelineRun map[string]interface{}
+				h.AssertNil(t, yaml.Unmarshal(out.Bytes(), &pipelineRun))
+				h.AssertEq(t, pipelineRun["apiVersion"], "tekton.dev/v1")
+			})
+
+			it("uses the buildpacks task for a trusted builder", func() {
+				b.TrustBuilder = true
+
+				h.AssertNil(t, ciconfig.Write(&out, ciconfig.FormatTekton, b))
+				h.AssertContains(t, out.String(), "value: buildpacks\n")
+				h.AssertNotContains(t, out.String(), "buildpacks-phases")
+			})
+
+			it("uses a placeholder for an unknown repository", func() {
+				b.GitURL = ""
+
+				h.AssertNil(t, ciconfig.Write(&out, ciconfig.FormatTekton, b))
+				h.AssertContains(t, out.String(), "value: https://example.com/your/app.git\n")
+			})
+
+			it("errors for buildpacks other than those of the builder", func() {
+				b.Buildpacks = []string{"paketo-buildpacks/java"}
+
+				err := ciconfig.Write(&out, ciconfig.FormatTekton, b)
+				h.AssertError(t, err, "tekton pipelines only use the buildpacks of the builder")
+			})
+		})
+
+		when("gha", func() {
+			it("writes a workflow running pack with the configuration of the build", func() {
+				b.Buildpacks = []string{"paketo-buildpacks/java"}
+				b.TrustBuilder = true
+
+				h.AssertNil(t, ciconfig.Write(&out, ciconfig.FormatGitHubActions, b))
+
+				h.AssertContains(t, out.String(), "# The env vars of the build are written as they are, move the secret ones to the secrets of your CI system\n")
+				h.AssertContains(t, out.String(), "uses: buildpacks/github-actions/setup-pack@v5.0.0\n")
+				h.AssertContains(t, out.String(), "registry: registry.example.com\n")
+				h.AssertContains(t, out.String(), "password: ${{ secrets.REGISTRY_PASSWORD }}\n")
+				h.AssertContains(t, out.String(), "run: pack build registry.example.com/team/my_app:latest --builder paketobuildpacks/builder-jammy-base "+
+					"--path services/api --buildpack paketo-buildpacks/java --env 'BP_GREETING=hello world' --env BP_JVM_VERSION=21 --trust-builder --publish\n")
+
+				var workflow map[string]interface{}
+				h.AssertNil(t, yaml.Unmarshal(out.Bytes(), &workflow))
+				h.AssertEq(t, workflow["name"], "Build registry.example.com/team/my_app:latest")
+			})
+
+			it("logs in to Docker Hub for its images", func() {
+				b.Image = "example/app"
+
+				h.AssertNil(t, ciconfig.Write(&out, ciconfig.FormatGitHubActions, b))
+				h.AssertNotContains(t, out.String(), "registry:")
+			})
+		})
+
+		it("errors for an unknown format", func() {
+			err := ciconfig.Write(&out, "jenkins", b)
+			h.AssertError(t, err, "unknown format 'jenkins', must be one of tekton, gha")
+		})
+	})
+}
@@ -2,20 +2,30 @@ package commands
 
 import (
 	"fmt"
+	"io"
+	"net"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/buildpacks/pack/pkg/cache"
 
+	"github.com/docker/go-units"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
 
+	"github.com/buildpacks/pack/internal/ciconfig"
 	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/i18n"
 	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/internal/term"
+	"github.com/buildpacks/pack/internal/termui"
+	"github.com/buildpacks/pack/pkg/buildpack"
 	"github.com/buildpacks/pack/pkg/client"
 	"github.com/buildpacks/pack/pkg/image"
 	"github.com/buildpacks/pack/pkg/logging"
@@ -26,8 +36,18 @@ import (
 type BuildFlags struct {
 	Publish              bool
 	ClearCache           bool
+	ClearBuildCache      bool
+	ClearLaunchCache     bool
+	ClearCacheBuildpacks []string
+	CacheSizeLimit       string
+	SerialExport         bool
+	Compression          string
 	TrustBuilder         bool
 	TrustExtraBuildpacks bool
+	UseCreator           bool
+	Format               string
+	Timings              bool
+	Progress             string
 	Interactive          bool
 	Sparse               bool
 	DockerHost           string
@@ -36,11 +56,26 @@ type BuildFlags struct {
 	AppPath              string
 	Builder              string
 	Registry             string
+	RegistryLookup       buildpack.RegistryLookup
 	RunImage             string
 	Platform             string
 	Policy               string
+	BuilderPullPolicy    string
+	RunImagePullPolicy   string
+	BuildpackPullPolicy  string
 	Network              string
+	AddHosts             []string
+	SSH                  string
 	DescriptorPath       string
+	Profile              string
+	All                  bool
+	NoHooks              bool
+	AllowHooks           bool
+	NoScan               bool
+	Provenance           string
+	AttachProvenance     bool
+	Force                bool
+	Jobs                 int
 	DefaultProcessType   string
 	LifecycleImage       string
 	Env                  []string
@@ -58,168 +93,709 @@ type BuildFlags struct {
 	DateTime             string
 	PreBuildpacks        []string
 	PostBuildpacks       []string
+	LifecycleLogLevel    string
+	LifecycleEnv         []string
+	Labels               []string
+	Annotations          []string
+	DebugBundle          string
+	PushRetries          int
+	Memory               string
+	CPUs                 float64
+	PidsLimit            int64
+	ReadOnly             bool
+	CapAdd               []string
+	CapDrop              []string
+	SecurityOpt          []string
+	UsernsRemap          string
+	SeccompProfile       string
+	AppArmorProfile      string
+	Output               string
+	Daemonless           bool
+	Driver               string
+	KubeContext          string
+	KubeNamespace        string
+	DetectOnly           bool
+	ExportConfig         string
+	GitCache             bool
+	SourceChecksum       string
+
+	// remoteSource is set once the source of a git or archive URL is fetched into AppPath
+	remoteSource bool
 }
 
+// lifecycleLogLevels are the values accepted by the lifecycle's -log-level flag
+var lifecycleLogLevels = []string{"debug", "info", "warn", "error"}
+
 // Build an image from source code
 func Build(logger logging.Logger, cfg config.Config, packClient PackClient) *cobra.Command {
 	var flags BuildFlags
 
 	cmd := &cobra.Command{
 		Use:     "build <image-name>",
-		Args:    cobra.ExactArgs(1),
+		Args:    buildArgs(&flags),
 		Short:   "Generate app image from source code",
 		Example: "pack build test_img --path apps/test-app --builder cnbs/sample-builder:bionic",
 		Long: "Pack Build uses Cloud Native Buildpacks to create a runnable app image from source code.\n\nPack Build " +
 			"requires an image name, which will be generated from the source code. Build defaults to the current directory, " +
 			"but you can use `--path` to specify another source code directory. Build requires a `builder`, which can either " +
 			"be provided directly to build using `--builder`, or can be set using the `set-default-builder` command. For more " +
-			"on how to use `pack build`, see: https://buildpacks.io/docs/app-developer-guide/build-an-app/.",
+			"on how to use `pack build`, see: https://buildpacks.io/docs/app-developer-guide/build-an-app/.\n\n" +
+			"The path may also be the URL of a git repository, with an optional `#<branch, tag or commit>`, which is fetched before building, " +
+			"or the http(s) URL of a .tar.gz, .tgz, .tar or .zip archive, which is downloaded and extracted before building.\n\n" +
+			"With `--all`, Pack Build builds every app declared in the project descriptor instead, e.g.\n\n" +
+			"  [[io.buildpacks.apps]]\n  path = \"services/api\"\n  image = \"registry.example.com/api\"\n\n" +
+			"Apps may set their own `builder` and `[[io.buildpacks.apps.env]]`, which take precedence over the ones of the descriptor.",
 		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
-			inputImageName := client.ParseInputImageReference(args[0])
-			if err := validateBuildFlags(&flags, cfg, inputImageName, logger); err != nil {
+			cleanup, err := fetchRemoteSource(cmd.Context(), logger, &flags)
+			if err != nil {
 				return err
 			}
+			defer cleanup()
 
-			inputPreviousImage := client.ParseInputImageReference(flags.PreviousImage)
+			if flags.All {
+				return buildApps(cmd, logger, cfg, packClient, flags)
+			}
 
-			descriptor, actualDescriptorPath, err := parseProjectToml(flags.AppPath, flags.DescriptorPath, logger)
+			descriptor, actualDescriptorPath, err := readProjectDescriptor(flags, logger)
 			if err != nil {
 				return err
 			}
+			return buildImage(cmd, logger, cfg, packClient, flags, args[0], descriptor, actualDescriptorPath)
+		}),
+	}
+	buildCommandFlags(cmd, &flags, cfg)
+	AddHelpFlag(cmd, "build")
+	return cmd
+}
 
-			if actualDescriptorPath != "" {
-				logger.Debugf("Using project descriptor located at %s", style.Symbol(actualDescriptorPath))
-			}
+// buildImage builds the app at flags.AppPath into imageName. logger is also used for the output of the build
+// itself, which allows building several apps at once with distinct loggers.
+func buildImage(cmd *cobra.Command, logger logging.Logger, cfg config.Config, packClient PackClient, flags BuildFlags, imageName string, descriptor projectTypes.Descriptor, actualDescriptorPath string) error {
+	inputImageName := client.ParseInputImageReference(imageName)
+	output, err := parseBuildOutput(flags.Output)
+	if err != nil {
+		return err
+	}
+	if output.dir != "" || output.archive != "" {
+		if inputImageName.Layout() {
+			return errors.New("output flag requires an image name rather than an OCI layout")
+		}
+		inputImageName = client.NewLayoutInputImageReference(output.dir, imageName)
+	}
+	if err := validateBuildFlags(&flags, cfg, inputImageName, logger); err != nil {
+		return err
+	}
+	// the report is the only output on stdout, so that it can be parsed, while the logs of the build go to stderr
+	var reportOut io.Writer
+	if flags.Format == buildFormatJSON {
+		reportOut = logger.Writer()
+		logger = stderrLogger(logger)
+	}
+	if output.archive != "" {
+		// the image is exported to a layout in a temporary directory, which is then archived
+		layoutDir, err := os.MkdirTemp("", "pack.output.")
+		if err != nil {
+			return errors.Wrap(err, "creating directory of the OCI layout")
+		}
+		defer os.RemoveAll(layoutDir)
+		inputImageName = client.NewLayoutInputImageReference(layoutDir, imageName)
+	}
 
-			builder := flags.Builder
-			// We only override the builder to the one in the project descriptor
-			// if it was not explicitly set by the user
-			if !cmd.Flags().Changed("builder") && descriptor.Build.Builder != "" {
-				builder = descriptor.Build.Builder
-			}
+	inputPreviousImage := client.ParseInputImageReference(flags.PreviousImage)
 
-			if builder == "" {
-				suggestSettingBuilder(logger, packClient)
-				return client.NewSoftError()
-			}
+	builder := flags.Builder
+	// We only override the builder to the one in the project descriptor
+	// if it was not explicitly set by the user
+	if !cmd.Flags().Changed("builder") && descriptor.Build.Builder != "" {
+		builder = descriptor.Build.Builder
+	}
 
-			buildpacks := flags.Buildpacks
-			extensions := flags.Extensions
+	if builder == "" && term.IsTerminalInput(cmd.InOrStdin()) && !logging.IsQuiet(logger) {
+		var err error
+		if builder, err = pickBuilder(logger, cfg, cmd.InOrStdin()); err != nil {
+			return err
+		}
+	}
 
-			env, err := parseEnv(flags.EnvFiles, flags.Env)
-			if err != nil {
-				return err
-			}
+	if builder == "" {
+		suggestSettingBuilder(logger, packClient)
+		return client.NewSoftError()
+	}
 
-			trustBuilder := isTrustedBuilder(cfg, builder) || flags.TrustBuilder
-			if trustBuilder {
-				logger.Debugf("Builder %s is trusted", style.Symbol(builder))
-				if flags.LifecycleImage != "" {
-					logger.Warn("Ignoring the provided lifecycle image as the builder is trusted, running the creator in a single container using the provided builder")
-				}
-			} else {
-				logger.Debugf("Builder %s is untrusted", style.Symbol(builder))
-				logger.Debug("As a result, the phases of the lifecycle which require root access will be run in separate trusted ephemeral containers.")
-				logger.Debug("For more information, see https://medium.com/buildpacks/faster-more-secure-builds-with-pack-0-11-0-4d0c633ca619")
-			}
+	buildpacks := flags.Buildpacks
+	extensions := flags.Extensions
+
+	env, err := parseEnv(flags.EnvFiles, flags.Env)
+	if err != nil {
+		return err
+	}
+
+	lifecycleEnv, err := parseEnv(nil, flags.LifecycleEnv)
+	if err != nil {
+		return err
+	}
+
+	labels, err := parseKeyValues("label", descriptorLabels(descriptor, inputImageName), flags.Labels)
+	if err != nil {
+		return err
+	}
 
-			if !trustBuilder && len(flags.Volumes) > 0 {
-				logger.Warn("Using untrusted builder with volume mounts. If there is sensitive data in the volumes, this may present a security vulnerability.")
+	annotations, err := parseKeyValues("annotation", descriptor.Build.Annotations, flags.Annotations)
+	if err != nil {
+		return err
+	}
+
+	// the creator is only forced on, or off, when the flag is given, otherwise it depends on whether the builder is trusted
+	var useCreator *bool
+	if cmd.Flags().Changed("use-creator") {
+		useCreator = &flags.UseCreator
+	}
+
+	trustBuilder := isTrustedBuilder(cfg, builder) || flags.TrustBuilder
+	switch {
+	case useCreator != nil && *useCreator:
+		logger.Debugf("Running the creator with builder %s, as the use-creator flag is set", style.Symbol(builder))
+		if flags.LifecycleImage != "" {
+			logger.Warn("Ignoring the provided lifecycle image as the creator runs in a single container using the provided builder")
+		}
+	case useCreator != nil:
+		logger.Debugf("Running the phases of the lifecycle in separate containers, as the use-creator flag is false")
+	case trustBuilder:
+		logger.Debugf("Builder %s is trusted", style.Symbol(builder))
+		if flags.LifecycleImage != "" {
+			logger.Warn("Ignoring the provided lifecycle image as the builder is trusted, running the creator in a single container using the provided builder")
+		}
+	default:
+		logger.Debugf("Builder %s is untrusted", style.Symbol(builder))
+		logger.Debug("As a result, the phases of the lifecycle which require root access will be run in separate trusted ephemeral containers.")
+		logger.Debug("For more information, see https://medium.com/buildpacks/faster-more-secure-builds-with-pack-0-11-0-4d0c633ca619")
+	}
+
+	if flags.ExportConfig != "" {
+		return exportBuildConfig(logger, flags, inputImageName.Name(), builder, env, trustBuilder)
+	}
+
+	if !trustBuilder && len(flags.Volumes) > 0 {
+		logger.Warn("Using untrusted builder with volume mounts. If there is sensitive data in the volumes, this may present a security vulnerability.")
+	}
+
+	stringPolicy := flags.Policy
+	if stringPolicy == "" {
+		stringPolicy = cfg.PullPolicy
+	}
+	pullPolicy, err := image.ParsePullPolicy(stringPolicy)
+	if err != nil {
+		return errors.Wrapf(err, "parsing pull policy %s", flags.Policy)
+	}
+	imagePullPolicies, err := parseImagePullPolicies(flags, cfg)
+	if err != nil {
+		return err
+	}
+
+	var lifecycleImage string
+	if flags.LifecycleImage != "" {
+		ref, err := name.ParseReference(flags.LifecycleImage)
+		if err != nil {
+			return errors.Wrapf(err, "parsing lifecycle image %s", flags.LifecycleImage)
+		}
+		lifecycleImage = ref.Name()
+	}
+
+	err = isForbiddenTag(cfg, inputImageName.Name(), lifecycleImage, builder)
+	if err != nil {
+		return errors.Wrapf(err, "forbidden image name")
+	}
+
+	var gid = -1
+	if cmd.Flags().Changed("gid") {
+		gid = flags.GID
+	}
+
+	var uid = -1
+	if cmd.Flags().Changed("uid") {
+		uid = flags.UID
+	}
+
+	dateTime, err := creationTime(logger, flags)
+	if err != nil {
+		return err
+	}
+	usernsRemap, err := parseUsernsRemap(flags.UsernsRemap)
+	if err != nil {
+		return err
+	}
+	sshAgent, err := sshAgentSocket(flags.SSH)
+	if err != nil {
+		return err
+	}
+	sizeLimit, err := cacheSizeLimit(flags.CacheSizeLimit, cfg, inputImageName.Name())
+	if err != nil {
+		return err
+	}
+	compression, err := parseCompressionFlag(flags.Compression, flags.Publish)
+	if err != nil {
+		return err
+	}
+	securityOpt, err := securityOpts(logger, cfg.SecurityProfiles, flags)
+	if err != nil {
+		return err
+	}
+	var memory int64
+	if flags.Memory != "" {
+		if memory, err = units.RAMInBytes(flags.Memory); err != nil || memory <= 0 {
+			return errors.Errorf("invalid memory limit %s, must be a size such as '512m' or '2g'", style.Symbol(flags.Memory))
+		}
+	}
+	scanConfig, err := resolveScanConfig(cfg.Scan, descriptor.Build.Scan)
+	if err != nil {
+		return err
+	}
+	if flags.NoScan {
+		scanConfig = config.Scan{}
+	}
+	checkPlan, err := policyCheck(cfg.Policy, logger)
+	if err != nil {
+		return err
+	}
+
+	hooks := descriptor.Build.Hooks
+	if flags.NoHooks {
+		hooks = projectTypes.Hooks{}
+	}
+	if !trustsDescriptor(flags) && (len(hooks.PreBuild) > 0 || len(hooks.PostBuild) > 0) {
+		logger.Warnf("Skipping the hooks of the project descriptor of a remote source, use %s to run them", style.Symbol("--allow-hooks"))
+		hooks = projectTypes.Hooks{}
+	}
+	hookDir := filepath.Dir(actualDescriptorPath)
+	appPath, err := filepath.Abs(flags.AppPath)
+	if err != nil {
+		return err
+	}
+	hookEnv := map[string]string{
+		hookEnvImage:   inputImageName.Name(),
+		hookEnvAppPath: appPath,
+	}
+	if err := runHooks(cmd.Context(), logger, "pre-build", hooks.PreBuild, hookDir, hookEnv); err != nil {
+		return err
+	}
+
+	reportDir := flags.ReportDestinationDir
+	if reportDir == "" && len(hooks.PostBuild) > 0 {
+		// post-build hooks receive the report, even when it isn't exported
+		if reportDir, err = os.MkdirTemp("", "pack-report"); err != nil {
+			return err
+		}
+		defer os.RemoveAll(reportDir)
+	}
+
+	// provenance describes an actual build, so builds producing it are never skipped
+	skipUnchanged := !flags.Force && !provenanceRequested(flags)
+
+	// the summary tells skipped builds apart, which aren't reported as built
+	var summary *client.BuildSummary
+	if !logging.IsQuiet(logger) || skipUnchanged || len(hooks.PostBuild) > 0 || provenanceRequested(flags) || reportOut != nil {
+		summary = &client.BuildSummary{}
+	}
+	var (
+		buildOutput *outputCapture
+		plan        *client.BuildPlan
+	)
+	if flags.DebugBundle != "" {
+		var stopCapture func()
+		buildOutput, stopCapture = captureOutput(logger)
+		defer stopCapture()
+
+		policyCheck := checkPlan
+		checkPlan = func(p client.BuildPlan) error {
+			plan = &p
+			if policyCheck != nil {
+				return policyCheck(p)
 			}
+			return nil
+		}
+	}
+
+	buildLogger := logger
+	lifecycleLogLevel := flags.LifecycleLogLevel
+	var progress *termui.Progress
+	if flags.Progress == buildProgressTTY {
+		progress, buildLogger = newBuildProgress(logger, inputImageName.Name())
+		if progress != nil && lifecycleLogLevel == "" {
+			// the builder only logs the buildpacks it runs at debug level
+			lifecycleLogLevel = "debug"
+		}
+	}
 
-			stringPolicy := flags.Policy
-			if stringPolicy == "" {
-				stringPolicy = cfg.PullPolicy
+	buildOpts := client.BuildOptions{
+		AppPath:              flags.AppPath,
+		Builder:              builder,
+		Registry:             flags.Registry,
+		RegistryLookup:       flags.RegistryLookup,
+		AdditionalMirrors:    getMirrors(cfg),
+		AdditionalTags:       flags.AdditionalTags,
+		RunImage:             flags.RunImage,
+		Env:                  env,
+		Image:                inputImageName.Name(),
+		Publish:              flags.Publish,
+		Daemonless:           flags.Daemonless,
+		DetectOnly:           flags.DetectOnly,
+		Driver:               flags.Driver,
+		DockerHost:           flags.DockerHost,
+		Platform:             flags.Platform,
+		PullPolicy:           pullPolicy,
+		ImagePullPolicies:    imagePullPolicies,
+		ClearCache:           flags.ClearCache,
+		ClearBuildCache:      flags.ClearBuildCache,
+		ClearLaunchCache:     flags.ClearLaunchCache,
+		ClearBuildpackCaches: flags.ClearCacheBuildpacks,
+		CacheSizeLimit:       sizeLimit,
+		SerialExport:         flags.SerialExport,
+		LayerCompression:     compression,
+		Kubernetes: client.KubernetesOptions{
+			Context:   flags.KubeContext,
+			Namespace: flags.KubeNamespace,
+		},
+		TrustBuilder: func(string) bool {
+			return trustBuilder
+		},
+		TrustExtraBuildpacks: flags.TrustExtraBuildpacks,
+		UseCreator:           useCreator,
+		Buildpacks:           buildpacks,
+		Extensions:           extensions,
+		ContainerConfig: client.ContainerConfig{
+			Network:     flags.Network,
+			ExtraHosts:  flags.AddHosts,
+			SSHAgent:    sshAgent,
+			Volumes:     flags.Volumes,
+			Memory:      memory,
+			CPUs:        flags.CPUs,
+			PidsLimit:   flags.PidsLimit,
+			ReadOnly:    flags.ReadOnly,
+			CapAdd:      flags.CapAdd,
+			CapDrop:     flags.CapDrop,
+			SecurityOpt: securityOpt,
+			UsernsRemap: usernsRemap,
+		},
+		DefaultProcessType:       flags.DefaultProcessType,
+		ProjectDescriptorBaseDir: filepath.Dir(actualDescriptorPath),
+		ProjectDescriptor:        descriptor,
+		Cache:                    flags.Cache,
+		CacheImage:               flags.CacheImage,
+		Workspace:                flags.Workspace,
+		LifecycleImage:           lifecycleImage,
+		GroupID:                  gid,
+		UserID:                   uid,
+		PreviousImage:            inputPreviousImage.Name(),
+		Interactive:              flags.Interactive,
+		SBOMDestinationDir:       flags.SBOMDestinationDir,
+		ReportDestinationDir:     reportDir,
+		CreationTime:             dateTime,
+		PreBuildpacks:            flags.PreBuildpacks,
+		PostBuildpacks:           flags.PostBuildpacks,
+		LifecycleLogLevel:        lifecycleLogLevel,
+		LifecycleEnv:             lifecycleEnv,
+		PushRetries:              flags.PushRetries,
+		Labels:                   labels,
+		Annotations:              annotations,
+		Summary:                  summary,
+		CheckPlan:                checkPlan,
+		RecordSourceDigest:       true,
+		SkipUnchanged:            skipUnchanged,
+		Logger:                   buildLogger,
+		LayoutConfig: &client.LayoutConfig{
+			Sparse:             flags.Sparse,
+			InputImage:         inputImageName,
+			PreviousInputImage: inputPreviousImage,
+			LayoutRepoDir:      cfg.LayoutRepositoryDir,
+			ArchivePath:        output.archive,
+		},
+	}
+	if progress != nil {
+		progress.Start()
+	}
+	err = packClient.Build(cmd.Context(), buildOpts)
+	if progress != nil {
+		progress.Finish(err)
+	}
+	if err != nil {
+		if flags.DebugBundle != "" {
+			cfgPath, _ := config.DefaultConfigPath()
+			if bundleErr := collectDebugBundle(cmd.Context(), logger, packClient, cfgPath, client.DebugBundleOptions{
+				Path:     flags.DebugBundle,
+				BuildLog: buildOutput.Bytes(),
+				Build:    &buildOpts,
+				Plan:     plan,
+				Err:      err,
+			}); bundleErr != nil {
+				logger.Warnf("Unable to write debug bundle: %s", bundleErr)
 			}
-			pullPolicy, err := image.ParsePullPolicy(stringPolicy)
-			if err != nil {
-				return errors.Wrapf(err, "parsing pull policy %s", flags.Policy)
+		}
+		return errors.Wrap(err, "failed to build")
+	}
+	if flags.DetectOnly {
+		return nil
+	}
+	if summary == nil || !summary.Skipped {
+		logger.Info(i18n.T(i18n.BuildSuccess, style.Symbol(inputImageName.Name())))
+		if summary != nil && !logging.IsQuiet(logger) {
+			if reportOut == nil {
+				printBuildSummary(logger, *summary)
 			}
-
-			var lifecycleImage string
-			if flags.LifecycleImage != "" {
-				ref, err := name.ParseReference(flags.LifecycleImage)
-				if err != nil {
-					return errors.Wrapf(err, "parsing lifecycle image %s", flags.LifecycleImage)
-				}
-				lifecycleImage = ref.Name()
+			if flags.Timings {
+				printBuildTimings(logger, *summary)
 			}
+		}
+	}
+	if reportOut != nil {
+		if err := writeBuildReport(reportOut, *summary); err != nil {
+			return err
+		}
+	}
 
-			err = isForbiddenTag(cfg, inputImageName.Name(), lifecycleImage, builder)
-			if err != nil {
-				return errors.Wrapf(err, "forbidden image name")
-			}
+	if err := scanImage(cmd.Context(), logger, scanConfig, inputImageName, flags.Publish); err != nil {
+		return err
+	}
+	if provenanceRequested(flags) {
+		if err := emitProvenance(cmd.Context(), cmd, logger, packClient, flags, *summary, env); err != nil {
+			return err
+		}
+	}
+
+	if len(hooks.PostBuild) > 0 {
+		hookEnv[hookEnvReport] = filepath.Join(reportDir, "report.toml")
+		if summary.Digest != "" {
+			hookEnv[hookEnvImageDigest] = summary.Digest
+		}
+	}
+	return runHooks(cmd.Context(), logger, "post-build", hooks.PostBuild, hookDir, hookEnv)
+}
 
-			var gid = -1
-			if cmd.Flags().Changed("gid") {
-				gid = flags.GID
+// buildArgs requires an image name, unless all the apps of the project descriptor are built
+func buildArgs(flags *BuildFlags) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if flags.All {
+			if len(args) > 0 {
+				return errors.New("an image name cannot be provided with --all, the image of each app is declared in the project descriptor")
 			}
+			return nil
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	}
+}
+
+// buildApps builds the apps declared in the project descriptor, up to flags.Jobs at once. The output of each
+// build is prefixed with the image of the app.
+func buildApps(cmd *cobra.Command, logger logging.Logger, cfg config.Config, packClient PackClient, flags BuildFlags) error {
+	if flags.Jobs < 1 {
+		return errors.New("jobs flag must be at least 1")
+	}
+	if flags.Interactive {
+		return errors.New("interactive mode cannot be used with --all")
+	}
+	if flags.Format == buildFormatJSON {
+		return errors.New("json format cannot be used with --all, as each build prints its own report")
+	}
+	for _, flag := range []string{"tag", "previous-image", "cache-image", "sbom-output-dir", "report-output-dir", "provenance", "debug-bundle"} {
+		if cmd.Flags().Changed(flag) {
+			return errors.Errorf("--%s cannot be used with --all, it would apply to every app", flag)
+		}
+	}
+
+	descriptor, actualDescriptorPath, err := readProjectDescriptor(flags, logger)
+	if err != nil {
+		return err
+	}
+	apps := descriptor.Build.Apps
+	if len(apps) == 0 {
+		return errors.New("--all requires a project descriptor declaring apps, e.g. [[io.buildpacks.apps]]")
+	}
+
+	for _, app := range apps {
+		if app.Builder == "" && descriptor.Build.Builder == "" && flags.Builder == "" {
+			return errors.Errorf("no builder set for app %s, set one with --builder, in the project descriptor or for the app", style.Symbol(app.Path))
+		}
+	}
+
+	logger.Infof("Building %d apps, %d at a time", len(apps), flags.Jobs)
+
+	var dashboard *termui.MultiBuild
+	if !cmd.Flags().Changed("progress") || flags.Progress == buildProgressTTY {
+		images := make([]string, len(apps))
+		for i, app := range apps {
+			images[i] = app.Image
+		}
+		dashboard = newMultiBuild(logger, images)
+	}
+
+	errs := make([]error, len(apps))
+	builds := func() {
+		var group errgroup.Group
+		group.SetLimit(flags.Jobs)
+		for i, app := range apps {
+			group.Go(func() error {
+				errs[i] = buildApp(cmd, logger, cfg, packClient, flags, app, descriptor, actualDescriptorPath, dashboard)
+				return nil
+			})
+		}
+		_ = group.Wait()
+	}
+	if dashboard == nil {
+		builds()
+	} else if err := runMultiBuild(cmd, logger, dashboard, builds); err != nil {
+		return err
+	}
 
-			var uid = -1
-			if cmd.Flags().Changed("uid") {
-				uid = flags.UID
+	failed := 0
+	for i, err := range errs {
+		if err != nil {
+			failed++
+			if dashboard != nil {
+				logMultiBuild(logger, dashboard, apps[i].Image)
 			}
+			logger.Errorf("Failed to build %s: %s", style.Symbol(apps[i].Image), err)
+		}
+	}
+	if failed > 0 {
+		return errors.Errorf("%d of %d apps failed to build", failed, len(apps))
+	}
+	return nil
+}
+
+// buildApp builds app, logging to its row of dashboard if any, or else with lines prefixed by its image. The warnings
+// of the build count towards those of logger.
+func buildApp(cmd *cobra.Command, logger logging.Logger, cfg config.Config, packClient PackClient, flags BuildFlags, app projectTypes.App, descriptor projectTypes.Descriptor, actualDescriptorPath string, dashboard *termui.MultiBuild) error {
+	flags.AppPath = filepath.Join(filepath.Dir(actualDescriptorPath), filepath.FromSlash(app.Path))
+	if dashboard != nil {
+		dashboard.Start(app.Image)
+		err := buildImage(cmd, multiBuildLogger(logger, dashboard, app.Image), cfg, packClient, flags, app.Image, project.ForApp(descriptor, app), actualDescriptorPath)
+		dashboard.Finish(app.Image, err)
+		return err
+	}
+
+	out := logging.NewPrefixWriter(logging.GetUncountedWriterForLevel(logger, logging.InfoLevel), app.Image)
+	defer out.Close()
+	errOut := logging.NewPrefixWriter(logging.GetUncountedWriterForLevel(logger, logging.ErrorLevel), app.Image)
+	defer errOut.Close()
+
+	opts := []func(*logging.LogWithWriters){logging.WithParent(logger)}
+	if logger.IsVerbose() {
+		opts = append(opts, logging.WithVerbose())
+	}
+	appLogger := logging.NewLogWithWriters(out, errOut, opts...)
+	appLogger.WantQuiet(logging.IsQuiet(logger))
+
+	return buildImage(cmd, appLogger, cfg, packClient, flags, app.Image, project.ForApp(descriptor, app), actualDescriptorPath)
+}
+
+// readProjectDescriptor reads the project descriptor of the build, if any, resolves its variables and applies the
+// selected profile
+func readProjectDescriptor(flags BuildFlags, logger logging.Logger) (projectTypes.Descriptor, string, error) {
+	descriptor, actualDescriptorPath, err := parseProjectToml(flags.AppPath, flags.DescriptorPath, logger)
+	if err != nil {
+		return projectTypes.Descriptor{}, "", err
+	}
+
+	if actualDescriptorPath != "" {
+		logger.Debugf("Using project descriptor located at %s", style.Symbol(actualDescriptorPath))
+
+		appPath := flags.AppPath
+		if appPath == "" {
+			appPath = "."
+		}
+		substitute := project.Substitute
+		if !trustsDescriptor(flags) {
+			logger.Debugf("Not reading the environment for the variables of the project descriptor of a remote source, use %s to read it", style.Symbol("--allow-hooks"))
+			substitute = project.SubstituteWithoutEnv
+		}
+		if descriptor, err = substitute(descriptor, appPath); err != nil {
+			return projectTypes.Descriptor{}, "", err
+		}
+	}
+
+	descriptor, err = project.WithProfile(descriptor, flags.Profile)
+	if err != nil {
+		return projectTypes.Descriptor{}, "", err
+	}
+	if flags.Profile != "" {
+		logger.Debugf("Using profile %s of the project descriptor", style.Symbol(flags.Profile))
+	}
+	return descriptor, actualDescriptorPath, nil
+}
+
+// parseImagePullPolicies are the pull policies of the builder, run image and buildpacks set by their flags, or else by
+// the pack config unless --pull-policy is set, so that the pull policy of the command line applies to every image
+func parseImagePullPolicies(flags BuildFlags, cfg config.Config) (client.ImagePullPolicies, error) {
+	var policies client.ImagePullPolicies
+	for _, p := range []struct {
+		name   string
+		flag   string
+		config string
+		policy **image.PullPolicy
+	}{
+		{"builder-pull-policy", flags.BuilderPullPolicy, cfg.PullPolicies.Builder, &policies.Builder},
+		{"run-image-pull-policy", flags.RunImagePullPolicy, cfg.PullPolicies.RunImage, &policies.RunImage},
+		{"buildpack-pull-policy", flags.BuildpackPullPolicy, cfg.PullPolicies.Buildpacks, &policies.Buildpacks},
+	} {
+		value := p.flag
+		if value == "" && flags.Policy == "" {
+			value = p.config
+		}
+		if value == "" {
+			continue
+		}
+
+		policy, err := image.ParsePullPolicy(value)
+		if err != nil {
+			return client.ImagePullPolicies{}, errors.Wrapf(err, "parsing %s %s", p.name, value)
+		}
+		*p.policy = &policy
+	}
+	return policies, nil
+}
+
+// cacheSizeLimit is the size limit of the build cache of the app image imageName, set by the cache-size-limit flag or
+// else by the config, for the app or for all of them
+func cacheSizeLimit(flag string, cfg config.Config, imageName string) (int64, error) {
+	if flag != "" {
+		return parseCacheSizeLimit(flag)
+	}
+	limit := cfg.CacheSizeLimit
+	if key, err := cacheSizeLimitKey(imageName); err == nil {
+		if appLimit, ok := cfg.CacheSizeLimits[key]; ok {
+			limit = appLimit
+		}
+	}
+	if limit == "" {
+		return 0, nil
+	}
+	size, err := parseCacheSizeLimit(limit)
+	if err != nil {
+		return 0, errors.Errorf("invalid cache size limit %s in your config file", style.Symbol(limit))
+	}
+	return size, nil
+}
 
-			dateTime, err := parseTime(flags.DateTime)
+// sourceDateEpochEnv is the env var of reproducible builds setting the creation time, when --creation-time isn't set
+const sourceDateEpochEnv = "SOURCE_DATE_EPOCH"
+
+// creationTime is the creation time of the image, set by --creation-time or else by SOURCE_DATE_EPOCH, if any
+func creationTime(logger logging.Logger, flags BuildFlags) (*time.Time, error) {
+	if flags.DateTime == "" {
+		if epoch := os.Getenv(sourceDateEpochEnv); epoch != "" {
+			logger.Debugf("Using creation time %s of %s", style.Symbol(epoch), sourceDateEpochEnv)
+			dateTime, err := parseTime(epoch)
 			if err != nil {
-				return errors.Wrapf(err, "parsing creation time %s", flags.DateTime)
-			}
-			if err := packClient.Build(cmd.Context(), client.BuildOptions{
-				AppPath:           flags.AppPath,
-				Builder:           builder,
-				Registry:          flags.Registry,
-				AdditionalMirrors: getMirrors(cfg),
-				AdditionalTags:    flags.AdditionalTags,
-				RunImage:          flags.RunImage,
-				Env:               env,
-				Image:             inputImageName.Name(),
-				Publish:           flags.Publish,
-				DockerHost:        flags.DockerHost,
-				Platform:          flags.Platform,
-				PullPolicy:        pullPolicy,
-				ClearCache:        flags.ClearCache,
-				TrustBuilder: func(string) bool {
-					return trustBuilder
-				},
-				TrustExtraBuildpacks: flags.TrustExtraBuildpacks,
-				Buildpacks:           buildpacks,
-				Extensions:           extensions,
-				ContainerConfig: client.ContainerConfig{
-					Network: flags.Network,
-					Volumes: flags.Volumes,
-				},
-				DefaultProcessType:       flags.DefaultProcessType,
-				ProjectDescriptorBaseDir: filepath.Dir(actualDescriptorPath),
-				ProjectDescriptor:        descriptor,
-				Cache:                    flags.Cache,
-				CacheImage:               flags.CacheImage,
-				Workspace:                flags.Workspace,
-				LifecycleImage:           lifecycleImage,
-				GroupID:                  gid,
-				UserID:                   uid,
-				PreviousImage:            inputPreviousImage.Name(),
-				Interactive:              flags.Interactive,
-				SBOMDestinationDir:       flags.SBOMDestinationDir,
-				ReportDestinationDir:     flags.ReportDestinationDir,
-				CreationTime:             dateTime,
-				PreBuildpacks:            flags.PreBuildpacks,
-				PostBuildpacks:           flags.PostBuildpacks,
-				LayoutConfig: &client.LayoutConfig{
-					Sparse:             flags.Sparse,
-					InputImage:         inputImageName,
-					PreviousInputImage: inputPreviousImage,
-					LayoutRepoDir:      cfg.LayoutRepositoryDir,
-				},
-			}); err != nil {
-				return errors.Wrap(err, "failed to build")
+				return nil, errors.Wrapf(err, "parsing %s %s", sourceDateEpochEnv, epoch)
 			}
-			logger.Infof("Successfully built image %s", style.Symbol(inputImageName.Name()))
-			return nil
-		}),
+			return dateTime, nil
+		}
 	}
-	buildCommandFlags(cmd, &flags, cfg)
-	AddHelpFlag(cmd, "build")
-	return cmd
+
+	dateTime, err := parseTime(flags.DateTime)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing creation time %s", flags.DateTime)
+	}
+	return dateTime, nil
 }
 
 func parseTime(providedTime string) (*time.Time, error) {
@@ -240,8 +816,11 @@ func parseTime(providedTime string) (*time.Time, error) {
 }
 
 func buildCommandFlags(cmd *cobra.Command, buildFlags *BuildFlags, cfg config.Config) {
-	cmd.Flags().StringVarP(&buildFlags.AppPath, "path", "p", "", "Path to app dir or zip-formatted file (defaults to current working directory)")
+	cmd.Flags().StringVarP(&buildFlags.AppPath, "path", "p", "", "Path to app dir or zip-formatted file (defaults to current working directory),\n  URL of a git repository to build, e.g. 'https://github.com/org/app.git#main', fetched with the credentials of git,\n  or URL of an archive to build, e.g. 'https://example.com/app-1.0.0.tar.gz'")
+	cmd.Flags().BoolVar(&buildFlags.GitCache, "git-cache", false, "Fetch the git repository given as path in a workspace of the pack home kept across builds, rather than in a temporary directory")
+	cmd.Flags().StringVar(&buildFlags.SourceChecksum, "source-checksum", "", "SHA-256 checksum the archive given as path must have, e.g. 'sha256:<checksum>'")
 	cmd.Flags().StringSliceVarP(&buildFlags.Buildpacks, "buildpack", "b", nil, "Buildpack to use. One of:\n  a buildpack by id and version in the form of '<buildpack>@<version>',\n  path to a buildpack directory (not supported on Windows),\n  path/URL to a buildpack .tar or .tgz file, or\n  a packaged buildpack image name in the form of '<hostname>/<repo>[:<tag>]'"+stringSliceHelp("buildpack"))
+	cmd.RegisterFlagCompletionFunc("buildpack", completeRegistryBuildpacks(cfg))
 	cmd.Flags().StringSliceVarP(&buildFlags.Extensions, "extension", "", nil, "Extension to use. One of:\n  an extension by id and version in the form of '<extension>@<version>',\n  path to an extension directory (not supported on Windows),\n  path/URL to an extension .tar or .tgz file, or\n  a packaged extension image name in the form of '<hostname>/<repo>[:<tag>]'"+stringSliceHelp("extension"))
 	cmd.Flags().StringVarP(&buildFlags.Builder, "builder", "B", cfg.DefaultBuilder, "Builder image")
 	cmd.Flags().Var(&buildFlags.Cache, "cache",
@@ -250,32 +829,75 @@ func buildCommandFlags(cmd *cobra.Command, buildFlags *BuildFlags, cfg config.Co
 - Cache as image (requires --publish): 'type=<build/launch>;format=image;name=<registry image name>'
 - Cache as volume: 'type=<build/launch>;format=volume;[name=<volume name>]'
     - If no name is provided, a random name will be generated.
+Repeat the flag to configure both the build and launch caches, e.g.
+  --cache 'type=build;format=image;name=registry.example.com/app:cache' --cache 'type=launch;format=volume;name=app-launch'
 `)
 	cmd.Flags().StringVar(&buildFlags.CacheImage, "cache-image", "", `Cache build layers in remote registry. Requires --publish`)
 	cmd.Flags().BoolVar(&buildFlags.ClearCache, "clear-cache", false, "Clear image's associated cache before building")
-	cmd.Flags().StringVar(&buildFlags.DateTime, "creation-time", "", "Desired create time in the output image config. Accepted values are Unix timestamps (e.g., '1641013200'), or 'now'. Platform API version must be at least 0.9 to use this feature.")
+	cmd.Flags().BoolVar(&buildFlags.ClearBuildCache, "clear-build-cache", false, "Clear only the build cache before building, still reusing the layers of the previous image. Requires a volume or bind build cache")
+	cmd.Flags().BoolVar(&buildFlags.ClearLaunchCache, "clear-launch-cache", false, "Clear only the launch cache before building")
+	cmd.Flags().StringArrayVar(&buildFlags.ClearCacheBuildpacks, "clear-cache-buildpack", nil, "ID of a buildpack whose entries of the build cache are cleared before building, so that it starts from a cold cache while the other buildpacks keep theirs. Requires a volume or bind build cache"+stringArrayHelp("buildpack"))
+	cmd.Flags().StringVar(&buildFlags.CacheSizeLimit, "cache-size-limit", "", "Size the build cache is pruned to before restoring it, evicting the least recently created layers first, e.g. '5GB'; '0' doesn't limit it. Overrides `pack config cache-size-limit`. Only applies to a volume or bind build cache")
+	cmd.Flags().StringVar(&buildFlags.Compression, "compression", string(client.GzipCompression), "Compression of the layers of the published image, 'gzip' or 'zstd'. zstd layers decompress faster, the image keeps gzip layers when the registry rejects them. Requires --publish for zstd")
+	cmd.Flags().BoolVar(&buildFlags.SerialExport, "serial-export", false, "Export the app image, then the build cache, rather than both at once, e.g. to lower the peak load on the daemon or registry")
+	cmd.Flags().StringVar(&buildFlags.DateTime, "creation-time", "", "Desired create time in the output image config. Accepted values are Unix timestamps (e.g., '1641013200'), or 'now'. Platform API version must be at least 0.9 to use this feature.\nDefaults to the SOURCE_DATE_EPOCH environment variable, if set, so that builds of the same source with the same builder produce the same image digest.")
 	cmd.Flags().StringVarP(&buildFlags.DescriptorPath, "descriptor", "d", "", "Path to the project descriptor file")
+	cmd.Flags().BoolVar(&buildFlags.All, "all", false, "Build every app declared in the project descriptor instead of a single image")
+	cmd.Flags().IntVar(&buildFlags.Jobs, "jobs", 2, "Number of apps built at once when using --all")
+	cmd.Flags().BoolVar(&buildFlags.Force, "force", false, "Build even when the image was built from the same source and configuration, as recorded by its source digest")
+	cmd.Flags().BoolVar(&buildFlags.NoHooks, "no-hooks", false, "Skip the pre-build and post-build hooks declared in the project descriptor")
+	cmd.Flags().BoolVar(&buildFlags.AllowHooks, "allow-hooks", false, "Run the hooks and read the environment variables referenced by the project descriptor of a remote source, which are skipped by default as the descriptor comes from the remote source")
+	cmd.Flags().BoolVar(&buildFlags.NoScan, "no-scan", false, "Skip the vulnerability scan configured in the project descriptor or the pack config")
+	cmd.Flags().StringVar(&buildFlags.DebugBundle, "debug-bundle", "", "Path to write a debug bundle to when the build fails, holding the output, options and plan of the build, daemon info and the pack config, with credentials redacted.\nUse with --verbose to include the full output of the lifecycle.")
+	cmd.Flags().StringVar(&buildFlags.Provenance, "provenance", "", "Path to write the SLSA provenance of the build to, as an in-toto statement")
+	cmd.Flags().BoolVar(&buildFlags.AttachProvenance, "attach-provenance", false, "Attach the SLSA provenance of the build to the published image, as an attestation listed by the referrers API of the registry. Requires --publish")
+	cmd.Flags().StringVar(&buildFlags.Profile, "profile", "", "Profile of the project descriptor to apply, e.g. 'dev' or 'prod'.\nBuild-time environment variables are merged in order of precedence, from lowest to highest:\n  the project descriptor, the selected profile, --env-file and --env.")
 	cmd.Flags().StringVarP(&buildFlags.DefaultProcessType, "default-process", "D", "", `Set the default process type. (default "web")`)
 	cmd.Flags().StringArrayVarP(&buildFlags.Env, "env", "e", []string{}, "Build-time environment variable, in the form 'VAR=VALUE' or 'VAR'.\nWhen using latter value-less form, value will be taken from current\n  environment at the time this command is executed.\nThis flag may be specified multiple times and will override\n  individual values defined by --env-file."+stringArrayHelp("env")+"\nNOTE: These are NOT available at image runtime.")
-	cmd.Flags().StringArrayVar(&buildFlags.EnvFiles, "env-file", []string{}, "Build-time environment variables file\nOne variable per line, of the form 'VAR=VALUE' or 'VAR'\nWhen using latter value-less form, value will be taken from current\n  environment at the time this command is executed\nNOTE: These are NOT available at image runtime.\"")
-	cmd.Flags().StringVar(&buildFlags.Network, "network", "", "Connect detect and build containers to network")
+	cmd.Flags().StringArrayVar(&buildFlags.EnvFiles, "env-file", []string{}, "Build-time environment variables file, following the conventions of .env files\nOne variable per line, of the form 'VAR=VALUE' or 'VAR', optionally prefixed with 'export'\nWhen using latter value-less form, value will be taken from current\n  environment at the time this command is executed\nValues may be quoted, span several lines in quotes, and reference other variables as '${VAR}'\n  or '${VAR:-default}', except in single quotes. Lines starting with '#' are comments.\nThis flag may be specified multiple times, later files overriding earlier ones.\nNOTE: These are NOT available at image runtime.")
+	cmd.Flags().StringVar(&buildFlags.Network, "network", "", "Connect detect and build containers to network, such as 'host' or a user-defined network like 'my-project_default' of docker compose")
+	cmd.Flags().StringArrayVar(&buildFlags.AddHosts, "add-host", nil, "Host added to /etc/hosts of the detect and build containers, in the form 'HOST:IP'.\nThe IP may be 'host-gateway' for the docker host, e.g. 'host.docker.internal:host-gateway' to reach its services."+stringArrayHelp("host"))
+	cmd.Flags().StringVar(&buildFlags.SSH, "ssh", "", "Forward the SSH agent to the detect and build containers, for buildpacks to fetch private dependencies, in the form 'default' or 'default=<socket>'.\n'default' is the agent of SSH_AUTH_SOCK, or of the OpenSSH named pipe on Windows.")
+	cmd.Flags().StringVar(&buildFlags.Memory, "memory", "", "Memory limit of the detect and build containers, e.g. '512m' or '2g'")
+	cmd.Flags().Float64Var(&buildFlags.CPUs, "cpus", 0, "Number of CPUs available to the detect and build containers, e.g. '1.5'")
+	cmd.Flags().Int64Var(&buildFlags.PidsLimit, "pids-limit", 0, "Maximum number of processes in the detect and build containers")
+	cmd.Flags().BoolVar(&buildFlags.ReadOnly, "read-only", false, "Run the lifecycle containers with a read-only root filesystem, with a tmpfs mounted at /tmp.\nBuilders with extensions modifying the build image aren't supported")
+	cmd.Flags().StringArrayVar(&buildFlags.CapAdd, "cap-add", nil, "Linux capability to add to the lifecycle containers, e.g. 'NET_RAW' which is dropped by default"+stringArrayHelp("capability"))
+	cmd.Flags().StringArrayVar(&buildFlags.CapDrop, "cap-drop", nil, "Linux capability to drop from the lifecycle containers, e.g. 'SYS_CHROOT'"+stringArrayHelp("capability"))
+	cmd.Flags().StringArrayVar(&buildFlags.SecurityOpt, "security-opt", nil, "Security option of the lifecycle containers, e.g. 'seccomp=/path/to/profile.json'.\nOverrides the default option with the same key, 'no-new-privileges=true'"+stringArrayHelp("security option"))
+	cmd.Flags().StringVar(&buildFlags.SeccompProfile, "seccomp-profile", "", "Path to the seccomp profile of the lifecycle containers, or 'unconfined' to run them without one.\nOverrides the profile set with `pack config security-profiles`")
+	cmd.Flags().StringVar(&buildFlags.AppArmorProfile, "apparmor-profile", "", "Name of the AppArmor profile of the lifecycle containers, loaded on the docker host, or 'unconfined' to run them without one.\nOverrides the profile set with `pack config security-profiles`")
+	cmd.Flags().StringVar(&buildFlags.UsernsRemap, "userns-remap", "auto", "Whether the daemon remaps user namespaces, one of auto, true or false.\nWhen it does, the ownership of the app and layers volumes is restored after copying the app. Defaults to detecting it from the daemon")
 	cmd.Flags().StringArrayVar(&buildFlags.PreBuildpacks, "pre-buildpack", []string{}, "Buildpacks to prepend to the groups in the builder's order")
 	cmd.Flags().StringArrayVar(&buildFlags.PostBuildpacks, "post-buildpack", []string{}, "Buildpacks to append to the groups in the builder's order")
-	cmd.Flags().BoolVar(&buildFlags.Publish, "publish", false, "Publish the application image directly to the container registry specified in <image-name>, instead of the daemon. The run image must also reside in the registry.")
+	cmd.Flags().BoolVar(&buildFlags.Publish, "publish", false, "Publish the application image directly to the container registry specified in <image-name>, instead of the daemon. The run image must also reside in the registry.\nThe lifecycle pushes the image without reporting the progress of its layers, which is only shown with --push-retries.")
+	cmd.Flags().IntVar(&buildFlags.PushRetries, "push-retries", 0, "Number of times the upload of each layer is retried when publishing, e.g. on unreliable links. When set, the image is exported to an OCI layout that pack then pushes, showing the progress of each layer and reporting the layers that reached the registry if the push fails. Requires --publish and Platform API 0.12 or later")
 	cmd.Flags().StringVar(&buildFlags.DockerHost, "docker-host", "",
 		`Address to docker daemon that will be exposed to the build container.
 If not set (or set to empty string) the standard socket location will be used.
+When the daemon is reached over SSH, the standard socket location of its host will be used.
 Special value 'inherit' may be used in which case DOCKER_HOST environment variable will be used.
+Special value 'podman' may be used in which case the Docker-compatible socket of Podman will be used,
+  which pack also uses when DOCKER_HOST=podman, or when there's no Docker socket.
 This option may set DOCKER_HOST environment variable for the build container if needed.
 `)
+	cmd.Flags().StringVar(&buildFlags.LifecycleLogLevel, "lifecycle-log-level", "", fmt.Sprintf("Log level of the lifecycle phases, one of %s.\nDefaults to debug when --verbose is set.", strings.Join(lifecycleLogLevels, ", ")))
+	cmd.Flags().StringArrayVar(&buildFlags.LifecycleEnv, "lifecycle-env", []string{}, "Platform environment variable set on every lifecycle phase, in the form 'VAR=VALUE' or 'VAR'.\nWhen using latter value-less form, value will be taken from current\n  environment at the time this command is executed.\nUseful for troubleshooting, e.g. 'CNB_EXPERIMENTAL_MODE=warn'."+stringArrayHelp("lifecycle-env")+"\nNOTE: These are NOT available to buildpacks.")
+	cmd.Flags().StringArrayVar(&buildFlags.Labels, "label", []string{}, "Label set on the config of the image, in the form 'KEY=VALUE'.\nLabels starting with 'io.buildpacks.' are reserved.\nThis flag may be specified multiple times and will override\n  individual values defined in the project descriptor, including the labels\n  of its project information and metadata."+stringArrayHelp("label"))
+	cmd.Flags().StringArrayVar(&buildFlags.Annotations, "annotation", []string{}, "Annotation set on the manifest of the image, in the form 'KEY=VALUE'.\nAnnotations are only kept by registries, so they're only set with --publish.\nThis flag may be specified multiple times and will override\n  individual values defined in the project descriptor."+stringArrayHelp("annotation"))
 	cmd.Flags().StringVar(&buildFlags.LifecycleImage, "lifecycle-image", cfg.LifecycleImage, `Custom lifecycle image to use for analysis, restore, and export when builder is untrusted.`)
-	cmd.Flags().StringVar(&buildFlags.Platform, "platform", "", `Platform to build on (e.g., "linux/amd64").`)
+	cmd.Flags().StringVar(&buildFlags.Platform, "platform", "", "Platform to build for (e.g., \"linux/arm64\").\nThe builder, run image and buildpacks are fetched for the platform, and the lifecycle\n  runs for it, under emulation when the daemon is for another platform.")
 	cmd.Flags().StringVar(&buildFlags.Policy, "pull-policy", "", `Pull policy to use. Accepted values are always, never, and if-not-present. (default "always")`)
+	cmd.Flags().StringVar(&buildFlags.BuilderPullPolicy, "builder-pull-policy", "", "Pull policy of the builder and lifecycle images, overriding --pull-policy and the pull policies of the pack config")
+	cmd.Flags().StringVar(&buildFlags.RunImagePullPolicy, "run-image-pull-policy", "", "Pull policy of the run image, overriding --pull-policy and the pull policies of the pack config")
+	cmd.Flags().StringVar(&buildFlags.BuildpackPullPolicy, "buildpack-pull-policy", "", "Pull policy of the buildpack and extension images, overriding --pull-policy and the pull policies of the pack config")
 	cmd.Flags().StringVarP(&buildFlags.Registry, "buildpack-registry", "r", cfg.DefaultRegistryName, "Buildpack Registry by name")
+	addRegistryLookupFlags(cmd, &buildFlags.RegistryLookup)
 	cmd.Flags().StringVar(&buildFlags.RunImage, "run-image", "", "Run image (defaults to default stack's run image)")
 	cmd.Flags().StringSliceVarP(&buildFlags.AdditionalTags, "tag", "t", nil, "Additional tags to push the output image to.\nTags should be in the format 'image:tag' or 'repository/image:tag'."+stringSliceHelp("tag"))
 	cmd.Flags().BoolVar(&buildFlags.TrustBuilder, "trust-builder", false, "Trust the provided builder.\nAll lifecycle phases will be run in a single container.\nFor more on trusted builders, and when to trust or untrust a builder, check out our docs here: https://buildpacks.io/docs/tools/pack/concepts/trusted_builders")
 	cmd.Flags().BoolVar(&buildFlags.TrustExtraBuildpacks, "trust-extra-buildpacks", false, "Trust buildpacks that are provided in addition to the buildpacks on the builder")
+	cmd.Flags().BoolVar(&buildFlags.UseCreator, "use-creator", false, "Run all lifecycle phases in a single container with the creator (true), or each phase in its own container (false), whether or not the builder is trusted.\nWhen not set, the creator is only used for trusted builders. Forcing the creator gives an untrusted builder access to registry credentials and the daemon.")
 	cmd.Flags().StringArrayVar(&buildFlags.Volumes, "volume", nil, "Mount host volume into the build container, in the form '<host path>:<target path>[:<options>]'.\n- 'host path': Name of the volume or absolute directory path to mount.\n- 'target path': The path where the file or directory is available in the container.\n- 'options' (default \"ro\"): An optional comma separated list of mount options.\n    - \"ro\", volume contents are read-only.\n    - \"rw\", volume contents are readable and writeable.\n    - \"volume-opt=<key>=<value>\", can be specified more than once, takes a key-value pair consisting of the option name and its value."+stringArrayHelp("volume"))
 	cmd.Flags().StringVar(&buildFlags.Workspace, "workspace", "", "Location at which to mount the app dir in the build image")
 	cmd.Flags().IntVar(&buildFlags.GID, "gid", 0, `Override GID of user's group in the stack's build and run images. The provided value must be a positive number`)
@@ -284,10 +906,24 @@ This option may set DOCKER_HOST environment variable for the build container if
 	cmd.Flags().StringVar(&buildFlags.SBOMDestinationDir, "sbom-output-dir", "", "Path to export SBoM contents.\nOmitting the flag will yield no SBoM content.")
 	cmd.Flags().StringVar(&buildFlags.ReportDestinationDir, "report-output-dir", "", "Path to export build report.toml.\nOmitting the flag yield no report file.")
 	cmd.Flags().BoolVar(&buildFlags.Interactive, "interactive", false, "Launch a terminal UI to depict the build process")
+	cmd.Flags().BoolVar(&buildFlags.Timings, "timings", false, "Print the time each lifecycle phase took, and the time each buildpack took to build.\n  Buildpacks are only timed when the lifecycle logs at debug level, e.g. with --verbose or --lifecycle-log-level debug.")
+	cmd.Flags().StringVar(&buildFlags.Progress, "progress", buildProgressPlain, "How to show the progress of the build, either plain or tty.\n  With tty, the lifecycle phases, the buildpacks and the export of the image are shown live in place of the logs, and the logs of the step that failed are printed at the end. Plain logs are shown when the output isn't a terminal.\n  With --all, a dashboard of the builds is shown on a terminal until they are done, unless the progress is set to plain; quitting it cancels the builds left.")
+	cmd.Flags().StringVar(&buildFlags.Format, "format", buildFormatHumanReadable, "Format of the summary of the build, either human-readable or json.\n  With json, a report of the image, its buildpacks, base images, processes and the time of each lifecycle phase is the only output on stdout, while the logs go to stderr.")
 	cmd.Flags().BoolVar(&buildFlags.Sparse, "sparse", false, "Use this flag to avoid saving on disk the run-image layers when the application image is exported to OCI layout format")
+	cmd.Flags().StringVar(&buildFlags.Output, "output", "", "Write the application image to an OCI layout directory, 'oci:<dir>', or to a tarball of an OCI layout, 'tar:<file>',\n  rather than to the daemon or a registry.")
+	cmd.Flags().BoolVar(&buildFlags.Daemonless, "daemonless", false, "Run the lifecycle of the builder in a sandbox on this host rather than in containers, so that no daemon is needed (Linux only).\n  Requires the publish flag and a trusted builder.")
+	cmd.Flags().BoolVar(&buildFlags.DetectOnly, "detect-only", false, "Only run the detect phase, printing the buildpacks that passed detection and the build plan they resolved, without building the image.\n  The detector logs why the other buildpacks failed detection, unless a lifecycle log level is set.")
+	cmd.Flags().StringVar(&buildFlags.ExportConfig, "export-config", "", "Print a CI pipeline reproducing the build, rather than building, one of 'tekton' for a Tekton PipelineRun or 'gha' for a GitHub Actions workflow.\n  The pipeline uses the builder, env and buildpacks of the build, and whether the builder is trusted.")
+	cmd.Flags().StringVar(&buildFlags.Driver, "driver", client.DriverDocker, "Driver running the lifecycle of the builder, 'docker' or 'kubernetes'.\n  With 'kubernetes', the lifecycle runs as a Job of the cluster of the current kubectl context, which requires the publish flag and a trusted builder.")
+	cmd.Flags().StringVar(&buildFlags.KubeContext, "kube-context", "", "Context of the kubeconfig to run the Job of the 'kubernetes' driver in, rather than the current context")
+	cmd.Flags().StringVar(&buildFlags.KubeNamespace, "kube-namespace", "", "Namespace to run the Job of the 'kubernetes' driver in, rather than that of the context")
 	if !cfg.Experimental {
 		cmd.Flags().MarkHidden("interactive")
 		cmd.Flags().MarkHidden("sparse")
+		cmd.Flags().MarkHidden("daemonless")
+		cmd.Flags().MarkHidden("driver")
+		cmd.Flags().MarkHidden("kube-context")
+		cmd.Flags().MarkHidden("kube-namespace")
 	}
 }
 
@@ -312,6 +948,75 @@ func validateBuildFlags(flags *BuildFlags, cfg config.Config, inputImageRef clie
 		return errors.New("cache-image flag requires the publish flag")
 	}
 
+	if flags.ClearBuildCache || len(flags.ClearCacheBuildpacks) > 0 {
+		if flags.ClearCache {
+			return errors.New("clear-cache flag already clears the build cache, it can't be used with the clear-build-cache or clear-cache-buildpack flags")
+		}
+		if flags.CacheImage != "" || flags.Cache.Build.Format == cache.CacheImage {
+			return errors.New("clear-build-cache and clear-cache-buildpack flags require a volume or bind build cache")
+		}
+	}
+
+	if flags.Output != "" && flags.Publish {
+		return errors.New("output flag cannot be used with the publish flag")
+	}
+
+	if flags.Sparse && strings.HasPrefix(flags.Output, "tar:") {
+		return errors.New("sparse flag cannot be used with a 'tar' output, as the tarball needs every layer")
+	}
+
+	if flags.Daemonless && !flags.Publish {
+		return errors.New("daemonless flag requires the publish flag")
+	}
+
+	if flags.Driver != "" && flags.Driver != client.DriverDocker && flags.Driver != client.DriverKubernetes {
+		return errors.Errorf("invalid driver %s, must be %s or %s", style.Symbol(flags.Driver), style.Symbol(client.DriverDocker), style.Symbol(client.DriverKubernetes))
+	}
+
+	if flags.Driver == client.DriverKubernetes && !flags.Publish {
+		return errors.New("kubernetes driver requires the publish flag")
+	}
+
+	if flags.Driver == client.DriverKubernetes && flags.Daemonless {
+		return errors.New("kubernetes driver cannot be used with the daemonless flag")
+	}
+
+	if (flags.KubeContext != "" || flags.KubeNamespace != "") && flags.Driver != client.DriverKubernetes {
+		return errors.New("kube-context and kube-namespace flags require the kubernetes driver")
+	}
+
+	if flags.ExportConfig != "" && !isExportConfigFormat(flags.ExportConfig) {
+		return errors.Errorf("invalid export config %s, must be one of %s", style.Symbol(flags.ExportConfig), strings.Join(ciconfig.Formats, ", "))
+	}
+
+	if flags.DetectOnly && (flags.Daemonless || flags.Driver == client.DriverKubernetes) {
+		return errors.New("detect-only flag cannot be used with the daemonless flag or the kubernetes driver, as they run the creator")
+	}
+
+	if flags.DetectOnly && flags.Interactive {
+		return errors.New("detect-only flag cannot be used with the interactive flag")
+	}
+
+	if flags.Format != "" && flags.Format != buildFormatHumanReadable && flags.Format != buildFormatJSON {
+		return errors.Errorf("unknown format %s, must be %s or %s", style.Symbol(flags.Format), buildFormatHumanReadable, buildFormatJSON)
+	}
+
+	if flags.Format == buildFormatJSON && (flags.DetectOnly || flags.Interactive || flags.ExportConfig != "") {
+		return errors.New("json format cannot be used with the detect-only, interactive or export-config flags, as they don't build an image")
+	}
+
+	if flags.Progress != "" && flags.Progress != buildProgressPlain && flags.Progress != buildProgressTTY {
+		return errors.Errorf("unknown progress %s, must be %s or %s", style.Symbol(flags.Progress), buildProgressPlain, buildProgressTTY)
+	}
+
+	if flags.Progress == buildProgressTTY && (flags.Interactive || flags.Format == buildFormatJSON) {
+		return errors.New("tty progress cannot be used with the interactive flag or the json format")
+	}
+
+	if flags.AttachProvenance && !flags.Publish {
+		return errors.New("attach-provenance flag requires the publish flag")
+	}
+
 	if flags.GID < 0 {
 		return errors.New("gid flag must be in the range of 0-2147483647")
 	}
@@ -324,18 +1029,162 @@ func validateBuildFlags(flags *BuildFlags, cfg config.Config, inputImageRef clie
 		return client.NewExperimentError("Interactive mode is currently experimental.")
 	}
 
+	if flags.Daemonless && !cfg.Experimental {
+		return client.NewExperimentError("Daemonless builds are currently experimental.")
+	}
+
+	if flags.Driver == client.DriverKubernetes && !cfg.Experimental {
+		return client.NewExperimentError("The kubernetes driver is currently experimental.")
+	}
+
 	if inputImageRef.Layout() && !cfg.Experimental {
 		return client.NewExperimentError("Exporting to OCI layout is currently experimental.")
 	}
 
+	if flags.LifecycleLogLevel != "" && !isLifecycleLogLevel(flags.LifecycleLogLevel) {
+		return errors.Errorf("invalid lifecycle log level %s, must be one of %s", style.Symbol(flags.LifecycleLogLevel), strings.Join(lifecycleLogLevels, ", "))
+	}
+
+	if flags.CPUs < 0 {
+		return errors.New("cpus flag must not be negative")
+	}
+
+	if flags.PidsLimit < 0 {
+		return errors.New("pids-limit flag must not be negative")
+	}
+
+	for _, securityOpt := range flags.SecurityOpt {
+		if securityOpt == "" || strings.HasPrefix(securityOpt, "=") || strings.HasPrefix(securityOpt, ":") {
+			return errors.Errorf("invalid security option %s", style.Symbol(securityOpt))
+		}
+	}
+
+	for _, host := range flags.AddHosts {
+		if err := validateExtraHost(host); err != nil {
+			return err
+		}
+	}
+
+	if flags.PushRetries < 0 {
+		return errors.New("push-retries flag must not be negative")
+	}
+
+	if flags.PushRetries > 0 && !flags.Publish {
+		return errors.New("push-retries flag requires the publish flag")
+	}
+
+	for _, envVar := range flags.LifecycleEnv {
+		if strings.SplitN(envVar, "=", 2)[0] == "" {
+			return errors.Errorf("invalid lifecycle env %s, must be of the form 'VAR=VALUE' or 'VAR'", style.Symbol(envVar))
+		}
+	}
+
 	return nil
 }
 
+// defaultSSHAgent is the only SSH agent of the ssh flag, optionally followed by the socket of the agent
+const defaultSSHAgent = "default"
+
+// windowsSSHAgentPipe is the named pipe of the SSH agent of OpenSSH for Windows
+const windowsSSHAgentPipe = `\\.\pipe\openssh-ssh-agent`
+
+// sshAgentSocket is the socket of the SSH agent to forward for the ssh flag, empty when it isn't set
+func sshAgentSocket(ssh string) (string, error) {
+	if ssh == "" {
+		return "", nil
+	}
+	id, socket, _ := strings.Cut(ssh, "=")
+	if id != defaultSSHAgent {
+		return "", errors.Errorf("invalid ssh %s, must be of the form %s or %s", style.Symbol(ssh), style.Symbol(defaultSSHAgent), style.Symbol(defaultSSHAgent+"=<socket>"))
+	}
+	if socket == "" {
+		socket = os.Getenv("SSH_AUTH_SOCK")
+	}
+	if socket == "" && runtime.GOOS == "windows" {
+		socket = windowsSSHAgentPipe
+	}
+	if socket == "" {
+		return "", errors.New("SSH_AUTH_SOCK isn't set, start an SSH agent or give its socket with --ssh default=<socket>")
+	}
+	return socket, nil
+}
+
+// hostGateway is the IP of an extra host that the daemon replaces with the IP of the docker host
+const hostGateway = "host-gateway"
+
+// validateExtraHost returns an error unless host is of the form HOST:IP, where IP may be host-gateway
+func validateExtraHost(host string) error {
+	name, ip, ok := strings.Cut(host, ":")
+	if !ok || name == "" || (ip != hostGateway && net.ParseIP(ip) == nil) {
+		return errors.Errorf("invalid host %s, must be of the form 'HOST:IP', where IP may be %s", style.Symbol(host), style.Symbol(hostGateway))
+	}
+	return nil
+}
+
+// buildOutput is where --output writes the application image, the directory of an OCI layout or a tarball of it
+type buildOutput struct {
+	dir     string
+	archive string
+}
+
+// parseBuildOutput parses the value of --output, e.g. 'oci:./out' or 'tar:app.tar'
+func parseBuildOutput(value string) (buildOutput, error) {
+	if value == "" {
+		return buildOutput{}, nil
+	}
+	kind, path, _ := strings.Cut(value, ":")
+	switch {
+	case path == "":
+	case kind == "oci":
+		return buildOutput{dir: path}, nil
+	case kind == "tar":
+		return buildOutput{archive: path}, nil
+	}
+	return buildOutput{}, errors.Errorf("invalid output %s, must be 'oci:<dir>' or 'tar:<file>'", style.Symbol(value))
+}
+
+// parseUsernsRemap parses the value of --userns-remap, returning nil when it is to be detected from the daemon
+func parseUsernsRemap(value string) (*bool, error) {
+	if value == "" || value == "auto" {
+		return nil, nil
+	}
+	remap, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, errors.Errorf("invalid userns-remap %s, must be one of auto, true, false", style.Symbol(value))
+	}
+	return &remap, nil
+}
+
+func isExportConfigFormat(format string) bool {
+	for _, f := range ciconfig.Formats {
+		if format == f {
+			return true
+		}
+	}
+	return false
+}
+
+func isLifecycleLogLevel(level string) bool {
+	for _, l := range lifecycleLogLevels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
 func parseEnv(envFiles []string, envVars []string) (map[string]string, error) {
 	env := map[string]string{}
 
+	// env files may reference the variables of the files before them, which they override
+	lookup := func(name string) (string, bool) {
+		if v, ok := env[name]; ok {
+			return v, true
+		}
+		return os.LookupEnv(name)
+	}
 	for _, envFile := range envFiles {
-		envFileVars, err := parseEnvFile(envFile)
+		envFileVars, err := parseEnvFile(envFile, lookup)
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to parse env file '%s'", envFile)
 		}
@@ -350,20 +1199,41 @@ func parseEnv(envFiles []string, envVars []string) (map[string]string, error) {
 	return env, nil
 }
 
-func parseEnvFile(filename string) (map[string]string, error) {
-	out := make(map[string]string)
-	f, err := os.ReadFile(filepath.Clean(filename))
-	if err != nil {
-		return nil, errors.Wrapf(err, "open %s", filename)
+// descriptorLabels returns the labels of the project information and metadata of the descriptor, overridden by the
+// labels it declares. As labels can't be set on images exported to OCI layout, only the declared ones are returned
+// for them, so that the build fails as usual.
+func descriptorLabels(descriptor projectTypes.Descriptor, inputImageName client.InputImageReference) map[string]string {
+	if inputImageName.Layout() {
+		return descriptor.Build.Labels
 	}
-	for _, line := range strings.Split(string(f), "\n") {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
+	labels := project.Labels(descriptor)
+	if labels == nil {
+		return descriptor.Build.Labels
+	}
+	for k, v := range descriptor.Build.Labels {
+		labels[k] = v
+	}
+	return labels
+}
+
+// parseKeyValues merges the 'KEY=VALUE' values of a flag over the ones of the project descriptor, returning nil when
+// there are none
+func parseKeyValues(kind string, descriptorValues map[string]string, values []string) (map[string]string, error) {
+	if len(descriptorValues) == 0 && len(values) == 0 {
+		return nil, nil
+	}
+	result := map[string]string{}
+	for k, v := range descriptorValues {
+		result[k] = v
+	}
+	for _, item := range values {
+		k, v, found := strings.Cut(item, "=")
+		if !found || k == "" {
+			return nil, errors.Errorf("invalid %s %s, must be of the form 'KEY=VALUE'", kind, style.Symbol(item))
 		}
-		out = addEnvVar(out, line)
+		result[k] = v
 	}
-	return out, nil
+	return result, nil
 }
 
 func addEnvVar(env map[string]string, item string) map[string]string {
@@ -0,0 +1,164 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+var (
+	envFileKeyMatcher = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+	envFileRefMatcher = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*`)
+)
+
+// parseEnvFile parses an env file following the conventions of .env files:
+//   - blank lines and lines starting with '#' are ignored, as are comments after unquoted values
+//   - variables may be prefixed with 'export'
+//   - 'VAR' alone takes the value of VAR from lookup
+//   - values in single quotes are literal, and may span several lines
+//   - values in double quotes may span several lines, and may contain the escapes \n, \t, \r, \", \\ and \$
+//   - '$VAR', '${VAR}' and '${VAR:-default}' in unquoted and double quoted values are expanded to the variables
+//     defined earlier in the file, or else to the ones from lookup
+func parseEnvFile(filename string, lookup func(string) (string, bool)) (map[string]string, error) {
+	contents, err := os.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return nil, errors.Wrapf(err, "open %s", filename)
+	}
+
+	out := map[string]string{}
+	expandLookup := func(name string) (string, bool) {
+		if v, ok := out[name]; ok {
+			return v, true
+		}
+		return lookup(name)
+	}
+
+	lines := strings.Split(strings.ReplaceAll(string(contents), "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNumber := i + 1
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if rest := strings.TrimPrefix(line, "export"); rest != line && (rest == "" || rest[0] == ' ' || rest[0] == '\t') {
+			line = strings.TrimSpace(rest)
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !envFileKeyMatcher.MatchString(key) {
+			return nil, errors.Errorf("line %d: invalid variable name %s", lineNumber, style.Symbol(key))
+		}
+		if !found {
+			out[key], _ = lookup(key)
+			continue
+		}
+
+		value = strings.TrimSpace(value)
+		switch {
+		case strings.HasPrefix(value, "'") || strings.HasPrefix(value, `"`):
+			quote := value[0]
+			quoted := value[1:]
+			end := closingQuote(quoted, quote)
+			for end < 0 && i+1 < len(lines) {
+				// the value continues on the next line
+				i++
+				quoted += "\n" + lines[i]
+				end = closingQuote(quoted, quote)
+			}
+			if end < 0 {
+				return nil, errors.Errorf("line %d: missing closing quote of the value of %s", lineNumber, style.Symbol(key))
+			}
+			if rest := strings.TrimSpace(quoted[end+1:]); rest != "" && !strings.HasPrefix(rest, "#") {
+				return nil, errors.Errorf("line %d: unexpected %s after the quoted value of %s", lineNumber, style.Symbol(rest), style.Symbol(key))
+			}
+
+			quoted = quoted[:end]
+			if quote == '\'' {
+				out[key] = quoted
+				continue
+			}
+			if out[key], err = expandEnvValue(quoted, true, expandLookup); err != nil {
+				return nil, errors.Wrapf(err, "line %d", lineNumber)
+			}
+		default:
+			// comments must be preceded by whitespace, so that values such as URLs with fragments are kept whole
+			for j := 1; j < len(value); j++ {
+				if value[j] == '#' && (value[j-1] == ' ' || value[j-1] == '\t') {
+					value = strings.TrimSpace(value[:j])
+					break
+				}
+			}
+			if out[key], err = expandEnvValue(value, false, expandLookup); err != nil {
+				return nil, errors.Wrapf(err, "line %d", lineNumber)
+			}
+		}
+	}
+	return out, nil
+}
+
+// closingQuote returns the index of the quote closing s, skipping the ones escaped in double quoted values
+func closingQuote(s string, quote byte) int {
+	for i := 0; i < len(s); i++ {
+		switch {
+		case quote == '"' && s[i] == '\\':
+			i++
+		case s[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// expandEnvValue expands the variables referenced in value, unescaping it when it was double quoted
+func expandEnvValue(value string, unescape bool, lookup func(string) (string, bool)) (string, error) {
+	var out strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case unescape && c == '\\' && i+1 < len(value):
+			i++
+			switch value[i] {
+			case 'n':
+				out.WriteByte('\n')
+			case 't':
+				out.WriteByte('\t')
+			case 'r':
+				out.WriteByte('\r')
+			case '"', '\\', '$':
+				out.WriteByte(value[i])
+			default:
+				out.WriteByte('\\')
+				out.WriteByte(value[i])
+			}
+		case c == '$' && strings.HasPrefix(value[i+1:], "{"):
+			end := strings.IndexByte(value[i:], '}')
+			if end < 0 {
+				return "", errors.Errorf("missing closing brace of %s", style.Symbol(value[i:]))
+			}
+			name, fallback, hasFallback := strings.Cut(value[i+2:i+end], ":-")
+			if name == "" || envFileRefMatcher.FindString(name) != name {
+				return "", errors.Errorf("invalid variable reference %s", style.Symbol(value[i:i+end+1]))
+			}
+			v, _ := lookup(name)
+			if v == "" && hasFallback {
+				v = fallback
+			}
+			out.WriteString(v)
+			i += end
+		case c == '$' && envFileRefMatcher.MatchString(value[i+1:]):
+			name := envFileRefMatcher.FindString(value[i+1:])
+			v, _ := lookup(name)
+			out.WriteString(v)
+			i += len(name)
+		default:
+			out.WriteByte(c)
+		}
+	}
+	return out.String(), nil
+}
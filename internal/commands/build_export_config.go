@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"path/filepath"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/ciconfig"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+// exportBuildConfig writes the build as the pipeline of a CI system rather than building it. The env and buildpacks
+// of the project descriptor aren't exported, as pack reads the descriptor of the repository in CI as well.
+func exportBuildConfig(logger logging.Logger, flags BuildFlags, imageName, builder string, env map[string]string, trustBuilder bool) error {
+	appPath, err := filepath.Abs(flags.AppPath)
+	if err != nil {
+		return err
+	}
+	if resolved, err := filepath.EvalSymlinks(appPath); err == nil {
+		appPath = resolved
+	}
+
+	b := ciconfig.Build{
+		Image:        imageName,
+		Builder:      builder,
+		RunImage:     flags.RunImage,
+		Buildpacks:   flags.Buildpacks,
+		Env:          env,
+		TrustBuilder: trustBuilder,
+		AppPath:      ".",
+	}
+	if root, gitURL, ok := gitRepository(appPath); ok {
+		if rel, err := filepath.Rel(root, appPath); err == nil {
+			b.AppPath = filepath.ToSlash(rel)
+		}
+		b.GitURL = gitURL
+	} else {
+		logger.Debug("App isn't in a git repository, the path of the app in the pipeline is the root of its repository")
+	}
+
+	if err := ciconfig.Write(logger.Writer(), flags.ExportConfig, b); err != nil {
+		return errors.Wrap(err, "exporting build")
+	}
+	return nil
+}
+
+// gitRepository is the root of the git repository of path and the URL of its origin remote, if any
+func gitRepository(path string) (root, url string, ok bool) {
+	repo, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return "", "", false
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return "", "", false
+	}
+
+	if remote, err := repo.Remote(git.DefaultRemoteName); err == nil && len(remote.Config().URLs) > 0 {
+		url = remote.Config().URLs[0]
+	}
+	return worktree.Filesystem.Root(), url, true
+}
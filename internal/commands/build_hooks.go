@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/logging"
+	projectTypes "github.com/buildpacks/pack/pkg/project/types"
+)
+
+// Environment variables set for the hooks of the project descriptor
+const (
+	hookEnvImage       = "PACK_IMAGE"
+	hookEnvAppPath     = "PACK_APP_PATH"
+	hookEnvReport      = "PACK_BUILD_REPORT"
+	hookEnvImageDigest = "PACK_IMAGE_DIGEST"
+)
+
+// runHooks runs the hooks of a stage one after the other from dir, stopping at the first one that fails. The output
+// of the hooks is logged, and env is added to the environment of pack.
+func runHooks(ctx context.Context, logger logging.Logger, stage string, hooks []projectTypes.Hook, dir string, env map[string]string) error {
+	for _, hook := range hooks {
+		logger.Infof("Running %s hook %s", stage, style.Symbol(hook.Command))
+
+		cmd := hookCommand(ctx, hook.Command)
+		cmd.Dir = dir
+		cmd.Stdout = logging.GetWriterForLevel(logger, logging.InfoLevel)
+		cmd.Stderr = logging.GetWriterForLevel(logger, logging.ErrorLevel)
+		cmd.Env = os.Environ()
+		for key, value := range env {
+			cmd.Env = append(cmd.Env, key+"="+value)
+		}
+
+		if err := cmd.Run(); err != nil {
+			return errors.Wrapf(err, "%s hook %s failed", stage, style.Symbol(hook.Command))
+		}
+	}
+	return nil
+}
+
+func hookCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
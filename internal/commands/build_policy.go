@@ -0,0 +1,29 @@
+package commands
+
+import (
+	"github.com/buildpacks/pack/internal/policy"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+// policyCheck returns the check of the plan of builds against the policy file at policyPath, or nil when no policy is
+// set. The policy is read before the build starts, so that an invalid policy fails fast.
+func policyCheck(policyPath string, logger logging.Logger) (func(client.BuildPlan) error, error) {
+	if policyPath == "" {
+		return nil, nil
+	}
+
+	buildPolicy, err := policy.Read(policyPath)
+	if err != nil {
+		return nil, err
+	}
+	return func(plan client.BuildPlan) error {
+		return policy.Check(buildPolicy, policy.Plan{
+			Builder:    plan.Builder,
+			RunImage:   plan.RunImage,
+			Image:      plan.Image,
+			Publish:    plan.Publish,
+			Buildpacks: plan.Buildpacks,
+		}, logger)
+	}, nil
+}
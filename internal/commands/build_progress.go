@@ -0,0 +1,99 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/rivo/tview"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/term"
+	"github.com/buildpacks/pack/internal/termui"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+const (
+	buildProgressPlain = "plain"
+	buildProgressTTY   = "tty"
+)
+
+// newBuildProgress returns the progress of the build of imageName drawn to the terminal of logger, along with the
+// logger of the build, which feeds it. The progress is nil when the logs can't be redrawn in place, as the output
+// isn't a terminal or the logger is quiet, in which case the build logs as usual.
+func newBuildProgress(logger logging.Logger, imageName string) (*termui.Progress, logging.Logger) {
+	out := logger.Writer()
+	if _, isTerm := term.IsTerminal(out); !isTerm || logging.IsQuiet(logger) {
+		logger.Debug("Showing plain logs, as the output isn't a terminal")
+		return nil, logger
+	}
+	if w, ok := out.(interface{ Writer() io.Writer }); ok {
+		// the progress is drawn with escape codes, which the log writer would strip without colors
+		out = w.Writer()
+	}
+
+	progress := termui.NewProgress(out, imageName)
+	opts := []func(*logging.LogWithWriters){logging.WithParent(logger)}
+	if logger.IsVerbose() {
+		opts = append(opts, logging.WithVerbose())
+	}
+	buildLogger := logging.NewLogWithWriters(progress, logging.GetUncountedWriterForLevel(logger, logging.ErrorLevel), opts...)
+	if fileLogger, ok := logger.(logFileLogger); ok && fileLogger.LogFile() != nil {
+		buildLogger.WantLogFile(fileLogger.LogFile())
+	}
+	return progress, buildLogger
+}
+
+// newMultiBuild returns the dashboard of the builds of images run by one command, shown in place of their logs. It is
+// nil when the output of logger isn't a terminal or logger is quiet, in which case the builds log as usual.
+var newMultiBuild = func(logger logging.Logger, images []string) *termui.MultiBuild {
+	if _, isTerm := term.IsTerminal(logger.Writer()); !isTerm || logging.IsQuiet(logger) {
+		return nil
+	}
+	return termui.NewMultiBuild(tview.NewApplication(), images)
+}
+
+// runMultiBuild shows dashboard while builds run with the context of cmd. When the dashboard is quit before the
+// builds are done, their context is cancelled and the cancelled builds are waited for, so that they are reported.
+func runMultiBuild(cmd *cobra.Command, logger logging.Logger, dashboard *termui.MultiBuild, builds func()) error {
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+	cmd.SetContext(ctx)
+
+	done := make(chan struct{})
+	err := dashboard.Run(func() {
+		defer close(done)
+		builds()
+	})
+	select {
+	case <-done:
+	default:
+		logger.Info("Cancelling the builds left, as the dashboard was quit")
+		cancel()
+		<-done
+	}
+	return errors.Wrap(err, "showing the builds")
+}
+
+// logMultiBuild logs the output of the build of image shown by dashboard, which is gone once closed, with lines
+// prefixed by the image as when the builds aren't shown by a dashboard
+func logMultiBuild(logger logging.Logger, dashboard *termui.MultiBuild, image string) {
+	// the warnings of the build were counted as it logged them
+	out := logging.NewPrefixWriter(logging.GetUncountedWriterForLevel(logger, logging.InfoLevel), image)
+	defer out.Close()
+	for _, line := range dashboard.Logs(image) {
+		fmt.Fprintln(out, line)
+	}
+}
+
+// multiBuildLogger returns the logger of the build of image, which writes to its row of dashboard and counts its
+// warnings towards those of logger
+func multiBuildLogger(logger logging.Logger, dashboard *termui.MultiBuild, image string) logging.Logger {
+	opts := []func(*logging.LogWithWriters){logging.WithParent(logger)}
+	if logger.IsVerbose() {
+		opts = append(opts, logging.WithVerbose())
+	}
+	out := dashboard.Writer(image)
+	return logging.NewLogWithWriters(out, out, opts...)
+}
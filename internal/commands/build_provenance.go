@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/buildpacks/pack"
+	"github.com/buildpacks/pack/internal/provenance"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+// provenanceRequested returns whether the provenance of the build is written or attached to the image
+func provenanceRequested(flags BuildFlags) bool {
+	return flags.Provenance != "" || flags.AttachProvenance
+}
+
+// emitProvenance writes the SLSA provenance of a completed build to the file set with --provenance, and attaches it to
+// the published image with --attach-provenance
+func emitProvenance(ctx context.Context, cmd *cobra.Command, logger logging.Logger, packClient PackClient, flags BuildFlags, summary client.BuildSummary, env map[string]string) error {
+	invocationFlags := map[string]string{}
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		// values of environment variables may be secrets, only their names are recorded
+		if flag.Name == "env" || flag.Name == "lifecycle-env" {
+			return
+		}
+		invocationFlags[flag.Name] = flag.Value.String()
+	})
+
+	statement := provenance.Generate(summary, provenance.Invocation{
+		AppPath:  flags.AppPath,
+		Publish:  flags.Publish,
+		RunImage: flags.RunImage,
+		Flags:    invocationFlags,
+		Env:      env,
+		Version:  pack.Version,
+	})
+
+	if flags.Provenance != "" {
+		contents, err := json.MarshalIndent(statement, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(flags.Provenance, append(contents, '\n'), 0644); err != nil {
+			return errors.Wrapf(err, "writing provenance to %s", style.Symbol(flags.Provenance))
+		}
+		logger.Infof("Provenance written to %s", style.Symbol(flags.Provenance))
+	}
+
+	if flags.AttachProvenance {
+		if summary.Digest == "" {
+			return errors.New("the digest of the image is unknown, provenance cannot be attached")
+		}
+		ref, err := name.ParseReference(summary.Image, name.WeakValidation)
+		if err != nil {
+			return err
+		}
+		envelope, err := provenance.Envelope(statement)
+		if err != nil {
+			return err
+		}
+
+		imageWithDigest := ref.Context().Digest(summary.Digest).Name()
+		digest, err := packClient.AttachAttestation(ctx, imageWithDigest, client.AttachAttestationOptions{
+			Envelope:      envelope,
+			PredicateType: provenance.PredicateType,
+		})
+		if err != nil {
+			return errors.Wrap(err, "attaching provenance")
+		}
+		logger.Infof("Provenance attached to %s as %s", style.Symbol(imageWithDigest), style.Symbol(digest))
+	}
+	return nil
+}
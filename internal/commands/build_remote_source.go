@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/gitsource"
+	"github.com/buildpacks/pack/internal/sourcearchive"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+// gitSourcesDir is the directory of the pack home holding the workspaces of the git repositories built with --git-cache
+const gitSourcesDir = "git-sources"
+
+// fetchRemoteSource fetches the source of the app when flags.AppPath is the URL of an archive or of a git repository,
+// pointing flags.AppPath to it and marking the source as remote. Archives are extracted in a temporary directory, removed by the returned func, and so
+// are git repositories unless flags.GitCache is set, in which case they're fetched in a workspace of the pack home
+// kept across builds.
+func fetchRemoteSource(ctx context.Context, logger logging.Logger, flags *BuildFlags) (func(), error) {
+	if source, ok := sourcearchive.Parse(flags.AppPath); ok {
+		if flags.GitCache {
+			return nil, errors.New("git-cache flag requires the path to be the URL of a git repository")
+		}
+		source.Checksum = flags.SourceChecksum
+		return fetchSourceArchive(ctx, logger, flags, source)
+	}
+	if flags.SourceChecksum != "" {
+		return nil, errors.New("source-checksum flag requires the path to be the URL of an archive")
+	}
+
+	source, ok := gitsource.Parse(flags.AppPath)
+	if !ok {
+		if flags.GitCache {
+			return nil, errors.New("git-cache flag requires the path to be the URL of a git repository")
+		}
+		return func() {}, nil
+	}
+
+	dir, cleanup, err := gitSourceDir(source, flags.GitCache)
+	if err != nil {
+		return nil, err
+	}
+	if err := gitsource.Fetch(ctx, logger, gitsource.Options{}, source, dir); err != nil {
+		cleanup()
+		return nil, err
+	}
+	flags.AppPath = dir
+	flags.remoteSource = true
+	return cleanup, nil
+}
+
+func fetchSourceArchive(ctx context.Context, logger logging.Logger, flags *BuildFlags, source sourcearchive.Source) (func(), error) {
+	if source.Checksum != "" {
+		if err := sourcearchive.ValidateChecksum(source.Checksum); err != nil {
+			return nil, err
+		}
+	}
+
+	dir, err := os.MkdirTemp("", "pack-source-archive-")
+	if err != nil {
+		return nil, errors.Wrap(err, "creating directory for source archive")
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+
+	appDir, err := sourcearchive.Fetch(ctx, logger, sourcearchive.Options{}, source, dir)
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+	flags.AppPath = appDir
+	flags.remoteSource = true
+	return cleanup, nil
+}
+
+// trustsDescriptor is whether the hooks of the project descriptor may run and its variables may read the environment,
+// which the descriptor of a remote source would otherwise be able to run commands on the host and copy its secrets into
+// the build with
+func trustsDescriptor(flags BuildFlags) bool {
+	return !flags.remoteSource || flags.AllowHooks
+}
+
+// gitSourceDir is the directory to fetch source in, a workspace of the pack home named after the URL of the
+// repository when cached, so that its objects are reused by the next builds, and a temporary directory otherwise
+func gitSourceDir(source gitsource.Source, cached bool) (string, func(), error) {
+	if !cached {
+		dir, err := os.MkdirTemp("", "pack-git-source-")
+		if err != nil {
+			return "", nil, errors.Wrap(err, "creating directory for git source")
+		}
+		return dir, func() { os.RemoveAll(dir) }, nil
+	}
+
+	home, err := config.PackHome()
+	if err != nil {
+		return "", nil, err
+	}
+	sum := sha256.Sum256([]byte(source.URL))
+	return filepath.Join(home, gitSourcesDir, hex.EncodeToString(sum[:])[:16]), func() {}, nil
+}
@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/scan"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	projectTypes "github.com/buildpacks/pack/pkg/project/types"
+)
+
+// resolveScanConfig returns the scan configuration of pack, overridden by the values set in the project descriptor
+func resolveScanConfig(cfg config.Scan, descriptor projectTypes.Scan) (config.Scan, error) {
+	if descriptor.Scanner != "" {
+		cfg.Scanner = descriptor.Scanner
+	}
+	if descriptor.Command != "" {
+		cfg.Command = descriptor.Command
+	}
+	if descriptor.FailOn != "" {
+		cfg.FailOn = descriptor.FailOn
+	}
+
+	if cfg.Scanner == "" {
+		return cfg, nil
+	}
+	if _, ok := scan.Scanners[cfg.Scanner]; !ok {
+		return cfg, errors.Errorf("unknown scanner %s, must be one of %s", style.Symbol(cfg.Scanner), strings.Join(scan.ScannerNames(), ", "))
+	}
+	if cfg.FailOn != "" {
+		if _, err := scan.ParseSeverity(cfg.FailOn); err != nil {
+			return cfg, err
+		}
+	}
+	return cfg, nil
+}
+
+// scanImage scans the image that was built for vulnerabilities when a scanner is configured, and fails when some are
+// at least as severe as the configured threshold
+func scanImage(ctx context.Context, logger logging.Logger, cfg config.Scan, image client.InputImageReference, publish bool) error {
+	if cfg.Scanner == "" {
+		return nil
+	}
+
+	source := scan.Source{Image: image.Name(), Daemon: !publish}
+	if image.Layout() {
+		path, err := image.FullName()
+		if err != nil {
+			return err
+		}
+		source = scan.Source{Image: path, Layout: true}
+	}
+
+	logger.Infof("Scanning %s for vulnerabilities with %s", style.Symbol(image.Name()), style.Symbol(cfg.Scanner))
+	vulnerabilities, err := scan.Run(ctx, logger, scan.Options{Scanner: cfg.Scanner, Command: cfg.Command}, source)
+	if err != nil {
+		return err
+	}
+
+	if len(vulnerabilities) == 0 {
+		logger.Info("No vulnerabilities found")
+		return nil
+	}
+	logger.Infof("Found %d vulnerabilities: %s", len(vulnerabilities), scan.Summary(vulnerabilities))
+	for _, vulnerability := range vulnerabilities {
+		logger.Debugf("  %s (%s) in %s %s", vulnerability.ID, vulnerability.Severity, vulnerability.Package, vulnerability.Version)
+	}
+
+	if cfg.FailOn == "" {
+		return nil
+	}
+	failOn, err := scan.ParseSeverity(cfg.FailOn)
+	if err != nil {
+		return err
+	}
+	return errors.Wrapf(scan.Check(vulnerabilities, failOn), "scanning %s", style.Symbol(image.Name()))
+}
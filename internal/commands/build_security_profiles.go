@@ -0,0 +1,80 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+const unconfinedProfile = "unconfined"
+
+// securityOpts returns the security options of the lifecycle containers: those of the seccomp and AppArmor profiles,
+// taken from the flags or else the config, followed by the options given with --security-opt. Like the docker CLI,
+// seccomp profiles are given as paths to their JSON and passed on to the daemon as their contents.
+func securityOpts(logger logging.Logger, profiles config.SecurityProfiles, flags BuildFlags) ([]string, error) {
+	if flags.SeccompProfile != "" {
+		profiles.Seccomp = flags.SeccompProfile
+	}
+	if flags.AppArmorProfile != "" {
+		profiles.AppArmor = flags.AppArmorProfile
+	}
+
+	var opts []string
+	if profiles.Seccomp != "" {
+		opts = append(opts, "seccomp="+profiles.Seccomp)
+	}
+	if profiles.AppArmor != "" {
+		opts = append(opts, "apparmor="+profiles.AppArmor)
+	}
+	opts = append(opts, flags.SecurityOpt...)
+
+	for i, opt := range opts {
+		key, value, _ := strings.Cut(opt, "=")
+		if value == "" {
+			key, value, _ = strings.Cut(opt, ":")
+		}
+		switch key {
+		case "seccomp":
+			if value == unconfinedProfile {
+				logger.Warn("Running the lifecycle containers without a seccomp profile, buildpacks may make any system call")
+				continue
+			}
+			profile, err := readSeccompProfile(value)
+			if err != nil {
+				return nil, err
+			}
+			opts[i] = "seccomp=" + profile
+		case "apparmor":
+			if value == unconfinedProfile {
+				logger.Warn("Running the lifecycle containers without an AppArmor profile, buildpacks aren't confined by it")
+			}
+		}
+	}
+	return opts, nil
+}
+
+// readSeccompProfile returns the compacted JSON of the seccomp profile at path
+func readSeccompProfile(path string) (string, error) {
+	if strings.HasPrefix(strings.TrimSpace(path), "{") {
+		// already the contents of a profile, as the daemon expects
+		return path, nil
+	}
+
+	contents, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return "", errors.Wrapf(err, "reading seccomp profile %s", style.Symbol(path))
+	}
+	var profile bytes.Buffer
+	if err := json.Compact(&profile, contents); err != nil {
+		return "", errors.Wrapf(err, "invalid seccomp profile %s", style.Symbol(path))
+	}
+	return profile.String(), nil
+}
@@ -0,0 +1,200 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/buildpacks/pack/internal/i18n"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+// Formats of the summary of a build
+const (
+	buildFormatHumanReadable = "human-readable"
+	buildFormatJSON          = "json"
+)
+
+// buildReport is the summary of a build printed with the json format
+type buildReport struct {
+	Image            string            `json:"image"`
+	Digest           string            `json:"digest,omitempty"`
+	Size             int64             `json:"size,omitempty"`
+	Skipped          bool              `json:"skipped,omitempty"`
+	SourceDigest     string            `json:"sourceDigest,omitempty"`
+	Builder          baseImageReport   `json:"builder"`
+	RunImage         baseImageReport   `json:"runImage"`
+	LifecycleVersion string            `json:"lifecycleVersion,omitempty"`
+	Buildpacks       []buildpackReport `json:"buildpacks"`
+	Processes        []processReport   `json:"processes"`
+	Phases           []phaseReport     `json:"phases"`
+	Started          time.Time         `json:"started"`
+	DurationSeconds  float64           `json:"durationSeconds"`
+}
+
+type baseImageReport struct {
+	Image  string `json:"image,omitempty"`
+	Digest string `json:"digest,omitempty"`
+}
+
+type buildpackReport struct {
+	ID              string  `json:"id"`
+	Version         string  `json:"version,omitempty"`
+	Homepage        string  `json:"homepage,omitempty"`
+	Cache           string  `json:"cache,omitempty"`
+	DurationSeconds float64 `json:"durationSeconds,omitempty"`
+}
+
+type processReport struct {
+	Type    string `json:"type"`
+	Default bool   `json:"default,omitempty"`
+}
+
+type phaseReport struct {
+	Name            string  `json:"name"`
+	DurationSeconds float64 `json:"durationSeconds"`
+}
+
+// writeBuildReport writes the summary of a build as JSON
+func writeBuildReport(w io.Writer, summary client.BuildSummary) error {
+	report := buildReport{
+		Image:            summary.Image,
+		Digest:           summary.Digest,
+		Size:             summary.Size,
+		Skipped:          summary.Skipped,
+		SourceDigest:     summary.SourceDigest,
+		Builder:          baseImageReport{Image: summary.Builder, Digest: summary.BuilderDigest},
+		RunImage:         baseImageReport{Image: summary.RunImage, Digest: summary.RunImageDigest},
+		LifecycleVersion: summary.LifecycleVersion,
+		Buildpacks:       []buildpackReport{},
+		Processes:        []processReport{},
+		Phases:           []phaseReport{},
+		Started:          summary.Started,
+		DurationSeconds:  summary.Duration.Seconds(),
+	}
+	for _, bp := range summary.Buildpacks {
+		report.Buildpacks = append(report.Buildpacks, buildpackReport{ID: bp.ID, Version: bp.Version, Homepage: bp.Homepage, Cache: bp.Cache, DurationSeconds: bp.Duration.Seconds()})
+	}
+	for _, process := range summary.Processes {
+		report.Processes = append(report.Processes, processReport{Type: process, Default: process == summary.DefaultProcess})
+	}
+	for _, phase := range summary.Phases {
+		report.Phases = append(report.Phases, phaseReport{Name: phase.Name, DurationSeconds: phase.Duration.Seconds()})
+	}
+
+	contents, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(contents))
+	return err
+}
+
+// stderrLogger is a logger like logger, writing all its output to the error writer of logger
+func stderrLogger(logger logging.Logger) logging.Logger {
+	errOut := logging.GetUncountedWriterForLevel(logger, logging.ErrorLevel)
+
+	opts := []func(*logging.LogWithWriters){logging.WithParent(logger)}
+	if logger.IsVerbose() {
+		opts = append(opts, logging.WithVerbose())
+	}
+	stderr := logging.NewLogWithWriters(errOut, errOut, opts...)
+	stderr.WantQuiet(logging.IsQuiet(logger))
+	return stderr
+}
+
+// printBuildSummary writes a table with the key facts about a completed build
+func printBuildSummary(logger logging.Logger, summary client.BuildSummary) {
+	logger.Info("")
+	logger.Info(i18n.T(i18n.BuildSummary))
+
+	tw := tabwriter.NewWriter(logger.Writer(), 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "  Image:\t%s\n", summary.Image)
+	fmt.Fprintf(tw, "  Digest:\t%s\n", valueOrUnknown(summary.Digest))
+
+	size := "unknown"
+	if summary.Size > 0 {
+		size = humanize.Bytes(uint64(summary.Size))
+	}
+	fmt.Fprintf(tw, "  Size:\t%s\n", size)
+
+	var processes []string
+	for _, process := range summary.Processes {
+		if process == summary.DefaultProcess {
+			process += " (default)"
+		}
+		processes = append(processes, process)
+	}
+	fmt.Fprintf(tw, "  Processes:\t%s\n", valueOrUnknown(strings.Join(processes, ", ")))
+	fmt.Fprintf(tw, "  Total time:\t%s\n", summary.Duration.Round(time.Millisecond))
+	tw.Flush()
+
+	if len(summary.Buildpacks) == 0 {
+		return
+	}
+
+	tw = tabwriter.NewWriter(logger.Writer(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  BUILDPACK\tVERSION\tCACHE")
+	for _, bp := range summary.Buildpacks {
+		fmt.Fprintf(tw, "  %s\t%s\t%s\n", bp.ID, valueOrDash(bp.Version), valueOrDash(bp.Cache))
+	}
+	tw.Flush()
+}
+
+// printBuildTimings writes tables with the time each lifecycle phase took and, when the lifecycle logged them, the
+// time each buildpack took to build, slowest first
+func printBuildTimings(logger logging.Logger, summary client.BuildSummary) {
+	logger.Info("")
+	logger.Info(i18n.T(i18n.BuildTimings))
+
+	tw := tabwriter.NewWriter(logger.Writer(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  PHASE\tTIME")
+	for _, phase := range summary.Phases {
+		fmt.Fprintf(tw, "  %s\t%s\n", phase.Name, phase.Duration.Round(time.Millisecond))
+	}
+	fmt.Fprintf(tw, "  total\t%s\n", summary.Duration.Round(time.Millisecond))
+	tw.Flush()
+
+	var buildpacks []client.BuildpackSummary
+	for _, bp := range summary.Buildpacks {
+		if bp.Duration > 0 {
+			buildpacks = append(buildpacks, bp)
+		}
+	}
+	if len(buildpacks) == 0 {
+		logger.Info("  Buildpacks are only timed when the lifecycle logs at debug level, e.g. with --verbose or --lifecycle-log-level debug")
+		return
+	}
+	sort.SliceStable(buildpacks, func(i, j int) bool {
+		return buildpacks[i].Duration > buildpacks[j].Duration
+	})
+
+	logger.Info("")
+	tw = tabwriter.NewWriter(logger.Writer(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  BUILDPACK\tVERSION\tTIME")
+	for _, bp := range buildpacks {
+		fmt.Fprintf(tw, "  %s\t%s\t%s\n", bp.ID, valueOrDash(bp.Version), bp.Duration.Round(time.Millisecond))
+	}
+	tw.Flush()
+}
+
+func valueOrUnknown(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
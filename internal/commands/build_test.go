@@ -1,11 +1,22 @@
 package commands_test
 
 import (
+	"archive/zip"
 	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"reflect"
+	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -22,7 +33,10 @@ import (
 	"github.com/buildpacks/pack/internal/commands"
 	"github.com/buildpacks/pack/internal/commands/testmocks"
 	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/registry"
+	"github.com/buildpacks/pack/pkg/buildpack"
 	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/errcode"
 	"github.com/buildpacks/pack/pkg/image"
 	"github.com/buildpacks/pack/pkg/logging"
 	projectTypes "github.com/buildpacks/pack/pkg/project/types"
@@ -181,6 +195,32 @@ func testBuildCommand(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("--error-format json", func() {
+			it("writes the failure as a json block with its error code", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), gomock.Any()).
+					Return(errcode.Wrap(errcode.BuilderIncompatible, errors.New("builder is incompatible")))
+
+				commands.AddErrorFormatFlag(command)
+				command.SetArgs([]string{"--builder", "my-builder", "image", "--error-format", "json"})
+				h.AssertError(t, command.Execute(), "builder is incompatible")
+
+				var out struct {
+					Error struct {
+						Code     string `json:"code"`
+						Name     string `json:"name"`
+						Message  string `json:"message"`
+						ExitCode int    `json:"exitCode"`
+					} `json:"error"`
+				}
+				h.AssertNil(t, json.Unmarshal(outBuf.Bytes(), &out))
+				h.AssertEq(t, out.Error.Code, "PACK1002")
+				h.AssertEq(t, out.Error.Name, "builder-incompatible")
+				h.AssertEq(t, out.Error.Message, "failed to build: builder is incompatible")
+				h.AssertEq(t, out.Error.ExitCode, errcode.BuilderIncompatible.ExitCode())
+			})
+		})
+
 		when("--buildpack-registry flag is specified but experimental isn't set in the config", func() {
 			it("errors with a descriptive message", func() {
 				command.SetArgs([]string{"image", "--builder", "my-builder", "--buildpack-registry", "some-registry"})
@@ -201,6 +241,169 @@ func testBuildCommand(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("--warnings-as-errors", func() {
+			it.Before(func() {
+				commands.AddWarningsAsErrorsFlag(command, logger)
+			})
+
+			it("fails the build when the lifecycle reported warnings", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithImage("my-builder", "image")).
+					DoAndReturn(func(_ context.Context, _ client.BuildOptions) error {
+						_, err := fmt.Fprintln(logging.GetWriterForLevel(logger, logging.InfoLevel), "[detector] Warning: platform API 0.9 is deprecated")
+						return err
+					})
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--warnings-as-errors"})
+				err := command.Execute()
+				h.AssertError(t, err, "1 warning(s) were reported")
+				h.AssertEq(t, errcode.Of(err), errcode.WarningsAsErrors)
+			})
+
+			it("succeeds without warnings", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithImage("my-builder", "image")).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--warnings-as-errors"})
+				h.AssertNil(t, command.Execute())
+			})
+		})
+
+		when("the registry lookup flags are given", func() {
+			it("forwards how registry buildpacks are located onto the client", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithRegistryLookup(buildpack.RegistryLookup{Offline: true, ForceRefresh: true})).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--offline", "--force-refresh"})
+				h.AssertNil(t, command.Execute())
+			})
+		})
+
+		when("resource limits are given", func() {
+			it("forwards the limits onto the client", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithResources(2*1024*1024*1024, 1.5, 512)).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--memory", "2g", "--cpus", "1.5", "--pids-limit", "512"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			when("the memory limit is invalid", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--memory", "lots"})
+					err := command.Execute()
+					h.AssertError(t, err, "invalid memory limit 'lots', must be a size such as '512m' or '2g'")
+				})
+			})
+
+			when("the cpus are negative", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--cpus", "-1"})
+					err := command.Execute()
+					h.AssertError(t, err, "cpus flag must not be negative")
+				})
+			})
+		})
+
+		when("security profiles are given", func() {
+			var profilePath string
+
+			it.Before(func() {
+				tmpDir := t.TempDir()
+				profilePath = filepath.Join(tmpDir, "seccomp.json")
+				h.AssertNil(t, os.WriteFile(profilePath, []byte("{\n  \"defaultAction\": \"SCMP_ACT_ALLOW\"\n}\n"), 0600))
+			})
+
+			it("passes the contents of the seccomp profile and the AppArmor profile onto the client", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithHardening(false, nil, nil, []string{`seccomp={"defaultAction":"SCMP_ACT_ALLOW"}`, "apparmor=some-profile"})).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--seccomp-profile", profilePath, "--apparmor-profile", "some-profile"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("reads the seccomp profiles given as security options", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithHardening(false, nil, nil, []string{`seccomp={"defaultAction":"SCMP_ACT_ALLOW"}`})).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--security-opt", "seccomp=" + profilePath})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("uses the profiles of the config unless overridden", func() {
+				cfg.SecurityProfiles = config.SecurityProfiles{Seccomp: profilePath, AppArmor: "some-profile"}
+				command = commands.Build(logger, cfg, mockClient)
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithHardening(false, nil, nil, []string{"seccomp=unconfined", "apparmor=some-profile"})).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--seccomp-profile", "unconfined"})
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), "Running the lifecycle containers without a seccomp profile")
+			})
+
+			when("the seccomp profile doesn't exist", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--seccomp-profile", "/does/not/exist.json"})
+					err := command.Execute()
+					h.AssertError(t, err, "reading seccomp profile '/does/not/exist.json'")
+				})
+			})
+		})
+
+		when("hardening options are given", func() {
+			it("forwards them onto the client", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithHardening(true, []string{"NET_RAW"}, []string{"SYS_CHROOT"}, []string{"apparmor=some-profile"})).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--read-only", "--cap-add", "NET_RAW", "--cap-drop", "SYS_CHROOT", "--security-opt", "apparmor=some-profile"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			when("a security option has no key", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--security-opt", "=unconfined"})
+					err := command.Execute()
+					h.AssertError(t, err, "invalid security option '=unconfined'")
+				})
+			})
+		})
+
+		when("--userns-remap", func() {
+			it("detects it from the daemon by default", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithUsernsRemap(nil)).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("overrides the detection", func() {
+				remap := true
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithUsernsRemap(&remap)).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--userns-remap", "true"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			when("the value is invalid", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--userns-remap", "maybe"})
+					err := command.Execute()
+					h.AssertError(t, err, "invalid userns-remap 'maybe', must be one of auto, true, false")
+				})
+			})
+		})
+
 		when("--platform", func() {
 			it("sets platform", func() {
 				mockClient.EXPECT().
@@ -266,6 +469,107 @@ func testBuildCommand(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("the pull policies of images are specified", func() {
+			var (
+				never        = image.PullNever
+				ifNotPresent = image.PullIfNotPresent
+			)
+
+			it("passes the pull policies of the flags", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithImagePullPolicies(client.ImagePullPolicies{Builder: &never, Buildpacks: &ifNotPresent})).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--builder-pull-policy", "never", "--buildpack-pull-policy", "if-not-present"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("returns error for unknown policy", func() {
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--run-image-pull-policy", "sometimes"})
+				h.AssertError(t, command.Execute(), "parsing run-image-pull-policy sometimes")
+			})
+
+			when("pull policies are set in config", func() {
+				it.Before(func() {
+					cfg := config.Config{PullPolicies: config.PullPolicies{Builder: "never", RunImage: "always"}}
+					command = commands.Build(logger, cfg, mockClient)
+				})
+
+				it("uses the set policies, overridden by the flags", func() {
+					always := image.PullAlways
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithImagePullPolicies(client.ImagePullPolicies{Builder: &ifNotPresent, RunImage: &always})).
+						Return(nil)
+
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--builder-pull-policy", "if-not-present"})
+					h.AssertNil(t, command.Execute())
+				})
+
+				it("ignores them when --pull-policy is specified", func() {
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithImagePullPolicies(client.ImagePullPolicies{})).
+						Return(nil)
+
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--pull-policy", "if-not-present"})
+					h.AssertNil(t, command.Execute())
+				})
+			})
+		})
+
+		when("--add-host", func() {
+			it("passes the hosts to the builder", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithExtraHosts([]string{"host.docker.internal:host-gateway", "db:10.0.0.5", "cache:::1"})).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--network", "my-project_default",
+					"--add-host", "host.docker.internal:host-gateway", "--add-host", "db:10.0.0.5", "--add-host", "cache:::1"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			for _, host := range []string{"db", ":10.0.0.5", "db:not-an-ip"} {
+				host := host
+				it("errors for the invalid host "+host, func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--add-host", host})
+					h.AssertError(t, command.Execute(), "invalid host '"+host+"', must be of the form 'HOST:IP', where IP may be 'host-gateway'")
+				})
+			}
+		})
+
+		when("--ssh", func() {
+			it("forwards the agent of SSH_AUTH_SOCK", func() {
+				t.Setenv("SSH_AUTH_SOCK", "/tmp/ssh-agent.sock")
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithSSHAgent("/tmp/ssh-agent.sock")).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--ssh", "default"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("forwards the agent of the given socket", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithSSHAgent("/run/user/1000/agent.sock")).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--ssh", "default=/run/user/1000/agent.sock"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("errors when SSH_AUTH_SOCK isn't set", func() {
+				h.SkipIf(t, runtime.GOOS == "windows", "the agent of OpenSSH for Windows is used instead")
+				t.Setenv("SSH_AUTH_SOCK", "")
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--ssh", "default"})
+				h.AssertError(t, command.Execute(), "SSH_AUTH_SOCK isn't set")
+			})
+
+			it("errors for another agent than default", func() {
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--ssh", "github=/tmp/agent.sock"})
+				h.AssertError(t, command.Execute(), "invalid ssh 'github=/tmp/agent.sock', must be of the form 'default' or 'default=<socket>'")
+			})
+		})
+
 		when("volume mounts are specified", func() {
 			it("mounts the volumes", func() {
 				mockClient.EXPECT().
@@ -398,6 +702,86 @@ func testBuildCommand(t *testing.T, when spec.G, it spec.S) {
 					h.AssertNil(t, command.Execute())
 				})
 			})
+
+			when("an env file follows the conventions of .env files", func() {
+				var envPath string
+
+				writeEnvFile := func(contents string) {
+					envPath = filepath.Join(t.TempDir(), ".env")
+					h.AssertNil(t, os.WriteFile(envPath, []byte(contents), 0600))
+				}
+
+				it.Before(func() {
+					h.AssertNil(t, os.Setenv("PACK_TEST_HOST_VAR", "from-host"))
+				})
+
+				it.After(func() {
+					h.AssertNil(t, os.Unsetenv("PACK_TEST_HOST_VAR"))
+				})
+
+				it("parses quotes, comments, multiline values and references to variables", func() {
+					writeEnvFile(`# some comment
+export EXPORTED=value
+UNQUOTED = some value # some comment
+FRAGMENT=https://example.com/#fragment
+SINGLE='${PACK_TEST_HOST_VAR} # not a comment'
+DOUBLE="line 1\nline \"2\" \$HOME"
+MULTILINE="first
+second"
+HOST=${PACK_TEST_HOST_VAR}
+LOCAL=$EXPORTED-${UNQUOTED}
+DEFAULT=${PACK_TEST_UNSET_VAR:-fallback}
+PACK_TEST_HOST_VAR
+`)
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithEnv(map[string]string{
+							"EXPORTED":           "value",
+							"UNQUOTED":           "some value",
+							"FRAGMENT":           "https://example.com/#fragment",
+							"SINGLE":             "${PACK_TEST_HOST_VAR} # not a comment",
+							"DOUBLE":             "line 1\nline \"2\" $HOME",
+							"MULTILINE":          "first\nsecond",
+							"HOST":               "from-host",
+							"LOCAL":              "value-some value",
+							"DEFAULT":            "fallback",
+							"PACK_TEST_HOST_VAR": "from-host",
+						})).
+						Return(nil)
+
+					command.SetArgs([]string{"--builder", "my-builder", "image", "--env-file", envPath})
+					h.AssertNil(t, command.Execute())
+				})
+
+				it("references the variables of earlier env files", func() {
+					writeEnvFile("BASE=base")
+					basePath := envPath
+					writeEnvFile("DERIVED=${BASE}-derived")
+
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithEnv(map[string]string{
+							"BASE":    "base",
+							"DERIVED": "base-derived",
+						})).
+						Return(nil)
+
+					command.SetArgs([]string{"--builder", "my-builder", "image", "--env-file", basePath, "--env-file", envPath})
+					h.AssertNil(t, command.Execute())
+				})
+
+				it("errors for a missing closing quote", func() {
+					writeEnvFile("KEY=value\nOTHER=\"unterminated\n")
+
+					command.SetArgs([]string{"--builder", "my-builder", "image", "--env-file", envPath})
+					h.AssertError(t, command.Execute(), "line 2: missing closing quote of the value of 'OTHER'")
+				})
+
+				it("errors for an invalid variable name", func() {
+					writeEnvFile("SOME KEY=value")
+
+					command.SetArgs([]string{"--builder", "my-builder", "image", "--env-file", envPath})
+					h.AssertError(t, command.Execute(), "line 1: invalid variable name 'SOME KEY'")
+				})
+			})
 		})
 
 		when("a cache-image passed", func() {
@@ -458,41 +842,223 @@ func testBuildCommand(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
-		when("a valid lifecycle-image is provided", func() {
-			when("only the image repo is provided", func() {
-				it("uses the provided lifecycle-image and parses it correctly", func() {
+		when("cache flag is passed for both types of cache", func() {
+			it("configures each cache", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithCacheFlags("type=build;format=image;name=myorg/myimage:cache;type=launch;format=volume;name=my-launch-cache;")).
+					Return(nil)
+
+				command.SetArgs([]string{"--builder", "my-builder", "image", "--publish",
+					"--cache", "type=build;format=image;name=myorg/myimage:cache", "--cache", "type=launch;format=volume;name=my-launch-cache"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("errors for an unknown field", func() {
+				command.SetArgs([]string{"--builder", "my-builder", "image", "--cache", "type=build;format=volume;nmae=my-build-cache"})
+				h.AssertError(t, command.Execute(), "invalid cache field 'nmae'")
+			})
+		})
+
+		when("caches are cleared apart", func() {
+			it("clears the build and launch caches and the cache of buildpacks", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithClearedCaches(true, true, []string{"some/buildpack", "other/buildpack"})).
+					Return(nil)
+
+				command.SetArgs([]string{"--builder", "my-builder", "image", "--clear-build-cache", "--clear-launch-cache",
+					"--clear-cache-buildpack", "some/buildpack", "--clear-cache-buildpack", "other/buildpack"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("errors with --clear-cache", func() {
+				command.SetArgs([]string{"--builder", "my-builder", "image", "--clear-cache", "--clear-cache-buildpack", "some/buildpack"})
+				h.AssertError(t, command.Execute(), "clear-cache flag already clears the build cache")
+			})
+
+			it("errors with a cache image", func() {
+				command.SetArgs([]string{"--builder", "my-builder", "image", "--publish", "--cache-image", "some-cache-image", "--clear-build-cache"})
+				h.AssertError(t, command.Execute(), "clear-build-cache and clear-cache-buildpack flags require a volume or bind build cache")
+			})
+		})
+
+		when("the build cache is limited in size", func() {
+			it("limits the build cache to the size of the flag", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithCacheSizeLimit(2*1000*1000*1000)).
+					Return(nil)
+
+				command.SetArgs([]string{"--builder", "my-builder", "image", "--cache-size-limit", "2GB"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("errors for an invalid size", func() {
+				command.SetArgs([]string{"--builder", "my-builder", "image", "--cache-size-limit", "big"})
+				h.AssertError(t, command.Execute(), "invalid cache size limit 'big'")
+			})
+
+			when("the config limits the build caches", func() {
+				it.Before(func() {
+					cfg.CacheSizeLimit = "5GB"
+					cfg.CacheSizeLimits = map[string]string{"index.docker.io/library/image:latest": "1GB"}
+					command = commands.Build(logger, cfg, mockClient)
+				})
+
+				it("limits the build cache of the app to its own limit", func() {
 					mockClient.EXPECT().
-						Build(gomock.Any(), EqBuildOptionsWithLifecycleImage("index.docker.io/library/some-lifecycle-image:latest")).
+						Build(gomock.Any(), EqBuildOptionsWithCacheSizeLimit(1000*1000*1000)).
 						Return(nil)
 
-					command.SetArgs([]string{"--builder", "my-builder", "image", "--lifecycle-image", "some-lifecycle-image"})
+					command.SetArgs([]string{"--builder", "my-builder", "image"})
 					h.AssertNil(t, command.Execute())
 				})
-			})
-			when("a custom image repo is provided", func() {
-				it("uses the provided lifecycle-image and parses it correctly", func() {
+
+				it("limits the build cache of other apps to the limit of all the caches", func() {
 					mockClient.EXPECT().
-						Build(gomock.Any(), EqBuildOptionsWithLifecycleImage("test.com/some-lifecycle-image:latest")).
+						Build(gomock.Any(), EqBuildOptionsWithCacheSizeLimit(5*1000*1000*1000)).
 						Return(nil)
 
-					command.SetArgs([]string{"--builder", "my-builder", "image", "--lifecycle-image", "test.com/some-lifecycle-image"})
+					command.SetArgs([]string{"--builder", "my-builder", "other-image"})
 					h.AssertNil(t, command.Execute())
 				})
-			})
-			when("a custom image repo is provided with a tag", func() {
-				it("uses the provided lifecycle-image and parses it correctly", func() {
+
+				it("prefers the flag", func() {
 					mockClient.EXPECT().
-						Build(gomock.Any(), EqBuildOptionsWithLifecycleImage("test.com/some-lifecycle-image:v1")).
+						Build(gomock.Any(), EqBuildOptionsWithCacheSizeLimit(0)).
 						Return(nil)
 
-					command.SetArgs([]string{"--builder", "my-builder", "image", "--lifecycle-image", "test.com/some-lifecycle-image:v1"})
+					command.SetArgs([]string{"--builder", "my-builder", "image", "--cache-size-limit", "0"})
 					h.AssertNil(t, command.Execute())
 				})
 			})
-			when("a custom image repo is provided with a digest", func() {
-				it("uses the provided lifecycle-image and parses it correctly", func() {
-					mockClient.EXPECT().
-						Build(gomock.Any(), EqBuildOptionsWithLifecycleImage("test.com/some-lifecycle-image@sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855")).
+		})
+
+		when("--compression", func() {
+			it("compresses the layers of the published image with zstd", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithLayerCompression(client.ZstdCompression)).
+					Return(nil)
+
+				command.SetArgs([]string{"--builder", "my-builder", "image", "--publish", "--compression", "zstd"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("compresses them with gzip by default", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithLayerCompression(client.GzipCompression)).
+					Return(nil)
+
+				command.SetArgs([]string{"--builder", "my-builder", "image"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("errors for zstd without publishing", func() {
+				command.SetArgs([]string{"--builder", "my-builder", "image", "--compression", "zstd"})
+				h.AssertError(t, command.Execute(), "zstd compression requires the publish flag")
+			})
+
+			it("errors for an unknown compression", func() {
+				command.SetArgs([]string{"--builder", "my-builder", "image", "--publish", "--compression", "lz4"})
+				h.AssertError(t, command.Execute(), "invalid layer compression 'lz4'")
+			})
+		})
+
+		when("--use-creator", func() {
+			it("forces the creator for an untrusted builder", func() {
+				useCreator := true
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithUseCreator(&useCreator)).
+					Return(nil)
+
+				command.SetArgs([]string{"--builder", "my-builder", "image", "--use-creator"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("forces the phases in separate containers", func() {
+				useCreator := false
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithUseCreator(&useCreator)).
+					Return(nil)
+
+				command.SetArgs([]string{"--builder", "my-builder", "image", "--use-creator=false"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("depends on whether the builder is trusted by default", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithUseCreator(nil)).
+					Return(nil)
+
+				command.SetArgs([]string{"--builder", "my-builder", "image"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("ignores the lifecycle image when forcing the creator", func() {
+				useCreator := true
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithUseCreator(&useCreator)).
+					Return(nil)
+
+				command.SetArgs([]string{"--builder", "my-builder", "image", "--use-creator", "--lifecycle-image", "some-lifecycle-image"})
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), "Warning: Ignoring the provided lifecycle image as the creator runs in a single container using the provided builder")
+			})
+		})
+
+		when("--serial-export", func() {
+			it("exports the app image and the cache serially", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithSerialExport(true)).
+					Return(nil)
+
+				command.SetArgs([]string{"--builder", "my-builder", "image", "--serial-export"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("exports them in parallel by default", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithSerialExport(false)).
+					Return(nil)
+
+				command.SetArgs([]string{"--builder", "my-builder", "image"})
+				h.AssertNil(t, command.Execute())
+			})
+		})
+
+		when("a valid lifecycle-image is provided", func() {
+			when("only the image repo is provided", func() {
+				it("uses the provided lifecycle-image and parses it correctly", func() {
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithLifecycleImage("index.docker.io/library/some-lifecycle-image:latest")).
+						Return(nil)
+
+					command.SetArgs([]string{"--builder", "my-builder", "image", "--lifecycle-image", "some-lifecycle-image"})
+					h.AssertNil(t, command.Execute())
+				})
+			})
+			when("a custom image repo is provided", func() {
+				it("uses the provided lifecycle-image and parses it correctly", func() {
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithLifecycleImage("test.com/some-lifecycle-image:latest")).
+						Return(nil)
+
+					command.SetArgs([]string{"--builder", "my-builder", "image", "--lifecycle-image", "test.com/some-lifecycle-image"})
+					h.AssertNil(t, command.Execute())
+				})
+			})
+			when("a custom image repo is provided with a tag", func() {
+				it("uses the provided lifecycle-image and parses it correctly", func() {
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithLifecycleImage("test.com/some-lifecycle-image:v1")).
+						Return(nil)
+
+					command.SetArgs([]string{"--builder", "my-builder", "image", "--lifecycle-image", "test.com/some-lifecycle-image:v1"})
+					h.AssertNil(t, command.Execute())
+				})
+			})
+			when("a custom image repo is provided with a digest", func() {
+				it("uses the provided lifecycle-image and parses it correctly", func() {
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithLifecycleImage("test.com/some-lifecycle-image@sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855")).
 						Return(nil)
 
 					command.SetArgs([]string{"--builder", "my-builder", "image", "--lifecycle-image", "test.com/some-lifecycle-image@sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"})
@@ -674,7 +1240,7 @@ builder = "my-builder"
 				})
 			})
 
-			when("file is invalid", func() {
+			when("file has profiles", func() {
 				var projectTomlPath string
 
 				it.Before(func() {
@@ -682,7 +1248,22 @@ builder = "my-builder"
 					h.AssertNil(t, err)
 					defer projectToml.Close()
 
-					projectToml.WriteString("project]")
+					projectToml.WriteString(`
+[_]
+schema-version = "0.2"
+
+[[io.buildpacks.build.env]]
+name = "LOG_LEVEL"
+value = "info"
+
+[[io.buildpacks.build.env]]
+name = "REGION"
+value = "eu"
+
+[[io.buildpacks.profiles.dev.env]]
+name = "LOG_LEVEL"
+value = "debug"
+`)
 					projectTomlPath = projectToml.Name()
 				})
 
@@ -690,244 +1271,1413 @@ builder = "my-builder"
 					h.AssertNil(t, os.RemoveAll(projectTomlPath))
 				})
 
-				it("should fail to build", func() {
-					command.SetArgs([]string{"--builder", "my-builder", "--descriptor", projectTomlPath, "image"})
-					h.AssertNotNil(t, command.Execute())
+				it("should apply the env of the selected profile", func() {
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithProjectDescriptorEnv([]projectTypes.EnvVar{
+							{Name: "REGION", Value: "eu"},
+							{Name: "LOG_LEVEL", Value: "debug"},
+						})).
+						Return(nil)
+
+					command.SetArgs([]string{"--builder", "my-builder", "--descriptor", projectTomlPath, "--profile", "dev", "image"})
+					h.AssertNil(t, command.Execute())
+				})
+
+				it("should fail if the profile is not defined", func() {
+					command.SetArgs([]string{"--builder", "my-builder", "--descriptor", projectTomlPath, "--profile", "prod", "image"})
+					h.AssertError(t, command.Execute(), "profile 'prod' is not defined, available profiles are: dev")
 				})
 			})
 
-			when("descriptor path is NOT specified", func() {
-				when("project.toml exists in source repo", func() {
-					it.Before(func() {
-						h.AssertNil(t, os.Chdir("testdata"))
-					})
+			when("file uses variables", func() {
+				var projectTomlPath string
 
-					it.After(func() {
-						h.AssertNil(t, os.Chdir(".."))
-					})
+				it.Before(func() {
+					projectToml, err := os.CreateTemp("", "project.toml")
+					h.AssertNil(t, err)
+					defer projectToml.Close()
 
-					it("should use project.toml in source repo", func() {
-						mockClient.EXPECT().
-							Build(gomock.Any(), EqBuildOptionsWithProjectDescriptor(projectTypes.Descriptor{
-								Project: projectTypes.Project{
-									Name: "Sample",
-								},
-								Build: projectTypes.Build{
-									Buildpacks: []projectTypes.Buildpack{{
-										ID:      "example/lua",
-										Version: "1.0",
-									}},
-									Env: []projectTypes.EnvVar{{
-										Name:  "KEY1",
-										Value: "VALUE1",
-									}},
-								},
-								SchemaVersion: api.MustParse("0.1"),
-							})).
-							Return(nil)
+					projectToml.WriteString(`
+[_]
+schema-version = "0.2"
 
-						command.SetArgs([]string{"--builder", "my-builder", "image"})
-						h.AssertNil(t, command.Execute())
-					})
+[[io.buildpacks.build.env]]
+name = "LOG_LEVEL"
+value = "${env:PACK_TEST_LOG_LEVEL:-info}"
+`)
+					projectTomlPath = projectToml.Name()
 				})
 
-				when("project.toml does NOT exist in source repo", func() {
-					it("should use empty descriptor", func() {
-						mockClient.EXPECT().
-							Build(gomock.Any(), EqBuildOptionsWithEnv(map[string]string{})).
-							Return(nil)
+				it.After(func() {
+					h.AssertNil(t, os.RemoveAll(projectTomlPath))
+				})
 
-						command.SetArgs([]string{"--builder", "my-builder", "image"})
-						h.AssertNil(t, command.Execute())
-					})
+				it("should resolve them", func() {
+					t.Setenv("PACK_TEST_LOG_LEVEL", "debug")
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithProjectDescriptorEnv([]projectTypes.EnvVar{
+							{Name: "LOG_LEVEL", Value: "debug"},
+						})).
+						Return(nil)
+
+					command.SetArgs([]string{"--builder", "my-builder", "--descriptor", projectTomlPath, "image"})
+					h.AssertNil(t, command.Execute())
 				})
 			})
 
-			when("descriptor path is specified", func() {
-				when("descriptor file exists", func() {
-					var projectTomlPath string
-					it.Before(func() {
-						projectTomlPath = filepath.Join("testdata", "project.toml")
-					})
+			when("file declares hooks", func() {
+				var projectDir string
 
-					it("should use specified descriptor", func() {
-						mockClient.EXPECT().
-							Build(gomock.Any(), EqBuildOptionsWithProjectDescriptor(projectTypes.Descriptor{
-								Project: projectTypes.Project{
-									Name: "Sample",
-								},
-								Build: projectTypes.Build{
-									Buildpacks: []projectTypes.Buildpack{{
-										ID:      "example/lua",
-										Version: "1.0",
-									}},
-									Env: []projectTypes.EnvVar{{
-										Name:  "KEY1",
-										Value: "VALUE1",
-									}},
-								},
-								SchemaVersion: api.MustParse("0.1"),
-							})).
-							Return(nil)
+				it.Before(func() {
+					if runtime.GOOS == "windows" {
+						t.Skip("hooks are written for sh")
+					}
 
-						command.SetArgs([]string{"--builder", "my-builder", "--descriptor", projectTomlPath, "image"})
-						h.AssertNil(t, command.Execute())
-					})
-				})
+					var err error
+					projectDir, err = os.MkdirTemp("", "hooks")
+					h.AssertNil(t, err)
 
-				when("descriptor file does NOT exist in source repo", func() {
-					it("should fail with an error message", func() {
-						command.SetArgs([]string{"--builder", "my-builder", "--descriptor", "non-existent-path", "image"})
-						h.AssertError(t, command.Execute(), "stat project descriptor")
-					})
-				})
-			})
-		})
+					h.AssertNil(t, os.WriteFile(filepath.Join(projectDir, "project.toml"), []byte(`
+[_]
+schema-version = "0.2"
 
-		when("additional tags are specified", func() {
-			it("forwards additional tags to lifecycle", func() {
-				expectedTags := []string{"additional-tag-1", "additional-tag-2"}
-				mockClient.EXPECT().
-					Build(gomock.Any(), EqBuildOptionsWithAdditionalTags(expectedTags)).
-					Return(nil)
+[[io.buildpacks.hooks.pre-build]]
+command = 'echo "$PACK_IMAGE" > pre-build.out'
 
-				command.SetArgs([]string{"image", "--builder", "my-builder", "--tag", expectedTags[0], "--tag", expectedTags[1]})
-				h.AssertNil(t, command.Execute())
-			})
-		})
+[[io.buildpacks.hooks.post-build]]
+command = 'echo "$PACK_BUILD_REPORT" > post-build.out'
+`), 0600))
+				})
 
-		when("gid flag is provided", func() {
-			when("--gid is a valid value", func() {
-				it("override build option should be set to true", func() {
+				it.After(func() {
+					h.AssertNil(t, os.RemoveAll(projectDir))
+				})
+
+				it("should run the hooks from the project directory", func() {
 					mockClient.EXPECT().
-						Build(gomock.Any(), EqBuildOptionsWithOverrideGroupID(1)).
+						Build(gomock.Any(), gomock.Any()).
+						Do(func(_ context.Context, opts client.BuildOptions) {
+							contents, err := os.ReadFile(filepath.Join(projectDir, "pre-build.out"))
+							h.AssertNil(t, err)
+							h.AssertEq(t, strings.TrimSpace(string(contents)), "image")
+							h.AssertNotEq(t, opts.ReportDestinationDir, "")
+						}).
 						Return(nil)
 
-					command.SetArgs([]string{"--builder", "my-builder", "image", "--gid", "1"})
+					command.SetArgs([]string{"--builder", "my-builder", "--descriptor", filepath.Join(projectDir, "project.toml"), "image"})
 					h.AssertNil(t, command.Execute())
+					h.AssertContains(t, outBuf.String(), "Running pre-build hook")
+					h.AssertContains(t, outBuf.String(), "Running post-build hook")
+
+					contents, err := os.ReadFile(filepath.Join(projectDir, "post-build.out"))
+					h.AssertNil(t, err)
+					h.AssertEq(t, filepath.Base(strings.TrimSpace(string(contents))), "report.toml")
 				})
-			})
-			when("--gid is an invalid value", func() {
-				it("error must be thrown", func() {
-					command.SetArgs([]string{"--builder", "my-builder", "image", "--gid", "-1"})
-					err := command.Execute()
-					h.AssertError(t, err, "gid flag must be in the range of 0-2147483647")
-				})
-			})
-		})
 
-		when("gid flag is not provided", func() {
-			it("override build option should be set to false", func() {
-				mockClient.EXPECT().
-					Build(gomock.Any(), EqBuildOptionsWithOverrideGroupID(-1)).
-					Return(nil)
+				it("should not build if a pre-build hook fails", func() {
+					h.AssertNil(t, os.WriteFile(filepath.Join(projectDir, "project.toml"), []byte(`
+[_]
+schema-version = "0.2"
 
-				command.SetArgs([]string{"--builder", "my-builder", "image"})
-				h.AssertNil(t, command.Execute())
-			})
-		})
+[[io.buildpacks.hooks.pre-build]]
+command = "exit 3"
+`), 0600))
 
-		when("previous-image flag is provided", func() {
-			when("image is invalid", func() {
-				it("error must be thrown", func() {
-					command.SetArgs([]string{"--builder", "my-builder", "/x@/y/?!z", "--previous-image", "previous-image"})
-					err := command.Execute()
-					h.AssertError(t, err, "forbidden image name")
+					command.SetArgs([]string{"--builder", "my-builder", "--descriptor", filepath.Join(projectDir, "project.toml"), "image"})
+					h.AssertError(t, command.Execute(), "pre-build hook 'exit 3' failed")
 				})
-			})
 
-			when("previous-image is invalid", func() {
-				it("error must be thrown", func() {
+				it("should skip the hooks with --no-hooks", func() {
 					mockClient.EXPECT().
-						Build(gomock.Any(), EqBuildOptionsWithPreviousImage("%%%")).
-						Return(errors.New(""))
+						Build(gomock.Any(), gomock.Any()).
+						Return(nil)
 
-					command.SetArgs([]string{"--builder", "my-builder", "image", "--previous-image", "%%%"})
-					err := command.Execute()
-					h.AssertError(t, err, "failed to build")
+					command.SetArgs([]string{"--builder", "my-builder", "--descriptor", filepath.Join(projectDir, "project.toml"), "--no-hooks", "image"})
+					h.AssertNil(t, command.Execute())
+					h.AssertPathDoesNotExists(t, filepath.Join(projectDir, "pre-build.out"))
+					h.AssertPathDoesNotExists(t, filepath.Join(projectDir, "post-build.out"))
 				})
 			})
 
-			when("--publish is false", func() {
-				it("previous-image should be passed to builder", func() {
+			when("file declares a scan", func() {
+				var projectDir string
+
+				writeDescriptor := func(failOn string) {
+					h.AssertNil(t, os.WriteFile(filepath.Join(projectDir, "project.toml"), []byte(fmt.Sprintf(`
+[_]
+schema-version = "0.2"
+
+[io.buildpacks.scan]
+scanner = "grype"
+command = "%s"
+fail-on = "%s"
+`, filepath.Join(projectDir, "fake-grype"), failOn)), 0600))
+				}
+
+				it.Before(func() {
+					if runtime.GOOS == "windows" {
+						t.Skip("the fake scanner is written for sh")
+					}
+
+					var err error
+					projectDir, err = os.MkdirTemp("", "scan")
+					h.AssertNil(t, err)
+
+					h.AssertNil(t, os.WriteFile(filepath.Join(projectDir, "fake-grype"), []byte(`#!/bin/sh
+echo "$@" > "$(dirname "$0")/args.out"
+echo '{"matches": [{"vulnerability": {"id": "CVE-2024-0001", "severity": "High"}, "artifact": {"name": "openssl", "version": "3.0.2"}}]}'
+`), 0700))
+				})
+
+				it.After(func() {
+					h.AssertNil(t, os.RemoveAll(projectDir))
+				})
+
+				it("should scan the image after the build", func() {
+					writeDescriptor("critical")
 					mockClient.EXPECT().
-						Build(gomock.Any(), EqBuildOptionsWithPreviousImage("previous-image")).
+						Build(gomock.Any(), gomock.Any()).
 						Return(nil)
 
-					command.SetArgs([]string{"--builder", "my-builder", "image", "--previous-image", "previous-image"})
+					command.SetArgs([]string{"--builder", "my-builder", "--descriptor", filepath.Join(projectDir, "project.toml"), "image"})
+					h.AssertNil(t, command.Execute())
+					h.AssertContains(t, outBuf.String(), "Scanning 'image' for vulnerabilities with 'grype'")
+					h.AssertContains(t, outBuf.String(), "Found 1 vulnerabilities: 1 high")
+
+					args, err := os.ReadFile(filepath.Join(projectDir, "args.out"))
+					h.AssertNil(t, err)
+					h.AssertEq(t, strings.TrimSpace(string(args)), "docker:image --output json --quiet")
+				})
+
+				it("should fail on vulnerabilities at or above the threshold", func() {
+					writeDescriptor("high")
+					mockClient.EXPECT().
+						Build(gomock.Any(), gomock.Any()).
+						Return(nil)
+
+					command.SetArgs([]string{"--builder", "my-builder", "--descriptor", filepath.Join(projectDir, "project.toml"), "image"})
+					h.AssertError(t, command.Execute(), "scanning 'image': found 1 vulnerabilities of severity high or higher")
+				})
+
+				it("should skip the scan with --no-scan", func() {
+					writeDescriptor("high")
+					mockClient.EXPECT().
+						Build(gomock.Any(), gomock.Any()).
+						Return(nil)
+
+					command.SetArgs([]string{"--builder", "my-builder", "--descriptor", filepath.Join(projectDir, "project.toml"), "--no-scan", "image"})
 					h.AssertNil(t, command.Execute())
+					h.AssertPathDoesNotExists(t, filepath.Join(projectDir, "args.out"))
 				})
 			})
 
-			when("--publish is true", func() {
-				when("image and previous-image are in same registry", func() {
-					it("previous-image should be passed to builder", func() {
+			when("file declares apps", func() {
+				var projectDir string
+
+				it.Before(func() {
+					var err error
+					projectDir, err = os.MkdirTemp("", "monorepo")
+					h.AssertNil(t, err)
+
+					h.AssertNil(t, os.WriteFile(filepath.Join(projectDir, "project.toml"), []byte(`
+[_]
+schema-version = "0.2"
+
+[io.buildpacks]
+builder = "my-builder"
+
+[[io.buildpacks.apps]]
+path = "services/api"
+image = "api"
+
+[[io.buildpacks.apps.env]]
+name = "PORT"
+value = "8080"
+
+[[io.buildpacks.apps]]
+path = "services/web"
+image = "web"
+builder = "web-builder"
+`), 0600))
+				})
+
+				it.After(func() {
+					h.AssertNil(t, os.RemoveAll(projectDir))
+				})
+
+				it("builds every app with --all", func() {
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsForApp("my-builder", "api", filepath.Join(projectDir, "services", "api"))).
+						Return(nil)
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsForApp("web-builder", "web", filepath.Join(projectDir, "services", "web"))).
+						Return(nil)
+
+					command.SetArgs([]string{"--all", "--path", projectDir, "--jobs", "1"})
+					h.AssertNil(t, command.Execute())
+					h.AssertContains(t, outBuf.String(), "Building 2 apps, 1 at a time")
+					h.AssertContains(t, outBuf.String(), "[api] Successfully built image 'api'")
+					h.AssertContains(t, outBuf.String(), "[web] Successfully built image 'web'")
+				})
+
+				it("applies the env vars of the app", func() {
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithProjectDescriptorEnv([]projectTypes.EnvVar{{Name: "PORT", Value: "8080"}})).
+						Return(nil)
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithProjectDescriptorEnv(nil)).
+						Return(nil)
+
+					command.SetArgs([]string{"--all", "--path", projectDir, "--jobs", "1"})
+					h.AssertNil(t, command.Execute())
+				})
+
+				it("builds the remaining apps when one fails", func() {
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithImage("my-builder", "api")).
+						Return(errors.New("detect failed"))
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithImage("web-builder", "web")).
+						Return(nil)
+
+					command.SetArgs([]string{"--all", "--path", projectDir, "--jobs", "1"})
+					h.AssertError(t, command.Execute(), "1 of 2 apps failed to build")
+					h.AssertContains(t, outBuf.String(), "Failed to build 'api': failed to build: detect failed")
+				})
+
+				it("rejects an image name with --all", func() {
+					command.SetArgs([]string{"--all", "--path", projectDir, "image"})
+					h.AssertError(t, command.Execute(), "an image name cannot be provided with --all")
+				})
+
+				it("logs the builds with the tty progress when the output isn't a terminal", func() {
+					mockClient.EXPECT().Build(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+					command.SetArgs([]string{"--all", "--path", projectDir, "--jobs", "1", "--progress", "tty"})
+					h.AssertNil(t, command.Execute())
+					h.AssertContains(t, outBuf.String(), "[api] Successfully built image 'api'")
+				})
+			})
+
+			when("--all is used without apps", func() {
+				it("fails", func() {
+					projectDir, err := os.MkdirTemp("", "monorepo")
+					h.AssertNil(t, err)
+					defer os.RemoveAll(projectDir)
+
+					command.SetArgs([]string{"--all", "--path", projectDir})
+					h.AssertError(t, command.Execute(), "--all requires a project descriptor declaring apps")
+				})
+			})
+
+			when("file is invalid", func() {
+				var projectTomlPath string
+
+				it.Before(func() {
+					projectToml, err := os.CreateTemp("", "project.toml")
+					h.AssertNil(t, err)
+					defer projectToml.Close()
+
+					projectToml.WriteString("project]")
+					projectTomlPath = projectToml.Name()
+				})
+
+				it.After(func() {
+					h.AssertNil(t, os.RemoveAll(projectTomlPath))
+				})
+
+				it("should fail to build", func() {
+					command.SetArgs([]string{"--builder", "my-builder", "--descriptor", projectTomlPath, "image"})
+					h.AssertNotNil(t, command.Execute())
+				})
+			})
+
+			when("descriptor path is NOT specified", func() {
+				when("project.toml exists in source repo", func() {
+					it.Before(func() {
+						h.AssertNil(t, os.Chdir("testdata"))
+					})
+
+					it.After(func() {
+						h.AssertNil(t, os.Chdir(".."))
+					})
+
+					it("should use project.toml in source repo", func() {
 						mockClient.EXPECT().
-							Build(gomock.Any(), EqBuildOptionsWithPreviousImage("index.docker.io/some/previous:latest")).
+							Build(gomock.Any(), EqBuildOptionsWithProjectDescriptor(projectTypes.Descriptor{
+								Project: projectTypes.Project{
+									Name: "Sample",
+								},
+								Build: projectTypes.Build{
+									Buildpacks: []projectTypes.Buildpack{{
+										ID:      "example/lua",
+										Version: "1.0",
+									}},
+									Env: []projectTypes.EnvVar{{
+										Name:  "KEY1",
+										Value: "VALUE1",
+									}},
+								},
+								SchemaVersion: api.MustParse("0.1"),
+							})).
 							Return(nil)
 
-						command.SetArgs([]string{"--builder", "my-builder", "index.docker.io/some/image:latest", "--previous-image", "index.docker.io/some/previous:latest", "--publish"})
+						command.SetArgs([]string{"--builder", "my-builder", "image"})
+						h.AssertNil(t, command.Execute())
+					})
+				})
+
+				when("project.toml does NOT exist in source repo", func() {
+					it("should use empty descriptor", func() {
+						mockClient.EXPECT().
+							Build(gomock.Any(), EqBuildOptionsWithEnv(map[string]string{})).
+							Return(nil)
+
+						command.SetArgs([]string{"--builder", "my-builder", "image"})
+						h.AssertNil(t, command.Execute())
+					})
+				})
+			})
+
+			when("descriptor path is specified", func() {
+				when("descriptor file exists", func() {
+					var projectTomlPath string
+					it.Before(func() {
+						projectTomlPath = filepath.Join("testdata", "project.toml")
+					})
+
+					it("should use specified descriptor", func() {
+						mockClient.EXPECT().
+							Build(gomock.Any(), EqBuildOptionsWithProjectDescriptor(projectTypes.Descriptor{
+								Project: projectTypes.Project{
+									Name: "Sample",
+								},
+								Build: projectTypes.Build{
+									Buildpacks: []projectTypes.Buildpack{{
+										ID:      "example/lua",
+										Version: "1.0",
+									}},
+									Env: []projectTypes.EnvVar{{
+										Name:  "KEY1",
+										Value: "VALUE1",
+									}},
+								},
+								SchemaVersion: api.MustParse("0.1"),
+							})).
+							Return(nil)
+
+						command.SetArgs([]string{"--builder", "my-builder", "--descriptor", projectTomlPath, "image"})
 						h.AssertNil(t, command.Execute())
 					})
 				})
+
+				when("descriptor file does NOT exist in source repo", func() {
+					it("should fail with an error message", func() {
+						command.SetArgs([]string{"--builder", "my-builder", "--descriptor", "non-existent-path", "image"})
+						h.AssertError(t, command.Execute(), "stat project descriptor")
+					})
+				})
+			})
+		})
+
+		when("completing --buildpack", func() {
+			var (
+				packHome string
+				complete func(toComplete string) []string
+			)
+
+			it.Before(func() {
+				var err error
+				packHome, err = os.MkdirTemp("", "pack-home")
+				h.AssertNil(t, err)
+				t.Setenv("PACK_HOME", packHome)
+
+				cache, err := registry.NewDefaultRegistryCache(logging.NewSimpleLogger(io.Discard), packHome)
+				h.AssertNil(t, err)
+				h.AssertNil(t, os.MkdirAll(filepath.Join(cache.Root, "ja", "va"), 0755))
+				h.AssertNil(t, os.WriteFile(filepath.Join(cache.Root, "ja", "va", "example_java"), []byte(
+					`{"ns":"example","name":"java","version":"1.0.0","yanked":false,"addr":"example.com/java@sha256:8c27fe111c11b722081701dfed3bd55e039b9ce92865473cf4cdfa918071c566"}
+{"ns":"example","name":"java","version":"1.1.0","yanked":false,"addr":"example.com/java@sha256:8c27fe111c11b722081701dfed3bd55e039b9ce92865473cf4cdfa918071c566"}
+{"ns":"example","name":"java","version":"1.1.1","yanked":true}
+`), 0600))
+				h.AssertNil(t, os.MkdirAll(filepath.Join(cache.Root, "go"), 0755))
+				h.AssertNil(t, os.WriteFile(filepath.Join(cache.Root, "go", "other_go"), []byte{}, 0600))
+
+				complete = func(toComplete string) []string {
+					var out bytes.Buffer
+					command.SetOut(&out)
+					command.SetErr(io.Discard)
+					command.SetArgs([]string{"__complete", "--buildpack", toComplete})
+					h.AssertNil(t, command.Execute())
+					lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+					return lines[:len(lines)-1]
+				}
+			})
+
+			it.After(func() {
+				h.AssertNil(t, os.RemoveAll(packHome))
+			})
+
+			it("completes buildpacks of the cached registry", func() {
+				h.AssertEq(t, complete("urn:cnb:registry:ex"), []string{"urn:cnb:registry:example/java"})
+				h.AssertEq(t, complete("some/bp,urn:cnb:registry:"), []string{"some/bp,urn:cnb:registry:example/java", "some/bp,urn:cnb:registry:other/go"})
+			})
+
+			it("completes versions that weren't yanked", func() {
+				h.AssertEq(t, complete("urn:cnb:registry:example/java@"), []string{"urn:cnb:registry:example/java@1.1.0", "urn:cnb:registry:example/java@1.0.0"})
+			})
+
+			it("completes the registry prefix", func() {
+				h.AssertEq(t, complete("urn:"), []string{"urn:cnb:registry:"})
+				h.AssertEq(t, complete("./buildpacks"), []string{})
+			})
+		})
+
+		when("additional tags are specified", func() {
+			it("forwards additional tags to lifecycle", func() {
+				expectedTags := []string{"additional-tag-1", "additional-tag-2"}
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithAdditionalTags(expectedTags)).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--tag", expectedTags[0], "--tag", expectedTags[1]})
+				h.AssertNil(t, command.Execute())
+			})
+		})
+
+		when("gid flag is provided", func() {
+			when("--gid is a valid value", func() {
+				it("override build option should be set to true", func() {
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithOverrideGroupID(1)).
+						Return(nil)
+
+					command.SetArgs([]string{"--builder", "my-builder", "image", "--gid", "1"})
+					h.AssertNil(t, command.Execute())
+				})
+			})
+			when("--gid is an invalid value", func() {
+				it("error must be thrown", func() {
+					command.SetArgs([]string{"--builder", "my-builder", "image", "--gid", "-1"})
+					err := command.Execute()
+					h.AssertError(t, err, "gid flag must be in the range of 0-2147483647")
+				})
+			})
+		})
+
+		when("gid flag is not provided", func() {
+			it("override build option should be set to false", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithOverrideGroupID(-1)).
+					Return(nil)
+
+				command.SetArgs([]string{"--builder", "my-builder", "image"})
+				h.AssertNil(t, command.Execute())
+			})
+		})
+
+		when("previous-image flag is provided", func() {
+			when("image is invalid", func() {
+				it("error must be thrown", func() {
+					command.SetArgs([]string{"--builder", "my-builder", "/x@/y/?!z", "--previous-image", "previous-image"})
+					err := command.Execute()
+					h.AssertError(t, err, "forbidden image name")
+				})
+			})
+
+			when("previous-image is invalid", func() {
+				it("error must be thrown", func() {
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithPreviousImage("%%%")).
+						Return(errors.New(""))
+
+					command.SetArgs([]string{"--builder", "my-builder", "image", "--previous-image", "%%%"})
+					err := command.Execute()
+					h.AssertError(t, err, "failed to build")
+				})
+			})
+
+			when("--publish is false", func() {
+				it("previous-image should be passed to builder", func() {
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithPreviousImage("previous-image")).
+						Return(nil)
+
+					command.SetArgs([]string{"--builder", "my-builder", "image", "--previous-image", "previous-image"})
+					h.AssertNil(t, command.Execute())
+				})
+			})
+
+			when("--publish is true", func() {
+				when("image and previous-image are in same registry", func() {
+					it("previous-image should be passed to builder", func() {
+						mockClient.EXPECT().
+							Build(gomock.Any(), EqBuildOptionsWithPreviousImage("index.docker.io/some/previous:latest")).
+							Return(nil)
+
+						command.SetArgs([]string{"--builder", "my-builder", "index.docker.io/some/image:latest", "--previous-image", "index.docker.io/some/previous:latest", "--publish"})
+						h.AssertNil(t, command.Execute())
+					})
+				})
+			})
+		})
+
+		when("daemonless flag is provided but experimental isn't set in the config", func() {
+			it("errors with a descriptive message", func() {
+				command.SetArgs([]string{"image", "--daemonless", "--publish"})
+				h.AssertError(t, command.Execute(), "Daemonless builds are currently experimental.")
+			})
+		})
+
+		when("kubernetes driver is provided but experimental isn't set in the config", func() {
+			it("errors with a descriptive message", func() {
+				command.SetArgs([]string{"image", "--driver", "kubernetes", "--publish"})
+				h.AssertError(t, command.Execute(), "The kubernetes driver is currently experimental.")
+			})
+		})
+
+		when("interactive flag is provided but experimental isn't set in the config", func() {
+			it("errors with a descriptive message", func() {
+				command.SetArgs([]string{"image", "--interactive"})
+				err := command.Execute()
+				h.AssertNotNil(t, err)
+				h.AssertError(t, err, "Interactive mode is currently experimental.")
+			})
+		})
+
+		when("sbom destination directory is provided", func() {
+			it("forwards the network onto the client", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithSBOMOutputDir("some-output-dir")).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--sbom-output-dir", "some-output-dir"})
+				h.AssertNil(t, command.Execute())
+			})
+		})
+
+		when("--creation-time", func() {
+			when("provided as 'now'", func() {
+				it("passes it to the builder", func() {
+					expectedTime := time.Now().UTC()
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithDateTime(&expectedTime)).
+						Return(nil)
+
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--creation-time", "now"})
+					h.AssertNil(t, command.Execute())
+				})
+			})
+
+			when("provided as unix timestamp", func() {
+				it("passes it to the builder", func() {
+					expectedTime, err := time.Parse("2006-01-02T03:04:05Z", "2019-08-19T00:00:01Z")
+					h.AssertNil(t, err)
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithDateTime(&expectedTime)).
+						Return(nil)
+
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--creation-time", "1566172801"})
+					h.AssertNil(t, command.Execute())
+				})
+			})
+
+			when("not provided", func() {
+				it("is nil", func() {
+					t.Setenv("SOURCE_DATE_EPOCH", "")
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithDateTime(nil)).
+						Return(nil)
+
+					command.SetArgs([]string{"image", "--builder", "my-builder"})
+					h.AssertNil(t, command.Execute())
+				})
+
+				when("SOURCE_DATE_EPOCH is set", func() {
+					it.Before(func() {
+						t.Setenv("SOURCE_DATE_EPOCH", "1566172801")
+					})
+
+					it("passes it to the builder", func() {
+						expectedTime := time.Unix(1566172801, 0).UTC()
+						mockClient.EXPECT().
+							Build(gomock.Any(), EqBuildOptionsWithDateTime(&expectedTime)).
+							Return(nil)
+
+						command.SetArgs([]string{"image", "--builder", "my-builder"})
+						h.AssertNil(t, command.Execute())
+					})
+
+					it("is overridden by the flag", func() {
+						expectedTime := time.Now().UTC()
+						mockClient.EXPECT().
+							Build(gomock.Any(), EqBuildOptionsWithDateTime(&expectedTime)).
+							Return(nil)
+
+						command.SetArgs([]string{"image", "--builder", "my-builder", "--creation-time", "now"})
+						h.AssertNil(t, command.Execute())
+					})
+
+					it("errors when it isn't a unix timestamp", func() {
+						t.Setenv("SOURCE_DATE_EPOCH", "yesterday")
+
+						command.SetArgs([]string{"image", "--builder", "my-builder"})
+						h.AssertError(t, command.Execute(), "parsing SOURCE_DATE_EPOCH yesterday")
+					})
+				})
+			})
+		})
+
+		when("--lifecycle-log-level", func() {
+			it("passes it to the builder", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithLifecycleLogLevel("warn")).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--lifecycle-log-level", "warn"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			when("the level is not supported by the lifecycle", func() {
+				it("errors with a descriptive message", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--lifecycle-log-level", "verbose"})
+					err := command.Execute()
+					h.AssertError(t, err, "invalid lifecycle log level 'verbose', must be one of debug, info, warn, error")
+				})
+			})
+		})
+
+		when("--detect-only", func() {
+			it("only detects, without reporting a built image", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithDetectOnly(true)).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--detect-only"})
+				h.AssertNil(t, command.Execute())
+				h.AssertNotContains(t, outBuf.String(), "Successfully built image")
+			})
+
+			it("errors with --interactive", func() {
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--detect-only", "--interactive"})
+				h.AssertError(t, command.Execute(), "detect-only flag cannot be used with the interactive flag")
+			})
+		})
+
+		when("--export-config", func() {
+			it("prints the pipeline rather than building", func() {
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--env", "BP_SOME_VAR=some-value", "--trust-builder", "--export-config", "gha"})
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), "run: pack build image --builder my-builder")
+				h.AssertContains(t, outBuf.String(), "--env BP_SOME_VAR=some-value --trust-builder --publish")
+			})
+
+			it("errors for an unknown format", func() {
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--export-config", "jenkins"})
+				h.AssertError(t, command.Execute(), "invalid export config 'jenkins', must be one of tekton, gha")
+			})
+		})
+
+		when("the path is the URL of a git repository", func() {
+			var (
+				repoDir string
+				repoURL string
+			)
+
+			it.Before(func() {
+				h.SkipIf(t, runtime.GOOS == "windows", "uses a repository created with sh")
+				_, err := exec.LookPath("git")
+				h.SkipIf(t, err != nil, "git isn't installed")
+
+				repoDir, err = os.MkdirTemp("", "git-source")
+				h.AssertNil(t, err)
+				script := "git init --quiet && echo main > app.txt && git add app.txt && " +
+					"git -c user.name=pack -c user.email=pack@example.com commit --quiet -m app"
+				cmd := exec.Command("sh", "-c", script)
+				cmd.Dir = repoDir
+				output, err := cmd.CombinedOutput()
+				h.AssertNilE(t, errors.Wrap(err, string(output)))
+				repoURL = "file://" + repoDir
+			})
+
+			it.After(func() {
+				h.AssertNil(t, os.RemoveAll(repoDir))
+			})
+
+			it("builds the source fetched in a temporary directory", func() {
+				var appPath string
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithAppFile("app.txt", "main\n")).
+					Do(func(_ context.Context, opts client.BuildOptions) { appPath = opts.AppPath }).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--path", repoURL})
+				h.AssertNil(t, command.Execute())
+				h.AssertPathDoesNotExists(t, appPath)
+			})
+
+			when("--git-cache", func() {
+				it("keeps the source in the pack home", func() {
+					packHome, err := os.MkdirTemp("", "pack-home")
+					h.AssertNil(t, err)
+					defer os.RemoveAll(packHome)
+					t.Setenv("PACK_HOME", packHome)
+
+					var appPath string
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithAppFile("app.txt", "main\n")).
+						Do(func(_ context.Context, opts client.BuildOptions) { appPath = opts.AppPath }).
+						Return(nil)
+
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--path", repoURL, "--git-cache"})
+					h.AssertNil(t, command.Execute())
+					h.AssertEq(t, filepath.Dir(appPath), filepath.Join(packHome, "git-sources"))
+					h.AssertPathExists(t, filepath.Join(appPath, "app.txt"))
+				})
+			})
+
+			it("errors for an unknown ref", func() {
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--path", repoURL + "#missing"})
+				h.AssertError(t, command.Execute(), "fetching '"+repoURL+"#missing'")
+			})
+		})
+
+		when("the path is the URL of an archive", func() {
+			var (
+				server  *httptest.Server
+				archive []byte
+			)
+
+			it.Before(func() {
+				var buf bytes.Buffer
+				zw := zip.NewWriter(&buf)
+				w, err := zw.Create("app.txt")
+				h.AssertNil(t, err)
+				_, err = w.Write([]byte("zip"))
+				h.AssertNil(t, err)
+				h.AssertNil(t, zw.Close())
+				archive = buf.Bytes()
+
+				server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Write(archive)
+				}))
+			})
+
+			it.After(func() {
+				server.Close()
+			})
+
+			it("builds the extracted archive", func() {
+				var appPath string
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithAppFile("app.txt", "zip")).
+					Do(func(_ context.Context, opts client.BuildOptions) { appPath = opts.AppPath }).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--path", server.URL + "/app.zip"})
+				h.AssertNil(t, command.Execute())
+				h.AssertPathDoesNotExists(t, appPath)
+			})
+
+			when("the archive has a project descriptor", func() {
+				var hookOut string
+
+				it.Before(func() {
+					if runtime.GOOS == "windows" {
+						t.Skip("hooks are written for sh")
+					}
+					t.Setenv("PACK_TEST_TOKEN", "secret")
+
+					hookDir, err := os.MkdirTemp("", "remote-hooks")
+					h.AssertNil(t, err)
+					hookOut = filepath.Join(hookDir, "pre-build.out")
+
+					var buf bytes.Buffer
+					zw := zip.NewWriter(&buf)
+					w, err := zw.Create("project.toml")
+					h.AssertNil(t, err)
+					_, err = w.Write([]byte(`
+[_]
+schema-version = "0.2"
+
+[[io.buildpacks.build.env]]
+name = "TOKEN"
+value = "${env:PACK_TEST_TOKEN:-none}"
+
+[[io.buildpacks.hooks.pre-build]]
+command = 'touch ` + hookOut + `'
+`))
+					h.AssertNil(t, err)
+					h.AssertNil(t, zw.Close())
+					archive = buf.Bytes()
+				})
+
+				it.After(func() {
+					h.AssertNil(t, os.RemoveAll(filepath.Dir(hookOut)))
+				})
+
+				it("skips the hooks and doesn't read the environment", func() {
+					mockClient.EXPECT().
+						Build(gomock.Any(), gomock.Any()).
+						Do(func(_ context.Context, opts client.BuildOptions) {
+							h.AssertEq(t, opts.ProjectDescriptor.Build.Env, []projectTypes.EnvVar{{Name: "TOKEN", Value: "none"}})
+						}).
+						Return(nil)
+
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--path", server.URL + "/app.zip"})
+					h.AssertNil(t, command.Execute())
+					h.AssertPathDoesNotExists(t, hookOut)
+					h.AssertContains(t, outBuf.String(), "Skipping the hooks of the project descriptor of a remote source")
+				})
+
+				it("runs the hooks and reads the environment with --allow-hooks", func() {
+					mockClient.EXPECT().
+						Build(gomock.Any(), gomock.Any()).
+						Do(func(_ context.Context, opts client.BuildOptions) {
+							h.AssertEq(t, opts.ProjectDescriptor.Build.Env, []projectTypes.EnvVar{{Name: "TOKEN", Value: "secret"}})
+						}).
+						Return(nil)
+
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--path", server.URL + "/app.zip", "--allow-hooks"})
+					h.AssertNil(t, command.Execute())
+					h.AssertPathExists(t, hookOut)
+				})
+			})
+
+			when("--source-checksum", func() {
+				it("builds the archive with the checksum", func() {
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithAppFile("app.txt", "zip")).
+						Return(nil)
+
+					sum := sha256.Sum256(archive)
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--path", server.URL + "/app.zip", "--source-checksum", "sha256:" + hex.EncodeToString(sum[:])})
+					h.AssertNil(t, command.Execute())
+				})
+
+				it("errors for another checksum", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--path", server.URL + "/app.zip", "--source-checksum", strings.Repeat("0", 64)})
+					h.AssertError(t, command.Execute(), "expected 'sha256:"+strings.Repeat("0", 64)+"'")
+				})
+
+				it("errors for an invalid checksum", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--path", server.URL + "/app.zip", "--source-checksum", "abc"})
+					h.AssertError(t, command.Execute(), "invalid checksum 'abc'")
+				})
+			})
+		})
+
+		when("--source-checksum is provided with a local path", func() {
+			it("errors", func() {
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--source-checksum", strings.Repeat("0", 64)})
+				h.AssertError(t, command.Execute(), "source-checksum flag requires the path to be the URL of an archive")
+			})
+		})
+
+		when("--git-cache is provided with a local path", func() {
+			it("errors", func() {
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--git-cache"})
+				h.AssertError(t, command.Execute(), "git-cache flag requires the path to be the URL of a git repository")
+			})
+		})
+
+		when("--push-retries", func() {
+			it("doesn't retry pushes by default", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithPushRetries(0)).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--publish"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("passes it to the builder", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithPushRetries(3)).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--publish", "--push-retries", "3"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			when("the image isn't published", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--push-retries", "3"})
+					h.AssertError(t, command.Execute(), "push-retries flag requires the publish flag")
+				})
+			})
+
+			when("it is negative", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--publish", "--push-retries", "-1"})
+					err := command.Execute()
+					h.AssertError(t, err, "push-retries flag must not be negative")
+				})
+			})
+		})
+
+		when("--lifecycle-env", func() {
+			it("passes the env vars to the builder", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithLifecycleEnv(map[string]string{
+						"CNB_EXPERIMENTAL_MODE": "warn",
+						"CNB_SKIP_LAYERS":       "true",
+					})).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--lifecycle-env", "CNB_EXPERIMENTAL_MODE=warn", "--lifecycle-env", "CNB_SKIP_LAYERS=true"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			when("the env var has no name", func() {
+				it("errors with a descriptive message", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--lifecycle-env", "=warn"})
+					err := command.Execute()
+					h.AssertError(t, err, "invalid lifecycle env '=warn'")
+				})
+			})
+		})
+
+		when("--debug-bundle", func() {
+			it("writes a debug bundle when the build fails", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, opts client.BuildOptions) error {
+						opts.Logger.Info("===> BUILDING")
+						h.AssertNil(t, opts.CheckPlan(client.BuildPlan{Builder: "my-builder", Image: "image"}))
+						return errors.New("failed with status code: 51")
+					})
+				mockClient.EXPECT().
+					CollectDebugBundle(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, opts client.DebugBundleOptions) ([]string, error) {
+						h.AssertEq(t, opts.Path, "debug.tar.gz")
+						h.AssertEq(t, opts.Build.Builder, "my-builder")
+						h.AssertEq(t, opts.Plan.Image, "image")
+						h.AssertContains(t, string(opts.BuildLog), "===> BUILDING")
+						h.AssertError(t, opts.Err, "failed with status code: 51")
+						return []string{"build.log"}, nil
+					})
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--debug-bundle", "debug.tar.gz"})
+				h.AssertError(t, command.Execute(), "failed to build")
+				h.AssertContains(t, outBuf.String(), "Wrote debug bundle to 'debug.tar.gz'")
+			})
+
+			it("doesn't write a debug bundle when the build succeeds", func() {
+				mockClient.EXPECT().Build(gomock.Any(), gomock.Any()).Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--debug-bundle", "debug.tar.gz"})
+				h.AssertNil(t, command.Execute())
+			})
+		})
+
+		when("--label and --annotation", func() {
+			var projectDir string
+
+			it.Before(func() {
+				var err error
+				projectDir, err = os.MkdirTemp("", "labels")
+				h.AssertNil(t, err)
+
+				h.AssertNil(t, os.WriteFile(filepath.Join(projectDir, "project.toml"), []byte(`
+[_]
+schema-version = "0.2"
+
+[io.buildpacks.labels]
+"org.opencontainers.image.vendor" = "some-vendor"
+"org.opencontainers.image.title" = "some-title"
+`), 0600))
+			})
+
+			it.After(func() {
+				h.AssertNil(t, os.RemoveAll(projectDir))
+			})
+
+			it("merges the flags over the labels of the project descriptor", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), gomock.Any()).
+					Do(func(_ context.Context, opts client.BuildOptions) {
+						h.AssertEq(t, opts.Labels, map[string]string{
+							"org.opencontainers.image.vendor": "some-vendor",
+							"org.opencontainers.image.title":  "other-title",
+						})
+						h.AssertEq(t, opts.Annotations, map[string]string{"org.opencontainers.image.url": "https://example.com"})
+					}).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--descriptor", filepath.Join(projectDir, "project.toml"),
+					"--label", "org.opencontainers.image.title=other-title", "--annotation", "org.opencontainers.image.url=https://example.com"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("passes the project information and metadata through to the labels", func() {
+				h.AssertNil(t, os.WriteFile(filepath.Join(projectDir, "project.toml"), []byte(`
+[_]
+schema-version = "0.2"
+name = "some-app"
+version = "1.2.3"
+source-url = "https://example.com/source"
+
+[_.metadata.org.example]
+team = "payments"
+
+[io.buildpacks.labels]
+"org.opencontainers.image.version" = "1.2.3-declared"
+`), 0600))
+				mockClient.EXPECT().
+					Build(gomock.Any(), gomock.Any()).
+					Do(func(_ context.Context, opts client.BuildOptions) {
+						h.AssertEq(t, opts.Labels, map[string]string{
+							"org.opencontainers.image.title":   "other-title",
+							"org.opencontainers.image.version": "1.2.3-declared",
+							"org.opencontainers.image.source":  "https://example.com/source",
+							"org.example.team":                 "payments",
+						})
+					}).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--descriptor", filepath.Join(projectDir, "project.toml"),
+					"--label", "org.opencontainers.image.title=other-title"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			when("the label isn't of the form KEY=VALUE", func() {
+				it("errors with a descriptive message", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--label", "some-label"})
+					err := command.Execute()
+					h.AssertError(t, err, "invalid label 'some-label', must be of the form 'KEY=VALUE'")
+				})
+			})
+		})
+
+		when("the build succeeds", func() {
+			it("prints a summary of the build", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), gomock.Any()).
+					Do(func(_ context.Context, opts client.BuildOptions) {
+						*opts.Summary = client.BuildSummary{
+							Image:  "index.docker.io/library/image:latest",
+							Digest: "sha256:abc123",
+							Size:   12345678,
+							Buildpacks: []client.BuildpackSummary{
+								{ID: "some/buildpack", Version: "1.2.3", Cache: client.CacheHit},
+								{ID: "other/buildpack", Version: "4.5.6"},
+							},
+							Processes:      []string{"web", "worker"},
+							DefaultProcess: "web",
+							Duration:       83 * time.Second,
+						}
+					}).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder"})
+				h.AssertNil(t, command.Execute())
+
+				output := outBuf.String()
+				h.AssertContains(t, output, "Build summary:")
+				h.AssertContainsMatch(t, output, `Image:\s+index.docker.io/library/image:latest`)
+				h.AssertContainsMatch(t, output, `Digest:\s+sha256:abc123`)
+				h.AssertContainsMatch(t, output, `Size:\s+12 MB`)
+				h.AssertContainsMatch(t, output, `Processes:\s+web \(default\), worker`)
+				h.AssertContainsMatch(t, output, `Total time:\s+1m23s`)
+				h.AssertContainsMatch(t, output, `some/buildpack\s+1.2.3\s+hit`)
+				h.AssertContainsMatch(t, output, `other/buildpack\s+4.5.6\s+-`)
+			})
+
+			when("quiet", func() {
+				it("does not ask for a summary when the build can't be skipped", func() {
+					logger.WantQuiet(true)
+					mockClient.EXPECT().
+						Build(gomock.Any(), gomock.Any()).
+						Do(func(_ context.Context, opts client.BuildOptions) {
+							h.AssertNil(t, opts.Summary)
+						}).
+						Return(nil)
+
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--force"})
+					h.AssertNil(t, command.Execute())
+					h.AssertNotContains(t, outBuf.String(), "Build summary:")
+				})
+			})
+		})
+
+		when("--timings", func() {
+			it("prints the time of each phase and of the slowest buildpacks first", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), gomock.Any()).
+					Do(func(_ context.Context, opts client.BuildOptions) {
+						*opts.Summary = client.BuildSummary{
+							Image: "index.docker.io/library/image:latest",
+							Buildpacks: []client.BuildpackSummary{
+								{ID: "some/buildpack", Version: "1.2.3", Duration: 2 * time.Second},
+								{ID: "other/buildpack", Version: "4.5.6", Duration: 40 * time.Second},
+							},
+							Phases: []client.PhaseSummary{
+								{Name: "detector", Duration: 1500 * time.Millisecond},
+								{Name: "builder", Duration: 42 * time.Second},
+							},
+							Duration: 83 * time.Second,
+						}
+					}).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--timings"})
+				h.AssertNil(t, command.Execute())
+
+				output := outBuf.String()
+				h.AssertContains(t, output, "Build timings:")
+				h.AssertContainsMatch(t, output, `detector\s+1.5s`)
+				h.AssertContainsMatch(t, output, `builder\s+42s`)
+				h.AssertContainsMatch(t, output, `total\s+1m23s`)
+				h.AssertContainsMatch(t, output, `(?s)other/buildpack\s+4.5.6\s+40s.*some/buildpack\s+1.2.3\s+2s`)
+			})
+
+			it("explains when buildpacks weren't timed", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), gomock.Any()).
+					Do(func(_ context.Context, opts client.BuildOptions) {
+						*opts.Summary = client.BuildSummary{
+							Image:      "index.docker.io/library/image:latest",
+							Buildpacks: []client.BuildpackSummary{{ID: "some/buildpack", Version: "1.2.3"}},
+						}
+					}).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--timings"})
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), "Buildpacks are only timed when the lifecycle logs at debug level")
+			})
+		})
+
+		when("--format", func() {
+			it("prints a json report of the build as the only output on stdout", func() {
+				var stdout, stderr bytes.Buffer
+				command = commands.Build(logging.NewLogWithWriters(&stdout, &stderr), cfg, mockClient)
+				started := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+				mockClient.EXPECT().
+					Build(gomock.Any(), gomock.Any()).
+					Do(func(_ context.Context, opts client.BuildOptions) {
+						opts.Logger.Info("===> BUILDING")
+						*opts.Summary = client.BuildSummary{
+							Image:  "index.docker.io/library/image:latest",
+							Digest: "sha256:abc123",
+							Buildpacks: []client.BuildpackSummary{
+								{ID: "some/buildpack", Version: "1.2.3", Homepage: "https://example.com/some/buildpack", Cache: client.CacheHit},
+							},
+							Processes:      []string{"web", "worker"},
+							DefaultProcess: "web",
+							Phases: []client.PhaseSummary{
+								{Name: "detector", Duration: 1500 * time.Millisecond},
+								{Name: "exporter", Duration: 3 * time.Second},
+							},
+							Builder:        "index.docker.io/library/my-builder:latest",
+							BuilderDigest:  "sha256:def456",
+							RunImage:       "index.docker.io/library/run:latest",
+							RunImageDigest: "sha256:789abc",
+							Started:        started,
+							Duration:       83 * time.Second,
+						}
+					}).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--format", "json"})
+				h.AssertNil(t, command.Execute())
+
+				h.AssertContains(t, stderr.String(), "===> BUILDING")
+				h.AssertNotContains(t, stderr.String(), "Build summary:")
+				var report map[string]interface{}
+				h.AssertNil(t, json.Unmarshal(stdout.Bytes(), &report))
+				h.AssertEq(t, report["image"], "index.docker.io/library/image:latest")
+				h.AssertEq(t, report["digest"], "sha256:abc123")
+				h.AssertEq(t, report["builder"], map[string]interface{}{"image": "index.docker.io/library/my-builder:latest", "digest": "sha256:def456"})
+				h.AssertEq(t, report["runImage"], map[string]interface{}{"image": "index.docker.io/library/run:latest", "digest": "sha256:789abc"})
+				h.AssertEq(t, report["buildpacks"], []interface{}{
+					map[string]interface{}{"id": "some/buildpack", "version": "1.2.3", "homepage": "https://example.com/some/buildpack", "cache": "hit"},
+				})
+				h.AssertEq(t, report["processes"], []interface{}{
+					map[string]interface{}{"type": "web", "default": true},
+					map[string]interface{}{"type": "worker"},
+				})
+				h.AssertEq(t, report["phases"], []interface{}{
+					map[string]interface{}{"name": "detector", "durationSeconds": 1.5},
+					map[string]interface{}{"name": "exporter", "durationSeconds": float64(3)},
+				})
+				h.AssertEq(t, report["started"], "2024-01-01T00:00:00Z")
+				h.AssertEq(t, report["durationSeconds"], float64(83))
+			})
+
+			it("errors for an unknown format", func() {
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--format", "yaml"})
+				h.AssertError(t, command.Execute(), "unknown format 'yaml', must be human-readable or json")
+			})
+
+			it("errors with the detect-only flag", func() {
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--format", "json", "--detect-only"})
+				h.AssertError(t, command.Execute(), "json format cannot be used with the detect-only, interactive or export-config flags")
+			})
+		})
+
+		when("--progress", func() {
+			it("shows plain logs when the output isn't a terminal", func() {
+				command = commands.Build(logging.NewLogWithWriters(&outBuf, &outBuf, logging.WithVerbose()), cfg, mockClient)
+				mockClient.EXPECT().
+					Build(gomock.Any(), gomock.Any()).
+					Do(func(_ context.Context, opts client.BuildOptions) {
+						h.AssertEq(t, opts.LifecycleLogLevel, "")
+						opts.Logger.Info("===> BUILDING")
+					}).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--progress", "tty"})
+				h.AssertNil(t, command.Execute())
+
+				h.AssertContains(t, outBuf.String(), "Showing plain logs, as the output isn't a terminal")
+				h.AssertContains(t, outBuf.String(), "===> BUILDING")
+			})
+
+			it("errors for an unknown progress", func() {
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--progress", "fancy"})
+				h.AssertError(t, command.Execute(), "unknown progress 'fancy', must be plain or tty")
+			})
+
+			it("errors with the json format", func() {
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--progress", "tty", "--format", "json"})
+				h.AssertError(t, command.Execute(), "tty progress cannot be used with the interactive flag or the json format")
+			})
+		})
+
+		when("provenance is requested", func() {
+			it("writes the provenance of the build", func() {
+				provenanceFile := filepath.Join(t.TempDir(), "provenance.json")
+				mockClient.EXPECT().
+					Build(gomock.Any(), gomock.Any()).
+					Do(func(_ context.Context, opts client.BuildOptions) {
+						*opts.Summary = client.BuildSummary{
+							Image:   "index.docker.io/library/image:latest",
+							Digest:  "sha256:abc123",
+							Builder: "index.docker.io/library/my-builder:latest",
+						}
+					}).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--env", "TOKEN=secret", "--provenance", provenanceFile})
+				h.AssertNil(t, command.Execute())
+
+				contents, err := os.ReadFile(provenanceFile)
+				h.AssertNil(t, err)
+				h.AssertContains(t, string(contents), `"predicateType": "https://slsa.dev/provenance/v1"`)
+				h.AssertContains(t, string(contents), `"sha256": "abc123"`)
+				h.AssertContains(t, string(contents), `"TOKEN"`)
+				h.AssertNotContains(t, string(contents), "secret")
+			})
+
+			it("attaches the provenance to the published image", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), gomock.Any()).
+					Do(func(_ context.Context, opts client.BuildOptions) {
+						*opts.Summary = client.BuildSummary{
+							Image:  "index.docker.io/library/image:latest",
+							Digest: "sha256:0000000000000000000000000000000000000000000000000000000000000001",
+						}
+					}).
+					Return(nil)
+				mockClient.EXPECT().
+					AttachAttestation(gomock.Any(), "index.docker.io/library/image@sha256:0000000000000000000000000000000000000000000000000000000000000001", gomock.Any()).
+					Return("sha256:0000000000000000000000000000000000000000000000000000000000000002", nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--publish", "--attach-provenance"})
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), "Provenance attached")
+			})
+
+			it("requires --publish to attach the provenance", func() {
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--attach-provenance"})
+				h.AssertError(t, command.Execute(), "attach-provenance flag requires the publish flag")
 			})
 		})
 
-		when("interactive flag is provided but experimental isn't set in the config", func() {
-			it("errors with a descriptive message", func() {
-				command.SetArgs([]string{"image", "--interactive"})
-				err := command.Execute()
-				h.AssertNotNil(t, err)
-				h.AssertError(t, err, "Interactive mode is currently experimental.")
+		when("--force", func() {
+			it("skips builds of unchanged sources by default", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithSkipUnchanged(true)).
+					Return(nil)
+
+				command.SetArgs([]string{"--builder", "my-builder", "image"})
+				h.AssertNil(t, command.Execute())
 			})
-		})
 
-		when("sbom destination directory is provided", func() {
-			it("forwards the network onto the client", func() {
+			it("builds even when the source is unchanged", func() {
 				mockClient.EXPECT().
-					Build(gomock.Any(), EqBuildOptionsWithSBOMOutputDir("some-output-dir")).
+					Build(gomock.Any(), EqBuildOptionsWithSkipUnchanged(false)).
 					Return(nil)
 
-				command.SetArgs([]string{"image", "--builder", "my-builder", "--sbom-output-dir", "some-output-dir"})
+				command.SetArgs([]string{"--builder", "my-builder", "image", "--force"})
 				h.AssertNil(t, command.Execute())
 			})
-		})
 
-		when("--creation-time", func() {
-			when("provided as 'now'", func() {
-				it("passes it to the builder", func() {
-					expectedTime := time.Now().UTC()
-					mockClient.EXPECT().
-						Build(gomock.Any(), EqBuildOptionsWithDateTime(&expectedTime)).
-						Return(nil)
+			it("doesn't report a skipped build as built", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, opts client.BuildOptions) error {
+						opts.Summary.Skipped = true
+						return nil
+					})
 
-					command.SetArgs([]string{"image", "--builder", "my-builder", "--creation-time", "now"})
-					h.AssertNil(t, command.Execute())
-				})
+				command.SetArgs([]string{"--builder", "my-builder", "image"})
+				h.AssertNil(t, command.Execute())
+				h.AssertNotContains(t, outBuf.String(), "Successfully built image")
 			})
 
-			when("provided as unix timestamp", func() {
-				it("passes it to the builder", func() {
-					expectedTime, err := time.Parse("2006-01-02T03:04:05Z", "2019-08-19T00:00:01Z")
-					h.AssertNil(t, err)
-					mockClient.EXPECT().
-						Build(gomock.Any(), EqBuildOptionsWithDateTime(&expectedTime)).
-						Return(nil)
+			it("passes the tags of a skipped build, even when quiet", func() {
+				logger.WantQuiet(true)
+				mockClient.EXPECT().
+					Build(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, opts client.BuildOptions) error {
+						h.AssertEq(t, opts.AdditionalTags, []string{"image:v2"})
+						h.AssertNotNil(t, opts.Summary)
+						opts.Summary.Skipped = true
+						return nil
+					})
 
-					command.SetArgs([]string{"image", "--builder", "my-builder", "--creation-time", "1566172801"})
-					h.AssertNil(t, command.Execute())
-				})
+				command.SetArgs([]string{"--builder", "my-builder", "image", "--tag", "image:v2"})
+				h.AssertNil(t, command.Execute())
+				h.AssertNotContains(t, outBuf.String(), "Successfully built image")
 			})
+		})
 
-			when("not provided", func() {
-				it("is nil", func() {
-					mockClient.EXPECT().
-						Build(gomock.Any(), EqBuildOptionsWithDateTime(nil)).
-						Return(nil)
+		when("a policy is configured", func() {
+			var policyFile string
 
-					command.SetArgs([]string{"image", "--builder", "my-builder"})
-					h.AssertNil(t, command.Execute())
-				})
+			it.Before(func() {
+				policyFile = filepath.Join(t.TempDir(), "policy.toml")
+				h.AssertNil(t, os.WriteFile(policyFile, []byte(`
+[[rules]]
+action = "deny"
+subject = "builder"
+match = "untrusted/*"
+message = "use an approved builder"
+`), 0600))
+				cfg.Policy = policyFile
+				command = commands.Build(logger, cfg, mockClient)
+			})
+
+			it("checks the plan of the build against the policy", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, opts client.BuildOptions) error {
+						h.AssertNil(t, opts.CheckPlan(client.BuildPlan{Builder: "approved/builder"}))
+						return opts.CheckPlan(client.BuildPlan{Builder: "untrusted/builder"})
+					})
+
+				command.SetArgs([]string{"image", "--builder", "untrusted/builder"})
+				h.AssertError(t, command.Execute(), "policy denies builder 'untrusted/builder': use an approved builder")
+			})
+
+			it("fails before building when the policy is invalid", func() {
+				h.AssertNil(t, os.WriteFile(policyFile, []byte(`default = "maybe"`), 0600))
+
+				command.SetArgs([]string{"image", "--builder", "my-builder"})
+				h.AssertError(t, command.Execute(), "invalid default action 'maybe'")
 			})
 		})
 
@@ -997,9 +2747,121 @@ builder = "my-builder"
 				h.AssertNil(t, err)
 			})
 		})
+
+		when("--output flag is provided", func() {
+			it("exports the image to the OCI layout of an oci output", func() {
+				outputDir := filepath.Join(paths.RootDir, "some", "output")
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithOutput("my-app", outputDir, "")).
+					Return(nil)
+
+				command.SetArgs([]string{"my-app", "--output", "oci:" + outputDir, "--builder", "my-builder"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("archives the OCI layout of the image for a tar output", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithOutput("my-app", "", "app.tar")).
+					Return(nil)
+
+				command.SetArgs([]string{"my-app", "--output", "tar:app.tar", "--builder", "my-builder"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("errors when the output is invalid", func() {
+				command.SetArgs([]string{"my-app", "--output", "docker:my-app", "--builder", "my-builder"})
+				h.AssertError(t, command.Execute(), "invalid output 'docker:my-app', must be 'oci:<dir>' or 'tar:<file>'")
+			})
+
+			it("errors when used with --publish", func() {
+				command.SetArgs([]string{"my-app", "--output", "oci:out", "--publish", "--builder", "my-builder"})
+				h.AssertError(t, command.Execute(), "output flag cannot be used with the publish flag")
+			})
+
+			it("errors when the image name is an OCI layout", func() {
+				command.SetArgs([]string{"oci:my-app", "--output", "oci:out", "--builder", "my-builder"})
+				h.AssertError(t, command.Execute(), "output flag requires an image name rather than an OCI layout")
+			})
+		})
+
+		when("--daemonless flag is provided", func() {
+			it("builds without a daemon", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithDaemonless(true)).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--daemonless", "--publish", "--builder", "my-builder"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("requires --publish", func() {
+				command.SetArgs([]string{"image", "--daemonless", "--builder", "my-builder"})
+				h.AssertError(t, command.Execute(), "daemonless flag requires the publish flag")
+			})
+		})
+
+		when("--driver flag is provided", func() {
+			it("runs the build in the cluster of the kube context and namespace", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithDriver(client.DriverKubernetes, client.KubernetesOptions{Context: "some-cluster", Namespace: "builds"})).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--driver", "kubernetes", "--kube-context", "some-cluster", "--kube-namespace", "builds", "--publish", "--builder", "my-builder"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("defaults to docker", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithDriver(client.DriverDocker, client.KubernetesOptions{})).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("errors for an unknown driver", func() {
+				command.SetArgs([]string{"image", "--driver", "nomad", "--builder", "my-builder"})
+				h.AssertError(t, command.Execute(), "invalid driver 'nomad', must be 'docker' or 'kubernetes'")
+			})
+
+			it("requires --publish for the kubernetes driver", func() {
+				command.SetArgs([]string{"image", "--driver", "kubernetes", "--builder", "my-builder"})
+				h.AssertError(t, command.Execute(), "kubernetes driver requires the publish flag")
+			})
+
+			it("errors with --daemonless", func() {
+				command.SetArgs([]string{"image", "--driver", "kubernetes", "--daemonless", "--publish", "--builder", "my-builder"})
+				h.AssertError(t, command.Execute(), "kubernetes driver cannot be used with the daemonless flag")
+			})
+
+			it("requires the kubernetes driver for --kube-namespace", func() {
+				command.SetArgs([]string{"image", "--kube-namespace", "builds", "--builder", "my-builder"})
+				h.AssertError(t, command.Execute(), "kube-context and kube-namespace flags require the kubernetes driver")
+			})
+		})
 	})
 }
 
+func EqBuildOptionsWithOutput(image, layoutDir, archivePath string) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("Image=%s, layout-dir=%s, archive=%s", image, layoutDir, archivePath),
+		equals: func(o client.BuildOptions) bool {
+			if !o.Layout() || o.Image != image || o.LayoutConfig.ArchivePath != archivePath {
+				return false
+			}
+			dir, err := o.LayoutConfig.InputImage.FullName()
+			if err != nil {
+				return false
+			}
+			if layoutDir == "" {
+				// the layout of a tar output is in a temporary directory
+				return dir != ""
+			}
+			return dir == layoutDir
+		},
+	}
+}
+
 func EqBuildOptionsWithImage(builder, image string) gomock.Matcher {
 	return buildOptionsMatcher{
 		description: fmt.Sprintf("Builder=%s and Image=%s", builder, image),
@@ -1009,6 +2871,15 @@ func EqBuildOptionsWithImage(builder, image string) gomock.Matcher {
 	}
 }
 
+func EqBuildOptionsForApp(builder, image, appPath string) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("Builder=%s and Image=%s and AppPath=%s", builder, image, appPath),
+		equals: func(o client.BuildOptions) bool {
+			return o.Builder == builder && o.Image == image && o.AppPath == appPath
+		},
+	}
+}
+
 func EqBuildOptionsDefaultProcess(defaultProc string) gomock.Matcher {
 	return buildOptionsMatcher{
 		description: fmt.Sprintf("Default Process Type=%s", defaultProc),
@@ -1027,6 +2898,53 @@ func EqBuildOptionsWithPlatform(platform string) gomock.Matcher {
 	}
 }
 
+func EqBuildOptionsWithSkipUnchanged(skip bool) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("RecordSourceDigest=true and SkipUnchanged=%t", skip),
+		equals: func(o client.BuildOptions) bool {
+			return o.RecordSourceDigest && o.SkipUnchanged == skip
+		},
+	}
+}
+
+func EqBuildOptionsWithImagePullPolicies(policies client.ImagePullPolicies) gomock.Matcher {
+	describe := func(policy *image.PullPolicy) string {
+		if policy == nil {
+			return "<nil>"
+		}
+		return policy.String()
+	}
+	equal := func(a, b *image.PullPolicy) bool {
+		return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+	}
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("ImagePullPolicies=builder:%s,run-image:%s,buildpacks:%s", describe(policies.Builder), describe(policies.RunImage), describe(policies.Buildpacks)),
+		equals: func(o client.BuildOptions) bool {
+			return equal(o.ImagePullPolicies.Builder, policies.Builder) &&
+				equal(o.ImagePullPolicies.RunImage, policies.RunImage) &&
+				equal(o.ImagePullPolicies.Buildpacks, policies.Buildpacks)
+		},
+	}
+}
+
+func EqBuildOptionsWithExtraHosts(hosts []string) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("ExtraHosts=%s", hosts),
+		equals: func(o client.BuildOptions) bool {
+			return reflect.DeepEqual(o.ContainerConfig.ExtraHosts, hosts)
+		},
+	}
+}
+
+func EqBuildOptionsWithSSHAgent(socket string) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("SSHAgent=%s", socket),
+		equals: func(o client.BuildOptions) bool {
+			return o.ContainerConfig.SSHAgent == socket
+		},
+	}
+}
+
 func EqBuildOptionsWithPullPolicy(policy image.PullPolicy) gomock.Matcher {
 	return buildOptionsMatcher{
 		description: fmt.Sprintf("PullPolicy=%s", policy),
@@ -1054,6 +2972,54 @@ func EqBuildOptionsWithCacheFlags(cacheFlags string) gomock.Matcher {
 	}
 }
 
+func EqBuildOptionsWithClearedCaches(build, launch bool, buildpacks []string) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("ClearBuildCache=%t ClearLaunchCache=%t ClearBuildpackCaches=%s", build, launch, buildpacks),
+		equals: func(o client.BuildOptions) bool {
+			return o.ClearBuildCache == build && o.ClearLaunchCache == launch && reflect.DeepEqual(o.ClearBuildpackCaches, buildpacks)
+		},
+	}
+}
+
+func EqBuildOptionsWithCacheSizeLimit(limit int64) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("CacheSizeLimit=%d", limit),
+		equals: func(o client.BuildOptions) bool {
+			return o.CacheSizeLimit == limit
+		},
+	}
+}
+
+func EqBuildOptionsWithLayerCompression(compression client.LayerCompression) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("LayerCompression=%s", compression),
+		equals: func(o client.BuildOptions) bool {
+			return o.LayerCompression == compression
+		},
+	}
+}
+
+func EqBuildOptionsWithSerialExport(serial bool) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("SerialExport=%t", serial),
+		equals: func(o client.BuildOptions) bool {
+			return o.SerialExport == serial
+		},
+	}
+}
+
+func EqBuildOptionsWithUseCreator(useCreator *bool) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("UseCreator=%v", useCreator),
+		equals: func(o client.BuildOptions) bool {
+			if useCreator == nil || o.UseCreator == nil {
+				return useCreator == o.UseCreator
+			}
+			return *o.UseCreator == *useCreator
+		},
+	}
+}
+
 func EqBuildOptionsWithLifecycleImage(lifecycleImage string) gomock.Matcher {
 	return buildOptionsMatcher{
 		description: fmt.Sprintf("LifecycleImage=%s", lifecycleImage),
@@ -1072,6 +3038,45 @@ func EqBuildOptionsWithNetwork(network string) gomock.Matcher {
 	}
 }
 
+func EqBuildOptionsWithRegistryLookup(lookup buildpack.RegistryLookup) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("RegistryLookup=%+v", lookup),
+		equals: func(o client.BuildOptions) bool {
+			return o.RegistryLookup == lookup
+		},
+	}
+}
+
+func EqBuildOptionsWithResources(memory int64, cpus float64, pidsLimit int64) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("Memory=%d CPUs=%g PidsLimit=%d", memory, cpus, pidsLimit),
+		equals: func(o client.BuildOptions) bool {
+			return o.ContainerConfig.Memory == memory && o.ContainerConfig.CPUs == cpus && o.ContainerConfig.PidsLimit == pidsLimit
+		},
+	}
+}
+
+func EqBuildOptionsWithHardening(readOnly bool, capAdd, capDrop, securityOpt []string) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("ReadOnly=%t CapAdd=%s CapDrop=%s SecurityOpt=%s", readOnly, capAdd, capDrop, securityOpt),
+		equals: func(o client.BuildOptions) bool {
+			return o.ContainerConfig.ReadOnly == readOnly &&
+				reflect.DeepEqual(o.ContainerConfig.CapAdd, capAdd) &&
+				reflect.DeepEqual(o.ContainerConfig.CapDrop, capDrop) &&
+				reflect.DeepEqual(o.ContainerConfig.SecurityOpt, securityOpt)
+		},
+	}
+}
+
+func EqBuildOptionsWithUsernsRemap(remap *bool) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("UsernsRemap=%v", remap),
+		equals: func(o client.BuildOptions) bool {
+			return reflect.DeepEqual(o.ContainerConfig.UsernsRemap, remap)
+		},
+	}
+}
+
 func EqBuildOptionsWithBuilder(builder string) gomock.Matcher {
 	return buildOptionsMatcher{
 		description: fmt.Sprintf("Builder=%s", builder),
@@ -1108,9 +3113,18 @@ func EqBuildOptionsWithAdditionalTags(additionalTags []string) gomock.Matcher {
 	}
 }
 
+func EqBuildOptionsWithProjectDescriptorEnv(env []projectTypes.EnvVar) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("ProjectDescriptor.Build.Env=%s", env),
+		equals: func(o client.BuildOptions) bool {
+			return reflect.DeepEqual(o.ProjectDescriptor.Build.Env, env)
+		},
+	}
+}
+
 func EqBuildOptionsWithProjectDescriptor(descriptor projectTypes.Descriptor) gomock.Matcher {
 	return buildOptionsMatcher{
-		description: fmt.Sprintf("Descriptor=%s", descriptor),
+		description: fmt.Sprintf("Descriptor=%v", descriptor),
 		equals: func(o client.BuildOptions) bool {
 			return reflect.DeepEqual(o.ProjectDescriptor, descriptor)
 		},
@@ -1136,6 +3150,52 @@ func EqBuildOptionsWithEnv(env map[string]string) gomock.Matcher {
 	}
 }
 
+func EqBuildOptionsWithDetectOnly(detectOnly bool) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("DetectOnly=%t", detectOnly),
+		equals: func(o client.BuildOptions) bool {
+			return o.DetectOnly == detectOnly
+		},
+	}
+}
+
+func EqBuildOptionsWithLifecycleLogLevel(level string) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("LifecycleLogLevel=%s", level),
+		equals: func(o client.BuildOptions) bool {
+			return o.LifecycleLogLevel == level
+		},
+	}
+}
+
+func EqBuildOptionsWithAppFile(file, contents string) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("AppPath with %s=%s", file, contents),
+		equals: func(o client.BuildOptions) bool {
+			actual, err := os.ReadFile(filepath.Join(o.AppPath, file))
+			return err == nil && string(actual) == contents
+		},
+	}
+}
+
+func EqBuildOptionsWithPushRetries(retries int) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("PushRetries=%d", retries),
+		equals: func(o client.BuildOptions) bool {
+			return o.PushRetries == retries
+		},
+	}
+}
+
+func EqBuildOptionsWithLifecycleEnv(env map[string]string) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("LifecycleEnv=%+v", env),
+		equals: func(o client.BuildOptions) bool {
+			return reflect.DeepEqual(o.LifecycleEnv, env)
+		},
+	}
+}
+
 func EqBuildOptionsWithOverrideGroupID(gid int) gomock.Matcher {
 	return buildOptionsMatcher{
 		description: fmt.Sprintf("GID=%d", gid),
@@ -1154,6 +3214,24 @@ func EqBuildOptionsWithPreviousImage(prevImage string) gomock.Matcher {
 	}
 }
 
+func EqBuildOptionsWithDaemonless(daemonless bool) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("Daemonless=%t", daemonless),
+		equals: func(o client.BuildOptions) bool {
+			return o.Daemonless == daemonless
+		},
+	}
+}
+
+func EqBuildOptionsWithDriver(driver string, kubernetes client.KubernetesOptions) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("Driver=%s Kubernetes=%+v", driver, kubernetes),
+		equals: func(o client.BuildOptions) bool {
+			return o.Driver == driver && o.Kubernetes == kubernetes
+		},
+	}
+}
+
 func EqBuildOptionsWithSBOMOutputDir(s string) interface{} {
 	return buildOptionsMatcher{
 		description: fmt.Sprintf("sbom-destination-dir=%s", s),
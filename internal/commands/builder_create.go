@@ -9,6 +9,7 @@ import (
 
 	"github.com/buildpacks/pack/builder"
 	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/i18n"
 	"github.com/buildpacks/pack/internal/style"
 	"github.com/buildpacks/pack/pkg/buildpack"
 	"github.com/buildpacks/pack/pkg/client"
@@ -21,10 +22,12 @@ type BuilderCreateFlags struct {
 	Publish         bool
 	BuilderTomlPath string
 	Registry        string
+	RegistryLookup  buildpack.RegistryLookup
 	Policy          string
 	Flatten         []string
 	Targets         []string
 	Label           map[string]string
+	Compression     string
 }
 
 // CreateBuilder creates a builder image, based on a builder config
@@ -88,6 +91,11 @@ Creating a custom builder allows you to control what buildpacks are used and wha
 				return err
 			}
 
+			compression, err := parseCompressionFlag(flags.Compression, flags.Publish)
+			if err != nil {
+				return err
+			}
+
 			multiArchCfg, err := processMultiArchitectureConfig(logger, flags.Targets, builderConfig.Targets, !flags.Publish)
 			if err != nil {
 				return err
@@ -99,26 +107,29 @@ Creating a custom builder allows you to control what buildpacks are used and wha
 
 			imageName := args[0]
 			if err := pack.CreateBuilder(cmd.Context(), client.CreateBuilderOptions{
-				RelativeBaseDir: relativeBaseDir,
-				BuildConfigEnv:  envMap,
-				BuilderName:     imageName,
-				Config:          builderConfig,
-				Publish:         flags.Publish,
-				Registry:        flags.Registry,
-				PullPolicy:      pullPolicy,
-				Flatten:         toFlatten,
-				Labels:          flags.Label,
-				Targets:         multiArchCfg.Targets(),
+				RelativeBaseDir:  relativeBaseDir,
+				BuildConfigEnv:   envMap,
+				BuilderName:      imageName,
+				Config:           builderConfig,
+				Publish:          flags.Publish,
+				Registry:         flags.Registry,
+				RegistryLookup:   flags.RegistryLookup,
+				PullPolicy:       pullPolicy,
+				Flatten:          toFlatten,
+				LayerCompression: compression,
+				Labels:           flags.Label,
+				Targets:          multiArchCfg.Targets(),
 			}); err != nil {
 				return err
 			}
-			logger.Infof("Successfully created builder image %s", style.Symbol(imageName))
+			logger.Info(i18n.T(i18n.BuilderCreateSuccess, style.Symbol(imageName)))
 			logging.Tip(logger, "Run %s to use this builder", style.Symbol(fmt.Sprintf("pack build <image-name> --builder %s", imageName)))
 			return nil
 		}),
 	}
 
 	cmd.Flags().StringVarP(&flags.Registry, "buildpack-registry", "R", cfg.DefaultRegistryName, "Buildpack Registry by name")
+	addRegistryLookupFlags(cmd, &flags.RegistryLookup)
 	if !cfg.Experimental {
 		cmd.Flags().MarkHidden("buildpack-registry")
 	}
@@ -127,11 +138,14 @@ Creating a custom builder allows you to control what buildpacks are used and wha
 	cmd.Flags().StringVar(&flags.Policy, "pull-policy", "", "Pull policy to use. Accepted values are always, never, and if-not-present. The default is always")
 	cmd.Flags().StringArrayVar(&flags.Flatten, "flatten", nil, "List of buildpacks to flatten together into a single layer (format: '<buildpack-id>@<buildpack-version>,<buildpack-id>@<buildpack-version>'")
 	cmd.Flags().StringToStringVarP(&flags.Label, "label", "l", nil, "Labels to add to the builder image, in the form of '<name>=<value>'")
+	cmd.Flags().StringVar(&flags.Compression, "compression", string(client.GzipCompression), "Compression of the layers of the published builder, 'gzip' or 'zstd'. zstd layers decompress faster, the builder keeps gzip layers when the registry rejects them. Requires --publish for zstd")
 	cmd.Flags().StringSliceVarP(&flags.Targets, "target", "t", nil,
 		`Target platforms to build for.\nTargets should be in the format '[os][/arch][/variant]:[distroname@osversion@anotherversion];[distroname@osversion]'.
 - To specify two different architectures:  '--target "linux/amd64" --target "linux/arm64"'
 - To specify the distribution version: '--target "linux/arm/v6:ubuntu@14.04"'
 - To specify multiple distribution versions: '--target "linux/arm/v6:ubuntu@14.04"  --target "linux/arm/v6:ubuntu@16.04"'
+With --publish, a builder is created for each target and <image-name> is an image index of them.
+The build image and buildpacks of an architecture can be set in [[architectures]] of the builder TOML file.
 	`)
 
 	AddHelpFlag(cmd, "create")
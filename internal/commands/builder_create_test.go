@@ -223,6 +223,33 @@ func testCreateCommand(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("--compression", func() {
+			it.Before(func() {
+				h.AssertNil(t, os.WriteFile(builderConfigPath, []byte(validConfig), 0666))
+			})
+
+			it("compresses the layers of the published builder with zstd", func() {
+				mockClient.EXPECT().CreateBuilder(gomock.Any(), EqCreateBuilderOptionsLayerCompression(client.ZstdCompression)).Return(nil)
+
+				command.SetArgs([]string{
+					"some/builder",
+					"--config", builderConfigPath,
+					"--publish",
+					"--compression", "zstd",
+				})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("errors for zstd without publishing", func() {
+				command.SetArgs([]string{
+					"some/builder",
+					"--config", builderConfigPath,
+					"--compression", "zstd",
+				})
+				h.AssertError(t, command.Execute(), "zstd compression requires the publish flag")
+			})
+		})
+
 		when("--pull-policy", func() {
 			it("returns error for unknown policy", func() {
 				command.SetArgs([]string{
@@ -570,6 +597,15 @@ func EqCreateBuilderOptionsTargets(targets []dist.Target) gomock.Matcher {
 	}
 }
 
+func EqCreateBuilderOptionsLayerCompression(compression client.LayerCompression) gomock.Matcher {
+	return createbuilderOptionsMatcher{
+		description: fmt.Sprintf("LayerCompression=%s", compression),
+		equals: func(o client.CreateBuilderOptions) bool {
+			return o.LayerCompression == compression
+		},
+	}
+}
+
 type createbuilderOptionsMatcher struct {
 	equals      func(options client.CreateBuilderOptions) bool
 	description string
@@ -19,6 +19,7 @@ import (
 	"github.com/buildpacks/pack/internal/commands/fakes"
 	"github.com/buildpacks/pack/internal/config"
 	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/errcode"
 	"github.com/buildpacks/pack/pkg/logging"
 	h "github.com/buildpacks/pack/testhelpers"
 )
@@ -274,6 +275,34 @@ func testBuilderInspectCommand(t *testing.T, when spec.G, it spec.S) {
 				err := command.Execute()
 				assert.ErrorWithMessage(err, "couldn't write builder")
 			})
+
+			when("error format is set to json", func() {
+				it("writes the error as a json block with its error code", func() {
+					baseError := errcode.Wrap(errcode.BuilderFetchFailed, errors.New("couldn't fetch builder"))
+
+					var errBuf bytes.Buffer
+					builderWriter := newBuilderWriter(errorsForPrint(baseError))
+					command := commands.BuilderInspect(
+						logging.NewLogWithWriters(&outBuf, &errBuf),
+						cfg,
+						newDefaultBuilderInspector(),
+						newWriterFactory(returnsForWriter(builderWriter)),
+					)
+					commands.AddErrorFormatFlag(command)
+					command.SetArgs([]string{"--output", "json", "--error-format", "json"})
+
+					err := command.Execute()
+					assert.ErrorWithMessage(err, "couldn't fetch builder")
+					assert.EqualJSON(errBuf.String(), `{
+  "error": {
+    "code": "PACK1003",
+    "name": "builder-fetch-failed",
+    "message": "couldn't fetch builder",
+    "exitCode": 10
+  }
+}`)
+				})
+			})
 		})
 
 		when("writer factory returns an error", func() {
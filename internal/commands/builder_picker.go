@@ -0,0 +1,96 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+
+	bldr "github.com/buildpacks/pack/internal/builder"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/i18n"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+type builderChoice struct {
+	vendor      string
+	image       string
+	description string
+}
+
+// builderChoices returns the suggested builders followed by any trusted builders from the config
+func builderChoices(cfg config.Config) []builderChoice {
+	var suggested []bldr.KnownBuilder
+	for _, knownBuilder := range bldr.KnownBuilders {
+		if knownBuilder.Suggested {
+			suggested = append(suggested, knownBuilder)
+		}
+	}
+	sort.Slice(suggested, func(i, j int) bool {
+		if suggested[i].Vendor == suggested[j].Vendor {
+			return suggested[i].Image < suggested[j].Image
+		}
+		return suggested[i].Vendor < suggested[j].Vendor
+	})
+
+	seen := map[string]bool{}
+	var choices []builderChoice
+	for _, knownBuilder := range suggested {
+		seen[knownBuilder.Image] = true
+		choices = append(choices, builderChoice{vendor: knownBuilder.Vendor, image: knownBuilder.Image, description: knownBuilder.DefaultDescription})
+	}
+	for _, trustedBuilder := range cfg.TrustedBuilders {
+		if seen[trustedBuilder.Name] {
+			continue
+		}
+		seen[trustedBuilder.Name] = true
+		choices = append(choices, builderChoice{vendor: "Trusted", image: trustedBuilder.Name})
+	}
+	return choices
+}
+
+// pickBuilder asks the user to choose a builder from the suggested and trusted builders.
+// It returns an empty string when the user declines to choose one.
+func pickBuilder(logger logging.Logger, cfg config.Config, in io.Reader) (string, error) {
+	choices := builderChoices(cfg)
+	if len(choices) == 0 {
+		return "", nil
+	}
+
+	logger.Info(i18n.T(i18n.BuilderPickerIntro))
+	logger.Info("")
+	tw := tabwriter.NewWriter(logger.Writer(), 10, 10, 5, ' ', tabwriter.TabIndent)
+	for i, choice := range choices {
+		fmt.Fprintf(tw, "\t%d)\t%s:\t%s\t%s\t\n", i+1, choice.vendor, style.Symbol(choice.image), choice.description)
+	}
+	fmt.Fprintln(tw)
+	tw.Flush()
+
+	fmt.Fprint(logger.Writer(), i18n.T(i18n.BuilderPickerPrompt, len(choices)))
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", errors.Wrap(err, "reading builder selection")
+	}
+
+	line = strings.TrimSpace(line)
+	if line == "" {
+		logger.Info("")
+		return "", nil
+	}
+
+	index, err := strconv.Atoi(line)
+	if err != nil || index < 1 || index > len(choices) {
+		return "", errors.New(i18n.T(i18n.BuilderPickerInvalid, style.Symbol(line), len(choices)))
+	}
+
+	builder := choices[index-1].image
+	logger.Info(i18n.T(i18n.BuilderPickerUsing, style.Symbol(builder)))
+	logging.Tip(logger, "%s", i18n.T(i18n.BuilderPickerDefaultTip, builder))
+	return builder, nil
+}
@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestBuilderPicker(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "BuilderPicker", testBuilderPicker, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testBuilderPicker(t *testing.T, when spec.G, it spec.S) {
+	var (
+		logger logging.Logger
+		outBuf bytes.Buffer
+		cfg    config.Config
+	)
+
+	it.Before(func() {
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		cfg = config.Config{
+			TrustedBuilders: []config.TrustedBuilder{
+				{Name: "some/trusted-builder"},
+				{Name: "heroku/builder:24"},
+			},
+		}
+	})
+
+	when("#pickBuilder", func() {
+		it("lists the suggested builders followed by the trusted builders", func() {
+			_, err := pickBuilder(logger, cfg, strings.NewReader("\n"))
+			h.AssertNil(t, err)
+
+			choices := builderChoices(cfg)
+			h.AssertEq(t, choices[len(choices)-1].image, "some/trusted-builder")
+			h.AssertContains(t, outBuf.String(), "'heroku/builder:24'")
+			h.AssertContains(t, outBuf.String(), "Trusted:")
+			h.AssertEq(t, strings.Count(outBuf.String(), "'heroku/builder:24'"), 1)
+		})
+
+		it("returns the selected builder", func() {
+			choices := builderChoices(cfg)
+
+			builder, err := pickBuilder(logger, cfg, strings.NewReader("2\n"))
+			h.AssertNil(t, err)
+			h.AssertEq(t, builder, choices[1].image)
+			h.AssertContains(t, outBuf.String(), "pack config default-builder "+choices[1].image)
+		})
+
+		it("returns no builder when the selection is skipped", func() {
+			builder, err := pickBuilder(logger, cfg, strings.NewReader("\n"))
+			h.AssertNil(t, err)
+			h.AssertEq(t, builder, "")
+		})
+
+		it("returns no builder when the input is closed", func() {
+			builder, err := pickBuilder(logger, cfg, strings.NewReader(""))
+			h.AssertNil(t, err)
+			h.AssertEq(t, builder, "")
+		})
+
+		it("errors when the selection is out of range", func() {
+			_, err := pickBuilder(logger, cfg, strings.NewReader("99\n"))
+			h.AssertError(t, err, "invalid builder selection '99'")
+		})
+	})
+}
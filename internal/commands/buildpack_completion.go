@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/registry"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+// registryURNPrefix prefixes buildpacks of a registry, e.g. urn:cnb:registry:example/java@1.0.0
+const registryURNPrefix = "urn:cnb:registry:"
+
+// completeRegistryBuildpacks completes the buildpacks and versions of the registry selected with --buildpack-registry.
+// Only the local cache of the registry is read, so that completion is fast and works offline. Values that aren't
+// registry URNs complete as file paths.
+func completeRegistryBuildpacks(cfg config.Config) func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		// values of slice flags are separated by commas, only the last one is completed
+		var previous string
+		if i := strings.LastIndex(toComplete, ","); i >= 0 {
+			previous, toComplete = toComplete[:i+1], toComplete[i+1:]
+		}
+
+		if !strings.HasPrefix(toComplete, registryURNPrefix) {
+			if toComplete != "" && strings.HasPrefix(registryURNPrefix, toComplete) {
+				return []string{previous + registryURNPrefix}, cobra.ShellCompDirectiveNoSpace
+			}
+			return nil, cobra.ShellCompDirectiveDefault
+		}
+
+		registryName, _ := cmd.Flags().GetString("buildpack-registry")
+		cache, err := localRegistryCache(cfg, registryName)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		var candidates []string
+		query := strings.TrimPrefix(toComplete, registryURNPrefix)
+		if id, version, ok := strings.Cut(query, "@"); ok {
+			versions, _ := cache.Versions(id)
+			for _, candidate := range versions {
+				if strings.HasPrefix(candidate, version) {
+					candidates = append(candidates, previous+registryURNPrefix+id+"@"+candidate)
+				}
+			}
+		} else {
+			ids, _ := cache.IDs()
+			for _, id := range ids {
+				if strings.HasPrefix(id, query) {
+					candidates = append(candidates, previous+registryURNPrefix+id)
+				}
+			}
+		}
+		return candidates, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func localRegistryCache(cfg config.Config, registryName string) (registry.Cache, error) {
+	reg, err := config.GetRegistry(cfg, registryName)
+	if err != nil {
+		return nil, err
+	}
+
+	home, err := config.PackHome()
+	if err != nil {
+		return nil, err
+	}
+	return registry.NewCache(logging.NewSimpleLogger(io.Discard), home, reg.URL)
+}
@@ -7,14 +7,16 @@ import (
 
 	"github.com/buildpacks/pack/internal/config"
 	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/buildpack"
 	"github.com/buildpacks/pack/pkg/client"
 	"github.com/buildpacks/pack/pkg/logging"
 )
 
 type BuildpackInspectFlags struct {
-	Depth    int
-	Registry string
-	Verbose  bool
+	Depth          int
+	Registry       string
+	RegistryLookup buildpack.RegistryLookup
+	Verbose        bool
 }
 
 func BuildpackInspect(logger logging.Logger, cfg config.Config, client PackClient) *cobra.Command {
@@ -37,6 +39,7 @@ func BuildpackInspect(logger logging.Logger, cfg config.Config, client PackClien
 
 	cmd.Flags().IntVarP(&flags.Depth, "depth", "d", -1, "Max depth to display for Detection Order.\nOmission of this flag or values < 0 will display the entire tree.")
 	cmd.Flags().StringVarP(&flags.Registry, "registry", "r", "", "buildpack registry that may be searched")
+	addRegistryLookupFlags(cmd, &flags.RegistryLookup)
 	cmd.Flags().BoolVarP(&flags.Verbose, "verbose", "v", false, "show more output")
 	AddHelpFlag(cmd, "inspect")
 	return cmd
@@ -49,14 +52,16 @@ func buildpackInspect(logger logging.Logger, buildpackName, registryName string,
 		pack,
 		flags,
 		client.InspectBuildpackOptions{
-			BuildpackName: buildpackName,
-			Daemon:        true,
-			Registry:      registryName,
+			BuildpackName:  buildpackName,
+			Daemon:         true,
+			Registry:       registryName,
+			RegistryLookup: flags.RegistryLookup,
 		},
 		client.InspectBuildpackOptions{
-			BuildpackName: buildpackName,
-			Daemon:        false,
-			Registry:      registryName,
+			BuildpackName:  buildpackName,
+			Daemon:         false,
+			Registry:       registryName,
+			RegistryLookup: flags.RegistryLookup,
 		})
 	if err != nil {
 		return fmt.Errorf("error writing buildpack output: %q", err)
@@ -514,6 +514,39 @@ func testBuildpackInspectCommand(t *testing.T, when spec.G, it spec.S) {
 				simpleInfo.Location = buildpack.RegistryLocator
 			})
 
+			when("the registry entry describes the buildpack", func() {
+				it.Before(func() {
+					simpleInfo.Registry = &client.RegistryBuildpackInfo{
+						Address:     "example.com/some/package@sha256:8c27fe111c11b722081701dfed3bd55e039b9ce92865473cf4cdfa918071c566",
+						Description: "Builds some applications",
+						Licenses:    []string{"Apache-2.0", "MIT"},
+					}
+					mockClient.EXPECT().InspectBuildpack(client.InspectBuildpackOptions{
+						BuildpackName: "urn:cnb:registry:test/buildpack",
+						Daemon:        true,
+						Registry:      "default-registry",
+					}).Return(simpleInfo, nil)
+				})
+
+				it("shows the registry entry", func() {
+					command.SetArgs([]string{"urn:cnb:registry:test/buildpack"})
+					assert.Nil(command.Execute())
+
+					expectedOutput := fmt.Sprintf(inspectOutputTemplate,
+						"urn:cnb:registry:test/buildpack",
+						`REGISTRY IMAGE:
+
+Registry Entry:
+  Address: example.com/some/package@sha256:8c27fe111c11b722081701dfed3bd55e039b9ce92865473cf4cdfa918071c566
+  Description: Builds some applications
+  Licenses: Apache-2.0, MIT
+  Stacks: (none)`,
+						simpleOutputSection)
+
+					assert.TrimmedEq(outBuf.String(), expectedOutput)
+				})
+			})
+
 			when("using the default registry", func() {
 				it.Before(func() {
 					mockClient.EXPECT().InspectBuildpack(client.InspectBuildpackOptions{
@@ -12,6 +12,7 @@ import (
 	pubbldpkg "github.com/buildpacks/pack/buildpackage"
 	"github.com/buildpacks/pack/internal/config"
 	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/buildpack"
 	"github.com/buildpacks/pack/pkg/client"
 	"github.com/buildpacks/pack/pkg/dist"
 	"github.com/buildpacks/pack/pkg/image"
@@ -24,12 +25,14 @@ type BuildpackPackageFlags struct {
 	Format            string
 	Policy            string
 	BuildpackRegistry string
+	RegistryLookup    buildpack.RegistryLookup
 	Path              string
 	FlattenExclude    []string
 	Targets           []string
 	Label             map[string]string
 	Publish           bool
 	Flatten           bool
+	Compression       string
 }
 
 // BuildpackPackager packages buildpacks
@@ -109,6 +112,11 @@ func BuildpackPackage(logger logging.Logger, cfg config.Config, packager Buildpa
 				return err
 			}
 
+			compression, err := parseCompressionFlag(flags.Compression, flags.Publish)
+			if err != nil {
+				return err
+			}
+
 			daemon := !flags.Publish && flags.Format == ""
 			multiArchCfg, err := processMultiArchitectureConfig(logger, flags.Targets, targets, daemon)
 			if err != nil {
@@ -131,17 +139,19 @@ func BuildpackPackage(logger logging.Logger, cfg config.Config, packager Buildpa
 			}
 
 			if err := packager.PackageBuildpack(cmd.Context(), client.PackageBuildpackOptions{
-				RelativeBaseDir: relativeBaseDir,
-				Name:            name,
-				Format:          flags.Format,
-				Config:          bpPackageCfg,
-				Publish:         flags.Publish,
-				PullPolicy:      pullPolicy,
-				Registry:        flags.BuildpackRegistry,
-				Flatten:         flags.Flatten,
-				FlattenExclude:  flags.FlattenExclude,
-				Labels:          flags.Label,
-				Targets:         multiArchCfg.Targets(),
+				RelativeBaseDir:  relativeBaseDir,
+				Name:             name,
+				Format:           flags.Format,
+				Config:           bpPackageCfg,
+				Publish:          flags.Publish,
+				PullPolicy:       pullPolicy,
+				Registry:         flags.BuildpackRegistry,
+				RegistryLookup:   flags.RegistryLookup,
+				Flatten:          flags.Flatten,
+				FlattenExclude:   flags.FlattenExclude,
+				Labels:           flags.Label,
+				Targets:          multiArchCfg.Targets(),
+				LayerCompression: compression,
 			}); err != nil {
 				return err
 			}
@@ -156,6 +166,7 @@ func BuildpackPackage(logger logging.Logger, cfg config.Config, packager Buildpa
 				location = "file"
 			}
 			logger.Infof("Successfully %s package %s and saved to %s", action, style.Symbol(name), location)
+			logQuietReference(logger, name)
 			return nil
 		}),
 	}
@@ -164,8 +175,10 @@ func BuildpackPackage(logger logging.Logger, cfg config.Config, packager Buildpa
 	cmd.Flags().StringVarP(&flags.Format, "format", "f", "", `Format to save package as ("image" or "file")`)
 	cmd.Flags().BoolVar(&flags.Publish, "publish", false, `Publish the buildpack directly to the container registry specified in <name>, instead of the daemon (applies to "--format=image" only).`)
 	cmd.Flags().StringVar(&flags.Policy, "pull-policy", "", "Pull policy to use. Accepted values are always, never, and if-not-present. The default is always")
+	cmd.Flags().StringVar(&flags.Compression, "compression", string(client.GzipCompression), "Compression of the layers of the published buildpack, 'gzip' or 'zstd'. zstd layers decompress faster, the buildpack keeps gzip layers when the registry rejects them. Requires --publish for zstd")
 	cmd.Flags().StringVarP(&flags.Path, "path", "p", "", "Path to the Buildpack that needs to be packaged")
 	cmd.Flags().StringVarP(&flags.BuildpackRegistry, "buildpack-registry", "r", "", "Buildpack Registry name")
+	addRegistryLookupFlags(cmd, &flags.RegistryLookup)
 	cmd.Flags().BoolVar(&flags.Flatten, "flatten", false, "Flatten the buildpack into a single layer")
 	cmd.Flags().StringSliceVarP(&flags.FlattenExclude, "flatten-exclude", "e", nil, "Buildpacks to exclude from flattening, in the form of '<buildpack-id>@<buildpack-version>'")
 	cmd.Flags().StringToStringVarP(&flags.Label, "label", "l", nil, "Labels to add to packaged Buildpack, in the form of '<name>=<value>'")
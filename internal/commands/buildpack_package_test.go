@@ -16,6 +16,7 @@ import (
 	"github.com/buildpacks/pack/internal/commands"
 	"github.com/buildpacks/pack/internal/commands/fakes"
 	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/client"
 	"github.com/buildpacks/pack/pkg/dist"
 	"github.com/buildpacks/pack/pkg/image"
 	"github.com/buildpacks/pack/pkg/logging"
@@ -88,6 +89,22 @@ func testPackageCommand(t *testing.T, when spec.G, it spec.S) {
 				h.AssertEq(t, receivedOptions.Config, myConfig)
 			})
 
+			when("quiet mode", func() {
+				it("only prints the package reference", func() {
+					var quietOut bytes.Buffer
+					quietLogger := logging.NewLogWithWriters(&quietOut, &quietOut)
+					quietLogger.WantQuiet(true)
+
+					cmd := packageCommand(
+						withImageName("my-quiet-image"),
+						withBuildpackPackager(fakeBuildpackPackager),
+						withLogger(quietLogger),
+					)
+					h.AssertNil(t, cmd.Execute())
+					h.AssertEq(t, quietOut.String(), "my-quiet-image\n")
+				})
+			})
+
 			when("file format", func() {
 				when("extension is .cnb", func() {
 					it("does not modify the name", func() {
@@ -178,6 +195,23 @@ func testPackageCommand(t *testing.T, when spec.G, it spec.S) {
 				})
 			})
 
+			when("--compression", func() {
+				it("compresses the layers of the published package with zstd", func() {
+					cmd := packageCommand(withBuildpackPackager(fakeBuildpackPackager))
+					cmd.SetArgs([]string{"some-image-name", "--config", "/path/to/some/file", "--publish", "--compression", "zstd"})
+					h.AssertNil(t, cmd.Execute())
+
+					receivedOptions := fakeBuildpackPackager.CreateCalledWithOptions
+					h.AssertEq(t, receivedOptions.LayerCompression, client.ZstdCompression)
+				})
+
+				it("errors for zstd without publishing", func() {
+					cmd := packageCommand(withBuildpackPackager(fakeBuildpackPackager))
+					cmd.SetArgs([]string{"some-image-name", "--config", "/path/to/some/file", "--compression", "zstd"})
+					h.AssertError(t, cmd.Execute(), "zstd compression requires the publish flag")
+				})
+			})
+
 			when("pull-policy", func() {
 				var pullPolicyArgs = []string{
 					"some-image-name",
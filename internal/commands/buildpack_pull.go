@@ -5,6 +5,7 @@ import (
 
 	"github.com/buildpacks/pack/internal/config"
 	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/buildpack"
 	"github.com/buildpacks/pack/pkg/client"
 	"github.com/buildpacks/pack/pkg/logging"
 )
@@ -13,6 +14,9 @@ import (
 type BuildpackPullFlags struct {
 	// BuildpackRegistry is the name of the buildpack registry to use to search for
 	BuildpackRegistry string
+
+	// RegistryLookup configures how the buildpack is located in the registry
+	RegistryLookup buildpack.RegistryLookup
 }
 
 // BuildpackPull pulls a buildpack and stores it locally
@@ -31,8 +35,9 @@ func BuildpackPull(logger logging.Logger, cfg config.Config, pack PackClient) *c
 			}
 
 			opts := client.PullBuildpackOptions{
-				URI:          args[0],
-				RegistryName: registry.Name,
+				URI:            args[0],
+				RegistryName:   registry.Name,
+				RegistryLookup: flags.RegistryLookup,
 			}
 
 			if err := pack.PullBuildpack(cmd.Context(), opts); err != nil {
@@ -43,6 +48,7 @@ func BuildpackPull(logger logging.Logger, cfg config.Config, pack PackClient) *c
 		}),
 	}
 	cmd.Flags().StringVarP(&flags.BuildpackRegistry, "buildpack-registry", "r", "", "Buildpack Registry name")
+	addRegistryLookupFlags(cmd, &flags.RegistryLookup)
 	AddHelpFlag(cmd, "pull")
 	return cmd
 }
@@ -1,16 +1,19 @@
 package commands
 
 import (
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"github.com/buildpacks/pack/internal/config"
 	"github.com/buildpacks/pack/internal/style"
 	"github.com/buildpacks/pack/pkg/client"
 	"github.com/buildpacks/pack/pkg/logging"
+	registrytypes "github.com/buildpacks/pack/registry"
 )
 
 type BuildpackRegisterFlags struct {
 	BuildpackRegistry string
+	PullRequest       bool
 }
 
 func BuildpackRegister(logger logging.Logger, cfg config.Config, pack PackClient) *cobra.Command {
@@ -22,15 +25,25 @@ func BuildpackRegister(logger logging.Logger, cfg config.Config, pack PackClient
 		Args:    cobra.ExactArgs(1),
 		Short:   "Register a buildpack to a registry",
 		Example: "pack buildpack register my-buildpack",
+		Long: "Register a buildpack to a registry.\n\n" +
+			"For `github` registries, the form of an issue registering the buildpack is opened in the browser, unless " +
+			"`--pull-request` is set: pack then validates the metadata of the buildpack and opens a pull request adding it " +
+			"to the index of the registry through the GitHub API, from a fork of the registry when the token can't push to it. " +
+			"The token is read from the PACK_REGISTRY_TOKEN_<NAME> environment variable of the registry, the token of the registry in the config, " +
+			"or GITHUB_TOKEN. The official registry is public and never sent a token from its PACK_REGISTRY_TOKEN_OFFICIAL variable.",
 		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
 			registry, err := config.GetRegistry(cfg, flags.BuildpackRegistry)
 			if err != nil {
 				return err
 			}
+			if flags.PullRequest && registry.Type != registrytypes.TypeGitHub {
+				return errors.Errorf("%s requires a registry of type %s, %s is of type %s", style.Symbol("--pull-request"), style.Symbol(registrytypes.TypeGitHub), style.Symbol(registry.Name), style.Symbol(registry.Type))
+			}
 			opts.ImageName = args[0]
 			opts.Type = registry.Type
 			opts.URL = registry.URL
 			opts.Name = registry.Name
+			opts.PullRequest = flags.PullRequest
 
 			if err := pack.RegisterBuildpack(cmd.Context(), opts); err != nil {
 				return err
@@ -40,6 +53,7 @@ func BuildpackRegister(logger logging.Logger, cfg config.Config, pack PackClient
 		}),
 	}
 	cmd.Flags().StringVarP(&flags.BuildpackRegistry, "buildpack-registry", "r", "", "Buildpack Registry name")
+	cmd.Flags().BoolVar(&flags.PullRequest, "pull-request", false, "Open a pull request adding the buildpack to the index of a github registry")
 	AddHelpFlag(cmd, "register")
 	return cmd
 }
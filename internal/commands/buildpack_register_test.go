@@ -8,6 +8,7 @@ import (
 	"github.com/buildpacks/pack/pkg/client"
 
 	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
 	"github.com/sclevine/spec"
 	"github.com/sclevine/spec/report"
 	"github.com/spf13/cobra"
@@ -19,6 +20,8 @@ import (
 )
 
 func TestRegisterCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
 	spec.Run(t, "RegisterCommand", testRegisterCommand, spec.Parallel(), spec.Report(report.Terminal{}))
 }
 
@@ -141,6 +144,40 @@ func testRegisterCommand(t *testing.T, when spec.G, it spec.S) {
 				cmd.SetArgs([]string{buildpackImage, "--buildpack-registry", buildpackRegistry})
 				h.AssertNil(t, cmd.Execute())
 			})
+
+			when("--pull-request", func() {
+				it("opens a pull request against a github registry", func() {
+					opts := client.RegisterBuildpackOptions{
+						ImageName:   buildpackImage,
+						Type:        "github",
+						URL:         "https://github.com/buildpacks/registry-index",
+						Name:        "official",
+						PullRequest: true,
+					}
+					mockClient.EXPECT().
+						RegisterBuildpack(gomock.Any(), opts).
+						Return(nil)
+
+					cmd.SetArgs([]string{buildpackImage, "--pull-request"})
+					h.AssertNil(t, cmd.Execute())
+				})
+
+				it("fails for a git registry", func() {
+					cfg = config.Config{
+						Registries: []config.Registry{
+							{
+								Name: "private",
+								Type: "git",
+								URL:  "https://github.com/private/buildpack-registry",
+							},
+						},
+					}
+					cmd = commands.BuildpackRegister(logger, cfg, mockClient)
+					cmd.SetArgs([]string{buildpackImage, "--buildpack-registry", "private", "--pull-request"})
+
+					h.AssertError(t, cmd.Execute(), "requires a registry of type 'github', 'private' is of type 'git'")
+				})
+			})
 		})
 	})
 }
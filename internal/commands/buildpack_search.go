@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/buildpack"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+// BuildpackSearchFlags consist of flags applicable to the `buildpack search` command
+type BuildpackSearchFlags struct {
+	// BuildpackRegistry is the name of the buildpack registry to search
+	BuildpackRegistry string
+
+	// RegistryLookup configures how the buildpacks are located in the registry
+	RegistryLookup buildpack.RegistryLookup
+
+	// OutputFormat is either table or json
+	OutputFormat string
+}
+
+// BuildpackSearch searches a buildpack registry for buildpacks
+func BuildpackSearch(logger logging.Logger, cfg config.Config, pack PackClient) *cobra.Command {
+	var flags BuildpackSearchFlags
+
+	cmd := &cobra.Command{
+		Use:   "search <term>",
+		Args:  cobra.ExactArgs(1),
+		Short: "Search a buildpack registry for buildpacks",
+		Long: "Search a buildpack registry for the buildpacks whose id, i.e. <namespace>/<name>, contains the term, " +
+			"and show them at their latest version that wasn't yanked. As the index of a registry served over HTTP " +
+			"can't be listed, only the buildpacks located in it before are found.",
+		Example: "pack buildpack search java",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			if flags.OutputFormat != "table" && flags.OutputFormat != "json" {
+				return errors.Errorf("unknown output format %s, must be table or json", style.Symbol(flags.OutputFormat))
+			}
+
+			registry, err := config.GetRegistry(cfg, flags.BuildpackRegistry)
+			if err != nil {
+				return err
+			}
+
+			buildpacks, err := pack.SearchBuildpacks(cmd.Context(), client.SearchBuildpacksOptions{
+				Term:           args[0],
+				Registry:       registry.Name,
+				RegistryLookup: flags.RegistryLookup,
+			})
+			if err != nil {
+				return err
+			}
+
+			if flags.OutputFormat == "json" {
+				contents, err := json.MarshalIndent(buildpacks, "", "  ")
+				if err != nil {
+					return err
+				}
+				logger.Info(string(contents))
+				return nil
+			}
+
+			if len(buildpacks) == 0 {
+				logger.Infof("No buildpacks of registry %s match %s", style.Symbol(registry.Name), style.Symbol(args[0]))
+				return nil
+			}
+
+			tw := tabwriter.NewWriter(logger.Writer(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "ID\tVERSION\tADDRESS")
+			for _, bp := range buildpacks {
+				fmt.Fprintf(tw, "%s\t%s\t%s\n", bp.ID, bp.Version, bp.Address)
+			}
+			return tw.Flush()
+		}),
+	}
+
+	cmd.Flags().StringVarP(&flags.BuildpackRegistry, "buildpack-registry", "r", "", "Buildpack Registry name")
+	addRegistryLookupFlags(cmd, &flags.RegistryLookup)
+	cmd.Flags().StringVarP(&flags.OutputFormat, "output", "o", "table", "Output format of the buildpacks found, either table or json")
+	AddHelpFlag(cmd, "search")
+	return cmd
+}
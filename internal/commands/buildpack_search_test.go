@@ -0,0 +1,117 @@
+package commands_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/buildpack"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestBuildpackSearchCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "BuildpackSearchCommand", testBuildpackSearchCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testBuildpackSearchCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+		cfg            config.Config
+		buildpacks     = []client.RegistryBuildpack{
+			{ID: "example/java", Version: "1.0.0", Address: "example.com/some/java@sha256:8c27fe111c11b722081701dfed3bd55e039b9ce92865473cf4cdfa918071c566"},
+		}
+	)
+
+	it.Before(func() {
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+		cfg = config.Config{
+			Registries: []config.Registry{{Name: "some-registry", Type: "github", URL: "https://example.com/registry-index"}},
+		}
+
+		command = commands.BuildpackSearch(logger, cfg, mockClient)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#BuildpackSearch", func() {
+		it("fails without a term", func() {
+			command.SetArgs([]string{})
+			h.AssertError(t, command.Execute(), "accepts 1 arg")
+		})
+
+		it("prints the buildpacks found as a table", func() {
+			mockClient.EXPECT().
+				SearchBuildpacks(gomock.Any(), client.SearchBuildpacksOptions{Term: "java", Registry: "official"}).
+				Return(buildpacks, nil)
+
+			command.SetArgs([]string{"java"})
+			h.AssertNil(t, command.Execute())
+			h.AssertContainsMatch(t, outBuf.String(), `ID\s+VERSION\s+ADDRESS`)
+			h.AssertContainsMatch(t, outBuf.String(), `example/java\s+1.0.0\s+example.com/some/java@sha256:8c27fe`)
+		})
+
+		it("prints the buildpacks found as json", func() {
+			mockClient.EXPECT().
+				SearchBuildpacks(gomock.Any(), client.SearchBuildpacksOptions{Term: "java", Registry: "some-registry"}).
+				Return(buildpacks, nil)
+
+			command.SetArgs([]string{"java", "--buildpack-registry", "some-registry", "--output", "json"})
+			h.AssertNil(t, command.Execute())
+			h.AssertContains(t, outBuf.String(), `"id": "example/java"`)
+			h.AssertContains(t, outBuf.String(), `"version": "1.0.0"`)
+		})
+
+		it("passes how the registry is looked up", func() {
+			mockClient.EXPECT().
+				SearchBuildpacks(gomock.Any(), client.SearchBuildpacksOptions{
+					Term:           "java",
+					Registry:       "official",
+					RegistryLookup: buildpack.RegistryLookup{Offline: true, AllowYanked: true, IncludePrereleases: true},
+				}).
+				Return(buildpacks, nil)
+
+			command.SetArgs([]string{"java", "--offline", "--allow-yanked", "--include-prereleases"})
+			h.AssertNil(t, command.Execute())
+		})
+
+		it("says when no buildpacks were found", func() {
+			mockClient.EXPECT().
+				SearchBuildpacks(gomock.Any(), gomock.Any()).
+				Return([]client.RegistryBuildpack{}, nil)
+
+			command.SetArgs([]string{"ruby"})
+			h.AssertNil(t, command.Execute())
+			h.AssertContains(t, outBuf.String(), "No buildpacks of registry 'official' match 'ruby'")
+		})
+
+		it("fails for an unknown output format", func() {
+			command.SetArgs([]string{"java", "--output", "yaml"})
+			h.AssertError(t, command.Execute(), "unknown output format 'yaml', must be table or json")
+		})
+
+		it("fails for a registry that isn't defined", func() {
+			command.SetArgs([]string{"java", "--buildpack-registry", "missing-registry"})
+			h.AssertError(t, command.Execute(), "registry 'missing-registry' is not defined in your config file")
+		})
+	})
+}
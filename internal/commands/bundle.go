@@ -0,0 +1,21 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+func NewBundleCommand(logger logging.Logger, cfg config.Config, client PackClient) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bundle",
+		Short: "Interact with bundles for offline builds",
+		RunE:  nil,
+	}
+
+	cmd.AddCommand(BundleCreate(logger, cfg, client))
+	cmd.AddCommand(BundleApply(logger, cfg, client))
+	AddHelpFlag(cmd, "bundle")
+	return cmd
+}
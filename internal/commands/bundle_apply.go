@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+func BundleApply(logger logging.Logger, cfg config.Config, client PackClient) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply <archive>",
+		Args:  cobra.ExactArgs(1),
+		Short: "Load a bundle for offline builds",
+		Long: "Load the images of a bundle created with `pack bundle create` into the docker daemon, and restore the " +
+			"snapshot of the buildpack registry index it holds, so that builds with its builder and buildpacks run " +
+			"without network access. Build with --pull-policy never, so that images aren't pulled again.",
+		Example: "pack bundle apply offline.tgz",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			bundle, err := client.ApplyBundle(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+
+			logger.Infof("Bundle %s applied, created on %s with:", style.Symbol(args[0]), bundle.Created.Format("2006-01-02"))
+			for _, imageName := range bundle.Images {
+				logger.Infof("  %s", imageName)
+			}
+
+			if bundle.RegistryURL != "" {
+				registryName := registryNameOf(cfg, bundle.RegistryURL)
+				if registryName == "" {
+					logger.Warnf("The buildpack registry %s isn't configured, add it with `pack config registries add <name> %s`", style.Symbol(bundle.RegistryURL), bundle.RegistryURL)
+				} else {
+					logger.Infof("  index of the buildpack registry %s", style.Symbol(registryName))
+				}
+			}
+
+			logger.Infof("Build offline with `pack build <image-name> --builder %s --pull-policy never`", bundle.Builder)
+			return nil
+		}),
+	}
+	AddHelpFlag(cmd, "apply")
+	return cmd
+}
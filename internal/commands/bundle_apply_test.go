@@ -0,0 +1,94 @@
+package commands_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/pkg/errors"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/internal/config"
+	cpkg "github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestBundleApplyCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "BundleApplyCommand", testBundleApplyCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testBundleApplyCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		command = commands.BundleApply(logger, config.Config{}, mockClient)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#BundleApply", func() {
+		it("lists the images of the bundle", func() {
+			mockClient.EXPECT().ApplyBundle(gomock.Any(), "offline.tgz").Return(cpkg.Bundle{
+				Builder: "some/builder",
+				Images:  []string{"some/builder", "some/run"},
+			}, nil)
+
+			command.SetArgs([]string{"offline.tgz"})
+			h.AssertNil(t, command.Execute())
+			h.AssertContains(t, outBuf.String(), "some/run")
+			h.AssertContains(t, outBuf.String(), "--builder some/builder --pull-policy never")
+		})
+
+		when("the registry of the bundle is the default registry", func() {
+			it("names it", func() {
+				mockClient.EXPECT().ApplyBundle(gomock.Any(), "offline.tgz").Return(cpkg.Bundle{
+					RegistryURL: config.DefaultRegistry().URL,
+				}, nil)
+
+				command.SetArgs([]string{"offline.tgz"})
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), "index of the buildpack registry 'official'")
+			})
+		})
+
+		when("the registry of the bundle isn't configured", func() {
+			it("warns", func() {
+				mockClient.EXPECT().ApplyBundle(gomock.Any(), "offline.tgz").Return(cpkg.Bundle{
+					RegistryURL: "https://example.com/registry-index",
+				}, nil)
+
+				command.SetArgs([]string{"offline.tgz"})
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), "Warning: The buildpack registry 'https://example.com/registry-index' isn't configured")
+			})
+		})
+
+		when("the bundle can't be applied", func() {
+			it("errors", func() {
+				mockClient.EXPECT().ApplyBundle(gomock.Any(), "offline.tgz").Return(cpkg.Bundle{}, errors.New("not a bundle"))
+
+				command.SetArgs([]string{"offline.tgz"})
+				h.AssertError(t, command.Execute(), "not a bundle")
+			})
+		})
+	})
+}
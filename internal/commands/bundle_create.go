@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/buildpack"
+	cpkg "github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/image"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+type BundleCreateFlags struct {
+	Builder        string
+	RunImage       string
+	LifecycleImage string
+	Buildpacks     []string
+	Registry       string
+	RegistryLookup buildpack.RegistryLookup
+	Policy         string
+}
+
+func BundleCreate(logger logging.Logger, cfg config.Config, client PackClient) *cobra.Command {
+	var flags BundleCreateFlags
+	cmd := &cobra.Command{
+		Use:   "create <archive>",
+		Args:  cobra.ExactArgs(1),
+		Short: "Create a bundle of everything needed to build offline",
+		Long: "Create a gzipped archive holding everything builds need to run without network access: the builder, its " +
+			"run image, the lifecycle image used for untrusted builds and the images of additional buildpacks. When " +
+			"buildpacks come from a buildpack registry, a snapshot of the index of the registry is included.\n\n" +
+			"Load the bundle on a disconnected machine with `pack bundle apply`.",
+		Example: "pack bundle create offline.tgz --builder cnbs/sample-builder:jammy --buildpack urn:cnb:registry:example/java@1.0.0",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			if flags.Builder == "" {
+				return errors.New("a builder is required, set one with --builder")
+			}
+
+			stringPolicy := flags.Policy
+			if stringPolicy == "" {
+				stringPolicy = cfg.PullPolicy
+			}
+			pullPolicy, err := image.ParsePullPolicy(stringPolicy)
+			if err != nil {
+				return errors.Wrapf(err, "parsing pull policy %s", flags.Policy)
+			}
+
+			bundle, err := client.CreateBundle(cmd.Context(), args[0], cpkg.CreateBundleOptions{
+				Builder:        flags.Builder,
+				RunImage:       flags.RunImage,
+				LifecycleImage: flags.LifecycleImage,
+				Buildpacks:     flags.Buildpacks,
+				Registry:       flags.Registry,
+				RegistryLookup: flags.RegistryLookup,
+				PullPolicy:     pullPolicy,
+			})
+			if err != nil {
+				return err
+			}
+
+			logger.Infof("Bundle %s created with:", style.Symbol(args[0]))
+			for _, imageName := range bundle.Images {
+				logger.Infof("  %s", imageName)
+			}
+			if bundle.RegistryURL != "" {
+				logger.Infof("  index of the buildpack registry %s", bundle.RegistryURL)
+			}
+			return nil
+		}),
+	}
+	AddHelpFlag(cmd, "create")
+	cmd.Flags().StringVarP(&flags.Builder, "builder", "B", cfg.DefaultBuilder, "Builder image to bundle")
+	cmd.Flags().StringVar(&flags.RunImage, "run-image", "", "Run image to bundle (defaults to the run image of the builder)")
+	cmd.Flags().StringVar(&flags.LifecycleImage, "lifecycle-image", cfg.LifecycleImage, "Lifecycle image to bundle (defaults to the lifecycle image of the lifecycle version of the builder)")
+	cmd.Flags().StringSliceVarP(&flags.Buildpacks, "buildpack", "b", nil, "Buildpack to bundle, from a buildpack registry in the form of 'urn:cnb:registry:<buildpack>@<version>', or a packaged buildpack image"+stringSliceHelp("buildpack"))
+	cmd.Flags().StringVarP(&flags.Registry, "buildpack-registry", "r", cfg.DefaultRegistryName, "Buildpack registry that registry buildpacks come from")
+	addRegistryLookupFlags(cmd, &flags.RegistryLookup)
+	cmd.Flags().StringVar(&flags.Policy, "pull-policy", "", "Pull policy of the images to bundle, one of always, if-not-present or never (defaults to the configured policy)")
+	return cmd
+}
@@ -0,0 +1,103 @@
+package commands_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/internal/config"
+	cpkg "github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/image"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestBundleCreateCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "BundleCreateCommand", testBundleCreateCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testBundleCreateCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+		cfg            config.Config
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		cfg = config.Config{DefaultBuilder: "default/builder"}
+		command = commands.BundleCreate(logger, cfg, mockClient)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#BundleCreate", func() {
+		it("bundles the default builder and the buildpacks", func() {
+			mockClient.EXPECT().CreateBundle(gomock.Any(), "offline.tgz", cpkg.CreateBundleOptions{
+				Builder:    "default/builder",
+				Buildpacks: []string{"urn:cnb:registry:example/java@1.0.0"},
+				PullPolicy: image.PullAlways,
+			}).Return(cpkg.Bundle{
+				Images:      []string{"default/builder", "some/run", "buildpacksio/lifecycle:0.20.0", "example/java:1.0.0"},
+				RegistryURL: "https://github.com/buildpacks/registry-index",
+			}, nil)
+
+			command.SetArgs([]string{"offline.tgz", "--buildpack", "urn:cnb:registry:example/java@1.0.0"})
+			h.AssertNil(t, command.Execute())
+			h.AssertContains(t, outBuf.String(), "Bundle 'offline.tgz' created with:")
+			h.AssertContains(t, outBuf.String(), "example/java:1.0.0")
+			h.AssertContains(t, outBuf.String(), "index of the buildpack registry https://github.com/buildpacks/registry-index")
+		})
+
+		it("passes the images and pull policy that are set", func() {
+			mockClient.EXPECT().CreateBundle(gomock.Any(), "offline.tgz", cpkg.CreateBundleOptions{
+				Builder:        "some/builder",
+				RunImage:       "some/run",
+				LifecycleImage: "some/lifecycle",
+				Registry:       "some-registry",
+				PullPolicy:     image.PullIfNotPresent,
+			}).Return(cpkg.Bundle{}, nil)
+
+			command.SetArgs([]string{
+				"offline.tgz",
+				"--builder", "some/builder",
+				"--run-image", "some/run",
+				"--lifecycle-image", "some/lifecycle",
+				"--buildpack-registry", "some-registry",
+				"--pull-policy", "if-not-present",
+			})
+			h.AssertNil(t, command.Execute())
+		})
+
+		when("no builder is set", func() {
+			it("errors", func() {
+				command = commands.BundleCreate(logger, config.Config{}, mockClient)
+				command.SetArgs([]string{"offline.tgz"})
+				h.AssertError(t, command.Execute(), "a builder is required")
+			})
+		})
+
+		when("the pull policy is invalid", func() {
+			it("errors", func() {
+				command.SetArgs([]string{"offline.tgz", "--pull-policy", "sometimes"})
+				h.AssertError(t, command.Execute(), "parsing pull policy")
+			})
+		})
+	})
+}
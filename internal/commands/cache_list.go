@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+// CacheList lists the cache volumes of app images
+func CacheList(logger logging.Logger, packClient PackClient) *cobra.Command {
+	var apps []string
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Args:    cobra.NoArgs,
+		Short:   "List the build and launch cache volumes of app images",
+		Example: "pack cache list --app my-app",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			volumes, err := packClient.ListCaches(cmd.Context(), client.ListCachesOptions{Apps: apps})
+			if err != nil {
+				return err
+			}
+			if len(volumes) == 0 {
+				logger.Info("No cache volumes")
+				return nil
+			}
+
+			tw := tabwriter.NewWriter(logger.Writer(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "APP\tTYPE\tNAME\tCREATED\tSIZE\tIN USE")
+			var total int64
+			for _, volume := range volumes {
+				created := "unknown"
+				if !volume.Created.IsZero() {
+					created = humanize.Time(volume.Created)
+				}
+				size := "unknown"
+				if volume.Size > 0 {
+					size = humanize.Bytes(uint64(volume.Size))
+				}
+				inUse := "no"
+				if volume.InUse {
+					inUse = "yes"
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", volume.App, volume.Type, volume.Name, created, size, inUse)
+				total += volume.Size
+			}
+			tw.Flush()
+
+			logger.Info("")
+			logger.Infof("%d cache volumes, taking %s", len(volumes), humanize.Bytes(uint64(total)))
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringArrayVar(&apps, "app", nil, "Only list the cache volumes of this app image"+stringArrayHelp("app"))
+	AddHelpFlag(cmd, "list")
+	return cmd
+}
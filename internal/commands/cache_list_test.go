@@ -0,0 +1,69 @@
+package commands_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestCacheListCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "CacheListCommand", testCacheListCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testCacheListCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+		command = commands.CacheList(logging.NewLogWithWriters(&outBuf, &outBuf), mockClient)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#CacheList", func() {
+		it("lists the cache volumes", func() {
+			mockClient.EXPECT().ListCaches(gomock.Any(), client.ListCachesOptions{}).Return([]client.CacheVolume{
+				{Name: "pack-cache-some_app_latest-0123456789ab.build", App: "some_app_latest", Type: "build", Created: time.Now().Add(-48 * time.Hour), Size: 500_000_000},
+				{Name: "pack-cache-some_app_latest-0123456789ab.launch", App: "some_app_latest", Type: "launch", InUse: true},
+			}, nil)
+
+			command.SetArgs([]string{})
+			h.AssertNil(t, command.Execute())
+
+			h.AssertContains(t, outBuf.String(), "some_app_latest  build   pack-cache-some_app_latest-0123456789ab.build   2 days ago  500 MB   no")
+			h.AssertContains(t, outBuf.String(), "some_app_latest  launch  pack-cache-some_app_latest-0123456789ab.launch  unknown     unknown  yes")
+			h.AssertContains(t, outBuf.String(), "2 cache volumes, taking 500 MB")
+		})
+
+		it("only lists the cache volumes of the given apps", func() {
+			mockClient.EXPECT().ListCaches(gomock.Any(), client.ListCachesOptions{Apps: []string{"some/app", "other/app"}}).Return(nil, nil)
+
+			command.SetArgs([]string{"--app", "some/app", "--app", "other/app"})
+			h.AssertNil(t, command.Execute())
+
+			h.AssertContains(t, outBuf.String(), "No cache volumes")
+		})
+	})
+}
@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+type CachePruneFlags struct {
+	Apps      []string
+	OlderThan string
+	MinSize   string
+	DryRun    bool
+}
+
+// CachePrune removes the cache volumes of app images
+func CachePrune(logger logging.Logger, packClient PackClient) *cobra.Command {
+	var flags CachePruneFlags
+
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Args:  cobra.NoArgs,
+		Short: "Remove the build and launch cache volumes of app images",
+		Long: "Remove the cache volumes of app images, across all apps unless `--app` is set. The next build of an app " +
+			"whose caches are removed starts from cold caches.\n\n" +
+			"Only volumes older than `--older-than` are removed, and volumes used by containers, such as the ones of " +
+			"builds running now, are always kept. Use `--dry-run` to list the volumes without removing them.",
+		Example: "pack cache prune --app my-app --older-than 30d --dry-run",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			olderThan, err := parseAge(flags.OlderThan)
+			if err != nil {
+				return err
+			}
+			minSize, err := parseMinSize(flags.MinSize)
+			if err != nil {
+				return err
+			}
+
+			resources, err := packClient.Prune(cmd.Context(), client.PruneOptions{
+				Kinds:     []string{client.PruneCache},
+				Apps:      flags.Apps,
+				OlderThan: olderThan,
+				MinSize:   minSize,
+				DryRun:    flags.DryRun,
+			})
+			if err != nil {
+				return err
+			}
+			return logPrunedResources(logger, resources, flags.DryRun)
+		}),
+	}
+
+	cmd.Flags().StringArrayVar(&flags.Apps, "app", nil, "Only remove the cache volumes of this app image"+stringArrayHelp("app"))
+	cmd.Flags().StringVar(&flags.OlderThan, "older-than", "1h", "Only remove volumes created at least this long ago, e.g. '90m', '72h' or '30d'; '0' removes volumes of any age")
+	cmd.Flags().StringVar(&flags.MinSize, "min-size", "", "Only remove volumes of at least this size, e.g. '500MB'")
+	cmd.Flags().BoolVar(&flags.DryRun, "dry-run", false, "List the volumes that would be removed, without removing them")
+	AddHelpFlag(cmd, "prune")
+	return cmd
+}
@@ -0,0 +1,81 @@
+package commands_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestCachePruneCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "CachePruneCommand", testCachePruneCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testCachePruneCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+		resources      []client.PrunedResource
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+		command = commands.CachePrune(logging.NewLogWithWriters(&outBuf, &outBuf), mockClient)
+
+		resources = []client.PrunedResource{
+			{Kind: client.PruneCache, Name: "pack-cache-some_app_latest-0123456789ab.build", Created: time.Now().Add(-48 * time.Hour), Size: 500_000_000},
+		}
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#CachePrune", func() {
+		it("removes the cache volumes older than an hour by default", func() {
+			mockClient.EXPECT().Prune(gomock.Any(), client.PruneOptions{Kinds: []string{client.PruneCache}, OlderThan: time.Hour}).Return(resources, nil)
+
+			command.SetArgs([]string{})
+			h.AssertNil(t, command.Execute())
+
+			h.AssertContains(t, outBuf.String(), "cache  pack-cache-some_app_latest-0123456789ab.build  2 days ago  500 MB")
+			h.AssertContains(t, outBuf.String(), "Removed 1 resources, freeing 500 MB")
+		})
+
+		it("passes the filters", func() {
+			mockClient.EXPECT().Prune(gomock.Any(), client.PruneOptions{
+				Kinds:     []string{client.PruneCache},
+				Apps:      []string{"some/app"},
+				OlderThan: 30 * 24 * time.Hour,
+				MinSize:   100_000_000,
+				DryRun:    true,
+			}).Return(resources, nil)
+
+			command.SetArgs([]string{"--app", "some/app", "--older-than", "30d", "--min-size", "100MB", "--dry-run"})
+			h.AssertNil(t, command.Execute())
+
+			h.AssertContains(t, outBuf.String(), "1 resources would be removed, freeing 500 MB")
+		})
+
+		it("fails for an invalid min-size", func() {
+			command.SetArgs([]string{"--min-size", "big"})
+			h.AssertError(t, command.Execute(), "invalid min-size 'big'")
+		})
+	})
+}
@@ -2,7 +2,9 @@ package commands
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"syscall"
@@ -14,11 +16,13 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/i18n"
 	"github.com/buildpacks/pack/internal/style"
 	"github.com/buildpacks/pack/internal/target"
 	"github.com/buildpacks/pack/pkg/buildpack"
 	"github.com/buildpacks/pack/pkg/client"
 	"github.com/buildpacks/pack/pkg/dist"
+	"github.com/buildpacks/pack/pkg/errcode"
 	"github.com/buildpacks/pack/pkg/logging"
 )
 
@@ -37,7 +41,20 @@ type PackClient interface {
 	InspectBuildpack(client.InspectBuildpackOptions) (*client.BuildpackInfo, error)
 	InspectExtension(client.InspectExtensionOptions) (*client.ExtensionInfo, error)
 	PullBuildpack(context.Context, client.PullBuildpackOptions) error
+	SearchBuildpacks(context.Context, client.SearchBuildpacksOptions) ([]client.RegistryBuildpack, error)
 	DownloadSBOM(name string, options client.DownloadSBOMOptions) error
+	MergeSBOM(name string, options client.MergeSBOMOptions) ([]byte, error)
+	DiffImages(ctx context.Context, base, target string, opts client.DiffImagesOptions) (*client.ImageDiff, error)
+	SetDefaultProcess(ctx context.Context, opts client.SetDefaultProcessOptions) error
+	CollectDebugBundle(ctx context.Context, opts client.DebugBundleOptions) ([]string, error)
+	AttachAttestation(ctx context.Context, imageName string, opts client.AttachAttestationOptions) (string, error)
+	CreateBundle(ctx context.Context, path string, opts client.CreateBundleOptions) (client.Bundle, error)
+	ApplyBundle(ctx context.Context, path string) (client.Bundle, error)
+	ExportRegistryCache(path string, opts client.ExportRegistryCacheOptions) (client.RegistryCacheArchive, error)
+	ImportRegistryCache(path string) (client.RegistryCacheArchive, error)
+	Run(ctx context.Context, opts client.RunOptions) error
+	Prune(ctx context.Context, opts client.PruneOptions) ([]client.PrunedResource, error)
+	ListCaches(ctx context.Context, opts client.ListCachesOptions) ([]client.CacheVolume, error)
 	CreateManifest(ctx context.Context, opts client.CreateManifestOptions) error
 	AnnotateManifest(ctx context.Context, opts client.ManifestAnnotateOptions) error
 	AddManifest(ctx context.Context, opts client.ManifestAddOptions) error
@@ -45,12 +62,35 @@ type PackClient interface {
 	RemoveManifest(name string, images []string) error
 	PushManifest(client.PushManifestOptions) error
 	InspectManifest(string) error
+	Doctor(context.Context, client.DoctorOptions) []client.Diagnostic
+	InspectLifecycle(ctx context.Context, opts client.InspectLifecycleOptions) (*client.LifecycleCompatibility, error)
 }
 
 func AddHelpFlag(cmd *cobra.Command, commandName string) {
 	cmd.Flags().BoolP("help", "h", false, fmt.Sprintf("Help for '%s'", commandName))
 }
 
+// AddErrorFormatFlag adds the persistent flag selecting the format in which cmd and its subcommands report failures
+func AddErrorFormatFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().String("error-format", "text", "Format of failures, text or json; json reports them as a structured block with a stable error code")
+}
+
+// AddWarningsAsErrorsFlag adds the persistent flag failing cmd and its subcommands when any warnings were reported
+// to logger, the ones logged by pack as well as the ones the lifecycle writes to its output
+func AddWarningsAsErrorsFlag(cmd *cobra.Command, logger logging.Logger) {
+	cmd.PersistentFlags().Bool("warnings-as-errors", false, "Fail the command if any warnings were reported, including those of the lifecycle (e.g. deprecations or mixin mismatches)")
+	cmd.PersistentPostRunE = func(cmd *cobra.Command, args []string) error {
+		strict, _ := cmd.Flags().GetBool("warnings-as-errors")
+		if count := logging.WarningCount(logger); strict && count > 0 {
+			cmd.SilenceErrors = true
+			err := errcode.Wrap(errcode.WarningsAsErrors, errors.New(i18n.T(i18n.WarningsAsErrors, count)))
+			ReportError(logger, cmd, err)
+			return err
+		}
+		return nil
+	}
+}
+
 func CreateCancellableContext() context.Context {
 	signals := make(chan os.Signal, 1)
 	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
@@ -70,8 +110,12 @@ func logError(logger logging.Logger, f func(cmd *cobra.Command, args []string) e
 		cmd.SilenceUsage = true
 		err := f(cmd, args)
 		if err != nil {
-			if _, isSoftError := errors.Cause(err).(client.SoftError); !isSoftError {
-				logger.Error(err.Error())
+			_, isSoftError := errors.Cause(err).(client.SoftError)
+			if !isSoftError {
+				ReportError(logger, cmd, err)
+			}
+			if wantJSONErrors(cmd) {
+				return err
 			}
 
 			if _, isExpError := errors.Cause(err).(client.ExperimentError); isExpError {
@@ -87,10 +131,58 @@ func logError(logger logging.Logger, f func(cmd *cobra.Command, args []string) e
 	}
 }
 
+type jsonError struct {
+	Code     errcode.Code `json:"code"`
+	Name     string       `json:"name"`
+	Message  string       `json:"message"`
+	ExitCode int          `json:"exitCode"`
+}
+
+// ReportError reports err, the failure of cmd, as a log line or, when asked for with `--error-format json`,
+// as a structured block carrying a stable error code.
+func ReportError(logger logging.Logger, cmd *cobra.Command, err error) {
+	if wantJSONErrors(cmd) {
+		writeJSONError(logging.GetWriterForLevel(logger, logging.ErrorLevel), err)
+		return
+	}
+	logger.Error(err.Error())
+}
+
+func wantJSONErrors(cmd *cobra.Command) bool {
+	flag := cmd.Flags().Lookup("error-format")
+	return flag != nil && flag.Value.String() == "json"
+}
+
+func writeJSONError(w io.Writer, err error) {
+	code := errcode.Of(err)
+	out := struct {
+		Error jsonError `json:"error"`
+	}{
+		Error: jsonError{
+			Code:     code,
+			Name:     code.Name(),
+			Message:  err.Error(),
+			ExitCode: code.ExitCode(),
+		},
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(out)
+}
+
 func enableExperimentalTip(logger logging.Logger, configPath string) {
 	logging.Tip(logger, "To enable experimental features, run `pack config experimental true` to add %s to %s.", style.Symbol("experimental = true"), style.Symbol(configPath))
 }
 
+// logQuietReference writes the reference produced by a command as a single line to the logger's base writer
+// when quiet mode is enabled, so that the output of the command can be consumed by shell pipelines.
+func logQuietReference(logger logging.Logger, reference string) {
+	if logging.IsQuiet(logger) {
+		fmt.Fprintln(logger.Writer(), reference)
+	}
+}
+
 func stringArrayHelp(name string) string {
 	return fmt.Sprintf("\nRepeat for each %s in order (comma-separated lists not accepted)", name)
 }
@@ -99,6 +191,14 @@ func stringSliceHelp(name string) string {
 	return fmt.Sprintf("\nRepeat for each %s in order, or supply once by comma-separated list", name)
 }
 
+// addRegistryLookupFlags adds the flags configuring how the commands locate registry buildpacks
+func addRegistryLookupFlags(cmd *cobra.Command, lookup *buildpack.RegistryLookup) {
+	cmd.Flags().BoolVar(&lookup.Offline, "offline", false, "Locate registry buildpacks in the existing registry cache without refreshing it, for when there is no network")
+	cmd.Flags().BoolVar(&lookup.ForceRefresh, "force-refresh", false, "Refresh the registry cache even if it was refreshed within the interval set by `pack config registry-refresh-interval`")
+	cmd.Flags().BoolVar(&lookup.AllowYanked, "allow-yanked", false, "Locate yanked versions of registry buildpacks, which are skipped otherwise")
+	cmd.Flags().BoolVar(&lookup.IncludePrereleases, "include-prereleases", false, "Locate pre-release versions of registry buildpacks requested without a version, which are skipped otherwise")
+}
+
 func getMirrors(config config.Config) map[string][]string {
 	mirrors := map[string][]string{}
 	for _, ri := range config.RunImages {
@@ -134,6 +234,18 @@ func parseFormatFlag(value string) (types.MediaType, error) {
 	return format, nil
 }
 
+// parseCompressionFlag parses the compression of the layers of published images, zstd requiring the publish flag
+func parseCompressionFlag(value string, publish bool) (client.LayerCompression, error) {
+	compression, err := client.ParseLayerCompression(value)
+	if err != nil {
+		return "", err
+	}
+	if compression == client.ZstdCompression && !publish {
+		return "", errors.New("zstd compression requires the publish flag")
+	}
+	return compression, nil
+}
+
 // processMultiArchitectureConfig takes an array of targets with format: [os][/arch][/variant]:[distroname@osversion@anotherversion];[distroname@osversion]
 // and a list of targets defined in a configuration file (buildpack.toml or package.toml) and creates a multi-architecture configuration
 func processMultiArchitectureConfig(logger logging.Logger, userTargets []string, configTargets []dist.Target, daemon bool) (*buildpack.MultiArchConfig, error) {
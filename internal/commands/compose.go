@@ -0,0 +1,179 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/compose"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/cache"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+type ComposeFlags struct {
+	File         string
+	Publish      bool
+	Policy       string
+	TrustBuilder bool
+	ClearCache   bool
+}
+
+// Compose builds the images of a compose file
+func Compose(logger logging.Logger, cfg config.Config, packClient PackClient) *cobra.Command {
+	var flags ComposeFlags
+
+	cmd := &cobra.Command{
+		Use:   "compose [<name>...]",
+		Short: "Build the images of a compose file",
+		Long: "Build the images described in a compose file, each after the images it depends on. Without names, " +
+			"every image of the file is built; otherwise the named images and their dependencies are. When a build " +
+			"fails, the images depending on it are skipped.\n\n" +
+			"The compose file is pack-compose.toml, pack-compose.yaml or pack-compose.yml in the working directory, " +
+			"or the one set with `--file`, e.g.\n\n" +
+			"  builder = \"cnbs/sample-builder:jammy\"\n\n" +
+			"  [cache]\n  build = \"shop-build-cache\"\n\n" +
+			"  [images.api]\n  image = \"registry.example.com/shop/api\"\n  path = \"services/api\"\n  env = { BP_JVM_VERSION = \"21\" }\n\n" +
+			"  [images.web]\n  image = \"registry.example.com/shop/web\"\n  path = \"services/web\"\n  depends-on = [\"api\"]\n\n" +
+			"Images may also set their own `builder`, `run-image`, `buildpacks`, `tags` and `publish`. Paths are relative " +
+			"to the compose file. The volumes named in `[cache]` hold the build and launch caches shared by every image.",
+		Example: "pack compose web --file pack-compose.yaml",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			path := flags.File
+			if path == "" {
+				var err error
+				if path, err = compose.Find("."); err != nil {
+					return err
+				}
+			}
+			file, err := compose.Read(path)
+			if err != nil {
+				return err
+			}
+			order, err := file.Order(args)
+			if err != nil {
+				return err
+			}
+
+			images := make([]string, len(order))
+			for i, imageName := range order {
+				images[i] = file.Images[imageName].Image
+			}
+			dashboard := newMultiBuild(logger, images)
+
+			skipped := map[string]bool{}
+			failures := map[string]error{}
+			builds := func() {
+				for i, imageName := range order {
+					composeImage := file.Images[imageName]
+					imageLogger := logger
+					if dashboard != nil {
+						imageLogger = multiBuildLogger(logger, dashboard, composeImage.Image)
+					}
+					if dependency, ok := failedDependency(composeImage, skipped); ok {
+						err := errors.Errorf("its dependency %s wasn't built", style.Symbol(dependency))
+						imageLogger.Warnf("Skipping %s, %s", style.Symbol(imageName), err)
+						skipped[imageName] = true
+						if dashboard != nil {
+							dashboard.Finish(composeImage.Image, err)
+						}
+						continue
+					}
+
+					if dashboard != nil {
+						dashboard.Start(composeImage.Image)
+					}
+					imageLogger.Infof("Building %s (%d of %d)", style.Symbol(imageName), i+1, len(order))
+					err := buildComposeImage(cmd, imageLogger, cfg, packClient, flags, file, composeImage)
+					if dashboard != nil {
+						dashboard.Finish(composeImage.Image, err)
+					}
+					if err != nil {
+						failures[imageName] = err
+						skipped[imageName] = true
+						if dashboard == nil {
+							logger.Errorf("Failed to build %s: %s", style.Symbol(imageName), err)
+						}
+					}
+				}
+			}
+			if dashboard == nil {
+				builds()
+			} else {
+				if err := runMultiBuild(cmd, logger, dashboard, builds); err != nil {
+					return err
+				}
+				// the failures were shown by the dashboard, which is gone once closed
+				for _, imageName := range order {
+					if err, ok := failures[imageName]; ok {
+						logMultiBuild(logger, dashboard, file.Images[imageName].Image)
+						logger.Errorf("Failed to build %s: %s", style.Symbol(imageName), err)
+					}
+				}
+			}
+
+			if len(skipped) > 0 {
+				return errors.Errorf("%d of %d images weren't built", len(skipped), len(order))
+			}
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringVarP(&flags.File, "file", "f", "", "Path to the compose file (defaults to pack-compose.toml, pack-compose.yaml or pack-compose.yml)")
+	cmd.Flags().BoolVar(&flags.Publish, "publish", false, "Publish every image to its registry, instead of the images that set publish")
+	cmd.Flags().StringVar(&flags.Policy, "pull-policy", "", `Pull policy to use. Accepted values are always, never, and if-not-present. (default "always")`)
+	cmd.Flags().BoolVar(&flags.TrustBuilder, "trust-builder", false, "Trust the builders of the images")
+	cmd.Flags().BoolVar(&flags.ClearCache, "clear-cache", false, "Clear the caches of the images before building")
+	AddHelpFlag(cmd, "compose")
+	return cmd
+}
+
+// failedDependency returns a dependency of image that wasn't built
+func failedDependency(image compose.Image, skipped map[string]bool) (string, bool) {
+	for _, dependency := range image.DependsOn {
+		if skipped[dependency] {
+			return dependency, true
+		}
+	}
+	return "", false
+}
+
+func buildComposeImage(cmd *cobra.Command, logger logging.Logger, cfg config.Config, packClient PackClient, composeFlags ComposeFlags, file compose.File, composeImage compose.Image) error {
+	flags := BuildFlags{
+		AppPath:        composeImage.Path,
+		Builder:        cfg.DefaultBuilder,
+		RunImage:       composeImage.RunImage,
+		Buildpacks:     composeImage.Buildpacks,
+		AdditionalTags: composeImage.Tags,
+		Publish:        composeImage.Publish || composeFlags.Publish,
+		Policy:         composeFlags.Policy,
+		TrustBuilder:   composeFlags.TrustBuilder,
+		ClearCache:     composeFlags.ClearCache,
+		LifecycleImage: cfg.LifecycleImage,
+	}
+	for key, value := range composeImage.Env {
+		flags.Env = append(flags.Env, fmt.Sprintf("%s=%s", key, value))
+	}
+	sort.Strings(flags.Env)
+	if file.Cache.Build != "" {
+		flags.Cache.Build = cache.CacheInfo{Format: cache.CacheVolume, Source: file.Cache.Build}
+	}
+	if file.Cache.Launch != "" {
+		flags.Cache.Launch = cache.CacheInfo{Format: cache.CacheVolume, Source: file.Cache.Launch}
+	}
+
+	descriptor, actualDescriptorPath, err := readProjectDescriptor(flags, logger)
+	if err != nil {
+		return err
+	}
+	// the builders of the compose file take precedence over the one of the project descriptor of the image
+	if composeImage.Builder != "" {
+		descriptor.Build.Builder = composeImage.Builder
+	} else if file.Builder != "" {
+		descriptor.Build.Builder = file.Builder
+	}
+	return buildImage(cmd, logger, cfg, packClient, flags, composeImage.Image, descriptor, actualDescriptorPath)
+}
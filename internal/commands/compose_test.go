@@ -0,0 +1,145 @@
+package commands_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/pkg/errors"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/cache"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestComposeCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "ComposeCommand", testComposeCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testComposeCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+		composeDir     string
+		composePath    string
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		command = commands.Compose(logger, config.Config{}, mockClient)
+
+		composeDir = t.TempDir()
+		composePath = filepath.Join(composeDir, "pack-compose.toml")
+		h.AssertNil(t, os.WriteFile(composePath, []byte(`
+builder = "some/builder"
+
+[cache]
+build = "shared-build"
+
+[images.web]
+image = "web"
+path = "web"
+builder = "web/builder"
+depends-on = ["api"]
+
+[images.api]
+image = "api"
+path = "api"
+env = { PORT = "8080" }
+
+[images.docs]
+image = "docs"
+path = "docs"
+`), 0600))
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#Compose", func() {
+		it("builds every image after its dependencies", func() {
+			gomock.InOrder(
+				mockClient.EXPECT().Build(gomock.Any(), EqBuildOptionsForApp("some/builder", "api", filepath.Join(composeDir, "api"))).Return(nil),
+				mockClient.EXPECT().Build(gomock.Any(), EqBuildOptionsForApp("some/builder", "docs", filepath.Join(composeDir, "docs"))).Return(nil),
+				mockClient.EXPECT().Build(gomock.Any(), EqBuildOptionsForApp("web/builder", "web", filepath.Join(composeDir, "web"))).Return(nil),
+			)
+
+			command.SetArgs([]string{"--file", composePath})
+			h.AssertNil(t, command.Execute())
+			h.AssertContains(t, outBuf.String(), "Building 'api' (1 of 3)")
+			h.AssertContains(t, outBuf.String(), "Building 'web' (3 of 3)")
+		})
+
+		it("builds the named images and their dependencies", func() {
+			gomock.InOrder(
+				mockClient.EXPECT().Build(gomock.Any(), EqBuildOptionsWithImage("some/builder", "api")).Return(nil),
+				mockClient.EXPECT().Build(gomock.Any(), EqBuildOptionsWithImage("web/builder", "web")).Return(nil),
+			)
+
+			command.SetArgs([]string{"web", "--file", composePath})
+			h.AssertNil(t, command.Execute())
+		})
+
+		it("applies the env and the shared caches", func() {
+			mockClient.EXPECT().Build(gomock.Any(), buildOptionsMatcher{
+				description: "Env=PORT=8080 and a shared build cache",
+				equals: func(o client.BuildOptions) bool {
+					return o.Env["PORT"] == "8080" &&
+						o.Cache.Build == cache.CacheInfo{Format: cache.CacheVolume, Source: "shared-build"}
+				},
+			}).Return(nil)
+
+			command.SetArgs([]string{"api", "--file", composePath})
+			h.AssertNil(t, command.Execute())
+		})
+
+		it("publishes every image with --publish", func() {
+			mockClient.EXPECT().Build(gomock.Any(), buildOptionsMatcher{
+				description: "Publish=true",
+				equals: func(o client.BuildOptions) bool {
+					return o.Publish
+				},
+			}).Return(nil)
+
+			command.SetArgs([]string{"docs", "--file", composePath, "--publish"})
+			h.AssertNil(t, command.Execute())
+		})
+
+		when("a build fails", func() {
+			it("skips the images depending on it", func() {
+				mockClient.EXPECT().Build(gomock.Any(), EqBuildOptionsWithImage("some/builder", "api")).Return(errors.New("api failed"))
+				mockClient.EXPECT().Build(gomock.Any(), EqBuildOptionsWithImage("some/builder", "docs")).Return(nil)
+
+				command.SetArgs([]string{"--file", composePath})
+				h.AssertError(t, command.Execute(), "2 of 3 images weren't built")
+				h.AssertContains(t, outBuf.String(), "Failed to build 'api'")
+				h.AssertContains(t, outBuf.String(), "Skipping 'web', its dependency 'api' wasn't built")
+			})
+		})
+
+		when("an image is unknown", func() {
+			it("errors", func() {
+				command.SetArgs([]string{"db", "--file", composePath})
+				h.AssertError(t, command.Execute(), "unknown image 'db'")
+			})
+		})
+	})
+}
@@ -23,7 +23,21 @@ func NewConfigCommand(logger logging.Logger, cfg config.Config, cfgPath string,
 	cmd.AddCommand(ConfigRunImagesMirrors(logger, cfg, cfgPath))
 	cmd.AddCommand(ConfigTrustedBuilder(logger, cfg, cfgPath))
 	cmd.AddCommand(ConfigLifecycleImage(logger, cfg, cfgPath))
+	cmd.AddCommand(ConfigLifecycleMirror(logger, cfg, cfgPath))
 	cmd.AddCommand(ConfigRegistryMirrors(logger, cfg, cfgPath))
+	cmd.AddCommand(ConfigRegistryRefreshInterval(logger, cfg, cfgPath))
+	cmd.AddCommand(ConfigCacheSizeLimit(logger, cfg, cfgPath))
+	cmd.AddCommand(ConfigDownloadWorkers(logger, cfg, cfgPath))
+	cmd.AddCommand(ConfigRegistryCache(logger, cfg, cfgPath))
+	cmd.AddCommand(ConfigRegistryProxy(logger, cfg, cfgPath))
+	cmd.AddCommand(ConfigColorTheme(logger, cfg, cfgPath))
+	cmd.AddCommand(ConfigLocale(logger, cfg, cfgPath))
+	cmd.AddCommand(ConfigTelemetry(logger, cfg, cfgPath))
+	cmd.AddCommand(ConfigScan(logger, cfg, cfgPath))
+	cmd.AddCommand(ConfigUpdate(logger, cfg, cfgPath))
+	cmd.AddCommand(ConfigPolicy(logger, cfg, cfgPath))
+	cmd.AddCommand(ConfigSecurityProfiles(logger, cfg, cfgPath))
+	cmd.AddCommand(ConfigDockerHost(logger, cfg, cfgPath))
 
 	AddHelpFlag(cmd, "config")
 	return cmd
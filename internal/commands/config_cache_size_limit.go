@@ -0,0 +1,144 @@
+package commands
+
+import (
+	"sort"
+
+	"github.com/dustin/go-humanize"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+func ConfigCacheSizeLimit(logger logging.Logger, cfg config.Config, cfgPath string) *cobra.Command {
+	var (
+		app   string
+		unset bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "cache-size-limit <size>",
+		Args:  cobra.MaximumNArgs(1),
+		Short: "List, set and unset the size limit of the build caches",
+		Long: "Before restoring the volume build cache of an app image, the least recently created layers of the cache are " +
+			"evicted until the cache fits its size limit. The limit is a size such as 500MB or 5GB, and 0 doesn't limit the cache. " +
+			"Use --app to set the limit of a single app image, overriding the limit of the other ones.\n\n" +
+			"* Running `pack config cache-size-limit` prints the limits.\n" +
+			"* Running `pack config cache-size-limit <size>` sets the limit.\n" +
+			"* Running `pack config cache-size-limit --unset` unsets the limit.\n\n" +
+			"The --cache-size-limit flag of `pack build` overrides the limits for a build.",
+		Example: "pack config cache-size-limit 2GB --app my-app",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			var key string
+			if app != "" {
+				var err error
+				if key, err = cacheSizeLimitKey(app); err != nil {
+					return err
+				}
+			}
+
+			switch {
+			case unset:
+				if len(args) > 0 {
+					return errors.Errorf("size and --unset cannot be specified simultaneously")
+				}
+
+				if key != "" {
+					if _, ok := cfg.CacheSizeLimits[key]; !ok {
+						logger.Infof("No cache size limit was set for %s.", style.Symbol(key))
+						return nil
+					}
+					delete(cfg.CacheSizeLimits, key)
+				} else {
+					if cfg.CacheSizeLimit == "" {
+						logger.Info("No cache size limit was set.")
+						return nil
+					}
+					cfg.CacheSizeLimit = ""
+				}
+				if err := config.Write(cfg, cfgPath); err != nil {
+					return errors.Wrapf(err, "failed to write to config at %s", cfgPath)
+				}
+				logger.Info("Successfully unset the cache size limit")
+			case len(args) == 0:
+				if key != "" {
+					limit, ok := cfg.CacheSizeLimits[key]
+					if !ok {
+						limit = cfg.CacheSizeLimit
+					}
+					logCacheSizeLimit(logger, "The build cache of "+style.Symbol(key)+" is", limit)
+					return nil
+				}
+				logCacheSizeLimit(logger, "The build caches are", cfg.CacheSizeLimit)
+				var keys []string
+				for key := range cfg.CacheSizeLimits {
+					keys = append(keys, key)
+				}
+				sort.Strings(keys)
+				for _, key := range keys {
+					logCacheSizeLimit(logger, "The build cache of "+style.Symbol(key)+" is", cfg.CacheSizeLimits[key])
+				}
+			default:
+				size, err := parseCacheSizeLimit(args[0])
+				if err != nil {
+					return err
+				}
+
+				target := "The build caches"
+				if key != "" {
+					if cfg.CacheSizeLimits == nil {
+						cfg.CacheSizeLimits = map[string]string{}
+					}
+					cfg.CacheSizeLimits[key] = args[0]
+					target = "The build cache of " + style.Symbol(key)
+				} else {
+					cfg.CacheSizeLimit = args[0]
+				}
+				if err := config.Write(cfg, cfgPath); err != nil {
+					return errors.Wrapf(err, "failed to write to config at %s", cfgPath)
+				}
+				if size == 0 {
+					logger.Infof("%s will no longer be limited in size", target)
+				} else {
+					logger.Infof("%s will now be limited to %s", target, style.Symbol(args[0]))
+				}
+			}
+
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringVar(&app, "app", "", "App image whose limit is listed, set or unset, rather than the limit of all the build caches")
+	cmd.Flags().BoolVarP(&unset, "unset", "u", false, "Unset the limit")
+	AddHelpFlag(cmd, "cache-size-limit")
+	return cmd
+}
+
+func logCacheSizeLimit(logger logging.Logger, subject, limit string) {
+	if size, err := parseCacheSizeLimit(limit); err != nil || size == 0 {
+		logger.Infof("%s not limited in size", subject)
+		return
+	}
+	logger.Infof("%s limited to %s", subject, style.Symbol(limit))
+}
+
+// cacheSizeLimitKey is the key of the app image imageName in the cache size limits of the config
+func cacheSizeLimitKey(imageName string) (string, error) {
+	ref, err := name.ParseReference(imageName, name.WeakValidation)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid app image %s", style.Symbol(imageName))
+	}
+	return ref.Name(), nil
+}
+
+// parseCacheSizeLimit parses a size such as 500MB or 5GB, 0 not limiting the size
+func parseCacheSizeLimit(limit string) (int64, error) {
+	size, err := humanize.ParseBytes(limit)
+	if err != nil || size > 1<<62 {
+		return 0, errors.Errorf("invalid cache size limit %s, must be a size such as 500MB or 5GB", style.Symbol(limit))
+	}
+	return int64(size), nil
+}
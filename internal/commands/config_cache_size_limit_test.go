@@ -0,0 +1,136 @@
+package commands_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestConfigCacheSizeLimit(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "ConfigCacheSizeLimit", testConfigCacheSizeLimit, spec.Random(), spec.Report(report.Terminal{}))
+}
+
+func testConfigCacheSizeLimit(t *testing.T, when spec.G, it spec.S) {
+	var (
+		logger       logging.Logger
+		outBuf       bytes.Buffer
+		tempPackHome string
+		configPath   string
+	)
+
+	it.Before(func() {
+		var err error
+
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		tempPackHome, err = os.MkdirTemp("", "pack-home")
+		h.AssertNil(t, err)
+		configPath = filepath.Join(tempPackHome, "config.toml")
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.RemoveAll(tempPackHome))
+	})
+
+	newCommand := func(cfg config.Config, args ...string) *cobra.Command {
+		cmd := commands.ConfigCacheSizeLimit(logger, cfg, configPath)
+		cmd.SetOut(logging.GetWriterForLevel(logger, logging.InfoLevel))
+		cmd.SetArgs(args)
+		return cmd
+	}
+
+	when("#ConfigCacheSizeLimit", func() {
+		when("list", func() {
+			it("prints that the caches aren't limited when no limit is set", func() {
+				h.AssertNil(t, newCommand(config.Config{}).Execute())
+				h.AssertContains(t, outBuf.String(), "The build caches are not limited in size")
+			})
+
+			it("prints the limits", func() {
+				cfg := config.Config{
+					CacheSizeLimit:  "5GB",
+					CacheSizeLimits: map[string]string{"index.docker.io/library/my-app:latest": "1GB"},
+				}
+				h.AssertNil(t, newCommand(cfg).Execute())
+				h.AssertContains(t, outBuf.String(), "The build caches are limited to '5GB'")
+				h.AssertContains(t, outBuf.String(), "The build cache of 'index.docker.io/library/my-app:latest' is limited to '1GB'")
+			})
+
+			it("prints the limit of an app, falling back to the limit of all the caches", func() {
+				h.AssertNil(t, newCommand(config.Config{CacheSizeLimit: "5GB"}, "--app", "my-app").Execute())
+				h.AssertContains(t, outBuf.String(), "The build cache of 'index.docker.io/library/my-app:latest' is limited to '5GB'")
+			})
+		})
+
+		when("set", func() {
+			it("sets the limit", func() {
+				h.AssertNil(t, newCommand(config.Config{}, "5GB").Execute())
+
+				cfg, err := config.Read(configPath)
+				h.AssertNil(t, err)
+				h.AssertEq(t, cfg.CacheSizeLimit, "5GB")
+				h.AssertContains(t, outBuf.String(), "The build caches will now be limited to '5GB'")
+			})
+
+			it("sets the limit of an app", func() {
+				h.AssertNil(t, newCommand(config.Config{}, "0", "--app", "my-app").Execute())
+
+				cfg, err := config.Read(configPath)
+				h.AssertNil(t, err)
+				h.AssertEq(t, cfg.CacheSizeLimits, map[string]string{"index.docker.io/library/my-app:latest": "0"})
+				h.AssertContains(t, outBuf.String(), "The build cache of 'index.docker.io/library/my-app:latest' will no longer be limited in size")
+			})
+
+			it("fails for an invalid size", func() {
+				err := newCommand(config.Config{}, "big").Execute()
+				h.AssertError(t, err, "invalid cache size limit 'big', must be a size such as 500MB or 5GB")
+			})
+
+			it("fails for an invalid app", func() {
+				err := newCommand(config.Config{}, "1GB", "--app", "My-App").Execute()
+				h.AssertError(t, err, "invalid app image 'My-App'")
+			})
+		})
+
+		when("unset", func() {
+			it("unsets the limit", func() {
+				h.AssertNil(t, newCommand(config.Config{CacheSizeLimit: "5GB"}, "--unset").Execute())
+
+				cfg, err := config.Read(configPath)
+				h.AssertNil(t, err)
+				h.AssertEq(t, cfg.CacheSizeLimit, "")
+				h.AssertContains(t, outBuf.String(), "Successfully unset the cache size limit")
+			})
+
+			it("unsets the limit of an app", func() {
+				cfg := config.Config{
+					CacheSizeLimit:  "5GB",
+					CacheSizeLimits: map[string]string{"index.docker.io/library/my-app:latest": "1GB"},
+				}
+				h.AssertNil(t, newCommand(cfg, "--unset", "--app", "my-app").Execute())
+
+				cfg, err := config.Read(configPath)
+				h.AssertNil(t, err)
+				h.AssertEq(t, cfg.CacheSizeLimit, "5GB")
+				h.AssertEq(t, len(cfg.CacheSizeLimits), 0)
+			})
+
+			it("errors when a size is also given", func() {
+				err := newCommand(config.Config{}, "--unset", "1GB").Execute()
+				h.AssertError(t, err, "size and --unset cannot be specified simultaneously")
+			})
+		})
+	})
+}
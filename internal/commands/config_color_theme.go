@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+func ConfigColorTheme(logger logging.Logger, cfg config.Config, cfgPath string) *cobra.Command {
+	var unset bool
+
+	cmd := &cobra.Command{
+		Use:   fmt.Sprintf("color-theme <%s>", strings.Join(style.Themes(), " | ")),
+		Args:  cobra.MaximumNArgs(1),
+		Short: "List, set and unset the color theme used for output",
+		Long: "You can use this command to list, set, and unset the default color theme used when writing colored output:\n" +
+			"* To list your color theme, run `pack config color-theme`.\n" +
+			fmt.Sprintf("* To set your color theme, run `pack config color-theme <%s>`.\n", strings.Join(style.Themes(), " | ")) +
+			"* To unset your color theme, run `pack config color-theme --unset`.\n" +
+			"The theme may be overridden for a single command with the `--color-theme` flag.\n" +
+			"Colors are never used when the NO_COLOR environment variable is set, regardless of the theme.",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			switch {
+			case unset:
+				if len(args) > 0 {
+					return errors.Errorf("color theme and --unset cannot be specified simultaneously")
+				}
+				oldTheme := cfg.ColorTheme
+				cfg.ColorTheme = ""
+				if err := config.Write(cfg, cfgPath); err != nil {
+					return errors.Wrapf(err, "writing config to %s", cfgPath)
+				}
+
+				logger.Infof("Successfully unset color theme %s", style.Symbol(oldTheme))
+				logger.Infof("Color theme has been set to %s", style.Symbol(style.DefaultTheme))
+			case len(args) == 0: // list
+				theme := cfg.ColorTheme
+				if theme == "" {
+					theme = style.DefaultTheme
+				}
+
+				logger.Infof("The current color theme is %s", style.Symbol(theme))
+			default: // set
+				newTheme := args[0]
+
+				if newTheme == cfg.ColorTheme {
+					logger.Infof("Color theme is already set to %s", style.Symbol(newTheme))
+					return nil
+				}
+
+				if err := style.ValidateTheme(newTheme); err != nil {
+					return err
+				}
+
+				cfg.ColorTheme = newTheme
+				if err := config.Write(cfg, cfgPath); err != nil {
+					return errors.Wrapf(err, "writing config to %s", cfgPath)
+				}
+
+				logger.Infof("Successfully set %s as the color theme", style.Symbol(newTheme))
+			}
+
+			return nil
+		}),
+	}
+
+	cmd.Flags().BoolVarP(&unset, "unset", "u", false, "Unset color theme, and set it back to the default color theme, which is "+style.Symbol(style.DefaultTheme))
+	AddHelpFlag(cmd, "color-theme")
+	return cmd
+}
@@ -0,0 +1,133 @@
+package commands_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestConfigColorTheme(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "ConfigColorThemeCommand", testConfigColorThemeCommand, spec.Random(), spec.Report(report.Terminal{}))
+}
+
+func testConfigColorThemeCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command      *cobra.Command
+		logger       logging.Logger
+		outBuf       bytes.Buffer
+		tempPackHome string
+		configFile   string
+		assert       = h.NewAssertionManager(t)
+		cfg          = config.Config{}
+	)
+
+	it.Before(func() {
+		var err error
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		tempPackHome, err = os.MkdirTemp("", "pack-home")
+		h.AssertNil(t, err)
+		configFile = filepath.Join(tempPackHome, "config.toml")
+
+		command = commands.ConfigColorTheme(logger, cfg, configFile)
+		command.SetOut(logging.GetWriterForLevel(logger, logging.InfoLevel))
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.RemoveAll(tempPackHome))
+	})
+
+	when("#ConfigColorTheme", func() {
+		when("list", func() {
+			when("no theme is configured", func() {
+				it("lists the default theme", func() {
+					command.SetArgs([]string{})
+
+					h.AssertNil(t, command.Execute())
+
+					assert.Contains(outBuf.String(), "The current color theme is 'default'")
+				})
+			})
+
+			when("a theme is configured", func() {
+				it("lists the configured theme", func() {
+					command = commands.ConfigColorTheme(logger, config.Config{ColorTheme: "monochrome"}, configFile)
+					command.SetArgs([]string{})
+
+					h.AssertNil(t, command.Execute())
+
+					assert.Contains(outBuf.String(), "The current color theme is 'monochrome'")
+				})
+			})
+		})
+
+		when("set", func() {
+			when("a valid theme is specified", func() {
+				it("sets the theme in config", func() {
+					command.SetArgs([]string{"high-contrast"})
+					assert.Succeeds(command.Execute())
+
+					readCfg, err := config.Read(configFile)
+					assert.Nil(err)
+					assert.Equal(readCfg.ColorTheme, "high-contrast")
+				})
+			})
+
+			when("the theme is already configured", func() {
+				it("provides a helpful message", func() {
+					command = commands.ConfigColorTheme(logger, config.Config{ColorTheme: "monochrome"}, configFile)
+					command.SetArgs([]string{"monochrome"})
+
+					h.AssertNil(t, command.Execute())
+
+					h.AssertEq(t, strings.TrimSpace(outBuf.String()), `Color theme is already set to 'monochrome'`)
+				})
+			})
+
+			when("an invalid theme is specified", func() {
+				it("does not write the theme to config", func() {
+					command.SetArgs([]string{"neon"})
+
+					h.AssertError(t, command.Execute(), "unknown color theme 'neon'")
+
+					readCfg, err := config.Read(configFile)
+					assert.Nil(err)
+					assert.Equal(readCfg.ColorTheme, "")
+				})
+			})
+		})
+
+		when("unset", func() {
+			it("removes the configured theme", func() {
+				command = commands.ConfigColorTheme(logger, config.Config{ColorTheme: "monochrome"}, configFile)
+				command.SetArgs([]string{"--unset"})
+				assert.Succeeds(command.Execute())
+
+				readCfg, err := config.Read(configFile)
+				assert.Nil(err)
+				assert.Equal(readCfg.ColorTheme, "")
+				assert.Contains(outBuf.String(), "Color theme has been set to 'default'")
+			})
+		})
+
+		when("--unset and a theme to set are provided", func() {
+			it("errors", func() {
+				command.SetArgs([]string{"monochrome", "--unset"})
+				h.AssertError(t, command.Execute(), "color theme and --unset cannot be specified simultaneously")
+			})
+		})
+	})
+}
@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"net/url"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+// dockerHostSchemes are the schemes of the addresses of daemons pack can reach
+var dockerHostSchemes = []string{"unix", "tcp", "ssh", "npipe"}
+
+func ConfigDockerHost(logger logging.Logger, cfg config.Config, cfgPath string) *cobra.Command {
+	var unset bool
+
+	cmd := &cobra.Command{
+		Use:   "docker-host <docker-host>",
+		Args:  cobra.MaximumNArgs(1),
+		Short: "List, set and unset the daemon pack uses when DOCKER_HOST isn't set",
+		Long: "Builds and builders use the daemon of the DOCKER_HOST environment variable, or of the current docker context, " +
+			"unless a docker host is set. The daemon may be on another host, reached over SSH, in which case the lifecycle " +
+			"containers run on that host and the app is sent to it gzipped. Set 'podman' to use the socket of Podman.\n\n" +
+			"* Running `pack config docker-host` prints the docker host.\n" +
+			"* Running `pack config docker-host <docker-host>` sets the docker host.\n" +
+			"* Running `pack config docker-host --unset` goes back to the daemon of the environment.",
+		Example: "pack config docker-host ssh://user@build-host",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			switch {
+			case unset:
+				if len(args) > 0 {
+					return errors.Errorf("docker host and --unset cannot be specified simultaneously")
+				}
+
+				if cfg.DockerHost == "" {
+					logger.Info("No docker host was set.")
+					return nil
+				}
+				cfg.DockerHost = ""
+				if err := config.Write(cfg, cfgPath); err != nil {
+					return errors.Wrapf(err, "failed to write to config at %s", cfgPath)
+				}
+				logger.Info("Successfully unset the docker host, the daemon of the environment will be used")
+			case len(args) == 0:
+				if cfg.DockerHost == "" {
+					logger.Info("No docker host is set, the daemon of the environment is used")
+					return nil
+				}
+				logger.Infof("The docker host is %s", style.Symbol(cfg.DockerHost))
+			default:
+				if err := validateDockerHost(args[0]); err != nil {
+					return err
+				}
+
+				cfg.DockerHost = args[0]
+				if err := config.Write(cfg, cfgPath); err != nil {
+					return errors.Wrapf(err, "failed to write to config at %s", cfgPath)
+				}
+				logger.Infof("The docker host will now be %s, unless DOCKER_HOST is set", style.Symbol(cfg.DockerHost))
+			}
+
+			return nil
+		}),
+	}
+
+	cmd.Flags().BoolVarP(&unset, "unset", "u", false, "Unset the docker host, going back to the daemon of the environment")
+	AddHelpFlag(cmd, "docker-host")
+	return cmd
+}
+
+func validateDockerHost(dockerHost string) error {
+	if dockerHost == client.PodmanDockerHost {
+		return nil
+	}
+
+	address, err := url.Parse(dockerHost)
+	if err == nil {
+		for _, scheme := range dockerHostSchemes {
+			if address.Scheme == scheme {
+				return nil
+			}
+		}
+	}
+	return errors.Errorf("invalid docker host %s, must be 'podman' or an address such as unix:///var/run/docker.sock or ssh://user@build-host", style.Symbol(dockerHost))
+}
@@ -0,0 +1,107 @@
+package commands_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestConfigDockerHost(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "ConfigDockerHost", testConfigDockerHost, spec.Random(), spec.Report(report.Terminal{}))
+}
+
+func testConfigDockerHost(t *testing.T, when spec.G, it spec.S) {
+	var (
+		logger       logging.Logger
+		outBuf       bytes.Buffer
+		tempPackHome string
+		configPath   string
+	)
+
+	it.Before(func() {
+		var err error
+
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		tempPackHome, err = os.MkdirTemp("", "pack-home")
+		h.AssertNil(t, err)
+		configPath = filepath.Join(tempPackHome, "config.toml")
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.RemoveAll(tempPackHome))
+	})
+
+	newCommand := func(cfg config.Config, args ...string) *cobra.Command {
+		cmd := commands.ConfigDockerHost(logger, cfg, configPath)
+		cmd.SetOut(logging.GetWriterForLevel(logger, logging.InfoLevel))
+		cmd.SetArgs(args)
+		return cmd
+	}
+
+	when("#ConfigDockerHost", func() {
+		when("list", func() {
+			it("says when no docker host is set", func() {
+				h.AssertNil(t, newCommand(config.Config{}).Execute())
+				h.AssertContains(t, outBuf.String(), "No docker host is set")
+			})
+
+			it("prints the docker host", func() {
+				h.AssertNil(t, newCommand(config.Config{DockerHost: "ssh://user@build-host"}).Execute())
+				h.AssertContains(t, outBuf.String(), "The docker host is 'ssh://user@build-host'")
+			})
+		})
+
+		when("set", func() {
+			it("sets a remote docker host", func() {
+				h.AssertNil(t, newCommand(config.Config{}, "ssh://user@build-host").Execute())
+
+				cfg, err := config.Read(configPath)
+				h.AssertNil(t, err)
+				h.AssertEq(t, cfg.DockerHost, "ssh://user@build-host")
+				h.AssertContains(t, outBuf.String(), "The docker host will now be 'ssh://user@build-host', unless DOCKER_HOST is set")
+			})
+
+			it("sets podman", func() {
+				h.AssertNil(t, newCommand(config.Config{}, "podman").Execute())
+
+				cfg, err := config.Read(configPath)
+				h.AssertNil(t, err)
+				h.AssertEq(t, cfg.DockerHost, "podman")
+			})
+
+			it("fails for an invalid docker host", func() {
+				err := newCommand(config.Config{}, "build-host:2375").Execute()
+				h.AssertError(t, err, "invalid docker host 'build-host:2375'")
+			})
+		})
+
+		when("unset", func() {
+			it("unsets the docker host", func() {
+				h.AssertNil(t, newCommand(config.Config{DockerHost: "ssh://user@build-host"}, "--unset").Execute())
+
+				cfg, err := config.Read(configPath)
+				h.AssertNil(t, err)
+				h.AssertEq(t, cfg.DockerHost, "")
+				h.AssertContains(t, outBuf.String(), "Successfully unset the docker host")
+			})
+
+			it("errors when a docker host is also given", func() {
+				err := newCommand(config.Config{}, "--unset", "ssh://user@build-host").Execute()
+				h.AssertError(t, err, "docker host and --unset cannot be specified simultaneously")
+			})
+		})
+	})
+}
@@ -0,0 +1,70 @@
+package commands
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+func ConfigDownloadWorkers(logger logging.Logger, cfg config.Config, cfgPath string) *cobra.Command {
+	var unset bool
+
+	cmd := &cobra.Command{
+		Use:   "download-workers <workers>",
+		Args:  cobra.MaximumNArgs(1),
+		Short: "List, set and unset how many buildpacks and extensions are downloaded at once",
+		Long: "The buildpacks and extensions of `pack build`, `pack builder create` and `pack buildpack package` are " +
+			"downloaded this many at a time, from images, URIs and registries alike.\n\n" +
+			"* Running `pack config download-workers` prints the number of workers.\n" +
+			"* Running `pack config download-workers <workers>` sets the number of workers.\n" +
+			"* Running `pack config download-workers --unset` goes back to the default of " + strconv.Itoa(client.DefaultDownloadWorkers) + ".",
+		Example: "pack config download-workers 8",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			switch {
+			case unset:
+				if len(args) > 0 {
+					return errors.Errorf("workers and --unset cannot be specified simultaneously")
+				}
+
+				if cfg.DownloadWorkers == 0 {
+					logger.Info("No download workers were set.")
+					return nil
+				}
+				cfg.DownloadWorkers = 0
+				if err := config.Write(cfg, cfgPath); err != nil {
+					return errors.Wrapf(err, "failed to write to config at %s", cfgPath)
+				}
+				logger.Infof("Successfully unset the download workers, %d buildpacks and extensions are downloaded at once", client.DefaultDownloadWorkers)
+			case len(args) == 0:
+				workers := cfg.DownloadWorkers
+				if workers < 1 {
+					workers = client.DefaultDownloadWorkers
+				}
+				logger.Infof("%s buildpacks and extensions are downloaded at once", style.Symbol(strconv.Itoa(workers)))
+			default:
+				workers, err := strconv.Atoi(args[0])
+				if err != nil || workers < 1 {
+					return errors.Errorf("invalid workers %s, must be a number of at least 1", style.Symbol(args[0]))
+				}
+
+				cfg.DownloadWorkers = workers
+				if err := config.Write(cfg, cfgPath); err != nil {
+					return errors.Wrapf(err, "failed to write to config at %s", cfgPath)
+				}
+				logger.Infof("%s buildpacks and extensions will now be downloaded at once", style.Symbol(strconv.Itoa(workers)))
+			}
+
+			return nil
+		}),
+	}
+
+	cmd.Flags().BoolVarP(&unset, "unset", "u", false, "Unset the workers, going back to the default")
+	AddHelpFlag(cmd, "download-workers")
+	return cmd
+}
@@ -0,0 +1,102 @@
+package commands_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestConfigDownloadWorkers(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "ConfigDownloadWorkers", testConfigDownloadWorkers, spec.Random(), spec.Report(report.Terminal{}))
+}
+
+func testConfigDownloadWorkers(t *testing.T, when spec.G, it spec.S) {
+	var (
+		logger       logging.Logger
+		outBuf       bytes.Buffer
+		tempPackHome string
+		configPath   string
+	)
+
+	it.Before(func() {
+		var err error
+
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		tempPackHome, err = os.MkdirTemp("", "pack-home")
+		h.AssertNil(t, err)
+		configPath = filepath.Join(tempPackHome, "config.toml")
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.RemoveAll(tempPackHome))
+	})
+
+	newCommand := func(cfg config.Config, args ...string) *cobra.Command {
+		cmd := commands.ConfigDownloadWorkers(logger, cfg, configPath)
+		cmd.SetOut(logging.GetWriterForLevel(logger, logging.InfoLevel))
+		cmd.SetArgs(args)
+		return cmd
+	}
+
+	when("#ConfigDownloadWorkers", func() {
+		when("list", func() {
+			it("prints the default workers when none are set", func() {
+				h.AssertNil(t, newCommand(config.Config{}).Execute())
+				h.AssertContains(t, outBuf.String(), "'4' buildpacks and extensions are downloaded at once")
+			})
+
+			it("prints the workers", func() {
+				h.AssertNil(t, newCommand(config.Config{DownloadWorkers: 8}).Execute())
+				h.AssertContains(t, outBuf.String(), "'8' buildpacks and extensions are downloaded at once")
+			})
+		})
+
+		when("set", func() {
+			it("sets the workers", func() {
+				h.AssertNil(t, newCommand(config.Config{}, "8").Execute())
+
+				cfg, err := config.Read(configPath)
+				h.AssertNil(t, err)
+				h.AssertEq(t, cfg.DownloadWorkers, 8)
+				h.AssertContains(t, outBuf.String(), "'8' buildpacks and extensions will now be downloaded at once")
+			})
+
+			it("fails for invalid workers", func() {
+				err := newCommand(config.Config{}, "0").Execute()
+				h.AssertError(t, err, "invalid workers '0', must be a number of at least 1")
+
+				err = newCommand(config.Config{}, "many").Execute()
+				h.AssertError(t, err, "invalid workers 'many'")
+			})
+		})
+
+		when("unset", func() {
+			it("unsets the workers", func() {
+				h.AssertNil(t, newCommand(config.Config{DownloadWorkers: 8}, "--unset").Execute())
+
+				cfg, err := config.Read(configPath)
+				h.AssertNil(t, err)
+				h.AssertEq(t, cfg.DownloadWorkers, 0)
+				h.AssertContains(t, outBuf.String(), "Successfully unset the download workers")
+			})
+
+			it("errors when workers are also given", func() {
+				err := newCommand(config.Config{}, "--unset", "8").Execute()
+				h.AssertError(t, err, "workers and --unset cannot be specified simultaneously")
+			})
+		})
+	})
+}
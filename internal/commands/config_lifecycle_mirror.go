@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"net/url"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/lifecycle"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+func ConfigLifecycleMirror(logger logging.Logger, cfg config.Config, cfgPath string) *cobra.Command {
+	var unset bool
+
+	cmd := &cobra.Command{
+		Use:   "lifecycle-mirror <mirror-url>",
+		Args:  cobra.MaximumNArgs(1),
+		Short: "List, set and unset the mirror the lifecycle is downloaded from when creating builders",
+		Long: "Builders get the lifecycle version of their config from " + lifecycle.DefaultMirror + ", unless a lifecycle mirror is set. " +
+			"A mirror serves the archive of each version at <mirror-url>/v<version>/lifecycle-v<version>+<os>.<arch>.tgz, " +
+			"along with its checksum at the same url ending in .sha256. Archives are verified against their checksum and kept " +
+			"under PACK_HOME, so each version is downloaded once. To pull the lifecycle image from a mirror, use `pack config lifecycle-image`.\n\n" +
+			"* Running `pack config lifecycle-mirror` prints the mirror.\n" +
+			"* Running `pack config lifecycle-mirror <mirror-url>` sets the mirror.\n" +
+			"* Running `pack config lifecycle-mirror --unset` goes back to the releases of the lifecycle.",
+		Example: "pack config lifecycle-mirror https://artifacts.example.com/lifecycle/releases",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			switch {
+			case unset:
+				if len(args) > 0 {
+					return errors.Errorf("mirror url and --unset cannot be specified simultaneously")
+				}
+
+				if cfg.LifecycleMirror == "" {
+					logger.Info("No lifecycle mirror was set.")
+					return nil
+				}
+				cfg.LifecycleMirror = ""
+				if err := config.Write(cfg, cfgPath); err != nil {
+					return errors.Wrapf(err, "failed to write to config at %s", cfgPath)
+				}
+				logger.Infof("Successfully unset the lifecycle mirror, the lifecycle is downloaded from %s", style.Symbol(lifecycle.DefaultMirror))
+			case len(args) == 0:
+				if cfg.LifecycleMirror == "" {
+					logger.Infof("No lifecycle mirror is set, the lifecycle is downloaded from %s", style.Symbol(lifecycle.DefaultMirror))
+					return nil
+				}
+				logger.Infof("The lifecycle is downloaded from %s", style.Symbol(redactedURL(cfg.LifecycleMirror)))
+			default:
+				mirror, err := url.Parse(args[0])
+				if err != nil || (mirror.Scheme != "http" && mirror.Scheme != "https") || mirror.Host == "" {
+					return errors.Errorf("invalid mirror url %s, must be a url such as https://artifacts.example.com/lifecycle/releases", style.Symbol(args[0]))
+				}
+
+				cfg.LifecycleMirror = args[0]
+				if err := config.Write(cfg, cfgPath); err != nil {
+					return errors.Wrapf(err, "failed to write to config at %s", cfgPath)
+				}
+				logger.Infof("The lifecycle will now be downloaded from %s", style.Symbol(mirror.Redacted()))
+			}
+
+			return nil
+		}),
+	}
+
+	cmd.Flags().BoolVarP(&unset, "unset", "u", false, "Unset the mirror, going back to the releases of the lifecycle")
+	AddHelpFlag(cmd, "lifecycle-mirror")
+	return cmd
+}
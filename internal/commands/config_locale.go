@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/i18n"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+func ConfigLocale(logger logging.Logger, cfg config.Config, cfgPath string) *cobra.Command {
+	var unset bool
+
+	cmd := &cobra.Command{
+		Use:   fmt.Sprintf("locale <%s>", strings.Join(i18n.Locales(), " | ")),
+		Args:  cobra.MaximumNArgs(1),
+		Short: "List, set and unset the locale used for messages",
+		Long: "You can use this command to list, set, and unset the locale used for messages:\n" +
+			"* To list your locale, run `pack config locale`.\n" +
+			fmt.Sprintf("* To set your locale, run `pack config locale <%s>`.\n", strings.Join(i18n.Locales(), " | ")) +
+			"* To unset your locale, run `pack config locale --unset`.\n" +
+			"When no locale is set, it is taken from the LC_ALL, LC_MESSAGES or LANG environment variables.\n" +
+			"The PACK_LOCALE environment variable overrides the configured locale.",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			switch {
+			case unset:
+				if len(args) > 0 {
+					return errors.Errorf("locale and --unset cannot be specified simultaneously")
+				}
+				oldLocale := cfg.Locale
+				cfg.Locale = ""
+				if err := config.Write(cfg, cfgPath); err != nil {
+					return errors.Wrapf(err, "writing config to %s", cfgPath)
+				}
+
+				logger.Infof("Successfully unset locale %s", style.Symbol(oldLocale))
+			case len(args) == 0: // list
+				if cfg.Locale == "" {
+					logger.Info("No locale is set, the locale is taken from the environment")
+					return nil
+				}
+
+				logger.Infof("The current locale is %s", style.Symbol(cfg.Locale))
+			default: // set
+				newLocale := args[0]
+
+				if newLocale == cfg.Locale {
+					logger.Infof("Locale is already set to %s", style.Symbol(newLocale))
+					return nil
+				}
+
+				if err := i18n.ValidateLocale(newLocale); err != nil {
+					return err
+				}
+
+				cfg.Locale = newLocale
+				if err := config.Write(cfg, cfgPath); err != nil {
+					return errors.Wrapf(err, "writing config to %s", cfgPath)
+				}
+
+				logger.Infof("Successfully set %s as the locale", style.Symbol(newLocale))
+			}
+
+			return nil
+		}),
+	}
+
+	cmd.Flags().BoolVarP(&unset, "unset", "u", false, "Unset locale, so that it is taken from the environment")
+	AddHelpFlag(cmd, "locale")
+	return cmd
+}
@@ -0,0 +1,133 @@
+package commands_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestConfigLocale(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "ConfigLocaleCommand", testConfigLocaleCommand, spec.Random(), spec.Report(report.Terminal{}))
+}
+
+func testConfigLocaleCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command      *cobra.Command
+		logger       logging.Logger
+		outBuf       bytes.Buffer
+		tempPackHome string
+		configFile   string
+		assert       = h.NewAssertionManager(t)
+		cfg          = config.Config{}
+	)
+
+	it.Before(func() {
+		var err error
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		tempPackHome, err = os.MkdirTemp("", "pack-home")
+		h.AssertNil(t, err)
+		configFile = filepath.Join(tempPackHome, "config.toml")
+
+		command = commands.ConfigLocale(logger, cfg, configFile)
+		command.SetOut(logging.GetWriterForLevel(logger, logging.InfoLevel))
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.RemoveAll(tempPackHome))
+	})
+
+	when("#ConfigLocale", func() {
+		when("list", func() {
+			when("no locale is configured", func() {
+				it("explains that the locale is taken from the environment", func() {
+					command.SetArgs([]string{})
+
+					h.AssertNil(t, command.Execute())
+
+					assert.Contains(outBuf.String(), "No locale is set, the locale is taken from the environment")
+				})
+			})
+
+			when("a locale is configured", func() {
+				it("lists the configured locale", func() {
+					command = commands.ConfigLocale(logger, config.Config{Locale: "es"}, configFile)
+					command.SetArgs([]string{})
+
+					h.AssertNil(t, command.Execute())
+
+					assert.Contains(outBuf.String(), "The current locale is 'es'")
+				})
+			})
+		})
+
+		when("set", func() {
+			when("a supported locale is specified", func() {
+				it("sets the locale in config", func() {
+					command.SetArgs([]string{"es"})
+					assert.Succeeds(command.Execute())
+
+					readCfg, err := config.Read(configFile)
+					assert.Nil(err)
+					assert.Equal(readCfg.Locale, "es")
+				})
+			})
+
+			when("the locale is already configured", func() {
+				it("provides a helpful message", func() {
+					command = commands.ConfigLocale(logger, config.Config{Locale: "es"}, configFile)
+					command.SetArgs([]string{"es"})
+
+					h.AssertNil(t, command.Execute())
+
+					h.AssertEq(t, strings.TrimSpace(outBuf.String()), `Locale is already set to 'es'`)
+				})
+			})
+
+			when("an unsupported locale is specified", func() {
+				it("does not write the locale to config", func() {
+					command.SetArgs([]string{"xx"})
+
+					h.AssertError(t, command.Execute(), "unsupported locale 'xx'")
+
+					readCfg, err := config.Read(configFile)
+					assert.Nil(err)
+					assert.Equal(readCfg.Locale, "")
+				})
+			})
+		})
+
+		when("unset", func() {
+			it("removes the configured locale", func() {
+				command = commands.ConfigLocale(logger, config.Config{Locale: "es"}, configFile)
+				command.SetArgs([]string{"--unset"})
+				assert.Succeeds(command.Execute())
+
+				readCfg, err := config.Read(configFile)
+				assert.Nil(err)
+				assert.Equal(readCfg.Locale, "")
+				assert.Contains(outBuf.String(), "Successfully unset locale 'es'")
+			})
+		})
+
+		when("--unset and a locale to set are provided", func() {
+			it("errors", func() {
+				command.SetArgs([]string{"es", "--unset"})
+				h.AssertError(t, command.Execute(), "locale and --unset cannot be specified simultaneously")
+			})
+		})
+	})
+}
@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/policy"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+func ConfigPolicy(logger logging.Logger, cfg config.Config, cfgPath string) *cobra.Command {
+	var unset bool
+
+	cmd := &cobra.Command{
+		Use:   "policy <policy-file>",
+		Args:  cobra.MaximumNArgs(1),
+		Short: "Configure a policy that builds are checked against",
+		Long: "Set a policy file that every `pack build` is checked against before the lifecycle runs. A policy is a TOML " +
+			"file of rules that allow, deny or warn about the builder, run image, buildpacks, image and registries of a " +
+			"build. For each of these, the first rule of its subject matching it decides; values no rule matches get " +
+			"the default action, allow unless set otherwise. Builds using a denied value fail.\n\n" +
+			"Example policy:\n\n" +
+			"    default = \"deny\"\n\n" +
+			"    [[rules]]\n" +
+			"    action = \"allow\"\n" +
+			"    subject = \"registry\"      # builder, run-image, buildpack, image or registry\n" +
+			"    match = \"registry.example.com\"\n\n" +
+			"    [[rules]]\n" +
+			"    action = \"warn\"\n" +
+			"    subject = \"buildpack\"\n" +
+			"    match = \"example/legacy@*\"\n" +
+			"    message = \"example/legacy is deprecated\"",
+		Example: "pack config policy ./policy.toml",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			switch {
+			case unset:
+				if len(args) > 0 {
+					return errors.Errorf("policy file and --unset cannot be specified simultaneously")
+				}
+
+				if cfg.Policy == "" {
+					logger.Info("No policy was set.")
+				} else {
+					oldPolicy := cfg.Policy
+					cfg.Policy = ""
+					if err := config.Write(cfg, cfgPath); err != nil {
+						return errors.Wrapf(err, "failed to write to config at %s", cfgPath)
+					}
+					logger.Infof("Successfully unset policy %s", style.Symbol(oldPolicy))
+				}
+			case len(args) == 0:
+				if cfg.Policy != "" {
+					logger.Infof("Builds are checked against the policy %s", style.Symbol(cfg.Policy))
+				} else {
+					logger.Info("No policy is set.")
+				}
+			default:
+				policyPath, err := filepath.Abs(args[0])
+				if err != nil {
+					return err
+				}
+				if _, err := policy.Read(policyPath); err != nil {
+					return errors.Wrapf(err, "invalid policy %s", style.Symbol(policyPath))
+				}
+
+				cfg.Policy = policyPath
+				if err := config.Write(cfg, cfgPath); err != nil {
+					return errors.Wrapf(err, "failed to write to config at %s", cfgPath)
+				}
+				logger.Infof("Builds will now be checked against the policy %s", style.Symbol(policyPath))
+			}
+
+			return nil
+		}),
+	}
+
+	cmd.Flags().BoolVarP(&unset, "unset", "u", false, "Unset the policy, so that builds aren't checked")
+	AddHelpFlag(cmd, "policy")
+	return cmd
+}
@@ -0,0 +1,111 @@
+package commands_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestConfigPolicy(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "ConfigPolicy", testConfigPolicyCommand, spec.Random(), spec.Report(report.Terminal{}))
+}
+
+func testConfigPolicyCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command      *cobra.Command
+		logger       logging.Logger
+		outBuf       bytes.Buffer
+		tempPackHome string
+		configFile   string
+		policyFile   string
+		cfg          = config.Config{}
+	)
+
+	it.Before(func() {
+		var err error
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		tempPackHome, err = os.MkdirTemp("", "pack-home")
+		h.AssertNil(t, err)
+		configFile = filepath.Join(tempPackHome, "config.toml")
+		policyFile = filepath.Join(tempPackHome, "policy.toml")
+		h.AssertNil(t, os.WriteFile(policyFile, []byte(`default = "warn"`), 0600))
+
+		command = commands.ConfigPolicy(logger, cfg, configFile)
+		command.SetOut(logging.GetWriterForLevel(logger, logging.InfoLevel))
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.RemoveAll(tempPackHome))
+	})
+
+	when("#ConfigPolicy", func() {
+		when("list", func() {
+			it("says when no policy is set", func() {
+				command.SetArgs([]string{})
+
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), "No policy is set")
+			})
+
+			it("lists the policy", func() {
+				cfg.Policy = policyFile
+				command = commands.ConfigPolicy(logger, cfg, configFile)
+				command.SetArgs([]string{})
+
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), policyFile)
+			})
+		})
+
+		when("set", func() {
+			it("sets the policy", func() {
+				command.SetArgs([]string{policyFile})
+
+				h.AssertNil(t, command.Execute())
+				readCfg, err := config.Read(configFile)
+				h.AssertNil(t, err)
+				h.AssertEq(t, readCfg.Policy, policyFile)
+			})
+
+			it("fails for an invalid policy", func() {
+				h.AssertNil(t, os.WriteFile(policyFile, []byte(`default = "maybe"`), 0600))
+				command.SetArgs([]string{policyFile})
+
+				h.AssertError(t, command.Execute(), "invalid default action 'maybe'")
+				h.AssertPathDoesNotExists(t, configFile)
+			})
+		})
+
+		when("unset", func() {
+			it("unsets the policy", func() {
+				cfg.Policy = policyFile
+				command = commands.ConfigPolicy(logger, cfg, configFile)
+				command.SetArgs([]string{"--unset"})
+
+				h.AssertNil(t, command.Execute())
+				readCfg, err := config.Read(configFile)
+				h.AssertNil(t, err)
+				h.AssertEq(t, readCfg.Policy, "")
+			})
+
+			it("fails if a policy file is also specified", func() {
+				command.SetArgs([]string{policyFile, "--unset"})
+
+				h.AssertError(t, command.Execute(), "policy file and --unset cannot be specified simultaneously")
+			})
+		})
+	})
+}
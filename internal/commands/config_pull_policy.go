@@ -2,6 +2,7 @@ package commands
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -12,8 +13,14 @@ import (
 	"github.com/buildpacks/pack/pkg/logging"
 )
 
+// pullPolicyImages are the images of builds whose pull policy can be set apart from the pull policy
+var pullPolicyImages = []string{"builder", "run-image", "buildpacks"}
+
 func ConfigPullPolicy(logger logging.Logger, cfg config.Config, cfgPath string) *cobra.Command {
-	var unset bool
+	var (
+		unset     bool
+		imageKind string
+	)
 
 	cmd := &cobra.Command{
 		Use:   "pull-policy <always | if-not-present | never>",
@@ -23,8 +30,15 @@ func ConfigPullPolicy(logger logging.Logger, cfg config.Config, cfgPath string)
 			"* To list your pull policy, run `pack config pull-policy`.\n" +
 			"* To set your pull policy, run `pack config pull-policy <always | if-not-present | never>`.\n" +
 			"* To unset your pull policy, run `pack config pull-policy --unset`.\n" +
-			fmt.Sprintf("Unsetting the pull policy will reset the policy to the default, which is %s", style.Symbol("always")),
+			fmt.Sprintf("Unsetting the pull policy will reset the policy to the default, which is %s\n\n", style.Symbol("always")) +
+			"With `--image <builder | run-image | buildpacks>`, the commands apply to the pull policy of that image in builds, " +
+			"which is the pull policy unless set. The `--pull-policy` flag of `pack build` takes precedence over the pull policies of images.",
+		Example: "pack config pull-policy always --image run-image",
 		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			if cmd.Flags().Changed("image") {
+				return configImagePullPolicy(logger, cfg, cfgPath, imageKind, unset, args)
+			}
+
 			switch {
 			case unset:
 				if len(args) > 0 {
@@ -50,6 +64,11 @@ func ConfigPullPolicy(logger logging.Logger, cfg config.Config, cfgPath string)
 				}
 
 				logger.Infof("The current pull policy is %s", style.Symbol(pullPolicy.String()))
+				for _, kind := range pullPolicyImages {
+					if policy := *imagePullPolicy(&cfg, kind); policy != "" {
+						logger.Infof("The pull policy of the %s is %s", kind, style.Symbol(policy))
+					}
+				}
 			default: // set
 				newPullPolicy := args[0]
 
@@ -76,6 +95,61 @@ func ConfigPullPolicy(logger logging.Logger, cfg config.Config, cfgPath string)
 	}
 
 	cmd.Flags().BoolVarP(&unset, "unset", "u", false, "Unset pull policy, and set it back to the default pull-policy, which is "+style.Symbol("always"))
+	cmd.Flags().StringVar(&imageKind, "image", "", fmt.Sprintf("Image of builds to list, set or unset the pull policy of, one of %s", strings.Join(pullPolicyImages, ", ")))
 	AddHelpFlag(cmd, "pull-policy")
 	return cmd
 }
+
+// configImagePullPolicy lists, sets or unsets the pull policy of kind, the images of builds it's one of
+func configImagePullPolicy(logger logging.Logger, cfg config.Config, cfgPath, kind string, unset bool, args []string) error {
+	target := imagePullPolicy(&cfg, kind)
+	if target == nil {
+		return errors.Errorf("invalid image %s, must be one of %s", style.Symbol(kind), strings.Join(pullPolicyImages, ", "))
+	}
+
+	switch {
+	case unset:
+		if len(args) > 0 {
+			return errors.Errorf("pull policy and --unset cannot be specified simultaneously")
+		}
+		if *target == "" {
+			logger.Infof("The pull policy of the %s wasn't set", kind)
+			return nil
+		}
+		*target = ""
+		if err := config.Write(cfg, cfgPath); err != nil {
+			return errors.Wrapf(err, "writing config to %s", cfgPath)
+		}
+		logger.Infof("Successfully unset the pull policy of the %s, which is the pull policy again", kind)
+	case len(args) == 0: // list
+		if *target == "" {
+			logger.Infof("The pull policy of the %s isn't set, it's the pull policy", kind)
+			return nil
+		}
+		logger.Infof("The pull policy of the %s is %s", kind, style.Symbol(*target))
+	default: // set
+		pullPolicy, err := image.ParsePullPolicy(args[0])
+		if err != nil {
+			return err
+		}
+		*target = pullPolicy.String()
+		if err := config.Write(cfg, cfgPath); err != nil {
+			return errors.Wrapf(err, "writing config to %s", cfgPath)
+		}
+		logger.Infof("Successfully set %s as the pull policy of the %s", style.Symbol(pullPolicy.String()), kind)
+	}
+	return nil
+}
+
+// imagePullPolicy is the field of the config holding the pull policy of kind, nil for an unknown kind
+func imagePullPolicy(cfg *config.Config, kind string) *string {
+	switch kind {
+	case "builder":
+		return &cfg.PullPolicies.Builder
+	case "run-image":
+		return &cfg.PullPolicies.RunImage
+	case "buildpacks":
+		return &cfg.PullPolicies.Buildpacks
+	}
+	return nil
+}
@@ -189,5 +189,53 @@ func testConfigPullPolicyCommand(t *testing.T, when spec.G, it spec.S) {
 				h.AssertError(t, err, `pull policy and --unset cannot be specified simultaneously`)
 			})
 		})
+		when("--image", func() {
+			it("sets the pull policy of the image", func() {
+				command.SetArgs([]string{"if-not-present", "--image", "builder"})
+				assert.Succeeds(command.Execute())
+				assert.Contains(outBuf.String(), "Successfully set 'if-not-present' as the pull policy of the builder")
+
+				readCfg, err := config.Read(configFile)
+				assert.Nil(err)
+				assert.Equal(readCfg.PullPolicies, config.PullPolicies{Builder: "if-not-present"})
+				assert.Equal(readCfg.PullPolicy, "")
+			})
+
+			it("lists the pull policy of the image", func() {
+				command = commands.ConfigPullPolicy(logger, config.Config{PullPolicies: config.PullPolicies{RunImage: "always"}}, configFile)
+				command.SetArgs([]string{"--image", "run-image"})
+				assert.Succeeds(command.Execute())
+				assert.Contains(outBuf.String(), "The pull policy of the run-image is 'always'")
+			})
+
+			it("lists the pull policies of the images with the pull policy", func() {
+				command = commands.ConfigPullPolicy(logger, config.Config{PullPolicy: "never", PullPolicies: config.PullPolicies{Buildpacks: "if-not-present"}}, configFile)
+				command.SetArgs([]string{})
+				assert.Succeeds(command.Execute())
+				assert.Contains(outBuf.String(), "The current pull policy is 'never'")
+				assert.Contains(outBuf.String(), "The pull policy of the buildpacks is 'if-not-present'")
+			})
+
+			it("unsets the pull policy of the image", func() {
+				command = commands.ConfigPullPolicy(logger, config.Config{PullPolicy: "never", PullPolicies: config.PullPolicies{Builder: "always"}}, configFile)
+				command.SetArgs([]string{"--unset", "--image", "builder"})
+				assert.Succeeds(command.Execute())
+
+				readCfg, err := config.Read(configFile)
+				assert.Nil(err)
+				assert.Equal(readCfg.PullPolicies, config.PullPolicies{})
+				assert.Equal(readCfg.PullPolicy, "never")
+			})
+
+			it("errors for an unknown image", func() {
+				command.SetArgs([]string{"never", "--image", "lifecycle"})
+				h.AssertError(t, command.Execute(), "invalid image 'lifecycle', must be one of builder, run-image, buildpacks")
+			})
+
+			it("errors for an invalid policy", func() {
+				command.SetArgs([]string{"sometimes", "--image", "builder"})
+				h.AssertError(t, command.Execute(), "invalid pull policy sometimes")
+			})
+		})
 	})
 }
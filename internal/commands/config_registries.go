@@ -20,8 +20,9 @@ var (
 )
 
 var (
-	setDefault   bool
-	registryType string
+	setDefault      bool
+	registryType    string
+	registryMirrors []string
 )
 
 func ConfigRegistries(logger logging.Logger, cfg config.Config, cfgPath string) *cobra.Command {
@@ -44,9 +45,15 @@ func ConfigRegistries(logger logging.Logger, cfg config.Config, cfgPath string)
 	addCmd := generateAdd("registries", logger, cfg, cfgPath, addRegistry)
 	addCmd.Args = cobra.ExactArgs(2)
 	addCmd.Example = "pack config registries add my-registry https://github.com/buildpacks/my-registry"
-	addCmd.Long = bpRegistryExplanation + "Users can add registries from the config by using registries remove, and publish/yank buildpacks from it, as well as use those buildpacks when building applications."
+	addCmd.Long = bpRegistryExplanation + "Users can add registries from the config by using registries remove, and publish/yank buildpacks from it, as well as use those buildpacks when building applications.\n\n" +
+		"Private registries served over HTTPS authenticate with the token in their PACK_REGISTRY_TOKEN_<NAME> environment variable, e.g. PACK_REGISTRY_TOKEN_MY_REGISTRY for my-registry, " +
+		"else with the `token` of the registry in the config, " +
+		"else with the credentials of their host in your netrc file. Private registries served over SSH, e.g. ssh://git@example.com/registry-index, authenticate with the keys of your SSH agent.\n\n" +
+		"Registries served as plain files over HTTP(S) instead of git, e.g. by a static file server of an air-gapped mirror, are added with their URL prefixed with index+, e.g. index+https://mirror.example.com/registry-index.\n\n" +
+		"Registry indexes in a local directory, e.g. a checkout to test changes to an index before pushing them, or a copy synced to an air-gapped machine, are added with their file URL, e.g. file:///path/to/registry-index. They are read directly from the directory, without git."
 	addCmd.Flags().BoolVar(&setDefault, "default", false, "Set this buildpack registry as the default")
 	addCmd.Flags().StringVar(&registryType, "type", "github", "Type of buildpack registry [git|github]")
+	addCmd.Flags().StringSliceVar(&registryMirrors, "mirror", nil, "Mirror of the buildpack registry, tried in order when the registry can't be reached"+stringSliceHelp("mirror"))
 	cmd.AddCommand(addCmd)
 
 	rmCmd := generateRemove("registries", logger, cfg, cfgPath, removeRegistry)
@@ -55,6 +62,7 @@ func ConfigRegistries(logger logging.Logger, cfg config.Config, cfgPath string)
 	cmd.AddCommand(rmCmd)
 
 	cmd.AddCommand(ConfigRegistriesDefault(logger, cfg, cfgPath))
+	cmd.AddCommand(ConfigRegistriesMirrors(logger, cfg, cfgPath))
 
 	AddHelpFlag(cmd, "registries")
 	return cmd
@@ -62,9 +70,10 @@ func ConfigRegistries(logger logging.Logger, cfg config.Config, cfgPath string)
 
 func addRegistry(args []string, logger logging.Logger, cfg config.Config, cfgPath string) error {
 	newRegistry := config.Registry{
-		Name: args[0],
-		URL:  args[1],
-		Type: registryType,
+		Name:    args[0],
+		URL:     args[1],
+		Type:    registryType,
+		Mirrors: registryMirrors,
 	}
 
 	return addRegistryToConfig(logger, newRegistry, setDefault, cfg, cfgPath)
@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+// ConfigRegistriesMirrors lists, sets and unsets the mirrors a buildpack registry falls back to when it can't be reached
+func ConfigRegistriesMirrors(logger logging.Logger, cfg config.Config, cfgPath string) *cobra.Command {
+	var unset bool
+
+	cmd := &cobra.Command{
+		Use:   "mirrors <registry-name> [<mirror-url>...]",
+		Args:  cobra.MinimumNArgs(1),
+		Short: "List, set and unset the mirrors of a registry",
+		Long: bpRegistryExplanation + "\n\nWhen cloning or pulling a registry fails because it can't be reached, its mirrors are tried in order. " +
+			"The registry is tried first again on every refresh. Mirrors are only supported for registries served as git repositories.\n\n" +
+			"* To list the mirrors of a registry, run `pack config registries mirrors <registry-name>`.\n" +
+			"* To set the mirrors of a registry, run `pack config registries mirrors <registry-name> <mirror-url>...`.\n" +
+			"* To unset the mirrors of a registry, run `pack config registries mirrors <registry-name> --unset`.",
+		Example: "pack config registries mirrors official https://git.example.com/mirrors/registry-index",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			registryName, mirrors := args[0], args[1:]
+			reg, err := config.GetRegistry(cfg, registryName)
+			if err != nil || reg.Name != registryName {
+				return errors.Errorf("no registry with the name %s exists", style.Symbol(registryName))
+			}
+
+			switch {
+			case unset:
+				if len(mirrors) > 0 {
+					return errors.Errorf("mirror urls and --unset cannot be specified simultaneously")
+				}
+				if len(reg.Mirrors) == 0 {
+					logger.Infof("Registry %s has no mirrors", style.Symbol(registryName))
+					return nil
+				}
+				if err := writeRegistryMirrors(cfg, cfgPath, registryName, nil); err != nil {
+					return err
+				}
+				logger.Infof("Successfully unset the mirrors of registry %s", style.Symbol(registryName))
+			case len(mirrors) == 0:
+				if len(reg.Mirrors) == 0 {
+					logger.Infof("Registry %s has no mirrors", style.Symbol(registryName))
+					return nil
+				}
+				logger.Infof("Mirrors of registry %s:", style.Symbol(registryName))
+				for _, mirror := range reg.Mirrors {
+					logger.Infof("  %s", mirror)
+				}
+			default:
+				if strings.HasPrefix(reg.URL, "index+") {
+					return errors.Errorf("registry %s is served over HTTP, mirrors are only supported for registries served as git repositories", style.Symbol(registryName))
+				}
+				if strings.HasPrefix(reg.URL, "file://") {
+					return errors.Errorf("registry %s is a local directory, mirrors are only supported for registries served as git repositories", style.Symbol(registryName))
+				}
+				if err := writeRegistryMirrors(cfg, cfgPath, registryName, mirrors); err != nil {
+					return err
+				}
+				logger.Infof("Successfully set the mirrors of registry %s", style.Symbol(registryName))
+			}
+			return nil
+		}),
+	}
+
+	cmd.Flags().BoolVarP(&unset, "unset", "u", false, "Unset the mirrors of the registry")
+	AddHelpFlag(cmd, "mirrors")
+	return cmd
+}
+
+// writeRegistryMirrors sets the mirrors of the registry in the config, which are kept apart from the registries for the
+// official registry
+func writeRegistryMirrors(cfg config.Config, cfgPath, registryName string, mirrors []string) error {
+	if registryName == config.OfficialRegistryName {
+		cfg.OfficialRegistryMirrors = mirrors
+	} else {
+		registries := append([]config.Registry{}, cfg.Registries...)
+		registries[findRegistryIndex(registries, registryName)].Mirrors = mirrors
+		cfg.Registries = registries
+	}
+
+	if err := config.Write(cfg, cfgPath); err != nil {
+		return errors.Wrapf(err, "writing config to %s", cfgPath)
+	}
+	return nil
+}
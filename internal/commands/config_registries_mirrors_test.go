@@ -0,0 +1,129 @@
+package commands_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestConfigRegistriesMirrors(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "ConfigRegistriesMirrorsCommand", testConfigRegistriesMirrorsCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testConfigRegistriesMirrorsCommand(t *testing.T, when spec.G, it spec.S) {
+	when("#ConfigRegistriesMirrors", func() {
+		var (
+			tmpDir     string
+			configFile string
+			outBuf     bytes.Buffer
+			logger     = logging.NewLogWithWriters(&outBuf, &outBuf)
+			assert     = h.NewAssertionManager(t)
+			cfg        = config.Config{
+				Registries: []config.Registry{
+					{Name: "some-registry", Type: "git", URL: "https://git.example.com/registry-index"},
+					{Name: "http-registry", Type: "git", URL: "index+https://mirror.example.com/registry-index"},
+				},
+			}
+		)
+
+		it.Before(func() {
+			var err error
+			tmpDir, err = os.MkdirTemp("", "pack-home-*")
+			assert.Nil(err)
+
+			configFile = filepath.Join(tmpDir, "config.toml")
+		})
+
+		it.After(func() {
+			_ = os.RemoveAll(tmpDir)
+		})
+
+		when("list", func() {
+			it("says when the registry has no mirrors", func() {
+				command := commands.ConfigRegistriesMirrors(logger, cfg, configFile)
+				command.SetArgs([]string{"some-registry"})
+				assert.Succeeds(command.Execute())
+
+				assert.Contains(outBuf.String(), "Registry 'some-registry' has no mirrors")
+			})
+
+			it("lists the mirrors of the official registry", func() {
+				cfg.OfficialRegistryMirrors = []string{"https://git.example.com/mirrors/registry-index"}
+				command := commands.ConfigRegistriesMirrors(logger, cfg, configFile)
+				command.SetArgs([]string{"official"})
+				assert.Succeeds(command.Execute())
+
+				assert.Contains(outBuf.String(), "https://git.example.com/mirrors/registry-index")
+			})
+		})
+
+		when("set", func() {
+			it("sets the mirrors of a registry", func() {
+				command := commands.ConfigRegistriesMirrors(logger, cfg, configFile)
+				command.SetArgs([]string{"some-registry", "https://one.example.com/registry-index", "https://two.example.com/registry-index"})
+				assert.Succeeds(command.Execute())
+
+				written, err := config.Read(configFile)
+				assert.Nil(err)
+				assert.Equal(written.Registries[0].Mirrors, []string{"https://one.example.com/registry-index", "https://two.example.com/registry-index"})
+				assert.Equal(len(cfg.Registries[0].Mirrors), 0)
+			})
+
+			it("sets the mirrors of the official registry apart from the registries", func() {
+				command := commands.ConfigRegistriesMirrors(logger, cfg, configFile)
+				command.SetArgs([]string{"official", "https://git.example.com/mirrors/registry-index"})
+				assert.Succeeds(command.Execute())
+
+				written, err := config.Read(configFile)
+				assert.Nil(err)
+				assert.Equal(written.OfficialRegistryMirrors, []string{"https://git.example.com/mirrors/registry-index"})
+				assert.Equal(len(written.Registries), 2)
+			})
+
+			it("fails for a registry that doesn't exist", func() {
+				command := commands.ConfigRegistriesMirrors(logger, cfg, configFile)
+				command.SetArgs([]string{"missing", "https://git.example.com/mirrors/registry-index"})
+				assert.ErrorContains(command.Execute(), "no registry with the name 'missing' exists")
+			})
+
+			it("fails for a registry served over HTTP", func() {
+				command := commands.ConfigRegistriesMirrors(logger, cfg, configFile)
+				command.SetArgs([]string{"http-registry", "https://git.example.com/mirrors/registry-index"})
+				assert.ErrorContains(command.Execute(), "mirrors are only supported for registries served as git repositories")
+			})
+		})
+
+		when("unset", func() {
+			it("unsets the mirrors of a registry", func() {
+				cfg.Registries[0].Mirrors = []string{"https://one.example.com/registry-index"}
+				command := commands.ConfigRegistriesMirrors(logger, cfg, configFile)
+				command.SetArgs([]string{"some-registry", "--unset"})
+				assert.Succeeds(command.Execute())
+
+				written, err := config.Read(configFile)
+				assert.Nil(err)
+				assert.Equal(len(written.Registries[0].Mirrors), 0)
+				assert.Contains(outBuf.String(), "Successfully unset the mirrors of registry 'some-registry'")
+			})
+
+			it("fails when mirror urls are also specified", func() {
+				command := commands.ConfigRegistriesMirrors(logger, cfg, configFile)
+				command.SetArgs([]string{"some-registry", "https://one.example.com/registry-index", "--unset"})
+				assert.ErrorContains(command.Execute(), "mirror urls and --unset cannot be specified simultaneously")
+			})
+		})
+	})
+}
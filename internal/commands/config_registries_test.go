@@ -195,6 +195,17 @@ func testConfigRegistries(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("mirrors are given", func() {
+			it("adds the registry with its mirrors", func() {
+				cmd.SetArgs(append(args, "--mirror", "https://one.example.com/registry-index", "--mirror", "https://two.example.com/registry-index"))
+				assert.Succeeds(cmd.Execute())
+
+				cfg, err := config.Read(configPath)
+				assert.Nil(err)
+				assert.Equal(cfg.Registries[0].Mirrors, []string{"https://one.example.com/registry-index", "https://two.example.com/registry-index"})
+			})
+		})
+
 		when("default is true", func() {
 			it("sets newly added registry as the default", func() {
 				cmd.SetArgs(append(args, "--default"))
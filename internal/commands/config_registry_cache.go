@@ -0,0 +1,226 @@
+package commands
+
+import (
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/registry"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+type ConfigRegistryCachePruneFlags struct {
+	OlderThan string
+	DryRun    bool
+}
+
+// ConfigRegistryCache lists and prunes the caches of buildpack registries in the pack home, and configures how long
+// unused caches are kept
+func ConfigRegistryCache(logger logging.Logger, cfg config.Config, cfgPath string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "registry-cache",
+		Args:  cobra.NoArgs,
+		Short: "List and prune the caches of buildpack registries",
+		Long: "Each buildpack registry buildpacks are located in is cached in the pack home. Caches that go unused for " +
+			fmt.Sprintf("longer than the max age, %.0f days by default, are ", registry.DefaultCacheMaxAge.Hours()/24) +
+			"removed automatically. A removed cache is created again the next time a buildpack is located in its registry.",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			return listRegistryCaches(logger, cfg)
+		}),
+	}
+
+	listCmd := &cobra.Command{
+		Use:     "list",
+		Args:    cobra.NoArgs,
+		Short:   "List the caches of buildpack registries, from the least recently used",
+		Example: "pack config registry-cache list",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			return listRegistryCaches(logger, cfg)
+		}),
+	}
+	cmd.AddCommand(listCmd)
+	cmd.AddCommand(configRegistryCachePrune(logger))
+	cmd.AddCommand(configRegistryCacheMaxAge(logger, cfg, cfgPath))
+
+	AddHelpFlag(cmd, "registry-cache")
+	return cmd
+}
+
+func configRegistryCachePrune(logger logging.Logger) *cobra.Command {
+	var flags ConfigRegistryCachePruneFlags
+
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Args:  cobra.NoArgs,
+		Short: "Remove the caches of buildpack registries",
+		Long: "Remove the caches of buildpack registries that weren't used for at least `--older-than`, along with the " +
+			"temporary directories of interrupted clones of registries. Caches pack is using meanwhile are kept.",
+		Example: "pack config registry-cache prune --older-than 7d --dry-run",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			olderThan, err := parseAge(flags.OlderThan)
+			if err != nil {
+				return err
+			}
+			home, err := config.PackHome()
+			if err != nil {
+				return err
+			}
+
+			pruned, err := registry.PruneCaches(home, olderThan, flags.DryRun)
+			if err != nil {
+				return err
+			}
+			if len(pruned) == 0 {
+				logger.Info("Nothing to remove")
+				return nil
+			}
+
+			var (
+				removed int
+				freed   int64
+			)
+			writeRegistryCaches(logger, pruned)
+			for _, cache := range pruned {
+				if cache.Err != nil {
+					continue
+				}
+				removed++
+				freed += cache.Size
+			}
+
+			logger.Info("")
+			if flags.DryRun {
+				logger.Infof("%d registry caches would be removed, freeing %s", removed, humanize.Bytes(uint64(freed)))
+				return nil
+			}
+			for _, cache := range pruned {
+				if cache.Err != nil {
+					logger.Errorf("Failed to remove %s: %s", style.Symbol(cache.Path), cache.Err)
+				}
+			}
+			logger.Infof("Removed %d registry caches, freeing %s", removed, humanize.Bytes(uint64(freed)))
+			if removed < len(pruned) {
+				return errors.Errorf("%d of %d registry caches couldn't be removed", len(pruned)-removed, len(pruned))
+			}
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringVar(&flags.OlderThan, "older-than", "0", "Only remove caches unused for at least this long, e.g. '72h' or '30d'; '0' removes all of them")
+	cmd.Flags().BoolVar(&flags.DryRun, "dry-run", false, "List the caches that would be removed, without removing them")
+	AddHelpFlag(cmd, "prune")
+	return cmd
+}
+
+func configRegistryCacheMaxAge(logger logging.Logger, cfg config.Config, cfgPath string) *cobra.Command {
+	var unset bool
+
+	cmd := &cobra.Command{
+		Use:   "max-age <age>",
+		Args:  cobra.MaximumNArgs(1),
+		Short: "List, set and unset how long unused registry caches are kept",
+		Long: "Registry caches unused for longer than the max age are removed automatically, at most once a day, when " +
+			"buildpacks are located in a registry. The age is a duration such as 72h or 30d, and 0 keeps the caches.\n\n" +
+			"* Running `pack config registry-cache max-age` prints the max age.\n" +
+			"* Running `pack config registry-cache max-age <age>` sets the max age.\n" +
+			"* Running `pack config registry-cache max-age --unset` goes back to the default of " + registry.DefaultCacheMaxAge.String() + ".",
+		Example: "pack config registry-cache max-age 7d",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			switch {
+			case unset:
+				if len(args) > 0 {
+					return errors.Errorf("age and --unset cannot be specified simultaneously")
+				}
+
+				if cfg.RegistryCacheMaxAge == "" {
+					logger.Info("No registry cache max age was set.")
+					return nil
+				}
+				cfg.RegistryCacheMaxAge = ""
+				if err := config.Write(cfg, cfgPath); err != nil {
+					return errors.Wrapf(err, "failed to write to config at %s", cfgPath)
+				}
+				logger.Infof("Successfully unset the registry cache max age, unused registry caches are removed after %s", registry.DefaultCacheMaxAge)
+			case len(args) == 0:
+				maxAge := cfg.RegistryCacheMaxAge
+				if maxAge == "" {
+					maxAge = registry.DefaultCacheMaxAge.String()
+				}
+				if maxAge == (time.Duration(0)).String() {
+					logger.Info("Unused registry caches are kept")
+					return nil
+				}
+				logger.Infof("Unused registry caches are removed after %s", style.Symbol(maxAge))
+			default:
+				maxAge, err := parseAge(args[0])
+				if err != nil {
+					return err
+				}
+
+				cfg.RegistryCacheMaxAge = maxAge.String()
+				if err := config.Write(cfg, cfgPath); err != nil {
+					return errors.Wrapf(err, "failed to write to config at %s", cfgPath)
+				}
+				if maxAge == 0 {
+					logger.Info("Unused registry caches will now be kept")
+					return nil
+				}
+				logger.Infof("Unused registry caches will now be removed after %s", style.Symbol(maxAge.String()))
+			}
+
+			return nil
+		}),
+	}
+
+	cmd.Flags().BoolVarP(&unset, "unset", "u", false, "Unset the max age, going back to the default")
+	AddHelpFlag(cmd, "max-age")
+	return cmd
+}
+
+func listRegistryCaches(logger logging.Logger, cfg config.Config) error {
+	home, err := config.PackHome()
+	if err != nil {
+		return err
+	}
+
+	var urls []string
+	for _, reg := range config.GetRegistries(cfg) {
+		urls = append(urls, reg.URL)
+	}
+	caches, err := registry.ListCaches(home, urls)
+	if err != nil {
+		return err
+	}
+	if len(caches) == 0 {
+		logger.Info("No registry caches")
+		return nil
+	}
+
+	writeRegistryCaches(logger, caches)
+	var total int64
+	for _, cache := range caches {
+		total += cache.Size
+	}
+	logger.Info("")
+	logger.Infof("%d registry caches, using %s", len(caches), humanize.Bytes(uint64(total)))
+	return nil
+}
+
+func writeRegistryCaches(logger logging.Logger, caches []registry.CacheDir) {
+	tw := tabwriter.NewWriter(logger.Writer(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "REGISTRY\tLAST USED\tSIZE\tDIRECTORY")
+	for _, cache := range caches {
+		url := cache.URL
+		if url == "" {
+			url = "unknown"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", url, humanize.Time(cache.LastUsed), humanize.Bytes(uint64(cache.Size)), cache.Path)
+	}
+	tw.Flush()
+}
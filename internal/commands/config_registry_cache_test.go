@@ -0,0 +1,152 @@
+package commands_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestConfigRegistryCache(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "ConfigRegistryCache", testConfigRegistryCache, spec.Random(), spec.Report(report.Terminal{}))
+}
+
+func testConfigRegistryCache(t *testing.T, when spec.G, it spec.S) {
+	const cacheName = "registry-0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+	var (
+		logger       logging.Logger
+		outBuf       bytes.Buffer
+		tempPackHome string
+		configPath   string
+		cacheDir     string
+	)
+
+	it.Before(func() {
+		var err error
+
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		tempPackHome, err = os.MkdirTemp("", "pack-home")
+		h.AssertNil(t, err)
+		h.AssertNil(t, os.Setenv("PACK_HOME", tempPackHome))
+		configPath = filepath.Join(tempPackHome, "config.toml")
+
+		cacheDir = filepath.Join(tempPackHome, cacheName)
+		h.AssertNil(t, os.MkdirAll(cacheDir, 0750))
+		h.AssertNil(t, os.WriteFile(filepath.Join(cacheDir, "entry"), []byte("some-entry"), 0600))
+		used := time.Now().Add(-10 * 24 * time.Hour)
+		h.AssertNil(t, os.Chtimes(cacheDir, used, used))
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.Unsetenv("PACK_HOME"))
+		h.AssertNil(t, os.RemoveAll(tempPackHome))
+	})
+
+	newCommand := func(cfg config.Config, args ...string) *cobra.Command {
+		cmd := commands.ConfigRegistryCache(logger, cfg, configPath)
+		cmd.SetOut(logging.GetWriterForLevel(logger, logging.InfoLevel))
+		cmd.SetArgs(args)
+		return cmd
+	}
+
+	when("#ConfigRegistryCache", func() {
+		when("list", func() {
+			it("lists the registry caches", func() {
+				h.AssertNil(t, newCommand(config.Config{}, "list").Execute())
+
+				h.AssertContains(t, outBuf.String(), "REGISTRY")
+				h.AssertContains(t, outBuf.String(), cacheDir)
+				h.AssertContains(t, outBuf.String(), "1 registry caches, using 10 B")
+			})
+
+			it("lists the registry caches without a subcommand", func() {
+				h.AssertNil(t, newCommand(config.Config{}).Execute())
+				h.AssertContains(t, outBuf.String(), cacheDir)
+			})
+
+			it("says so when there are no registry caches", func() {
+				h.AssertNil(t, os.RemoveAll(cacheDir))
+
+				h.AssertNil(t, newCommand(config.Config{}, "list").Execute())
+				h.AssertContains(t, outBuf.String(), "No registry caches")
+			})
+		})
+
+		when("prune", func() {
+			it("removes all the registry caches", func() {
+				h.AssertNil(t, newCommand(config.Config{}, "prune").Execute())
+
+				h.AssertContains(t, outBuf.String(), "Removed 1 registry caches, freeing 10 B")
+				h.AssertPathDoesNotExists(t, cacheDir)
+			})
+
+			it("keeps the registry caches used within --older-than", func() {
+				h.AssertNil(t, newCommand(config.Config{}, "prune", "--older-than", "30d").Execute())
+
+				h.AssertContains(t, outBuf.String(), "Nothing to remove")
+				h.AssertPathExists(t, cacheDir)
+			})
+
+			it("removes nothing with --dry-run", func() {
+				h.AssertNil(t, newCommand(config.Config{}, "prune", "--dry-run").Execute())
+
+				h.AssertContains(t, outBuf.String(), "1 registry caches would be removed, freeing 10 B")
+				h.AssertPathExists(t, cacheDir)
+			})
+
+			it("errors when --older-than is invalid", func() {
+				h.AssertNotNil(t, newCommand(config.Config{}, "prune", "--older-than", "some-age").Execute())
+				h.AssertPathExists(t, cacheDir)
+			})
+		})
+
+		when("max-age", func() {
+			it("prints the default max age when none is set", func() {
+				h.AssertNil(t, newCommand(config.Config{}, "max-age").Execute())
+				h.AssertContains(t, outBuf.String(), "Unused registry caches are removed after '720h0m0s'")
+			})
+
+			it("prints the configured max age", func() {
+				h.AssertNil(t, newCommand(config.Config{RegistryCacheMaxAge: "0s"}, "max-age").Execute())
+				h.AssertContains(t, outBuf.String(), "Unused registry caches are kept")
+			})
+
+			it("sets the max age", func() {
+				h.AssertNil(t, newCommand(config.Config{}, "max-age", "7d").Execute())
+				h.AssertContains(t, outBuf.String(), "Unused registry caches will now be removed after '168h0m0s'")
+
+				cfg, err := config.Read(configPath)
+				h.AssertNil(t, err)
+				h.AssertEq(t, cfg.RegistryCacheMaxAge, "168h0m0s")
+			})
+
+			it("unsets the max age", func() {
+				h.AssertNil(t, newCommand(config.Config{RegistryCacheMaxAge: "168h0m0s"}, "max-age", "--unset").Execute())
+				h.AssertContains(t, outBuf.String(), "Successfully unset the registry cache max age")
+
+				cfg, err := config.Read(configPath)
+				h.AssertNil(t, err)
+				h.AssertEq(t, cfg.RegistryCacheMaxAge, "")
+			})
+
+			it("errors when both an age and --unset are specified", func() {
+				err := newCommand(config.Config{}, "max-age", "7d", "--unset").Execute()
+				h.AssertError(t, err, "age and --unset cannot be specified simultaneously")
+			})
+		})
+	})
+}
@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"net/url"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+func ConfigRegistryProxy(logger logging.Logger, cfg config.Config, cfgPath string) *cobra.Command {
+	var unset bool
+
+	cmd := &cobra.Command{
+		Use:   "registry-proxy <proxy-url>",
+		Args:  cobra.MaximumNArgs(1),
+		Short: "List, set and unset the proxy the registry caches are refreshed through",
+		Long: "Registries are cloned, pulled and fetched through the proxy of the HTTPS_PROXY and HTTP_PROXY environment " +
+			"variables, unless a registry proxy is set. Hosts in the NO_PROXY environment variable are reached directly either way. " +
+			"Registries served over SSH only go through the registry proxy.\n\n" +
+			"* Running `pack config registry-proxy` prints the proxy.\n" +
+			"* Running `pack config registry-proxy <proxy-url>` sets the proxy.\n" +
+			"* Running `pack config registry-proxy --unset` goes back to the proxy of the environment.",
+		Example: "pack config registry-proxy http://proxy.example.com:3128",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			switch {
+			case unset:
+				if len(args) > 0 {
+					return errors.Errorf("proxy url and --unset cannot be specified simultaneously")
+				}
+
+				if cfg.RegistryProxy == "" {
+					logger.Info("No registry proxy was set.")
+					return nil
+				}
+				cfg.RegistryProxy = ""
+				if err := config.Write(cfg, cfgPath); err != nil {
+					return errors.Wrapf(err, "failed to write to config at %s", cfgPath)
+				}
+				logger.Info("Successfully unset the registry proxy, the registry caches are refreshed through the proxy of the environment")
+			case len(args) == 0:
+				if cfg.RegistryProxy == "" {
+					logger.Info("No registry proxy is set, the registry caches are refreshed through the proxy of the environment")
+					return nil
+				}
+				logger.Infof("The registry caches are refreshed through %s", style.Symbol(redactedURL(cfg.RegistryProxy)))
+			default:
+				proxy, err := url.Parse(args[0])
+				if err != nil || proxy.Scheme == "" || proxy.Host == "" {
+					return errors.Errorf("invalid proxy url %s, must be a url such as http://proxy.example.com:3128", style.Symbol(args[0]))
+				}
+
+				cfg.RegistryProxy = args[0]
+				if err := config.Write(cfg, cfgPath); err != nil {
+					return errors.Wrapf(err, "failed to write to config at %s", cfgPath)
+				}
+				logger.Infof("The registry caches will now be refreshed through %s", style.Symbol(proxy.Redacted()))
+			}
+
+			return nil
+		}),
+	}
+
+	cmd.Flags().BoolVarP(&unset, "unset", "u", false, "Unset the proxy, going back to the proxy of the environment")
+	AddHelpFlag(cmd, "registry-proxy")
+	return cmd
+}
+
+// redactedURL is rawURL with its password redacted, or rawURL when it can't be parsed
+func redactedURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return parsed.Redacted()
+}
@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/registry"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+func ConfigRegistryRefreshInterval(logger logging.Logger, cfg config.Config, cfgPath string) *cobra.Command {
+	var unset bool
+
+	cmd := &cobra.Command{
+		Use:   "registry-refresh-interval <interval>",
+		Args:  cobra.MaximumNArgs(1),
+		Short: "List, set and unset how often the registry caches are refreshed",
+		Long: "Locating a buildpack in a registry pulls the latest index of the registry, unless its cache was refreshed " +
+			"within this interval. The interval is a duration such as 30s, 15m or 1h, and 0 refreshes the caches every time.\n\n" +
+			"* Running `pack config registry-refresh-interval` prints the interval.\n" +
+			"* Running `pack config registry-refresh-interval <interval>` sets the interval.\n" +
+			"* Running `pack config registry-refresh-interval --unset` goes back to the default of " + registry.DefaultRefreshInterval.String() + ".\n\n" +
+			"The --force-refresh flag refreshes the caches regardless of the interval.",
+		Example: "pack config registry-refresh-interval 1h",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			switch {
+			case unset:
+				if len(args) > 0 {
+					return errors.Errorf("interval and --unset cannot be specified simultaneously")
+				}
+
+				if cfg.RegistryRefreshInterval == "" {
+					logger.Info("No registry refresh interval was set.")
+					return nil
+				}
+				cfg.RegistryRefreshInterval = ""
+				if err := config.Write(cfg, cfgPath); err != nil {
+					return errors.Wrapf(err, "failed to write to config at %s", cfgPath)
+				}
+				logger.Infof("Successfully unset the registry refresh interval, the registry caches are refreshed every %s", registry.DefaultRefreshInterval)
+			case len(args) == 0:
+				interval := cfg.RegistryRefreshInterval
+				if interval == "" {
+					interval = registry.DefaultRefreshInterval.String()
+				}
+				logger.Infof("The registry caches are refreshed every %s", style.Symbol(interval))
+			default:
+				interval, err := time.ParseDuration(args[0])
+				if err != nil || interval < 0 {
+					return errors.Errorf("invalid interval %s, must be a duration such as 15m or 1h", style.Symbol(args[0]))
+				}
+
+				cfg.RegistryRefreshInterval = interval.String()
+				if err := config.Write(cfg, cfgPath); err != nil {
+					return errors.Wrapf(err, "failed to write to config at %s", cfgPath)
+				}
+				logger.Infof("The registry caches will now be refreshed every %s", style.Symbol(interval.String()))
+			}
+
+			return nil
+		}),
+	}
+
+	cmd.Flags().BoolVarP(&unset, "unset", "u", false, "Unset the interval, going back to the default")
+	AddHelpFlag(cmd, "registry-refresh-interval")
+	return cmd
+}
@@ -0,0 +1,102 @@
+package commands_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestConfigRegistryRefreshInterval(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "ConfigRegistryRefreshInterval", testConfigRegistryRefreshInterval, spec.Random(), spec.Report(report.Terminal{}))
+}
+
+func testConfigRegistryRefreshInterval(t *testing.T, when spec.G, it spec.S) {
+	var (
+		logger       logging.Logger
+		outBuf       bytes.Buffer
+		tempPackHome string
+		configPath   string
+	)
+
+	it.Before(func() {
+		var err error
+
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		tempPackHome, err = os.MkdirTemp("", "pack-home")
+		h.AssertNil(t, err)
+		configPath = filepath.Join(tempPackHome, "config.toml")
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.RemoveAll(tempPackHome))
+	})
+
+	newCommand := func(cfg config.Config, args ...string) *cobra.Command {
+		cmd := commands.ConfigRegistryRefreshInterval(logger, cfg, configPath)
+		cmd.SetOut(logging.GetWriterForLevel(logger, logging.InfoLevel))
+		cmd.SetArgs(args)
+		return cmd
+	}
+
+	when("#ConfigRegistryRefreshInterval", func() {
+		when("list", func() {
+			it("prints the default interval when none is set", func() {
+				h.AssertNil(t, newCommand(config.Config{}).Execute())
+				h.AssertContains(t, outBuf.String(), "The registry caches are refreshed every '5m0s'")
+			})
+
+			it("prints the interval", func() {
+				h.AssertNil(t, newCommand(config.Config{RegistryRefreshInterval: "1h0m0s"}).Execute())
+				h.AssertContains(t, outBuf.String(), "The registry caches are refreshed every '1h0m0s'")
+			})
+		})
+
+		when("set", func() {
+			it("sets the interval", func() {
+				h.AssertNil(t, newCommand(config.Config{}, "15m").Execute())
+
+				cfg, err := config.Read(configPath)
+				h.AssertNil(t, err)
+				h.AssertEq(t, cfg.RegistryRefreshInterval, "15m0s")
+				h.AssertContains(t, outBuf.String(), "The registry caches will now be refreshed every '15m0s'")
+			})
+
+			it("fails for an invalid interval", func() {
+				err := newCommand(config.Config{}, "--", "-1m").Execute()
+				h.AssertError(t, err, "invalid interval '-1m', must be a duration such as 15m or 1h")
+
+				err = newCommand(config.Config{}, "often").Execute()
+				h.AssertError(t, err, "invalid interval 'often'")
+			})
+		})
+
+		when("unset", func() {
+			it("unsets the interval", func() {
+				h.AssertNil(t, newCommand(config.Config{RegistryRefreshInterval: "1h0m0s"}, "--unset").Execute())
+
+				cfg, err := config.Read(configPath)
+				h.AssertNil(t, err)
+				h.AssertEq(t, cfg.RegistryRefreshInterval, "")
+				h.AssertContains(t, outBuf.String(), "Successfully unset the registry refresh interval")
+			})
+
+			it("errors when an interval is also given", func() {
+				err := newCommand(config.Config{}, "--unset", "1h").Execute()
+				h.AssertError(t, err, "interval and --unset cannot be specified simultaneously")
+			})
+		})
+	})
+}
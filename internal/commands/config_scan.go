@@ -0,0 +1,106 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/scan"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+func ConfigScan(logger logging.Logger, cfg config.Config, cfgPath string) *cobra.Command {
+	var (
+		command string
+		failOn  string
+		unset   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "scan [<scanner>]",
+		Args:  cobra.MaximumNArgs(1),
+		Short: "List and set the vulnerability scanner run after builds",
+		Long: "When a scanner is set, `pack build` scans the image it built for vulnerabilities and reports them. " +
+			"The supported scanners are " + strings.Join(scan.ScannerNames(), " and ") + ", which must be installed.\n\n" +
+			"* Running `pack config scan` prints the scan configuration.\n" +
+			"* Running `pack config scan <scanner>` sets the scanner.\n" +
+			"* Running `pack config scan --fail-on <severity>` fails builds with vulnerabilities of that severity or higher.\n" +
+			"* Running `pack config scan --unset` stops scanning images.\n\n" +
+			"The scan configuration of a project descriptor takes precedence over this configuration.",
+		Example: "pack config scan grype --fail-on high",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			changed := len(args) > 0 || cmd.Flags().Changed("command") || cmd.Flags().Changed("fail-on")
+			switch {
+			case unset:
+				if changed {
+					return errors.New("scanner and --unset cannot be specified simultaneously")
+				}
+				if cfg.Scan.Scanner == "" {
+					logger.Info("No scanner was set.")
+					return nil
+				}
+				cfg.Scan = config.Scan{}
+				if err := config.Write(cfg, cfgPath); err != nil {
+					return errors.Wrapf(err, "failed to write to config at %s", cfgPath)
+				}
+				logger.Info("Successfully unset the scanner")
+				return nil
+			case !changed:
+				if cfg.Scan.Scanner == "" {
+					logger.Info("No scanner is set. To scan images after builds, run `pack config scan <scanner>`")
+				} else {
+					logger.Info(describeScanConfig(cfg.Scan))
+				}
+				return nil
+			}
+
+			if len(args) > 0 {
+				if _, ok := scan.Scanners[args[0]]; !ok {
+					return errors.Errorf("unknown scanner %s, must be one of %s", style.Symbol(args[0]), strings.Join(scan.ScannerNames(), ", "))
+				}
+				cfg.Scan.Scanner = args[0]
+			}
+			if cmd.Flags().Changed("command") {
+				cfg.Scan.Command = command
+			}
+			if cmd.Flags().Changed("fail-on") {
+				if failOn != "" {
+					if _, err := scan.ParseSeverity(failOn); err != nil {
+						return err
+					}
+				}
+				cfg.Scan.FailOn = failOn
+			}
+			if cfg.Scan.Scanner == "" {
+				return errors.New("a scanner is required, provide one as an argument")
+			}
+
+			if err := config.Write(cfg, cfgPath); err != nil {
+				return errors.Wrapf(err, "failed to write to config at %s", cfgPath)
+			}
+			logger.Info(describeScanConfig(cfg.Scan))
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringVar(&command, "command", "", "Path of the scanner executable, when it isn't the name of the scanner in PATH")
+	cmd.Flags().StringVar(&failOn, "fail-on", "", "Lowest severity of vulnerabilities that fails the build, one of negligible, low, medium, high or critical (vulnerabilities are only reported when empty)")
+	cmd.Flags().BoolVarP(&unset, "unset", "u", false, "Unset the scanner, images are no longer scanned after builds")
+	AddHelpFlag(cmd, "scan")
+	return cmd
+}
+
+func describeScanConfig(cfg config.Scan) string {
+	description := fmt.Sprintf("Images are scanned with %s", style.Symbol(cfg.Scanner))
+	if cfg.Command != "" {
+		description += fmt.Sprintf(" using %s", style.Symbol(cfg.Command))
+	}
+	if cfg.FailOn != "" {
+		description += fmt.Sprintf(", failing builds with vulnerabilities of severity %s or higher", style.Symbol(cfg.FailOn))
+	}
+	return description
+}
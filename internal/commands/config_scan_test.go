@@ -0,0 +1,106 @@
+package commands_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestConfigScan(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "ConfigScanCommand", testConfigScan, spec.Random(), spec.Report(report.Terminal{}))
+}
+
+func testConfigScan(t *testing.T, when spec.G, it spec.S) {
+	var (
+		logger       logging.Logger
+		outBuf       bytes.Buffer
+		tempPackHome string
+		configPath   string
+	)
+
+	it.Before(func() {
+		var err error
+
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		tempPackHome, err = os.MkdirTemp("", "pack-home")
+		h.AssertNil(t, err)
+		configPath = filepath.Join(tempPackHome, "config.toml")
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.RemoveAll(tempPackHome))
+	})
+
+	newCommand := func(cfg config.Config, args ...string) *cobra.Command {
+		cmd := commands.ConfigScan(logger, cfg, configPath)
+		cmd.SetOut(logging.GetWriterForLevel(logger, logging.InfoLevel))
+		cmd.SetArgs(args)
+		return cmd
+	}
+
+	when("#ConfigScan", func() {
+		when("list values", func() {
+			it("prints a clear message if no scanner is set", func() {
+				h.AssertNil(t, newCommand(config.Config{}).Execute())
+				h.AssertContains(t, outBuf.String(), "No scanner is set")
+			})
+
+			it("prints the scan configuration", func() {
+				h.AssertNil(t, newCommand(config.Config{Scan: config.Scan{Scanner: "trivy", FailOn: "high"}}).Execute())
+				h.AssertContains(t, outBuf.String(), "Images are scanned with 'trivy', failing builds with vulnerabilities of severity 'high' or higher")
+			})
+		})
+
+		when("set", func() {
+			it("sets the scanner and threshold", func() {
+				h.AssertNil(t, newCommand(config.Config{}, "grype", "--command", "/opt/grype", "--fail-on", "critical").Execute())
+
+				cfg, err := config.Read(configPath)
+				h.AssertNil(t, err)
+				h.AssertEq(t, cfg.Scan, config.Scan{Scanner: "grype", Command: "/opt/grype", FailOn: "critical"})
+			})
+
+			it("keeps the scanner when only the threshold changes", func() {
+				h.AssertNil(t, newCommand(config.Config{Scan: config.Scan{Scanner: "grype"}}, "--fail-on", "medium").Execute())
+
+				cfg, err := config.Read(configPath)
+				h.AssertNil(t, err)
+				h.AssertEq(t, cfg.Scan, config.Scan{Scanner: "grype", FailOn: "medium"})
+			})
+
+			it("fails for unknown scanners and severities", func() {
+				h.AssertError(t, newCommand(config.Config{}, "clair").Execute(), "unknown scanner 'clair'")
+				h.AssertError(t, newCommand(config.Config{}, "grype", "--fail-on", "severe").Execute(), "unknown severity 'severe'")
+				h.AssertError(t, newCommand(config.Config{}, "--fail-on", "high").Execute(), "a scanner is required")
+			})
+		})
+
+		when("unset", func() {
+			it("removes the scan configuration", func() {
+				h.AssertNil(t, newCommand(config.Config{Scan: config.Scan{Scanner: "grype"}}, "--unset").Execute())
+
+				cfg, err := config.Read(configPath)
+				h.AssertNil(t, err)
+				h.AssertEq(t, cfg.Scan, config.Scan{})
+				h.AssertContains(t, outBuf.String(), "Successfully unset the scanner")
+			})
+
+			it("can't be combined with a scanner", func() {
+				h.AssertError(t, newCommand(config.Config{}, "grype", "--unset").Execute(), "scanner and --unset cannot be specified simultaneously")
+			})
+		})
+	})
+}
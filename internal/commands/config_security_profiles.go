@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+func ConfigSecurityProfiles(logger logging.Logger, cfg config.Config, cfgPath string) *cobra.Command {
+	var (
+		seccomp  string
+		appArmor string
+		unset    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "security-profiles",
+		Args:  cobra.NoArgs,
+		Short: "List and set the seccomp and AppArmor profiles of the lifecycle containers",
+		Long: "Hosts with restrictive default seccomp or AppArmor profiles may break buildpacks making system calls " +
+			"the profiles deny. The profiles set here are used by every `pack build` instead of the defaults of the daemon.\n\n" +
+			"* Running `pack config security-profiles` prints the profiles.\n" +
+			"* Running `pack config security-profiles --seccomp <path>` sets the seccomp profile to the JSON profile at path.\n" +
+			"* Running `pack config security-profiles --apparmor <profile>` sets the AppArmor profile, which must be loaded on the docker host.\n" +
+			"* Running `pack config security-profiles --unset` goes back to the defaults of the daemon.\n\n" +
+			"Either profile may be 'unconfined' to run the containers without one, which is best avoided. " +
+			"The --seccomp-profile and --apparmor-profile flags of `pack build` take precedence over these profiles.",
+		Example: "pack config security-profiles --seccomp ./seccomp.json",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			changed := cmd.Flags().Changed("seccomp") || cmd.Flags().Changed("apparmor")
+			switch {
+			case unset:
+				if changed {
+					return errors.New("profiles and --unset cannot be specified simultaneously")
+				}
+				if cfg.SecurityProfiles == (config.SecurityProfiles{}) {
+					logger.Info("No security profiles were set.")
+					return nil
+				}
+				cfg.SecurityProfiles = config.SecurityProfiles{}
+				if err := config.Write(cfg, cfgPath); err != nil {
+					return errors.Wrapf(err, "failed to write to config at %s", cfgPath)
+				}
+				logger.Info("Successfully unset the security profiles")
+				return nil
+			case !changed:
+				logger.Info(describeSecurityProfiles(cfg.SecurityProfiles))
+				return nil
+			}
+
+			if cmd.Flags().Changed("seccomp") {
+				if seccomp != "" && seccomp != unconfinedProfile {
+					path, err := filepath.Abs(seccomp)
+					if err != nil {
+						return err
+					}
+					if _, err := readSeccompProfile(path); err != nil {
+						return err
+					}
+					seccomp = path
+				}
+				cfg.SecurityProfiles.Seccomp = seccomp
+			}
+			if cmd.Flags().Changed("apparmor") {
+				cfg.SecurityProfiles.AppArmor = appArmor
+			}
+
+			if err := config.Write(cfg, cfgPath); err != nil {
+				return errors.Wrapf(err, "failed to write to config at %s", cfgPath)
+			}
+			logger.Info(describeSecurityProfiles(cfg.SecurityProfiles))
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringVar(&seccomp, "seccomp", "", "Path to the seccomp profile of the lifecycle containers, or 'unconfined' (the default profile of the daemon is used when empty)")
+	cmd.Flags().StringVar(&appArmor, "apparmor", "", "Name of the AppArmor profile of the lifecycle containers, or 'unconfined' (the default profile of the daemon is used when empty)")
+	cmd.Flags().BoolVarP(&unset, "unset", "u", false, "Unset the profiles, using the defaults of the daemon")
+	AddHelpFlag(cmd, "security-profiles")
+	return cmd
+}
+
+func describeSecurityProfiles(profiles config.SecurityProfiles) string {
+	var descriptions []string
+	if profiles.Seccomp != "" {
+		descriptions = append(descriptions, fmt.Sprintf("seccomp profile %s", style.Symbol(profiles.Seccomp)))
+	}
+	if profiles.AppArmor != "" {
+		descriptions = append(descriptions, fmt.Sprintf("AppArmor profile %s", style.Symbol(profiles.AppArmor)))
+	}
+	if len(descriptions) == 0 {
+		return "No security profiles are set, the lifecycle containers use the defaults of the daemon"
+	}
+	return "The lifecycle containers use the " + strings.Join(descriptions, " and the ")
+}
@@ -0,0 +1,112 @@
+package commands_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestConfigSecurityProfiles(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "ConfigSecurityProfilesCommand", testConfigSecurityProfiles, spec.Random(), spec.Report(report.Terminal{}))
+}
+
+func testConfigSecurityProfiles(t *testing.T, when spec.G, it spec.S) {
+	var (
+		logger       logging.Logger
+		outBuf       bytes.Buffer
+		tempPackHome string
+		configPath   string
+	)
+
+	it.Before(func() {
+		var err error
+
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		tempPackHome, err = os.MkdirTemp("", "pack-home")
+		h.AssertNil(t, err)
+		configPath = filepath.Join(tempPackHome, "config.toml")
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.RemoveAll(tempPackHome))
+	})
+
+	newCommand := func(cfg config.Config, args ...string) *cobra.Command {
+		cmd := commands.ConfigSecurityProfiles(logger, cfg, configPath)
+		cmd.SetOut(logging.GetWriterForLevel(logger, logging.InfoLevel))
+		cmd.SetArgs(args)
+		return cmd
+	}
+
+	when("#ConfigSecurityProfiles", func() {
+		when("list values", func() {
+			it("prints a clear message if no profiles are set", func() {
+				h.AssertNil(t, newCommand(config.Config{}).Execute())
+				h.AssertContains(t, outBuf.String(), "No security profiles are set, the lifecycle containers use the defaults of the daemon")
+			})
+
+			it("prints the profiles", func() {
+				h.AssertNil(t, newCommand(config.Config{SecurityProfiles: config.SecurityProfiles{Seccomp: "/some/seccomp.json", AppArmor: "some-profile"}}).Execute())
+				h.AssertContains(t, outBuf.String(), "The lifecycle containers use the seccomp profile '/some/seccomp.json' and the AppArmor profile 'some-profile'")
+			})
+		})
+
+		when("set", func() {
+			it("sets the absolute path of the seccomp profile", func() {
+				profilePath := filepath.Join(tempPackHome, "seccomp.json")
+				h.AssertNil(t, os.WriteFile(profilePath, []byte(`{"defaultAction": "SCMP_ACT_ALLOW"}`), 0600))
+
+				h.AssertNil(t, newCommand(config.Config{}, "--seccomp", profilePath, "--apparmor", "some-profile").Execute())
+
+				cfg, err := config.Read(configPath)
+				h.AssertNil(t, err)
+				h.AssertEq(t, cfg.SecurityProfiles, config.SecurityProfiles{Seccomp: profilePath, AppArmor: "some-profile"})
+			})
+
+			it("accepts unconfined", func() {
+				h.AssertNil(t, newCommand(config.Config{}, "--seccomp", "unconfined").Execute())
+
+				cfg, err := config.Read(configPath)
+				h.AssertNil(t, err)
+				h.AssertEq(t, cfg.SecurityProfiles.Seccomp, "unconfined")
+			})
+
+			it("fails for a seccomp profile that isn't JSON", func() {
+				profilePath := filepath.Join(tempPackHome, "seccomp.json")
+				h.AssertNil(t, os.WriteFile(profilePath, []byte("defaultAction: SCMP_ACT_ALLOW"), 0600))
+
+				err := newCommand(config.Config{}, "--seccomp", profilePath).Execute()
+				h.AssertError(t, err, "invalid seccomp profile")
+			})
+		})
+
+		when("unset", func() {
+			it("unsets the profiles", func() {
+				h.AssertNil(t, newCommand(config.Config{SecurityProfiles: config.SecurityProfiles{AppArmor: "some-profile"}}, "--unset").Execute())
+
+				cfg, err := config.Read(configPath)
+				h.AssertNil(t, err)
+				h.AssertEq(t, cfg.SecurityProfiles, config.SecurityProfiles{})
+				h.AssertContains(t, outBuf.String(), "Successfully unset the security profiles")
+			})
+
+			it("errors when profiles are also given", func() {
+				err := newCommand(config.Config{}, "--unset", "--apparmor", "some-profile").Execute()
+				h.AssertError(t, err, "profiles and --unset cannot be specified simultaneously")
+			})
+		})
+	})
+}
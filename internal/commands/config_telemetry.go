@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/internal/telemetry"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+func ConfigTelemetry(logger logging.Logger, cfg config.Config, cfgPath string) *cobra.Command {
+	var endpoint string
+
+	cmd := &cobra.Command{
+		Use:   "telemetry [<true | false>]",
+		Args:  cobra.MaximumNArgs(1),
+		Short: "List and set whether anonymous usage data is reported",
+		Long: "Telemetry is disabled unless you opt in. When enabled, pack reports the command that ran (e.g. `pack build`), " +
+			"its duration, the error code of a failure, the pack version and the OS/architecture to the configured endpoint. " +
+			"Arguments, flag values, image names and paths are never reported.\n\n" +
+			"* Running `pack config telemetry` prints whether telemetry is enabled and where it is reported to.\n" +
+			"* Running `pack config telemetry <true | false>` enables or disables telemetry.\n" +
+			"* Running `pack config telemetry --endpoint <url>` sets the endpoint events are posted to.\n\n" +
+			"Setting DO_NOT_TRACK or PACK_NO_TELEMETRY disables telemetry regardless of the config.",
+		Example: "pack config telemetry true --endpoint https://telemetry.example.com/v1/events",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 && !cmd.Flags().Changed("endpoint") {
+				if cfg.Telemetry {
+					logger.Infof("Telemetry is enabled and reported to %s. To turn it off, run `pack config telemetry false`", style.Symbol(cfg.TelemetryEndpoint))
+				} else {
+					logger.Info("Telemetry isn't currently enabled. To enable it, run `pack config telemetry true --endpoint <url>`")
+				}
+				return nil
+			}
+
+			if cmd.Flags().Changed("endpoint") {
+				if err := telemetry.ValidateEndpoint(endpoint); err != nil {
+					return err
+				}
+				cfg.TelemetryEndpoint = endpoint
+			}
+
+			if len(args) == 1 {
+				val, err := strconv.ParseBool(args[0])
+				if err != nil {
+					return errors.Wrapf(err, "invalid value %s provided", style.Symbol(args[0]))
+				}
+				cfg.Telemetry = val
+			}
+
+			if cfg.Telemetry && cfg.TelemetryEndpoint == "" {
+				return errors.New("an endpoint is required to enable telemetry, provide one with --endpoint")
+			}
+
+			if err := config.Write(cfg, cfgPath); err != nil {
+				return errors.Wrap(err, "writing to config")
+			}
+
+			if cfg.Telemetry {
+				logger.Infof("Telemetry enabled, reporting to %s", style.Symbol(cfg.TelemetryEndpoint))
+			} else {
+				logger.Info("Telemetry disabled")
+			}
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringVar(&endpoint, "endpoint", "", "URL that usage events are posted to as JSON")
+	AddHelpFlag(cmd, "telemetry")
+	return cmd
+}
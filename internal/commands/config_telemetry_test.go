@@ -0,0 +1,104 @@
+package commands_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestConfigTelemetry(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "ConfigTelemetryCommand", testConfigTelemetry, spec.Random(), spec.Report(report.Terminal{}))
+}
+
+func testConfigTelemetry(t *testing.T, when spec.G, it spec.S) {
+	var (
+		logger       logging.Logger
+		outBuf       bytes.Buffer
+		tempPackHome string
+		configPath   string
+	)
+
+	it.Before(func() {
+		var err error
+
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		tempPackHome, err = os.MkdirTemp("", "pack-home")
+		h.AssertNil(t, err)
+		configPath = filepath.Join(tempPackHome, "config.toml")
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.RemoveAll(tempPackHome))
+	})
+
+	newCommand := func(cfg config.Config, args ...string) *cobra.Command {
+		cmd := commands.ConfigTelemetry(logger, cfg, configPath)
+		cmd.SetOut(logging.GetWriterForLevel(logger, logging.InfoLevel))
+		cmd.SetArgs(args)
+		return cmd
+	}
+
+	when("#ConfigTelemetry", func() {
+		when("list values", func() {
+			it("prints a clear message if disabled", func() {
+				h.AssertNil(t, newCommand(config.Config{}).Execute())
+				h.AssertContains(t, outBuf.String(), "Telemetry isn't currently enabled")
+			})
+
+			it("prints the endpoint if enabled", func() {
+				h.AssertNil(t, newCommand(config.Config{Telemetry: true, TelemetryEndpoint: "https://example.com/events"}).Execute())
+				h.AssertContains(t, outBuf.String(), "Telemetry is enabled and reported to 'https://example.com/events'")
+			})
+		})
+
+		when("set", func() {
+			it("enables telemetry with an endpoint", func() {
+				h.AssertNil(t, newCommand(config.Config{}, "true", "--endpoint", "https://example.com/events").Execute())
+				h.AssertContains(t, outBuf.String(), "Telemetry enabled, reporting to 'https://example.com/events'")
+
+				cfg, err := config.Read(configPath)
+				h.AssertNil(t, err)
+				h.AssertEq(t, cfg.Telemetry, true)
+				h.AssertEq(t, cfg.TelemetryEndpoint, "https://example.com/events")
+			})
+
+			it("disables telemetry and keeps the endpoint", func() {
+				h.AssertNil(t, newCommand(config.Config{Telemetry: true, TelemetryEndpoint: "https://example.com/events"}, "false").Execute())
+				h.AssertContains(t, outBuf.String(), "Telemetry disabled")
+
+				cfg, err := config.Read(configPath)
+				h.AssertNil(t, err)
+				h.AssertEq(t, cfg.Telemetry, false)
+				h.AssertEq(t, cfg.TelemetryEndpoint, "https://example.com/events")
+			})
+
+			it("requires an endpoint to enable telemetry", func() {
+				err := newCommand(config.Config{}, "true").Execute()
+				h.AssertError(t, err, "an endpoint is required to enable telemetry")
+			})
+
+			it("rejects an invalid endpoint", func() {
+				err := newCommand(config.Config{}, "--endpoint", "not-a-url").Execute()
+				h.AssertError(t, err, "invalid telemetry endpoint 'not-a-url'")
+			})
+
+			it("rejects an invalid value", func() {
+				err := newCommand(config.Config{}, "maybe").Execute()
+				h.AssertError(t, err, "invalid value 'maybe' provided")
+			})
+		})
+	})
+}
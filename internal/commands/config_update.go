@@ -0,0 +1,106 @@
+package commands
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/internal/update"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+func ConfigUpdate(logger logging.Logger, cfg config.Config, cfgPath string) *cobra.Command {
+	var (
+		updateCfg config.Update
+		unset     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "update",
+		Args:  cobra.NoArgs,
+		Short: "List and set where pack finds new versions of itself",
+		Long: "Configure the release channel, mirror and public key used by `pack update`, and whether pack notices " +
+			"new versions.\n\n" +
+			"* Running `pack config update` prints the update configuration.\n" +
+			"* Running `pack config update --notify` prints a notice after commands when a new version is available, " +
+			"checking for releases at most once a day.\n" +
+			"* Running `pack config update --unset` restores the defaults: the stable channel of the GitHub releases, " +
+			"without notices.",
+		Example: "pack config update --channel pre-release --notify",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			flags := cmd.Flags()
+			changed := flags.Changed("channel") || flags.Changed("mirror") || flags.Changed("public-key") || flags.Changed("notify")
+			switch {
+			case unset:
+				if changed {
+					return errors.New("update settings and --unset cannot be specified simultaneously")
+				}
+				cfg.Update = config.Update{}
+			case !changed:
+				logger.Info(describeUpdateConfig(cfg.Update))
+				return nil
+			}
+
+			if flags.Changed("channel") {
+				if updateCfg.Channel != "" {
+					if err := update.ValidateChannel(updateCfg.Channel); err != nil {
+						return err
+					}
+				}
+				cfg.Update.Channel = updateCfg.Channel
+			}
+			if flags.Changed("mirror") {
+				if updateCfg.Mirror != "" {
+					if mirrorURL, err := url.Parse(updateCfg.Mirror); err != nil || (mirrorURL.Scheme != "http" && mirrorURL.Scheme != "https") || mirrorURL.Host == "" {
+						return errors.Errorf("invalid mirror %s, must be an http or https URL", style.Symbol(updateCfg.Mirror))
+					}
+				}
+				cfg.Update.Mirror = updateCfg.Mirror
+			}
+			if flags.Changed("public-key") {
+				cfg.Update.PublicKey = updateCfg.PublicKey
+			}
+			if flags.Changed("notify") {
+				cfg.Update.Notify = updateCfg.Notify
+			}
+
+			if err := config.Write(cfg, cfgPath); err != nil {
+				return errors.Wrapf(err, "failed to write to config at %s", cfgPath)
+			}
+			logger.Info(describeUpdateConfig(cfg.Update))
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringVar(&updateCfg.Channel, "channel", "", "Release channel, stable or pre-release")
+	cmd.Flags().StringVar(&updateCfg.Mirror, "mirror", "", "URL serving the releases of pack at <mirror>/releases, in the format of the GitHub API")
+	cmd.Flags().StringVar(&updateCfg.PublicKey, "public-key", "", "Path to a PEM encoded public key that release archives must be signed with")
+	cmd.Flags().BoolVar(&updateCfg.Notify, "notify", false, "Print a notice after commands when a new version is available")
+	cmd.Flags().BoolVarP(&unset, "unset", "u", false, "Restore the default update configuration")
+	AddHelpFlag(cmd, "update")
+	return cmd
+}
+
+func describeUpdateConfig(cfg config.Update) string {
+	channel := cfg.Channel
+	if channel == "" {
+		channel = update.ChannelStable
+	}
+	mirror := cfg.Mirror
+	if mirror == "" {
+		mirror = update.DefaultMirror
+	}
+
+	description := fmt.Sprintf("Updates come from the %s channel of %s", channel, style.Symbol(mirror))
+	if cfg.PublicKey != "" {
+		description += fmt.Sprintf(", signed with %s", style.Symbol(cfg.PublicKey))
+	}
+	if cfg.Notify {
+		description += ". New versions are noticed after commands"
+	}
+	return description
+}
@@ -0,0 +1,90 @@
+package commands_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestConfigUpdate(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "ConfigUpdateCommand", testConfigUpdate, spec.Random(), spec.Report(report.Terminal{}))
+}
+
+func testConfigUpdate(t *testing.T, when spec.G, it spec.S) {
+	var (
+		logger       logging.Logger
+		outBuf       bytes.Buffer
+		tempPackHome string
+		configPath   string
+	)
+
+	it.Before(func() {
+		var err error
+
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		tempPackHome, err = os.MkdirTemp("", "pack-home")
+		h.AssertNil(t, err)
+		configPath = filepath.Join(tempPackHome, "config.toml")
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.RemoveAll(tempPackHome))
+	})
+
+	newCommand := func(cfg config.Config, args ...string) *cobra.Command {
+		cmd := commands.ConfigUpdate(logger, cfg, configPath)
+		cmd.SetOut(logging.GetWriterForLevel(logger, logging.InfoLevel))
+		cmd.SetArgs(args)
+		return cmd
+	}
+
+	when("#ConfigUpdate", func() {
+		it("prints the default configuration", func() {
+			h.AssertNil(t, newCommand(config.Config{}).Execute())
+			h.AssertContains(t, outBuf.String(), "Updates come from the stable channel of 'https://api.github.com/repos/buildpacks/pack'")
+		})
+
+		it("sets the channel, mirror and notice", func() {
+			h.AssertNil(t, newCommand(config.Config{}, "--channel", "pre-release", "--mirror", "https://mirror.example.com/pack", "--notify").Execute())
+
+			cfg, err := config.Read(configPath)
+			h.AssertNil(t, err)
+			h.AssertEq(t, cfg.Update, config.Update{Channel: "pre-release", Mirror: "https://mirror.example.com/pack", Notify: true})
+			h.AssertContains(t, outBuf.String(), "Updates come from the pre-release channel of 'https://mirror.example.com/pack'. New versions are noticed after commands")
+		})
+
+		it("keeps the settings that aren't changed", func() {
+			h.AssertNil(t, newCommand(config.Config{Update: config.Update{Channel: "pre-release", Notify: true}}, "--notify=false").Execute())
+
+			cfg, err := config.Read(configPath)
+			h.AssertNil(t, err)
+			h.AssertEq(t, cfg.Update, config.Update{Channel: "pre-release"})
+		})
+
+		it("fails for invalid values", func() {
+			h.AssertError(t, newCommand(config.Config{}, "--channel", "nightly").Execute(), "unknown release channel 'nightly'")
+			h.AssertError(t, newCommand(config.Config{}, "--mirror", "mirror.example.com").Execute(), "invalid mirror 'mirror.example.com', must be an http or https URL")
+		})
+
+		it("restores the defaults with --unset", func() {
+			h.AssertNil(t, newCommand(config.Config{Update: config.Update{Channel: "pre-release"}}, "--unset").Execute())
+
+			cfg, err := config.Read(configPath)
+			h.AssertNil(t, err)
+			h.AssertEq(t, cfg.Update, config.Update{})
+		})
+	})
+}
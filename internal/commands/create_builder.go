@@ -9,6 +9,7 @@ import (
 
 	"github.com/buildpacks/pack/builder"
 	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/i18n"
 	"github.com/buildpacks/pack/internal/style"
 	"github.com/buildpacks/pack/pkg/client"
 	"github.com/buildpacks/pack/pkg/image"
@@ -74,17 +75,19 @@ Creating a custom builder allows you to control what buildpacks are used and wha
 				Config:          builderConfig,
 				Publish:         flags.Publish,
 				Registry:        flags.Registry,
+				RegistryLookup:  flags.RegistryLookup,
 				PullPolicy:      pullPolicy,
 			}); err != nil {
 				return err
 			}
-			logger.Infof("Successfully created builder image %s", style.Symbol(imageName))
+			logger.Info(i18n.T(i18n.BuilderCreateSuccess, style.Symbol(imageName)))
 			logging.Tip(logger, "Run %s to use this builder", style.Symbol(fmt.Sprintf("pack build <image-name> --builder %s", imageName)))
 			return nil
 		}),
 	}
 
 	cmd.Flags().StringVarP(&flags.Registry, "buildpack-registry", "R", cfg.DefaultRegistryName, "Buildpack Registry by name")
+	addRegistryLookupFlags(cmd, &flags.RegistryLookup)
 	if !cfg.Experimental {
 		cmd.Flags().MarkHidden("buildpack-registry")
 	}
@@ -0,0 +1,47 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+func NewDebugCommand(logger logging.Logger, cfgPath string, packClient PackClient) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "debug",
+		Short: "Collect information to troubleshoot pack",
+		RunE:  nil,
+	}
+
+	cmd.AddCommand(DebugCollect(logger, cfgPath, packClient))
+	AddHelpFlag(cmd, "debug")
+	return cmd
+}
+
+// DebugCollect writes a debug bundle to attach to bug reports
+func DebugCollect(logger logging.Logger, cfgPath string, packClient PackClient) *cobra.Command {
+	var outputFile string
+
+	cmd := &cobra.Command{
+		Use:   "collect",
+		Args:  cobra.NoArgs,
+		Short: "Write a debug bundle to attach to bug reports",
+		Long: "Write a gzipped tarball with the version of pack, daemon info, the pack config and the newest logs saved " +
+			"with `--save-logs`. Credentials and values that look like them are redacted. To collect the output, " +
+			"options and plan of a failed build as well, build with `--debug-bundle` instead.",
+		Example: "pack debug collect --output-file pack-debug.tar.gz",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			if outputFile == "" {
+				outputFile = defaultDebugBundlePath(time.Now())
+			}
+			return collectDebugBundle(cmd.Context(), logger, packClient, cfgPath, client.DebugBundleOptions{Path: outputFile})
+		}),
+	}
+
+	cmd.Flags().StringVar(&outputFile, "output-file", "", "Path to write the debug bundle to (defaults to pack-debug-<time>.tar.gz in the current directory)")
+	AddHelpFlag(cmd, "collect")
+	return cmd
+}
@@ -0,0 +1,99 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+// logFileLogger is implemented by loggers that also write their output to a log file, such as logging.LogWithWriters
+type logFileLogger interface {
+	LogFile() io.Writer
+	WantLogFile(w io.Writer)
+}
+
+// outputCapture holds the output of a logger, which may be written to from the goroutines streaming the output
+// of the lifecycle containers
+type outputCapture struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (c *outputCapture) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.buf.Write(p)
+}
+
+func (c *outputCapture) Bytes() []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]byte(nil), c.buf.Bytes()...)
+}
+
+// captureOutput additionally writes the output of logger to the returned capture, until stop is called. Loggers
+// that can't write to a log file are not captured.
+func captureOutput(logger logging.Logger) (capture *outputCapture, stop func()) {
+	capture = &outputCapture{}
+	fileLogger, ok := logger.(logFileLogger)
+	if !ok {
+		return capture, func() {}
+	}
+
+	previous := fileLogger.LogFile()
+	if previous != nil {
+		fileLogger.WantLogFile(io.MultiWriter(previous, capture))
+	} else {
+		fileLogger.WantLogFile(capture)
+	}
+	return capture, func() {
+		fileLogger.WantLogFile(previous)
+	}
+}
+
+// collectDebugBundle writes a debug bundle to path, logging where it was written or why it couldn't be
+func collectDebugBundle(ctx context.Context, logger logging.Logger, packClient PackClient, cfgPath string, opts client.DebugBundleOptions) error {
+	opts.Config = redactedConfig(cfgPath)
+	if packHome, err := config.PackHome(); err == nil {
+		opts.LogDir = filepath.Join(packHome, "logs")
+	}
+
+	names, err := packClient.CollectDebugBundle(ctx, opts)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		logger.Debugf("Added %s", style.Symbol(name))
+	}
+	logger.Infof("Wrote debug bundle to %s, attach it to bug reports after checking it holds nothing confidential", style.Symbol(opts.Path))
+	return nil
+}
+
+// redactedConfig returns the pack config at cfgPath, redacted as by pack report, or nil when there's none
+func redactedConfig(cfgPath string) []byte {
+	data, err := os.ReadFile(filepath.Clean(cfgPath))
+	if err != nil {
+		return nil
+	}
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		lines[i] = sanitize(line)
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+// defaultDebugBundlePath is the path of debug bundles written to the current directory
+func defaultDebugBundlePath(now time.Time) string {
+	return fmt.Sprintf("pack-debug-%s.tar.gz", now.UTC().Format("20060102T150405"))
+}
@@ -0,0 +1,86 @@
+package commands_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestDebugCollectCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "DebugCollectCommand", testDebugCollectCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testDebugCollectCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+		tmpDir         string
+	)
+
+	it.Before(func() {
+		var err error
+		tmpDir, err = os.MkdirTemp("", "debug-collect")
+		h.AssertNil(t, err)
+		h.AssertNil(t, os.WriteFile(filepath.Join(tmpDir, "config.toml"), []byte(`default-builder-image = "registry.example.com/builder"
+experimental = true
+`), 0600))
+
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+		command = commands.DebugCollect(logging.NewLogWithWriters(&outBuf, &outBuf), filepath.Join(tmpDir, "config.toml"), mockClient)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+		h.AssertNil(t, os.RemoveAll(tmpDir))
+	})
+
+	when("#DebugCollect", func() {
+		it("writes a debug bundle with the redacted config", func() {
+			mockClient.EXPECT().
+				CollectDebugBundle(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, opts client.DebugBundleOptions) ([]string, error) {
+					h.AssertEq(t, opts.Path, "bundle.tar.gz")
+					h.AssertEq(t, string(opts.Config), `default-builder-image = "[REDACTED]"
+experimental = true
+`)
+					h.AssertNil(t, opts.Build)
+					return []string{"config.toml", "pack.json"}, nil
+				})
+
+			command.SetArgs([]string{"--output-file", "bundle.tar.gz"})
+			h.AssertNil(t, command.Execute())
+			h.AssertContains(t, outBuf.String(), "Wrote debug bundle to 'bundle.tar.gz'")
+		})
+
+		it("names the bundle after the current time by default", func() {
+			mockClient.EXPECT().
+				CollectDebugBundle(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, opts client.DebugBundleOptions) ([]string, error) {
+					h.AssertContains(t, opts.Path, "pack-debug-")
+					return nil, nil
+				})
+
+			command.SetArgs([]string{})
+			h.AssertNil(t, command.Execute())
+		})
+	})
+}
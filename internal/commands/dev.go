@@ -0,0 +1,166 @@
+package commands
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/internal/watch"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+type DevFlags struct {
+	AppPath        string
+	Builder        string
+	RunImage       string
+	DescriptorPath string
+	Profile        string
+	Policy         string
+	TrustBuilder   bool
+	Buildpacks     []string
+	Env            []string
+	EnvFiles       []string
+	Ports          []string
+	RunEnv         []string
+	NoWatch        bool
+	Ignore         []string
+	PollInterval   time.Duration
+}
+
+// Dev builds an app, runs it, and rebuilds and restarts it when its source code changes
+func Dev(logger logging.Logger, cfg config.Config, packClient PackClient) *cobra.Command {
+	var flags DevFlags
+
+	cmd := &cobra.Command{
+		Use:   "dev <image-name>",
+		Args:  cobra.ExactArgs(1),
+		Short: "Build and run an app, rebuilding it when its source code changes",
+		Long: "Pack Dev builds an app image from source code as Pack Build does, runs it in the docker daemon with the " +
+			"ports and environment variables set with `--port` and `--run-env`, and streams its output. When the source " +
+			"code changes, the app is rebuilt and restarted; when a rebuild fails, the running app is kept until the " +
+			"next change.\n\n" +
+			"Source code is polled for changes, ignoring .git and the paths set with `--ignore`. Stop with Ctrl+C, " +
+			"which also removes the container of the app.",
+		Example: "pack dev my-app --builder cnbs/sample-builder:jammy --port 8080 --run-env LOG_LEVEL=debug",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			if flags.PollInterval <= 0 {
+				return errors.New("poll-interval flag must be positive")
+			}
+			runEnv, err := parseEnv(nil, flags.RunEnv)
+			if err != nil {
+				return err
+			}
+
+			buildFlags := BuildFlags{
+				AppPath:        flags.AppPath,
+				Builder:        flags.Builder,
+				RunImage:       flags.RunImage,
+				DescriptorPath: flags.DescriptorPath,
+				Profile:        flags.Profile,
+				Policy:         flags.Policy,
+				TrustBuilder:   flags.TrustBuilder,
+				Buildpacks:     flags.Buildpacks,
+				Env:            flags.Env,
+				EnvFiles:       flags.EnvFiles,
+				LifecycleImage: cfg.LifecycleImage,
+			}
+			descriptor, actualDescriptorPath, err := readProjectDescriptor(buildFlags, logger)
+			if err != nil {
+				return err
+			}
+
+			appPath := flags.AppPath
+			if appPath == "" {
+				appPath = "."
+			}
+			ignore := append(append([]string{}, watch.DefaultIgnore...), flags.Ignore...)
+			runOptions := client.RunOptions{
+				Image:  args[0],
+				Ports:  flags.Ports,
+				Env:    runEnv,
+				Out:    logging.GetWriterForLevel(logger, logging.InfoLevel),
+				ErrOut: logging.GetWriterForLevel(logger, logging.ErrorLevel),
+			}
+
+			ctx := cmd.Context()
+			stopApp := func() {}
+			defer func() { stopApp() }()
+			for {
+				// changes made while building are noticed by the next wait
+				snapshot, err := watch.Take(appPath, ignore)
+				if err != nil {
+					return errors.Wrap(err, "reading source code")
+				}
+
+				if err := buildImage(cmd, logger, cfg, packClient, buildFlags, args[0], descriptor, actualDescriptorPath); err != nil {
+					if flags.NoWatch {
+						return err
+					}
+					logger.Errorf("Failed to rebuild %s, waiting for changes: %s", style.Symbol(args[0]), err)
+				} else {
+					stopApp()
+					if flags.NoWatch {
+						return packClient.Run(ctx, runOptions)
+					}
+					stopApp = startApp(ctx, logger, packClient, runOptions)
+				}
+
+				_, changes, err := watch.Wait(ctx, appPath, ignore, flags.PollInterval, snapshot)
+				if err != nil {
+					if ctx.Err() != nil {
+						return nil
+					}
+					return errors.Wrap(err, "watching source code")
+				}
+				logger.Infof("%d files changed, rebuilding %s", len(changes), style.Symbol(args[0]))
+				for _, change := range changes {
+					logger.Debugf("  %s", change)
+				}
+			}
+		}),
+	}
+
+	cmd.Flags().StringVarP(&flags.AppPath, "path", "p", "", "Path to app dir (defaults to current working directory)")
+	cmd.Flags().StringVarP(&flags.Builder, "builder", "B", cfg.DefaultBuilder, "Builder image")
+	cmd.Flags().StringVar(&flags.RunImage, "run-image", "", "Run image (defaults to default stack's run image)")
+	cmd.Flags().StringVarP(&flags.DescriptorPath, "descriptor", "d", "", "Path to the project descriptor file")
+	cmd.Flags().StringVar(&flags.Profile, "profile", "", "Profile of the project descriptor to apply, e.g. 'dev'")
+	cmd.Flags().StringVar(&flags.Policy, "pull-policy", "", `Pull policy to use. Accepted values are always, never, and if-not-present. (default "always")`)
+	cmd.Flags().BoolVar(&flags.TrustBuilder, "trust-builder", false, "Trust the provided builder")
+	cmd.Flags().StringSliceVarP(&flags.Buildpacks, "buildpack", "b", nil, "Buildpack to use"+stringSliceHelp("buildpack"))
+	cmd.RegisterFlagCompletionFunc("buildpack", completeRegistryBuildpacks(cfg))
+	cmd.Flags().StringArrayVarP(&flags.Env, "env", "e", []string{}, "Build-time environment variable, in the form 'VAR=VALUE' or 'VAR'"+stringArrayHelp("env"))
+	cmd.Flags().StringArrayVar(&flags.EnvFiles, "env-file", []string{}, "Build-time environment variables file, following the conventions of .env files, one variable per line, of the form 'VAR=VALUE' or 'VAR'")
+	cmd.Flags().StringArrayVar(&flags.Ports, "port", nil, "Port of the app to publish, in the form '<host port>:<container port>', or '<port>' to publish it on the same host port"+stringArrayHelp("port"))
+	cmd.Flags().StringArrayVar(&flags.RunEnv, "run-env", nil, "Environment variable of the running app, in the form 'VAR=VALUE' or 'VAR'"+stringArrayHelp("run-env"))
+	cmd.Flags().BoolVar(&flags.NoWatch, "no-watch", false, "Build and run the app once, without watching the source code")
+	cmd.Flags().StringSliceVar(&flags.Ignore, "ignore", nil, "Path or pattern of files whose changes don't trigger a rebuild, e.g. 'node_modules' or '*.log'"+stringSliceHelp("ignore"))
+	cmd.Flags().DurationVar(&flags.PollInterval, "poll-interval", time.Second, "Interval at which the source code is polled for changes")
+	AddHelpFlag(cmd, "dev")
+	return cmd
+}
+
+// startApp runs the app in the background, and returns a function stopping it
+func startApp(ctx context.Context, logger logging.Logger, packClient PackClient, opts client.RunOptions) func() {
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		logger.Infof("Running %s", style.Symbol(opts.Image))
+		if err := packClient.Run(runCtx, opts); err != nil {
+			logger.Errorf("%s failed, waiting for changes: %s", style.Symbol(opts.Image), err)
+		} else if runCtx.Err() == nil {
+			logger.Infof("%s exited, waiting for changes", style.Symbol(opts.Image))
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
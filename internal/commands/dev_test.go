@@ -0,0 +1,136 @@
+package commands_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/pkg/errors"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestDevCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "DevCommand", testDevCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testDevCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+		appDir         string
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		command = commands.Dev(logger, config.Config{DefaultBuilder: "some/builder"}, mockClient)
+
+		appDir = t.TempDir()
+		h.AssertNil(t, os.WriteFile(filepath.Join(appDir, "main.go"), []byte("package main"), 0600))
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#Dev", func() {
+		when("--no-watch", func() {
+			it("builds and runs the app once", func() {
+				mockClient.EXPECT().Build(gomock.Any(), EqBuildOptionsForApp("some/builder", "my-app", appDir)).Return(nil)
+				mockClient.EXPECT().Run(gomock.Any(), runOptionsMatcher{
+					description: "Image=my-app and Ports=[8080] and Env=LOG_LEVEL=debug",
+					equals: func(o client.RunOptions) bool {
+						return o.Image == "my-app" && len(o.Ports) == 1 && o.Ports[0] == "8080" && o.Env["LOG_LEVEL"] == "debug"
+					},
+				}).Return(nil)
+
+				command.SetArgs([]string{"my-app", "--path", appDir, "--no-watch", "--port", "8080", "--run-env", "LOG_LEVEL=debug"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("errors when the build fails", func() {
+				mockClient.EXPECT().Build(gomock.Any(), gomock.Any()).Return(errors.New("build failed"))
+
+				command.SetArgs([]string{"my-app", "--path", appDir, "--no-watch"})
+				h.AssertError(t, command.Execute(), "build failed")
+			})
+		})
+
+		it("rebuilds and restarts the app when the source code changes", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			runs := 0
+			mockClient.EXPECT().Build(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+			mockClient.EXPECT().Run(gomock.Any(), gomock.Any()).DoAndReturn(func(runCtx context.Context, _ client.RunOptions) error {
+				runs++
+				if runs == 1 {
+					h.AssertNil(t, os.WriteFile(filepath.Join(appDir, "main.go"), []byte("package main\n\nfunc main() {}"), 0600))
+				} else {
+					cancel()
+				}
+				<-runCtx.Done()
+				return nil
+			}).Times(2)
+
+			command.SetArgs([]string{"my-app", "--path", appDir, "--poll-interval", "5ms"})
+			h.AssertNil(t, command.ExecuteContext(ctx))
+			h.AssertContains(t, outBuf.String(), "1 files changed, rebuilding 'my-app'")
+		})
+
+		it("keeps watching when a rebuild fails", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			mockClient.EXPECT().Build(gomock.Any(), gomock.Any()).DoAndReturn(func(context.Context, client.BuildOptions) error {
+				cancel()
+				return errors.New("compilation failed")
+			})
+
+			command.SetArgs([]string{"my-app", "--path", appDir, "--poll-interval", "5ms"})
+			h.AssertNil(t, command.ExecuteContext(ctx))
+			h.AssertContains(t, outBuf.String(), "Failed to rebuild 'my-app', waiting for changes")
+		})
+
+		it("requires a positive poll interval", func() {
+			command.SetArgs([]string{"my-app", "--poll-interval", "0s"})
+			h.AssertError(t, command.Execute(), "poll-interval flag must be positive")
+		})
+	})
+}
+
+type runOptionsMatcher struct {
+	equals      func(client.RunOptions) bool
+	description string
+}
+
+func (m runOptionsMatcher) Matches(x interface{}) bool {
+	if o, ok := x.(client.RunOptions); ok {
+		return m.equals(o)
+	}
+	return false
+}
+
+func (m runOptionsMatcher) String() string {
+	return "is a RunOptions with " + m.description
+}
@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+// Doctor checks the local environment for common problems and suggests how to fix them
+func Doctor(logger logging.Logger, cfg config.Config, packHome string, packClient PackClient) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Args:  cobra.NoArgs,
+		Short: "Check the local environment for common problems",
+		Long: "Check the local environment for common problems: daemon reachability and version, Platform API compatibility " +
+			"of the default builder, free disk space, proxy settings, experimental features and reachability of trusted builders.",
+		Example: "pack doctor",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			var trustedBuilders []string
+			for _, trustedBuilder := range cfg.TrustedBuilders {
+				trustedBuilders = append(trustedBuilders, trustedBuilder.Name)
+			}
+
+			diagnostics := packClient.Doctor(cmd.Context(), client.DoctorOptions{
+				DefaultBuilder:  cfg.DefaultBuilder,
+				TrustedBuilders: trustedBuilders,
+				Directories:     []string{packHome, os.TempDir()},
+			})
+
+			var failed, warnings int
+			for _, diagnostic := range diagnostics {
+				switch diagnostic.Status {
+				case client.DiagnosticFailed:
+					failed++
+				case client.DiagnosticWarning:
+					warnings++
+				}
+				printDiagnostic(logger, diagnostic)
+			}
+
+			logger.Info("")
+			if failed > 0 {
+				return errors.Errorf("%d check(s) failed and %d reported warnings", failed, warnings)
+			}
+			if warnings > 0 {
+				logger.Infof("All checks passed, %d reported warnings", warnings)
+				return nil
+			}
+			logger.Info("All checks passed")
+			return nil
+		}),
+	}
+	AddHelpFlag(cmd, "doctor")
+	return cmd
+}
+
+func printDiagnostic(logger logging.Logger, diagnostic client.Diagnostic) {
+	var status string
+	switch diagnostic.Status {
+	case client.DiagnosticFailed:
+		status = style.Error("[failed] ")
+	case client.DiagnosticWarning:
+		status = style.Warn("[warning]")
+	default:
+		status = style.Key("[ok]     ")
+	}
+
+	logger.Infof("%s %s: %s", status, diagnostic.Name, diagnostic.Message)
+	if diagnostic.Fix != "" {
+		logger.Infof("          Fix: %s", diagnostic.Fix)
+	}
+}
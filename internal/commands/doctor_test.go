@@ -0,0 +1,95 @@
+package commands_test
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestDoctorCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "DoctorCommand", testDoctorCommand, spec.Random(), spec.Report(report.Terminal{}))
+}
+
+func testDoctorCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+		cfg            = config.Config{
+			DefaultBuilder:  "some/builder",
+			TrustedBuilders: []config.TrustedBuilder{{Name: "some/trusted-builder"}},
+		}
+	)
+
+	it.Before(func() {
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+
+		command = commands.Doctor(logger, cfg, "/some/pack-home", mockClient)
+		command.SetArgs([]string{})
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	it("checks the environment with the configured builders and directories", func() {
+		mockClient.EXPECT().
+			Doctor(gomock.Any(), client.DoctorOptions{
+				DefaultBuilder:  "some/builder",
+				TrustedBuilders: []string{"some/trusted-builder"},
+				Directories:     []string{"/some/pack-home", os.TempDir()},
+			}).
+			Return([]client.Diagnostic{{Name: "Docker daemon", Status: client.DiagnosticOK, Message: "version 24.0.7"}})
+
+		h.AssertNil(t, command.Execute())
+		output := outBuf.String()
+		h.AssertContains(t, output, "[ok]      Docker daemon: version 24.0.7")
+		h.AssertContains(t, output, "All checks passed")
+	})
+
+	it("prints fixes for warnings", func() {
+		mockClient.EXPECT().
+			Doctor(gomock.Any(), gomock.Any()).
+			Return([]client.Diagnostic{
+				{Name: "Docker daemon", Status: client.DiagnosticOK, Message: "version 24.0.7"},
+				{Name: "Disk space in /tmp", Status: client.DiagnosticWarning, Message: "1 GB free", Fix: "Free up disk space"},
+			})
+
+		h.AssertNil(t, command.Execute())
+		output := outBuf.String()
+		h.AssertContains(t, output, "[warning] Disk space in /tmp: 1 GB free")
+		h.AssertContains(t, output, "          Fix: Free up disk space")
+		h.AssertContains(t, output, "All checks passed, 1 reported warnings")
+	})
+
+	it("errors when a check failed", func() {
+		mockClient.EXPECT().
+			Doctor(gomock.Any(), gomock.Any()).
+			Return([]client.Diagnostic{
+				{Name: "Docker daemon", Status: client.DiagnosticFailed, Message: "unable to reach the daemon", Fix: "Start Docker"},
+			})
+
+		err := command.Execute()
+		h.AssertError(t, err, "1 check(s) failed and 0 reported warnings")
+		h.AssertContains(t, outBuf.String(), "[failed]  Docker daemon: unable to reach the daemon")
+	})
+}
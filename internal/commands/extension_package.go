@@ -21,6 +21,7 @@ type ExtensionPackageFlags struct {
 	Format          string
 	Publish         bool
 	Policy          string
+	Compression     string
 }
 
 // ExtensionPackager packages extensions
@@ -74,13 +75,19 @@ func ExtensionPackage(logger logging.Logger, cfg config.Config, packager Extensi
 				}
 			}
 
+			compression, err := parseCompressionFlag(flags.Compression, flags.Publish)
+			if err != nil {
+				return err
+			}
+
 			if err := packager.PackageExtension(cmd.Context(), client.PackageBuildpackOptions{
-				RelativeBaseDir: relativeBaseDir,
-				Name:            name,
-				Format:          flags.Format,
-				Config:          exPackageCfg,
-				Publish:         flags.Publish,
-				PullPolicy:      pullPolicy,
+				RelativeBaseDir:  relativeBaseDir,
+				Name:             name,
+				Format:           flags.Format,
+				Config:           exPackageCfg,
+				Publish:          flags.Publish,
+				PullPolicy:       pullPolicy,
+				LayerCompression: compression,
 			}); err != nil {
 				return err
 			}
@@ -95,6 +102,7 @@ func ExtensionPackage(logger logging.Logger, cfg config.Config, packager Extensi
 				location = "file"
 			}
 			logger.Infof("Successfully %s package %s and saved to %s", action, style.Symbol(name), location)
+			logQuietReference(logger, name)
 			return nil
 		}),
 	}
@@ -104,6 +112,7 @@ func ExtensionPackage(logger logging.Logger, cfg config.Config, packager Extensi
 	cmd.Flags().StringVarP(&flags.Format, "format", "f", "", `Format to save package as ("image" or "file")`)
 	cmd.Flags().BoolVar(&flags.Publish, "publish", false, `Publish the extension directly to the container registry specified in <name>, instead of the daemon (applies to "--format=image" only).`)
 	cmd.Flags().StringVar(&flags.Policy, "pull-policy", "", "Pull policy to use. Accepted values are always, never, and if-not-present. The default is always")
+	cmd.Flags().StringVar(&flags.Compression, "compression", string(client.GzipCompression), "Compression of the layers of the published extension, 'gzip' or 'zstd'. zstd layers decompress faster, the extension keeps gzip layers when the registry rejects them. Requires --publish for zstd")
 	AddHelpFlag(cmd, "package")
 	return cmd
 }
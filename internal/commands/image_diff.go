@@ -0,0 +1,140 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/dustin/go-humanize"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+type ImageDiffFlags struct {
+	Remote        bool
+	OutputFormat  string
+	ShowUnchanged bool
+}
+
+// ImageDiff compares two app images
+func ImageDiff(logger logging.Logger, packClient PackClient) *cobra.Command {
+	var flags ImageDiffFlags
+
+	cmd := &cobra.Command{
+		Use:   "diff <base-image> <target-image>",
+		Args:  cobra.ExactArgs(2),
+		Short: "Compare the buildpack layers, dependencies and size of two app images",
+		Long: "Compare two app images built by buildpacks, e.g. two releases of an app. For each buildpack, the layers " +
+			"it added, removed or changed are listed, along with their compressed sizes when the images are read from " +
+			"a registry. Dependencies whose versions changed are listed from the SBoMs of the images, when both have one.",
+		Example: "pack image diff registry.example.com/shop/api:1.4.0 registry.example.com/shop/api:1.5.0 --remote",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			if flags.OutputFormat != "human-readable" && flags.OutputFormat != "json" {
+				return errors.Errorf("unknown output format %s, must be human-readable or json", style.Symbol(flags.OutputFormat))
+			}
+
+			diff, err := packClient.DiffImages(cmd.Context(), args[0], args[1], client.DiffImagesOptions{Daemon: !flags.Remote})
+			if err != nil {
+				return err
+			}
+
+			if flags.OutputFormat == "json" {
+				contents, err := json.MarshalIndent(diff, "", "  ")
+				if err != nil {
+					return err
+				}
+				logger.Info(string(contents))
+				return nil
+			}
+			printImageDiff(logger, *diff, flags.ShowUnchanged)
+			return nil
+		}),
+	}
+
+	cmd.Flags().BoolVar(&flags.Remote, "remote", false, "Compare images in a remote registry (without pulling them)")
+	cmd.Flags().StringVarP(&flags.OutputFormat, "output", "o", "human-readable", "Output format of the comparison, either human-readable or json")
+	cmd.Flags().BoolVar(&flags.ShowUnchanged, "show-unchanged", false, "Also list the layers that didn't change")
+	AddHelpFlag(cmd, "diff")
+	return cmd
+}
+
+func printImageDiff(logger logging.Logger, diff client.ImageDiff, showUnchanged bool) {
+	logger.Infof("Comparing %s to %s", style.Symbol(diff.Base), style.Symbol(diff.Target))
+	logger.Info("")
+
+	tw := tabwriter.NewWriter(logger.Writer(), 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "  Size:\t%s\n", sizeChange(diff.BaseSize, diff.TargetSize))
+	runImage := "unchanged"
+	if diff.RunImageChanged {
+		runImage = fmt.Sprintf("%s -> %s", valueOrUnknown(diff.BaseRunImage), valueOrUnknown(diff.TargetRunImage))
+	}
+	fmt.Fprintf(tw, "  Run image:\t%s\n", runImage)
+	app := "unchanged"
+	if diff.AppChanged {
+		app = "changed"
+	}
+	fmt.Fprintf(tw, "  App:\t%s\n", app)
+	tw.Flush()
+
+	logger.Info("")
+	tw = tabwriter.NewWriter(logger.Writer(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  BUILDPACK\tVERSION\tLAYER\tSTATUS\tSIZE")
+	for _, bp := range diff.Buildpacks {
+		version := valueOrDash(bp.TargetVersion)
+		if bp.BaseVersion != bp.TargetVersion {
+			version = fmt.Sprintf("%s -> %s", valueOrDash(bp.BaseVersion), valueOrDash(bp.TargetVersion))
+		}
+
+		listed := false
+		for _, layer := range bp.Layers {
+			if layer.Status == client.LayerUnchanged && !showUnchanged {
+				continue
+			}
+			fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t%s\n", bp.ID, version, layer.Name, layer.Status, sizeChange(layer.BaseSize, layer.TargetSize))
+			listed = true
+		}
+		if !listed && bp.BaseVersion != bp.TargetVersion {
+			fmt.Fprintf(tw, "  %s\t%s\t-\t-\t-\n", bp.ID, version)
+		}
+	}
+	tw.Flush()
+
+	logger.Info("")
+	if !diff.DependenciesKnown {
+		logger.Info("  Dependencies weren't compared, since an image has no SBoM")
+		return
+	}
+	if len(diff.Dependencies) == 0 {
+		logger.Info("  No dependency changed")
+		return
+	}
+	tw = tabwriter.NewWriter(logger.Writer(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  DEPENDENCY\tBASE\tTARGET")
+	for _, dependency := range diff.Dependencies {
+		fmt.Fprintf(tw, "  %s\t%s\t%s\n", dependency.Name, valueOrDash(dependency.BaseVersion), valueOrDash(dependency.TargetVersion))
+	}
+	tw.Flush()
+}
+
+// sizeChange describes the change from the size base to the size target, either of which is 0 when unknown or
+// missing
+func sizeChange(base, target int64) string {
+	switch {
+	case base == 0 && target == 0:
+		return "-"
+	case base == 0:
+		return humanize.Bytes(uint64(target))
+	case target == 0:
+		return humanize.Bytes(uint64(base))
+	case base == target:
+		return humanize.Bytes(uint64(target))
+	case target > base:
+		return fmt.Sprintf("%s -> %s (+%s)", humanize.Bytes(uint64(base)), humanize.Bytes(uint64(target)), humanize.Bytes(uint64(target-base)))
+	default:
+		return fmt.Sprintf("%s -> %s (-%s)", humanize.Bytes(uint64(base)), humanize.Bytes(uint64(target)), humanize.Bytes(uint64(base-target)))
+	}
+}
@@ -0,0 +1,128 @@
+package commands_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	cpkg "github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestImageDiffCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "ImageDiffCommand", testImageDiffCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testImageDiffCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+		diff           *cpkg.ImageDiff
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+		command = commands.ImageDiff(logging.NewLogWithWriters(&outBuf, &outBuf), mockClient)
+
+		diff = &cpkg.ImageDiff{
+			Base:       "some/app:1",
+			Target:     "some/app:2",
+			BaseSize:   200_000_000,
+			TargetSize: 250_000_000,
+			AppChanged: true,
+			Buildpacks: []cpkg.BuildpackDiff{
+				{
+					ID:            "example/java",
+					BaseVersion:   "1.0.0",
+					TargetVersion: "1.1.0",
+					Layers: []cpkg.LayerDiff{
+						{Name: "helper", Status: cpkg.LayerUnchanged},
+						{Name: "jre", Status: cpkg.LayerChanged, BaseSize: 40_000_000, TargetSize: 45_000_000},
+					},
+				},
+				{ID: "example/procfile", BaseVersion: "2.0.0"},
+			},
+			DependenciesKnown: true,
+			Dependencies: []cpkg.DependencyDiff{
+				{Name: "jre", BaseVersion: "17.0.1", TargetVersion: "21.0.2"},
+			},
+		}
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#ImageDiff", func() {
+		it("prints the changed layers and dependencies", func() {
+			mockClient.EXPECT().DiffImages(gomock.Any(), "some/app:1", "some/app:2", cpkg.DiffImagesOptions{Daemon: true}).Return(diff, nil)
+
+			command.SetArgs([]string{"some/app:1", "some/app:2"})
+			h.AssertNil(t, command.Execute())
+
+			h.AssertContains(t, outBuf.String(), "Comparing 'some/app:1' to 'some/app:2'")
+			h.AssertContains(t, outBuf.String(), "200 MB -> 250 MB (+50 MB)")
+			h.AssertContains(t, outBuf.String(), "example/java      1.0.0 -> 1.1.0  jre    changed  40 MB -> 45 MB (+5.0 MB)")
+			h.AssertContains(t, outBuf.String(), "example/procfile  2.0.0 -> -")
+			h.AssertNotContains(t, outBuf.String(), "helper")
+			h.AssertContains(t, outBuf.String(), "jre         17.0.1  21.0.2")
+		})
+
+		it("lists unchanged layers with --show-unchanged", func() {
+			mockClient.EXPECT().DiffImages(gomock.Any(), "some/app:1", "some/app:2", gomock.Any()).Return(diff, nil)
+
+			command.SetArgs([]string{"some/app:1", "some/app:2", "--show-unchanged"})
+			h.AssertNil(t, command.Execute())
+
+			h.AssertContains(t, outBuf.String(), "helper  unchanged")
+		})
+
+		it("compares remote images with --remote", func() {
+			mockClient.EXPECT().DiffImages(gomock.Any(), "some/app:1", "some/app:2", cpkg.DiffImagesOptions{Daemon: false}).Return(diff, nil)
+
+			command.SetArgs([]string{"some/app:1", "some/app:2", "--remote"})
+			h.AssertNil(t, command.Execute())
+		})
+
+		it("prints the comparison as json with --output json", func() {
+			mockClient.EXPECT().DiffImages(gomock.Any(), "some/app:1", "some/app:2", gomock.Any()).Return(diff, nil)
+
+			command.SetArgs([]string{"some/app:1", "some/app:2", "--output", "json"})
+			h.AssertNil(t, command.Execute())
+
+			var printed cpkg.ImageDiff
+			h.AssertNil(t, json.Unmarshal(outBuf.Bytes(), &printed))
+			h.AssertEq(t, printed, *diff)
+		})
+
+		it("reports images without SBoMs", func() {
+			diff.DependenciesKnown = false
+			diff.Dependencies = nil
+			mockClient.EXPECT().DiffImages(gomock.Any(), "some/app:1", "some/app:2", gomock.Any()).Return(diff, nil)
+
+			command.SetArgs([]string{"some/app:1", "some/app:2"})
+			h.AssertNil(t, command.Execute())
+
+			h.AssertContains(t, outBuf.String(), "Dependencies weren't compared, since an image has no SBoM")
+		})
+
+		it("fails for an unknown output format", func() {
+			command.SetArgs([]string{"some/app:1", "some/app:2", "--output", "yaml"})
+			h.AssertError(t, command.Execute(), "unknown output format 'yaml'")
+		})
+	})
+}
@@ -0,0 +1,43 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+type ImageSetDefaultProcessFlags struct {
+	Publish bool
+}
+
+// ImageSetDefaultProcess changes the default process of an app image
+func ImageSetDefaultProcess(logger logging.Logger, packClient PackClient) *cobra.Command {
+	var flags ImageSetDefaultProcessFlags
+
+	cmd := &cobra.Command{
+		Use:   "set-default-process <image-name> <process-type>",
+		Args:  cobra.ExactArgs(2),
+		Short: "Change the process an app image runs by default, without rebuilding it",
+		Long: "Change the process an app image runs by default to another of the processes contributed by its " +
+			"buildpacks, e.g. to run a worker rather than a web server. Only the configuration of the image changes, " +
+			"its layers are kept.",
+		Example: "pack image set-default-process registry.example.com/shop/api:1.4.0 worker --publish",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			if err := packClient.SetDefaultProcess(cmd.Context(), client.SetDefaultProcessOptions{
+				Image:       args[0],
+				ProcessType: args[1],
+				Publish:     flags.Publish,
+			}); err != nil {
+				return err
+			}
+			logger.Infof("Set the default process of %s to %s", style.Symbol(args[0]), style.Symbol(args[1]))
+			return nil
+		}),
+	}
+
+	cmd.Flags().BoolVar(&flags.Publish, "publish", false, "Change the image in its registry, rather than in the docker daemon")
+	AddHelpFlag(cmd, "set-default-process")
+	return cmd
+}
@@ -0,0 +1,65 @@
+package commands_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/pkg/errors"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestImageSetDefaultProcessCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "ImageSetDefaultProcessCommand", testImageSetDefaultProcessCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testImageSetDefaultProcessCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+		command = commands.ImageSetDefaultProcess(logging.NewLogWithWriters(&outBuf, &outBuf), mockClient)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#ImageSetDefaultProcess", func() {
+		it("sets the default process of the image", func() {
+			mockClient.EXPECT().SetDefaultProcess(gomock.Any(), client.SetDefaultProcessOptions{
+				Image:       "some/app",
+				ProcessType: "worker",
+				Publish:     true,
+			}).Return(nil)
+
+			command.SetArgs([]string{"some/app", "worker", "--publish"})
+			h.AssertNil(t, command.Execute())
+			h.AssertContains(t, outBuf.String(), "Set the default process of 'some/app' to 'worker'")
+		})
+
+		it("fails when the default process can't be set", func() {
+			mockClient.EXPECT().SetDefaultProcess(gomock.Any(), gomock.Any()).Return(errors.New("image 'some/app' has no process 'cron'"))
+
+			command.SetArgs([]string{"some/app", "cron"})
+			h.AssertError(t, command.Execute(), "image 'some/app' has no process 'cron'")
+		})
+	})
+}
@@ -21,6 +21,14 @@ import (
 
 const inspectBuildpackTemplate = `
 {{ .Location -}}:
+{{- with .Registry }}
+
+Registry Entry:
+  Address: {{ .Address }}
+  Description: {{ .Description }}
+  Licenses: {{ .Licenses }}
+  Stacks: {{ .Stacks }}
+{{- end }}
 
 Stacks:
 {{- range $stackIndex, $stack := .Metadata.Stacks }}
@@ -81,6 +89,7 @@ func InspectBuildpack(logger logging.Logger, cfg config.Config, client PackClien
 	}
 	cmd.Flags().IntVarP(&flags.Depth, "depth", "d", -1, "Max depth to display for Detection Order.\nOmission of this flag or values < 0 will display the entire tree.")
 	cmd.Flags().StringVarP(&flags.Registry, "registry", "r", "", "buildpack registry that may be searched")
+	addRegistryLookupFlags(cmd, &flags.RegistryLookup)
 	cmd.Flags().BoolVarP(&flags.Verbose, "verbose", "v", false, "show more output")
 	AddHelpFlag(cmd, "inspect-buildpack")
 	return cmd
@@ -129,14 +138,32 @@ func inspectBuildpackOutput(info *client.BuildpackInfo, prefix string, flags Bui
 	}
 	buf := bytes.NewBuffer(nil)
 
+	type registryEntry struct {
+		Address     string
+		Description string
+		Licenses    string
+		Stacks      string
+	}
+	var entry *registryEntry
+	if info.Registry != nil {
+		entry = &registryEntry{
+			Address:     info.Registry.Address,
+			Description: strs.ValueOrDefault(info.Registry.Description, "(none)"),
+			Licenses:    strs.ValueOrDefault(strings.Join(info.Registry.Licenses, ", "), "(none)"),
+			Stacks:      strs.ValueOrDefault(strings.Join(info.Registry.Stacks, ", "), "(none)"),
+		}
+	}
+
 	err = tpl.Execute(buf, &struct {
 		Location   string
+		Registry   *registryEntry
 		Metadata   buildpack.Metadata
 		ListMixins bool
 		Buildpacks string
 		Order      string
 	}{
 		Location:   prefix,
+		Registry:   entry,
 		Metadata:   info.BuildpackMetadata,
 		ListMixins: flags.Verbose,
 		Buildpacks: bpOutput,
@@ -0,0 +1,132 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+type LifecycleInspectFlags struct {
+	Remote       bool
+	OutputFormat string
+}
+
+func NewLifecycleCommand(logger logging.Logger, cfg config.Config, packClient PackClient) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lifecycle",
+		Short: "Interact with the lifecycles of builders",
+		RunE:  nil,
+	}
+
+	cmd.AddCommand(LifecycleInspect(logger, cfg, packClient))
+	AddHelpFlag(cmd, "lifecycle")
+	return cmd
+}
+
+// LifecycleInspect shows the APIs supported by the lifecycle of a builder, and whether pack and the buildpacks of the
+// builder are compatible with them
+func LifecycleInspect(logger logging.Logger, cfg config.Config, packClient PackClient) *cobra.Command {
+	var flags LifecycleInspectFlags
+
+	cmd := &cobra.Command{
+		Use:   "inspect <builder-image-name>",
+		Args:  cobra.MaximumNArgs(1),
+		Short: "Show the lifecycle of a builder and whether pack and its buildpacks are compatible with it",
+		Long: "Show the version of the lifecycle of a builder and the Platform and Buildpack APIs it supports, next to " +
+			"those of this version of pack and of the buildpacks of the builder. Incompatibilities are flagged, so that " +
+			"they are found before a build is attempted. If no argument is provided, the default builder is inspected, " +
+			"if one has been set.",
+		Example: "pack lifecycle inspect paketobuildpacks/builder-jammy-base",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			if flags.OutputFormat != "human-readable" && flags.OutputFormat != "json" {
+				return errors.Errorf("unknown output format %s, must be human-readable or json", style.Symbol(flags.OutputFormat))
+			}
+
+			imageName := cfg.DefaultBuilder
+			if len(args) >= 1 {
+				imageName = args[0]
+			}
+			if imageName == "" {
+				suggestSettingBuilder(logger, packClient)
+				return client.NewSoftError()
+			}
+
+			compatibility, err := packClient.InspectLifecycle(cmd.Context(), client.InspectLifecycleOptions{
+				Builder: imageName,
+				Daemon:  !flags.Remote,
+			})
+			if err != nil {
+				return err
+			}
+
+			if flags.OutputFormat == "json" {
+				contents, err := json.MarshalIndent(compatibility, "", "  ")
+				if err != nil {
+					return err
+				}
+				logger.Info(string(contents))
+			} else {
+				printLifecycleCompatibility(logger, *compatibility)
+			}
+
+			if !compatibility.Compatible() {
+				return errors.Errorf("builder %s is incompatible with its lifecycle", style.Symbol(imageName))
+			}
+			return nil
+		}),
+	}
+
+	cmd.Flags().BoolVar(&flags.Remote, "remote", false, "Only inspect the builder in a remote registry, even when the daemon has it")
+	cmd.Flags().StringVarP(&flags.OutputFormat, "output", "o", "human-readable", "Output format of the compatibility matrix, either human-readable or json")
+	AddHelpFlag(cmd, "inspect")
+	return cmd
+}
+
+func printLifecycleCompatibility(logger logging.Logger, compatibility client.LifecycleCompatibility) {
+	logger.Infof("Builder: %s", style.Symbol(compatibility.Builder))
+	logger.Infof("Lifecycle version: %s", valueOrUnknown(compatibility.LifecycleVersion))
+	logger.Infof("Pack version: %s", valueOrUnknown(compatibility.PackVersion))
+	logger.Info("")
+
+	tw := tabwriter.NewWriter(logger.Writer(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  API\tPACK\tLIFECYCLE\tLIFECYCLE DEPRECATED")
+	fmt.Fprintf(tw, "  Platform\t%s\t%s\t%s\n",
+		joinOrDash(compatibility.PackPlatformAPIs),
+		joinOrDash(compatibility.PlatformAPIs.Supported.AsStrings()),
+		joinOrDash(compatibility.PlatformAPIs.Deprecated.AsStrings()))
+	fmt.Fprintf(tw, "  Buildpack\t-\t%s\t%s\n",
+		joinOrDash(compatibility.BuildpackAPIs.Supported.AsStrings()),
+		joinOrDash(compatibility.BuildpackAPIs.Deprecated.AsStrings()))
+	tw.Flush()
+
+	if len(compatibility.Buildpacks) > 0 {
+		logger.Info("")
+		tw = tabwriter.NewWriter(logger.Writer(), 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "  BUILDPACK\tVERSION\tAPI\tSTATUS")
+		for _, buildpack := range compatibility.Buildpacks {
+			fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n", buildpack.ID, valueOrDash(buildpack.Version), valueOrDash(buildpack.API), buildpack.Status)
+		}
+		tw.Flush()
+	}
+
+	logger.Info("")
+	for _, diagnostic := range compatibility.Diagnostics {
+		printDiagnostic(logger, diagnostic)
+	}
+}
+
+func joinOrDash(apis []string) string {
+	if len(apis) == 0 {
+		return "-"
+	}
+	return strings.Join(apis, ", ")
+}
@@ -0,0 +1,113 @@
+package commands_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/buildpacks/lifecycle/api"
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/builder"
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestLifecycleCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "LifecycleCommand", testLifecycleCommand, spec.Random(), spec.Report(report.Terminal{}))
+}
+
+func testLifecycleCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+		compatibility  client.LifecycleCompatibility
+	)
+
+	it.Before(func() {
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+
+		command = commands.NewLifecycleCommand(logger, config.Config{DefaultBuilder: "some/default-builder"}, mockClient)
+
+		compatibility = client.LifecycleCompatibility{
+			Builder:          "some/builder",
+			LifecycleVersion: "0.20.1",
+			PlatformAPIs:     builder.APIVersions{Supported: builder.APISet{api.MustParse("0.12"), api.MustParse("0.13")}},
+			BuildpackAPIs:    builder.APIVersions{Deprecated: builder.APISet{api.MustParse("0.2")}, Supported: builder.APISet{api.MustParse("0.10")}},
+			PackVersion:      "0.36.0",
+			PackPlatformAPIs: []string{"0.12", "0.13"},
+			PlatformAPI:      "0.13",
+			Buildpacks:       []client.BuildpackCompatibility{{ID: "some/bp", Version: "1.0.0", API: "0.10", Status: client.DiagnosticOK}},
+			Diagnostics:      []client.Diagnostic{{Name: "Platform API", Status: client.DiagnosticOK, Message: "builds will use Platform API 0.13"}},
+		}
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#LifecycleInspect", func() {
+		it("prints the compatibility matrix of the builder", func() {
+			mockClient.EXPECT().
+				InspectLifecycle(gomock.Any(), client.InspectLifecycleOptions{Builder: "some/builder", Daemon: true}).
+				Return(&compatibility, nil)
+
+			command.SetArgs([]string{"inspect", "some/builder"})
+			h.AssertNil(t, command.Execute())
+
+			output := outBuf.String()
+			h.AssertContains(t, output, "Lifecycle version: 0.20.1")
+			h.AssertContainsMatch(t, output, `Platform\s+0.12, 0.13\s+0.12, 0.13\s+-`)
+			h.AssertContainsMatch(t, output, `Buildpack\s+-\s+0.10\s+0.2`)
+			h.AssertContainsMatch(t, output, `some/bp\s+1.0.0\s+0.10\s+ok`)
+			h.AssertContains(t, output, "[ok]      Platform API: builds will use Platform API 0.13")
+		})
+
+		it("inspects the default builder", func() {
+			mockClient.EXPECT().
+				InspectLifecycle(gomock.Any(), client.InspectLifecycleOptions{Builder: "some/default-builder"}).
+				Return(&compatibility, nil)
+
+			command.SetArgs([]string{"inspect", "--remote"})
+			h.AssertNil(t, command.Execute())
+		})
+
+		it("fails when the builder is incompatible", func() {
+			compatibility.Diagnostics = append(compatibility.Diagnostics, client.Diagnostic{
+				Name: "Buildpack API", Status: client.DiagnosticFailed, Message: "the lifecycle doesn't support the Buildpack API of some/bp@1.0.0 (0.1)",
+			})
+			mockClient.EXPECT().InspectLifecycle(gomock.Any(), gomock.Any()).Return(&compatibility, nil)
+
+			command.SetArgs([]string{"inspect", "some/builder"})
+			h.AssertError(t, command.Execute(), "builder 'some/builder' is incompatible with its lifecycle")
+			h.AssertContains(t, outBuf.String(), "[failed]  Buildpack API: the lifecycle doesn't support the Buildpack API of some/bp@1.0.0 (0.1)")
+		})
+
+		it("prints json", func() {
+			mockClient.EXPECT().InspectLifecycle(gomock.Any(), gomock.Any()).Return(&compatibility, nil)
+
+			command.SetArgs([]string{"inspect", "some/builder", "--output", "json"})
+			h.AssertNil(t, command.Execute())
+			h.AssertContains(t, outBuf.String(), `"platformApi": "0.13"`)
+		})
+
+		it("errors for an unknown output format", func() {
+			command.SetArgs([]string{"inspect", "some/builder", "--output", "yaml"})
+			h.AssertError(t, command.Execute(), "unknown output format 'yaml'")
+		})
+	})
+}
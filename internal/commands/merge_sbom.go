@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/sbom"
+	"github.com/buildpacks/pack/internal/style"
+	cpkg "github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+type MergeSBOMFlags struct {
+	Remote     bool
+	Format     string
+	OutputFile string
+	SignKey    string
+}
+
+func MergeSBOM(logger logging.Logger, client PackClient) *cobra.Command {
+	var flags MergeSBOMFlags
+	cmd := &cobra.Command{
+		Use:   "merge <image-name>",
+		Args:  cobra.ExactArgs(1),
+		Short: "Merge the SBoMs of an image into a single document",
+		Long: "Merge the CycloneDX and SPDX SBoMs written by buildpacks for the launch layers of an image into a single " +
+			"CycloneDX or SPDX JSON document. SBoMs in the other format are converted, keeping the name, version, purl and " +
+			"licenses of their components.\n\n" +
+			"With --sign-key, a base64 encoded signature of the document is written next to it, with a .sig extension. " +
+			"ECDSA and RSA keys sign the SHA-256 digest of the document, Ed25519 keys sign the document itself.",
+		Example: "pack sbom merge buildpacksio/pack --format spdx --output-file sbom.spdx.json",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			if flags.SignKey != "" && flags.OutputFile == "" {
+				return errors.New("--sign-key requires --output-file")
+			}
+
+			doc, err := client.MergeSBOM(args[0], cpkg.MergeSBOMOptions{
+				Daemon: !flags.Remote,
+				Format: flags.Format,
+			})
+			if err != nil {
+				return err
+			}
+
+			if flags.OutputFile == "" {
+				logger.Info(string(doc))
+				return nil
+			}
+
+			if err := os.WriteFile(flags.OutputFile, append(doc, '\n'), 0644); err != nil {
+				return errors.Wrapf(err, "writing SBoM to %s", style.Symbol(flags.OutputFile))
+			}
+			logger.Infof("SBoM of %s written to %s", style.Symbol(args[0]), style.Symbol(flags.OutputFile))
+
+			if flags.SignKey == "" {
+				return nil
+			}
+			key, err := os.ReadFile(flags.SignKey)
+			if err != nil {
+				return errors.Wrap(err, "reading signing key")
+			}
+			signature, err := sbom.Sign(append(doc, '\n'), key)
+			if err != nil {
+				return err
+			}
+			signaturePath := flags.OutputFile + ".sig"
+			if err := os.WriteFile(signaturePath, []byte(signature), 0644); err != nil {
+				return errors.Wrapf(err, "writing signature to %s", style.Symbol(signaturePath))
+			}
+			logger.Infof("Signature written to %s", style.Symbol(signaturePath))
+			return nil
+		}),
+	}
+	AddHelpFlag(cmd, "merge")
+	cmd.Flags().BoolVar(&flags.Remote, "remote", false, "Merge the SBoM of an image in a remote registry (without pulling the image)")
+	cmd.Flags().StringVarP(&flags.Format, "format", "f", string(sbom.FormatCycloneDX), "Format of the merged SBoM, either cyclonedx or spdx")
+	cmd.Flags().StringVarP(&flags.OutputFile, "output-file", "o", "", "Path to write the merged SBoM to, instead of printing it")
+	cmd.Flags().StringVar(&flags.SignKey, "sign-key", "", "Path to a PEM encoded private key to sign the merged SBoM with")
+	return cmd
+}
@@ -0,0 +1,99 @@
+package commands_test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	cpkg "github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestMergeSBOMCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "MergeSBOMCommand", testMergeSBOMCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testMergeSBOMCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+		tmpDir         string
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+		command = commands.MergeSBOM(logging.NewLogWithWriters(&outBuf, &outBuf), mockClient)
+
+		var err error
+		tmpDir, err = os.MkdirTemp("", "pack.merge.sbom.command.")
+		h.AssertNil(t, err)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+		os.RemoveAll(tmpDir)
+	})
+
+	when("#MergeSBOM", func() {
+		it("prints the merged SBoM", func() {
+			mockClient.EXPECT().MergeSBOM("some/image", cpkg.MergeSBOMOptions{Daemon: true, Format: "cyclonedx"}).
+				Return([]byte(`{"bomFormat": "CycloneDX"}`), nil)
+			command.SetArgs([]string{"some/image"})
+
+			h.AssertNil(t, command.Execute())
+			h.AssertContains(t, outBuf.String(), `{"bomFormat": "CycloneDX"}`)
+		})
+
+		it("writes the merged SBoM and its signature to files", func() {
+			public, private, err := ed25519.GenerateKey(rand.Reader)
+			h.AssertNil(t, err)
+			der, err := x509.MarshalPKCS8PrivateKey(private)
+			h.AssertNil(t, err)
+			keyPath := filepath.Join(tmpDir, "key.pem")
+			h.AssertNil(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600))
+
+			outputPath := filepath.Join(tmpDir, "sbom.spdx.json")
+			mockClient.EXPECT().MergeSBOM("some/image", cpkg.MergeSBOMOptions{Daemon: false, Format: "spdx"}).
+				Return([]byte(`{"spdxVersion": "SPDX-2.3"}`), nil)
+			command.SetArgs([]string{"some/image", "--remote", "--format", "spdx", "--output-file", outputPath, "--sign-key", keyPath})
+
+			h.AssertNil(t, command.Execute())
+
+			doc, err := os.ReadFile(outputPath)
+			h.AssertNil(t, err)
+			h.AssertEq(t, string(doc), "{\"spdxVersion\": \"SPDX-2.3\"}\n")
+
+			signature, err := os.ReadFile(outputPath + ".sig")
+			h.AssertNil(t, err)
+			decoded, err := base64.StdEncoding.DecodeString(string(signature))
+			h.AssertNil(t, err)
+			h.AssertTrue(t, ed25519.Verify(public, doc, decoded))
+		})
+
+		it("requires an output file to sign", func() {
+			command.SetArgs([]string{"some/image", "--sign-key", "key.pem"})
+
+			h.AssertError(t, command.Execute(), "--sign-key requires --output-file")
+		})
+	})
+}
@@ -0,0 +1,19 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+func NewMigrateCommand(logger logging.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Migrate configuration files away from deprecated features",
+		RunE:  nil,
+	}
+
+	cmd.AddCommand(MigrateTargets(logger))
+	AddHelpFlag(cmd, "migrate")
+	return cmd
+}
@@ -0,0 +1,103 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/migrate"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+type MigrateTargetsFlags struct {
+	Path   string
+	DryRun bool
+}
+
+// MigrateTargets rewrites builder, buildpack and package configuration files from stacks to targets
+func MigrateTargets(logger logging.Logger) *cobra.Command {
+	var flags MigrateTargetsFlags
+
+	cmd := &cobra.Command{
+		Use:   "targets [<file>...]",
+		Short: "Migrate builder.toml, buildpack.toml and package.toml from stacks to targets",
+		Long: "Rewrite the [stack] of a builder.toml as [build] and [[run.images]], the [[stacks]] of a buildpack.toml " +
+			"as [[targets]] and the [platform] of a package.toml as [[targets]]. Comments and other tables are kept.\n\n" +
+			"Without arguments, the builder.toml, buildpack.toml and package.toml found in --path are migrated. " +
+			"The lifecycle of builders and the Buildpack API of buildpacks are checked to support targets, and anything " +
+			"that can't be migrated automatically, like mixins, is reported as a warning.",
+		Example: "pack migrate targets --dry-run\npack migrate targets path/to/builder.toml",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			files := args
+			if len(files) == 0 {
+				for _, name := range []string{"builder.toml", "buildpack.toml", "package.toml"} {
+					file := filepath.Join(flags.Path, name)
+					if _, err := os.Stat(file); err == nil {
+						files = append(files, file)
+					}
+				}
+				if len(files) == 0 {
+					return errors.Errorf("no builder.toml, buildpack.toml or package.toml found in %s", style.Symbol(displayPath(flags.Path)))
+				}
+			}
+
+			for _, file := range files {
+				if err := migrateTargets(logger, file, flags.DryRun); err != nil {
+					return err
+				}
+			}
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringVarP(&flags.Path, "path", "p", "", "Path to the directory containing the files to migrate (defaults to current working directory)")
+	cmd.Flags().BoolVar(&flags.DryRun, "dry-run", false, "Print the migrated files instead of writing them")
+	AddHelpFlag(cmd, "targets")
+	return cmd
+}
+
+func migrateTargets(logger logging.Logger, file string, dryRun bool) error {
+	contents, err := os.ReadFile(filepath.Clean(file))
+	if err != nil {
+		return errors.Wrapf(err, "reading %s", style.Symbol(file))
+	}
+
+	result, err := migrate.Targets(file, string(contents))
+	if err != nil {
+		return errors.Wrapf(err, "migrating %s", style.Symbol(file))
+	}
+
+	switch {
+	case !result.Changed():
+		logger.Infof("%s doesn't declare stacks, nothing to migrate", style.Symbol(file))
+	case dryRun:
+		logger.Infof("%s would be migrated to:\n%s", style.Symbol(file), result.Contents)
+	default:
+		info, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(file, []byte(result.Contents), info.Mode().Perm()); err != nil {
+			return errors.Wrapf(err, "writing %s", style.Symbol(file))
+		}
+		logger.Infof("Migrated %s configuration %s", result.Kind, style.Symbol(file))
+	}
+
+	for _, change := range result.Changes {
+		logger.Infof("  - %s", change)
+	}
+	for _, attention := range result.Attention {
+		logger.Warnf("%s: %s", file, attention)
+	}
+	return nil
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return "."
+	}
+	return path
+}
@@ -0,0 +1,93 @@
+package commands_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestMigrateTargetsCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "MigrateTargetsCommand", testMigrateTargetsCommand, spec.Random(), spec.Report(report.Terminal{}))
+}
+
+func testMigrateTargetsCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command *cobra.Command
+		outBuf  bytes.Buffer
+		dir     string
+	)
+
+	const buildpackToml = `api = "0.10"
+
+[buildpack]
+id = "example/bp"
+
+[[stacks]]
+id = "*"
+`
+
+	it.Before(func() {
+		var err error
+		command = commands.MigrateTargets(logging.NewLogWithWriters(&outBuf, &outBuf))
+		dir, err = os.MkdirTemp("", "migrate-targets")
+		h.AssertNil(t, err)
+		h.AssertNil(t, os.WriteFile(filepath.Join(dir, "buildpack.toml"), []byte(buildpackToml), 0600))
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.RemoveAll(dir))
+	})
+
+	readFile := func(name string) string {
+		contents, err := os.ReadFile(filepath.Join(dir, name))
+		h.AssertNil(t, err)
+		return string(contents)
+	}
+
+	when("#MigrateTargets", func() {
+		it("migrates the files found in the path", func() {
+			h.AssertNil(t, os.WriteFile(filepath.Join(dir, "package.toml"), []byte("[buildpack]\nuri = \".\"\n"), 0600))
+
+			command.SetArgs([]string{"--path", dir})
+			h.AssertNil(t, command.Execute())
+
+			h.AssertContains(t, outBuf.String(), "Migrated buildpack configuration")
+			h.AssertContains(t, outBuf.String(), "  - added a linux target for any distribution")
+			h.AssertContains(t, outBuf.String(), "package.toml' doesn't declare stacks, nothing to migrate")
+			h.AssertContains(t, readFile("buildpack.toml"), "[[targets]]\nos = \"linux\"\n")
+		})
+
+		it("doesn't write files on a dry run", func() {
+			command.SetArgs([]string{"--dry-run", filepath.Join(dir, "buildpack.toml")})
+			h.AssertNil(t, command.Execute())
+
+			h.AssertContains(t, outBuf.String(), "would be migrated to:")
+			h.AssertEq(t, readFile("buildpack.toml"), buildpackToml)
+		})
+
+		it("warns about what needs manual attention", func() {
+			h.AssertNil(t, os.WriteFile(filepath.Join(dir, "builder.toml"), []byte("[lifecycle]\nversion = \"0.15.0\"\n"), 0600))
+
+			command.SetArgs([]string{filepath.Join(dir, "builder.toml")})
+			h.AssertNil(t, command.Execute())
+			h.AssertContains(t, outBuf.String(), "Warning: "+filepath.Join(dir, "builder.toml")+": lifecycle '0.15.0' doesn't support targets")
+		})
+
+		it("fails without files to migrate", func() {
+			command.SetArgs([]string{"--path", filepath.Join(dir, "empty")})
+			h.AssertError(t, command.Execute(), "no builder.toml, buildpack.toml or package.toml found in")
+		})
+	})
+}
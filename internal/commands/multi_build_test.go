@@ -0,0 +1,196 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/pkg/errors"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/termui"
+	"github.com/buildpacks/pack/internal/termui/fakes"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestMultiBuild(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	// not parallel, as the dashboard is replaced by one shown by a fake app
+	spec.Run(t, "MultiBuild", testMultiBuild, spec.Report(report.Terminal{}))
+}
+
+func testMultiBuild(t *testing.T, when spec.G, it spec.S) {
+	var (
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+		fakeApp        *fakes.App
+		shownImages    []string
+		dir            string
+		original       = newMultiBuild
+	)
+
+	it.Before(func() {
+		outBuf.Reset()
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+
+		fakeApp = fakes.NewApp()
+		shownImages = nil
+		newMultiBuild = func(_ logging.Logger, images []string) *termui.MultiBuild {
+			shownImages = images
+			return termui.NewMultiBuild(fakeApp, images)
+		}
+
+		dir = t.TempDir()
+	})
+
+	it.After(func() {
+		newMultiBuild = original
+		mockController.Finish()
+	})
+
+	when("building every app with --all", func() {
+		it.Before(func() {
+			h.AssertNil(t, os.WriteFile(filepath.Join(dir, "project.toml"), []byte(`
+[_]
+schema-version = "0.2"
+
+[io.buildpacks]
+builder = "my-builder"
+
+[[io.buildpacks.apps]]
+path = "api"
+image = "api"
+
+[[io.buildpacks.apps]]
+path = "web"
+image = "web"
+`), 0600))
+		})
+
+		it("shows the builds in the dashboard instead of their logs", func() {
+			mockClient.EXPECT().Build(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+			command := Build(logger, config.Config{}, mockClient)
+			command.SetArgs([]string{"--all", "--path", dir, "--jobs", "1"})
+			h.AssertNil(t, command.Execute())
+
+			h.AssertEq(t, shownImages, []string{"api", "web"})
+			h.AssertEq(t, fakeApp.SetRootCallCount, 1)
+			h.AssertContains(t, outBuf.String(), "Building 2 apps, 1 at a time")
+			h.AssertNotContains(t, outBuf.String(), "Successfully built image")
+		})
+
+		it("reports the failed builds with their logs once the dashboard is closed", func() {
+			mockClient.EXPECT().Build(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, opts client.BuildOptions) error {
+				opts.Logger.Info("[detector] no buildpack groups passed detection")
+				return errors.New("detect failed")
+			})
+			mockClient.EXPECT().Build(gomock.Any(), gomock.Any()).Return(nil)
+
+			command := Build(logger, config.Config{}, mockClient)
+			command.SetArgs([]string{"--all", "--path", dir, "--jobs", "1"})
+			h.AssertError(t, command.Execute(), "1 of 2 apps failed to build")
+			h.AssertEq(t, fakeApp.StopCallCount, 1)
+			h.AssertContains(t, outBuf.String(), "[api] [detector] no buildpack groups passed detection")
+			h.AssertContains(t, outBuf.String(), "Failed to build 'api': failed to build: detect failed")
+			h.AssertNotContains(t, outBuf.String(), "[web]")
+		})
+
+		it("cancels the builds left when the dashboard is quit", func() {
+			// quit before the builds are done
+			fakeApp.StopRunning()
+			mockClient.EXPECT().Build(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, _ client.BuildOptions) error {
+				<-ctx.Done()
+				return ctx.Err()
+			}).Times(2)
+
+			command := Build(logger, config.Config{}, mockClient)
+			command.SetArgs([]string{"--all", "--path", dir, "--jobs", "1"})
+			h.AssertError(t, command.Execute(), "2 of 2 apps failed to build")
+			h.AssertContains(t, outBuf.String(), "Cancelling the builds left, as the dashboard was quit")
+			h.AssertContains(t, outBuf.String(), "Failed to build 'api': failed to build: context canceled")
+		})
+
+		when("--warnings-as-errors", func() {
+			it.Before(func() {
+				mockClient.EXPECT().Build(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, opts client.BuildOptions) error {
+					opts.Logger.Warn("the run image is deprecated")
+					_, err := fmt.Fprintln(logging.GetWriterForLevel(opts.Logger, logging.InfoLevel), "[detector] Warning: platform API 0.9 is deprecated")
+					return err
+				})
+				mockClient.EXPECT().Build(gomock.Any(), gomock.Any()).Return(nil)
+			})
+
+			it("fails on the warnings of the builds shown in the dashboard", func() {
+				command := Build(logger, config.Config{}, mockClient)
+				AddWarningsAsErrorsFlag(command, logger)
+				command.SetArgs([]string{"--all", "--path", dir, "--jobs", "1", "--warnings-as-errors"})
+				h.AssertError(t, command.Execute(), "2 warning(s) were reported")
+			})
+
+			it("fails on the warnings of the builds logged with the plain progress", func() {
+				command := Build(logger, config.Config{}, mockClient)
+				AddWarningsAsErrorsFlag(command, logger)
+				command.SetArgs([]string{"--all", "--path", dir, "--jobs", "1", "--progress", "plain", "--warnings-as-errors"})
+				h.AssertError(t, command.Execute(), "2 warning(s) were reported")
+				h.AssertContains(t, outBuf.String(), "[api] Warning: the run image is deprecated")
+			})
+		})
+
+		it("logs the builds with the plain progress", func() {
+			mockClient.EXPECT().Build(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+			command := Build(logger, config.Config{}, mockClient)
+			command.SetArgs([]string{"--all", "--path", dir, "--jobs", "1", "--progress", "plain"})
+			h.AssertNil(t, command.Execute())
+
+			h.AssertEq(t, fakeApp.SetRootCallCount, 0)
+			h.AssertContains(t, outBuf.String(), "[api] Successfully built image 'api'")
+		})
+	})
+
+	when("composing images", func() {
+		it.Before(func() {
+			h.AssertNil(t, os.WriteFile(filepath.Join(dir, "pack-compose.toml"), []byte(`
+builder = "some/builder"
+
+[images.web]
+image = "web"
+path = "web"
+depends-on = ["api"]
+
+[images.api]
+image = "api"
+path = "api"
+`), 0600))
+		})
+
+		it("shows the builds in the dashboard and reports the failed ones once it is closed", func() {
+			mockClient.EXPECT().Build(gomock.Any(), gomock.Any()).Return(errors.New("detect failed"))
+
+			command := Compose(logger, config.Config{}, mockClient)
+			command.SetArgs([]string{"--file", filepath.Join(dir, "pack-compose.toml")})
+			h.AssertError(t, command.Execute(), "2 of 2 images weren't built")
+
+			h.AssertEq(t, shownImages, []string{"api", "web"})
+			h.AssertEq(t, fakeApp.SetRootCallCount, 1)
+			h.AssertNotContains(t, outBuf.String(), "Skipping")
+			h.AssertContains(t, outBuf.String(), "Failed to build 'api': failed to build: detect failed")
+		})
+	})
+}
@@ -69,6 +69,7 @@ func PackageBuildpack(logger logging.Logger, cfg config.Config, packager Buildpa
 				Publish:         flags.Publish,
 				PullPolicy:      pullPolicy,
 				Registry:        flags.BuildpackRegistry,
+				RegistryLookup:  flags.RegistryLookup,
 			}); err != nil {
 				return err
 			}
@@ -88,6 +89,7 @@ func PackageBuildpack(logger logging.Logger, cfg config.Config, packager Buildpa
 	cmd.Flags().BoolVar(&flags.Publish, "publish", false, `Publish the buildpack directly to the container registry specified in <name>, instead of the daemon (applies to "--format=image" only).`)
 	cmd.Flags().StringVar(&flags.Policy, "pull-policy", "", "Pull policy to use. Accepted values are always, never, and if-not-present. The default is always")
 	cmd.Flags().StringVarP(&flags.BuildpackRegistry, "buildpack-registry", "r", "", "Buildpack Registry name")
+	addRegistryLookupFlags(cmd, &flags.RegistryLookup)
 
 	AddHelpFlag(cmd, "package-buildpack")
 	return cmd
@@ -0,0 +1,24 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+func NewPluginCommand(logger logging.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plugin",
+		Short: "Interact with plugins",
+		Long: "Plugins are executables named pack-<name> on PATH. Running `pack <name>`, where name isn't a command of " +
+			"pack, runs the plugin with the arguments that follow its name.\n\n" +
+			"Plugins receive the path of the pack config in PACK_CONFIG, the pack home in PACK_HOME, and a JSON " +
+			"object with the version of pack, the config path, the pack home and whether experimental features are " +
+			"enabled in PACK_PLUGIN_CONTEXT.",
+		RunE: nil,
+	}
+
+	cmd.AddCommand(PluginList(logger))
+	AddHelpFlag(cmd, "plugin")
+	return cmd
+}
@@ -0,0 +1,35 @@
+package commands
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/plugin"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+// PluginList lists the plugins found on PATH
+func PluginList(logger logging.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Args:    cobra.NoArgs,
+		Short:   "List the plugins found on PATH",
+		Example: "pack plugin list",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			plugins := plugin.List(os.Getenv("PATH"))
+			if len(plugins) == 0 {
+				logger.Info("No plugins found. Plugins are executables named pack-<name> on PATH")
+				return nil
+			}
+
+			for _, p := range plugins {
+				logger.Infof("%s\t%s", p.Name, style.Symbol(p.Path))
+			}
+			return nil
+		}),
+	}
+	AddHelpFlag(cmd, "list")
+	return cmd
+}
@@ -0,0 +1,65 @@
+package commands_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestPluginListCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "PluginListCommand", testPluginListCommand, spec.Report(report.Terminal{}))
+}
+
+func testPluginListCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		outBuf bytes.Buffer
+		logger logging.Logger
+		dir    string
+	)
+
+	it.Before(func() {
+		if runtime.GOOS == "windows" {
+			t.Skip("plugins are found by their executable bit")
+		}
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+
+		var err error
+		dir, err = os.MkdirTemp("", "plugins")
+		h.AssertNil(t, err)
+		t.Setenv("PATH", dir)
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.RemoveAll(dir))
+	})
+
+	when("#PluginList", func() {
+		it("lists the plugins on PATH", func() {
+			h.AssertNil(t, os.WriteFile(filepath.Join(dir, "pack-deploy"), []byte("#!/bin/sh\n"), 0755))
+
+			command := commands.PluginList(logger)
+			command.SetArgs([]string{})
+			h.AssertNil(t, command.Execute())
+			h.AssertContains(t, outBuf.String(), "deploy\t'"+filepath.Join(dir, "pack-deploy")+"'")
+		})
+
+		it("explains how plugins are found when there are none", func() {
+			command := commands.PluginList(logger)
+			command.SetArgs([]string{})
+			h.AssertNil(t, command.Execute())
+			h.AssertContains(t, outBuf.String(), "No plugins found. Plugins are executables named pack-<name> on PATH")
+		})
+	})
+}
@@ -0,0 +1,21 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+func NewProjectCommand(logger logging.Logger, cfg config.Config, client PackClient) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "project",
+		Short: "Interact with project descriptors (project.toml)",
+		RunE:  nil,
+	}
+
+	cmd.AddCommand(ProjectInit(logger, cfg))
+	cmd.AddCommand(ProjectValidate(logger, cfg, client))
+	AddHelpFlag(cmd, "project")
+	return cmd
+}
@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/project"
+)
+
+type ProjectInitFlags struct {
+	AppPath string
+	Builder string
+	Force   bool
+}
+
+// builderLanguages are the languages supported by the builders suggested for a new project descriptor, in order of
+// preference
+var builderLanguages = []struct {
+	builder   string
+	languages []string
+}{
+	{"paketobuildpacks/builder-jammy-base", []string{"Go", "Node.js", "Python", "Java", "Ruby", ".NET"}},
+	{"paketobuildpacks/builder-jammy-full", []string{"Go", "Node.js", "Python", "Java", "Ruby", ".NET", "PHP"}},
+}
+
+// ProjectInit generates a starter project descriptor from the source of an app
+func ProjectInit(logger logging.Logger, cfg config.Config) *cobra.Command {
+	var flags ProjectInitFlags
+
+	cmd := &cobra.Command{
+		Use:   "init",
+		Args:  cobra.NoArgs,
+		Short: "Generate a starter project descriptor",
+		Long: "Generate a starter project descriptor (project.toml) for an app. The languages of the app are detected " +
+			"from files like go.mod or package.json to suggest a builder and exclude build output and dependency " +
+			"directories. If the app has a Dockerfile, its ENV instructions are added as build env vars, and the paths " +
+			"ignored by its .dockerignore are excluded.\n\n" +
+			"The builder is, in order of precedence, the one provided with --builder, the default builder or a builder " +
+			"supporting the detected languages.",
+		Example: "pack project init --path apps/api",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			appPath := flags.AppPath
+			if appPath == "" {
+				appPath = "."
+			}
+
+			descriptorPath := filepath.Join(appPath, "project.toml")
+			if _, err := os.Stat(descriptorPath); err == nil && !flags.Force {
+				return errors.Errorf("project descriptor %s already exists, use --force to overwrite it", style.Symbol(descriptorPath))
+			}
+
+			scaffold, err := project.NewScaffold(appPath)
+			if err != nil {
+				return err
+			}
+
+			scaffold.Builder = flags.Builder
+			if scaffold.Builder == "" {
+				scaffold.Builder = cfg.DefaultBuilder
+			}
+			if scaffold.Builder == "" {
+				scaffold.Builder = suggestBuilderForLanguages(scaffold.Languages)
+			}
+
+			if len(scaffold.Languages) > 0 {
+				logger.Infof("Detected %s", strings.Join(scaffold.Languages, ", "))
+			}
+			if scaffold.Dockerfile != "" {
+				logger.Infof("Using %d build env var(s) from %s", len(scaffold.Env), style.Symbol(scaffold.Dockerfile))
+			}
+
+			if err := os.WriteFile(descriptorPath, []byte(scaffold.ProjectToml()), 0600); err != nil {
+				return errors.Wrapf(err, "writing project descriptor %s", style.Symbol(descriptorPath))
+			}
+
+			logger.Infof("Created project descriptor %s", style.Symbol(descriptorPath))
+			if scaffold.Builder == "" {
+				logger.Info("No builder could be suggested for the app, run `pack builder suggest` to pick one and set it in the project descriptor")
+			}
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringVarP(&flags.AppPath, "path", "p", "", "Path to the app dir (defaults to current working directory)")
+	cmd.Flags().StringVarP(&flags.Builder, "builder", "B", "", "Builder image to declare in the project descriptor")
+	cmd.Flags().BoolVar(&flags.Force, "force", false, "Overwrite an existing project descriptor")
+	AddHelpFlag(cmd, "init")
+	return cmd
+}
+
+// suggestBuilderForLanguages returns the first builder supporting all the languages, or an empty string if none do
+func suggestBuilderForLanguages(languages []string) string {
+	if len(languages) == 0 {
+		return ""
+	}
+
+	for _, candidate := range builderLanguages {
+		supported := map[string]bool{}
+		for _, language := range candidate.languages {
+			supported[language] = true
+		}
+
+		supportsAll := true
+		for _, language := range languages {
+			supportsAll = supportsAll && supported[language]
+		}
+		if supportsAll {
+			return candidate.builder
+		}
+	}
+	return ""
+}
@@ -0,0 +1,92 @@
+package commands_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/project"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestProjectInitCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "ProjectInitCommand", testProjectInitCommand, spec.Random(), spec.Report(report.Terminal{}))
+}
+
+func testProjectInitCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		logger         *logging.LogWithWriters
+		outBuf         bytes.Buffer
+		appDir         string
+		newCommand     func(cfg config.Config, args ...string) *cobra.Command
+		readDescriptor func() string
+	)
+
+	it.Before(func() {
+		var err error
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		appDir, err = os.MkdirTemp("", "project-init")
+		h.AssertNil(t, err)
+
+		newCommand = func(cfg config.Config, args ...string) *cobra.Command {
+			cmd := commands.ProjectInit(logger, cfg)
+			cmd.SetArgs(append([]string{"--path", appDir}, args...))
+			return cmd
+		}
+		readDescriptor = func() string {
+			contents, err := os.ReadFile(filepath.Join(appDir, "project.toml"))
+			h.AssertNil(t, err)
+			return string(contents)
+		}
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.RemoveAll(appDir))
+	})
+
+	when("#ProjectInit", func() {
+		it("generates a descriptor with a builder for the detected languages", func() {
+			h.AssertNil(t, os.WriteFile(filepath.Join(appDir, "go.mod"), []byte("module example"), 0600))
+
+			h.AssertNil(t, newCommand(config.Config{}).Execute())
+			h.AssertContains(t, outBuf.String(), "Detected Go")
+			h.AssertContains(t, outBuf.String(), "Created project descriptor")
+
+			descriptor, err := project.ReadProjectDescriptor(filepath.Join(appDir, "project.toml"), logger)
+			h.AssertNil(t, err)
+			h.AssertEq(t, descriptor.Build.Builder, "paketobuildpacks/builder-jammy-base")
+		})
+
+		it("prefers the builder flag and the default builder", func() {
+			h.AssertNil(t, newCommand(config.Config{DefaultBuilder: "default/builder"}).Execute())
+			h.AssertContains(t, readDescriptor(), `builder = "default/builder"`)
+
+			h.AssertNil(t, newCommand(config.Config{DefaultBuilder: "default/builder"}, "--builder", "flag/builder", "--force").Execute())
+			h.AssertContains(t, readDescriptor(), `builder = "flag/builder"`)
+		})
+
+		it("suggests picking a builder when none is known", func() {
+			h.AssertNil(t, newCommand(config.Config{}).Execute())
+			h.AssertContains(t, outBuf.String(), "run `pack builder suggest`")
+		})
+
+		it("doesn't overwrite an existing descriptor without --force", func() {
+			h.AssertNil(t, os.WriteFile(filepath.Join(appDir, "project.toml"), []byte("existing"), 0600))
+
+			err := newCommand(config.Config{}).Execute()
+			h.AssertError(t, err, "already exists, use --force to overwrite it")
+			h.AssertEq(t, readDescriptor(), "existing")
+		})
+	})
+}
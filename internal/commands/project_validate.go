@@ -0,0 +1,173 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/paths"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/buildpack"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/project"
+	projectTypes "github.com/buildpacks/pack/pkg/project/types"
+)
+
+type ProjectValidateFlags struct {
+	AppPath        string
+	DescriptorPath string
+	Registry       string
+}
+
+// ProjectValidate validates a project descriptor and the buildpacks and paths it references
+func ProjectValidate(logger logging.Logger, cfg config.Config, packClient PackClient) *cobra.Command {
+	var flags ProjectValidateFlags
+
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Args:  cobra.NoArgs,
+		Short: "Validate a project descriptor",
+		Long: "Validate a project descriptor (project.toml) against the schema version it declares, and check that the " +
+			"paths and buildpacks it references exist. Buildpacks from a buildpack registry (urn:cnb:registry) are " +
+			"resolved against the registry.\n\n" +
+			"Keys ignored by the schema version are reported as warnings. Use --warnings-as-errors to fail on them, e.g. in CI.",
+		Example: "pack project validate --descriptor path/to/project.toml",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			descriptorPath := flags.DescriptorPath
+			if descriptorPath == "" {
+				descriptorPath = filepath.Join(flags.AppPath, "project.toml")
+			}
+			if _, err := os.Stat(descriptorPath); err != nil {
+				return errors.Wrapf(err, "reading project descriptor %s", style.Symbol(descriptorPath))
+			}
+
+			descriptor, err := project.ReadProjectDescriptor(descriptorPath, logger)
+			if err != nil {
+				return err
+			}
+
+			registry := flags.Registry
+			if registry == "" {
+				registry = cfg.DefaultRegistryName
+			}
+
+			problems := checkProjectReferences(descriptor, filepath.Dir(descriptorPath), registry, packClient)
+			for _, problem := range problems {
+				logger.Error(problem)
+			}
+			if len(problems) > 0 {
+				return errors.Errorf("project descriptor %s references %d missing path(s) or buildpack(s)", style.Symbol(descriptorPath), len(problems))
+			}
+
+			logger.Infof("Project descriptor %s is valid (schema version %s)", style.Symbol(descriptorPath), descriptor.SchemaVersion)
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringVarP(&flags.AppPath, "path", "p", "", "Path to the app dir containing the project descriptor (defaults to current working directory)")
+	cmd.Flags().StringVarP(&flags.DescriptorPath, "descriptor", "d", "", "Path to the project descriptor file")
+	cmd.Flags().StringVarP(&flags.Registry, "buildpack-registry", "r", "", "Buildpack Registry used to resolve registry buildpacks")
+	AddHelpFlag(cmd, "validate")
+	return cmd
+}
+
+// checkProjectReferences returns a problem for each path or buildpack referenced by the descriptor that doesn't exist.
+// Buildpacks from the builder or from images and URLs can only be checked when building, so they are skipped.
+func checkProjectReferences(descriptor projectTypes.Descriptor, baseDir, registry string, packClient PackClient) []string {
+	var problems []string
+
+	groups := []struct {
+		name       string
+		buildpacks []projectTypes.Buildpack
+	}{
+		{"group", descriptor.Build.Buildpacks},
+		{"pre group", descriptor.Build.Pre.Buildpacks},
+		{"post group", descriptor.Build.Post.Buildpacks},
+	}
+	for _, group := range groups {
+		for _, bp := range group.buildpacks {
+			if problem := checkBuildpackReference(bp, baseDir, registry, packClient); problem != "" {
+				problems = append(problems, fmt.Sprintf("%s of the %s: %s", buildpackName(bp), group.name, problem))
+			}
+		}
+	}
+
+	for _, app := range descriptor.Build.Apps {
+		if info, err := os.Stat(filepath.Join(baseDir, filepath.FromSlash(app.Path))); err != nil || !info.IsDir() {
+			problems = append(problems, fmt.Sprintf("app %s: path %s is not a directory", style.Symbol(app.Image), style.Symbol(app.Path)))
+		}
+	}
+
+	return problems
+}
+
+func checkBuildpackReference(bp projectTypes.Buildpack, baseDir, registry string, packClient PackClient) string {
+	switch {
+	case bp.Script.Inline != "" || len(bp.Script.Files) > 0:
+		if bp.Script.API == "" {
+			return "inline buildpacks must have a script api defined"
+		}
+		for _, file := range bp.Script.Files {
+			if problem := checkPathExists(filepath.Join(baseDir, filepath.FromSlash(file))); problem != "" {
+				return problem
+			}
+		}
+	case strings.HasPrefix(bp.URI, "urn:cnb:registry:"):
+		if _, err := packClient.InspectBuildpack(client.InspectBuildpackOptions{BuildpackName: bp.URI, Registry: registry}); err != nil {
+			return fmt.Sprintf("could not be resolved from the buildpack registry: %s", err)
+		}
+	case bp.URI == "", buildpack.HasDockerLocator(bp.URI), strings.HasPrefix(bp.URI, "urn:cnb:builder:"), strings.HasPrefix(bp.URI, "from=builder"):
+	case paths.IsURI(bp.URI):
+		if strings.HasPrefix(bp.URI, "file://") {
+			path, err := paths.URIToFilePath(bp.URI)
+			if err != nil {
+				return err.Error()
+			}
+			return checkPathExists(path)
+		}
+	case looksLikePath(bp.URI):
+		path := bp.URI
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(baseDir, path)
+		}
+		return checkPathExists(path)
+	}
+	return ""
+}
+
+// looksLikePath reports whether a naked buildpack locator is meant as a path, rather than an image reference
+func looksLikePath(locator string) bool {
+	if filepath.IsAbs(locator) || strings.HasPrefix(locator, ".") {
+		return true
+	}
+	for _, ext := range []string{".tgz", ".tar", ".tar.gz", ".cnb"} {
+		if strings.HasSuffix(locator, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkPathExists(path string) string {
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Sprintf("path %s does not exist", style.Symbol(path))
+	}
+	return ""
+}
+
+func buildpackName(bp projectTypes.Buildpack) string {
+	switch {
+	case bp.ID != "" && bp.Version != "":
+		return style.Symbol(bp.ID + "@" + bp.Version)
+	case bp.ID != "":
+		return style.Symbol(bp.ID)
+	default:
+		return style.Symbol(bp.URI)
+	}
+}
@@ -0,0 +1,161 @@
+package commands_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/pkg/errors"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestProjectValidateCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "ProjectValidateCommand", testProjectValidateCommand, spec.Random(), spec.Report(report.Terminal{}))
+}
+
+func testProjectValidateCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		logger         *logging.LogWithWriters
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+		projectDir     string
+	)
+
+	it.Before(func() {
+		var err error
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+		command = commands.ProjectValidate(logger, config.Config{DefaultRegistryName: "some-registry"}, mockClient)
+
+		projectDir, err = os.MkdirTemp("", "project-validate")
+		h.AssertNil(t, err)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+		h.AssertNil(t, os.RemoveAll(projectDir))
+	})
+
+	writeDescriptor := func(contents string) {
+		h.AssertNil(t, os.WriteFile(filepath.Join(projectDir, "project.toml"), []byte(contents), 0600))
+	}
+
+	when("#ProjectValidate", func() {
+		it("reports a valid descriptor", func() {
+			h.AssertNil(t, os.MkdirAll(filepath.Join(projectDir, "buildpacks", "local"), 0755))
+			writeDescriptor(`[_]
+schema-version = "0.2"
+
+[[io.buildpacks.group]]
+uri = "buildpacks/local"
+
+[[io.buildpacks.group]]
+uri = "docker://example.com/some/buildpack"
+
+[[io.buildpacks.group]]
+id = "example/from-builder"
+`)
+
+			command.SetArgs([]string{"--path", projectDir})
+			h.AssertNil(t, command.Execute())
+			h.AssertContains(t, outBuf.String(), "is valid (schema version 0.2)")
+		})
+
+		it("reports schema errors with their key and line", func() {
+			writeDescriptor(`[_]
+schema-version = "0.2"
+
+[[io.buildpacks.group]]
+version = "1.0.0"
+`)
+
+			command.SetArgs([]string{"--path", projectDir})
+			h.AssertError(t, command.Execute(), "project.toml: io.buildpacks.group[0]: buildpacks must have an id or url defined (line 4)")
+		})
+
+		it("warns about ignored keys", func() {
+			writeDescriptor(`[_]
+schema-version = "0.2"
+
+[io.buildpacks]
+unknown = "ignored"
+`)
+
+			command.SetArgs([]string{"--path", projectDir})
+			h.AssertNil(t, command.Execute())
+			h.AssertContains(t, outBuf.String(), "The following keys declared in project.toml are not supported in schema version 0.2")
+			h.AssertContains(t, outBuf.String(), "- io.buildpacks.unknown")
+		})
+
+		it("reports missing paths", func() {
+			writeDescriptor(`[_]
+schema-version = "0.2"
+
+[[io.buildpacks.pre.group]]
+id = "example/local"
+uri = "./buildpacks/missing.tgz"
+
+[[io.buildpacks.group]]
+id = "example/inline"
+
+[io.buildpacks.group.script]
+api = "0.10"
+files = ["scripts/missing.sh"]
+
+[[io.buildpacks.apps]]
+path = "services/missing"
+image = "missing"
+`)
+
+			command.SetArgs([]string{"--descriptor", filepath.Join(projectDir, "project.toml")})
+			h.AssertError(t, command.Execute(), "references 3 missing path(s) or buildpack(s)")
+			h.AssertContains(t, outBuf.String(), "path '"+filepath.Join(projectDir, "scripts", "missing.sh")+"' does not exist")
+			h.AssertContains(t, outBuf.String(), "'example/local' of the pre group: path '"+filepath.Join(projectDir, "buildpacks", "missing.tgz")+"' does not exist")
+			h.AssertContains(t, outBuf.String(), "app 'missing': path 'services/missing' is not a directory")
+		})
+
+		it("resolves registry buildpacks", func() {
+			writeDescriptor(`[_]
+schema-version = "0.2"
+
+[[io.buildpacks.group]]
+uri = "urn:cnb:registry:example/found@1.0.0"
+
+[[io.buildpacks.group]]
+uri = "urn:cnb:registry:example/missing@1.0.0"
+`)
+			mockClient.EXPECT().
+				InspectBuildpack(client.InspectBuildpackOptions{BuildpackName: "urn:cnb:registry:example/found@1.0.0", Registry: "some-registry"}).
+				Return(&client.BuildpackInfo{}, nil)
+			mockClient.EXPECT().
+				InspectBuildpack(client.InspectBuildpackOptions{BuildpackName: "urn:cnb:registry:example/missing@1.0.0", Registry: "some-registry"}).
+				Return(nil, errors.New("not found"))
+
+			command.SetArgs([]string{"--path", projectDir})
+			h.AssertError(t, command.Execute(), "references 1 missing path(s) or buildpack(s)")
+			h.AssertContains(t, outBuf.String(), "'urn:cnb:registry:example/missing@1.0.0' of the group: could not be resolved from the buildpack registry: not found")
+		})
+
+		it("fails without a descriptor", func() {
+			command.SetArgs([]string{"--path", projectDir})
+			h.AssertError(t, command.Execute(), "reading project descriptor")
+		})
+	})
+}
@@ -0,0 +1,148 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+type PruneFlags struct {
+	Kinds     []string
+	OlderThan string
+	MinSize   string
+	DryRun    bool
+}
+
+// Prune removes the resources left by builds
+func Prune(logger logging.Logger, cfg config.Config, packClient PackClient) *cobra.Command {
+	var flags PruneFlags
+
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Args:  cobra.NoArgs,
+		Short: "Remove the cache volumes, ephemeral images and temporary directories left by builds",
+		Long: "Remove the resources that builds leave behind and that otherwise accumulate:\n\n" +
+			"  cache      volumes of the build and launch caches of app images\n" +
+			"  builder    ephemeral builders and run images of interrupted builds\n" +
+			"  lifecycle  ephemeral lifecycle images, and lifecycle images that lost their tag to a newer pull\n" +
+			"  temp       temporary directories holding OCI layouts and scratch files\n\n" +
+			"Only resources older than `--older-than` are removed, so that the ones of builds running now are kept. " +
+			"Volumes and images used by containers are always kept. Use `--dry-run` to list the resources without " +
+			"removing them.",
+		Example: "pack prune --type cache --older-than 30d --min-size 100MB --dry-run",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			olderThan, err := parseAge(flags.OlderThan)
+			if err != nil {
+				return err
+			}
+			minSize, err := parseMinSize(flags.MinSize)
+			if err != nil {
+				return err
+			}
+
+			resources, err := packClient.Prune(cmd.Context(), client.PruneOptions{
+				Kinds:          flags.Kinds,
+				OlderThan:      olderThan,
+				MinSize:        minSize,
+				DryRun:         flags.DryRun,
+				LifecycleImage: cfg.LifecycleImage,
+			})
+			if err != nil {
+				return err
+			}
+			return logPrunedResources(logger, resources, flags.DryRun)
+		}),
+	}
+
+	cmd.Flags().StringSliceVarP(&flags.Kinds, "type", "t", nil, fmt.Sprintf("Kind of resources to remove, one of %s (defaults to all of them)", strings.Join(client.PruneKinds, ", "))+stringSliceHelp("type"))
+	cmd.Flags().StringVar(&flags.OlderThan, "older-than", "1h", "Only remove resources created at least this long ago, e.g. '90m', '72h' or '30d'; '0' removes resources of any age")
+	cmd.Flags().StringVar(&flags.MinSize, "min-size", "", "Only remove resources of at least this size, e.g. '500MB'")
+	cmd.Flags().BoolVar(&flags.DryRun, "dry-run", false, "List the resources that would be removed, without removing them")
+	AddHelpFlag(cmd, "prune")
+	return cmd
+}
+
+// logPrunedResources prints the resources removed by a prune, or that would be removed in a dry run, and fails when
+// some of them couldn't be removed
+func logPrunedResources(logger logging.Logger, resources []client.PrunedResource, dryRun bool) error {
+	if len(resources) == 0 {
+		logger.Info("Nothing to remove")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(logger.Writer(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KIND\tNAME\tCREATED\tSIZE")
+	var (
+		removed int
+		freed   int64
+	)
+	for _, resource := range resources {
+		created := "unknown"
+		if !resource.Created.IsZero() {
+			created = humanize.Time(resource.Created)
+		}
+		size := "unknown"
+		if resource.Size > 0 {
+			size = humanize.Bytes(uint64(resource.Size))
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", resource.Kind, resource.Name, created, size)
+		if resource.Err == nil {
+			removed++
+			freed += resource.Size
+		}
+	}
+	tw.Flush()
+
+	logger.Info("")
+	if dryRun {
+		logger.Infof("%d resources would be removed, freeing %s", removed, humanize.Bytes(uint64(freed)))
+		return nil
+	}
+	for _, resource := range resources {
+		if resource.Err != nil {
+			logger.Errorf("Failed to remove %s: %s", style.Symbol(resource.Name), resource.Err)
+		}
+	}
+	logger.Infof("Removed %d resources, freeing %s", removed, humanize.Bytes(uint64(freed)))
+	if removed < len(resources) {
+		return errors.Errorf("%d of %d resources couldn't be removed", len(resources)-removed, len(resources))
+	}
+	return nil
+}
+
+// parseMinSize parses a size such as 500MB, 0 when empty
+func parseMinSize(minSize string) (int64, error) {
+	if minSize == "" {
+		return 0, nil
+	}
+	size, err := humanize.ParseBytes(minSize)
+	if err != nil {
+		return 0, errors.Errorf("invalid min-size %s, must be a size such as 500MB", style.Symbol(minSize))
+	}
+	return int64(size), nil
+}
+
+// parseAge parses a duration, which may also be a number of days such as '30d'
+func parseAge(age string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(age, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return time.Duration(n) * 24 * time.Hour, nil
+		}
+	}
+	duration, err := time.ParseDuration(age)
+	if err != nil || duration < 0 {
+		return 0, errors.Errorf("invalid older-than %s, must be a duration such as 72h or 30d", style.Symbol(age))
+	}
+	return duration, nil
+}
@@ -0,0 +1,110 @@
+package commands_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/pkg/errors"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestPruneCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "PruneCommand", testPruneCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testPruneCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+		resources      []client.PrunedResource
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+		command = commands.Prune(logging.NewLogWithWriters(&outBuf, &outBuf), config.Config{LifecycleImage: "some/lifecycle"}, mockClient)
+
+		resources = []client.PrunedResource{
+			{Kind: client.PruneCache, Name: "pack-cache-some_app_latest-123.build", Created: time.Now().Add(-48 * time.Hour), Size: 500_000_000},
+			{Kind: client.PruneTemp, Name: "package-buildpack123", Size: 1_000_000},
+		}
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#Prune", func() {
+		it("removes the resources older than an hour by default", func() {
+			mockClient.EXPECT().Prune(gomock.Any(), client.PruneOptions{OlderThan: time.Hour, LifecycleImage: "some/lifecycle"}).Return(resources, nil)
+
+			command.SetArgs([]string{})
+			h.AssertNil(t, command.Execute())
+
+			h.AssertContains(t, outBuf.String(), "cache  pack-cache-some_app_latest-123.build  2 days ago  500 MB")
+			h.AssertContains(t, outBuf.String(), "temp   package-buildpack123                  unknown     1.0 MB")
+			h.AssertContains(t, outBuf.String(), "Removed 2 resources, freeing 501 MB")
+		})
+
+		it("passes the filters", func() {
+			mockClient.EXPECT().Prune(gomock.Any(), client.PruneOptions{
+				Kinds:          []string{client.PruneCache, client.PruneTemp},
+				OlderThan:      30 * 24 * time.Hour,
+				MinSize:        100_000_000,
+				DryRun:         true,
+				LifecycleImage: "some/lifecycle",
+			}).Return(resources, nil)
+
+			command.SetArgs([]string{"--type", "cache,temp", "--older-than", "30d", "--min-size", "100MB", "--dry-run"})
+			h.AssertNil(t, command.Execute())
+
+			h.AssertContains(t, outBuf.String(), "2 resources would be removed, freeing 501 MB")
+		})
+
+		it("reports when there's nothing to remove", func() {
+			mockClient.EXPECT().Prune(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+			command.SetArgs([]string{"--older-than", "0"})
+			h.AssertNil(t, command.Execute())
+
+			h.AssertContains(t, outBuf.String(), "Nothing to remove")
+		})
+
+		it("fails when resources couldn't be removed", func() {
+			resources[1].Err = errors.New("permission denied")
+			mockClient.EXPECT().Prune(gomock.Any(), gomock.Any()).Return(resources, nil)
+
+			command.SetArgs([]string{})
+			h.AssertError(t, command.Execute(), "1 of 2 resources couldn't be removed")
+
+			h.AssertContains(t, outBuf.String(), "Failed to remove 'package-buildpack123': permission denied")
+			h.AssertContains(t, outBuf.String(), "Removed 1 resources, freeing 500 MB")
+		})
+
+		it("fails for an invalid age", func() {
+			command.SetArgs([]string{"--older-than", "a week"})
+			h.AssertError(t, command.Execute(), "invalid older-than 'a week'")
+		})
+
+		it("fails for an invalid size", func() {
+			command.SetArgs([]string{"--min-size", "big"})
+			h.AssertError(t, command.Execute(), "invalid min-size 'big'")
+		})
+	})
+}
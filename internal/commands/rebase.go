@@ -9,6 +9,7 @@ import (
 	"github.com/buildpacks/pack/pkg/image"
 
 	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/i18n"
 	"github.com/buildpacks/pack/internal/style"
 	"github.com/buildpacks/pack/pkg/logging"
 )
@@ -41,7 +42,7 @@ func Rebase(logger logging.Logger, cfg config.Config, pack PackClient) *cobra.Co
 			if err := pack.Rebase(cmd.Context(), opts); err != nil {
 				return err
 			}
-			logger.Infof("Successfully rebased image %s", style.Symbol(opts.RepoName))
+			logger.Info(i18n.T(i18n.RebaseSuccess, style.Symbol(opts.RepoName)))
 			return nil
 		}),
 	}
@@ -0,0 +1,22 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+func NewRegistryCommand(logger logging.Logger, cfg config.Config, client PackClient) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "registry",
+		Short: "Interact with buildpack registry indexes",
+		RunE:  nil,
+	}
+
+	cmd.AddCommand(RegistryVerifyIndex(logger, cfg))
+	cmd.AddCommand(RegistryExport(logger, client))
+	cmd.AddCommand(RegistryImport(logger, cfg, client))
+	AddHelpFlag(cmd, "registry")
+	return cmd
+}
@@ -0,0 +1,43 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/buildpack"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+type RegistryExportFlags struct {
+	Registry       string
+	RegistryLookup buildpack.RegistryLookup
+}
+
+func RegistryExport(logger logging.Logger, packClient PackClient) *cobra.Command {
+	var flags RegistryExportFlags
+
+	cmd := &cobra.Command{
+		Use:   "export <archive>",
+		Args:  cobra.ExactArgs(1),
+		Short: "Export the cache of a buildpack registry to an archive",
+		Long: "Refresh the cache of a buildpack registry and write it to a gzipped tar archive, including the metadata of " +
+			"its git clone, so that a host on an isolated network can import it with `pack registry import` and locate " +
+			"buildpacks of the registry without reaching it.",
+		Example: "pack registry export registry-cache.tar.gz",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			cacheArchive, err := packClient.ExportRegistryCache(args[0], client.ExportRegistryCacheOptions{Registry: flags.Registry, RegistryLookup: flags.RegistryLookup})
+			if err != nil {
+				return err
+			}
+
+			logger.Infof("Exported the cache of registry %s to %s", style.Symbol(cacheArchive.RegistryURL), style.Symbol(args[0]))
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringVarP(&flags.Registry, "buildpack-registry", "r", "", "Buildpack Registry whose cache is exported, the default registry when unset")
+	addRegistryLookupFlags(cmd, &flags.RegistryLookup)
+	AddHelpFlag(cmd, "export")
+	return cmd
+}
@@ -0,0 +1,65 @@
+package commands_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/pkg/errors"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	cpkg "github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestRegistryExportCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "RegistryExportCommand", testRegistryExportCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testRegistryExportCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		command = commands.RegistryExport(logger, mockClient)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#RegistryExport", func() {
+		it("exports the cache of the registry", func() {
+			mockClient.EXPECT().ExportRegistryCache("cache.tgz", cpkg.ExportRegistryCacheOptions{Registry: "some-registry"}).Return(cpkg.RegistryCacheArchive{
+				RegistryURL: "https://github.com/some-org/registry-index",
+			}, nil)
+
+			command.SetArgs([]string{"cache.tgz", "--buildpack-registry", "some-registry"})
+			h.AssertNil(t, command.Execute())
+			h.AssertContains(t, outBuf.String(), "Exported the cache of registry 'https://github.com/some-org/registry-index' to 'cache.tgz'")
+		})
+
+		it("fails when the export fails", func() {
+			mockClient.EXPECT().ExportRegistryCache("cache.tgz", gomock.Any()).Return(cpkg.RegistryCacheArchive{}, errors.New("some-error"))
+
+			command.SetArgs([]string{"cache.tgz"})
+			h.AssertError(t, command.Execute(), "some-error")
+		})
+	})
+}
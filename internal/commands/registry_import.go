@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+func RegistryImport(logger logging.Logger, cfg config.Config, client PackClient) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import <archive>",
+		Args:  cobra.ExactArgs(1),
+		Short: "Import the cache of a buildpack registry from an archive",
+		Long: "Replace the cache of a buildpack registry with the cache in an archive written by `pack registry export`, " +
+			"e.g. on a host on an isolated network. Run with --offline to locate buildpacks in the imported cache without " +
+			"trying to refresh it.",
+		Example: "pack registry import registry-cache.tar.gz",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			cacheArchive, err := client.ImportRegistryCache(args[0])
+			if err != nil {
+				return err
+			}
+
+			logger.Infof("Imported the cache of registry %s, exported on %s", style.Symbol(cacheArchive.RegistryURL), cacheArchive.Exported.Format("2006-01-02"))
+			if registryNameOf(cfg, cacheArchive.RegistryURL) == "" {
+				logger.Warnf("The buildpack registry %s isn't configured, add it with `pack config registries add <name> %s`", style.Symbol(cacheArchive.RegistryURL), cacheArchive.RegistryURL)
+			}
+			return nil
+		}),
+	}
+	AddHelpFlag(cmd, "import")
+	return cmd
+}
+
+// registryNameOf is the name of the configured registry at registryURL, empty when none is
+func registryNameOf(cfg config.Config, registryURL string) string {
+	for _, registry := range config.GetRegistries(cfg) {
+		if registry.URL == registryURL {
+			return registry.Name
+		}
+	}
+	return ""
+}
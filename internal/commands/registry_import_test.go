@@ -0,0 +1,71 @@
+package commands_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/internal/config"
+	cpkg "github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestRegistryImportCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "RegistryImportCommand", testRegistryImportCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testRegistryImportCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+		cacheArchive   = cpkg.RegistryCacheArchive{
+			Exported:    time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			RegistryURL: "https://github.com/some-org/registry-index",
+		}
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#RegistryImport", func() {
+		it("imports the cache of the registry", func() {
+			mockClient.EXPECT().ImportRegistryCache("cache.tgz").Return(cacheArchive, nil)
+
+			command := commands.RegistryImport(logger, config.Config{
+				Registries: []config.Registry{{Name: "some-registry", Type: "github", URL: cacheArchive.RegistryURL}},
+			}, mockClient)
+			command.SetArgs([]string{"cache.tgz"})
+			h.AssertNil(t, command.Execute())
+			h.AssertContains(t, outBuf.String(), "Imported the cache of registry 'https://github.com/some-org/registry-index', exported on 2024-01-02")
+			h.AssertNotContains(t, outBuf.String(), "isn't configured")
+		})
+
+		it("warns when the registry isn't configured", func() {
+			mockClient.EXPECT().ImportRegistryCache("cache.tgz").Return(cacheArchive, nil)
+
+			command := commands.RegistryImport(logger, config.Config{}, mockClient)
+			command.SetArgs([]string{"cache.tgz"})
+			h.AssertNil(t, command.Execute())
+			h.AssertContains(t, outBuf.String(), "add it with `pack config registries add <name> https://github.com/some-org/registry-index`")
+		})
+	})
+}
@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/registry"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+// RegistryVerifyIndex verifies every entry of a registry index, in a directory or cloned from a git repository
+func RegistryVerifyIndex(logger logging.Logger, cfg config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify-index <path-or-url>",
+		Args:  cobra.ExactArgs(1),
+		Short: "Verify every entry of a buildpack registry index",
+		Long: "Verify every line of every file of a registry index, in a directory, given as a path or a file url, or cloned from the git repository at a url. " +
+			"Each line must be a buildpack in JSON with a digest reference as its address and a semver version, in the file " +
+			"of its namespace and name, and each file must be at the path of that namespace and name. All problems are " +
+			"reported at once, so that teams running their own index can check it, e.g. in CI.",
+		Example: "pack registry verify-index ./registry-index\n" +
+			"pack registry verify-index https://github.com/buildpacks/registry-index",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			root := args[0]
+			if strings.HasPrefix(root, "file://") {
+				fileURL, err := url.Parse(root)
+				if err != nil {
+					return errors.Wrapf(err, "parsing index url %s", style.Symbol(root))
+				}
+				root = registry.FileURLPath(fileURL)
+			}
+			if strings.Contains(root, "://") || strings.HasPrefix(root, "git@") {
+				cloned, cleanup, err := cloneIndex(logger, cfg, args[0])
+				if err != nil {
+					return err
+				}
+				defer cleanup()
+				root = cloned
+			} else if info, err := os.Stat(root); err != nil {
+				return errors.Wrapf(err, "reading index %s", style.Symbol(root))
+			} else if !info.IsDir() {
+				return errors.Errorf("index %s must be a directory", style.Symbol(root))
+			}
+
+			report, err := registry.VerifyIndex(root)
+			if err != nil {
+				return err
+			}
+
+			for _, problem := range report.Problems {
+				logger.Error(problem.String())
+			}
+			if len(report.Problems) > 0 {
+				return errors.Errorf("index %s has %d problem(s) in %d entries of %d file(s)", style.Symbol(args[0]), len(report.Problems), report.Entries, report.Files)
+			}
+
+			logger.Infof("Index %s is valid (%d entries in %d files)", style.Symbol(args[0]), report.Entries, report.Files)
+			return nil
+		}),
+	}
+
+	AddHelpFlag(cmd, "verify-index")
+	return cmd
+}
+
+// cloneIndex clones the index of the registry at registryURL into a temporary directory, returning the directory
+// and a function removing it
+func cloneIndex(logger logging.Logger, cfg config.Config, registryURL string) (string, func(), error) {
+	if strings.HasPrefix(registryURL, "index+") {
+		return "", nil, errors.Errorf("registry %s is served over HTTP, which can't be walked, verify a clone of its index instead", style.Symbol(registryURL))
+	}
+
+	home, err := os.MkdirTemp("", "pack-verify-index")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { _ = os.RemoveAll(home) }
+
+	cache, err := registry.NewRegistryCache(logger, home, registryURL, registry.WithShallowClone(), registry.WithProxy(cfg.RegistryProxy))
+	if err == nil {
+		err = cache.CreateCache()
+	}
+	if err != nil {
+		cleanup()
+		return "", nil, errors.Wrapf(err, "cloning index %s", style.Symbol(registryURL))
+	}
+	return cache.Dir(), cleanup, nil
+}
@@ -0,0 +1,80 @@
+package commands_test
+
+import (
+	"bytes"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestRegistryVerifyIndex(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "RegistryVerifyIndexCommand", testRegistryVerifyIndexCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testRegistryVerifyIndexCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		outBuf bytes.Buffer
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		assert = h.NewAssertionManager(t)
+	)
+
+	when("#RegistryVerifyIndex", func() {
+		it("succeeds for a valid index", func() {
+			command := commands.RegistryVerifyIndex(logger, config.Config{})
+			command.SetArgs([]string{filepath.Join("..", "..", "testdata", "registry")})
+			assert.Succeeds(command.Execute())
+
+			assert.Contains(outBuf.String(), "is valid (")
+		})
+
+		it("succeeds for the file url of a valid index", func() {
+			root, err := filepath.Abs(filepath.Join("..", "..", "testdata", "registry"))
+			assert.Nil(err)
+
+			command := commands.RegistryVerifyIndex(logger, config.Config{})
+			command.SetArgs([]string{(&url.URL{Scheme: "file", Path: filepath.ToSlash(root)}).String()})
+			assert.Succeeds(command.Execute())
+
+			assert.Contains(outBuf.String(), "is valid (")
+		})
+
+		it("reports every problem and fails for an invalid index", func() {
+			root := t.TempDir()
+			h.AssertNil(t, os.MkdirAll(filepath.Join(root, "3", "fo"), 0755))
+			h.AssertNil(t, os.WriteFile(filepath.Join(root, "3", "fo", "example_foo"), []byte("not json\n{\"ns\":\"example\",\"name\":\"foo\",\"version\":\"latest\"}\n"), 0600))
+
+			command := commands.RegistryVerifyIndex(logger, config.Config{})
+			command.SetArgs([]string{root})
+			assert.ErrorContains(command.Execute(), "has 3 problem(s) in 2 entries of 1 file(s)")
+
+			assert.Contains(outBuf.String(), "3/fo/example_foo:1: invalid JSON")
+			assert.Contains(outBuf.String(), "3/fo/example_foo:2: version 'latest' is not a semver version")
+			assert.Contains(outBuf.String(), "3/fo/example_foo:2: address is a required field")
+		})
+
+		it("fails for an index that doesn't exist", func() {
+			command := commands.RegistryVerifyIndex(logger, config.Config{})
+			command.SetArgs([]string{filepath.Join(t.TempDir(), "missing")})
+			assert.ErrorContains(command.Execute(), "reading index")
+		})
+
+		it("fails for a registry served over HTTP", func() {
+			command := commands.RegistryVerifyIndex(logger, config.Config{})
+			command.SetArgs([]string{"index+https://registry.example.com/index"})
+			assert.ErrorContains(command.Execute(), "is served over HTTP")
+		})
+	})
+}
@@ -91,6 +91,9 @@ func sanitize(line string) string {
 		"image",
 		"mirrors",
 		"name",
+		"official-registry-mirrors",
+		"registry-proxy",
+		"token",
 		"url",
 	}
 	for _, field := range sensitiveFields {
@@ -9,6 +9,7 @@ import (
 	"github.com/spf13/cobra"
 
 	bldr "github.com/buildpacks/pack/internal/builder"
+	"github.com/buildpacks/pack/internal/i18n"
 	"github.com/buildpacks/pack/internal/style"
 	"github.com/buildpacks/pack/pkg/logging"
 )
@@ -31,7 +32,7 @@ func SuggestBuilders(logger logging.Logger, inspector BuilderInspector) *cobra.C
 }
 
 func suggestSettingBuilder(logger logging.Logger, inspector BuilderInspector) {
-	logger.Info("Please select a default builder with:")
+	logger.Info(i18n.T(i18n.BuilderSelectDefault))
 	logger.Info("")
 	logger.Info("\tpack config default-builder <builder-image>")
 	logger.Info("")
@@ -57,7 +58,7 @@ func WriteSuggestedBuilder(logger logging.Logger, inspector BuilderInspector, bu
 		return builders[i].Vendor < builders[j].Vendor
 	})
 
-	logger.Info("Suggested builders:")
+	logger.Info(i18n.T(i18n.BuilderSuggested))
 
 	// Fetch descriptions concurrently.
 	descriptions := make([]string, len(builders))
@@ -80,7 +81,7 @@ func WriteSuggestedBuilder(logger logging.Logger, inspector BuilderInspector, bu
 	}
 	fmt.Fprintln(tw)
 
-	logging.Tip(logger, "Learn more about a specific builder with:")
+	logging.Tip(logger, "%s", i18n.T(i18n.BuilderLearnMore))
 	logger.Info("\tpack builder inspect <builder-image>")
 }
 
@@ -64,6 +64,36 @@ func (mr *MockPackClientMockRecorder) AnnotateManifest(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnnotateManifest", reflect.TypeOf((*MockPackClient)(nil).AnnotateManifest), arg0, arg1)
 }
 
+// ApplyBundle mocks base method.
+func (m *MockPackClient) ApplyBundle(arg0 context.Context, arg1 string) (client.Bundle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyBundle", arg0, arg1)
+	ret0, _ := ret[0].(client.Bundle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyBundle indicates an expected call of ApplyBundle.
+func (mr *MockPackClientMockRecorder) ApplyBundle(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyBundle", reflect.TypeOf((*MockPackClient)(nil).ApplyBundle), arg0, arg1)
+}
+
+// AttachAttestation mocks base method.
+func (m *MockPackClient) AttachAttestation(arg0 context.Context, arg1 string, arg2 client.AttachAttestationOptions) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachAttestation", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AttachAttestation indicates an expected call of AttachAttestation.
+func (mr *MockPackClientMockRecorder) AttachAttestation(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachAttestation", reflect.TypeOf((*MockPackClient)(nil).AttachAttestation), arg0, arg1, arg2)
+}
+
 // Build mocks base method.
 func (m *MockPackClient) Build(arg0 context.Context, arg1 client.BuildOptions) error {
 	m.ctrl.T.Helper()
@@ -78,6 +108,21 @@ func (mr *MockPackClientMockRecorder) Build(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Build", reflect.TypeOf((*MockPackClient)(nil).Build), arg0, arg1)
 }
 
+// CollectDebugBundle mocks base method.
+func (m *MockPackClient) CollectDebugBundle(arg0 context.Context, arg1 client.DebugBundleOptions) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CollectDebugBundle", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CollectDebugBundle indicates an expected call of CollectDebugBundle.
+func (mr *MockPackClientMockRecorder) CollectDebugBundle(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CollectDebugBundle", reflect.TypeOf((*MockPackClient)(nil).CollectDebugBundle), arg0, arg1)
+}
+
 // CreateBuilder mocks base method.
 func (m *MockPackClient) CreateBuilder(arg0 context.Context, arg1 client.CreateBuilderOptions) error {
 	m.ctrl.T.Helper()
@@ -92,6 +137,21 @@ func (mr *MockPackClientMockRecorder) CreateBuilder(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBuilder", reflect.TypeOf((*MockPackClient)(nil).CreateBuilder), arg0, arg1)
 }
 
+// CreateBundle mocks base method.
+func (m *MockPackClient) CreateBundle(arg0 context.Context, arg1 string, arg2 client.CreateBundleOptions) (client.Bundle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBundle", arg0, arg1, arg2)
+	ret0, _ := ret[0].(client.Bundle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBundle indicates an expected call of CreateBundle.
+func (mr *MockPackClientMockRecorder) CreateBundle(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBundle", reflect.TypeOf((*MockPackClient)(nil).CreateBundle), arg0, arg1, arg2)
+}
+
 // CreateManifest mocks base method.
 func (m *MockPackClient) CreateManifest(arg0 context.Context, arg1 client.CreateManifestOptions) error {
 	m.ctrl.T.Helper()
@@ -120,6 +180,35 @@ func (mr *MockPackClientMockRecorder) DeleteManifest(arg0 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteManifest", reflect.TypeOf((*MockPackClient)(nil).DeleteManifest), arg0)
 }
 
+// DiffImages mocks base method.
+func (m *MockPackClient) DiffImages(arg0 context.Context, arg1, arg2 string, arg3 client.DiffImagesOptions) (*client.ImageDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiffImages", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*client.ImageDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiffImages indicates an expected call of DiffImages.
+func (mr *MockPackClientMockRecorder) DiffImages(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiffImages", reflect.TypeOf((*MockPackClient)(nil).DiffImages), arg0, arg1, arg2, arg3)
+}
+
+// Doctor mocks base method.
+func (m *MockPackClient) Doctor(arg0 context.Context, arg1 client.DoctorOptions) []client.Diagnostic {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Doctor", arg0, arg1)
+	ret0, _ := ret[0].([]client.Diagnostic)
+	return ret0
+}
+
+// Doctor indicates an expected call of Doctor.
+func (mr *MockPackClientMockRecorder) Doctor(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Doctor", reflect.TypeOf((*MockPackClient)(nil).Doctor), arg0, arg1)
+}
+
 // DownloadSBOM mocks base method.
 func (m *MockPackClient) DownloadSBOM(arg0 string, arg1 client.DownloadSBOMOptions) error {
 	m.ctrl.T.Helper()
@@ -134,6 +223,36 @@ func (mr *MockPackClientMockRecorder) DownloadSBOM(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadSBOM", reflect.TypeOf((*MockPackClient)(nil).DownloadSBOM), arg0, arg1)
 }
 
+// ExportRegistryCache mocks base method.
+func (m *MockPackClient) ExportRegistryCache(arg0 string, arg1 client.ExportRegistryCacheOptions) (client.RegistryCacheArchive, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportRegistryCache", arg0, arg1)
+	ret0, _ := ret[0].(client.RegistryCacheArchive)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportRegistryCache indicates an expected call of ExportRegistryCache.
+func (mr *MockPackClientMockRecorder) ExportRegistryCache(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportRegistryCache", reflect.TypeOf((*MockPackClient)(nil).ExportRegistryCache), arg0, arg1)
+}
+
+// ImportRegistryCache mocks base method.
+func (m *MockPackClient) ImportRegistryCache(arg0 string) (client.RegistryCacheArchive, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportRegistryCache", arg0)
+	ret0, _ := ret[0].(client.RegistryCacheArchive)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportRegistryCache indicates an expected call of ImportRegistryCache.
+func (mr *MockPackClientMockRecorder) ImportRegistryCache(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportRegistryCache", reflect.TypeOf((*MockPackClient)(nil).ImportRegistryCache), arg0)
+}
+
 // InspectBuilder mocks base method.
 func (m *MockPackClient) InspectBuilder(arg0 string, arg1 bool, arg2 ...client.BuilderInspectionModifier) (*client.BuilderInfo, error) {
 	m.ctrl.T.Helper()
@@ -199,6 +318,21 @@ func (mr *MockPackClientMockRecorder) InspectImage(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InspectImage", reflect.TypeOf((*MockPackClient)(nil).InspectImage), arg0, arg1)
 }
 
+// InspectLifecycle mocks base method.
+func (m *MockPackClient) InspectLifecycle(arg0 context.Context, arg1 client.InspectLifecycleOptions) (*client.LifecycleCompatibility, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InspectLifecycle", arg0, arg1)
+	ret0, _ := ret[0].(*client.LifecycleCompatibility)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InspectLifecycle indicates an expected call of InspectLifecycle.
+func (mr *MockPackClientMockRecorder) InspectLifecycle(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InspectLifecycle", reflect.TypeOf((*MockPackClient)(nil).InspectLifecycle), arg0, arg1)
+}
+
 // InspectManifest mocks base method.
 func (m *MockPackClient) InspectManifest(arg0 string) error {
 	m.ctrl.T.Helper()
@@ -213,6 +347,36 @@ func (mr *MockPackClientMockRecorder) InspectManifest(arg0 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InspectManifest", reflect.TypeOf((*MockPackClient)(nil).InspectManifest), arg0)
 }
 
+// ListCaches mocks base method.
+func (m *MockPackClient) ListCaches(arg0 context.Context, arg1 client.ListCachesOptions) ([]client.CacheVolume, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCaches", arg0, arg1)
+	ret0, _ := ret[0].([]client.CacheVolume)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCaches indicates an expected call of ListCaches.
+func (mr *MockPackClientMockRecorder) ListCaches(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCaches", reflect.TypeOf((*MockPackClient)(nil).ListCaches), arg0, arg1)
+}
+
+// MergeSBOM mocks base method.
+func (m *MockPackClient) MergeSBOM(arg0 string, arg1 client.MergeSBOMOptions) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeSBOM", arg0, arg1)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MergeSBOM indicates an expected call of MergeSBOM.
+func (mr *MockPackClientMockRecorder) MergeSBOM(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeSBOM", reflect.TypeOf((*MockPackClient)(nil).MergeSBOM), arg0, arg1)
+}
+
 // NewBuildpack mocks base method.
 func (m *MockPackClient) NewBuildpack(arg0 context.Context, arg1 client.NewBuildpackOptions) error {
 	m.ctrl.T.Helper()
@@ -255,6 +419,21 @@ func (mr *MockPackClientMockRecorder) PackageExtension(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PackageExtension", reflect.TypeOf((*MockPackClient)(nil).PackageExtension), arg0, arg1)
 }
 
+// Prune mocks base method.
+func (m *MockPackClient) Prune(arg0 context.Context, arg1 client.PruneOptions) ([]client.PrunedResource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Prune", arg0, arg1)
+	ret0, _ := ret[0].([]client.PrunedResource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Prune indicates an expected call of Prune.
+func (mr *MockPackClientMockRecorder) Prune(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prune", reflect.TypeOf((*MockPackClient)(nil).Prune), arg0, arg1)
+}
+
 // PullBuildpack mocks base method.
 func (m *MockPackClient) PullBuildpack(arg0 context.Context, arg1 client.PullBuildpackOptions) error {
 	m.ctrl.T.Helper()
@@ -325,6 +504,49 @@ func (mr *MockPackClientMockRecorder) RemoveManifest(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveManifest", reflect.TypeOf((*MockPackClient)(nil).RemoveManifest), arg0, arg1)
 }
 
+// Run mocks base method.
+func (m *MockPackClient) Run(arg0 context.Context, arg1 client.RunOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Run", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockPackClientMockRecorder) Run(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockPackClient)(nil).Run), arg0, arg1)
+}
+
+// SearchBuildpacks mocks base method.
+func (m *MockPackClient) SearchBuildpacks(arg0 context.Context, arg1 client.SearchBuildpacksOptions) ([]client.RegistryBuildpack, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchBuildpacks", arg0, arg1)
+	ret0, _ := ret[0].([]client.RegistryBuildpack)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchBuildpacks indicates an expected call of SearchBuildpacks.
+func (mr *MockPackClientMockRecorder) SearchBuildpacks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchBuildpacks", reflect.TypeOf((*MockPackClient)(nil).SearchBuildpacks), arg0, arg1)
+}
+
+// SetDefaultProcess mocks base method.
+func (m *MockPackClient) SetDefaultProcess(arg0 context.Context, arg1 client.SetDefaultProcessOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDefaultProcess", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDefaultProcess indicates an expected call of SetDefaultProcess.
+func (mr *MockPackClientMockRecorder) SetDefaultProcess(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDefaultProcess", reflect.TypeOf((*MockPackClient)(nil).SetDefaultProcess), arg0, arg1)
+}
+
 // YankBuildpack mocks base method.
 func (m *MockPackClient) YankBuildpack(arg0 client.YankBuildpackOptions) error {
 	m.ctrl.T.Helper()
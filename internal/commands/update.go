@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/internal/update"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+type UpdateFlags struct {
+	Channel string
+	Check   bool
+}
+
+// Update replaces pack with the latest release of a release channel
+func Update(logger logging.Logger, cfg config.Config, version string) *cobra.Command {
+	var flags UpdateFlags
+
+	cmd := &cobra.Command{
+		Use:   "update",
+		Args:  cobra.NoArgs,
+		Short: "Update pack to the latest release",
+		Long: "Update pack to the latest release of a release channel, either stable or pre-release. The archive of the " +
+			"release is verified with its SHA-256 checksum, and with its signature when a public key is set with " +
+			"`pack config update --public-key`, before the pack executable is replaced.\n\n" +
+			"Releases are found on GitHub, or on the mirror set with `pack config update --mirror`.",
+		Example: "pack update --channel pre-release",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			channel := flags.Channel
+			if channel == "" {
+				channel = cfg.Update.Channel
+			}
+			if channel == "" {
+				channel = update.ChannelStable
+			}
+
+			var ops []update.UpdaterOption
+			if cfg.Update.PublicKey != "" {
+				publicKey, err := os.ReadFile(cfg.Update.PublicKey)
+				if err != nil {
+					return errors.Wrap(err, "reading public key")
+				}
+				ops = append(ops, update.WithPublicKey(publicKey))
+			}
+			updater := update.NewUpdater(cfg.Update.Mirror, ops...)
+
+			release, err := updater.Latest(cmd.Context(), channel)
+			if err != nil {
+				return err
+			}
+			current := strings.TrimSpace(version)
+			if !release.Newer(current) {
+				logger.Infof("pack %s is up to date with the %s channel", style.Symbol(current), channel)
+				return nil
+			}
+			if flags.Check {
+				logger.Infof("pack %s is available on the %s channel (current version %s), run `pack update` to install it", style.Symbol(release.Version.String()), channel, style.Symbol(current))
+				return nil
+			}
+
+			executable, err := os.Executable()
+			if err != nil {
+				return errors.Wrap(err, "finding the pack executable")
+			}
+			if executable, err = filepath.EvalSymlinks(executable); err != nil {
+				return errors.Wrap(err, "finding the pack executable")
+			}
+
+			logger.Infof("Downloading pack %s", style.Symbol(release.Version.String()))
+			binary, err := updater.Download(cmd.Context(), release, runtime.GOOS, runtime.GOARCH)
+			if err != nil {
+				return err
+			}
+			if err := update.Replace(executable, binary); err != nil {
+				return errors.Wrap(err, "replacing the pack executable")
+			}
+
+			logger.Infof("Updated pack from %s to %s", style.Symbol(current), style.Symbol(release.Version.String()))
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringVar(&flags.Channel, "channel", "", "Release channel to update from, stable or pre-release (defaults to the configured channel, or stable)")
+	cmd.Flags().BoolVar(&flags.Check, "check", false, "Only check whether a newer release is available")
+	AddHelpFlag(cmd, "update")
+	return cmd
+}
@@ -0,0 +1,71 @@
+package commands_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestUpdateCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "UpdateCommand", testUpdateCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testUpdateCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		outBuf bytes.Buffer
+		logger logging.Logger
+		server *httptest.Server
+		cfg    config.Config
+	)
+
+	it.Before(func() {
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h.AssertEq(t, r.URL.Path, "/releases")
+			_, _ = w.Write([]byte(`[{"tag_name": "v0.35.0-rc1", "prerelease": true}, {"tag_name": "v0.34.0"}]`))
+		}))
+		cfg = config.Config{Update: config.Update{Mirror: server.URL}}
+	})
+
+	it.After(func() {
+		server.Close()
+	})
+
+	when("#Update", func() {
+		it("reports that pack is up to date", func() {
+			command := commands.Update(logger, cfg, "0.34.0+git-8d1b1e8.build-5623\n")
+			command.SetArgs([]string{})
+
+			h.AssertNil(t, command.Execute())
+			h.AssertContains(t, outBuf.String(), "pack '0.34.0+git-8d1b1e8.build-5623' is up to date with the stable channel")
+		})
+
+		it("checks for a newer release of the configured channel", func() {
+			cfg.Update.Channel = "pre-release"
+			command := commands.Update(logger, cfg, "0.34.0")
+			command.SetArgs([]string{"--check"})
+
+			h.AssertNil(t, command.Execute())
+			h.AssertContains(t, outBuf.String(), "pack '0.35.0-rc1' is available on the pre-release channel (current version '0.34.0')")
+		})
+
+		it("fails for unknown channels", func() {
+			command := commands.Update(logger, cfg, "0.34.0")
+			command.SetArgs([]string{"--channel", "nightly"})
+
+			h.AssertError(t, command.Execute(), "unknown release channel 'nightly'")
+		})
+	})
+}
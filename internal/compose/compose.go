@@ -0,0 +1,178 @@
+// Package compose reads compose files, which describe several images built together by pack compose, and orders
+// their builds so that images are built after the images they depend on.
+package compose
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// DefaultFiles are the compose files looked up in the working directory, in order, when none is given
+var DefaultFiles = []string{"pack-compose.toml", "pack-compose.yaml", "pack-compose.yml"}
+
+// File describes the images of a compose file, by name
+type File struct {
+	// Builder of the images that don't set their own
+	Builder string `toml:"builder" yaml:"builder"`
+
+	// Caches shared by every image
+	Cache Cache `toml:"cache" yaml:"cache"`
+
+	Images map[string]Image `toml:"images" yaml:"images"`
+}
+
+// Cache names the volumes holding the caches shared by the builds of a compose file. Without a name, each image
+// has its own cache.
+type Cache struct {
+	Build  string `toml:"build" yaml:"build"`
+	Launch string `toml:"launch" yaml:"launch"`
+}
+
+// Image describes the build of an image
+type Image struct {
+	// Name of the image to build
+	Image string `toml:"image" yaml:"image"`
+
+	// Path to the source code of the image, relative to the compose file
+	Path string `toml:"path" yaml:"path"`
+
+	Builder    string            `toml:"builder" yaml:"builder"`
+	RunImage   string            `toml:"run-image" yaml:"run-image"`
+	Buildpacks []string          `toml:"buildpacks" yaml:"buildpacks"`
+	Env        map[string]string `toml:"env" yaml:"env"`
+	Tags       []string          `toml:"tags" yaml:"tags"`
+	Publish    bool              `toml:"publish" yaml:"publish"`
+
+	// Images of the compose file that must be built before this one, by name
+	DependsOn []string `toml:"depends-on" yaml:"depends-on"`
+}
+
+// Find returns the first of DefaultFiles found in dir
+func Find(dir string) (string, error) {
+	for _, name := range DefaultFiles {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", errors.Errorf("no compose file found, create one of %s or set one with --file", strings.Join(DefaultFiles, ", "))
+}
+
+// Read reads and validates the compose file at path, in YAML when its extension is .yaml or .yml and in TOML
+// otherwise. Paths of images are made relative to the working directory.
+func Read(path string) (File, error) {
+	contents, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return File{}, errors.Wrapf(err, "reading compose file %s", style.Symbol(path))
+	}
+
+	var file File
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(contents, &file)
+	default:
+		_, err = toml.Decode(string(contents), &file)
+	}
+	if err != nil {
+		return File{}, errors.Wrapf(err, "parsing compose file %s", style.Symbol(path))
+	}
+
+	if err := file.validate(); err != nil {
+		return File{}, errors.Wrapf(err, "invalid compose file %s", style.Symbol(path))
+	}
+
+	dir := filepath.Dir(path)
+	for name, image := range file.Images {
+		image.Path = filepath.Join(dir, filepath.FromSlash(image.Path))
+		file.Images[name] = image
+	}
+	return file, nil
+}
+
+func (f File) validate() error {
+	if len(f.Images) == 0 {
+		return errors.New("no images declared")
+	}
+
+	for _, name := range f.names() {
+		image := f.Images[name]
+		if image.Image == "" {
+			return errors.Errorf("image %s: image is required", style.Symbol(name))
+		}
+		for _, dependency := range image.DependsOn {
+			if _, ok := f.Images[dependency]; !ok {
+				return errors.Errorf("image %s depends on unknown image %s", style.Symbol(name), style.Symbol(dependency))
+			}
+		}
+	}
+
+	_, err := f.Order(nil)
+	return err
+}
+
+func (f File) names() []string {
+	var names []string
+	for name := range f.Images {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Order returns the names of the images to build, each after the images it depends on. Without names, every image
+// is built; otherwise the named images and their dependencies are. Independent images are ordered by name.
+func (f File) Order(names []string) ([]string, error) {
+	if len(names) == 0 {
+		names = f.names()
+	}
+
+	const (
+		visiting = iota + 1
+		visited
+	)
+	var (
+		order []string
+		state = map[string]int{}
+		visit func(name string, path []string) error
+	)
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case visiting:
+			return errors.Errorf("dependency cycle %s", strings.Join(append(path, name), " -> "))
+		case visited:
+			return nil
+		}
+
+		image, ok := f.Images[name]
+		if !ok {
+			return errors.Errorf("unknown image %s", style.Symbol(name))
+		}
+
+		state[name] = visiting
+		dependencies := append([]string{}, image.DependsOn...)
+		sort.Strings(dependencies)
+		for _, dependency := range dependencies {
+			if err := visit(dependency, append(path, name)); err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+		order = append(order, name)
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
@@ -0,0 +1,165 @@
+package compose_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/compose"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestCompose(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Compose", testCompose, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testCompose(t *testing.T, when spec.G, it spec.S) {
+	var tmpDir string
+
+	it.Before(func() {
+		tmpDir = t.TempDir()
+	})
+
+	write := func(name, contents string) string {
+		path := filepath.Join(tmpDir, name)
+		h.AssertNil(t, os.WriteFile(path, []byte(contents), 0600))
+		return path
+	}
+
+	when("#Read", func() {
+		it("reads TOML", func() {
+			file, err := compose.Read(write("pack-compose.toml", `
+builder = "some/builder"
+
+[cache]
+build = "shared-build"
+
+[images.api]
+image = "registry.example.com/api"
+path = "services/api"
+env = { KEY = "value" }
+
+[images.web]
+image = "registry.example.com/web"
+path = "services/web"
+builder = "web/builder"
+tags = ["registry.example.com/web:1.0"]
+publish = true
+depends-on = ["api"]
+`))
+			h.AssertNil(t, err)
+			h.AssertEq(t, file.Builder, "some/builder")
+			h.AssertEq(t, file.Cache.Build, "shared-build")
+			h.AssertEq(t, file.Images["api"].Path, filepath.Join(tmpDir, "services", "api"))
+			h.AssertEq(t, file.Images["api"].Env, map[string]string{"KEY": "value"})
+			h.AssertEq(t, file.Images["web"].Builder, "web/builder")
+			h.AssertEq(t, file.Images["web"].Tags, []string{"registry.example.com/web:1.0"})
+			h.AssertTrue(t, file.Images["web"].Publish)
+			h.AssertEq(t, file.Images["web"].DependsOn, []string{"api"})
+		})
+
+		it("reads YAML", func() {
+			file, err := compose.Read(write("pack-compose.yaml", `
+cache:
+  launch: shared-launch
+images:
+  api:
+    image: registry.example.com/api
+    run-image: some/run
+    buildpacks: [some/buildpack]
+`))
+			h.AssertNil(t, err)
+			h.AssertEq(t, file.Cache.Launch, "shared-launch")
+			h.AssertEq(t, file.Images["api"].RunImage, "some/run")
+			h.AssertEq(t, file.Images["api"].Buildpacks, []string{"some/buildpack"})
+			h.AssertEq(t, file.Images["api"].Path, tmpDir)
+		})
+
+		it("requires images", func() {
+			_, err := compose.Read(write("pack-compose.toml", `builder = "some/builder"`))
+			h.AssertError(t, err, "no images declared")
+		})
+
+		it("requires the name of each image", func() {
+			_, err := compose.Read(write("pack-compose.toml", `
+[images.api]
+path = "api"
+`))
+			h.AssertError(t, err, "image 'api': image is required")
+		})
+
+		it("rejects unknown dependencies", func() {
+			_, err := compose.Read(write("pack-compose.toml", `
+[images.api]
+image = "api"
+depends-on = ["db"]
+`))
+			h.AssertError(t, err, "image 'api' depends on unknown image 'db'")
+		})
+
+		it("rejects dependency cycles", func() {
+			_, err := compose.Read(write("pack-compose.toml", `
+[images.a]
+image = "a"
+depends-on = ["b"]
+
+[images.b]
+image = "b"
+depends-on = ["a"]
+`))
+			h.AssertError(t, err, "dependency cycle a -> b -> a")
+		})
+	})
+
+	when("#Find", func() {
+		it("finds the compose files in order", func() {
+			write("pack-compose.yml", "")
+			path, err := compose.Find(tmpDir)
+			h.AssertNil(t, err)
+			h.AssertEq(t, path, filepath.Join(tmpDir, "pack-compose.yml"))
+
+			write("pack-compose.toml", "")
+			path, err = compose.Find(tmpDir)
+			h.AssertNil(t, err)
+			h.AssertEq(t, path, filepath.Join(tmpDir, "pack-compose.toml"))
+		})
+
+		it("errors without a compose file", func() {
+			_, err := compose.Find(tmpDir)
+			h.AssertError(t, err, "no compose file found")
+		})
+	})
+
+	when("#Order", func() {
+		file := compose.File{Images: map[string]compose.Image{
+			"web":    {Image: "web", DependsOn: []string{"api", "assets"}},
+			"api":    {Image: "api", DependsOn: []string{"base"}},
+			"assets": {Image: "assets"},
+			"base":   {Image: "base"},
+			"docs":   {Image: "docs"},
+		}}
+
+		it("orders every image after its dependencies", func() {
+			order, err := file.Order(nil)
+			h.AssertNil(t, err)
+			h.AssertEq(t, order, []string{"base", "api", "assets", "docs", "web"})
+		})
+
+		it("orders the named images and their dependencies", func() {
+			order, err := file.Order([]string{"web"})
+			h.AssertNil(t, err)
+			h.AssertEq(t, order, []string{"base", "api", "assets", "web"})
+		})
+
+		it("errors on unknown images", func() {
+			_, err := file.Order([]string{"db"})
+			h.AssertError(t, err, "unknown image 'db'")
+		})
+	})
+}
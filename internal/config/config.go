@@ -23,6 +23,76 @@ type Config struct {
 	LifecycleImage      string            `toml:"lifecycle-image,omitempty"`
 	RegistryMirrors     map[string]string `toml:"registry-mirrors,omitempty"`
 	LayoutRepositoryDir string            `toml:"layout-repo-dir,omitempty"`
+	ColorTheme          string            `toml:"color-theme,omitempty"`
+	Locale              string            `toml:"locale,omitempty"`
+	SaveLogs            bool              `toml:"save-logs,omitempty"`
+	Telemetry           bool              `toml:"telemetry,omitempty"`
+	TelemetryEndpoint   string            `toml:"telemetry-endpoint,omitempty"`
+	Scan                Scan              `toml:"scan,omitempty"`
+	Update              Update            `toml:"update,omitempty"`
+	Policy              string            `toml:"policy,omitempty"`
+	SecurityProfiles    SecurityProfiles  `toml:"security-profiles,omitempty"`
+
+	// RegistryRefreshInterval is how long after a refresh the registry caches aren't refreshed again, e.g. "15m"
+	RegistryRefreshInterval string `toml:"registry-refresh-interval,omitempty"`
+
+	// RegistryCacheMaxAge is how long a registry cache may go unused before it's removed automatically, e.g. "720h",
+	// with "0s" keeping the caches
+	RegistryCacheMaxAge string `toml:"registry-cache-max-age,omitempty"`
+
+	// OfficialRegistryMirrors are the mirrors of the official registry, which isn't in Registries
+	OfficialRegistryMirrors []string `toml:"official-registry-mirrors,omitempty"`
+
+	// RegistryProxy is the proxy the registry caches are cloned, pulled and fetched through, e.g.
+	// "http://proxy.example.com:3128", rather than the proxy of the environment
+	RegistryProxy string `toml:"registry-proxy,omitempty"`
+
+	// DockerHost is the daemon pack uses when DOCKER_HOST isn't set, e.g. "ssh://user@build-host" or "podman"
+	DockerHost string `toml:"docker-host,omitempty"`
+
+	// PullPolicies override PullPolicy for the builder, run image or buildpacks of builds
+	PullPolicies PullPolicies `toml:"pull-policies,omitempty"`
+
+	// CacheSizeLimit is the size the volume build caches are pruned to before each build, e.g. "5GB"
+	CacheSizeLimit string `toml:"cache-size-limit,omitempty"`
+
+	// DownloadWorkers is how many buildpacks and extensions are downloaded at once, the default of the client when 0
+	DownloadWorkers int `toml:"download-workers,omitempty"`
+
+	// LifecycleMirror is where the archives of released lifecycle versions are downloaded from, along with their
+	// checksums, rather than the releases of the lifecycle on GitHub
+	LifecycleMirror string `toml:"lifecycle-mirror,omitempty"`
+
+	// CacheSizeLimits override CacheSizeLimit for the app images they're keyed by, by the full name of the image
+	CacheSizeLimits map[string]string `toml:"cache-size-limits,omitempty"`
+}
+
+// PullPolicies are the pull policies of the images of builds, when they differ from the pull policy
+type PullPolicies struct {
+	Builder    string `toml:"builder,omitempty"`
+	RunImage   string `toml:"run-image,omitempty"`
+	Buildpacks string `toml:"buildpacks,omitempty"`
+}
+
+// SecurityProfiles are the seccomp and AppArmor profiles of the lifecycle containers, when not the daemon's defaults
+type SecurityProfiles struct {
+	Seccomp  string `toml:"seccomp,omitempty"`
+	AppArmor string `toml:"apparmor,omitempty"`
+}
+
+// Scan configures the vulnerability scan of images after a successful build, when a scanner is set
+type Scan struct {
+	Scanner string `toml:"scanner,omitempty"`
+	Command string `toml:"command,omitempty"`
+	FailOn  string `toml:"fail-on,omitempty"`
+}
+
+// Update configures where `pack update` finds new versions of pack
+type Update struct {
+	Channel   string `toml:"channel,omitempty"`
+	Mirror    string `toml:"mirror,omitempty"`
+	PublicKey string `toml:"public-key,omitempty"`
+	Notify    bool   `toml:"notify,omitempty"`
 }
 
 type VolumeConfig struct {
@@ -33,6 +103,12 @@ type Registry struct {
 	Name string `toml:"name"`
 	Type string `toml:"type"`
 	URL  string `toml:"url"`
+
+	// Token authenticates to a private registry served over HTTPS, unless PACK_REGISTRY_TOKEN_<NAME> is set for it
+	Token string `toml:"token,omitempty"`
+
+	// Mirrors are cloned and pulled from, in order, when the registry can't be reached
+	Mirrors []string `toml:"mirrors,omitempty"`
 }
 
 type RunImage struct {
@@ -48,9 +124,9 @@ const OfficialRegistryName = "official"
 
 func DefaultRegistry() Registry {
 	return Registry{
-		OfficialRegistryName,
-		"github",
-		"https://github.com/buildpacks/registry-index",
+		Name: OfficialRegistryName,
+		Type: "github",
+		URL:  "https://github.com/buildpacks/registry-index",
 	}
 }
 
@@ -129,7 +205,9 @@ func SetRunImageMirrors(cfg Config, image string, mirrors []string) Config {
 }
 
 func GetRegistries(cfg Config) []Registry {
-	return append(cfg.Registries, DefaultRegistry())
+	official := DefaultRegistry()
+	official.Mirrors = cfg.OfficialRegistryMirrors
+	return append(cfg.Registries, official)
 }
 
 func GetRegistry(cfg Config, registryName string) (Registry, error) {
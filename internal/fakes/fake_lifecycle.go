@@ -2,6 +2,8 @@ package fakes
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 
 	"github.com/buildpacks/pack/internal/build"
 )
@@ -12,5 +14,12 @@ type FakeLifecycle struct {
 
 func (f *FakeLifecycle) Execute(ctx context.Context, opts build.LifecycleOptions) error {
 	f.Opts = opts
+	if opts.DetectDestinationDir != "" {
+		// what the detector copies out for a group of a single buildpack
+		if err := os.WriteFile(filepath.Join(opts.DetectDestinationDir, "group.toml"), []byte("[[group]]\n  id = \"buildpack.1.id\"\n  version = \"buildpack.1.version\"\n"), 0600); err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(opts.DetectDestinationDir, "plan.toml"), []byte("[[entries]]\n\n  [[entries.providers]]\n    id = \"buildpack.1.id\"\n    version = \"buildpack.1.version\"\n"), 0600)
+	}
 	return nil
 }
@@ -0,0 +1,138 @@
+// Package gitsource fetches the source of an app from a git repository, with the git executable so that the
+// credential helpers and SSH configuration of git authenticate to the repository.
+package gitsource
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+// urlSchemes are the schemes of the URLs of git repositories
+var urlSchemes = []string{"https://", "http://", "ssh://", "git://", "git+ssh://", "file://"}
+
+// scpLikeURL matches the scp-like syntax of SSH URLs of git, such as git@github.com:org/app.git
+var scpLikeURL = regexp.MustCompile(`^[A-Za-z0-9._-]+@[A-Za-z0-9.-]+:[^/\\]`)
+
+// Source is a revision of a git repository
+type Source struct {
+	// URL of the repository
+	URL string
+
+	// Ref is the branch, tag or commit to fetch, the default branch of the repository when empty
+	Ref string
+}
+
+func (s Source) String() string {
+	if s.Ref == "" {
+		return s.URL
+	}
+	return s.URL + "#" + s.Ref
+}
+
+// Parse parses a path of the form <url>#<ref> as the source of an app, returning false when path isn't the URL of
+// a git repository
+func Parse(path string) (Source, bool) {
+	isURL := scpLikeURL.MatchString(path)
+	for _, scheme := range urlSchemes {
+		isURL = isURL || strings.HasPrefix(path, scheme)
+	}
+	if !isURL {
+		return Source{}, false
+	}
+
+	url, ref, _ := strings.Cut(path, "#")
+	return Source{URL: url, Ref: ref}, true
+}
+
+// Options of the git executable
+type Options struct {
+	// Git is the git executable, defaults to git in PATH
+	Git string
+}
+
+// Fetch checks out the revision of source in dir, cloning the repository when dir is empty or missing, and otherwise
+// updating the clone that dir has, so that dir may be a workspace reused across builds. Only the revision is fetched,
+// with its submodules, and files that aren't in the revision are removed.
+func Fetch(ctx context.Context, logger logging.Logger, options Options, source Source, dir string) error {
+	// the ref is an argument of git fetch, which would take it for an option
+	if strings.HasPrefix(source.Ref, "-") {
+		return errors.Errorf("invalid ref %s of %s, refs can't start with '-'", style.Symbol(source.Ref), style.Symbol(source.URL))
+	}
+
+	git, err := findGit(options)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		logger.Debugf("Updating the clone of %s in %s", style.Symbol(source.URL), style.Symbol(dir))
+		if err := git.run(ctx, dir, "remote", "set-url", "origin", source.URL); err != nil {
+			return err
+		}
+	} else {
+		if err := git.run(ctx, "", "init", "--quiet", dir); err != nil {
+			return err
+		}
+		if err := git.run(ctx, dir, "remote", "add", "origin", source.URL); err != nil {
+			return err
+		}
+	}
+
+	ref := source.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	logger.Infof("Fetching %s", style.Symbol(source.String()))
+	if err := git.run(ctx, dir, "fetch", "--quiet", "--depth", "1", "origin", ref); err != nil {
+		return errors.Wrapf(err, "fetching %s", style.Symbol(source.String()))
+	}
+	for _, args := range [][]string{
+		{"checkout", "--quiet", "--force", "--detach", "FETCH_HEAD"},
+		{"clean", "--quiet", "-ffdx"},
+		{"submodule", "--quiet", "update", "--init", "--recursive", "--depth", "1"},
+	} {
+		if err := git.run(ctx, dir, args...); err != nil {
+			return errors.Wrapf(err, "checking out %s", style.Symbol(source.String()))
+		}
+	}
+	return nil
+}
+
+type gitExecutable string
+
+func findGit(options Options) (gitExecutable, error) {
+	command := options.Git
+	if command == "" {
+		command = "git"
+	}
+	executable, err := exec.LookPath(command)
+	if err != nil {
+		return "", errors.Wrapf(err, "finding git %s, which builds from a git repository require", style.Symbol(command))
+	}
+	return gitExecutable(executable), nil
+}
+
+// run runs git with args in dir, with its error output in the error when it fails
+func (g gitExecutable) run(ctx context.Context, dir string, args ...string) error {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, string(g), args...)
+	cmd.Dir = dir
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return errors.Wrap(errors.New(message), "running git "+args[0])
+		}
+		return errors.Wrap(err, "running git "+args[0])
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package gitsource_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/gitsource"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestGitSource(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "GitSource", testGitSource, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testGitSource(t *testing.T, when spec.G, it spec.S) {
+	when("#Parse", func() {
+		for _, tc := range []struct {
+			path string
+			want gitsource.Source
+		}{
+			{"https://github.com/org/app.git", gitsource.Source{URL: "https://github.com/org/app.git"}},
+			{"https://github.com/org/app.git#main", gitsource.Source{URL: "https://github.com/org/app.git", Ref: "main"}},
+			{"ssh://git@github.com/org/app.git#v1.0.0", gitsource.Source{URL: "ssh://git@github.com/org/app.git", Ref: "v1.0.0"}},
+			{"git@github.com:org/app.git#feature/x", gitsource.Source{URL: "git@github.com:org/app.git", Ref: "feature/x"}},
+		} {
+			tc := tc
+			it("parses "+tc.path, func() {
+				source, ok := gitsource.Parse(tc.path)
+				h.AssertTrue(t, ok)
+				h.AssertEq(t, source, tc.want)
+			})
+		}
+
+		for _, path := range []string{"", ".", "apps/api", "/workspace/app", `C:\apps\api`, "app.zip"} {
+			path := path
+			it("doesn't parse the local path "+path, func() {
+				_, ok := gitsource.Parse(path)
+				h.AssertFalse(t, ok)
+			})
+		}
+	})
+
+	when("#Fetch", func() {
+		var (
+			logger  logging.Logger
+			outBuf  bytes.Buffer
+			tmpDir  string
+			repoDir string
+			source  gitsource.Source
+		)
+
+		git := func(dir string, args ...string) {
+			t.Helper()
+			cmd := exec.Command("git", append([]string{"-c", "user.name=pack", "-c", "user.email=pack@example.com", "-c", "init.defaultBranch=main"}, args...)...)
+			cmd.Dir = dir
+			if output, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("running git %s: %s: %s", args[0], err, output)
+			}
+		}
+
+		commit := func(file, contents string) {
+			t.Helper()
+			h.AssertNil(t, os.WriteFile(filepath.Join(repoDir, file), []byte(contents), 0600))
+			git(repoDir, "add", file)
+			git(repoDir, "commit", "--quiet", "-m", "add "+file)
+		}
+
+		it.Before(func() {
+			_, err := exec.LookPath("git")
+			h.SkipIf(t, err != nil, "git isn't installed")
+
+			logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+			tmpDir, err = os.MkdirTemp("", "gitsource")
+			h.AssertNil(t, err)
+
+			repoDir = filepath.Join(tmpDir, "repo")
+			h.AssertNil(t, os.Mkdir(repoDir, 0755))
+			git(repoDir, "init", "--quiet")
+			commit("app.txt", "main")
+			source = gitsource.Source{URL: "file://" + filepath.ToSlash(repoDir)}
+		})
+
+		it.After(func() {
+			h.AssertNil(t, os.RemoveAll(tmpDir))
+		})
+
+		it("clones the default branch", func() {
+			workspace := filepath.Join(tmpDir, "workspace")
+
+			h.AssertNil(t, gitsource.Fetch(context.Background(), logger, gitsource.Options{}, source, workspace))
+			h.AssertEq(t, readFile(t, filepath.Join(workspace, "app.txt")), "main")
+			h.AssertContains(t, outBuf.String(), "Fetching '"+source.URL+"'")
+		})
+
+		it("checks out the ref", func() {
+			git(repoDir, "checkout", "--quiet", "-b", "feature")
+			commit("app.txt", "feature")
+			git(repoDir, "checkout", "--quiet", "main")
+			source.Ref = "feature"
+
+			workspace := filepath.Join(tmpDir, "workspace")
+			h.AssertNil(t, gitsource.Fetch(context.Background(), logger, gitsource.Options{}, source, workspace))
+			h.AssertEq(t, readFile(t, filepath.Join(workspace, "app.txt")), "feature")
+		})
+
+		it("updates a workspace, removing the files that aren't in the revision", func() {
+			workspace := filepath.Join(tmpDir, "workspace")
+			h.AssertNil(t, gitsource.Fetch(context.Background(), logger, gitsource.Options{}, source, workspace))
+			h.AssertNil(t, os.WriteFile(filepath.Join(workspace, "build-output"), []byte("stale"), 0600))
+
+			commit("app.txt", "updated")
+			h.AssertNil(t, gitsource.Fetch(context.Background(), logger, gitsource.Options{}, source, workspace))
+			h.AssertEq(t, readFile(t, filepath.Join(workspace, "app.txt")), "updated")
+			h.AssertPathDoesNotExists(t, filepath.Join(workspace, "build-output"))
+		})
+
+		it("errors for an unknown ref", func() {
+			source.Ref = "missing"
+
+			err := gitsource.Fetch(context.Background(), logger, gitsource.Options{}, source, filepath.Join(tmpDir, "workspace"))
+			h.AssertError(t, err, "fetching '"+source.String()+"'")
+			h.AssertError(t, err, "missing")
+		})
+
+		it("errors for a ref git would take for an option", func() {
+			marker := filepath.Join(tmpDir, "marker")
+			source.Ref = "--upload-pack=touch " + marker
+
+			err := gitsource.Fetch(context.Background(), logger, gitsource.Options{}, source, filepath.Join(tmpDir, "workspace"))
+			h.AssertError(t, err, "invalid ref '--upload-pack=touch "+marker+"'")
+			h.AssertPathDoesNotExists(t, marker)
+			h.AssertPathDoesNotExists(t, filepath.Join(tmpDir, "workspace"))
+		})
+
+		it("errors when git can't be found", func() {
+			err := gitsource.Fetch(context.Background(), logger, gitsource.Options{Git: filepath.Join(tmpDir, "missing-git")}, source, filepath.Join(tmpDir, "workspace"))
+			h.AssertError(t, err, "finding git")
+		})
+	})
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	contents, err := os.ReadFile(path)
+	h.AssertNil(t, err)
+	return string(contents)
+}
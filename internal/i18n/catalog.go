@@ -0,0 +1,57 @@
+package i18n
+
+// Message keys. Formats use fmt verbs and every catalog must use the same verbs in the same order.
+const (
+	RootShort               = "root.short"
+	BuildSuccess            = "build.success"
+	BuildSummary            = "build.summary"
+	BuildTimings            = "build.timings"
+	RebaseSuccess           = "rebase.success"
+	BuilderCreateSuccess    = "builder.create.success"
+	BuilderSelectDefault    = "builder.select-default"
+	BuilderSuggested        = "builder.suggested"
+	BuilderLearnMore        = "builder.learn-more"
+	BuilderPickerIntro      = "builder.picker.intro"
+	BuilderPickerPrompt     = "builder.picker.prompt"
+	BuilderPickerUsing      = "builder.picker.using"
+	BuilderPickerDefaultTip = "builder.picker.default-tip"
+	BuilderPickerInvalid    = "builder.picker.invalid"
+	WarningsAsErrors        = "warnings-as-errors"
+)
+
+var catalogs = map[string]map[string]string{
+	"en": {
+		RootShort:               "CLI for building apps using Cloud Native Buildpacks",
+		BuildSuccess:            "Successfully built image %s",
+		BuildSummary:            "Build summary:",
+		BuildTimings:            "Build timings:",
+		RebaseSuccess:           "Successfully rebased image %s",
+		BuilderCreateSuccess:    "Successfully created builder image %s",
+		BuilderSelectDefault:    "Please select a default builder with:",
+		BuilderSuggested:        "Suggested builders:",
+		BuilderLearnMore:        "Learn more about a specific builder with:",
+		BuilderPickerIntro:      "No builder was specified and no default builder is configured. Select one of the following builders:",
+		BuilderPickerPrompt:     "Builder [1-%d, press enter to skip]: ",
+		BuilderPickerUsing:      "Using builder %s",
+		BuilderPickerDefaultTip: "To use this builder by default, run `pack config default-builder %s`",
+		BuilderPickerInvalid:    "invalid builder selection %s, must be a number between 1 and %d",
+		WarningsAsErrors:        "%d warning(s) were reported and --warnings-as-errors is set",
+	},
+	"es": {
+		RootShort:               "CLI para construir aplicaciones con Cloud Native Buildpacks",
+		BuildSuccess:            "Imagen %s construida correctamente",
+		BuildSummary:            "Resumen de la construcción:",
+		BuildTimings:            "Tiempos de la construcción:",
+		RebaseSuccess:           "Imagen %s rebasada correctamente",
+		BuilderCreateSuccess:    "Imagen de builder %s creada correctamente",
+		BuilderSelectDefault:    "Seleccione un builder por defecto con:",
+		BuilderSuggested:        "Builders sugeridos:",
+		BuilderLearnMore:        "Obtenga más información sobre un builder con:",
+		BuilderPickerIntro:      "No se especificó ningún builder y no hay un builder por defecto configurado. Seleccione uno de los siguientes builders:",
+		BuilderPickerPrompt:     "Builder [1-%d, pulse intro para omitir]: ",
+		BuilderPickerUsing:      "Usando el builder %s",
+		BuilderPickerDefaultTip: "Para usar este builder por defecto, ejecute `pack config default-builder %s`",
+		BuilderPickerInvalid:    "selección de builder %s no válida, debe ser un número entre 1 y %d",
+		WarningsAsErrors:        "se reportaron %d advertencia(s) y --warnings-as-errors está activado",
+	},
+}
@@ -0,0 +1,106 @@
+// Package i18n translates user-facing CLI messages.
+//
+// Messages are looked up by key in the catalog of the selected locale, falling back to English
+// when the locale has no translation for a key.
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// DefaultLocale is the locale used when no other locale is selected or the selected one is not supported
+const DefaultLocale = "en"
+
+var current = DefaultLocale
+
+// Locales returns the supported locales
+func Locales() []string {
+	var locales []string
+	for locale := range catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// SetLocale selects the locale used by T. An empty locale selects the default locale.
+func SetLocale(locale string) error {
+	if locale == "" {
+		locale = DefaultLocale
+	}
+
+	if err := ValidateLocale(locale); err != nil {
+		return err
+	}
+
+	current = locale
+	return nil
+}
+
+// ValidateLocale returns an error if there is no catalog for the given locale
+func ValidateLocale(locale string) error {
+	if _, ok := catalogs[locale]; !ok {
+		return errors.Errorf("unsupported locale %s, must be one of %s", style.Symbol(locale), strings.Join(Locales(), ", "))
+	}
+	return nil
+}
+
+// DetectLocale returns the locale to use. In order of precedence:
+//   - the PACK_LOCALE environment variable,
+//   - the locale set in the config,
+//   - the LC_ALL, LC_MESSAGES and LANG environment variables (e.g. es_ES.UTF-8).
+//
+// The default locale is returned when none of them names a supported locale.
+func DetectLocale(configured string, getenv func(string) string) string {
+	candidates := []string{getenv("PACK_LOCALE"), configured, getenv("LC_ALL"), getenv("LC_MESSAGES"), getenv("LANG")}
+	for _, candidate := range candidates {
+		if candidate == "" {
+			continue
+		}
+
+		// the first non-empty value wins, as with POSIX locale resolution
+		locale := normalize(candidate)
+		if _, ok := catalogs[locale]; ok {
+			return locale
+		}
+		return DefaultLocale
+	}
+	return DefaultLocale
+}
+
+// T returns the message for key in the current locale, formatted with args
+func T(key string, args ...interface{}) string {
+	format, ok := catalogs[current][key]
+	if !ok {
+		format, ok = catalogs[DefaultLocale][key]
+	}
+	if !ok {
+		format = key
+	}
+
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// normalize turns a POSIX locale such as es_ES.UTF-8 into a catalog name such as es
+func normalize(locale string) string {
+	locale = strings.ToLower(locale)
+	if i := strings.IndexAny(locale, ".@"); i >= 0 {
+		locale = locale[:i]
+	}
+	if i := strings.IndexAny(locale, "_-"); i >= 0 {
+		locale = locale[:i]
+	}
+	if locale == "c" || locale == "posix" {
+		return DefaultLocale
+	}
+	return locale
+}
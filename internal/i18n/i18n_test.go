@@ -0,0 +1,97 @@
+package i18n_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/i18n"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestI18n(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "I18n", testI18n, spec.Sequential(), spec.Report(report.Terminal{}))
+}
+
+func testI18n(t *testing.T, when spec.G, it spec.S) {
+	it.After(func() {
+		h.AssertNil(t, i18n.SetLocale(i18n.DefaultLocale))
+	})
+
+	env := func(vars map[string]string) func(string) string {
+		return func(key string) string { return vars[key] }
+	}
+
+	when("#Locales", func() {
+		it("lists the supported locales", func() {
+			h.AssertEq(t, i18n.Locales(), []string{"en", "es"})
+		})
+	})
+
+	when("#T", func() {
+		it("formats the message of the current locale", func() {
+			h.AssertEq(t, i18n.T(i18n.BuildSuccess, "'some/image'"), "Successfully built image 'some/image'")
+
+			h.AssertNil(t, i18n.SetLocale("es"))
+			h.AssertEq(t, i18n.T(i18n.BuildSuccess, "'some/image'"), "Imagen 'some/image' construida correctamente")
+		})
+
+		it("returns the key for unknown messages", func() {
+			h.AssertEq(t, i18n.T("some.unknown.key"), "some.unknown.key")
+		})
+	})
+
+	when("#SetLocale", func() {
+		it("errors for unsupported locales", func() {
+			h.AssertError(t, i18n.SetLocale("xx"), "unsupported locale 'xx', must be one of en, es")
+		})
+	})
+
+	when("#DetectLocale", func() {
+		it("prefers PACK_LOCALE over the config", func() {
+			h.AssertEq(t, i18n.DetectLocale("en", env(map[string]string{"PACK_LOCALE": "es"})), "es")
+		})
+
+		it("prefers the config over the POSIX locale", func() {
+			h.AssertEq(t, i18n.DetectLocale("en", env(map[string]string{"LANG": "es_ES.UTF-8"})), "en")
+		})
+
+		it("normalizes the POSIX locale", func() {
+			h.AssertEq(t, i18n.DetectLocale("", env(map[string]string{"LANG": "es_ES.UTF-8"})), "es")
+			h.AssertEq(t, i18n.DetectLocale("", env(map[string]string{"LC_ALL": "C", "LANG": "es_ES.UTF-8"})), "en")
+		})
+
+		it("falls back to the default locale", func() {
+			h.AssertEq(t, i18n.DetectLocale("", env(map[string]string{"LANG": "fr_FR.UTF-8"})), "en")
+			h.AssertEq(t, i18n.DetectLocale("", env(nil)), "en")
+		})
+	})
+
+	when("catalogs", func() {
+		it("use the same verbs as the default locale", func() {
+			verbs := regexp.MustCompile(`%[a-z]`)
+			keys := []string{
+				i18n.RootShort, i18n.BuildSuccess, i18n.RebaseSuccess, i18n.BuilderCreateSuccess, i18n.BuilderSelectDefault,
+				i18n.BuilderSuggested, i18n.BuilderLearnMore, i18n.BuilderPickerIntro, i18n.BuilderPickerPrompt,
+				i18n.BuilderPickerUsing, i18n.BuilderPickerDefaultTip, i18n.BuilderPickerInvalid, i18n.WarningsAsErrors,
+			}
+
+			expected := map[string][]string{}
+			for _, key := range keys {
+				expected[key] = verbs.FindAllString(i18n.T(key), -1)
+			}
+
+			for _, locale := range i18n.Locales() {
+				h.AssertNil(t, i18n.SetLocale(locale))
+				for _, key := range keys {
+					h.AssertEq(t, verbs.FindAllString(i18n.T(key), -1), expected[key])
+				}
+			}
+		})
+	})
+}
@@ -0,0 +1,130 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// Directories of the volumes of the containers of a Job
+const (
+	AppDir      = "/workspace"
+	LayersDir   = "/layers"
+	PlatformDir = "/platform"
+	CacheDir    = "/cache"
+)
+
+// Names of the containers of the pod of a Job
+const (
+	prepareContainer = "prepare"
+	creatorContainer = "creator"
+)
+
+// readyFile is created in the app directory once the files of the Job are copied, which the prepare container waits
+// for before the creator starts
+const readyFile = AppDir + "/.pack-ready"
+
+// reportPath is where the creator writes its report, which is the termination message of its container
+const reportPath = LayersDir + "/report.toml"
+
+// managedByLabel marks the Jobs and Secrets created by pack
+const managedByLabel = "app.kubernetes.io/managed-by"
+
+// Job is a build run as a Job: the creator of a builder, once Files are extracted in the volumes of its pod
+type Job struct {
+	// Name of the Job, and of the Secret of its SecretEnv
+	Name string
+
+	// Image is the builder image
+	Image string
+
+	// UID and GID of the user of the builder, who owns the extracted files
+	UID, GID int
+
+	// Command runs the creator, with its arguments
+	Command []string
+
+	// Env of the creator, in the form KEY=VALUE
+	Env []string
+
+	// SecretEnv is env of the creator kept in a Secret rather than in the Job, e.g. CNB_REGISTRY_AUTH
+	SecretEnv map[string]string
+
+	// Files is a tar archive extracted at the root of the pod, with entries under AppDir and PlatformDir
+	Files io.Reader
+}
+
+// manifest is the list of the Secret and the Job to apply, in JSON
+func (j Job) manifest() ([]byte, error) {
+	labels := map[string]string{managedByLabel: "pack"}
+	metadata := map[string]interface{}{"name": j.Name, "labels": labels}
+
+	var env []map[string]string
+	for _, pair := range j.Env {
+		key, value, _ := strings.Cut(pair, "=")
+		env = append(env, map[string]string{"name": key, "value": value})
+	}
+
+	volumes := []map[string]interface{}{}
+	mounts := []map[string]string{}
+	for _, volume := range []struct{ name, dir string }{{"workspace", AppDir}, {"layers", LayersDir}, {"platform", PlatformDir}, {"cache", CacheDir}} {
+		volumes = append(volumes, map[string]interface{}{"name": volume.name, "emptyDir": map[string]string{}})
+		mounts = append(mounts, map[string]string{"name": volume.name, "mountPath": volume.dir})
+	}
+
+	waitForFiles := "until [ -f " + readyFile + " ]; do sleep 1; done; rm " + readyFile
+	job := map[string]interface{}{
+		"apiVersion": "batch/v1",
+		"kind":       "Job",
+		"metadata":   metadata,
+		"spec": map[string]interface{}{
+			"backoffLimit":            0,
+			"ttlSecondsAfterFinished": 3600,
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{"labels": labels},
+				"spec": map[string]interface{}{
+					"restartPolicy":   "Never",
+					"securityContext": map[string]int{"fsGroup": j.GID},
+					"volumes":         volumes,
+					"initContainers": []map[string]interface{}{{
+						"name":            prepareContainer,
+						"image":           j.Image,
+						"command":         []string{"/bin/sh", "-c", waitForFiles},
+						"securityContext": map[string]int{"runAsUser": j.UID, "runAsGroup": j.GID},
+						"volumeMounts":    mounts,
+					}},
+					"containers": []map[string]interface{}{{
+						"name":    creatorContainer,
+						"image":   j.Image,
+						"command": j.Command,
+						"env":     env,
+						"envFrom": []map[string]interface{}{{"secretRef": map[string]string{"name": j.Name}}},
+						// the creator drops the privileges of root for those of the user of the builder, like the
+						// creator container of a trusted builder
+						"securityContext":          map[string]int{"runAsUser": 0, "runAsGroup": 0},
+						"volumeMounts":             mounts,
+						"terminationMessagePath":   reportPath,
+						"terminationMessagePolicy": "File",
+					}},
+				},
+			},
+		},
+	}
+	secretEnv := j.SecretEnv
+	if secretEnv == nil {
+		secretEnv = map[string]string{}
+	}
+	secret := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   metadata,
+		"type":       "Opaque",
+		"stringData": secretEnv,
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "List",
+		"items":      []interface{}{secret, job},
+	})
+}
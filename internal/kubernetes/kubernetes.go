@@ -0,0 +1,254 @@
+// Package kubernetes runs builds as Jobs of a Kubernetes cluster, through kubectl, the way kpack builds images in a
+// cluster, while pack streams the logs of the build and reports the image it exported.
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+// pollInterval is how long to wait between checks of the state of a pod
+var pollInterval = time.Second
+
+// failedWaitingReasons are the reasons a container waits for that it won't recover from
+var failedWaitingReasons = []string{"ErrImagePull", "ImagePullBackOff", "InvalidImageName", "CreateContainerConfigError", "CreateContainerError"}
+
+// Options of the cluster a Job runs in
+type Options struct {
+	// Kubectl is the kubectl executable, defaults to kubectl in PATH
+	Kubectl string
+
+	// Context of the kubeconfig, defaults to its current context
+	Context string
+
+	// Namespace of the Job, defaults to the namespace of the context
+	Namespace string
+}
+
+// Result of a Job
+type Result struct {
+	// Digest of the image the creator exported
+	Digest string
+}
+
+// Run creates the Job, copies its files to its pod, streams the logs of the creator to the logger and returns the
+// digest of the image it exported. The Job and its Secret are deleted once it's done, or when ctx is canceled.
+func Run(ctx context.Context, logger logging.Logger, options Options, job Job) (Result, error) {
+	kubectl, err := newKubectl(options)
+	if err != nil {
+		return Result{}, err
+	}
+
+	manifest, err := job.manifest()
+	if err != nil {
+		return Result{}, err
+	}
+	if _, err := kubectl.run(ctx, bytes.NewReader(manifest), "apply", "-f", "-"); err != nil {
+		return Result{}, errors.Wrapf(err, "creating job %s", style.Symbol(job.Name))
+	}
+	defer func() {
+		// not with ctx, so that the Job is deleted when the build is canceled
+		if _, err := kubectl.run(context.Background(), nil, "delete", "job/"+job.Name, "secret/"+job.Name, "--ignore-not-found", "--wait=false"); err != nil {
+			logger.Warnf("Unable to delete job %s: %s", style.Symbol(job.Name), err)
+		}
+	}()
+	logger.Debugf("Created job %s", style.Symbol(job.Name))
+
+	pod, err := kubectl.jobPod(ctx, job.Name)
+	if err != nil {
+		return Result{}, err
+	}
+
+	if _, err := kubectl.waitFor(ctx, pod, prepareContainer, func(state containerState) bool { return state.Running != nil }); err != nil {
+		return Result{}, err
+	}
+	logger.Debugf("Copying the app to pod %s", style.Symbol(pod))
+	if _, err := kubectl.run(ctx, job.Files, "exec", "-i", pod, "-c", prepareContainer, "--", "tar", "-x", "-C", "/"); err != nil {
+		return Result{}, errors.Wrapf(err, "copying the app to pod %s", style.Symbol(pod))
+	}
+	if _, err := kubectl.run(ctx, nil, "exec", pod, "-c", prepareContainer, "--", "touch", readyFile); err != nil {
+		return Result{}, errors.Wrapf(err, "starting the creator of pod %s", style.Symbol(pod))
+	}
+
+	if _, err := kubectl.waitFor(ctx, pod, creatorContainer, func(state containerState) bool { return state.Running != nil || state.Terminated != nil }); err != nil {
+		return Result{}, err
+	}
+	if err := kubectl.stream(ctx, logging.GetWriterForLevel(logger, logging.InfoLevel), "logs", "-f", pod, "-c", creatorContainer); err != nil {
+		return Result{}, errors.Wrapf(err, "streaming the logs of pod %s", style.Symbol(pod))
+	}
+
+	state, err := kubectl.waitFor(ctx, pod, creatorContainer, func(state containerState) bool { return state.Terminated != nil })
+	if err != nil {
+		return Result{}, err
+	}
+	if state.Terminated.ExitCode != 0 {
+		return Result{}, errors.Errorf("the creator of job %s failed with exit code %d", style.Symbol(job.Name), state.Terminated.ExitCode)
+	}
+
+	var report struct {
+		Image struct {
+			Digest string `toml:"digest"`
+		} `toml:"image"`
+	}
+	if _, err := toml.Decode(state.Terminated.Message, &report); err != nil {
+		return Result{}, errors.Wrapf(err, "reading the report of job %s", style.Symbol(job.Name))
+	}
+	return Result{Digest: report.Image.Digest}, nil
+}
+
+type containerState struct {
+	Waiting *struct {
+		Reason  string `json:"reason"`
+		Message string `json:"message"`
+	} `json:"waiting"`
+	Running    *struct{} `json:"running"`
+	Terminated *struct {
+		ExitCode int    `json:"exitCode"`
+		Reason   string `json:"reason"`
+		Message  string `json:"message"`
+	} `json:"terminated"`
+}
+
+type containerStatus struct {
+	Name  string         `json:"name"`
+	State containerState `json:"state"`
+}
+
+type kubectl struct {
+	executable string
+	args       []string
+}
+
+func newKubectl(options Options) (*kubectl, error) {
+	command := options.Kubectl
+	if command == "" {
+		command = "kubectl"
+	}
+	executable, err := exec.LookPath(command)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding kubectl %s", style.Symbol(command))
+	}
+
+	k := &kubectl{executable: executable}
+	if options.Context != "" {
+		k.args = append(k.args, "--context", options.Context)
+	}
+	if options.Namespace != "" {
+		k.args = append(k.args, "--namespace", options.Namespace)
+	}
+	return k, nil
+}
+
+// run runs kubectl with args and stdin, returning its output, with its error output in the error when it fails
+func (k *kubectl) run(ctx context.Context, stdin io.Reader, args ...string) ([]byte, error) {
+	var stdout bytes.Buffer
+	if err := k.command(ctx, stdin, &stdout, args...); err != nil {
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
+
+// stream runs kubectl with args, writing its output to stdout
+func (k *kubectl) stream(ctx context.Context, stdout io.Writer, args ...string) error {
+	return k.command(ctx, nil, stdout, args...)
+}
+
+func (k *kubectl) command(ctx context.Context, stdin io.Reader, stdout io.Writer, args ...string) error {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, k.executable, append(append([]string{}, k.args...), args...)...)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return errors.Wrap(errors.New(message), "running kubectl "+args[0])
+		}
+		return errors.Wrap(err, "running kubectl "+args[0])
+	}
+	return nil
+}
+
+// jobPod is the name of the pod of a Job, once the Job created it
+func (k *kubectl) jobPod(ctx context.Context, job string) (string, error) {
+	for {
+		output, err := k.run(ctx, nil, "get", "pods", "--selector", "job-name="+job, "--output", "jsonpath={.items[*].metadata.name}")
+		if err != nil {
+			return "", errors.Wrapf(err, "finding the pod of job %s", style.Symbol(job))
+		}
+		if pods := strings.Fields(string(output)); len(pods) > 0 {
+			return pods[0], nil
+		}
+		if err := sleep(ctx); err != nil {
+			return "", err
+		}
+	}
+}
+
+// waitFor waits until the state of a container of a pod is ready, failing when the container can't start or, for the
+// prepare container, ended
+func (k *kubectl) waitFor(ctx context.Context, pod, container string, ready func(containerState) bool) (containerState, error) {
+	for {
+		output, err := k.run(ctx, nil, "get", "pod", pod, "--output", "json")
+		if err != nil {
+			return containerState{}, errors.Wrapf(err, "reading pod %s", style.Symbol(pod))
+		}
+		var status struct {
+			Status struct {
+				InitContainerStatuses []containerStatus `json:"initContainerStatuses"`
+				ContainerStatuses     []containerStatus `json:"containerStatuses"`
+			} `json:"status"`
+		}
+		if err := json.Unmarshal(output, &status); err != nil {
+			return containerState{}, errors.Wrapf(err, "reading pod %s", style.Symbol(pod))
+		}
+
+		for _, containerStatus := range append(status.Status.InitContainerStatuses, status.Status.ContainerStatuses...) {
+			if containerStatus.Name != container {
+				continue
+			}
+			state := containerStatus.State
+			if ready(state) {
+				return state, nil
+			}
+			if state.Waiting != nil && containsString(failedWaitingReasons, state.Waiting.Reason) {
+				return containerState{}, errors.Errorf("container %s of pod %s can't start: %s %s", style.Symbol(container), style.Symbol(pod), state.Waiting.Reason, state.Waiting.Message)
+			}
+			if state.Terminated != nil && container == prepareContainer {
+				return containerState{}, errors.Errorf("container %s of pod %s ended before the app was copied: %s", style.Symbol(container), style.Symbol(pod), state.Terminated.Reason)
+			}
+		}
+
+		if err := sleep(ctx); err != nil {
+			return containerState{}, err
+		}
+	}
+}
+
+func sleep(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(pollInterval):
+		return nil
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
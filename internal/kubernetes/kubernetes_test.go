@@ -0,0 +1,178 @@
+package kubernetes_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/kubernetes"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestKubernetes(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Kubernetes", testKubernetes, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+// fakeKubectl records its calls and answers them like kubectl would for a Job whose pod is already running
+const fakeKubectl = `#!/bin/sh
+dir="$(dirname "$0")"
+echo "$@" >> "$dir/calls.out"
+case "$*" in
+  *" apply -f -") cat > "$dir/manifest.json" ;;
+  *" get pods "*) echo "pack-build-abc-xyz" ;;
+  *" get pod "*) cat "$dir/pod.json" ;;
+  *" exec -i "*) cat > "$dir/files.tar" ;;
+  *" logs -f "*) echo "===> BUILDING" ;;
+esac
+`
+
+func podJSON(exitCode int, report string) string {
+	pod, _ := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{
+			"initContainerStatuses": []interface{}{
+				map[string]interface{}{"name": "prepare", "state": map[string]interface{}{"running": map[string]interface{}{}}},
+			},
+			"containerStatuses": []interface{}{
+				map[string]interface{}{"name": "creator", "state": map[string]interface{}{
+					"terminated": map[string]interface{}{"exitCode": exitCode, "message": report},
+				}},
+			},
+		},
+	})
+	return string(pod)
+}
+
+func testKubernetes(t *testing.T, when spec.G, it spec.S) {
+	var (
+		logger  logging.Logger
+		outBuf  bytes.Buffer
+		tmpDir  string
+		options kubernetes.Options
+		job     kubernetes.Job
+	)
+
+	it.Before(func() {
+		h.SkipIf(t, runtime.GOOS == "windows", "the fake kubectl is written for sh")
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf, logging.WithVerbose())
+
+		var err error
+		tmpDir, err = os.MkdirTemp("", "kubernetes")
+		h.AssertNil(t, err)
+
+		options = kubernetes.Options{Kubectl: filepath.Join(tmpDir, "kubectl"), Context: "some-cluster", Namespace: "builds"}
+		h.AssertNil(t, os.WriteFile(options.Kubectl, []byte(fakeKubectl), 0700))
+
+		job = kubernetes.Job{
+			Name:      "pack-build-abc",
+			Image:     "example.com/some/builder@sha256:0000000000000000000000000000000000000000000000000000000000000001",
+			UID:       1000,
+			GID:       1001,
+			Command:   []string{"/cnb/lifecycle/creator", "-app", "/workspace", "example.com/some/app"},
+			Env:       []string{"CNB_PLATFORM_API=0.12"},
+			SecretEnv: map[string]string{"CNB_REGISTRY_AUTH": `{"example.com":"Basic c29tZTpzZWNyZXQ="}`},
+			Files:     strings.NewReader("some-tar"),
+		}
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.RemoveAll(tmpDir))
+	})
+
+	calls := func() string {
+		contents, err := os.ReadFile(filepath.Join(tmpDir, "calls.out"))
+		h.AssertNil(t, err)
+		return string(contents)
+	}
+
+	when("#Run", func() {
+		it("runs the job, streams its logs and returns the digest of the image", func() {
+			h.AssertNil(t, os.WriteFile(filepath.Join(tmpDir, "pod.json"), []byte(podJSON(0, "[image]\n  digest = \"sha256:0000000000000000000000000000000000000000000000000000000000000002\"\n")), 0600))
+
+			result, err := kubernetes.Run(context.Background(), logger, options, job)
+			h.AssertNil(t, err)
+			h.AssertEq(t, result.Digest, "sha256:0000000000000000000000000000000000000000000000000000000000000002")
+			h.AssertContains(t, outBuf.String(), "===> BUILDING")
+
+			files, err := os.ReadFile(filepath.Join(tmpDir, "files.tar"))
+			h.AssertNil(t, err)
+			h.AssertEq(t, string(files), "some-tar")
+
+			h.AssertContains(t, calls(), "--context some-cluster --namespace builds apply -f -")
+			h.AssertContains(t, calls(), "exec pack-build-abc-xyz -c prepare -- touch /workspace/.pack-ready")
+			h.AssertContains(t, calls(), "logs -f pack-build-abc-xyz -c creator")
+			h.AssertContains(t, calls(), "delete job/pack-build-abc secret/pack-build-abc --ignore-not-found")
+		})
+
+		it("creates the job with its secret", func() {
+			h.AssertNil(t, os.WriteFile(filepath.Join(tmpDir, "pod.json"), []byte(podJSON(0, "")), 0600))
+
+			_, err := kubernetes.Run(context.Background(), logger, options, job)
+			h.AssertNil(t, err)
+
+			manifest, err := os.ReadFile(filepath.Join(tmpDir, "manifest.json"))
+			h.AssertNil(t, err)
+			var list struct {
+				Items []struct {
+					Kind       string            `json:"kind"`
+					StringData map[string]string `json:"stringData"`
+					Spec       struct {
+						Template struct {
+							Spec struct {
+								Containers []struct {
+									Image   string   `json:"image"`
+									Command []string `json:"command"`
+									Env     []struct {
+										Name  string `json:"name"`
+										Value string `json:"value"`
+									} `json:"env"`
+									TerminationMessagePath string `json:"terminationMessagePath"`
+								} `json:"containers"`
+							} `json:"spec"`
+						} `json:"template"`
+					} `json:"spec"`
+				} `json:"items"`
+			}
+			h.AssertNil(t, json.Unmarshal(manifest, &list))
+
+			h.AssertEq(t, len(list.Items), 2)
+			h.AssertEq(t, list.Items[0].Kind, "Secret")
+			h.AssertEq(t, list.Items[0].StringData["CNB_REGISTRY_AUTH"], `{"example.com":"Basic c29tZTpzZWNyZXQ="}`)
+			h.AssertEq(t, list.Items[1].Kind, "Job")
+
+			creator := list.Items[1].Spec.Template.Spec.Containers[0]
+			h.AssertEq(t, creator.Image, job.Image)
+			h.AssertEq(t, creator.Command, job.Command)
+			h.AssertEq(t, creator.Env[0].Name, "CNB_PLATFORM_API")
+			h.AssertEq(t, creator.Env[0].Value, "0.12")
+			h.AssertEq(t, creator.TerminationMessagePath, "/layers/report.toml")
+			h.AssertNotContains(t, string(manifest[:strings.Index(string(manifest), `"kind":"Job"`)]), "CNB_REGISTRY_AUTH\",\"value")
+		})
+
+		it("errors when the creator fails, deleting the job", func() {
+			h.AssertNil(t, os.WriteFile(filepath.Join(tmpDir, "pod.json"), []byte(podJSON(51, "")), 0600))
+
+			_, err := kubernetes.Run(context.Background(), logger, options, job)
+			h.AssertError(t, err, "the creator of job 'pack-build-abc' failed with exit code 51")
+			h.AssertContains(t, calls(), "delete job/pack-build-abc")
+		})
+
+		it("errors when kubectl can't be found", func() {
+			options.Kubectl = filepath.Join(tmpDir, "missing-kubectl")
+
+			_, err := kubernetes.Run(context.Background(), logger, options, job)
+			h.AssertError(t, err, "finding kubectl")
+		})
+	})
+}
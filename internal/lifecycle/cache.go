@@ -0,0 +1,257 @@
+// Package lifecycle caches the archives of the released versions of the lifecycle under PACK_HOME. Archives are
+// stored by digest, once verified against the checksum published along with them, and the digest of each archive is
+// pinned, so that cached archives are used without reaching the mirror and are verified again on every use.
+package lifecycle
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/mitchellh/ioprogress"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/blob"
+)
+
+// DefaultMirror is where the lifecycle releases its archives, each at <mirror>/v<version>/<archive>, along with their
+// checksums at <mirror>/v<version>/<archive>.sha256
+const DefaultMirror = "https://github.com/buildpacks/lifecycle/releases/download"
+
+// CacheOption configures a Cache
+type CacheOption func(c *Cache)
+
+// WithHTTPClient sets the client used to reach the mirror
+func WithHTTPClient(client *http.Client) CacheOption {
+	return func(c *Cache) {
+		c.client = client
+	}
+}
+
+// Cache downloads the archives of the lifecycle, keeping them in a directory. It's a blob.Downloader of the URLs of
+// the archives.
+type Cache struct {
+	dir    string
+	logger blob.Logger
+	client *http.Client
+
+	// locks holds a mutex per archive, so that concurrent builders download it once
+	locks sync.Map
+}
+
+// NewCache creates a Cache keeping the archives in dir
+func NewCache(logger blob.Logger, dir string, ops ...CacheOption) *Cache {
+	c := &Cache{
+		dir:    dir,
+		logger: logger,
+		client: http.DefaultClient,
+	}
+	for _, op := range ops {
+		op(c)
+	}
+	return c
+}
+
+// ArchiveURL is the URL of the archive of a version of the lifecycle at mirror, or at DefaultMirror when mirror is
+// empty. arch is the architecture of the archive, such as x86-64 or arm64.
+func ArchiveURL(mirror, version, os, arch string) string {
+	if mirror == "" {
+		mirror = DefaultMirror
+	}
+	return fmt.Sprintf("%s/v%s/lifecycle-v%s+%s.%s.tgz", strings.TrimSuffix(mirror, "/"), version, version, os, arch)
+}
+
+// Download returns the archive at uri. A cached archive is used when it still has its pinned digest, otherwise the
+// archive is downloaded and verified against the checksum published at <uri>.sha256.
+func (c *Cache) Download(ctx context.Context, uri string) (blob.Blob, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing uri %s", style.Symbol(uri))
+	}
+	// archives are pinned by name, which holds their version, OS and architecture, so that mirrors share them
+	name := path.Base(parsed.Path)
+	if name == "." || name == "/" {
+		return nil, errors.Errorf("uri %s has no archive name", style.Symbol(uri))
+	}
+
+	lock, _ := c.locks.LoadOrStore(name, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	pinPath := filepath.Join(c.dir, "pins", name)
+	if pinned, err := os.ReadFile(filepath.Clean(pinPath)); err == nil {
+		digest := string(pinned)
+		err := c.verify(digest)
+		if err == nil {
+			c.logger.Debugf("Using cached lifecycle %s", style.Symbol(name))
+			return blob.NewBlob(c.blobPath(digest)), nil
+		}
+		if !os.IsNotExist(errors.Cause(err)) {
+			c.logger.Infof("Downloading lifecycle %s again, as the cached one is invalid: %s", style.Symbol(name), err)
+		}
+	}
+
+	digest, err := c.checksum(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.verify(digest); err != nil {
+		if err := c.download(ctx, uri, digest); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writeFile(pinPath, []byte(digest)); err != nil {
+		return nil, errors.Wrapf(err, "pinning lifecycle %s", style.Symbol(name))
+	}
+	return blob.NewBlob(c.blobPath(digest)), nil
+}
+
+// checksum is the digest of the archive at uri, as published at <uri>.sha256 in the format of sha256sum
+func (c *Cache) checksum(ctx context.Context, uri string) (string, error) {
+	body, err := c.get(ctx, uri+".sha256")
+	if err != nil {
+		return "", errors.Wrapf(err, "downloading checksum of %s", style.Symbol(uri))
+	}
+	defer body.Close()
+
+	contents, err := io.ReadAll(io.LimitReader(body, 4096))
+	if err != nil {
+		return "", errors.Wrapf(err, "downloading checksum of %s", style.Symbol(uri))
+	}
+	fields := strings.Fields(string(contents))
+	if len(fields) == 0 || !isDigest(strings.ToLower(fields[0])) {
+		return "", errors.Errorf("invalid checksum of %s, must be a SHA-256 digest", style.Symbol(uri))
+	}
+	return strings.ToLower(fields[0]), nil
+}
+
+// download writes the archive at uri to the blob of digest, failing when the archive has another digest
+func (c *Cache) download(ctx context.Context, uri, digest string) error {
+	body, err := c.get(ctx, uri)
+	if err != nil {
+		return errors.Wrap(err, "downloading lifecycle")
+	}
+	defer body.Close()
+
+	blobPath := c.blobPath(digest)
+	if err := os.MkdirAll(filepath.Dir(blobPath), 0750); err != nil {
+		return err
+	}
+	// write to a temporary file first so that other processes never read a partially downloaded archive
+	fh, err := os.CreateTemp(filepath.Dir(blobPath), digest+".*.tmp")
+	if err != nil {
+		return errors.Wrap(err, "writing lifecycle cache")
+	}
+	defer os.Remove(fh.Name())
+
+	hasher := sha256.New()
+	_, err = io.Copy(io.MultiWriter(fh, hasher), body)
+	if closeErr := fh.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.Wrap(err, "downloading lifecycle")
+	}
+	if actual := hex.EncodeToString(hasher.Sum(nil)); actual != digest {
+		return errors.Errorf("lifecycle %s doesn't match its published checksum, expected %s but got %s", style.Symbol(uri), digest, actual)
+	}
+	return errors.Wrap(os.Rename(fh.Name(), blobPath), "writing lifecycle cache")
+}
+
+// verify fails when the blob of digest is missing or has another digest
+func (c *Cache) verify(digest string) error {
+	if !isDigest(digest) {
+		return errors.Errorf("invalid digest %s", style.Symbol(digest))
+	}
+	fh, err := os.Open(c.blobPath(digest))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer fh.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, fh); err != nil {
+		return err
+	}
+	if actual := hex.EncodeToString(hasher.Sum(nil)); actual != digest {
+		return errors.Errorf("expected digest %s but got %s", digest, actual)
+	}
+	return nil
+}
+
+func (c *Cache) get(ctx context.Context, uri string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req) //nolint:bodyclose
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		resp.Body.Close()
+		return nil, errors.Errorf("could not download from %s, code http status %s", style.Symbol(uri), style.SymbolF("%d", resp.StatusCode))
+	}
+	if strings.HasSuffix(uri, ".sha256") {
+		return resp.Body, nil
+	}
+
+	c.logger.Infof("Downloading from %s", style.Symbol(uri))
+	return &progressReader{
+		Closer: resp.Body,
+		Reader: &ioprogress.Reader{
+			Reader:   resp.Body,
+			Size:     resp.ContentLength,
+			DrawFunc: ioprogress.DrawTerminalf(c.logger.Writer(), ioprogress.DrawTextFormatBytes),
+		},
+	}, nil
+}
+
+func (c *Cache) blobPath(digest string) string {
+	return filepath.Join(c.dir, "blobs", "sha256", digest)
+}
+
+type progressReader struct {
+	*ioprogress.Reader
+	io.Closer
+}
+
+// writeFile writes a file through a temporary file, so that other processes never read it partially written
+func writeFile(path string, contents []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	fh, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(fh.Name())
+
+	_, err = fh.Write(contents)
+	if closeErr := fh.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(fh.Name(), path)
+}
+
+func isDigest(digest string) bool {
+	if len(digest) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(digest)
+	return err == nil && digest == strings.ToLower(digest)
+}
@@ -0,0 +1,149 @@
+package lifecycle_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/lifecycle"
+	"github.com/buildpacks/pack/pkg/blob"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestCache(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Cache", testCache, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testCache(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject  *lifecycle.Cache
+		server   *httptest.Server
+		cacheDir string
+		outBuf   bytes.Buffer
+		archive  []byte
+		checksum string
+		requests int32
+		uri      string
+	)
+
+	it.Before(func() {
+		var err error
+		cacheDir, err = os.MkdirTemp("", "lifecycle-cache")
+		h.AssertNil(t, err)
+
+		archive = []byte("some-lifecycle-archive")
+		digest := sha256.Sum256(archive)
+		checksum = hex.EncodeToString(digest[:]) + "  lifecycle-v0.20.0+linux.x86-64.tgz\n"
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			switch r.URL.Path {
+			case "/v0.20.0/lifecycle-v0.20.0+linux.x86-64.tgz":
+				w.Write(archive)
+			case "/v0.20.0/lifecycle-v0.20.0+linux.x86-64.tgz.sha256":
+				w.Write([]byte(checksum))
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		uri = lifecycle.ArchiveURL(server.URL, "0.20.0", "linux", "x86-64")
+		subject = lifecycle.NewCache(logging.NewLogWithWriters(&outBuf, &outBuf), cacheDir)
+	})
+
+	it.After(func() {
+		server.Close()
+		h.AssertNil(t, os.RemoveAll(cacheDir))
+	})
+
+	readBlob := func(b blob.Blob) string {
+		t.Helper()
+		rc, err := b.Open()
+		h.AssertNil(t, err)
+		defer rc.Close()
+		contents, err := io.ReadAll(rc)
+		h.AssertNil(t, err)
+		return string(contents)
+	}
+
+	when("#ArchiveURL", func() {
+		it("is the archive of the release on GitHub by default", func() {
+			h.AssertEq(t, lifecycle.ArchiveURL("", "0.20.0", "linux", "arm64"), "https://github.com/buildpacks/lifecycle/releases/download/v0.20.0/lifecycle-v0.20.0+linux.arm64.tgz")
+		})
+
+		it("is the archive of the mirror", func() {
+			h.AssertEq(t, lifecycle.ArchiveURL("https://artifacts.example.com/lifecycle/", "0.20.0", "windows", "x86-64"), "https://artifacts.example.com/lifecycle/v0.20.0/lifecycle-v0.20.0+windows.x86-64.tgz")
+		})
+	})
+
+	when("#Download", func() {
+		it("downloads the archive once, verified against its checksum", func() {
+			b, err := subject.Download(context.TODO(), uri)
+			h.AssertNil(t, err)
+			h.AssertEq(t, readBlob(b), string(archive))
+			h.AssertEq(t, atomic.LoadInt32(&requests), int32(2))
+
+			b, err = subject.Download(context.TODO(), uri)
+			h.AssertNil(t, err)
+			h.AssertEq(t, readBlob(b), string(archive))
+			h.AssertEq(t, atomic.LoadInt32(&requests), int32(2))
+		})
+
+		it("uses the archive cached from another mirror", func() {
+			_, err := subject.Download(context.TODO(), uri)
+			h.AssertNil(t, err)
+
+			b, err := subject.Download(context.TODO(), lifecycle.ArchiveURL("https://unreachable.example.com", "0.20.0", "linux", "x86-64"))
+			h.AssertNil(t, err)
+			h.AssertEq(t, readBlob(b), string(archive))
+		})
+
+		it("downloads the archive again when the cached one was altered", func() {
+			_, err := subject.Download(context.TODO(), uri)
+			h.AssertNil(t, err)
+			digest := sha256.Sum256(archive)
+			h.AssertNil(t, os.WriteFile(filepath.Join(cacheDir, "blobs", "sha256", hex.EncodeToString(digest[:])), []byte("altered"), 0600))
+
+			b, err := subject.Download(context.TODO(), uri)
+			h.AssertNil(t, err)
+			h.AssertEq(t, readBlob(b), string(archive))
+			h.AssertEq(t, atomic.LoadInt32(&requests), int32(4))
+			h.AssertContains(t, outBuf.String(), "as the cached one is invalid")
+		})
+
+		it("fails when the archive doesn't match its checksum", func() {
+			digest := sha256.Sum256([]byte("another-archive"))
+			checksum = hex.EncodeToString(digest[:])
+
+			_, err := subject.Download(context.TODO(), uri)
+			h.AssertError(t, err, "doesn't match its published checksum")
+			h.AssertPathDoesNotExists(t, filepath.Join(cacheDir, "pins", "lifecycle-v0.20.0+linux.x86-64.tgz"))
+		})
+
+		it("fails when the checksum isn't published", func() {
+			_, err := subject.Download(context.TODO(), lifecycle.ArchiveURL(server.URL, "0.21.0", "linux", "x86-64"))
+			h.AssertError(t, err, "downloading checksum of")
+		})
+
+		it("fails for an invalid checksum", func() {
+			checksum = "not-a-digest"
+
+			_, err := subject.Download(context.TODO(), uri)
+			h.AssertError(t, err, "must be a SHA-256 digest")
+		})
+	})
+}
@@ -0,0 +1,163 @@
+// Package logfile writes command output to timestamped files with size-based rotation.
+package logfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultMaxSize is the size after which output continues in a new file
+	DefaultMaxSize = 10 * 1024 * 1024
+	// DefaultMaxFiles is the number of log files kept in the directory, older files are removed
+	DefaultMaxFiles = 50
+
+	filePrefix = "pack-"
+	fileSuffix = ".log"
+)
+
+// File is an io.WriteCloser that writes to files named after the time it was opened,
+// continuing in a new file whenever the current one would exceed the maximum size.
+type File struct {
+	mu       sync.Mutex
+	dir      string
+	base     string
+	maxSize  int64
+	maxFiles int
+
+	file  *os.File
+	size  int64
+	index int
+}
+
+// Option configures a File
+type Option func(*File)
+
+// WithMaxSize sets the size after which output continues in a new file
+func WithMaxSize(size int64) Option {
+	return func(f *File) {
+		f.maxSize = size
+	}
+}
+
+// WithMaxFiles sets the number of log files kept in the directory
+func WithMaxFiles(count int) Option {
+	return func(f *File) {
+		f.maxFiles = count
+	}
+}
+
+// Open creates dir if needed and opens a new log file in it, named after now
+func Open(dir string, now time.Time, ops ...Option) (*File, error) {
+	f := &File{
+		dir:      dir,
+		base:     filePrefix + now.UTC().Format("20060102T150405") + fmt.Sprintf("%03d", now.Nanosecond()/int(time.Millisecond)),
+		maxSize:  DefaultMaxSize,
+		maxFiles: DefaultMaxFiles,
+	}
+
+	for _, op := range ops {
+		op(f)
+	}
+
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, errors.Wrapf(err, "creating log directory %s", dir)
+	}
+
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Name returns the path of the file currently written to
+func (f *File) Name() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.file.Name()
+}
+
+func (f *File) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err := f.file.Close(); err != nil {
+			return 0, err
+		}
+		f.index++
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+func (f *File) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.file.Close()
+}
+
+func (f *File) open() error {
+	name := f.base + fileSuffix
+	if f.index > 0 {
+		name = fmt.Sprintf("%s.%d%s", f.base, f.index, fileSuffix)
+	}
+
+	file, err := os.OpenFile(filepath.Join(f.dir, name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "opening log file %s", name)
+	}
+
+	f.file = file
+	f.size = 0
+	return f.prune()
+}
+
+// prune removes the oldest log files so that at most maxFiles are kept
+func (f *File) prune() error {
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return errors.Wrapf(err, "reading log directory %s", f.dir)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasPrefix(entry.Name(), filePrefix) && strings.HasSuffix(entry.Name(), fileSuffix) {
+			names = append(names, entry.Name())
+		}
+	}
+
+	// names start with a sortable timestamp, rotated files sort after the first file of a run
+	sort.Slice(names, func(i, j int) bool {
+		return rotationKey(names[i]) < rotationKey(names[j])
+	})
+
+	for len(names) > f.maxFiles {
+		if err := os.Remove(filepath.Join(f.dir, names[0])); err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "removing old log file %s", names[0])
+		}
+		names = names[1:]
+	}
+	return nil
+}
+
+// rotationKey orders pack-<time>.log before pack-<time>.1.log, pack-<time>.2.log, ... pack-<time>.10.log
+func rotationKey(name string) string {
+	trimmed := strings.TrimSuffix(strings.TrimPrefix(name, filePrefix), fileSuffix)
+	timestamp, index, _ := strings.Cut(trimmed, ".")
+	return fmt.Sprintf("%s.%08s", timestamp, index)
+}
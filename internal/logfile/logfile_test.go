@@ -0,0 +1,113 @@
+package logfile_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/logfile"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestLogfile(t *testing.T) {
+	spec.Run(t, "Logfile", testLogfile, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testLogfile(t *testing.T, when spec.G, it spec.S) {
+	var (
+		tmpDir string
+		logDir string
+		now    = time.Date(2024, 1, 2, 3, 4, 5, 6000000, time.UTC)
+	)
+
+	it.Before(func() {
+		var err error
+		tmpDir, err = os.MkdirTemp("", "logfile")
+		h.AssertNil(t, err)
+		logDir = filepath.Join(tmpDir, "logs")
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.RemoveAll(tmpDir))
+	})
+
+	listLogs := func() []string {
+		entries, err := os.ReadDir(logDir)
+		h.AssertNil(t, err)
+
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		return names
+	}
+
+	it("writes to a file named after the time it was opened", func() {
+		f, err := logfile.Open(logDir, now)
+		h.AssertNil(t, err)
+
+		_, err = f.Write([]byte("some output\n"))
+		h.AssertNil(t, err)
+		h.AssertNil(t, f.Close())
+
+		h.AssertEq(t, f.Name(), filepath.Join(logDir, "pack-20240102T030405006.log"))
+		contents, err := os.ReadFile(f.Name())
+		h.AssertNil(t, err)
+		h.AssertEq(t, string(contents), "some output\n")
+	})
+
+	it("continues in a new file when the maximum size would be exceeded", func() {
+		f, err := logfile.Open(logDir, now, logfile.WithMaxSize(10))
+		h.AssertNil(t, err)
+
+		for _, line := range []string{"line-1\n", "line-2\n", "line-3\n"} {
+			_, err = f.Write([]byte(line))
+			h.AssertNil(t, err)
+		}
+		h.AssertNil(t, f.Close())
+
+		h.AssertEq(t, listLogs(), []string{
+			"pack-20240102T030405006.1.log",
+			"pack-20240102T030405006.2.log",
+			"pack-20240102T030405006.log",
+		})
+		contents, err := os.ReadFile(filepath.Join(logDir, "pack-20240102T030405006.2.log"))
+		h.AssertNil(t, err)
+		h.AssertEq(t, string(contents), "line-3\n")
+	})
+
+	it("removes the oldest files beyond the maximum number of files", func() {
+		for i := 0; i < 3; i++ {
+			f, err := logfile.Open(logDir, now.Add(time.Duration(i)*time.Hour), logfile.WithMaxFiles(2))
+			h.AssertNil(t, err)
+			_, err = fmt.Fprintf(f, "run %d\n", i)
+			h.AssertNil(t, err)
+			h.AssertNil(t, f.Close())
+		}
+
+		h.AssertEq(t, listLogs(), []string{
+			"pack-20240102T040405006.log",
+			"pack-20240102T050405006.log",
+		})
+	})
+
+	it("orders rotated files after the first file of a run when pruning", func() {
+		f, err := logfile.Open(logDir, now, logfile.WithMaxSize(1), logfile.WithMaxFiles(2))
+		h.AssertNil(t, err)
+		for _, line := range []string{"a", "b", "c"} {
+			_, err = f.Write([]byte(line))
+			h.AssertNil(t, err)
+		}
+		h.AssertNil(t, f.Close())
+
+		h.AssertEq(t, listLogs(), []string{
+			"pack-20240102T030405006.1.log",
+			"pack-20240102T030405006.2.log",
+		})
+	})
+}
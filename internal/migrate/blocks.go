@@ -0,0 +1,81 @@
+package migrate
+
+import (
+	"strings"
+)
+
+// block is a table of a TOML document along with the lines up to the next table. The block before the first table
+// has an empty name.
+type block struct {
+	name  string
+	array bool
+	lines []string
+}
+
+func (b block) text() string {
+	return strings.Join(b.lines, "\n")
+}
+
+// isTable reports whether the block is the table name, or one of its sub-tables
+func (b block) isTable(name string) bool {
+	return b.name == name || strings.HasPrefix(b.name, name+".")
+}
+
+// splitBlocks splits a TOML document in blocks, keeping all its lines, comments included. Comments right above a
+// table are part of its block.
+func splitBlocks(contents string) []block {
+	blocks := []block{{}}
+	for _, line := range strings.Split(strings.TrimRight(contents, "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if name, array, ok := tableHeader(trimmed); ok {
+			previous := &blocks[len(blocks)-1]
+			start := len(previous.lines)
+			for start > 0 && strings.HasPrefix(strings.TrimSpace(previous.lines[start-1]), "#") {
+				start--
+			}
+			comments := append([]string{}, previous.lines[start:]...)
+			previous.lines = previous.lines[:start]
+			blocks = append(blocks, block{name: name, array: array, lines: comments})
+		}
+		blocks[len(blocks)-1].lines = append(blocks[len(blocks)-1].lines, line)
+	}
+	return blocks
+}
+
+func tableHeader(line string) (name string, array bool, ok bool) {
+	if i := strings.Index(line, "#"); i >= 0 {
+		line = strings.TrimSpace(line[:i])
+	}
+	switch {
+	case strings.HasPrefix(line, "[[") && strings.HasSuffix(line, "]]"):
+		return normalizeName(line[2 : len(line)-2]), true, true
+	case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+		return normalizeName(line[1 : len(line)-1]), false, true
+	}
+	return "", false, false
+}
+
+func normalizeName(name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = strings.Trim(strings.TrimSpace(part), `"'`)
+	}
+	return strings.Join(parts, ".")
+}
+
+// joinBlocks joins blocks back into a document, followed by the tables appended after a blank line
+func joinBlocks(blocks []block, appended string) string {
+	var lines []string
+	for _, b := range blocks {
+		lines = append(lines, b.lines...)
+	}
+
+	contents := strings.TrimRight(strings.Join(lines, "\n"), "\n\t ")
+	if appended != "" {
+		if contents != "" {
+			contents += "\n\n"
+		}
+		contents += strings.TrimRight(appended, "\n")
+	}
+	return contents + "\n"
+}
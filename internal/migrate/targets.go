@@ -0,0 +1,364 @@
+// Package migrate rewrites configuration files declaring deprecated features to their replacement.
+package migrate
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/Masterminds/semver"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/builder"
+	"github.com/buildpacks/pack/buildpackage"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/dist"
+)
+
+// Kind is the kind of a configuration file
+type Kind string
+
+const (
+	KindBuilder   Kind = "builder"
+	KindBuildpack Kind = "buildpack"
+	KindPackage   Kind = "package"
+)
+
+const (
+	// minTargetsBuildpackAPI is the first Buildpack API declaring targets in buildpack.toml
+	minTargetsBuildpackAPI = "0.10"
+
+	// minTargetsLifecycleVersion is the first lifecycle version implementing Platform API 0.12, which replaces stacks
+	// with targets
+	minTargetsLifecycleVersion = "0.17.0"
+)
+
+// Result is the outcome of migrating a configuration file to targets
+type Result struct {
+	Kind     Kind
+	Contents string
+
+	// Changes made to the file
+	Changes []string
+
+	// Attention lists what couldn't be migrated and needs to be checked manually
+	Attention []string
+}
+
+// Changed reports whether the contents of the file were changed
+func (r Result) Changed() bool {
+	return len(r.Changes) > 0
+}
+
+type distro struct {
+	name    string
+	version string
+}
+
+// stackDistros are the distributions of well known stacks
+var stackDistros = map[string]distro{
+	"io.buildpacks.stacks.bionic":       {"ubuntu", "18.04"},
+	"io.paketo.stacks.tiny":             {"ubuntu", "18.04"},
+	"io.buildpacks.stacks.focal":        {"ubuntu", "20.04"},
+	"io.buildpacks.stacks.jammy":        {"ubuntu", "22.04"},
+	"io.buildpacks.stacks.jammy.tiny":   {"ubuntu", "22.04"},
+	"io.buildpacks.stacks.jammy.static": {"ubuntu", "22.04"},
+	"io.buildpacks.stacks.noble":        {"ubuntu", "24.04"},
+	"heroku-20":                         {"ubuntu", "20.04"},
+	"heroku-22":                         {"ubuntu", "22.04"},
+	"heroku-24":                         {"ubuntu", "24.04"},
+}
+
+// Targets migrates the contents of a builder.toml, buildpack.toml or package.toml from stack declarations to targets.
+// The kind of file is detected from its name, or its contents if the name isn't a standard one. Comments and the
+// layout of the tables that aren't migrated are kept.
+func Targets(name, contents string) (Result, error) {
+	kind, err := detectKind(name, contents)
+	if err != nil {
+		return Result{}, err
+	}
+
+	switch kind {
+	case KindBuilder:
+		return migrateBuilder(contents)
+	case KindBuildpack:
+		return migrateBuildpack(contents)
+	default:
+		return migratePackage(contents)
+	}
+}
+
+func detectKind(name, contents string) (Kind, error) {
+	switch filepath.Base(name) {
+	case "builder.toml":
+		return KindBuilder, nil
+	case "buildpack.toml":
+		return KindBuildpack, nil
+	case "package.toml":
+		return KindPackage, nil
+	}
+
+	var tables map[string]interface{}
+	md, err := toml.Decode(contents, &tables)
+	if err != nil {
+		return "", errors.Wrapf(err, "decoding %s", style.Symbol(name))
+	}
+	switch {
+	case md.IsDefined("api") && md.IsDefined("buildpack", "id"):
+		return KindBuildpack, nil
+	case md.IsDefined("platform"), md.IsDefined("dependencies"), md.IsDefined("buildpack", "uri"), md.IsDefined("extension", "uri"):
+		return KindPackage, nil
+	case md.IsDefined("stack"), md.IsDefined("order"), md.IsDefined("lifecycle"), md.IsDefined("run"), md.IsDefined("build"):
+		return KindBuilder, nil
+	}
+	return "", errors.Errorf("%s isn't a builder, buildpack or package configuration", style.Symbol(name))
+}
+
+func migrateBuilder(contents string) (Result, error) {
+	var cfg builder.Config
+	if _, err := toml.Decode(contents, &cfg); err != nil {
+		return Result{}, errors.Wrap(err, "decoding builder configuration")
+	}
+
+	result := Result{Kind: KindBuilder, Contents: contents}
+	checkLifecycle(cfg.Lifecycle, &result)
+
+	stack := cfg.Stack
+	if stack.ID == "" && stack.BuildImage == "" && stack.RunImage == "" && len(stack.RunImageMirrors) == 0 {
+		return result, nil
+	}
+
+	blocks := splitBlocks(contents)
+	if !hasTable(blocks, "stack") {
+		result.Attention = append(result.Attention, "[stack] isn't declared as a table and must be migrated manually")
+		return result, nil
+	}
+
+	var appended strings.Builder
+	switch {
+	case cfg.Build.Image == "" && stack.BuildImage != "":
+		if i := tableIndex(blocks, "build"); i >= 0 {
+			blocks[i].lines = insertAfterHeader(blocks[i].lines, "image = "+strconv.Quote(stack.BuildImage))
+		} else {
+			fmt.Fprintf(&appended, "[build]\nimage = %s\n\n", strconv.Quote(stack.BuildImage))
+		}
+		result.Changes = append(result.Changes, "moved stack.build-image to build.image")
+	case stack.BuildImage != "" && stack.BuildImage != cfg.Build.Image:
+		result.Attention = append(result.Attention, fmt.Sprintf("stack.build-image %s was dropped in favor of build.image %s",
+			style.Symbol(stack.BuildImage), style.Symbol(cfg.Build.Image)))
+	}
+
+	switch {
+	case len(cfg.Run.Images) == 0 && stack.RunImage != "":
+		fmt.Fprintf(&appended, "[[run.images]]\nimage = %s\n", strconv.Quote(stack.RunImage))
+		if len(stack.RunImageMirrors) > 0 {
+			fmt.Fprintf(&appended, "mirrors = %s\n", quoteList(stack.RunImageMirrors))
+		}
+		appended.WriteString("\n")
+		result.Changes = append(result.Changes, "moved stack.run-image and stack.run-image-mirrors to [[run.images]]")
+	case stack.RunImage != "" && stack.RunImage != cfg.Run.Images[0].Image:
+		result.Attention = append(result.Attention, fmt.Sprintf("stack.run-image %s was dropped in favor of run.images %s",
+			style.Symbol(stack.RunImage), style.Symbol(cfg.Run.Images[0].Image)))
+	}
+
+	blocks = removeTables(blocks, "stack")
+	result.Changes = append(result.Changes, "removed [stack]")
+	if stack.ID != "" && stack.ID != "*" {
+		result.Attention = append(result.Attention, fmt.Sprintf("the build and run images must declare their target with "+
+			"io.buildpacks.base.distro.name and io.buildpacks.base.distro.version labels, rather than only io.buildpacks.stack.id %s",
+			style.Symbol(stack.ID)))
+	}
+
+	result.Contents = joinBlocks(blocks, appended.String())
+	var migrated builder.Config
+	if _, err := toml.Decode(result.Contents, &migrated); err != nil {
+		return Result{}, errors.Wrap(err, "migrated builder configuration is invalid")
+	}
+	return result, nil
+}
+
+func checkLifecycle(lifecycle builder.LifecycleConfig, result *Result) {
+	switch {
+	case lifecycle.Version != "":
+		version, err := semver.NewVersion(lifecycle.Version)
+		if err != nil {
+			result.Attention = append(result.Attention, fmt.Sprintf("lifecycle version %s isn't a valid semantic version", style.Symbol(lifecycle.Version)))
+			return
+		}
+		if version.LessThan(semver.MustParse(minTargetsLifecycleVersion)) {
+			result.Attention = append(result.Attention, fmt.Sprintf("lifecycle %s doesn't support targets, use lifecycle %s or later (Platform API 0.12)",
+				style.Symbol(lifecycle.Version), minTargetsLifecycleVersion))
+		}
+	case lifecycle.URI != "":
+		result.Attention = append(result.Attention, fmt.Sprintf("check that the lifecycle at %s is version %s or later (Platform API 0.12), which supports targets",
+			style.Symbol(lifecycle.URI), minTargetsLifecycleVersion))
+	}
+}
+
+func migrateBuildpack(contents string) (Result, error) {
+	var descriptor dist.BuildpackDescriptor
+	if _, err := toml.Decode(contents, &descriptor); err != nil {
+		return Result{}, errors.Wrap(err, "decoding buildpack descriptor")
+	}
+
+	result := Result{Kind: KindBuildpack, Contents: contents}
+	if descriptor.WithAPI != nil && descriptor.WithAPI.LessThan(minTargetsBuildpackAPI) {
+		result.Attention = append(result.Attention, fmt.Sprintf("Buildpack API %s doesn't support targets, update api to %s or later "+
+			"once the buildpack handles the changes of the Buildpack APIs in between", descriptor.WithAPI.String(), minTargetsBuildpackAPI))
+	}
+	if len(descriptor.WithStacks) == 0 {
+		return result, nil
+	}
+
+	blocks := splitBlocks(contents)
+	var (
+		kept       []block
+		distros    []distro
+		anyDistro  bool
+		removedIDs []string
+	)
+	for _, b := range blocks {
+		if !b.isTable("stacks") {
+			kept = append(kept, b)
+			continue
+		}
+
+		var parsed struct {
+			Stacks []dist.Stack `toml:"stacks"`
+		}
+		if _, err := toml.Decode(b.text(), &parsed); err != nil || len(parsed.Stacks) != 1 {
+			kept = append(kept, b)
+			continue
+		}
+		stack := parsed.Stacks[0]
+
+		d, known := stackDistros[stack.ID]
+		switch {
+		case stack.ID == "*":
+			anyDistro = true
+		case known:
+			if !containsDistro(distros, d) {
+				distros = append(distros, d)
+			}
+		case len(descriptor.WithTargets) == 0:
+			kept = append(kept, b)
+			result.Attention = append(result.Attention, fmt.Sprintf("stack %s has no known target, declare its os, arch and distros in [[targets]] and remove it",
+				style.Symbol(stack.ID)))
+			continue
+		}
+
+		if len(stack.Mixins) > 0 {
+			result.Attention = append(result.Attention, fmt.Sprintf("mixins of stack %s have no equivalent in targets (%s), check for them in bin/detect or bin/build instead",
+				style.Symbol(stack.ID), strings.Join(stack.Mixins, ", ")))
+		}
+		removedIDs = append(removedIDs, stack.ID)
+	}
+
+	if len(removedIDs) == 0 {
+		return result, nil
+	}
+	result.Changes = append(result.Changes, fmt.Sprintf("removed [[stacks]] %s", strings.Join(removedIDs, ", ")))
+
+	var appended strings.Builder
+	switch {
+	case len(descriptor.WithTargets) > 0:
+		result.Changes = append(result.Changes, "kept the [[targets]] already declared")
+	case anyDistro:
+		appended.WriteString("[[targets]]\nos = \"linux\"\n")
+		result.Changes = append(result.Changes, "added a linux target for any distribution")
+	case len(distros) > 0:
+		appended.WriteString("[[targets]]\nos = \"linux\"\n")
+		var names []string
+		for _, d := range distros {
+			fmt.Fprintf(&appended, "\n[[targets.distros]]\nname = %s\nversion = %s\n", strconv.Quote(d.name), strconv.Quote(d.version))
+			names = append(names, d.name+" "+d.version)
+		}
+		result.Changes = append(result.Changes, fmt.Sprintf("added a linux target for %s", strings.Join(names, ", ")))
+	}
+
+	result.Contents = joinBlocks(kept, appended.String())
+	var migrated dist.BuildpackDescriptor
+	if _, err := toml.Decode(result.Contents, &migrated); err != nil {
+		return Result{}, errors.Wrap(err, "migrated buildpack descriptor is invalid")
+	}
+	return result, nil
+}
+
+func migratePackage(contents string) (Result, error) {
+	var cfg buildpackage.Config
+	if _, err := toml.Decode(contents, &cfg); err != nil {
+		return Result{}, errors.Wrap(err, "decoding package configuration")
+	}
+
+	result := Result{Kind: KindPackage, Contents: contents}
+	if cfg.Platform.OS == "" {
+		return result, nil
+	}
+
+	blocks := splitBlocks(contents)
+	if !hasTable(blocks, "platform") {
+		result.Attention = append(result.Attention, "[platform] isn't declared as a table and must be migrated manually")
+		return result, nil
+	}
+
+	var appended string
+	if len(cfg.Targets) == 0 {
+		appended = fmt.Sprintf("[[targets]]\nos = %s\n", strconv.Quote(cfg.Platform.OS))
+		result.Changes = append(result.Changes, fmt.Sprintf("replaced [platform] with a %s target", cfg.Platform.OS))
+	} else {
+		result.Changes = append(result.Changes, "removed [platform], the [[targets]] already declared are used instead")
+	}
+
+	result.Contents = joinBlocks(removeTables(blocks, "platform"), appended)
+	var migrated buildpackage.Config
+	if _, err := toml.Decode(result.Contents, &migrated); err != nil {
+		return Result{}, errors.Wrap(err, "migrated package configuration is invalid")
+	}
+	return result, nil
+}
+
+func hasTable(blocks []block, name string) bool {
+	return tableIndex(blocks, name) >= 0
+}
+
+func tableIndex(blocks []block, name string) int {
+	for i, b := range blocks {
+		if b.name == name && !b.array {
+			return i
+		}
+	}
+	return -1
+}
+
+func removeTables(blocks []block, name string) []block {
+	var kept []block
+	for _, b := range blocks {
+		if !b.isTable(name) {
+			kept = append(kept, b)
+		}
+	}
+	return kept
+}
+
+func insertAfterHeader(lines []string, line string) []string {
+	return append([]string{lines[0], line}, lines[1:]...)
+}
+
+func containsDistro(distros []distro, d distro) bool {
+	for _, existing := range distros {
+		if existing == d {
+			return true
+		}
+	}
+	return false
+}
+
+func quoteList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = strconv.Quote(value)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
@@ -0,0 +1,215 @@
+package migrate_test
+
+import (
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/migrate"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestTargets(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Targets", testTargets, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testTargets(t *testing.T, when spec.G, it spec.S) {
+	when("#Targets", func() {
+		when("builder.toml", func() {
+			it("moves the stack images to build and run images", func() {
+				result, err := migrate.Targets("builder.toml", `description = "my builder"
+
+# the stack of the builder
+[stack]
+id = "io.buildpacks.stacks.jammy"
+build-image = "example/build"
+run-image = "example/run"
+run-image-mirrors = [
+  "mirror.example.com/run",
+]
+
+[[order]]
+[[order.group]]
+id = "example/bp"
+`)
+				h.AssertNil(t, err)
+				h.AssertEq(t, result.Kind, migrate.KindBuilder)
+				h.AssertEq(t, result.Contents, `description = "my builder"
+
+[[order]]
+[[order.group]]
+id = "example/bp"
+
+[build]
+image = "example/build"
+
+[[run.images]]
+image = "example/run"
+mirrors = ["mirror.example.com/run"]
+`)
+				h.AssertEq(t, result.Changes, []string{
+					"moved stack.build-image to build.image",
+					"moved stack.run-image and stack.run-image-mirrors to [[run.images]]",
+					"removed [stack]",
+				})
+				h.AssertEq(t, len(result.Attention), 1)
+				h.AssertContains(t, result.Attention[0], "rather than only io.buildpacks.stack.id 'io.buildpacks.stacks.jammy'")
+			})
+
+			it("adds the build image to an existing build table", func() {
+				result, err := migrate.Targets("builder.toml", `[stack]
+id = "*"
+build-image = "example/build"
+
+[build]
+[[build.env]]
+name = "KEY"
+value = "value"
+
+[[run.images]]
+image = "example/run"
+`)
+				h.AssertNil(t, err)
+				h.AssertEq(t, result.Contents, `[build]
+image = "example/build"
+[[build.env]]
+name = "KEY"
+value = "value"
+
+[[run.images]]
+image = "example/run"
+`)
+				h.AssertEq(t, len(result.Attention), 0)
+			})
+
+			it("checks the lifecycle supports targets", func() {
+				result, err := migrate.Targets("builder.toml", `[lifecycle]
+version = "0.16.5"
+
+[build]
+image = "example/build"
+`)
+				h.AssertNil(t, err)
+				h.AssertFalse(t, result.Changed())
+				h.AssertEq(t, result.Attention, []string{"lifecycle '0.16.5' doesn't support targets, use lifecycle 0.17.0 or later (Platform API 0.12)"})
+
+				result, err = migrate.Targets("builder.toml", `[lifecycle]
+uri = "lifecycle.tgz"
+`)
+				h.AssertNil(t, err)
+				h.AssertContains(t, result.Attention[0], "check that the lifecycle at 'lifecycle.tgz' is version 0.17.0 or later")
+			})
+		})
+
+		when("buildpack.toml", func() {
+			it("replaces known stacks with a target", func() {
+				result, err := migrate.Targets("buildpack.toml", `api = "0.10"
+
+[buildpack]
+id = "example/bp"
+version = "1.0.0"
+
+[[stacks]]
+id = "io.buildpacks.stacks.bionic"
+
+[[stacks]]
+id = "io.buildpacks.stacks.jammy"
+mixins = ["curl"]
+
+[[stacks]]
+id = "io.buildpacks.stacks.jammy.tiny"
+`)
+				h.AssertNil(t, err)
+				h.AssertEq(t, result.Contents, `api = "0.10"
+
+[buildpack]
+id = "example/bp"
+version = "1.0.0"
+
+[[targets]]
+os = "linux"
+
+[[targets.distros]]
+name = "ubuntu"
+version = "18.04"
+
+[[targets.distros]]
+name = "ubuntu"
+version = "22.04"
+`)
+				h.AssertEq(t, result.Changes, []string{
+					"removed [[stacks]] io.buildpacks.stacks.bionic, io.buildpacks.stacks.jammy, io.buildpacks.stacks.jammy.tiny",
+					"added a linux target for ubuntu 18.04, ubuntu 22.04",
+				})
+				h.AssertEq(t, result.Attention, []string{
+					"mixins of stack 'io.buildpacks.stacks.jammy' have no equivalent in targets (curl), check for them in bin/detect or bin/build instead",
+				})
+			})
+
+			it("replaces the any stack with a linux target", func() {
+				result, err := migrate.Targets("buildpack.toml", `api = "0.10"
+
+[buildpack]
+id = "example/bp"
+
+[[stacks]]
+id = "*"
+`)
+				h.AssertNil(t, err)
+				h.AssertContains(t, result.Contents, "[[targets]]\nos = \"linux\"\n")
+				h.AssertNotContains(t, result.Contents, "distros")
+			})
+
+			it("keeps unknown stacks and reports old Buildpack APIs", func() {
+				result, err := migrate.Targets("buildpack.toml", `api = "0.8"
+
+[buildpack]
+id = "example/bp"
+
+[[stacks]]
+id = "com.example.stack"
+`)
+				h.AssertNil(t, err)
+				h.AssertFalse(t, result.Changed())
+				h.AssertEq(t, result.Attention, []string{
+					"Buildpack API 0.8 doesn't support targets, update api to 0.10 or later once the buildpack handles the changes of the Buildpack APIs in between",
+					"stack 'com.example.stack' has no known target, declare its os, arch and distros in [[targets]] and remove it",
+				})
+			})
+		})
+
+		when("package.toml", func() {
+			it("replaces the platform with a target", func() {
+				result, err := migrate.Targets("package.toml", `[buildpack]
+uri = "."
+
+[platform]
+os = "windows"
+`)
+				h.AssertNil(t, err)
+				h.AssertEq(t, result.Contents, `[buildpack]
+uri = "."
+
+[[targets]]
+os = "windows"
+`)
+				h.AssertEq(t, result.Changes, []string{"replaced [platform] with a windows target"})
+			})
+		})
+
+		it("detects the kind of files from their contents", func() {
+			result, err := migrate.Targets("my-package.toml", `[buildpack]
+uri = "."
+`)
+			h.AssertNil(t, err)
+			h.AssertEq(t, result.Kind, migrate.KindPackage)
+
+			_, err = migrate.Targets("other.toml", `key = "value"`)
+			h.AssertError(t, err, "'other.toml' isn't a builder, buildpack or package configuration")
+		})
+	})
+}
@@ -0,0 +1,135 @@
+// Package plugin runs executables named pack-<name> found on PATH as subcommands of pack, so that pack can be extended
+// without being forked.
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Prefix of the executables of plugins
+const Prefix = "pack-"
+
+// Environment variables set for plugins
+const (
+	EnvConfig  = "PACK_CONFIG"
+	EnvHome    = "PACK_HOME"
+	EnvContext = "PACK_PLUGIN_CONTEXT"
+)
+
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// Context describes the pack that runs a plugin. It is passed to plugins as JSON in PACK_PLUGIN_CONTEXT.
+type Context struct {
+	Version      string `json:"version"`
+	ConfigPath   string `json:"configPath"`
+	Home         string `json:"home"`
+	Experimental bool   `json:"experimental"`
+}
+
+type Plugin struct {
+	Name string
+	Path string
+}
+
+// Lookup returns the plugin for a subcommand, found on PATH
+func Lookup(name string) (Plugin, bool) {
+	if !namePattern.MatchString(name) {
+		return Plugin{}, false
+	}
+
+	path, err := exec.LookPath(Prefix + name)
+	if err != nil {
+		return Plugin{}, false
+	}
+	return Plugin{Name: name, Path: path}, true
+}
+
+// List returns the plugins found in the directories of pathList, sorted by name. A plugin found in more than one
+// directory is the one of the first directory, as for Lookup.
+func List(pathList string) []Plugin {
+	found := map[string]Plugin{}
+	for _, dir := range filepath.SplitList(pathList) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			name, ok := pluginName(entry.Name())
+			if !ok || entry.IsDir() {
+				continue
+			}
+			if _, ok := found[name]; ok {
+				continue
+			}
+
+			path := filepath.Join(dir, entry.Name())
+			if info, err := os.Stat(path); err != nil || !executable(info) {
+				continue
+			}
+			found[name] = Plugin{Name: name, Path: path}
+		}
+	}
+
+	var plugins []Plugin
+	for _, p := range found {
+		plugins = append(plugins, p)
+	}
+	sort.Slice(plugins, func(i, j int) bool {
+		return plugins[i].Name < plugins[j].Name
+	})
+	return plugins
+}
+
+func pluginName(file string) (string, bool) {
+	if runtime.GOOS == "windows" {
+		ext := filepath.Ext(file)
+		if !strings.EqualFold(ext, ".exe") && !strings.EqualFold(ext, ".bat") && !strings.EqualFold(ext, ".cmd") {
+			return "", false
+		}
+		file = strings.TrimSuffix(file, ext)
+	}
+
+	name := strings.TrimPrefix(file, Prefix)
+	if name == file || !namePattern.MatchString(name) {
+		return "", false
+	}
+	return name, true
+}
+
+func executable(info os.FileInfo) bool {
+	if runtime.GOOS == "windows" {
+		return info.Mode().IsRegular()
+	}
+	return info.Mode().IsRegular() && info.Mode().Perm()&0111 != 0
+}
+
+// Run runs a plugin with args, connected to the standard streams of pack. The exit code of a plugin that fails is
+// available from the returned *exec.ExitError.
+func (p Plugin) Run(ctx context.Context, args []string, pluginContext Context) error {
+	encoded, err := json.Marshal(pluginContext)
+	if err != nil {
+		return errors.Wrap(err, "encoding plugin context")
+	}
+
+	cmd := exec.CommandContext(ctx, p.Path, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(),
+		EnvConfig+"="+pluginContext.ConfigPath,
+		EnvHome+"="+pluginContext.Home,
+		EnvContext+"="+string(encoded),
+	)
+	return cmd.Run()
+}
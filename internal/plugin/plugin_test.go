@@ -0,0 +1,110 @@
+package plugin_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/plugin"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestPlugin(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Plugin", testPlugin, spec.Report(report.Terminal{}))
+}
+
+func testPlugin(t *testing.T, when spec.G, it spec.S) {
+	var firstDir, secondDir string
+
+	writeExecutable := func(path, contents string) {
+		h.AssertNil(t, os.WriteFile(path, []byte(contents), 0755))
+	}
+
+	it.Before(func() {
+		if runtime.GOOS == "windows" {
+			t.Skip("plugins are written for sh")
+		}
+
+		var err error
+		firstDir, err = os.MkdirTemp("", "plugins")
+		h.AssertNil(t, err)
+		secondDir, err = os.MkdirTemp("", "plugins")
+		h.AssertNil(t, err)
+
+		writeExecutable(filepath.Join(firstDir, "pack-hello"), "#!/bin/sh\n")
+		writeExecutable(filepath.Join(secondDir, "pack-hello"), "#!/bin/sh\n")
+		writeExecutable(filepath.Join(secondDir, "pack-deploy"), "#!/bin/sh\n")
+		writeExecutable(filepath.Join(secondDir, "other"), "#!/bin/sh\n")
+		h.AssertNil(t, os.WriteFile(filepath.Join(secondDir, "pack-notes"), []byte("not executable"), 0600))
+		h.AssertNil(t, os.Mkdir(filepath.Join(secondDir, "pack-dir"), 0755))
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.RemoveAll(firstDir))
+		h.AssertNil(t, os.RemoveAll(secondDir))
+	})
+
+	when("#List", func() {
+		it("lists the executables named pack-<name>, the first on PATH wins", func() {
+			h.AssertEq(t, plugin.List(strings.Join([]string{firstDir, secondDir, "/does/not/exist"}, string(os.PathListSeparator))), []plugin.Plugin{
+				{Name: "deploy", Path: filepath.Join(secondDir, "pack-deploy")},
+				{Name: "hello", Path: filepath.Join(firstDir, "pack-hello")},
+			})
+		})
+	})
+
+	when("#Lookup", func() {
+		it("finds plugins on PATH", func() {
+			t.Setenv("PATH", firstDir+string(os.PathListSeparator)+secondDir)
+
+			p, ok := plugin.Lookup("deploy")
+			h.AssertTrue(t, ok)
+			h.AssertEq(t, p.Path, filepath.Join(secondDir, "pack-deploy"))
+
+			_, ok = plugin.Lookup("missing")
+			h.AssertFalse(t, ok)
+			_, ok = plugin.Lookup("../pack-deploy")
+			h.AssertFalse(t, ok)
+		})
+	})
+
+	when("#Run", func() {
+		it("passes the arguments and context to the plugin", func() {
+			out := filepath.Join(firstDir, "out")
+			writeExecutable(filepath.Join(firstDir, "pack-hello"), `#!/bin/sh
+echo "$@" > "`+out+`"
+echo "$PACK_CONFIG" >> "`+out+`"
+echo "$PACK_PLUGIN_CONTEXT" >> "`+out+`"
+exit 3
+`)
+
+			p := plugin.Plugin{Name: "hello", Path: filepath.Join(firstDir, "pack-hello")}
+			err := p.Run(context.Background(), []string{"world", "--flag"}, plugin.Context{
+				Version:    "1.2.3",
+				ConfigPath: "/home/pack/config.toml",
+				Home:       "/home/pack",
+			})
+			h.AssertError(t, err, "exit status 3")
+
+			contents, err := os.ReadFile(out)
+			h.AssertNil(t, err)
+			lines := strings.Split(strings.TrimSpace(string(contents)), "\n")
+			h.AssertEq(t, lines[0], "world --flag")
+			h.AssertEq(t, lines[1], "/home/pack/config.toml")
+
+			var pluginContext plugin.Context
+			h.AssertNil(t, json.Unmarshal([]byte(lines[2]), &pluginContext))
+			h.AssertEq(t, pluginContext, plugin.Context{Version: "1.2.3", ConfigPath: "/home/pack/config.toml", Home: "/home/pack"})
+		})
+	})
+}
@@ -0,0 +1,245 @@
+// Package policy checks the plan of a build against rules allowing, denying or warning about the builders,
+// run images, buildpacks, images and registries it uses, so that organizations can govern what pack builds.
+package policy
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+// Actions of rules
+const (
+	Allow = "allow"
+	Deny  = "deny"
+	Warn  = "warn"
+)
+
+// Subjects of rules
+const (
+	SubjectBuilder   = "builder"
+	SubjectRunImage  = "run-image"
+	SubjectBuildpack = "buildpack"
+	SubjectImage     = "image"
+	SubjectRegistry  = "registry"
+)
+
+var subjects = []string{SubjectBuilder, SubjectRunImage, SubjectBuildpack, SubjectImage, SubjectRegistry}
+
+// Policy is a list of rules. For each value of a plan, the first rule of its subject that matches it decides;
+// values that no rule matches get the default action.
+type Policy struct {
+	Default string `toml:"default"`
+	Rules   []Rule `toml:"rules"`
+}
+
+// Rule applies an action to the values of a subject matching a pattern. In patterns, * matches any sequence of
+// characters, including /, and ? matches a single character.
+type Rule struct {
+	Action  string `toml:"action"`
+	Subject string `toml:"subject"`
+	Match   string `toml:"match"`
+	Message string `toml:"message"`
+
+	pattern *regexp.Regexp
+}
+
+// Plan holds the values of a build that rules apply to
+type Plan struct {
+	Builder    string
+	RunImage   string
+	Image      string
+	Publish    bool
+	Buildpacks []string
+}
+
+// Decision is the action taken for a value of a plan
+type Decision struct {
+	Subject string
+	Value   string
+	Action  string
+	Message string
+}
+
+func (d Decision) String() string {
+	description := fmt.Sprintf("%s %s", d.Subject, style.Symbol(d.Value))
+	if d.Message != "" {
+		description += ": " + d.Message
+	}
+	return description
+}
+
+// Read reads and validates the policy file at path
+func Read(path string) (Policy, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return Policy{}, errors.Wrapf(err, "reading policy %s", style.Symbol(path))
+	}
+	return Parse(string(contents))
+}
+
+// Parse parses and validates a policy in TOML
+func Parse(contents string) (Policy, error) {
+	var p Policy
+	if _, err := toml.Decode(contents, &p); err != nil {
+		return Policy{}, errors.Wrap(err, "parsing policy")
+	}
+
+	switch p.Default {
+	case "":
+		p.Default = Allow
+	case Allow, Deny, Warn:
+	default:
+		return Policy{}, errors.Errorf("invalid default action %s, must be one of %s, %s or %s", style.Symbol(p.Default), Allow, Deny, Warn)
+	}
+
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+		switch rule.Action {
+		case Allow, Deny, Warn:
+		default:
+			return Policy{}, errors.Errorf("rule %d: invalid action %s, must be one of %s, %s or %s", i+1, style.Symbol(rule.Action), Allow, Deny, Warn)
+		}
+		if !knownSubject(rule.Subject) {
+			return Policy{}, errors.Errorf("rule %d: invalid subject %s, must be one of %s", i+1, style.Symbol(rule.Subject), strings.Join(subjects, ", "))
+		}
+		if rule.Match == "" {
+			return Policy{}, errors.Errorf("rule %d: match is required", i+1)
+		}
+		rule.pattern = compile(rule.Match)
+	}
+	return p, nil
+}
+
+func knownSubject(subject string) bool {
+	for _, known := range subjects {
+		if subject == known {
+			return true
+		}
+	}
+	return false
+}
+
+func compile(pattern string) *regexp.Regexp {
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.ReplaceAll(expr, `\*`, ".*")
+	expr = strings.ReplaceAll(expr, `\?`, ".")
+	return regexp.MustCompile("^" + expr + "$")
+}
+
+// Evaluate returns the decision for each value of a plan, in the order builder, run image, buildpacks, image
+// and registries. The registries of a plan are those of the builder and run image, and of the image when it's
+// published.
+func (p Policy) Evaluate(plan Plan) []Decision {
+	var decisions []Decision
+	decide := func(subject, value string, candidates []string) {
+		decisions = append(decisions, p.decide(subject, value, candidates))
+	}
+
+	if plan.Builder != "" {
+		decide(SubjectBuilder, plan.Builder, imageCandidates(plan.Builder))
+	}
+	if plan.RunImage != "" {
+		decide(SubjectRunImage, plan.RunImage, imageCandidates(plan.RunImage))
+	}
+	for _, bp := range plan.Buildpacks {
+		decide(SubjectBuildpack, bp, []string{bp})
+	}
+	if plan.Image != "" {
+		decide(SubjectImage, plan.Image, imageCandidates(plan.Image))
+	}
+
+	registries := []string{plan.Builder, plan.RunImage}
+	if plan.Publish {
+		registries = append(registries, plan.Image)
+	}
+	seen := map[string]bool{}
+	for _, imageName := range registries {
+		registry := registryOf(imageName)
+		if registry == "" || seen[registry] {
+			continue
+		}
+		seen[registry] = true
+		decide(SubjectRegistry, registry, []string{registry})
+	}
+	return decisions
+}
+
+func (p Policy) decide(subject, value string, candidates []string) Decision {
+	for _, rule := range p.Rules {
+		if rule.Subject != subject {
+			continue
+		}
+		for _, candidate := range candidates {
+			if rule.pattern.MatchString(candidate) {
+				return Decision{Subject: subject, Value: value, Action: rule.Action, Message: rule.Message}
+			}
+		}
+	}
+	return Decision{Subject: subject, Value: value, Action: p.Default, Message: "matches no rule"}
+}
+
+// imageCandidates returns the forms of an image name that patterns are matched against: the name as given and its
+// fully qualified name, e.g. paketobuildpacks/builder, docker.io/paketobuildpacks/builder:latest and
+// index.docker.io/paketobuildpacks/builder:latest
+func imageCandidates(imageName string) []string {
+	candidates := []string{imageName}
+	ref, err := name.ParseReference(imageName, name.WeakValidation)
+	if err != nil {
+		return candidates
+	}
+
+	qualified := ref.Name()
+	candidates = append(candidates, qualified)
+	if strings.HasPrefix(qualified, name.DefaultRegistry+"/") {
+		candidates = append(candidates, "docker.io/"+strings.TrimPrefix(qualified, name.DefaultRegistry+"/"))
+	}
+	return candidates
+}
+
+// registryOf returns the registry of an image, with docker.io for Docker Hub
+func registryOf(imageName string) string {
+	if imageName == "" {
+		return ""
+	}
+	ref, err := name.ParseReference(imageName, name.WeakValidation)
+	if err != nil {
+		return ""
+	}
+	registry := ref.Context().RegistryStr()
+	if registry == name.DefaultRegistry {
+		return "docker.io"
+	}
+	return registry
+}
+
+// Check logs a warning for each value of a plan the policy warns about, and returns an error listing the values it
+// denies, if any
+func Check(p Policy, plan Plan, logger logging.Logger) error {
+	var denied []string
+	for _, decision := range p.Evaluate(plan) {
+		switch decision.Action {
+		case Warn:
+			logger.Warnf("Policy warns about %s", decision)
+		case Deny:
+			denied = append(denied, decision.String())
+		}
+	}
+
+	switch len(denied) {
+	case 0:
+		return nil
+	case 1:
+		return errors.Errorf("policy denies %s", denied[0])
+	default:
+		return errors.Errorf("policy denies:\n  %s", strings.Join(denied, "\n  "))
+	}
+}
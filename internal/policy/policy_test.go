@@ -0,0 +1,186 @@
+package policy_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/policy"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestPolicy(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Policy", testPolicy, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testPolicy(t *testing.T, when spec.G, it spec.S) {
+	plan := policy.Plan{
+		Builder:    "paketobuildpacks/builder-jammy-base",
+		RunImage:   "registry.example.com/run:jammy",
+		Image:      "registry.example.com/team/app",
+		Buildpacks: []string{"paketo-buildpacks/go@4.0.0", "example/legacy@1.0.0"},
+	}
+
+	when("#Parse", func() {
+		it("defaults to allow", func() {
+			p, err := policy.Parse(``)
+			h.AssertNil(t, err)
+			h.AssertEq(t, p.Default, policy.Allow)
+		})
+
+		it("rejects unknown actions", func() {
+			_, err := policy.Parse(`
+[[rules]]
+action = "block"
+subject = "builder"
+match = "*"
+`)
+			h.AssertError(t, err, "rule 1: invalid action 'block'")
+		})
+
+		it("rejects unknown subjects", func() {
+			_, err := policy.Parse(`
+[[rules]]
+action = "deny"
+subject = "stack"
+match = "*"
+`)
+			h.AssertError(t, err, "rule 1: invalid subject 'stack'")
+		})
+
+		it("requires a pattern", func() {
+			_, err := policy.Parse(`
+[[rules]]
+action = "deny"
+subject = "builder"
+`)
+			h.AssertError(t, err, "rule 1: match is required")
+		})
+
+		it("rejects unknown default actions", func() {
+			_, err := policy.Parse(`default = "maybe"`)
+			h.AssertError(t, err, "invalid default action 'maybe'")
+		})
+	})
+
+	when("#Read", func() {
+		it("reads a policy file", func() {
+			path := filepath.Join(t.TempDir(), "policy.toml")
+			h.AssertNil(t, os.WriteFile(path, []byte(`default = "deny"`), 0600))
+
+			p, err := policy.Read(path)
+			h.AssertNil(t, err)
+			h.AssertEq(t, p.Default, policy.Deny)
+		})
+	})
+
+	when("#Evaluate", func() {
+		it("decides with the first matching rule of each subject", func() {
+			p, err := policy.Parse(`
+default = "deny"
+
+[[rules]]
+action = "warn"
+subject = "buildpack"
+match = "example/legacy@*"
+message = "deprecated"
+
+[[rules]]
+action = "allow"
+subject = "buildpack"
+match = "*"
+
+[[rules]]
+action = "allow"
+subject = "builder"
+match = "docker.io/paketobuildpacks/*"
+
+[[rules]]
+action = "allow"
+subject = "registry"
+match = "registry.example.com"
+`)
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, p.Evaluate(plan), []policy.Decision{
+				{Subject: "builder", Value: "paketobuildpacks/builder-jammy-base", Action: policy.Allow},
+				{Subject: "run-image", Value: "registry.example.com/run:jammy", Action: policy.Deny, Message: "matches no rule"},
+				{Subject: "buildpack", Value: "paketo-buildpacks/go@4.0.0", Action: policy.Allow},
+				{Subject: "buildpack", Value: "example/legacy@1.0.0", Action: policy.Warn, Message: "deprecated"},
+				{Subject: "image", Value: "registry.example.com/team/app", Action: policy.Deny, Message: "matches no rule"},
+				{Subject: "registry", Value: "docker.io", Action: policy.Deny, Message: "matches no rule"},
+				{Subject: "registry", Value: "registry.example.com", Action: policy.Allow},
+			})
+		})
+
+		it("includes the registry of the image only when it's published", func() {
+			p, err := policy.Parse(`
+[[rules]]
+action = "deny"
+subject = "registry"
+match = "docker.io"
+`)
+			h.AssertNil(t, err)
+
+			local := policy.Plan{Builder: "registry.example.com/builder", Image: "some/app"}
+			for _, decision := range p.Evaluate(local) {
+				h.AssertEq(t, decision.Action, policy.Allow)
+			}
+
+			local.Publish = true
+			decisions := p.Evaluate(local)
+			h.AssertEq(t, decisions[len(decisions)-1], policy.Decision{Subject: "registry", Value: "docker.io", Action: policy.Deny})
+		})
+	})
+
+	when("#Check", func() {
+		var (
+			outBuf bytes.Buffer
+			logger logging.Logger
+		)
+
+		it.Before(func() {
+			logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		})
+
+		it("warns and lists denied values", func() {
+			p, err := policy.Parse(`
+[[rules]]
+action = "warn"
+subject = "buildpack"
+match = "example/legacy@*"
+message = "deprecated"
+
+[[rules]]
+action = "deny"
+subject = "run-image"
+match = "registry.example.com/*"
+message = "use the hardened run image"
+
+[[rules]]
+action = "deny"
+subject = "builder"
+match = "*builder-jammy-*"
+`)
+			h.AssertNil(t, err)
+
+			err = policy.Check(p, plan, logger)
+			h.AssertError(t, err, "policy denies:\n  builder 'paketobuildpacks/builder-jammy-base'\n  run-image 'registry.example.com/run:jammy': use the hardened run image")
+			h.AssertContains(t, outBuf.String(), "Policy warns about buildpack 'example/legacy@1.0.0': deprecated")
+		})
+
+		it("succeeds when nothing is denied", func() {
+			p, err := policy.Parse(``)
+			h.AssertNil(t, err)
+			h.AssertNil(t, policy.Check(p, plan, logger))
+		})
+	})
+}
@@ -0,0 +1,165 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// spinnerInterval is how often the spinner of a CloneDisplay turns on a terminal
+const spinnerInterval = 100 * time.Millisecond
+
+var spinnerFrames = []string{"|", "/", "-", "\\"}
+
+// stageRegexp matches the progress of a stage reported by git, e.g. "Counting objects:  45% (123/270)"
+var stageRegexp = regexp.MustCompile(`^(.+?):\s+(\d+)% \((\d+)/(\d+)\)(.*)$`)
+
+// CloneDisplay renders the progress git reports while a repository is cloned, e.g. "Counting objects:  45% (123/270)",
+// as the progress writer of the clone.
+//
+// On a terminal it redraws a single line with a spinner, the current stage and its percentage, turning the spinner
+// even while git reports nothing so that the clone doesn't look hung. Otherwise (e.g. in CI) it prints each stage as
+// it's done, and a line with the current stage at a fixed interval.
+type CloneDisplay struct {
+	display
+
+	out    io.Writer
+	isTerm bool
+	name   string
+
+	mu      sync.Mutex
+	started time.Time
+	pending string
+	stage   string
+	frame   int
+	drawn   bool
+	stop    chan struct{}
+	stopped sync.WaitGroup
+}
+
+// NewCloneDisplay creates a CloneDisplay writing to out for the repository with the given name
+func NewCloneDisplay(out io.Writer, isTerm bool, name string, ops ...DisplayOption) *CloneDisplay {
+	return &CloneDisplay{
+		display: newDisplay(ops),
+		out:     out,
+		isTerm:  isTerm,
+		name:    name,
+	}
+}
+
+// Start shows that the clone started, and keeps the display moving until Done
+func (c *CloneDisplay) Start() {
+	c.mu.Lock()
+	c.started = c.clock()
+	c.stop = make(chan struct{})
+	if c.isTerm {
+		c.redraw()
+	} else {
+		fmt.Fprintf(c.out, "Cloning %s\n", style.Symbol(c.name))
+	}
+	c.mu.Unlock()
+
+	interval := c.interval
+	if c.isTerm {
+		interval = spinnerInterval
+	}
+	c.stopped.Add(1)
+	go func() {
+		defer c.stopped.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.stop:
+				return
+			case <-ticker.C:
+				c.tick()
+			}
+		}
+	}()
+}
+
+// Write consumes the progress reported by git, whose messages end with a carriage return while they're updated and
+// with a newline once they're done
+func (c *CloneDisplay) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pending += string(p)
+	for {
+		end := strings.IndexAny(c.pending, "\r\n")
+		if end < 0 {
+			break
+		}
+		message, done := strings.TrimSpace(c.pending[:end]), c.pending[end] == '\n'
+		c.pending = c.pending[end+1:]
+		if message != "" {
+			c.handle(message, done)
+		}
+	}
+	return len(p), nil
+}
+
+// Done stops the display, clearing its line on a terminal, and prints how long the clone took unless it failed with err
+func (c *CloneDisplay) Done(err error) {
+	if c.stop != nil {
+		close(c.stop)
+		c.stopped.Wait()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.isTerm && c.drawn {
+		io.WriteString(c.out, "\x1b[2K\r")
+	}
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(c.out, "Cloned %s (%s elapsed)\n", style.Symbol(c.name), c.clock().Sub(c.started).Round(time.Second))
+}
+
+func (c *CloneDisplay) handle(message string, done bool) {
+	message = strings.TrimPrefix(message, "remote: ")
+	c.stage = strings.TrimSuffix(message, ", done.")
+	if c.isTerm {
+		c.redraw()
+		return
+	}
+	if done {
+		fmt.Fprintln(c.out, message)
+	}
+}
+
+func (c *CloneDisplay) tick() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.isTerm {
+		c.frame = (c.frame + 1) % len(spinnerFrames)
+		c.redraw()
+		return
+	}
+	fmt.Fprintf(c.out, "Cloning %s: %s\n", style.Symbol(c.name), c.status())
+}
+
+func (c *CloneDisplay) redraw() {
+	fmt.Fprintf(c.out, "\x1b[2K\r%s Cloning %s: %s", style.Working(spinnerFrames[c.frame]), style.Symbol(c.name), c.status())
+	c.drawn = true
+}
+
+// status is the current stage with its percentage, and how long the clone has taken
+func (c *CloneDisplay) status() string {
+	elapsed := fmt.Sprintf("(%s elapsed)", c.clock().Sub(c.started).Round(time.Second))
+	if c.stage == "" {
+		return "waiting for the server " + elapsed
+	}
+	if match := stageRegexp.FindStringSubmatch(c.stage); match != nil {
+		return fmt.Sprintf("%s %s%% (%s/%s) %s", match[1], match[2], match[3], match[4], elapsed)
+	}
+	return c.stage + " " + elapsed
+}
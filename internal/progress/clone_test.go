@@ -0,0 +1,94 @@
+package progress_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/progress"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestCloneDisplay(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "CloneDisplay", testCloneDisplay, spec.Report(report.Terminal{}))
+}
+
+func testCloneDisplay(t *testing.T, when spec.G, it spec.S) {
+	var (
+		out   bytes.Buffer
+		now   time.Time
+		clock = func() time.Time { return now }
+	)
+
+	write := func(w io.Writer, messages ...string) {
+		for _, message := range messages {
+			_, err := io.WriteString(w, message)
+			h.AssertNil(t, err)
+		}
+	}
+
+	it.Before(func() {
+		out.Reset()
+		now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	})
+
+	when("output is not a terminal", func() {
+		it("prints each stage once it's done", func() {
+			subject := progress.NewCloneDisplay(&out, false, "some-registry", progress.WithClock(clock), progress.WithInterval(time.Hour))
+
+			subject.Start()
+			write(subject,
+				"Enumerating objects: 10, done.\n",
+				"Counting objects:  50% (1/2)\r",
+				"Counting objects: 100% (2/2)\r",
+				"Counting objects: 100% (2/2), ",
+				"done.\n",
+				"remote: Compressing objects:  50% (1/2)\r",
+			)
+			now = now.Add(3 * time.Second)
+			subject.Done(nil)
+
+			output := out.String()
+			h.AssertContains(t, output, "Cloning 'some-registry'\n")
+			h.AssertContains(t, output, "Enumerating objects: 10, done.\n")
+			h.AssertContains(t, output, "Counting objects: 100% (2/2), done.\n")
+			h.AssertNotContains(t, output, "50% (1/2)")
+			h.AssertContains(t, output, "Cloned 'some-registry' (3s elapsed)\n")
+			h.AssertNotContains(t, output, "\r")
+		})
+
+		it("doesn't print that the clone is done when it failed", func() {
+			subject := progress.NewCloneDisplay(&out, false, "some-registry", progress.WithClock(clock), progress.WithInterval(time.Hour))
+
+			subject.Start()
+			subject.Done(errors.New("some-error"))
+
+			h.AssertNotContains(t, out.String(), "Cloned")
+		})
+	})
+
+	when("output is a terminal", func() {
+		it("redraws a single line with the progress of the current stage", func() {
+			subject := progress.NewCloneDisplay(&out, true, "some-registry", progress.WithClock(clock))
+
+			subject.Start()
+			write(subject, "Receiving objects:  45% (123/270), 1.20 MiB | 2.00 MiB/s\r")
+			subject.Done(nil)
+
+			output := out.String()
+			h.AssertContains(t, output, "\x1b[2K\r")
+			h.AssertContains(t, output, "Cloning 'some-registry': waiting for the server (0s elapsed)")
+			h.AssertContains(t, output, "Cloning 'some-registry': Receiving objects 45% (123/270) (0s elapsed)")
+			h.AssertContains(t, output, "Cloned 'some-registry' (0s elapsed)\n")
+		})
+	})
+}
@@ -0,0 +1,308 @@
+// Package progress renders the progress of long running image transfers and repository clones.
+package progress
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/dustin/go-humanize"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/internal/term"
+)
+
+// DefaultInterval is how often a summary line is printed when output is not a terminal
+const DefaultInterval = 10 * time.Second
+
+// PullDisplay renders the JSON messages streamed by the daemon while pulling an image.
+//
+// On a terminal it redraws one line per layer showing its status, size, transfer speed and ETA, the complete layers
+// being collapsed into a single line.
+// Otherwise (e.g. in CI) it prints a single summary line at a fixed interval instead of every progress event.
+type PullDisplay struct {
+	display
+
+	out    io.Writer
+	isTerm bool
+	name   string
+
+	layers     map[string]*layerProgress
+	order      []string
+	started    time.Time
+	lastReport time.Time
+	drawnLines int
+}
+
+type layerProgress struct {
+	status  string
+	current int64
+	total   int64
+	started time.Time
+}
+
+// display holds the settings shared by the displays
+type display struct {
+	interval time.Duration
+	clock    func() time.Time
+	height   int
+}
+
+func newDisplay(ops []DisplayOption) display {
+	d := display{interval: DefaultInterval, clock: time.Now}
+	for _, op := range ops {
+		op(&d)
+	}
+	return d
+}
+
+// DisplayOption configures a PullDisplay or a CloneDisplay
+type DisplayOption func(*display)
+
+// PullDisplayOption configures a PullDisplay
+type PullDisplayOption = DisplayOption
+
+// WithClock sets the clock used to compute elapsed times, speeds and ETAs
+func WithClock(clock func() time.Time) DisplayOption {
+	return func(d *display) {
+		d.clock = clock
+	}
+}
+
+// WithHeight sets the number of rows of the terminal, which the redrawn lines never exceed so that they can all be
+// moved back over
+func WithHeight(rows int) DisplayOption {
+	return func(d *display) {
+		d.height = rows
+	}
+}
+
+// WithInterval sets how often a summary line is printed when output is not a terminal
+func WithInterval(interval time.Duration) DisplayOption {
+	return func(d *display) {
+		d.interval = interval
+	}
+}
+
+// Output returns the writer for displays to draw to out, whether out is a terminal and the options of the displays
+// drawn to it. On a terminal, they draw to the writer under a log writer, which would strip their escape codes without
+// colors and prefix their lines with timestamps, and never draw more lines than the terminal has rows.
+func Output(out io.Writer) (io.Writer, bool, []DisplayOption) {
+	fd, isTerm := term.IsTerminal(out)
+	if !isTerm {
+		return out, false, nil
+	}
+	if w, ok := out.(interface{ Writer() io.Writer }); ok {
+		out = w.Writer()
+	}
+	return out, true, []DisplayOption{WithHeight(term.Height(fd))}
+}
+
+// NewPullDisplay creates a PullDisplay writing to out for the image with the given name
+func NewPullDisplay(out io.Writer, isTerm bool, name string, ops ...PullDisplayOption) *PullDisplay {
+	return &PullDisplay{
+		display: newDisplay(ops),
+		out:     out,
+		isTerm:  isTerm,
+		name:    name,
+		layers:  map[string]*layerProgress{},
+	}
+}
+
+// Display consumes the stream until it is exhausted, returning the first error reported by the daemon.
+func (p *PullDisplay) Display(in io.Reader) error {
+	p.started = p.clock()
+	p.lastReport = p.started
+
+	decoder := json.NewDecoder(in)
+	for {
+		var msg jsonmessage.JSONMessage
+		if err := decoder.Decode(&msg); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return err
+		}
+
+		if msg.Error != nil {
+			return msg.Error
+		}
+
+		if err := p.handle(msg); err != nil {
+			return err
+		}
+	}
+
+	if !p.isTerm && len(p.layers) > 0 {
+		_, err := fmt.Fprintf(p.out, "Pulled %s: %s\n", style.Symbol(p.name), p.summary())
+		return err
+	}
+	return nil
+}
+
+func (p *PullDisplay) handle(msg jsonmessage.JSONMessage) error {
+	if msg.ID == "" || !isLayerStatus(msg.Status) {
+		return p.printStatus(msg)
+	}
+
+	layer, ok := p.layers[msg.ID]
+	if !ok {
+		layer = &layerProgress{started: p.clock()}
+		p.layers[msg.ID] = layer
+		p.order = append(p.order, msg.ID)
+	}
+	layer.status = msg.Status
+	if msg.Progress != nil {
+		layer.current = msg.Progress.Current
+		if msg.Progress.Total > 0 {
+			layer.total = msg.Progress.Total
+		}
+	}
+	if isCompleteStatus(msg.Status) && layer.total > 0 {
+		layer.current = layer.total
+	}
+
+	if p.isTerm {
+		return p.redraw()
+	}
+
+	if now := p.clock(); now.Sub(p.lastReport) >= p.interval {
+		p.lastReport = now
+		_, err := fmt.Fprintf(p.out, "Pulling %s: %s\n", style.Symbol(p.name), p.summary())
+		return err
+	}
+	return nil
+}
+
+func (p *PullDisplay) printStatus(msg jsonmessage.JSONMessage) error {
+	status := msg.Status
+	if msg.ID != "" {
+		status = fmt.Sprintf("%s: %s", msg.ID, msg.Status)
+	}
+	if strings.TrimSpace(status) == "" {
+		return nil
+	}
+
+	// the line is printed below any drawn layers, which are then left as they are
+	p.drawnLines = 0
+	_, err := fmt.Fprintln(p.out, status)
+	return err
+}
+
+func (p *PullDisplay) redraw() error {
+	var lines []string
+	complete := 0
+	for _, id := range p.order {
+		if isCompleteStatus(p.layers[id].status) {
+			complete++
+			continue
+		}
+		lines = append(lines, p.layerLine(id))
+	}
+	if complete > 0 {
+		lines = append([]string{fmt.Sprintf("%d of %d layers %s", complete, len(p.order), style.Complete("complete"))}, lines...)
+	}
+
+	var err error
+	p.drawnLines, err = p.redrawLines(p.out, p.drawnLines, lines)
+	return err
+}
+
+// redrawLines replaces the drawn lines written to out with lines, returning how many lines are drawn. They are clamped
+// to the height of the terminal, the last one telling how many lines aren't shown, as lines scrolled out of it can't be
+// moved back over.
+func (d display) redrawLines(out io.Writer, drawn int, lines []string) (int, error) {
+	if d.height > 0 {
+		maxLines := max(d.height-1, 1)
+		if len(lines) > maxLines {
+			hidden := len(lines) - maxLines + 1
+			lines = append(lines[:maxLines-1:maxLines-1], fmt.Sprintf("... and %d more layers", hidden))
+		}
+	}
+
+	var b strings.Builder
+	if drawn > 0 {
+		fmt.Fprintf(&b, "\x1b[%dA", drawn)
+	}
+	for _, line := range lines {
+		fmt.Fprintf(&b, "\x1b[2K%s\n", line)
+	}
+	// clear the lines drawn before that aren't drawn anymore
+	for i := len(lines); i < drawn; i++ {
+		b.WriteString("\x1b[2K\n")
+	}
+	if extra := drawn - len(lines); extra > 0 {
+		fmt.Fprintf(&b, "\x1b[%dA", extra)
+	}
+
+	_, err := io.WriteString(out, b.String())
+	return len(lines), err
+}
+
+func (p *PullDisplay) layerLine(id string) string {
+	layer := p.layers[id]
+	line := fmt.Sprintf("%s: %s", id, colorizeStatus(layer.status))
+	if layer.total <= 0 || isCompleteStatus(layer.status) {
+		return line
+	}
+
+	line += fmt.Sprintf(" %s/%s", humanize.Bytes(uint64(layer.current)), humanize.Bytes(uint64(layer.total)))
+	elapsed := p.clock().Sub(layer.started).Seconds()
+	if elapsed <= 0 || layer.current <= 0 {
+		return line
+	}
+
+	speed := float64(layer.current) / elapsed
+	line += fmt.Sprintf(" %s/s", humanize.Bytes(uint64(speed)))
+	if remaining := layer.total - layer.current; remaining > 0 {
+		eta := time.Duration(float64(remaining) / speed * float64(time.Second))
+		line += fmt.Sprintf(" ETA %s", eta.Round(time.Second))
+	}
+	return line
+}
+
+func (p *PullDisplay) summary() string {
+	var current, total int64
+	complete := 0
+	for _, id := range p.order {
+		layer := p.layers[id]
+		current += layer.current
+		total += layer.total
+		if isCompleteStatus(layer.status) {
+			complete++
+		}
+	}
+
+	summary := fmt.Sprintf("%d/%d layers complete", complete, len(p.order))
+	if total > 0 {
+		summary += fmt.Sprintf(", %s/%s", humanize.Bytes(uint64(current)), humanize.Bytes(uint64(total)))
+	}
+	return summary + fmt.Sprintf(" (%s elapsed)", p.clock().Sub(p.started).Round(time.Second))
+}
+
+func isLayerStatus(status string) bool {
+	switch status {
+	case "Pulling fs layer", "Waiting", "Downloading", "Verifying Checksum", "Download complete", "Extracting",
+		"Pull complete", "Already exists":
+		return true
+	}
+	return strings.HasPrefix(status, "Retrying")
+}
+
+func isCompleteStatus(status string) bool {
+	return status == "Pull complete" || status == "Already exists"
+}
+
+func colorizeStatus(status string) string {
+	switch status {
+	case "Waiting", "Pulling fs layer":
+		return style.Waiting(status)
+	case "Pull complete", "Already exists":
+		return style.Complete(status)
+	default:
+		return style.Working(status)
+	}
+}
@@ -0,0 +1,151 @@
+package progress_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/progress"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestPullDisplay(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "PullDisplay", testPullDisplay, spec.Report(report.Terminal{}))
+}
+
+func testPullDisplay(t *testing.T, when spec.G, it spec.S) {
+	var (
+		out   bytes.Buffer
+		now   time.Time
+		clock = func() time.Time { return now }
+	)
+
+	stream := func(messages ...string) *strings.Reader {
+		return strings.NewReader(strings.Join(messages, "\n"))
+	}
+
+	it.Before(func() {
+		out.Reset()
+		now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	})
+
+	when("output is not a terminal", func() {
+		it("prints a summary at the configured interval and when done", func() {
+			subject := progress.NewPullDisplay(&out, false, "some/image", progress.WithClock(clock), progress.WithInterval(10*time.Second))
+
+			reader := &tickingReader{
+				lines: []string{
+					`{"status":"Pulling from some/image","id":"latest"}`,
+					`{"status":"Pulling fs layer","id":"aaa"}`,
+					`{"status":"Already exists","id":"bbb"}`,
+					`{"status":"Downloading","id":"aaa","progressDetail":{"current":1000000,"total":4000000}}`,
+					`{"status":"Downloading","id":"aaa","progressDetail":{"current":2000000,"total":4000000}}`,
+					`{"status":"Pull complete","id":"aaa"}`,
+					`{"status":"Status: Downloaded newer image for some/image:latest"}`,
+				},
+				tick: func() { now = now.Add(4 * time.Second) },
+			}
+
+			h.AssertNil(t, subject.Display(reader))
+
+			output := out.String()
+			h.AssertContains(t, output, "latest: Pulling from some/image\n")
+			h.AssertContains(t, output, "Pulling 'some/image': 1/2 layers complete (12s elapsed)\n")
+			h.AssertContains(t, output, "Status: Downloaded newer image for some/image:latest\n")
+			h.AssertContains(t, output, "Pulled 'some/image': 2/2 layers complete, 4.0 MB/4.0 MB (28s elapsed)\n")
+			h.AssertNotContains(t, output, "Downloading")
+		})
+
+		it("does not print a summary when nothing was pulled", func() {
+			subject := progress.NewPullDisplay(&out, false, "some/image")
+			h.AssertNil(t, subject.Display(stream(`{"status":"Status: Image is up to date for some/image:latest"}`)))
+			h.AssertEq(t, out.String(), "Status: Image is up to date for some/image:latest\n")
+		})
+	})
+
+	when("output is a terminal", func() {
+		it("redraws a line per layer with size, speed and ETA", func() {
+			subject := progress.NewPullDisplay(&out, true, "some/image", progress.WithClock(clock))
+
+			reader := &tickingReader{
+				lines: []string{
+					`{"status":"Pulling fs layer","id":"aaa"}`,
+					`{"status":"Downloading","id":"aaa","progressDetail":{"current":1000000,"total":4000000}}`,
+				},
+				tick: func() { now = now.Add(time.Second) },
+			}
+			h.AssertNil(t, subject.Display(reader))
+
+			output := out.String()
+			h.AssertContains(t, output, "\x1b[2Kaaa: Pulling fs layer\n")
+			h.AssertContains(t, output, "\x1b[1A\x1b[2Kaaa: Downloading 1.0 MB/4.0 MB 1.0 MB/s ETA 3s\n")
+		})
+
+		it("collapses the complete layers into a single line", func() {
+			subject := progress.NewPullDisplay(&out, true, "some/image", progress.WithClock(clock))
+
+			h.AssertNil(t, subject.Display(stream(
+				`{"status":"Pulling fs layer","id":"aaa"}`,
+				`{"status":"Pulling fs layer","id":"bbb"}`,
+				`{"status":"Pull complete","id":"aaa"}`,
+				`{"status":"Pull complete","id":"bbb"}`,
+			)))
+
+			output := out.String()
+			h.AssertContains(t, output, "\x1b[2A\x1b[2K1 of 2 layers complete\n\x1b[2Kbbb: Pulling fs layer\n")
+			// the line that isn't drawn anymore is cleared
+			h.AssertContains(t, output, "\x1b[2A\x1b[2K2 of 2 layers complete\n\x1b[2K\n\x1b[1A")
+		})
+
+		it("never draws more lines than the terminal has rows", func() {
+			subject := progress.NewPullDisplay(&out, true, "some/image", progress.WithClock(clock), progress.WithHeight(3))
+
+			h.AssertNil(t, subject.Display(stream(
+				`{"status":"Pulling fs layer","id":"aaa"}`,
+				`{"status":"Pulling fs layer","id":"bbb"}`,
+				`{"status":"Pulling fs layer","id":"ccc"}`,
+				`{"status":"Pulling fs layer","id":"ddd"}`,
+			)))
+
+			output := out.String()
+			h.AssertContains(t, output, "\x1b[2A\x1b[2Kaaa: Pulling fs layer\n\x1b[2K... and 3 more layers\n")
+			h.AssertNotContains(t, output, "\x1b[3A")
+		})
+	})
+
+	it("returns errors reported by the daemon", func() {
+		subject := progress.NewPullDisplay(&out, false, "some/image")
+		err := subject.Display(stream(`{"errorDetail":{"message":"manifest unknown"},"error":"manifest unknown"}`))
+		h.AssertError(t, err, "manifest unknown")
+	})
+}
+
+// tickingReader yields one message at a time, advancing the clock before each one
+type tickingReader struct {
+	lines   []string
+	pending string
+	tick    func()
+}
+
+func (r *tickingReader) Read(p []byte) (int, error) {
+	if r.pending == "" {
+		if len(r.lines) == 0 {
+			return 0, io.EOF
+		}
+		r.tick()
+		r.pending = r.lines[0] + "\n"
+		r.lines = r.lines[1:]
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
@@ -0,0 +1,192 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+const (
+	pushStatusPushing = "Pushing"
+	pushStatusPushed  = "Pushed"
+	pushStatusExists  = "Layer already exists"
+	pushStatusFailed  = "Push failed"
+)
+
+// PushDisplay renders the updates reported while the layers of an image are pushed to a registry.
+//
+// On a terminal it redraws one line per layer being pushed showing its size, transfer speed and ETA, the pushed
+// layers being collapsed into a single line. Otherwise (e.g. in CI) it prints a single summary line at a fixed interval.
+type PushDisplay struct {
+	display
+
+	out    io.Writer
+	isTerm bool
+	name   string
+	total  int
+
+	layers     map[string]*layerProgress
+	order      []string
+	started    time.Time
+	lastReport time.Time
+	drawnLines int
+}
+
+// NewPushDisplay creates a PushDisplay writing to out for the image with the given name and number of layers
+func NewPushDisplay(out io.Writer, isTerm bool, name string, layers int, ops ...DisplayOption) *PushDisplay {
+	d := newDisplay(ops)
+	now := d.clock()
+	return &PushDisplay{
+		display:    d,
+		out:        out,
+		isTerm:     isTerm,
+		name:       name,
+		total:      layers,
+		layers:     map[string]*layerProgress{},
+		started:    now,
+		lastReport: now,
+	}
+}
+
+// Layer consumes the updates of the push of the layer with the given id and size until they are closed, as remote
+// does once the layer is pushed. The size is taken from the updates when it's 0.
+func (p *PushDisplay) Layer(id string, size int64, updates <-chan v1.Update) error {
+	layer := &layerProgress{status: pushStatusPushing, total: size, started: p.clock()}
+	p.layers[id] = layer
+	p.order = append(p.order, id)
+
+	// the updates are drained even when the output fails, as remote blocks until they are received
+	err := p.update()
+	var failed, uploaded bool
+	for u := range updates {
+		if u.Error != nil {
+			failed = true
+			continue
+		}
+		uploaded = true
+		layer.current = u.Complete
+		if size == 0 && u.Total > 0 {
+			layer.total = u.Total
+		}
+		if err == nil {
+			err = p.update()
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case failed:
+		layer.status = pushStatusFailed
+	case uploaded:
+		layer.status = pushStatusPushed
+		layer.current = layer.total
+	default:
+		layer.status = pushStatusExists
+		layer.current = layer.total
+	}
+	return p.update()
+}
+
+// Done prints the summary of the push when output is not a terminal
+func (p *PushDisplay) Done() error {
+	if p.isTerm || len(p.order) == 0 {
+		return nil
+	}
+	_, err := fmt.Fprintf(p.out, "Pushed %s: %s\n", style.Symbol(p.name), p.summary())
+	return err
+}
+
+func (p *PushDisplay) update() error {
+	if p.isTerm {
+		return p.redraw()
+	}
+
+	if now := p.clock(); now.Sub(p.lastReport) >= p.interval {
+		p.lastReport = now
+		_, err := fmt.Fprintf(p.out, "Pushing %s: %s\n", style.Symbol(p.name), p.summary())
+		return err
+	}
+	return nil
+}
+
+func (p *PushDisplay) redraw() error {
+	var lines []string
+	pushed := 0
+	for _, id := range p.order {
+		if isPushedStatus(p.layers[id].status) {
+			pushed++
+			continue
+		}
+		lines = append(lines, p.layerLine(id))
+	}
+	if pushed > 0 {
+		lines = append([]string{fmt.Sprintf("%d of %d layers %s", pushed, p.total, style.Complete("pushed"))}, lines...)
+	}
+
+	var err error
+	p.drawnLines, err = p.redrawLines(p.out, p.drawnLines, lines)
+	return err
+}
+
+func (p *PushDisplay) layerLine(id string) string {
+	layer := p.layers[id]
+	line := fmt.Sprintf("%s: %s", id, colorizePushStatus(layer.status))
+	if layer.total <= 0 || layer.status != pushStatusPushing {
+		return line
+	}
+
+	line += fmt.Sprintf(" %s/%s", humanize.Bytes(uint64(layer.current)), humanize.Bytes(uint64(layer.total)))
+	elapsed := p.clock().Sub(layer.started).Seconds()
+	if elapsed <= 0 || layer.current <= 0 {
+		return line
+	}
+
+	speed := float64(layer.current) / elapsed
+	line += fmt.Sprintf(" %s/s", humanize.Bytes(uint64(speed)))
+	if remaining := layer.total - layer.current; remaining > 0 {
+		eta := time.Duration(float64(remaining) / speed * float64(time.Second))
+		line += fmt.Sprintf(" ETA %s", eta.Round(time.Second))
+	}
+	return line
+}
+
+func (p *PushDisplay) summary() string {
+	var current, total int64
+	pushed := 0
+	for _, id := range p.order {
+		layer := p.layers[id]
+		current += layer.current
+		total += layer.total
+		if isPushedStatus(layer.status) {
+			pushed++
+		}
+	}
+
+	summary := fmt.Sprintf("%d/%d layers pushed", pushed, p.total)
+	if total > 0 {
+		summary += fmt.Sprintf(", %s/%s", humanize.Bytes(uint64(current)), humanize.Bytes(uint64(total)))
+	}
+	return summary + fmt.Sprintf(" (%s elapsed)", p.clock().Sub(p.started).Round(time.Second))
+}
+
+func isPushedStatus(status string) bool {
+	return status == pushStatusPushed || status == pushStatusExists
+}
+
+func colorizePushStatus(status string) string {
+	switch status {
+	case pushStatusPushed, pushStatusExists:
+		return style.Complete(status)
+	case pushStatusFailed:
+		return style.Error(status)
+	default:
+		return style.Working(status)
+	}
+}
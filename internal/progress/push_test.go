@@ -0,0 +1,99 @@
+package progress_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/progress"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestPushDisplay(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "PushDisplay", testPushDisplay, spec.Report(report.Terminal{}))
+}
+
+func testPushDisplay(t *testing.T, when spec.G, it spec.S) {
+	var (
+		out bytes.Buffer
+		now time.Time
+		// tick is how much the clock advances each time it's read
+		tick  time.Duration
+		clock = func() time.Time {
+			now = now.Add(tick)
+			return now
+		}
+	)
+
+	// updates returns the closed channel of the given updates, which the display consumes at once
+	updates := func(sent ...v1.Update) <-chan v1.Update {
+		ch := make(chan v1.Update, len(sent))
+		for _, u := range sent {
+			ch <- u
+		}
+		close(ch)
+		return ch
+	}
+
+	it.Before(func() {
+		out.Reset()
+		now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		tick = time.Second
+	})
+
+	when("output is not a terminal", func() {
+		it("prints a summary at the configured interval and when done", func() {
+			tick = 4 * time.Second
+			subject := progress.NewPushDisplay(&out, false, "some/app", 2, progress.WithClock(clock), progress.WithInterval(10*time.Second))
+
+			h.AssertNil(t, subject.Layer("aaa", 4000000, updates(
+				v1.Update{Complete: 1000000},
+				v1.Update{Complete: 2000000},
+				v1.Update{Complete: 4000000},
+			)))
+			h.AssertNil(t, subject.Layer("bbb", 1000000, updates()))
+			h.AssertNil(t, subject.Done())
+
+			output := out.String()
+			h.AssertContains(t, output, "Pushing 'some/app': 0/2 layers pushed, 1.0 MB/4.0 MB (16s elapsed)\n")
+			h.AssertContains(t, output, "Pushed 'some/app': 2/2 layers pushed, 5.0 MB/5.0 MB (52s elapsed)\n")
+			h.AssertNotContains(t, output, "bbb")
+		})
+	})
+
+	when("output is a terminal", func() {
+		it("redraws a line per layer being pushed with size, speed and ETA", func() {
+			subject := progress.NewPushDisplay(&out, true, "some/app", 2, progress.WithClock(clock))
+
+			h.AssertNil(t, subject.Layer("aaa", 4000000, updates(v1.Update{Complete: 1000000})))
+
+			output := out.String()
+			h.AssertContains(t, output, "\x1b[2Kaaa: Pushing 0 B/4.0 MB\n")
+			h.AssertContains(t, output, "\x1b[1A\x1b[2Kaaa: Pushing 1.0 MB/4.0 MB 500 kB/s ETA 6s\n")
+			h.AssertContains(t, output, "\x1b[1A\x1b[2K1 of 2 layers pushed\n")
+		})
+
+		it("shows the layers the registry already has as pushed", func() {
+			subject := progress.NewPushDisplay(&out, true, "some/app", 1, progress.WithClock(clock))
+
+			h.AssertNil(t, subject.Layer("aaa", 4000000, updates()))
+			h.AssertContains(t, out.String(), "\x1b[1A\x1b[2K1 of 1 layers pushed\n")
+		})
+
+		it("shows the layers that failed to push", func() {
+			subject := progress.NewPushDisplay(&out, true, "some/app", 1, progress.WithClock(clock))
+
+			h.AssertNil(t, subject.Layer("aaa", 4000000, updates(v1.Update{Complete: 1000}, v1.Update{Error: errors.New("connection reset")})))
+			h.AssertContains(t, out.String(), "\x1b[1A\x1b[2Kaaa: Push failed\n")
+		})
+	})
+}
@@ -0,0 +1,228 @@
+// Package provenance generates SLSA provenance of builds, in-toto statements describing how an image was produced:
+// from which source, with which builder, run image and buildpacks, and with which parameters.
+package provenance
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/project/v02"
+)
+
+const (
+	// StatementType is the type of in-toto statements
+	StatementType = "https://in-toto.io/Statement/v1"
+
+	// PredicateType is the type of SLSA provenance predicates
+	PredicateType = "https://slsa.dev/provenance/v1"
+
+	// BuildType identifies builds by pack, which parameters are described by ExternalParameters
+	BuildType = "https://buildpacks.io/pack/build/v1"
+
+	// BuilderID identifies pack as the platform running builds
+	BuilderID = "https://buildpacks.io/pack"
+
+	// PayloadType is the type of in-toto statements in DSSE envelopes
+	PayloadType = "application/vnd.in-toto+json"
+)
+
+type Statement struct {
+	Type          string     `json:"_type"`
+	Subject       []Resource `json:"subject"`
+	PredicateType string     `json:"predicateType"`
+	Predicate     Predicate  `json:"predicate"`
+}
+
+// Resource describes an artifact by name or URI, and digest
+type Resource struct {
+	Name        string            `json:"name,omitempty"`
+	URI         string            `json:"uri,omitempty"`
+	Digest      map[string]string `json:"digest,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type Predicate struct {
+	BuildDefinition BuildDefinition `json:"buildDefinition"`
+	RunDetails      RunDetails      `json:"runDetails"`
+}
+
+type BuildDefinition struct {
+	BuildType            string             `json:"buildType"`
+	ExternalParameters   ExternalParameters `json:"externalParameters"`
+	ResolvedDependencies []Resource         `json:"resolvedDependencies"`
+}
+
+// ExternalParameters are the parameters of a build chosen by whoever ran it
+type ExternalParameters struct {
+	Image    string `json:"image"`
+	Builder  string `json:"builder"`
+	RunImage string `json:"runImage,omitempty"`
+	Publish  bool   `json:"publish"`
+
+	// Flags set on the command line, but env flags whose values are only listed by name in Env
+	Flags map[string]string `json:"flags,omitempty"`
+
+	// Names of the environment variables set for buildpacks. Values are left out as they may be secrets.
+	Env []string `json:"env,omitempty"`
+}
+
+type RunDetails struct {
+	Builder  Builder  `json:"builder"`
+	Metadata Metadata `json:"metadata"`
+}
+
+type Builder struct {
+	ID      string            `json:"id"`
+	Version map[string]string `json:"version,omitempty"`
+}
+
+type Metadata struct {
+	StartedOn  string `json:"startedOn,omitempty"`
+	FinishedOn string `json:"finishedOn,omitempty"`
+}
+
+// Invocation describes how a build was run
+type Invocation struct {
+	// Directory of the app
+	AppPath string
+
+	// Whether the image was published
+	Publish bool
+
+	// Run image requested, if any
+	RunImage string
+
+	// Flags set on the command line
+	Flags map[string]string
+
+	// Environment variables set for buildpacks
+	Env map[string]string
+
+	// Version of pack
+	Version string
+}
+
+// Generate returns the provenance of a completed build
+func Generate(summary client.BuildSummary, invocation Invocation) Statement {
+	var env []string
+	for key := range invocation.Env {
+		env = append(env, key)
+	}
+	sort.Strings(env)
+
+	version := map[string]string{}
+	if invocation.Version != "" {
+		version["pack"] = invocation.Version
+	}
+	if summary.LifecycleVersion != "" {
+		version["lifecycle"] = summary.LifecycleVersion
+	}
+
+	metadata := Metadata{}
+	if !summary.Started.IsZero() {
+		metadata.StartedOn = summary.Started.UTC().Format(time.RFC3339)
+		metadata.FinishedOn = summary.Started.Add(summary.Duration).UTC().Format(time.RFC3339)
+	}
+
+	return Statement{
+		Type:          StatementType,
+		Subject:       []Resource{{Name: repositoryName(summary.Image), Digest: digestSet(summary.Digest)}},
+		PredicateType: PredicateType,
+		Predicate: Predicate{
+			BuildDefinition: BuildDefinition{
+				BuildType: BuildType,
+				ExternalParameters: ExternalParameters{
+					Image:    summary.Image,
+					Builder:  summary.Builder,
+					RunImage: invocation.RunImage,
+					Publish:  invocation.Publish,
+					Flags:    invocation.Flags,
+					Env:      env,
+				},
+				ResolvedDependencies: resolvedDependencies(summary, invocation.AppPath),
+			},
+			RunDetails: RunDetails{
+				Builder:  Builder{ID: BuilderID, Version: version},
+				Metadata: metadata,
+			},
+		},
+	}
+}
+
+func resolvedDependencies(summary client.BuildSummary, appPath string) []Resource {
+	dependencies := []Resource{source(appPath)}
+	if summary.Builder != "" {
+		dependencies = append(dependencies, Resource{Name: "builder", URI: summary.Builder, Digest: digestSet(summary.BuilderDigest)})
+	}
+	if summary.RunImage != "" {
+		dependencies = append(dependencies, Resource{Name: "run-image", URI: summary.RunImage, Digest: digestSet(summary.RunImageDigest)})
+	}
+	for _, bp := range summary.Buildpacks {
+		dependencies = append(dependencies, Resource{
+			Name:   "buildpack",
+			URI:    fmt.Sprintf("urn:cnb:builder:%s@%s", bp.ID, bp.Version),
+			Digest: digestSet(bp.Digest),
+		})
+	}
+	return dependencies
+}
+
+// source describes the app by its git commit when it's a git repository, or by its path otherwise
+func source(appPath string) Resource {
+	if absPath, err := filepath.Abs(appPath); err == nil {
+		appPath = absPath
+	}
+
+	uri := "file://" + filepath.ToSlash(appPath)
+	if git := v02.GitMetadata(appPath); git != nil {
+		commit, _ := git.Version["commit"].(string)
+		if remote, _ := git.Metadata["url"].(string); remote != "" {
+			uri = "git+" + remote
+		}
+		return Resource{Name: "source", URI: uri, Digest: map[string]string{"gitCommit": commit}}
+	}
+	return Resource{Name: "source", URI: uri}
+}
+
+// digestSet returns the digest set of a digest such as sha256:<hex>, or nil when the digest is empty
+func digestSet(digest string) map[string]string {
+	algorithm, value, ok := strings.Cut(digest, ":")
+	if !ok || value == "" {
+		return nil
+	}
+	return map[string]string{algorithm: value}
+}
+
+// repositoryName returns the name of an image without its tag or digest
+func repositoryName(imageName string) string {
+	ref, err := name.ParseReference(imageName, name.WeakValidation)
+	if err != nil {
+		return imageName
+	}
+	return ref.Context().Name()
+}
+
+// Envelope returns the unsigned DSSE envelope of a statement, the format of attestations attached to images
+func Envelope(statement Statement) ([]byte, error) {
+	payload, err := json.Marshal(statement)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(struct {
+		PayloadType string        `json:"payloadType"`
+		Payload     string        `json:"payload"`
+		Signatures  []interface{} `json:"signatures"`
+	}{
+		PayloadType: PayloadType,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures:  []interface{}{},
+	})
+}
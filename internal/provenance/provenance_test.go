@@ -0,0 +1,139 @@
+package provenance_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/provenance"
+	"github.com/buildpacks/pack/pkg/client"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestProvenance(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Provenance", testProvenance, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testProvenance(t *testing.T, when spec.G, it spec.S) {
+	started := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	summary := client.BuildSummary{
+		Image:            "registry.example.com/team/app:latest",
+		Digest:           "sha256:aaaa",
+		Duration:         90 * time.Second,
+		Started:          started,
+		Builder:          "registry.example.com/builder:jammy",
+		BuilderDigest:    "sha256:bbbb",
+		RunImage:         "registry.example.com/run:jammy",
+		RunImageDigest:   "sha256:cccc",
+		LifecycleVersion: "0.20.0",
+		Buildpacks: []client.BuildpackSummary{
+			{ID: "example/go", Version: "1.2.3", Digest: "sha256:dddd"},
+			{ID: "example/procfile", Version: "4.5.6"},
+		},
+	}
+
+	when("#Generate", func() {
+		it("describes the image, its dependencies and how it was built", func() {
+			appPath := t.TempDir()
+
+			statement := provenance.Generate(summary, provenance.Invocation{
+				AppPath: appPath,
+				Publish: true,
+				Flags:   map[string]string{"builder": "registry.example.com/builder:jammy", "publish": "true"},
+				Env:     map[string]string{"TOKEN": "secret", "BP_GO_VERSION": "1.22"},
+				Version: "0.34.0",
+			})
+
+			h.AssertEq(t, statement.Type, provenance.StatementType)
+			h.AssertEq(t, statement.PredicateType, provenance.PredicateType)
+			h.AssertEq(t, statement.Subject, []provenance.Resource{
+				{Name: "registry.example.com/team/app", Digest: map[string]string{"sha256": "aaaa"}},
+			})
+
+			definition := statement.Predicate.BuildDefinition
+			h.AssertEq(t, definition.BuildType, provenance.BuildType)
+			h.AssertEq(t, definition.ExternalParameters, provenance.ExternalParameters{
+				Image:   "registry.example.com/team/app:latest",
+				Builder: "registry.example.com/builder:jammy",
+				Publish: true,
+				Flags:   map[string]string{"builder": "registry.example.com/builder:jammy", "publish": "true"},
+				Env:     []string{"BP_GO_VERSION", "TOKEN"},
+			})
+			h.AssertEq(t, definition.ResolvedDependencies, []provenance.Resource{
+				{Name: "source", URI: "file://" + filepath.ToSlash(appPath)},
+				{Name: "builder", URI: "registry.example.com/builder:jammy", Digest: map[string]string{"sha256": "bbbb"}},
+				{Name: "run-image", URI: "registry.example.com/run:jammy", Digest: map[string]string{"sha256": "cccc"}},
+				{Name: "buildpack", URI: "urn:cnb:builder:example/go@1.2.3", Digest: map[string]string{"sha256": "dddd"}},
+				{Name: "buildpack", URI: "urn:cnb:builder:example/procfile@4.5.6"},
+			})
+
+			details := statement.Predicate.RunDetails
+			h.AssertEq(t, details.Builder, provenance.Builder{
+				ID:      provenance.BuilderID,
+				Version: map[string]string{"pack": "0.34.0", "lifecycle": "0.20.0"},
+			})
+			h.AssertEq(t, details.Metadata, provenance.Metadata{
+				StartedOn:  "2024-05-01T10:00:00Z",
+				FinishedOn: "2024-05-01T10:01:30Z",
+			})
+		})
+
+		it("describes a git source by its commit", func() {
+			appPath := t.TempDir()
+			repo, err := git.PlainInit(appPath, false)
+			h.AssertNil(t, err)
+			_, err = repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{"https://github.com/example/app"}})
+			h.AssertNil(t, err)
+			worktree, err := repo.Worktree()
+			h.AssertNil(t, err)
+			commit, err := worktree.Commit("initial", &git.CommitOptions{
+				AllowEmptyCommits: true,
+				Author:            &object.Signature{Name: "Example", Email: "example@example.com", When: started},
+			})
+			h.AssertNil(t, err)
+
+			statement := provenance.Generate(summary, provenance.Invocation{AppPath: appPath})
+
+			h.AssertEq(t, statement.Predicate.BuildDefinition.ResolvedDependencies[0], provenance.Resource{
+				Name:   "source",
+				URI:    "git+https://github.com/example/app",
+				Digest: map[string]string{"gitCommit": commit.String()},
+			})
+		})
+	})
+
+	when("#Envelope", func() {
+		it("encodes the statement as the payload of a DSSE envelope", func() {
+			statement := provenance.Generate(summary, provenance.Invocation{AppPath: t.TempDir()})
+
+			contents, err := provenance.Envelope(statement)
+			h.AssertNil(t, err)
+
+			var envelope struct {
+				PayloadType string        `json:"payloadType"`
+				Payload     string        `json:"payload"`
+				Signatures  []interface{} `json:"signatures"`
+			}
+			h.AssertNil(t, json.Unmarshal(contents, &envelope))
+			h.AssertEq(t, envelope.PayloadType, provenance.PayloadType)
+			h.AssertEq(t, len(envelope.Signatures), 0)
+
+			payload, err := base64.StdEncoding.DecodeString(envelope.Payload)
+			h.AssertNil(t, err)
+			var decoded provenance.Statement
+			h.AssertNil(t, json.Unmarshal(payload, &decoded))
+			h.AssertEq(t, decoded, statement)
+		})
+	})
+}
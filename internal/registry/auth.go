@@ -0,0 +1,182 @@
+package registry
+
+import (
+	"bufio"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/errcode"
+)
+
+// tokenUsername is the username sent with a token, which git hosts such as GitHub ignore as the token identifies the user
+const tokenUsername = "pack"
+
+// WithToken authenticates to a registry served over HTTPS with the given token, e.g. a personal access token of a
+// private GitHub repository
+func WithToken(token string) CacheOption {
+	return func(s *CacheSettings) {
+		s.token = token
+	}
+}
+
+// auth is how the cache authenticates to the registry when cloning and pulling it:
+//   - registries served over SSH authenticate with the keys of the SSH agent
+//   - registries served over HTTPS authenticate with the token of the cache, else with the credentials in the URL,
+//     else with the credentials of the registry host in the netrc file
+//
+// It's nil when there is nothing to authenticate with, for public registries.
+func (r *GitCache) auth() (transport.AuthMethod, error) {
+	return r.authFor(r.url)
+}
+
+// authFor is how the cache authenticates to remote, the registry or one of its mirrors, like auth. The token of the
+// cache is only sent to the host of the registry.
+func (r *GitCache) authFor(remote *url.URL) (transport.AuthMethod, error) {
+	switch remote.Scheme {
+	case "ssh":
+		username := remote.User.Username()
+		if username == "" {
+			username = "git"
+		}
+		auth, err := gitssh.NewSSHAgentAuth(username)
+		if err != nil {
+			return nil, errors.Wrap(err, "connecting to the SSH agent")
+		}
+		return auth, nil
+	case "http", "https":
+		username := remote.User.Username()
+		if r.token != "" && remote.Host == r.url.Host {
+			if username == "" {
+				username = tokenUsername
+			}
+			return &githttp.BasicAuth{Username: username, Password: r.token}, nil
+		}
+		if username != "" {
+			// go-git authenticates with the credentials in the URL
+			return nil, nil
+		}
+		if login, password, ok := netrcCredentials(remote.Hostname()); ok {
+			return &githttp.BasicAuth{Username: login, Password: password}, nil
+		}
+	}
+	return nil, nil
+}
+
+// isAuthError is true when the registry refused the cache for lack of credentials, or of valid ones
+func isAuthError(err error) bool {
+	return errors.Is(err, transport.ErrAuthenticationRequired) || errors.Is(err, transport.ErrAuthorizationFailed)
+}
+
+// authError explains how to authenticate to the registry when it refused the cache
+func (r *GitCache) authError(err error) error {
+	if !isAuthError(err) {
+		return err
+	}
+	return authRequiredError(r.url, err)
+}
+
+// authRequiredError explains how to authenticate to a registry that refused a cache
+func authRequiredError(registryURL *url.URL, err error) error {
+	return errcode.Errorf(errcode.RegistryAuthRequired,
+		"authenticating to registry %s, set a token with the PACK_REGISTRY_TOKEN_<NAME> environment variable of the registry (e.g. PACK_REGISTRY_TOKEN_MY_REGISTRY for my-registry) "+
+			"or the token of the registry in your config file, "+
+			"or add its host to your netrc file: %s", style.Symbol(registryURL.Redacted()), err)
+}
+
+// netrcCredentials are the login and password of host in the netrc file, in $NETRC or the home directory
+func netrcCredentials(host string) (string, string, bool) {
+	path := os.Getenv("NETRC")
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", "", false
+		}
+		name := ".netrc"
+		if runtime.GOOS == "windows" {
+			name = "_netrc"
+		}
+		path = filepath.Join(home, name)
+	}
+
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return "", "", false
+	}
+	defer file.Close()
+
+	var (
+		fields  []string
+		scanner = bufio.NewScanner(file)
+	)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields = append(fields, strings.Fields(line)...)
+	}
+
+	return parseNetrc(fields, host)
+}
+
+// parseNetrc finds the login and password of host in the fields of a netrc file, falling back to its default entry
+func parseNetrc(fields []string, host string) (string, string, bool) {
+	type machine struct {
+		login, password string
+	}
+
+	var (
+		current  *machine
+		matched  *machine
+		fallback *machine
+	)
+	for i := 0; i < len(fields); i++ {
+		switch fields[i] {
+		case "machine":
+			current = nil
+			if i+1 < len(fields) {
+				i++
+				if fields[i] == host && matched == nil {
+					matched = &machine{}
+					current = matched
+				}
+			}
+		case "default":
+			current = nil
+			if fallback == nil {
+				fallback = &machine{}
+				current = fallback
+			}
+		case "login", "password", "account":
+			if i+1 >= len(fields) {
+				break
+			}
+			i++
+			if current == nil {
+				continue
+			}
+			switch fields[i-1] {
+			case "login":
+				current.login = fields[i]
+			case "password":
+				current.password = fields[i]
+			}
+		}
+	}
+
+	for _, m := range []*machine{matched, fallback} {
+		if m != nil && m.password != "" {
+			return m.login, m.password, true
+		}
+	}
+	return "", "", false
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/errcode"
 )
 
 // Buildpack contains information about a buildpack stored in a Registry
@@ -17,6 +18,11 @@ type Buildpack struct {
 	Version   string `json:"version"`
 	Yanked    bool   `json:"yanked"`
 	Address   string `json:"addr,omitempty"`
+
+	// Description, Licenses and Stacks are optional, so that users can check a buildpack before pulling its image
+	Description string   `json:"description,omitempty"`
+	Licenses    []string `json:"licenses,omitempty"`
+	Stacks      []string `json:"stacks,omitempty"`
 }
 
 // Validate that a buildpack reference contains required information
@@ -36,9 +42,9 @@ func Validate(b Buildpack) error {
 func ParseNamespaceName(id string) (ns string, name string, err error) {
 	parts := strings.Split(id, "/")
 	if len(parts) < 2 {
-		return "", "", fmt.Errorf("invalid id %s does not contain a namespace", style.Symbol(id))
+		return "", "", errcode.Errorf(errcode.RegistryInvalidID, "invalid id %s does not contain a namespace", style.Symbol(id))
 	} else if len(parts) > 2 {
-		return "", "", fmt.Errorf("invalid id %s contains unexpected characters", style.Symbol(id))
+		return "", "", errcode.Errorf(errcode.RegistryInvalidID, "invalid id %s contains unexpected characters", style.Symbol(id))
 	}
 
 	return parts[0], parts[1], nil
@@ -0,0 +1,376 @@
+package registry
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/mod/semver"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/buildpack"
+	"github.com/buildpacks/pack/pkg/errcode"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+// httpIndexSchemePrefix prefixes the scheme of the URLs of registries served over plain HTTP(S), e.g.
+// index+https://mirror.example.com/registry-index, to tell them apart from git repositories served over HTTP(S)
+const httpIndexSchemePrefix = "index+"
+
+// Cache is a RegistryCache, a local copy of the index of a buildpack registry that buildpacks are located in
+type Cache interface {
+	// URL returns the URL of the registry
+	URL() string
+
+	// Dir returns the directory the index is cached in, laid out like the index of the registry
+	Dir() string
+
+	// LocateBuildpack refreshes the cache, unless offline, and returns the buildpack of the registry id bp
+	LocateBuildpack(bp string) (Buildpack, error)
+
+	// LocateBuildpacks refreshes the cache once, unless offline, and locates the buildpack of each registry id in bps,
+	// in order. A buildpack that fails to be located has the error in its result rather than failing the others, the
+	// error returned is only for failing to refresh the cache.
+	LocateBuildpacks(bps []string) ([]LocatedBuildpack, error)
+
+	// IDs returns the ids of the buildpacks in the cache, sorted, without refreshing it
+	IDs() ([]string, error)
+
+	// Versions returns the versions of a buildpack in the cache that weren't yanked, from the highest, without
+	// refreshing it
+	Versions(id string) ([]string, error)
+
+	// ListBuildpacks calls fn with the id and entry of each buildpack in the cache, sorted by id, without refreshing
+	// it. An error returned by fn stops the listing and is returned as is.
+	ListBuildpacks(fn func(id string, entry Entry) error) error
+
+	// Refresh updates the cache from the registry
+	Refresh() error
+}
+
+// LocatedBuildpack is the result of locating the buildpack of a registry id with LocateBuildpacks
+type LocatedBuildpack struct {
+	// ID is the registry id the buildpack was located by, e.g. example/node@^1.2
+	ID        string
+	Buildpack Buildpack
+
+	// Err is the error locating the buildpack, if any
+	Err error
+}
+
+// CacheSettings configure how a cache refreshes the index of its registry
+type CacheSettings struct {
+	// Offline uses the existing cache without refreshing it, so that buildpacks are located without a network
+	Offline bool
+
+	// RefreshInterval is how long after a refresh Refresh doesn't fetch the registry again, it always does when 0
+	RefreshInterval time.Duration
+
+	// ForceRefresh fetches the registry even when it was refreshed within the RefreshInterval
+	ForceRefresh bool
+
+	// AllowYanked locates yanked versions of buildpacks, which are skipped otherwise
+	AllowYanked bool
+
+	// IncludePrereleases locates pre-release versions of buildpacks as their highest version, which are skipped
+	// otherwise. Pre-releases requested explicitly, or in a range that includes a pre-release, are always located.
+	IncludePrereleases bool
+
+	shallow bool
+	token   string
+	mirrors []string
+	proxy   string
+}
+
+// CacheOption configures a Cache
+type CacheOption func(*CacheSettings)
+
+// WithOffline locates buildpacks in the existing cache without refreshing it
+func WithOffline(offline bool) CacheOption {
+	return func(s *CacheSettings) {
+		s.Offline = offline
+	}
+}
+
+// WithRefreshInterval doesn't refresh the cache again within interval of a refresh
+func WithRefreshInterval(interval time.Duration) CacheOption {
+	return func(s *CacheSettings) {
+		s.RefreshInterval = interval
+	}
+}
+
+// WithForceRefresh refreshes the cache even within the refresh interval
+func WithForceRefresh(forceRefresh bool) CacheOption {
+	return func(s *CacheSettings) {
+		s.ForceRefresh = forceRefresh
+	}
+}
+
+// WithAllowYanked locates yanked versions of buildpacks rather than skipping them
+func WithAllowYanked(allowYanked bool) CacheOption {
+	return func(s *CacheSettings) {
+		s.AllowYanked = allowYanked
+	}
+}
+
+// WithIncludePrereleases locates pre-release versions of buildpacks as their highest version rather than skipping them
+func WithIncludePrereleases(includePrereleases bool) CacheOption {
+	return func(s *CacheSettings) {
+		s.IncludePrereleases = includePrereleases
+	}
+}
+
+// NewCache creates the cache of a registry, selected by the scheme of its URL: registries served over plain HTTP(S)
+// have URLs such as index+https://mirror.example.com/registry-index, indexes in a local directory have URLs such as
+// file:///mnt/registry-index, all others are git repositories
+func NewCache(logger logging.Logger, home, registryURL string, ops ...CacheOption) (Cache, error) {
+	if isFileURL(registryURL) {
+		cache, err := NewDirCache(logger, registryURL, ops...)
+		if err != nil {
+			return nil, err
+		}
+		return &cache, nil
+	}
+
+	if strings.HasPrefix(registryURL, httpIndexSchemePrefix) {
+		cache, err := NewHTTPCache(logger, home, registryURL, ops...)
+		if err != nil {
+			return nil, err
+		}
+		return &cache, nil
+	}
+
+	cache, err := NewRegistryCache(logger, home, registryURL, ops...)
+	if err != nil {
+		return nil, err
+	}
+	return &cache, nil
+}
+
+// cacheRoot is the directory in home that the index of the registry at registryURL is cached in
+func cacheRoot(home string, registryURL *url.URL) string {
+	key := sha256.New()
+	key.Write([]byte(registryURL.String()))
+	return filepath.Join(home, fmt.Sprintf("%s-%s", defaultRegistryDir, hex.EncodeToString(key.Sum(nil))))
+}
+
+// RequireCache fails when the cache wasn't created yet, as pack is offline, else warns that it may be out of date
+func RequireCache(logger logging.Logger, cache Cache) error {
+	if _, ok := cache.(*MemoryCache); ok {
+		return nil
+	}
+	if _, err := os.Stat(cache.Dir()); err != nil {
+		return errors.Errorf("registry cache for %s doesn't exist yet, it's created by running without --offline", style.Symbol(cache.URL()))
+	}
+	logger.Warn("Using the registry cache without refreshing it as pack is offline, buildpacks located may be out of date")
+	return nil
+}
+
+// locateBuildpack finds the buildpack of the registry id bp in the index in root: its highest version when bp has none,
+// and its highest version in the range when bp has a semver range, e.g. example/node@^1.2. Yanked versions are skipped,
+// and fail to be located when requested explicitly, unless settings.AllowYanked. Pre-releases aren't the highest version
+// unless settings.IncludePrereleases.
+func locateBuildpack(logger logging.Logger, root, bp string, settings CacheSettings) (Buildpack, error) {
+	ns, name, version, err := buildpack.ParseRegistryID(bp)
+	if err != nil {
+		return Buildpack{}, errors.Wrap(err, "parsing buildpacks registry id")
+	}
+
+	entry, err := readEntry(root, ns, name)
+	if err != nil {
+		return Buildpack{}, errors.Wrap(err, "reading entry")
+	}
+	return locateInEntry(logger, entry, bp, ns, name, version, settings)
+}
+
+// locateInEntry finds the buildpack of the registry id bp, parsed as ns, name and version, in its entry
+func locateInEntry(logger logging.Logger, entry Entry, bp, ns, name, version string, settings CacheSettings) (Buildpack, error) {
+	if len(entry.Buildpacks) == 0 {
+		return Buildpack{}, errcode.Errorf(errcode.RegistryEntryMissing, "no entries for buildpack: %s", bp)
+	}
+
+	id := ns + "/" + name
+	located, found := Buildpack{}, false
+	switch {
+	case version == "":
+		if located, found = highestVersion(entry, settings.AllowYanked, settings.IncludePrereleases); found {
+			break
+		}
+		if _, found = highestVersion(entry, settings.AllowYanked, true); found {
+			return Buildpack{}, errcode.Errorf(errcode.RegistryEntryMissing,
+				"buildpack %s only has pre-release versions, request one or use --include-prereleases to locate the highest", style.Symbol(id))
+		}
+		return Buildpack{}, errcode.Errorf(errcode.RegistryEntryYanked,
+			"all versions of buildpack %s were yanked, use --allow-yanked to locate its highest version anyway", style.Symbol(id))
+	default:
+		for _, bpIndex := range entry.Buildpacks {
+			if bpIndex.Version == version {
+				located, found = bpIndex, true
+				break
+			}
+		}
+		if found && located.Yanked && !settings.AllowYanked {
+			return Buildpack{}, errcode.Errorf(errcode.RegistryEntryYanked,
+				"version %s of buildpack %s was yanked, pick another version or use --allow-yanked to locate it anyway", style.Symbol(version), style.Symbol(id))
+		}
+		if !found && isVersionRange(version) {
+			var err error
+			if located, err = resolveVersionRange(entry, id, version, settings.AllowYanked); err != nil {
+				return Buildpack{}, err
+			}
+			found = true
+		}
+		if !found {
+			return Buildpack{}, errcode.Errorf(errcode.RegistryEntryMissing, "could not find version for buildpack: %s", bp)
+		}
+	}
+
+	if located.Yanked {
+		logger.Warnf("Version %s of buildpack %s was yanked", style.Symbol(located.Version), style.Symbol(id))
+	}
+	return located, Validate(located)
+}
+
+// locateBuildpacks finds the buildpack of each registry id in bps in the index in root, as locateBuildpack does
+func locateBuildpacks(logger logging.Logger, root string, bps []string, settings CacheSettings) []LocatedBuildpack {
+	located := make([]LocatedBuildpack, 0, len(bps))
+	for _, bp := range bps {
+		found, err := locateBuildpack(logger, root, bp, settings)
+		located = append(located, LocatedBuildpack{ID: bp, Buildpack: found, Err: err})
+	}
+	return located
+}
+
+// listIDs returns the ids of the buildpacks in the index in root, sorted, and none when root doesn't exist
+func listIDs(root string) ([]string, error) {
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	var ids []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Dir(path) == root {
+			return nil
+		}
+
+		ns, name, found := strings.Cut(d.Name(), "_")
+		if found && validateField("namespace", ns) == nil && validateField("name", name) == nil {
+			ids = append(ids, ns+"/"+name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "listing buildpacks of %s", style.Symbol(root))
+	}
+
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// listVersions returns the versions of a buildpack in the index in root that weren't yanked, from the highest
+func listVersions(root, id string) ([]string, error) {
+	ns, name, err := ParseNamespaceName(id)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := readEntry(root, ns, name)
+	if err != nil {
+		return nil, err
+	}
+	return entryVersions(entry), nil
+}
+
+// entryVersions returns the versions of the buildpacks of entry that weren't yanked, from the highest
+func entryVersions(entry Entry) []string {
+	var versions []string
+	for _, bp := range entry.Buildpacks {
+		if !bp.Yanked {
+			versions = append(versions, bp.Version)
+		}
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		return semver.Compare("v"+versions[i], "v"+versions[j]) > 0
+	})
+	return versions
+}
+
+// listEntries calls fn with the id and entry of each buildpack in the index in root, sorted by id, reading one entry
+// at a time so that the whole index is never held in memory. An error returned by fn stops the listing and is
+// returned as is.
+func listEntries(root string, fn func(id string, entry Entry) error) error {
+	ids, err := listIDs(root)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		ns, name, err := ParseNamespaceName(id)
+		if err != nil {
+			return err
+		}
+		entry, err := readEntry(root, ns, name)
+		if err != nil {
+			return err
+		}
+		if err := fn(id, entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readEntry reads the entry of a buildpack in the index in root
+func readEntry(root, ns, name string) (Entry, error) {
+	index, err := IndexPath(root, ns, name)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	if _, err := os.Stat(index); err != nil {
+		return Entry{}, errors.Wrapf(err, "finding buildpack: %s/%s", ns, name)
+	}
+
+	file, err := os.Open(filepath.Clean(index))
+	if err != nil {
+		return Entry{}, errors.Wrapf(err, "opening index for buildpack: %s/%s", ns, name)
+	}
+	defer file.Close()
+
+	entry := Entry{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var bp Buildpack
+		err = json.Unmarshal([]byte(scanner.Text()), &bp)
+		if err != nil {
+			return Entry{}, errors.Wrapf(err, "parsing index for buildpack: %s/%s", ns, name)
+		}
+
+		entry.Buildpacks = append(entry.Buildpacks, bp)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return entry, errors.Wrapf(err, "reading index for buildpack: %s/%s", ns, name)
+	}
+
+	return entry, nil
+}
@@ -0,0 +1,122 @@
+package registry
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+// fileIndexScheme is the scheme of the URLs of registry indexes in a local directory, e.g. file:///mnt/registry-index
+const fileIndexScheme = "file"
+
+// DirCache is a RegistryCache of a registry index in a local directory, e.g. a checkout of an index that buildpack
+// authors test changes in before pushing them, or a copy synced to an air-gapped machine. The index is read directly
+// from the directory rather than cloned into the pack home, so there is nothing to refresh.
+//
+// Its URL is the file URL of the directory, e.g. file:///mnt/registry-index
+type DirCache struct {
+	logger logging.Logger
+	url    *url.URL
+	Root   string
+
+	CacheSettings
+}
+
+// NewDirCache creates a new cache of the registry index in the directory of a file URL
+func NewDirCache(logger logging.Logger, registryURL string, ops ...CacheOption) (DirCache, error) {
+	normalizedURL, err := url.Parse(registryURL)
+	if err != nil {
+		return DirCache{}, errors.Wrapf(err, "parsing registry url %s", registryURL)
+	}
+	if normalizedURL.Scheme != fileIndexScheme {
+		return DirCache{}, errors.Errorf("registry url %s must be a file url", style.Symbol(registryURL))
+	}
+	if normalizedURL.Host != "" && normalizedURL.Host != "localhost" {
+		return DirCache{}, errors.Errorf("registry url %s must be a file url of a local directory, e.g. file:///path/to/index", style.Symbol(registryURL))
+	}
+
+	cache := DirCache{
+		logger: logger,
+		url:    normalizedURL,
+		Root:   FileURLPath(normalizedURL),
+	}
+	for _, op := range ops {
+		op(&cache.CacheSettings)
+	}
+	return cache, nil
+}
+
+// FileURLPath is the path on the filesystem of a file URL, e.g. /mnt/registry-index for file:///mnt/registry-index
+// and C:\registry-index for file:///C:/registry-index on Windows
+func FileURLPath(fileURL *url.URL) string {
+	path := fileURL.Path
+	if runtime.GOOS == "windows" && len(path) > 2 && path[0] == '/' && path[2] == ':' {
+		path = path[1:]
+	}
+	return filepath.FromSlash(path)
+}
+
+// URL returns the URL of the registry
+func (r *DirCache) URL() string {
+	return r.url.String()
+}
+
+// Dir returns the directory of the index
+func (r *DirCache) Dir() string {
+	return r.Root
+}
+
+// LocateBuildpack returns the buildpack from the index in the directory
+func (r *DirCache) LocateBuildpack(bp string) (Buildpack, error) {
+	if err := r.Refresh(); err != nil {
+		return Buildpack{}, err
+	}
+	return locateBuildpack(r.logger, r.Root, bp, r.CacheSettings)
+}
+
+// LocateBuildpacks returns the buildpacks from the index in the directory
+func (r *DirCache) LocateBuildpacks(bps []string) ([]LocatedBuildpack, error) {
+	if err := r.Refresh(); err != nil {
+		return nil, err
+	}
+	return locateBuildpacks(r.logger, r.Root, bps, r.CacheSettings), nil
+}
+
+// IDs returns the ids of the buildpacks in the index, sorted
+func (r *DirCache) IDs() ([]string, error) {
+	return listIDs(r.Root)
+}
+
+// Versions returns the versions of a buildpack in the index that weren't yanked, from the highest
+func (r *DirCache) Versions(id string) ([]string, error) {
+	return listVersions(r.Root, id)
+}
+
+// ListBuildpacks calls fn with the id and entry of each buildpack in the index, sorted by id
+func (r *DirCache) ListBuildpacks(fn func(id string, entry Entry) error) error {
+	return listEntries(r.Root, fn)
+}
+
+// Refresh only checks that the directory of the index exists, as the index is read from it directly
+func (r *DirCache) Refresh() error {
+	info, err := os.Stat(r.Root)
+	if err != nil {
+		return errors.Wrapf(err, "reading registry index %s", style.Symbol(r.Root))
+	}
+	if !info.IsDir() {
+		return errors.Errorf("registry index %s must be a directory", style.Symbol(r.Root))
+	}
+	return nil
+}
+
+// isFileURL is true when registryURL is the file URL of a registry index in a local directory
+func isFileURL(registryURL string) bool {
+	return strings.HasPrefix(registryURL, fileIndexScheme+"://")
+}
@@ -0,0 +1,96 @@
+package registry_test
+
+import (
+	"bytes"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/registry"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestDirCache(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "DirCache", testDirCache, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testDirCache(t *testing.T, when spec.G, it spec.S) {
+	var (
+		outBuf bytes.Buffer
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		index  string
+	)
+
+	fileURL := func(path string) string {
+		abs, err := filepath.Abs(path)
+		h.AssertNil(t, err)
+		return (&url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}).String()
+	}
+
+	it.Before(func() {
+		index = fileURL(filepath.Join("..", "..", "testdata", "registry"))
+	})
+
+	when("#NewCache", func() {
+		it("reads registries with file urls from the directory", func() {
+			home := t.TempDir()
+			cache, err := registry.NewCache(logger, home, index)
+			h.AssertNil(t, err)
+
+			dirCache, ok := cache.(*registry.DirCache)
+			h.AssertTrue(t, ok)
+			abs, err := filepath.Abs(filepath.Join("..", "..", "testdata", "registry"))
+			h.AssertNil(t, err)
+			h.AssertEq(t, dirCache.Dir(), abs)
+			h.AssertEq(t, dirCache.URL(), index)
+		})
+	})
+
+	when("#NewDirCache", func() {
+		it("fails for a file url of another host", func() {
+			_, err := registry.NewDirCache(logger, "file://fileserver/registry-index")
+			h.AssertError(t, err, "must be a file url of a local directory")
+		})
+	})
+
+	when("#LocateBuildpack", func() {
+		it("locates the buildpack in the directory", func() {
+			cache, err := registry.NewDirCache(logger, index)
+			h.AssertNil(t, err)
+
+			bp, err := cache.LocateBuildpack("example/foo")
+			h.AssertNil(t, err)
+			h.AssertEq(t, bp.Version, "1.2.0")
+
+			bp, err = cache.LocateBuildpack("example/java@1.0.0")
+			h.AssertNil(t, err)
+			h.AssertEq(t, bp.Name, "java")
+		})
+
+		it("fails when the directory doesn't exist", func() {
+			cache, err := registry.NewDirCache(logger, fileURL(filepath.Join(t.TempDir(), "missing")))
+			h.AssertNil(t, err)
+
+			_, err = cache.LocateBuildpack("example/foo")
+			h.AssertError(t, err, "reading registry index")
+		})
+	})
+
+	when("#IDs", func() {
+		it("lists the buildpacks in the directory", func() {
+			cache, err := registry.NewDirCache(logger, index)
+			h.AssertNil(t, err)
+
+			ids, err := cache.IDs()
+			h.AssertNil(t, err)
+			h.AssertEq(t, ids, []string{"example/foo", "example/java"})
+		})
+	})
+}
@@ -7,8 +7,8 @@ import (
 	"github.com/pkg/errors"
 )
 
-// GitCommit commits a Buildpack to a registry Cache.
-func GitCommit(b Buildpack, username string, registryCache Cache) error {
+// GitCommit commits a Buildpack to a registry GitCache.
+func GitCommit(b Buildpack, username string, registryCache GitCache) error {
 	if err := registryCache.Initialize(); err != nil {
 		return err
 	}
@@ -22,7 +22,7 @@ func TestGit(t *testing.T) {
 
 func testGit(t *testing.T, when spec.G, it spec.S) {
 	var (
-		registryCache   registry.Cache
+		registryCache   registry.GitCache
 		tmpDir          string
 		err             error
 		registryFixture string
@@ -0,0 +1,298 @@
+package registry
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// githubAPIURL is the API of github.com, GitHub Enterprise servers serve theirs at /api/v3 of their host
+const githubAPIURL = "https://api.github.com"
+
+// githubForkTimeout is how long creating the branch of a pull request waits for a fork that was just requested
+const githubForkTimeout = 30 * time.Second
+
+// githubAPI creates pull requests against a registry index hosted on GitHub
+type githubAPI struct {
+	client *http.Client
+	repo   *url.URL
+	token  string
+
+	forkPollInterval time.Duration
+}
+
+// OpenGithubPullRequest validates the buildpack and opens a pull request adding it to the index of the registry hosted
+// at the GitHub repository registryURL, authenticated with token. The entry is committed to a branch of the registry
+// repository when token can push to it, else to a branch of a fork of it. It returns the URL of the pull request.
+func OpenGithubPullRequest(ctx context.Context, registryURL, token string, b Buildpack) (string, error) {
+	repo, err := githubRepoAPI(registryURL)
+	if err != nil {
+		return "", err
+	}
+
+	api := &githubAPI{
+		client:           &http.Client{Timeout: httpTimeout},
+		repo:             repo,
+		token:            token,
+		forkPollInterval: time.Second,
+	}
+	return api.openPullRequest(ctx, b)
+}
+
+// githubRepoAPI is the API URL of the GitHub repository at repoURL, e.g. https://api.github.com/repos/buildpacks/registry-index
+// for https://github.com/buildpacks/registry-index
+func githubRepoAPI(repoURL string) (*url.URL, error) {
+	parsed, err := url.Parse(repoURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing registry url %s", style.Symbol(repoURL))
+	}
+
+	parts := strings.Split(strings.TrimSuffix(strings.Trim(parsed.Path, "/"), ".git"), "/")
+	if parsed.Host == "" || len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, errors.Errorf("registry url %s is not the url of a GitHub repository", style.Symbol(repoURL))
+	}
+
+	api, err := url.Parse(githubAPIURL)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.Host != "github.com" {
+		api = &url.URL{Scheme: parsed.Scheme, Host: parsed.Host, Path: "/api/v3"}
+	}
+	return api.JoinPath("repos", parts[0], parts[1]), nil
+}
+
+type githubRepo struct {
+	FullName      string `json:"full_name"`
+	DefaultBranch string `json:"default_branch"`
+	URL           string `json:"url"`
+	Owner         struct {
+		Login string `json:"login"`
+	} `json:"owner"`
+	Permissions struct {
+		Push bool `json:"push"`
+	} `json:"permissions"`
+}
+
+type githubRef struct {
+	Object struct {
+		SHA string `json:"sha"`
+	} `json:"object"`
+}
+
+type githubContent struct {
+	SHA     string `json:"sha"`
+	Content string `json:"content"`
+}
+
+type githubPullRequest struct {
+	HTMLURL string `json:"html_url"`
+}
+
+func (g *githubAPI) openPullRequest(ctx context.Context, b Buildpack) (string, error) {
+	if b.Version == "" {
+		return "", errors.New("invalid entry: version is a required field")
+	}
+	if err := Validate(b); err != nil {
+		return "", err
+	}
+	index, err := IndexPath("", b.Namespace, b.Name)
+	if err != nil {
+		return "", err
+	}
+	index = filepath.ToSlash(index)
+
+	issue, err := CreateGithubIssue(b)
+	if err != nil {
+		return "", err
+	}
+
+	var upstream githubRepo
+	if err := g.do(ctx, http.MethodGet, g.repo.String(), nil, &upstream); err != nil {
+		return "", errors.Wrap(err, "reading registry repository")
+	}
+
+	var base githubRef
+	if err := g.do(ctx, http.MethodGet, g.repo.JoinPath("git", "ref", "heads", upstream.DefaultBranch).String(), nil, &base); err != nil {
+		return "", errors.Wrapf(err, "reading branch %s", style.Symbol(upstream.DefaultBranch))
+	}
+
+	contents, entrySHA, err := g.appendEntry(ctx, index, base.Object.SHA, b)
+	if err != nil {
+		return "", err
+	}
+
+	head := upstream
+	if !upstream.Permissions.Push {
+		if err := g.do(ctx, http.MethodPost, g.repo.JoinPath("forks").String(), struct{}{}, &head); err != nil {
+			return "", errors.Wrap(err, "forking registry repository")
+		}
+	}
+	headRepo, err := url.Parse(head.URL)
+	if err != nil {
+		return "", errors.Wrapf(err, "parsing url of repository %s", style.Symbol(head.FullName))
+	}
+
+	branch := fmt.Sprintf("register/%s-%s-%s", b.Namespace, b.Name, b.Version)
+	if err := g.createBranch(ctx, headRepo, branch, base.Object.SHA); err != nil {
+		return "", errors.Wrapf(err, "creating branch %s in %s", style.Symbol(branch), style.Symbol(head.FullName))
+	}
+
+	update := map[string]string{
+		"message": issue.Title,
+		"content": base64.StdEncoding.EncodeToString(contents),
+		"branch":  branch,
+	}
+	if entrySHA != "" {
+		update["sha"] = entrySHA
+	}
+	if err := g.do(ctx, http.MethodPut, headRepo.JoinPath("contents", index).String(), update, nil); err != nil {
+		return "", errors.Wrapf(err, "committing %s", style.Symbol(index))
+	}
+
+	var pr githubPullRequest
+	err = g.do(ctx, http.MethodPost, g.repo.JoinPath("pulls").String(), map[string]string{
+		"title": issue.Title,
+		"body":  issue.Body,
+		"head":  head.Owner.Login + ":" + branch,
+		"base":  upstream.DefaultBranch,
+	}, &pr)
+	if err != nil {
+		return "", errors.Wrap(err, "opening pull request")
+	}
+	return pr.HTMLURL, nil
+}
+
+// createBranch creates branch at sha in repo, retrying while a fork that was just requested isn't ready yet
+func (g *githubAPI) createBranch(ctx context.Context, repo *url.URL, branch, sha string) error {
+	deadline := time.Now().Add(githubForkTimeout)
+	for {
+		err := g.do(ctx, http.MethodPost, repo.JoinPath("git", "refs").String(), map[string]string{
+			"ref": "refs/heads/" + branch,
+			"sha": sha,
+		}, nil)
+		if err == nil || time.Now().After(deadline) {
+			return err
+		}
+		if statusErr, ok := err.(*githubStatusError); !ok || (statusErr.status != http.StatusNotFound && statusErr.status != http.StatusConflict) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(g.forkPollInterval):
+		}
+	}
+}
+
+// appendEntry returns the entry at index in the registry repository at commit sha with the buildpack appended, and the
+// sha of the entry, which is empty for buildpacks that weren't registered before. It fails when the version of the
+// buildpack is in the entry already.
+func (g *githubAPI) appendEntry(ctx context.Context, index, sha string, b Buildpack) ([]byte, string, error) {
+	contentsURL := g.repo.JoinPath("contents", index)
+	query := contentsURL.Query()
+	query.Set("ref", sha)
+	contentsURL.RawQuery = query.Encode()
+
+	var existing githubContent
+	err := g.do(ctx, http.MethodGet, contentsURL.String(), nil, &existing)
+	if statusErr, ok := err.(*githubStatusError); ok && statusErr.status == http.StatusNotFound {
+		err = nil
+	}
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "reading %s", style.Symbol(index))
+	}
+
+	contents, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(existing.Content, "\n", ""))
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "decoding %s", style.Symbol(index))
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var registered Buildpack
+		if err := json.Unmarshal(scanner.Bytes(), &registered); err != nil {
+			return nil, "", errors.Wrapf(err, "parsing index for buildpack: %s/%s", b.Namespace, b.Name)
+		}
+		if registered.Version == b.Version {
+			return nil, "", errors.Errorf("version %s of buildpack %s is registered already", style.Symbol(b.Version), style.Symbol(b.Namespace+"/"+b.Name))
+		}
+	}
+
+	line, err := json.Marshal(b)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "converting buildpack file to json: %s/%s", b.Namespace, b.Name)
+	}
+	if len(contents) > 0 && !bytes.HasSuffix(contents, []byte("\n")) {
+		contents = append(contents, '\n')
+	}
+	return append(append(contents, line...), '\n'), existing.SHA, nil
+}
+
+// githubStatusError is a response of the GitHub API with an unsuccessful status
+type githubStatusError struct {
+	status  int
+	message string
+}
+
+func (e *githubStatusError) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.status, http.StatusText(e.status), e.message)
+}
+
+// do sends body as JSON to the GitHub API and decodes the response into out, unless out is nil
+func (g *githubAPI) do(ctx context.Context, method, endpoint string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+g.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		return &githubStatusError{status: resp.StatusCode, message: apiErr.Message}
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
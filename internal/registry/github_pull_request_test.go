@@ -0,0 +1,220 @@
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestGithubPullRequest(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "GithubPullRequest", testGithubPullRequest, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testGithubPullRequest(t *testing.T, when spec.G, it spec.S) {
+	const existingEntry = `{"ns":"example","name":"java","version":"1.0.0","yanked":false,"addr":"example.com/some/package@sha256:74eb48882e835d8767f62940d453eb96ed2737de3a16573881dcea7dea769df7"}`
+
+	var (
+		server *httptest.Server
+		mu     sync.Mutex
+
+		canPush         bool
+		forkNotReadyFor int
+		existing        string
+
+		refs        []string
+		committed   map[string]string
+		pullRequest map[string]string
+		subject     *githubAPI
+
+		bp = Buildpack{
+			Namespace: "example",
+			Name:      "java",
+			Version:   "1.1.0",
+			Address:   "example.com/some/package@sha256:8c27fe111c11b722081701dfed3bd55e039b9ce92865473cf4cdfa918071c566",
+		}
+	)
+
+	it.Before(func() {
+		canPush, forkNotReadyFor, existing = false, 0, ""
+		refs, committed, pullRequest = nil, map[string]string{}, nil
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			h.AssertEq(t, r.Header.Get("Authorization"), "Bearer some-token")
+			repo := func(owner string) map[string]interface{} {
+				return map[string]interface{}{
+					"full_name":      owner + "/registry-index",
+					"default_branch": "main",
+					"url":            server.URL + "/api/v3/repos/" + owner + "/registry-index",
+					"owner":          map[string]string{"login": owner},
+					"permissions":    map[string]bool{"push": canPush},
+				}
+			}
+			path := strings.TrimPrefix(r.URL.Path, "/api/v3/repos/")
+
+			switch {
+			case r.Method == http.MethodGet && path == "buildpacks/registry-index":
+				_ = json.NewEncoder(w).Encode(repo("buildpacks"))
+			case r.Method == http.MethodGet && path == "buildpacks/registry-index/git/ref/heads/main":
+				fmt.Fprint(w, `{"object":{"sha":"some-sha"}}`)
+			case r.Method == http.MethodPost && path == "buildpacks/registry-index/forks":
+				w.WriteHeader(http.StatusAccepted)
+				_ = json.NewEncoder(w).Encode(repo("some-user"))
+			case r.Method == http.MethodPost && strings.HasSuffix(path, "/git/refs"):
+				if forkNotReadyFor > 0 {
+					forkNotReadyFor--
+					w.WriteHeader(http.StatusNotFound)
+					fmt.Fprint(w, `{"message":"Not Found"}`)
+					return
+				}
+				var ref map[string]string
+				h.AssertNil(t, json.NewDecoder(r.Body).Decode(&ref))
+				h.AssertEq(t, ref["sha"], "some-sha")
+				refs = append(refs, strings.TrimSuffix(path, "/git/refs")+":"+ref["ref"])
+				w.WriteHeader(http.StatusCreated)
+			case r.Method == http.MethodGet && strings.Contains(path, "/contents/"):
+				if existing == "" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				h.AssertEq(t, r.URL.Query().Get("ref"), "some-sha")
+				_ = json.NewEncoder(w).Encode(map[string]string{"sha": "entry-sha", "content": base64.StdEncoding.EncodeToString([]byte(existing))})
+			case r.Method == http.MethodPut && strings.Contains(path, "/contents/"):
+				var update map[string]string
+				h.AssertNil(t, json.NewDecoder(r.Body).Decode(&update))
+				contents, err := base64.StdEncoding.DecodeString(update["content"])
+				h.AssertNil(t, err)
+				committed[path] = string(contents)
+				committed["sha"] = update["sha"]
+				committed["message"] = update["message"]
+				w.WriteHeader(http.StatusCreated)
+			case r.Method == http.MethodPost && path == "buildpacks/registry-index/pulls":
+				h.AssertNil(t, json.NewDecoder(r.Body).Decode(&pullRequest))
+				w.WriteHeader(http.StatusCreated)
+				fmt.Fprint(w, `{"html_url":"https://github.com/buildpacks/registry-index/pull/1"}`)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `{"message":"Not Found"}`)
+			}
+		}))
+
+		repo, err := githubRepoAPI(server.URL + "/buildpacks/registry-index")
+		h.AssertNil(t, err)
+		subject = &githubAPI{client: server.Client(), repo: repo, token: "some-token", forkPollInterval: time.Millisecond}
+	})
+
+	it.After(func() {
+		server.Close()
+	})
+
+	when("#githubRepoAPI", func() {
+		it("is the API of github.com for its repositories", func() {
+			api, err := githubRepoAPI("https://github.com/buildpacks/registry-index.git")
+			h.AssertNil(t, err)
+			h.AssertEq(t, api.String(), "https://api.github.com/repos/buildpacks/registry-index")
+		})
+
+		it("is the API of a GitHub Enterprise server for its repositories", func() {
+			api, err := githubRepoAPI("https://github.example.com/buildpacks/registry-index")
+			h.AssertNil(t, err)
+			h.AssertEq(t, api.String(), "https://github.example.com/api/v3/repos/buildpacks/registry-index")
+		})
+
+		it("errors when the url isn't of a repository", func() {
+			_, err := githubRepoAPI("https://github.com/buildpacks")
+			h.AssertError(t, err, "is not the url of a GitHub repository")
+		})
+	})
+
+	when("#openPullRequest", func() {
+		it("opens a pull request from a fork adding a new entry", func() {
+			url, err := subject.openPullRequest(context.Background(), bp)
+			h.AssertNil(t, err)
+			h.AssertEq(t, url, "https://github.com/buildpacks/registry-index/pull/1")
+
+			h.AssertEq(t, refs, []string{"some-user/registry-index:refs/heads/register/example-java-1.1.0"})
+			line, err := json.Marshal(bp)
+			h.AssertNil(t, err)
+			h.AssertEq(t, committed["some-user/registry-index/contents/ja/va/example_java"], string(line)+"\n")
+			h.AssertEq(t, committed["sha"], "")
+			h.AssertEq(t, committed["message"], "ADD example/java@1.1.0")
+
+			h.AssertEq(t, pullRequest["title"], "ADD example/java@1.1.0")
+			h.AssertEq(t, pullRequest["head"], "some-user:register/example-java-1.1.0")
+			h.AssertEq(t, pullRequest["base"], "main")
+			h.AssertContains(t, pullRequest["body"], `id = "example/java"`)
+		})
+
+		it("appends to the existing entry", func() {
+			existing = existingEntry + "\n"
+
+			_, err := subject.openPullRequest(context.Background(), bp)
+			h.AssertNil(t, err)
+
+			line, err := json.Marshal(bp)
+			h.AssertNil(t, err)
+			h.AssertEq(t, committed["some-user/registry-index/contents/ja/va/example_java"], existingEntry+"\n"+string(line)+"\n")
+			h.AssertEq(t, committed["sha"], "entry-sha")
+		})
+
+		it("waits for the fork to be ready", func() {
+			forkNotReadyFor = 2
+
+			_, err := subject.openPullRequest(context.Background(), bp)
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(refs), 1)
+		})
+
+		it("commits to the registry repository when the token can push to it", func() {
+			canPush = true
+
+			_, err := subject.openPullRequest(context.Background(), bp)
+			h.AssertNil(t, err)
+			h.AssertEq(t, refs, []string{"buildpacks/registry-index:refs/heads/register/example-java-1.1.0"})
+			h.AssertEq(t, pullRequest["head"], "buildpacks:register/example-java-1.1.0")
+		})
+
+		it("errors when the version is registered already", func() {
+			existing = existingEntry + "\n"
+			bp.Version = "1.0.0"
+
+			_, err := subject.openPullRequest(context.Background(), bp)
+			h.AssertError(t, err, "version '1.0.0' of buildpack 'example/java' is registered already")
+			h.AssertEq(t, len(refs), 0)
+		})
+
+		it("errors when the address isn't a digest reference", func() {
+			invalid := bp
+			invalid.Address = "example.com/some/package:latest"
+
+			_, err := subject.openPullRequest(context.Background(), invalid)
+			h.AssertError(t, err, "is not a digest reference")
+			h.AssertEq(t, len(refs), 0)
+		})
+
+		it("errors when the name has illegal characters", func() {
+			invalid := bp
+			invalid.Name = "Java"
+
+			_, err := subject.openPullRequest(context.Background(), invalid)
+			h.AssertError(t, err, "'name' contains illegal characters")
+		})
+	})
+}
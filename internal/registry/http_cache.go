@@ -0,0 +1,285 @@
+package registry
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/buildpack"
+	"github.com/buildpacks/pack/pkg/errcode"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+// httpTimeout is how long fetching an entry of a registry served over HTTP may take
+const httpTimeout = 30 * time.Second
+
+// HTTPCache is a RegistryCache of a registry served over plain HTTP(S), e.g. by a static file server or as the raw
+// files of a git host, so that mirrors don't need to serve a git repository. As an index served over HTTP can't be
+// listed, the entries of buildpacks are fetched as they are located.
+//
+// Its URL is the URL of the index prefixed with index+, e.g.
+// index+https://raw.githubusercontent.com/buildpacks/registry-index/main
+type HTTPCache struct {
+	logger logging.Logger
+	url    *url.URL
+	index  *url.URL
+	client *http.Client
+	Root   string
+
+	CacheSettings
+}
+
+// NewHTTPCache creates a new cache of a registry served over HTTP
+func NewHTTPCache(logger logging.Logger, home, registryURL string, ops ...CacheOption) (HTTPCache, error) {
+	if _, err := os.Stat(home); err != nil {
+		return HTTPCache{}, errors.Wrapf(err, "finding home %s", home)
+	}
+
+	normalizedURL, err := url.Parse(registryURL)
+	if err != nil {
+		return HTTPCache{}, errors.Wrapf(err, "parsing registry url %s", registryURL)
+	}
+
+	index := *normalizedURL
+	index.Scheme = strings.TrimPrefix(normalizedURL.Scheme, httpIndexSchemePrefix)
+	if index.Scheme != "http" && index.Scheme != "https" {
+		return HTTPCache{}, errors.Errorf("registry url %s must be served over http or https", style.Symbol(registryURL))
+	}
+
+	cache := HTTPCache{
+		logger: logger,
+		url:    normalizedURL,
+		index:  &index,
+		client: &http.Client{Timeout: httpTimeout},
+		Root:   cacheRoot(home, normalizedURL),
+	}
+	for _, op := range ops {
+		op(&cache.CacheSettings)
+	}
+	cache.client.Transport = cache.proxyTransport()
+	return cache, nil
+}
+
+// URL returns the URL of the registry
+func (r *HTTPCache) URL() string {
+	return r.url.String()
+}
+
+// Dir returns the directory the fetched entries are cached in
+func (r *HTTPCache) Dir() string {
+	return r.Root
+}
+
+// LocateBuildpack fetches the entry of the buildpack, unless offline, and returns the buildpack from it
+func (r *HTTPCache) LocateBuildpack(bp string) (Buildpack, error) {
+	if r.Offline {
+		if err := RequireCache(r.logger, r); err != nil {
+			return Buildpack{}, err
+		}
+		return locateBuildpack(r.logger, r.Root, bp, r.CacheSettings)
+	}
+
+	ns, name, _, err := buildpack.ParseRegistryID(bp)
+	if err != nil {
+		return Buildpack{}, errors.Wrap(err, "parsing buildpacks registry id")
+	}
+
+	if err := r.prepareEntry(ns, name); err != nil {
+		return Buildpack{}, err
+	}
+
+	markUsed(r.Root)
+	return locateBuildpack(r.logger, r.Root, bp, r.CacheSettings)
+}
+
+// LocateBuildpacks fetches the entry of each buildpack once, unless offline, and returns the buildpacks from them. As
+// entries are fetched one by one, a buildpack whose entry fails to be fetched has the error in its result.
+func (r *HTTPCache) LocateBuildpacks(bps []string) ([]LocatedBuildpack, error) {
+	if r.Offline {
+		if err := RequireCache(r.logger, r); err != nil {
+			return nil, err
+		}
+		return locateBuildpacks(r.logger, r.Root, bps, r.CacheSettings), nil
+	}
+
+	prepared := map[string]error{}
+	located := make([]LocatedBuildpack, 0, len(bps))
+	for _, bp := range bps {
+		// ids that don't parse fail to be located below
+		if ns, name, _, err := buildpack.ParseRegistryID(bp); err == nil {
+			id := ns + "/" + name
+			if _, ok := prepared[id]; !ok {
+				prepared[id] = r.prepareEntry(ns, name)
+			}
+			if err := prepared[id]; err != nil {
+				located = append(located, LocatedBuildpack{ID: bp, Err: err})
+				continue
+			}
+		}
+		found, err := locateBuildpack(r.logger, r.Root, bp, r.CacheSettings)
+		located = append(located, LocatedBuildpack{ID: bp, Buildpack: found, Err: err})
+	}
+
+	markUsed(r.Root)
+	return located, nil
+}
+
+// prepareEntry fetches the entry of a buildpack before it's located, falling back to a cached entry when the registry
+// can't be reached
+func (r *HTTPCache) prepareEntry(ns, name string) error {
+	if err := r.refreshEntry(ns, name); err != nil {
+		// a cached entry still locates the buildpack when the registry can't be reached, but not when it's gone
+		index, indexErr := IndexPath(r.Root, ns, name)
+		if indexErr != nil || errcode.Of(err) == errcode.RegistryEntryMissing {
+			return errors.Wrap(err, "refreshing cache")
+		}
+		if _, statErr := os.Stat(index); statErr != nil {
+			return errors.Wrap(err, "refreshing cache")
+		}
+		r.logger.Warnf("Unable to refresh the registry cache, using the cached index: %s", err)
+	}
+	return nil
+}
+
+// IDs returns the ids of the buildpacks whose entries were fetched, sorted. The cache isn't refreshed.
+func (r *HTTPCache) IDs() ([]string, error) {
+	return listIDs(r.Root)
+}
+
+// Versions returns the versions of a buildpack whose entry was fetched that weren't yanked, from the highest. The
+// cache isn't refreshed.
+func (r *HTTPCache) Versions(id string) ([]string, error) {
+	return listVersions(r.Root, id)
+}
+
+// ListBuildpacks calls fn with the id and entry of each buildpack whose entry was fetched, sorted by id. The cache
+// isn't refreshed.
+func (r *HTTPCache) ListBuildpacks(fn func(id string, entry Entry) error) error {
+	return listEntries(r.Root, fn)
+}
+
+// Refresh fetches the entries in the cache again, the entries of other buildpacks are fetched as they are located
+func (r *HTTPCache) Refresh() error {
+	ids, err := listIDs(r.Root)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		ns, name, err := ParseNamespaceName(id)
+		if err != nil {
+			return err
+		}
+		if err := r.refreshEntry(ns, name); err != nil && errcode.Of(err) != errcode.RegistryEntryMissing {
+			return err
+		}
+	}
+	return nil
+}
+
+// refreshEntry fetches the entry of a buildpack into the cache, unless it was fetched within the refresh interval. An
+// entry the registry doesn't have anymore is removed from the cache. The lock of the cache is held meanwhile, so that
+// concurrent pack invocations don't fetch the same entry at once.
+func (r *HTTPCache) refreshEntry(ns, name string) error {
+	index, err := IndexPath(r.Root, ns, name)
+	if err != nil {
+		return err
+	}
+
+	unlock, err := lockCache(r.Root)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if !r.ForceRefresh && fetchedWithin(index, r.RefreshInterval) {
+		r.logger.Debugf("Registry entry %s/%s was fetched less than %s ago, not fetching it", ns, name, r.RefreshInterval)
+		return nil
+	}
+
+	relativeIndex, err := IndexPath("", ns, name)
+	if err != nil {
+		return err
+	}
+	entryURL := r.index.JoinPath(filepath.ToSlash(relativeIndex))
+
+	r.logger.Debugf("Fetching registry entry %s", style.Symbol(entryURL.Redacted()))
+	req, err := http.NewRequest(http.MethodGet, entryURL.String(), nil)
+	if err != nil {
+		return err
+	}
+	r.authorize(req)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "fetching %s", style.Symbol(entryURL.Redacted()))
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		if err := os.Remove(index); err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "removing %s", style.Symbol(index))
+		}
+		return errcode.Errorf(errcode.RegistryEntryMissing, "no entries for buildpack: %s/%s", ns, name)
+	case resp.StatusCode == http.StatusUnauthorized, resp.StatusCode == http.StatusForbidden:
+		return authRequiredError(r.url, errors.New(resp.Status))
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return errors.Errorf("fetching %s: %s", style.Symbol(entryURL.Redacted()), resp.Status)
+	}
+
+	return writeFileAtomically(index, resp.Body)
+}
+
+// authorize authenticates req with the token of the cache, else with the credentials in the URL, else with the
+// credentials of the registry host in the netrc file
+func (r *HTTPCache) authorize(req *http.Request) {
+	switch {
+	case r.token != "":
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	case r.index.User != nil:
+		// the client authenticates with the credentials in the URL
+	default:
+		if login, password, ok := netrcCredentials(r.index.Hostname()); ok {
+			req.SetBasicAuth(login, password)
+		}
+	}
+}
+
+// fetchedWithin is true when the file at path was written less than interval ago
+func fetchedWithin(path string, interval time.Duration) bool {
+	if interval <= 0 {
+		return false
+	}
+	info, err := os.Stat(path)
+	return err == nil && time.Since(info.ModTime()) < interval
+}
+
+// writeFileAtomically writes the contents of r to path, so that an interrupted write leaves the previous contents
+func writeFileAtomically(path string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".entry")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return errors.Wrapf(err, "writing %s", style.Symbol(path))
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,198 @@
+package registry
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/errcode"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestHTTPCache(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "HTTPCache", testHTTPCache, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testHTTPCache(t *testing.T, when spec.G, it spec.S) {
+	var (
+		tmpDir   string
+		logger   logging.Logger
+		outBuf   bytes.Buffer
+		server   *httptest.Server
+		mu       sync.Mutex
+		requests []*http.Request
+		subject  HTTPCache
+	)
+
+	it.Before(func() {
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		tmpDir = t.TempDir()
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			requests = append(requests, r)
+			mu.Unlock()
+			http.StripPrefix("/registry-index", http.FileServer(http.Dir(filepath.Join("..", "..", "testdata", "registry")))).ServeHTTP(w, r)
+		}))
+
+		var err error
+		subject, err = NewHTTPCache(logger, tmpDir, "index+"+server.URL+"/registry-index")
+		h.AssertNil(t, err)
+	})
+
+	it.After(func() {
+		server.Close()
+	})
+
+	when("#NewCache", func() {
+		it("selects the HTTP cache for index+ URLs", func() {
+			cache, err := NewCache(logger, tmpDir, "index+https://example.com/registry-index")
+			h.AssertNil(t, err)
+			_, ok := cache.(*HTTPCache)
+			h.AssertTrue(t, ok)
+		})
+
+		it("selects the git cache for other URLs", func() {
+			cache, err := NewCache(logger, tmpDir, "https://example.com/registry-index")
+			h.AssertNil(t, err)
+			_, ok := cache.(*GitCache)
+			h.AssertTrue(t, ok)
+		})
+
+		it("fails for index+ URLs not served over HTTP", func() {
+			_, err := NewCache(logger, tmpDir, "index+ftp://example.com/registry-index")
+			h.AssertError(t, err, "must be served over http or https")
+		})
+	})
+
+	when("#LocateBuildpack", func() {
+		it("fetches the entry of the buildpack", func() {
+			bp, err := subject.LocateBuildpack("example/foo@1.1.0")
+			h.AssertNil(t, err)
+			h.AssertEq(t, bp.Address, "example.com/some/package@sha256:74eb48882e835d8767f62940d453eb96ed2737de3a16573881dcea7dea769df7")
+
+			h.AssertEq(t, len(requests), 1)
+			h.AssertEq(t, requests[0].URL.Path, "/registry-index/3/fo/example_foo")
+
+			ids, err := subject.IDs()
+			h.AssertNil(t, err)
+			h.AssertEq(t, ids, []string{"example/foo"})
+		})
+
+		it("locates the highest version", func() {
+			bp, err := subject.LocateBuildpack("example/foo")
+			h.AssertNil(t, err)
+			h.AssertEq(t, bp.Version, "1.2.0")
+		})
+
+		it("fails for a buildpack the registry doesn't have", func() {
+			_, err := subject.LocateBuildpack("example/missing@1.0.0")
+			h.AssertError(t, err, "no entries for buildpack: example/missing")
+			h.AssertEq(t, errcode.Of(err), errcode.RegistryEntryMissing)
+		})
+
+		it("doesn't fetch the entry again within the refresh interval", func() {
+			subject.RefreshInterval = time.Hour
+
+			_, err := subject.LocateBuildpack("example/foo@1.1.0")
+			h.AssertNil(t, err)
+			_, err = subject.LocateBuildpack("example/foo@1.2.0")
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(requests), 1)
+
+			subject.ForceRefresh = true
+			_, err = subject.LocateBuildpack("example/foo@1.2.0")
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(requests), 2)
+		})
+
+		it("uses the cached entry when the registry can't be reached", func() {
+			_, err := subject.LocateBuildpack("example/foo@1.1.0")
+			h.AssertNil(t, err)
+			server.Close()
+
+			bp, err := subject.LocateBuildpack("example/foo@1.1.0")
+			h.AssertNil(t, err)
+			h.AssertEq(t, bp.Version, "1.1.0")
+			h.AssertContains(t, outBuf.String(), "Unable to refresh the registry cache, using the cached index")
+		})
+
+		it("sends the token", func() {
+			subject, err := NewHTTPCache(logger, tmpDir, "index+"+server.URL+"/registry-index", WithToken("some-token"))
+			h.AssertNil(t, err)
+
+			_, err = subject.LocateBuildpack("example/foo@1.1.0")
+			h.AssertNil(t, err)
+			h.AssertEq(t, requests[0].Header.Get("Authorization"), "Bearer some-token")
+		})
+
+		when("offline", func() {
+			it.Before(func() {
+				subject.Offline = true
+			})
+
+			it("fails when the cache doesn't exist yet", func() {
+				_, err := subject.LocateBuildpack("example/foo@1.1.0")
+				h.AssertError(t, err, "doesn't exist yet, it's created by running without --offline")
+			})
+
+			it("locates the buildpack in the cache without fetching it", func() {
+				h.AssertNil(t, os.MkdirAll(filepath.Join(subject.Root, "3", "fo"), 0755))
+				entry, err := os.ReadFile(filepath.Join("..", "..", "testdata", "registry", "3", "fo", "example_foo"))
+				h.AssertNil(t, err)
+				h.AssertNil(t, os.WriteFile(filepath.Join(subject.Root, "3", "fo", "example_foo"), entry, 0600))
+
+				bp, err := subject.LocateBuildpack("example/foo@1.1.0")
+				h.AssertNil(t, err)
+				h.AssertEq(t, bp.Version, "1.1.0")
+				h.AssertEq(t, len(requests), 0)
+			})
+		})
+	})
+
+	when("#LocateBuildpacks", func() {
+		it("fetches the entry of each buildpack once", func() {
+			located, err := subject.LocateBuildpacks([]string{"example/foo@1.0.0", "example/foo", "example/missing", "example/java"})
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(located), 4)
+
+			h.AssertEq(t, located[0].Buildpack.Version, "1.0.0")
+			h.AssertEq(t, located[1].Buildpack.Version, "1.2.0")
+			h.AssertEq(t, errcode.Of(located[2].Err), errcode.RegistryEntryMissing)
+			h.AssertNil(t, located[3].Err)
+			h.AssertEq(t, located[3].Buildpack.Name, "java")
+
+			h.AssertEq(t, len(requests), 3)
+		})
+	})
+
+	when("#Refresh", func() {
+		it("fetches the cached entries again and removes the ones the registry doesn't have", func() {
+			_, err := subject.LocateBuildpack("example/foo@1.1.0")
+			h.AssertNil(t, err)
+			gone := filepath.Join(subject.Root, "mi", "ss", "example_missing")
+			h.AssertNil(t, os.MkdirAll(filepath.Dir(gone), 0755))
+			h.AssertNil(t, os.WriteFile(gone, []byte(strings.TrimSpace(`{"ns":"example","name":"missing","version":"1.0.0","addr":"example.com/missing@sha256:0000000000000000000000000000000000000000000000000000000000000000"}`)), 0600))
+
+			h.AssertNil(t, subject.Refresh())
+			h.AssertEq(t, len(requests), 3)
+
+			ids, err := subject.IDs()
+			h.AssertNil(t, err)
+			h.AssertEq(t, ids, []string{"example/foo"})
+		})
+	})
+}
@@ -0,0 +1,38 @@
+package registry
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// lockFileSuffix suffixes the cache root to name its lock file, which is beside rather than in the root so that it
+// outlives the root being replaced by a new clone
+const lockFileSuffix = ".lock"
+
+// lockCache takes an advisory, exclusive lock of the cache in root, blocking while another process holds it, so that
+// concurrent pack invocations don't clone into or pull into the same cache at once. The returned func releases it.
+func lockCache(root string) (func(), error) {
+	path := root + lockFileSuffix
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(filepath.Clean(path), os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, errors.Wrapf(err, "opening registry cache lock %s", style.Symbol(path))
+	}
+
+	if err := lockFile(file); err != nil {
+		file.Close()
+		return nil, errors.Wrapf(err, "locking registry cache %s", style.Symbol(root))
+	}
+
+	return func() {
+		_ = unlockFile(file)
+		file.Close()
+	}, nil
+}
@@ -0,0 +1,117 @@
+package registry
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/buildpack"
+	"github.com/buildpacks/pack/pkg/errcode"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+// MemoryCache is a RegistryCache of a registry index held in memory, e.g. for the tests of tools embedding pack and
+// for ephemeral CI jobs, so that buildpacks are located without reaching a registry or writing to disk. It has no
+// directory, and there is nothing to refresh.
+type MemoryCache struct {
+	logger  logging.Logger
+	url     string
+	entries map[string]Entry
+
+	CacheSettings
+}
+
+// NewMemoryCache creates a new cache of the registry index made of buildpacks, named url
+func NewMemoryCache(logger logging.Logger, url string, buildpacks []Buildpack, ops ...CacheOption) (MemoryCache, error) {
+	cache := MemoryCache{
+		logger:  logger,
+		url:     url,
+		entries: map[string]Entry{},
+	}
+	for _, bp := range buildpacks {
+		if _, err := IndexPath("", bp.Namespace, bp.Name); err != nil {
+			return MemoryCache{}, errors.Wrapf(err, "invalid buildpack %s", style.Symbol(bp.Namespace+"/"+bp.Name))
+		}
+		id := bp.Namespace + "/" + bp.Name
+		entry := cache.entries[id]
+		entry.Buildpacks = append(entry.Buildpacks, bp)
+		cache.entries[id] = entry
+	}
+	for _, op := range ops {
+		op(&cache.CacheSettings)
+	}
+	return cache, nil
+}
+
+// URL returns the name of the index
+func (r *MemoryCache) URL() string {
+	return r.url
+}
+
+// Dir returns no directory, as the index is held in memory
+func (r *MemoryCache) Dir() string {
+	return ""
+}
+
+// LocateBuildpack returns the buildpack from the index
+func (r *MemoryCache) LocateBuildpack(bp string) (Buildpack, error) {
+	ns, name, version, err := buildpack.ParseRegistryID(bp)
+	if err != nil {
+		return Buildpack{}, errors.Wrap(err, "parsing buildpacks registry id")
+	}
+
+	entry, ok := r.entries[ns+"/"+name]
+	if !ok {
+		return Buildpack{}, errcode.Errorf(errcode.RegistryEntryMissing, "no entries for buildpack: %s", bp)
+	}
+	return locateInEntry(r.logger, entry, bp, ns, name, version, r.CacheSettings)
+}
+
+// LocateBuildpacks returns the buildpacks from the index
+func (r *MemoryCache) LocateBuildpacks(bps []string) ([]LocatedBuildpack, error) {
+	located := make([]LocatedBuildpack, 0, len(bps))
+	for _, bp := range bps {
+		found, err := r.LocateBuildpack(bp)
+		located = append(located, LocatedBuildpack{ID: bp, Buildpack: found, Err: err})
+	}
+	return located, nil
+}
+
+// IDs returns the ids of the buildpacks in the index, sorted
+func (r *MemoryCache) IDs() ([]string, error) {
+	ids := make([]string, 0, len(r.entries))
+	for id := range r.entries {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// Versions returns the versions of a buildpack in the index that weren't yanked, from the highest
+func (r *MemoryCache) Versions(id string) ([]string, error) {
+	if _, _, err := ParseNamespaceName(id); err != nil {
+		return nil, err
+	}
+	entry, ok := r.entries[id]
+	if !ok {
+		return nil, errcode.Errorf(errcode.RegistryEntryMissing, "no entries for buildpack: %s", id)
+	}
+	return entryVersions(entry), nil
+}
+
+// ListBuildpacks calls fn with the id and entry of each buildpack in the index, sorted by id
+func (r *MemoryCache) ListBuildpacks(fn func(id string, entry Entry) error) error {
+	ids, _ := r.IDs()
+	for _, id := range ids {
+		if err := fn(id, r.entries[id]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Refresh does nothing, as the index is held in memory
+func (r *MemoryCache) Refresh() error {
+	return nil
+}
@@ -0,0 +1,96 @@
+package registry_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/registry"
+	"github.com/buildpacks/pack/pkg/errcode"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestMemoryCache(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "MemoryCache", testMemoryCache, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testMemoryCache(t *testing.T, when spec.G, it spec.S) {
+	const digest = "@sha256:8c27fe111c11b722081701dfed3bd55e039b9ce92865473cf4cdfa918071c566"
+
+	var (
+		outBuf  bytes.Buffer
+		logger  = logging.NewLogWithWriters(&outBuf, &outBuf)
+		subject registry.MemoryCache
+	)
+
+	it.Before(func() {
+		var err error
+		subject, err = registry.NewMemoryCache(logger, "memory://some-registry", []registry.Buildpack{
+			{Namespace: "example", Name: "foo", Version: "1.0.0", Address: "example.com/foo" + digest},
+			{Namespace: "example", Name: "foo", Version: "1.1.0", Address: "example.com/foo" + digest},
+			{Namespace: "example", Name: "foo", Version: "1.2.0", Address: "example.com/foo" + digest, Yanked: true},
+			{Namespace: "example", Name: "bar", Version: "2.0.0", Address: "example.com/bar" + digest},
+		})
+		h.AssertNil(t, err)
+	})
+
+	it("fails for buildpacks with an invalid id", func() {
+		_, err := registry.NewMemoryCache(logger, "memory://some-registry", []registry.Buildpack{{Namespace: "Example", Name: "foo"}})
+		h.AssertError(t, err, "invalid buildpack 'Example/foo'")
+	})
+
+	when("#LocateBuildpack", func() {
+		it("locates the highest version that wasn't yanked", func() {
+			bp, err := subject.LocateBuildpack("example/foo")
+			h.AssertNil(t, err)
+			h.AssertEq(t, bp.Version, "1.1.0")
+		})
+
+		it("locates a version in a range", func() {
+			bp, err := subject.LocateBuildpack("example/foo@~1.0")
+			h.AssertNil(t, err)
+			h.AssertEq(t, bp.Version, "1.0.0")
+		})
+
+		it("fails for a buildpack the index doesn't have", func() {
+			_, err := subject.LocateBuildpack("example/missing")
+			h.AssertEq(t, errcode.Of(err), errcode.RegistryEntryMissing)
+		})
+	})
+
+	when("#LocateBuildpacks", func() {
+		it("locates every buildpack, with the errors of the ones that fail", func() {
+			located, err := subject.LocateBuildpacks([]string{"example/bar", "example/missing"})
+			h.AssertNil(t, err)
+			h.AssertEq(t, located[0].Buildpack.Version, "2.0.0")
+			h.AssertError(t, located[1].Err, "no entries for buildpack: example/missing")
+		})
+	})
+
+	when("#IDs and #Versions", func() {
+		it("lists the buildpacks and their versions", func() {
+			ids, err := subject.IDs()
+			h.AssertNil(t, err)
+			h.AssertEq(t, ids, []string{"example/bar", "example/foo"})
+
+			versions, err := subject.Versions("example/foo")
+			h.AssertNil(t, err)
+			h.AssertEq(t, versions, []string{"1.1.0", "1.0.0"})
+		})
+	})
+
+	when("#SearchBuildpacks", func() {
+		it("searches the index", func() {
+			buildpacks, err := registry.SearchBuildpacks(&subject, "ba")
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(buildpacks), 1)
+			h.AssertEq(t, buildpacks[0].Name, "bar")
+		})
+	})
+}
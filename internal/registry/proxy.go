@@ -0,0 +1,66 @@
+package registry
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/pkg/errors"
+	"golang.org/x/net/http/httpproxy"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// WithProxy sends the requests of the cache to its registry through the proxy at proxyURL, e.g.
+// http://proxy.example.com:3128, rather than through the proxy of the HTTPS_PROXY and HTTP_PROXY environment
+// variables. Hosts in the NO_PROXY environment variable are still reached directly.
+func WithProxy(proxyURL string) CacheOption {
+	return func(s *CacheSettings) {
+		s.proxy = proxyURL
+	}
+}
+
+// proxyURL is the proxy requests to remote go through, nil when they don't go through one. Registries served over
+// HTTP(S) go through the proxy of the cache, else through the proxy of the environment, while registries served over
+// SSH only go through the proxy of the cache.
+func (s CacheSettings) proxyURL(remote *url.URL) (*url.URL, error) {
+	cfg := httpproxy.FromEnvironment()
+	if s.proxy != "" {
+		if _, err := url.Parse(s.proxy); err != nil {
+			return nil, errors.Wrapf(err, "parsing registry proxy url %s", style.Symbol(s.proxy))
+		}
+		cfg.HTTPProxy, cfg.HTTPSProxy = s.proxy, s.proxy
+	}
+
+	target := *remote
+	switch remote.Scheme {
+	case "http", "https":
+	case "ssh":
+		if s.proxy == "" {
+			return nil, nil
+		}
+		// NO_PROXY is matched against the host of the registry as for registries served over HTTPS
+		target.Scheme = "https"
+	default:
+		return nil, nil
+	}
+	return cfg.ProxyFunc()(&target)
+}
+
+// proxyOptions are the proxy options of the git transport cloning and pulling remote
+func (s CacheSettings) proxyOptions(remote *url.URL) (transport.ProxyOptions, error) {
+	proxy, err := s.proxyURL(remote)
+	if err != nil || proxy == nil {
+		return transport.ProxyOptions{}, err
+	}
+	return transport.ProxyOptions{URL: proxy.String()}, nil
+}
+
+// proxyTransport is the HTTP transport of the requests of the cache, going through its proxy
+func (s CacheSettings) proxyTransport() http.RoundTripper {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = func(req *http.Request) (*url.URL, error) {
+		return s.proxyURL(req.URL)
+	}
+	return t
+}
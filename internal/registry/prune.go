@@ -0,0 +1,233 @@
+package registry
+
+import (
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+// DefaultCacheMaxAge is how long a registry cache may go unused before it's removed automatically, unless configured
+// otherwise
+const DefaultCacheMaxAge = 30 * 24 * time.Hour
+
+// autoPruneInterval is how often registry caches are pruned automatically
+const autoPruneInterval = 24 * time.Hour
+
+// prunedAtFile records when the registry caches in home were last pruned automatically
+const prunedAtFile = "registry-pruned-at"
+
+var (
+	// cacheDirRegexp matches the directories of registry caches, named after the sha256 of the URL of their registry
+	cacheDirRegexp = regexp.MustCompile(`^` + defaultRegistryDir + `-[0-9a-f]{64}$`)
+
+	// cloneDirRegexp matches the temporary directories registries are cloned to, left behind by interrupted clones
+	cloneDirRegexp = regexp.MustCompile(`^` + defaultRegistryDir + `[0-9]+$`)
+)
+
+// CacheDir is the directory of a registry cache in the pack home
+type CacheDir struct {
+	// Path of the directory
+	Path string
+
+	// URL of the registry, empty when unknown, e.g. for the temporary directories of interrupted clones
+	URL string
+
+	// LastUsed is when buildpacks were last located in the cache, or when it was last refreshed
+	LastUsed time.Time
+
+	// Size of the files of the cache in bytes
+	Size int64
+
+	// Err is the error removing the directory, if any
+	Err error
+}
+
+// ListCaches returns the directories of the registry caches in home, from the least recently used. The URLs of the
+// caches of git registries are read from their clones, the URLs of other caches are only known for registryURLs.
+func ListCaches(home string, registryURLs []string) ([]CacheDir, error) {
+	entries, err := os.ReadDir(home)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "listing registry caches in %s", style.Symbol(home))
+	}
+
+	urls := map[string]string{}
+	for _, registryURL := range registryURLs {
+		if parsed, err := url.Parse(registryURL); err == nil {
+			urls[cacheRoot(home, parsed)] = parsed.String()
+		}
+	}
+
+	var caches []CacheDir
+	for _, entry := range entries {
+		if !entry.IsDir() || !(cacheDirRegexp.MatchString(entry.Name()) || cloneDirRegexp.MatchString(entry.Name())) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		path := filepath.Join(home, entry.Name())
+		cache := CacheDir{
+			Path:     path,
+			URL:      urls[path],
+			LastUsed: info.ModTime(),
+			Size:     dirSize(path),
+		}
+		if cache.URL == "" && cacheDirRegexp.MatchString(entry.Name()) {
+			cache.URL = cloneURL(path)
+		}
+		caches = append(caches, cache)
+	}
+
+	sort.SliceStable(caches, func(i, j int) bool {
+		return caches[i].LastUsed.Before(caches[j].LastUsed)
+	})
+	return caches, nil
+}
+
+// PruneCaches removes the registry caches in home that weren't used for at least olderThan, or all of them when
+// olderThan is 0, other than the caches in keep. Each cache is removed while holding its lock, so that caches pack
+// is using meanwhile are kept, and the temporary directories of clones are only removed once they are a day old.
+// When dryRun, the caches are returned without being removed.
+func PruneCaches(home string, olderThan time.Duration, dryRun bool, keep ...string) ([]CacheDir, error) {
+	caches, err := ListCaches(home, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var pruned []CacheDir
+	for _, cache := range caches {
+		if contains(keep, cache.Path) || (olderThan > 0 && time.Since(cache.LastUsed) < olderThan) {
+			continue
+		}
+
+		if cloneDirRegexp.MatchString(filepath.Base(cache.Path)) {
+			if time.Since(cache.LastUsed) < autoPruneInterval {
+				continue
+			}
+			if !dryRun {
+				cache.Err = os.RemoveAll(cache.Path)
+			}
+			pruned = append(pruned, cache)
+			continue
+		}
+
+		if !dryRun {
+			removed, err := removeCache(cache.Path, olderThan)
+			if err == nil && !removed {
+				continue
+			}
+			cache.Err = err
+		}
+		pruned = append(pruned, cache)
+	}
+	return pruned, nil
+}
+
+// AutoPruneCaches removes the registry caches in home that weren't used for at least maxAge, other than the caches
+// in keep, at most once every day. Failing to only means the caches are kept until the next time.
+func AutoPruneCaches(logger logging.Logger, home string, maxAge time.Duration, keep ...string) {
+	if maxAge <= 0 {
+		return
+	}
+
+	marker := filepath.Join(home, prunedAtFile)
+	if info, err := os.Stat(marker); err == nil && time.Since(info.ModTime()) < autoPruneInterval {
+		return
+	}
+	if err := os.WriteFile(marker, []byte(time.Now().UTC().Format(time.RFC3339)), 0600); err != nil {
+		logger.Debugf("Unable to record the pruning of the registry caches: %s", err)
+		return
+	}
+
+	pruned, err := PruneCaches(home, maxAge, false, keep...)
+	if err != nil {
+		logger.Debugf("Unable to prune the registry caches: %s", err)
+		return
+	}
+	for _, cache := range pruned {
+		if cache.Err != nil {
+			logger.Debugf("Unable to remove the registry cache %s: %s", style.Symbol(cache.Path), cache.Err)
+			continue
+		}
+		logger.Debugf("Removed the registry cache %s, unused since %s", style.Symbol(cache.Path), cache.LastUsed.Format(time.RFC3339))
+	}
+}
+
+// markUsed records that the cache in root was just used, for it to be kept by PruneCaches. Failing to only means the
+// cache may be pruned sooner.
+func markUsed(root string) {
+	now := time.Now()
+	_ = os.Chtimes(root, now, now)
+}
+
+// removeCache removes the cache in root, unless it was used within olderThan while waiting for its lock. Its lock file
+// is kept, as other pack invocations may be waiting for it.
+func removeCache(root string, olderThan time.Duration) (bool, error) {
+	unlock, err := lockCache(root)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	if olderThan > 0 {
+		if info, err := os.Stat(root); err == nil && time.Since(info.ModTime()) < olderThan {
+			return false, nil
+		}
+	}
+	if err := os.RemoveAll(root); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// cloneURL is the URL of the registry cloned to dir, empty when dir isn't a clone
+func cloneURL(dir string) string {
+	repository, err := git.PlainOpen(dir)
+	if err != nil {
+		return ""
+	}
+	remote, err := repository.Remote("origin")
+	if err != nil || len(remote.Config().URLs) == 0 {
+		return ""
+	}
+	return remote.Config().URLs[0]
+}
+
+// dirSize returns the size of the files of dir, skipping the ones that can't be read
+func dirSize(dir string) int64 {
+	var size int64
+	_ = filepath.WalkDir(dir, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info, err := entry.Info(); err == nil && info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,177 @@
+package registry
+
+import (
+	"bytes"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestPrune(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Prune", testPrune, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testPrune(t *testing.T, when spec.G, it spec.S) {
+	var (
+		home   string
+		recent string
+		stale  string
+		clone  string
+	)
+
+	// makeCache creates the cache of registryURL in home, last used age ago
+	makeCache := func(registryURL string, age time.Duration) string {
+		parsed, err := url.Parse(registryURL)
+		h.AssertNil(t, err)
+		root := cacheRoot(home, parsed)
+		h.AssertNil(t, os.MkdirAll(root, 0750))
+		h.AssertNil(t, os.WriteFile(filepath.Join(root, "entry"), []byte("some-entry"), 0600))
+		used := time.Now().Add(-age)
+		h.AssertNil(t, os.Chtimes(root, used, used))
+		return root
+	}
+
+	it.Before(func() {
+		var err error
+		home, err = os.MkdirTemp("", "pack-home")
+		h.AssertNil(t, err)
+
+		recent = makeCache("https://example.com/recent", time.Hour)
+		stale = makeCache("https://example.com/stale", 40*24*time.Hour)
+
+		clone = filepath.Join(home, "registry123")
+		h.AssertNil(t, os.MkdirAll(clone, 0750))
+		old := time.Now().Add(-48 * time.Hour)
+		h.AssertNil(t, os.Chtimes(clone, old, old))
+
+		h.AssertNil(t, os.WriteFile(filepath.Join(home, "config.toml"), nil, 0600))
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.RemoveAll(home))
+	})
+
+	when("#ListCaches", func() {
+		it("lists the caches from the least recently used", func() {
+			caches, err := ListCaches(home, []string{"https://example.com/recent", "https://example.com/stale"})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, len(caches), 3)
+			h.AssertEq(t, caches[0].Path, stale)
+			h.AssertEq(t, caches[0].URL, "https://example.com/stale")
+			h.AssertEq(t, caches[0].Size, int64(len("some-entry")))
+			h.AssertEq(t, caches[1].Path, clone)
+			h.AssertEq(t, caches[1].URL, "")
+			h.AssertEq(t, caches[2].Path, recent)
+		})
+
+		it("lists nothing when home doesn't exist", func() {
+			caches, err := ListCaches(filepath.Join(home, "missing"), nil)
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(caches), 0)
+		})
+	})
+
+	when("#PruneCaches", func() {
+		it("removes the caches unused for at least olderThan", func() {
+			pruned, err := PruneCaches(home, 30*24*time.Hour, false)
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, len(pruned), 1)
+			h.AssertEq(t, pruned[0].Path, stale)
+			h.AssertNil(t, pruned[0].Err)
+			h.AssertPathDoesNotExists(t, stale)
+			h.AssertPathExists(t, recent)
+			h.AssertPathExists(t, clone)
+		})
+
+		it("removes all the caches and day old clones when olderThan is 0", func() {
+			pruned, err := PruneCaches(home, 0, false)
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, len(pruned), 3)
+			h.AssertPathDoesNotExists(t, stale)
+			h.AssertPathDoesNotExists(t, recent)
+			h.AssertPathDoesNotExists(t, clone)
+			h.AssertPathExists(t, filepath.Join(home, "config.toml"))
+		})
+
+		it("keeps clones that may still be in progress", func() {
+			now := time.Now()
+			h.AssertNil(t, os.Chtimes(clone, now, now))
+
+			_, err := PruneCaches(home, 0, false)
+			h.AssertNil(t, err)
+			h.AssertPathExists(t, clone)
+		})
+
+		it("keeps the caches in keep", func() {
+			_, err := PruneCaches(home, 0, false, recent)
+			h.AssertNil(t, err)
+
+			h.AssertPathExists(t, recent)
+			h.AssertPathDoesNotExists(t, stale)
+		})
+
+		it("removes nothing on a dry run", func() {
+			pruned, err := PruneCaches(home, 0, true)
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, len(pruned), 3)
+			h.AssertPathExists(t, stale)
+			h.AssertPathExists(t, recent)
+			h.AssertPathExists(t, clone)
+		})
+	})
+
+	when("#AutoPruneCaches", func() {
+		var logger logging.Logger
+
+		it.Before(func() {
+			logger = logging.NewLogWithWriters(&bytes.Buffer{}, &bytes.Buffer{})
+		})
+
+		it("removes the caches unused for longer than maxAge", func() {
+			AutoPruneCaches(logger, home, DefaultCacheMaxAge)
+
+			h.AssertPathDoesNotExists(t, stale)
+			h.AssertPathExists(t, recent)
+			h.AssertPathExists(t, filepath.Join(home, prunedAtFile))
+		})
+
+		it("prunes at most once a day", func() {
+			h.AssertNil(t, os.WriteFile(filepath.Join(home, prunedAtFile), nil, 0600))
+
+			AutoPruneCaches(logger, home, DefaultCacheMaxAge)
+			h.AssertPathExists(t, stale)
+		})
+
+		it("keeps the caches when maxAge is 0", func() {
+			AutoPruneCaches(logger, home, 0)
+
+			h.AssertPathExists(t, stale)
+			h.AssertPathDoesNotExists(t, filepath.Join(home, prunedAtFile))
+		})
+	})
+
+	when("#markUsed", func() {
+		it("keeps the cache from being pruned", func() {
+			markUsed(stale)
+
+			_, err := PruneCaches(home, 30*24*time.Hour, false)
+			h.AssertNil(t, err)
+			h.AssertPathExists(t, stale)
+		})
+	})
+}
@@ -1,25 +1,26 @@
 package registry
 
 import (
-	"bufio"
-	"crypto/sha256"
-	"encoding/hex"
 	"encoding/json"
-	"fmt"
+	"net"
+	"net/http"
 	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/pkg/errors"
-	"golang.org/x/mod/semver"
 
+	"github.com/buildpacks/pack/internal/progress"
 	"github.com/buildpacks/pack/internal/style"
-	"github.com/buildpacks/pack/pkg/buildpack"
+	"github.com/buildpacks/pack/internal/term"
 	"github.com/buildpacks/pack/pkg/logging"
 )
 
@@ -27,12 +28,41 @@ const DefaultRegistryURL = "https://github.com/buildpacks/registry-index"
 const DefaultRegistryName = "official"
 const defaultRegistryDir = "registry"
 
-// Cache is a RegistryCache
-type Cache struct {
+// DefaultRefreshInterval is how long after a refresh pack doesn't pull the registry again, unless configured otherwise
+const DefaultRefreshInterval = 5 * time.Minute
+
+// refreshedAtFile records when the cache was last refreshed, in its git directory so that it isn't part of the index
+const refreshedAtFile = "pack-refreshed-at"
+
+// activeRemoteFile records the URL the cache was last cloned or pulled from, the registry or one of its mirrors
+const activeRemoteFile = "pack-active-remote"
+
+// GitCache is a RegistryCache of a registry served as a git repository, which it clones
+type GitCache struct {
 	logger      logging.Logger
 	url         *url.URL
 	Root        string
 	RegistryDir string
+
+	CacheSettings
+}
+
+// WithShallowClone clones the registry with only its latest commit, which is all the cache needs and is much faster
+// to fetch than its entire history. The entire history is cloned instead when the registry doesn't support shallow
+// clones, or when the shallow clone turns out to be unusable. It has no effect on registries served over HTTP.
+func WithShallowClone() CacheOption {
+	return func(s *CacheSettings) {
+		s.shallow = true
+	}
+}
+
+// WithMirrors falls back to the mirrors of the registry, in order, when cloning or pulling the registry fails because
+// it can't be reached. The registry is tried first again on every refresh. It has no effect on registries served
+// over HTTP.
+func WithMirrors(mirrors ...string) CacheOption {
+	return func(s *CacheSettings) {
+		s.mirrors = mirrors
+	}
 }
 
 const GithubIssueTitleTemplate = "{{ if .Yanked }}YANK{{ else }}ADD{{ end }} {{.Namespace}}/{{.Name}}@{{.Version}}"
@@ -49,78 +79,153 @@ type Entry struct {
 }
 
 // NewDefaultRegistryCache creates a new registry cache with default options
-func NewDefaultRegistryCache(logger logging.Logger, home string) (Cache, error) {
-	return NewRegistryCache(logger, home, DefaultRegistryURL)
+func NewDefaultRegistryCache(logger logging.Logger, home string, ops ...CacheOption) (GitCache, error) {
+	return NewRegistryCache(logger, home, DefaultRegistryURL, ops...)
 }
 
 // NewRegistryCache creates a new registry cache
-func NewRegistryCache(logger logging.Logger, home, registryURL string) (Cache, error) {
+func NewRegistryCache(logger logging.Logger, home, registryURL string, ops ...CacheOption) (GitCache, error) {
 	if _, err := os.Stat(home); err != nil {
-		return Cache{}, errors.Wrapf(err, "finding home %s", home)
+		return GitCache{}, errors.Wrapf(err, "finding home %s", home)
 	}
 
 	normalizedURL, err := url.Parse(registryURL)
 	if err != nil {
-		return Cache{}, errors.Wrapf(err, "parsing registry url %s", registryURL)
+		return GitCache{}, errors.Wrapf(err, "parsing registry url %s", registryURL)
 	}
 
-	key := sha256.New()
-	key.Write([]byte(normalizedURL.String()))
-	cacheDir := fmt.Sprintf("%s-%s", defaultRegistryDir, hex.EncodeToString(key.Sum(nil)))
-
-	return Cache{
+	cache := GitCache{
 		url:    normalizedURL,
 		logger: logger,
-		Root:   filepath.Join(home, cacheDir),
-	}, nil
+		Root:   cacheRoot(home, normalizedURL),
+	}
+	for _, op := range ops {
+		op(&cache.CacheSettings)
+	}
+	return cache, nil
+}
+
+// URL returns the URL of the registry
+func (r *GitCache) URL() string {
+	return r.url.String()
+}
+
+// Dir returns the directory the index is cloned to
+func (r *GitCache) Dir() string {
+	return r.Root
+}
+
+// ActiveURL returns the URL the cache was last cloned or pulled from, which is the URL of the registry unless it
+// couldn't be reached and one of its mirrors was used instead
+func (r *GitCache) ActiveURL() string {
+	contents, err := os.ReadFile(filepath.Join(r.Root, ".git", activeRemoteFile))
+	if err != nil || len(strings.TrimSpace(string(contents))) == 0 {
+		return r.URL()
+	}
+	return strings.TrimSpace(string(contents))
 }
 
 // LocateBuildpack stored in registry
-func (r *Cache) LocateBuildpack(bp string) (Buildpack, error) {
-	err := r.Refresh()
-	if err != nil {
-		return Buildpack{}, errors.Wrap(err, "refreshing cache")
+func (r *GitCache) LocateBuildpack(bp string) (Buildpack, error) {
+	if err := r.prepareLocate(); err != nil {
+		return Buildpack{}, err
 	}
+	return locateBuildpack(r.logger, r.Root, bp, r.CacheSettings)
+}
 
-	ns, name, version, err := buildpack.ParseRegistryID(bp)
-	if err != nil {
-		return Buildpack{}, errors.Wrap(err, "parsing buildpacks registry id")
+// LocateBuildpacks stored in registry, refreshing it once for all of them
+func (r *GitCache) LocateBuildpacks(bps []string) ([]LocatedBuildpack, error) {
+	if err := r.prepareLocate(); err != nil {
+		return nil, err
+	}
+	return locateBuildpacks(r.logger, r.Root, bps, r.CacheSettings), nil
+}
+
+// prepareLocate refreshes the cache before buildpacks are located in it, unless offline
+func (r *GitCache) prepareLocate() error {
+	if r.Offline {
+		if err := RequireCache(r.logger, r); err != nil {
+			return err
+		}
+	} else if err := r.Refresh(); err != nil {
+		// an existing cache still locates buildpacks when the registry can't be reached, e.g. offline
+		if _, statErr := os.Stat(r.Root); statErr != nil {
+			return errors.Wrap(err, "refreshing cache")
+		}
+		r.logger.Warnf("Unable to refresh the registry cache, using the cached index: %s", err)
 	}
 
-	entry, err := r.readEntry(ns, name)
+	markUsed(r.Root)
+	return nil
+}
+
+// IDs returns the ids of the buildpacks in the registry cache, sorted. The cache isn't refreshed, and there are no ids
+// when it doesn't exist yet.
+func (r *GitCache) IDs() ([]string, error) {
+	return listIDs(r.Root)
+}
+
+// Versions returns the versions of a buildpack in the registry cache that weren't yanked, from the highest. The cache
+// isn't refreshed.
+func (r *GitCache) Versions(id string) ([]string, error) {
+	return listVersions(r.Root, id)
+}
+
+// ListBuildpacks calls fn with the id and entry of each buildpack in the registry cache, sorted by id. The cache isn't
+// refreshed, and there are no buildpacks when it doesn't exist yet.
+func (r *GitCache) ListBuildpacks(fn func(id string, entry Entry) error) error {
+	return listEntries(r.Root, fn)
+}
+
+// Refresh local Registry Cache, holding the lock of the cache so that concurrent pack invocations take turns. A cache
+// another invocation refreshed while this one waited isn't refreshed again within the refresh interval.
+func (r *GitCache) Refresh() error {
+	unlock, err := lockCache(r.Root)
 	if err != nil {
-		return Buildpack{}, errors.Wrap(err, "reading entry")
+		return err
 	}
+	defer unlock()
 
-	if len(entry.Buildpacks) > 0 {
-		if version == "" {
-			highestVersion := entry.Buildpacks[0]
-			if len(entry.Buildpacks) > 1 {
-				for _, bp := range entry.Buildpacks[1:] {
-					if semver.Compare(fmt.Sprintf("v%s", bp.Version), fmt.Sprintf("v%s", highestVersion.Version)) > 0 {
-						highestVersion = bp
-					}
-				}
-			}
-			return highestVersion, Validate(highestVersion)
-		}
+	if !r.ForceRefresh && r.refreshedWithin(r.RefreshInterval) {
+		r.logger.Debugf("Registry cache for %s/%s was refreshed less than %s ago, not refreshing it", r.url.Host, r.url.Path, r.RefreshInterval)
+		return nil
+	}
 
-		for _, bpIndex := range entry.Buildpacks {
-			if bpIndex.Version == version {
-				return bpIndex, Validate(bpIndex)
-			}
-		}
-		return Buildpack{}, fmt.Errorf("could not find version for buildpack: %s", bp)
+	if err := r.pull(); err != nil {
+		return err
 	}
+	r.recordRefresh()
+	return nil
+}
 
-	return Buildpack{}, fmt.Errorf("no entries for buildpack: %s", bp)
+// refreshedWithin is true when the cache was last refreshed less than interval ago
+func (r *GitCache) refreshedWithin(interval time.Duration) bool {
+	if interval <= 0 {
+		return false
+	}
+	contents, err := os.ReadFile(filepath.Join(r.Root, ".git", refreshedAtFile))
+	if err != nil {
+		return false
+	}
+	refreshedAt, err := time.Parse(time.RFC3339, strings.TrimSpace(string(contents)))
+	if err != nil {
+		return false
+	}
+	return time.Since(refreshedAt) < interval
 }
 
-// Refresh local Registry Cache
-func (r *Cache) Refresh() error {
+// recordRefresh records that the cache was just refreshed, failing to only means the next refresh pulls again
+func (r *GitCache) recordRefresh() {
+	path := filepath.Join(r.Root, ".git", refreshedAtFile)
+	if err := os.WriteFile(path, []byte(time.Now().UTC().Format(time.RFC3339)), 0600); err != nil {
+		r.logger.Debugf("Unable to record the refresh of the registry cache: %s", err)
+	}
+}
+
+func (r *GitCache) pull() error {
 	r.logger.Debugf("Refreshing registry cache for %s/%s", r.url.Host, r.url.Path)
 
-	if err := r.Initialize(); err != nil {
+	if err := r.initialize(); err != nil {
 		return errors.Wrapf(err, "initializing (%s)", r.Root)
 	}
 
@@ -134,15 +239,66 @@ func (r *Cache) Refresh() error {
 		return errors.Wrapf(err, "reading (%s)", r.Root)
 	}
 
-	err = w.Pull(&git.PullOptions{RemoteName: "origin"})
-	if err == git.NoErrAlreadyUpToDate {
+	remotes, err := r.remotes()
+	if err != nil {
+		return err
+	}
+
+	shallow := isShallow(repository)
+	for i, remote := range remotes {
+		auth, err := r.authFor(remote)
+		if err != nil {
+			return err
+		}
+
+		proxy, err := r.proxyOptions(remote)
+		if err != nil {
+			return err
+		}
+
+		pullOptions := &git.PullOptions{RemoteName: "origin", RemoteURL: remote.String(), Auth: auth, ProxyOptions: proxy}
+		if shallow {
+			pullOptions.Depth = 1
+		}
+		err = w.Pull(pullOptions)
+		if isNetworkError(err) && i < len(remotes)-1 {
+			r.logger.Warnf("Unable to reach %s, falling back to mirror %s: %s", style.Symbol(remote.Redacted()), style.Symbol(remotes[i+1].Redacted()), err)
+			continue
+		}
+		if err == nil || err == git.NoErrAlreadyUpToDate {
+			recordRemote(r.Root, remote)
+		}
+		return r.pulled(err, shallow)
+	}
+	return nil
+}
+
+// pulled handles the result of pulling the registry, or one of its mirrors, into the cache
+func (r *GitCache) pulled(err error, shallow bool) error {
+	switch {
+	case err == nil, err == git.NoErrAlreadyUpToDate:
 		return nil
+	case shallow && err != git.ErrNonFastForwardUpdate && !isAuthError(err):
+		// pulling into a shallow clone fails when the history it needs is missing
+		r.logger.Debugf("Pulling into the shallow registry cache failed, cloning its entire history: %s", err)
+		return r.unshallow()
+	default:
+		return r.authError(err)
 	}
-	return err
 }
 
-// Initialize a local Registry Cache
-func (r *Cache) Initialize() error {
+// Initialize a local Registry Cache, holding the lock of the cache
+func (r *GitCache) Initialize() error {
+	unlock, err := lockCache(r.Root)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return r.initialize()
+}
+
+func (r *GitCache) initialize() error {
 	_, err := os.Stat(r.Root)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -154,6 +310,10 @@ func (r *Cache) Initialize() error {
 	}
 
 	if err := r.validateCache(); err != nil {
+		if r.isShallowCache() {
+			r.logger.Debugf("Shallow registry cache is invalid, cloning its entire history: %s", err)
+			return r.unshallow()
+		}
 		err = os.RemoveAll(r.Root)
 		if err != nil {
 			return errors.Wrap(err, "resetting registry cache")
@@ -167,8 +327,23 @@ func (r *Cache) Initialize() error {
 	return nil
 }
 
+// unshallow replaces the cache with a clone of the entire history of the registry
+func (r *GitCache) unshallow() error {
+	if err := os.RemoveAll(r.Root); err != nil {
+		return errors.Wrap(err, "resetting registry cache")
+	}
+
+	shallow := r.shallow
+	r.shallow = false
+	defer func() { r.shallow = shallow }()
+	if err := r.CreateCache(); err != nil {
+		return errors.Wrap(err, "rebuilding registry cache")
+	}
+	return nil
+}
+
 // CreateCache creates the cache on the filesystem
-func (r *Cache) CreateCache() error {
+func (r *GitCache) CreateCache() error {
 	var repository *git.Repository
 	r.logger.Debugf("Creating registry cache for %s/%s", r.url.Host, r.url.Path)
 
@@ -180,7 +355,20 @@ func (r *Cache) CreateCache() error {
 	r.RegistryDir = registryDir
 
 	if r.url.Host == "dev.azure.com" {
-		err = exec.Command("git", "clone", r.url.String(), r.RegistryDir).Run()
+		args := []string{"clone", r.url.String(), r.RegistryDir}
+		if r.proxy != "" {
+			// native git reads the proxy of the environment itself
+			args = append([]string{"-c", "http.proxy=" + r.proxy}, args...)
+		}
+		if r.shallow {
+			args = append(args, "--depth", "1")
+		}
+		display := r.cloneDisplay(r.url)
+		cmd := exec.Command("git", append(args, "--progress")...)
+		cmd.Stderr = display
+		display.Start()
+		err = cmd.Run()
+		display.Done(err)
 		if err != nil {
 			return errors.Wrap(err, "cloning remote registry with native git")
 		}
@@ -190,11 +378,9 @@ func (r *Cache) CreateCache() error {
 			return errors.Wrap(err, "opening remote registry clone")
 		}
 	} else {
-		repository, err = git.PlainClone(r.RegistryDir, false, &git.CloneOptions{
-			URL: r.url.String(),
-		})
+		repository, err = r.cloneRemotes()
 		if err != nil {
-			return errors.Wrap(err, "cloning remote registry")
+			return errors.Wrap(r.authError(err), "cloning remote registry")
 		}
 	}
 
@@ -214,7 +400,169 @@ func (r *Cache) CreateCache() error {
 	return nil
 }
 
-func (r *Cache) validateCache() error {
+// cloneRemotes clones the registry into RegistryDir, falling back to its mirrors in order while they can't be reached.
+// The origin of a clone of a mirror is the registry, so that the registry is tried first again on the next refresh.
+func (r *GitCache) cloneRemotes() (*git.Repository, error) {
+	remotes, err := r.remotes()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, remote := range remotes {
+		repository, err := r.cloneRemote(remote)
+		if isNetworkError(err) && i < len(remotes)-1 {
+			r.logger.Warnf("Unable to reach %s, falling back to mirror %s: %s", style.Symbol(remote.Redacted()), style.Symbol(remotes[i+1].Redacted()), err)
+			if err := os.RemoveAll(r.RegistryDir); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if i > 0 {
+			if err := setOrigin(repository, r.url.String()); err != nil {
+				return nil, err
+			}
+		}
+		recordRemote(r.RegistryDir, remote)
+		return repository, nil
+	}
+	return nil, errors.New("no registry to clone")
+}
+
+// cloneRemote clones remote, the registry or one of its mirrors, into RegistryDir, cloning its entire history when a
+// shallow clone fails
+func (r *GitCache) cloneRemote(remote *url.URL) (*git.Repository, error) {
+	repository, err := r.clone(remote, r.shallow)
+	if err != nil && r.shallow && !isNetworkError(err) {
+		r.logger.Debugf("Shallow clone of the registry failed, cloning its entire history: %s", err)
+		if err := os.RemoveAll(r.RegistryDir); err != nil {
+			return nil, err
+		}
+		repository, err = r.clone(remote, false)
+	}
+	return repository, err
+}
+
+// clone clones remote into RegistryDir, with only its latest commit when shallow
+func (r *GitCache) clone(remote *url.URL, shallow bool) (*git.Repository, error) {
+	auth, err := r.authFor(remote)
+	if err != nil {
+		return nil, err
+	}
+
+	proxy, err := r.proxyOptions(remote)
+	if err != nil {
+		return nil, err
+	}
+
+	display := r.cloneDisplay(remote)
+	options := &git.CloneOptions{URL: remote.String(), Auth: auth, ProxyOptions: proxy, Progress: display}
+	if shallow {
+		options.Depth = 1
+	}
+	display.Start()
+	repository, err := git.PlainClone(r.RegistryDir, false, options)
+	display.Done(err)
+	if err != nil {
+		return nil, err
+	}
+	if shallow {
+		if err := validateHead(repository); err != nil {
+			return nil, err
+		}
+	}
+	return repository, nil
+}
+
+// cloneDisplay shows the progress of cloning remote, so that a clone of a large registry doesn't look hung
+func (r *GitCache) cloneDisplay(remote *url.URL) *progress.CloneDisplay {
+	writer := logging.GetWriterForLevel(r.logger, logging.InfoLevel)
+	_, isTerm := term.IsTerminal(writer)
+	return progress.NewCloneDisplay(writer, isTerm, remote.Redacted())
+}
+
+// remotes are the URLs the cache is cloned and pulled from, the registry followed by its mirrors
+func (r *GitCache) remotes() ([]*url.URL, error) {
+	remotes := []*url.URL{r.url}
+	for _, mirror := range r.mirrors {
+		parsed, err := url.Parse(mirror)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing registry mirror url %s", style.Symbol(mirror))
+		}
+		remotes = append(remotes, parsed)
+	}
+	return remotes, nil
+}
+
+// setOrigin points the origin of repository at originURL
+func setOrigin(repository *git.Repository, originURL string) error {
+	cfg, err := repository.Config()
+	if err != nil {
+		return errors.Wrap(err, "reading registry cache config")
+	}
+	origin, ok := cfg.Remotes["origin"]
+	if !ok {
+		return errors.New("registry cache has no origin")
+	}
+	origin.URLs = []string{originURL}
+	return errors.Wrap(repository.SetConfig(cfg), "writing registry cache config")
+}
+
+// recordRemote records that the clone in dir was just cloned or pulled from remote, failing to only means ActiveURL
+// is out of date
+func recordRemote(dir string, remote *url.URL) {
+	_ = os.WriteFile(filepath.Join(dir, ".git", activeRemoteFile), []byte(remote.Redacted()), 0600)
+}
+
+// isNetworkError is true when a remote couldn't be reached, e.g. because it or the network is down, rather than when
+// it refused the cache
+func isNetworkError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	var unexpected *plumbing.UnexpectedError
+	if errors.As(err, &unexpected) {
+		var httpErr *githttp.Err
+		return errors.As(unexpected.Err, &httpErr) && httpErr.StatusCode() >= http.StatusInternalServerError
+	}
+	return false
+}
+
+// validateHead checks that the commit checked out and its files can be read, which a broken shallow clone fails
+func validateHead(repository *git.Repository) error {
+	head, err := repository.Head()
+	if err != nil {
+		return errors.Wrap(err, "reading HEAD")
+	}
+	commit, err := repository.CommitObject(head.Hash())
+	if err != nil {
+		return errors.Wrapf(err, "reading commit %s", head.Hash())
+	}
+	if _, err := commit.Tree(); err != nil {
+		return errors.Wrapf(err, "reading the files of commit %s", head.Hash())
+	}
+	return nil
+}
+
+// isShallow is true when the repository has only part of the history of the registry
+func isShallow(repository *git.Repository) bool {
+	shallow, err := repository.Storer.Shallow()
+	return err == nil && len(shallow) > 0
+}
+
+func (r *GitCache) isShallowCache() bool {
+	repository, err := git.PlainOpen(r.Root)
+	return err == nil && isShallow(repository)
+}
+
+func (r *GitCache) validateCache() error {
 	r.logger.Debugf("Validating registry cache for %s/%s", r.url.Host, r.url.Path)
 
 	repository, err := git.PlainOpen(r.Root)
@@ -228,7 +576,10 @@ func (r *Cache) validateCache() error {
 	}
 
 	for _, remote := range remotes {
-		if remote.Config().Name == "origin" && remotes[0].Config().URLs[0] != r.url.String() {
+		if remote.Config().Name == "origin" && len(remote.Config().URLs) > 0 && remote.Config().URLs[0] == r.url.String() {
+			if isShallow(repository) {
+				return validateHead(repository)
+			}
 			return nil
 		}
 	}
@@ -236,7 +587,7 @@ func (r *Cache) validateCache() error {
 }
 
 // Commit a Buildpack change
-func (r *Cache) Commit(b Buildpack, username, msg string) error {
+func (r *GitCache) Commit(b Buildpack, username, msg string) error {
 	r.logger.Debugf("Creating commit in registry cache")
 
 	if msg == "" {
@@ -280,7 +631,7 @@ func (r *Cache) Commit(b Buildpack, username, msg string) error {
 	return nil
 }
 
-func (r *Cache) writeEntry(b Buildpack) (string, error) {
+func (r *GitCache) writeEntry(b Buildpack) (string, error) {
 	var ns = b.Namespace
 	var name = b.Name
 
@@ -295,7 +646,7 @@ func (r *Cache) writeEntry(b Buildpack) (string, error) {
 		}
 	} else {
 		if _, err := os.Stat(index); err == nil {
-			entry, err := r.readEntry(ns, name)
+			entry, err := readEntry(r.Root, ns, name)
 			if err != nil {
 				return "", errors.Wrapf(err, "reading existing buildpack entries")
 			}
@@ -333,38 +684,3 @@ func (r *Cache) writeEntry(b Buildpack) (string, error) {
 
 	return index, nil
 }
-
-func (r *Cache) readEntry(ns, name string) (Entry, error) {
-	index, err := IndexPath(r.Root, ns, name)
-	if err != nil {
-		return Entry{}, err
-	}
-
-	if _, err := os.Stat(index); err != nil {
-		return Entry{}, errors.Wrapf(err, "finding buildpack: %s/%s", ns, name)
-	}
-
-	file, err := os.Open(filepath.Clean(index))
-	if err != nil {
-		return Entry{}, errors.Wrapf(err, "opening index for buildpack: %s/%s", ns, name)
-	}
-	defer file.Close()
-
-	entry := Entry{}
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		var bp Buildpack
-		err = json.Unmarshal([]byte(scanner.Text()), &bp)
-		if err != nil {
-			return Entry{}, errors.Wrapf(err, "parsing index for buildpack: %s/%s", ns, name)
-		}
-
-		entry.Buildpacks = append(entry.Buildpacks, bp)
-	}
-
-	if err := scanner.Err(); err != nil {
-		return entry, errors.Wrapf(err, "reading index for buildpack: %s/%s", ns, name)
-	}
-
-	return entry, nil
-}
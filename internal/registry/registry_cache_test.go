@@ -2,19 +2,24 @@ package registry
 
 import (
 	"bytes"
+	"io"
 	"net/url"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/heroku/color"
+	"github.com/pkg/errors"
 	"github.com/sclevine/spec"
 	"github.com/sclevine/spec/report"
 
+	"github.com/buildpacks/pack/pkg/errcode"
 	"github.com/buildpacks/pack/pkg/logging"
 	h "github.com/buildpacks/pack/testhelpers"
 )
@@ -47,6 +52,14 @@ func testRegistryCache(t *testing.T, when spec.G, it spec.S) {
 		_ = os.RemoveAll(tmpDir)
 	})
 
+	// danglingRoot is a cache root in tmpDir the clone can't be moved to, as it's a symlink to a missing directory, so
+	// that neither the failed cache nor its lock land in the package directory
+	danglingRoot := func() string {
+		root := filepath.Join(tmpDir, "dangling-cache")
+		h.AssertNil(t, os.Symlink(filepath.Join(tmpDir, "missing", "cache"), root))
+		return root
+	}
+
 	when("#NewDefaultRegistryCache", func() {
 		it("creates a RegistryCache with default URL", func() {
 			registryCache, err := NewDefaultRegistryCache(logger, tmpDir)
@@ -89,9 +102,89 @@ func testRegistryCache(t *testing.T, when spec.G, it spec.S) {
 		})
 	})
 
+	when("#IDs", func() {
+		it("lists the buildpacks of the cache without creating it", func() {
+			registryCache, err := NewRegistryCache(logger, tmpDir, registryFixture)
+			h.AssertNil(t, err)
+
+			ids, err := registryCache.IDs()
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(ids), 0)
+			h.AssertPathDoesNotExists(t, registryCache.Root)
+
+			h.AssertNil(t, registryCache.Initialize())
+			ids, err = registryCache.IDs()
+			h.AssertNil(t, err)
+			h.AssertEq(t, ids, []string{"example/foo", "example/java"})
+		})
+	})
+
+	when("#Versions", func() {
+		it("lists the versions of a buildpack from the highest", func() {
+			registryCache, err := NewRegistryCache(logger, tmpDir, registryFixture)
+			h.AssertNil(t, err)
+			h.AssertNil(t, registryCache.Initialize())
+
+			versions, err := registryCache.Versions("example/foo")
+			h.AssertNil(t, err)
+			h.AssertEq(t, versions, []string{"1.2.0", "1.1.0", "1.0.0"})
+
+			_, err = registryCache.Versions("example/missing")
+			h.AssertError(t, err, "finding buildpack: example/missing")
+		})
+	})
+
+	when("#ListBuildpacks", func() {
+		it("lists the entries of the buildpacks of the cache by id", func() {
+			registryCache, err := NewRegistryCache(logger, tmpDir, registryFixture)
+			h.AssertNil(t, err)
+			h.AssertNil(t, registryCache.Initialize())
+
+			var ids []string
+			err = registryCache.ListBuildpacks(func(id string, entry Entry) error {
+				ids = append(ids, id)
+				for _, bp := range entry.Buildpacks {
+					h.AssertEq(t, bp.Namespace+"/"+bp.Name, id)
+				}
+				if id == "example/foo" {
+					h.AssertEq(t, len(entry.Buildpacks), 3)
+				}
+				return nil
+			})
+			h.AssertNil(t, err)
+			h.AssertEq(t, ids, []string{"example/foo", "example/java"})
+		})
+
+		it("stops at the first error of fn", func() {
+			registryCache, err := NewRegistryCache(logger, tmpDir, registryFixture)
+			h.AssertNil(t, err)
+			h.AssertNil(t, registryCache.Initialize())
+
+			stop := errors.New("stop")
+			calls := 0
+			err = registryCache.ListBuildpacks(func(id string, entry Entry) error {
+				calls++
+				return stop
+			})
+			h.AssertTrue(t, err == stop)
+			h.AssertEq(t, calls, 1)
+		})
+
+		it("lists nothing when the cache doesn't exist", func() {
+			registryCache, err := NewRegistryCache(logger, tmpDir, registryFixture)
+			h.AssertNil(t, err)
+
+			err = registryCache.ListBuildpacks(func(id string, entry Entry) error {
+				t.Fatalf("unexpected buildpack %s", id)
+				return nil
+			})
+			h.AssertNil(t, err)
+		})
+	})
+
 	when("#LocateBuildpack", func() {
 		var (
-			registryCache Cache
+			registryCache GitCache
 		)
 
 		it.Before(func() {
@@ -119,6 +212,186 @@ func testRegistryCache(t *testing.T, when spec.G, it spec.S) {
 			h.AssertEq(t, bp.Version, "1.0.0")
 		})
 
+		when("versions were yanked", func() {
+			var yank = func(version string) {
+				index, err := IndexPath(registryCache.Root, "example", "foo")
+				h.AssertNil(t, err)
+				contents, err := os.ReadFile(index)
+				h.AssertNil(t, err)
+				h.AssertNil(t, os.WriteFile(index, []byte(strings.Replace(string(contents), `"version":"`+version+`","yanked":false`, `"version":"`+version+`","yanked":true`, 1)), 0600))
+			}
+
+			it.Before(func() {
+				h.AssertNil(t, registryCache.Refresh())
+				yank("1.2.0")
+				registryCache.Offline = true
+			})
+
+			it("locates the highest version that wasn't yanked", func() {
+				bp, err := registryCache.LocateBuildpack("example/foo")
+				h.AssertNil(t, err)
+				h.AssertEq(t, bp.Version, "1.1.0")
+			})
+
+			it("errors when the version requested was yanked", func() {
+				_, err := registryCache.LocateBuildpack("example/foo@1.2.0")
+				h.AssertError(t, err, "version '1.2.0' of buildpack 'example/foo' was yanked, pick another version or use --allow-yanked")
+				h.AssertEq(t, errcode.Of(err), errcode.RegistryEntryYanked)
+			})
+
+			it("errors when all versions were yanked", func() {
+				yank("1.0.0")
+				yank("1.1.0")
+
+				_, err := registryCache.LocateBuildpack("example/foo")
+				h.AssertError(t, err, "all versions of buildpack 'example/foo' were yanked")
+				h.AssertEq(t, errcode.Of(err), errcode.RegistryEntryYanked)
+			})
+
+			when("yanked versions are allowed", func() {
+				it.Before(func() {
+					registryCache.AllowYanked = true
+				})
+
+				it("locates the version requested with a warning", func() {
+					bp, err := registryCache.LocateBuildpack("example/foo@1.2.0")
+					h.AssertNil(t, err)
+					h.AssertEq(t, bp.Version, "1.2.0")
+					h.AssertContains(t, outBuf.String(), "Version '1.2.0' of buildpack 'example/foo' was yanked")
+				})
+
+				it("locates the highest version", func() {
+					bp, err := registryCache.LocateBuildpack("example/foo@^1.0")
+					h.AssertNil(t, err)
+					h.AssertEq(t, bp.Version, "1.2.0")
+				})
+			})
+		})
+
+		when("there are pre-releases", func() {
+			var index string
+
+			var release = func(version string) {
+				file, err := os.OpenFile(index, os.O_APPEND|os.O_WRONLY, 0600)
+				h.AssertNil(t, err)
+				defer file.Close()
+				_, err = file.WriteString(`{"ns":"example","name":"foo","version":"` + version + `","yanked":false,"addr":"example.com/some/package@sha256:8c27fe111c11b722081701dfed3bd55e039b9ce92865473cf4cdfa918071c566"}` + "\n")
+				h.AssertNil(t, err)
+			}
+
+			it.Before(func() {
+				h.AssertNil(t, registryCache.Refresh())
+				index, err = IndexPath(registryCache.Root, "example", "foo")
+				h.AssertNil(t, err)
+				release("2.0.0-rc.1")
+				registryCache.Offline = true
+			})
+
+			it("locates the highest version that isn't a pre-release", func() {
+				bp, err := registryCache.LocateBuildpack("example/foo")
+				h.AssertNil(t, err)
+				h.AssertEq(t, bp.Version, "1.2.0")
+			})
+
+			it("locates a pre-release requested explicitly", func() {
+				bp, err := registryCache.LocateBuildpack("example/foo@2.0.0-rc.1")
+				h.AssertNil(t, err)
+				h.AssertEq(t, bp.Version, "2.0.0-rc.1")
+			})
+
+			it("errors when there are only pre-releases", func() {
+				h.AssertNil(t, os.WriteFile(index, nil, 0600))
+				release("2.0.0-rc.1")
+
+				_, err := registryCache.LocateBuildpack("example/foo")
+				h.AssertError(t, err, "buildpack 'example/foo' only has pre-release versions")
+				h.AssertEq(t, errcode.Of(err), errcode.RegistryEntryMissing)
+			})
+
+			when("pre-releases are included", func() {
+				it.Before(func() {
+					registryCache.IncludePrereleases = true
+				})
+
+				it("locates the highest version", func() {
+					bp, err := registryCache.LocateBuildpack("example/foo")
+					h.AssertNil(t, err)
+					h.AssertEq(t, bp.Version, "2.0.0-rc.1")
+				})
+			})
+		})
+
+		when("the version is a semver range", func() {
+			it("locates the highest version in the range", func() {
+				for versionRange, expected := range map[string]string{
+					"^1.0":          "1.2.0",
+					"~1.1":          "1.1.0",
+					"1.x":           "1.2.0",
+					">=1.0, <1.2":   "1.1.0",
+					"^1.1.0":        "1.2.0",
+					"1.0.0 - 1.1.5": "1.1.0",
+				} {
+					bp, err := registryCache.LocateBuildpack("example/foo@" + versionRange)
+					h.AssertNil(t, err)
+					h.AssertEq(t, bp.Version, expected)
+				}
+			})
+
+			it("skips yanked versions", func() {
+				h.AssertNil(t, registryCache.Refresh())
+				index, err := IndexPath(registryCache.Root, "example", "foo")
+				h.AssertNil(t, err)
+				contents, err := os.ReadFile(index)
+				h.AssertNil(t, err)
+				h.AssertNil(t, os.WriteFile(index, []byte(strings.Replace(string(contents), `"version":"1.2.0","yanked":false`, `"version":"1.2.0","yanked":true`, 1)), 0600))
+				registryCache.Offline = true
+
+				bp, err := registryCache.LocateBuildpack("example/foo@^1.0")
+				h.AssertNil(t, err)
+				h.AssertEq(t, bp.Version, "1.1.0")
+			})
+
+			it("errors when no version is in the range", func() {
+				_, err := registryCache.LocateBuildpack("example/foo@^2.0")
+				h.AssertError(t, err, "no version of buildpack 'example/foo' matches '^2.0'")
+				h.AssertEq(t, errcode.Of(err), errcode.RegistryEntryMissing)
+			})
+		})
+
+		it("locates a buildpack in the existing cache when the registry can't be reached", func() {
+			_, err := registryCache.LocateBuildpack("example/foo")
+			h.AssertNil(t, err)
+			h.AssertNil(t, os.RemoveAll(registryFixture))
+
+			bp, err := registryCache.LocateBuildpack("example/foo@1.1.0")
+			h.AssertNil(t, err)
+			h.AssertEq(t, bp.Version, "1.1.0")
+			h.AssertContains(t, outBuf.String(), "Unable to refresh the registry cache, using the cached index")
+		})
+
+		when("offline", func() {
+			it.Before(func() {
+				registryCache.Offline = true
+			})
+
+			it("locates a buildpack in the existing cache without refreshing it", func() {
+				h.AssertNil(t, registryCache.Initialize())
+				h.AssertNil(t, os.RemoveAll(registryFixture))
+
+				bp, err := registryCache.LocateBuildpack("example/foo@1.1.0")
+				h.AssertNil(t, err)
+				h.AssertEq(t, bp.Version, "1.1.0")
+				h.AssertContains(t, outBuf.String(), "Using the registry cache without refreshing it as pack is offline")
+				h.AssertNotContains(t, outBuf.String(), "Unable to refresh the registry cache")
+			})
+
+			it("errors when there is no cache yet", func() {
+				_, err := registryCache.LocateBuildpack("example/foo")
+				h.AssertError(t, err, "doesn't exist yet, it's created by running without --offline")
+				h.AssertPathDoesNotExists(t, registryCache.Root)
+			})
+		})
+
 		it("locates a buildpack with version", func() {
 			bp, err := registryCache.LocateBuildpack("example/foo@1.1.0")
 			h.AssertNil(t, err)
@@ -150,9 +423,45 @@ func testRegistryCache(t *testing.T, when spec.G, it spec.S) {
 		})
 	})
 
+	when("#LocateBuildpacks", func() {
+		var (
+			registryCache GitCache
+		)
+
+		it.Before(func() {
+			registryCache, err = NewRegistryCache(logger, tmpDir, registryFixture)
+			h.AssertNil(t, err)
+		})
+
+		it("locates every buildpack, with the errors of the ones that fail", func() {
+			located, err := registryCache.LocateBuildpacks([]string{"example/foo", "example/missing", "example/java@1.0.0", "quack"})
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(located), 4)
+
+			h.AssertEq(t, located[0].ID, "example/foo")
+			h.AssertNil(t, located[0].Err)
+			h.AssertEq(t, located[0].Buildpack.Version, "1.2.0")
+
+			h.AssertEq(t, located[1].ID, "example/missing")
+			h.AssertError(t, located[1].Err, "reading entry")
+
+			h.AssertNil(t, located[2].Err)
+			h.AssertEq(t, located[2].Buildpack.Name, "java")
+
+			h.AssertError(t, located[3].Err, "parsing buildpacks registry id")
+		})
+
+		it("errors offline when there is no cache yet", func() {
+			registryCache.Offline = true
+
+			_, err := registryCache.LocateBuildpacks([]string{"example/foo"})
+			h.AssertError(t, err, "doesn't exist yet")
+		})
+	})
+
 	when("#Refresh", func() {
 		var (
-			registryCache Cache
+			registryCache GitCache
 		)
 
 		it.Before(func() {
@@ -186,24 +495,187 @@ func testRegistryCache(t *testing.T, when spec.G, it spec.S) {
 				h.AssertNil(t, registryCache.Refresh())
 				h.AssertGitHeadEq(t, registryFixture, registryCache.Root)
 			})
+
+			it("pulls the latest index into a shallow cache", func() {
+				registryCache, err = NewRegistryCache(logger, tmpDir, registryFixture, WithShallowClone())
+				h.AssertNil(t, err)
+				h.AssertNil(t, registryCache.Refresh())
+
+				r, err := git.PlainOpen(registryFixture)
+				h.AssertNil(t, err)
+				w, err := r.Worktree()
+				h.AssertNil(t, err)
+				_, err = w.Commit("second", &git.CommitOptions{
+					Author: &object.Signature{
+						Name:  "John Doe",
+						Email: "john@doe.org",
+						When:  time.Now(),
+					},
+				})
+				h.AssertNil(t, err)
+
+				h.AssertNil(t, registryCache.Refresh())
+				h.AssertGitHeadEq(t, registryFixture, registryCache.Root)
+			})
 		})
 
-		when("Root is an empty string", func() {
+		when("refreshed by several pack invocations at once", func() {
+			it("creates a single valid cache", func() {
+				var (
+					wg   sync.WaitGroup
+					errs = make(chan error, 4)
+				)
+				for i := 0; i < cap(errs); i++ {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						cache, err := NewRegistryCache(logging.NewSimpleLogger(io.Discard), tmpDir, registryFixture)
+						if err == nil {
+							err = cache.Refresh()
+						}
+						errs <- err
+					}()
+				}
+				wg.Wait()
+				close(errs)
+
+				for err := range errs {
+					h.AssertNil(t, err)
+				}
+				h.AssertGitHeadEq(t, registryFixture, registryCache.Root)
+				h.AssertNil(t, registryCache.validateCache())
+			})
+		})
+
+		when("the cache was refreshed within the refresh interval", func() {
+			var (
+				head string
+
+				headOf = func(path string) string {
+					r, err := git.PlainOpen(path)
+					h.AssertNil(t, err)
+					ref, err := r.Head()
+					h.AssertNil(t, err)
+					return ref.Hash().String()
+				}
+			)
+
+			it.Before(func() {
+				registryCache.RefreshInterval = time.Hour
+				h.AssertNil(t, registryCache.Refresh())
+				head = headOf(registryCache.Root)
+
+				r, err := git.PlainOpen(registryFixture)
+				h.AssertNil(t, err)
+				w, err := r.Worktree()
+				h.AssertNil(t, err)
+				_, err = w.Commit("second", &git.CommitOptions{
+					Author: &object.Signature{
+						Name:  "John Doe",
+						Email: "john@doe.org",
+						When:  time.Now(),
+					},
+				})
+				h.AssertNil(t, err)
+			})
+
+			it("doesn't pull the registry", func() {
+				h.AssertNil(t, registryCache.Refresh())
+				h.AssertEq(t, headOf(registryCache.Root), head)
+			})
+
+			it("pulls the registry when forced", func() {
+				registryCache.ForceRefresh = true
+
+				h.AssertNil(t, registryCache.Refresh())
+				h.AssertGitHeadEq(t, registryFixture, registryCache.Root)
+			})
+
+			it("pulls the registry once the interval passed", func() {
+				refreshedAt := time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+				h.AssertNil(t, os.WriteFile(filepath.Join(registryCache.Root, ".git", "pack-refreshed-at"), []byte(refreshedAt), 0600))
+
+				h.AssertNil(t, registryCache.Refresh())
+				h.AssertGitHeadEq(t, registryFixture, registryCache.Root)
+			})
+		})
+
+		when("Root can't be created", func() {
 			it("fails to refresh", func() {
-				registryCache.Root = ""
+				h.SkipIf(t, runtime.GOOS == "windows", "creating symlinks requires privileges on windows")
+
+				registryCache.Root = danglingRoot()
 				err = registryCache.Refresh()
 				h.AssertError(t, err, "initializing")
 			})
+		})
+	})
+
+	when("the registry has mirrors", func() {
+		const unreachable = "http://127.0.0.1:1/registry-index"
+
+		var registryCache GitCache
 
-			it.After(func() {
-				h.AssertNil(t, os.RemoveAll(registryCache.RegistryDir))
+		it.Before(func() {
+			registryCache, err = NewRegistryCache(logger, tmpDir, unreachable, WithMirrors(registryFixture), WithShallowClone())
+			h.AssertNil(t, err)
+		})
+
+		it("clones the first mirror that can be reached", func() {
+			h.AssertNil(t, registryCache.Refresh())
+
+			h.AssertGitHeadEq(t, registryFixture, registryCache.Root)
+			h.AssertEq(t, registryCache.ActiveURL(), registryFixture)
+			h.AssertContains(t, outBuf.String(), "Unable to reach '"+unreachable+"', falling back to mirror '"+registryFixture+"'")
+
+			repository, err := git.PlainOpen(registryCache.Root)
+			h.AssertNil(t, err)
+			origin, err := repository.Remote("origin")
+			h.AssertNil(t, err)
+			h.AssertEq(t, origin.Config().URLs, []string{unreachable})
+		})
+
+		it("pulls from the first mirror that can be reached", func() {
+			h.AssertNil(t, registryCache.Refresh())
+
+			r, err := git.PlainOpen(registryFixture)
+			h.AssertNil(t, err)
+			w, err := r.Worktree()
+			h.AssertNil(t, err)
+			_, err = w.Commit("second", &git.CommitOptions{
+				Author: &object.Signature{
+					Name:  "John Doe",
+					Email: "john@doe.org",
+					When:  time.Now(),
+				},
 			})
+			h.AssertNil(t, err)
+
+			h.AssertNil(t, registryCache.Refresh())
+			h.AssertGitHeadEq(t, registryFixture, registryCache.Root)
+			h.AssertEq(t, registryCache.ActiveURL(), registryFixture)
+		})
+
+		it("doesn't fall back when the registry refuses the cache", func() {
+			registryCache, err = NewRegistryCache(logger, tmpDir, filepath.Join(tmpDir, "missing"), WithMirrors(registryFixture))
+			h.AssertNil(t, err)
+
+			h.AssertNotNil(t, registryCache.Refresh())
+			h.AssertNotContains(t, outBuf.String(), "falling back to mirror")
+		})
+
+		it("is the registry when no mirror was used", func() {
+			registryCache, err = NewRegistryCache(logger, tmpDir, registryFixture, WithMirrors(unreachable))
+			h.AssertNil(t, err)
+
+			h.AssertNil(t, registryCache.Refresh())
+			h.AssertEq(t, registryCache.ActiveURL(), registryFixture)
 		})
 	})
 
 	when("#Initialize", func() {
 		var (
-			registryCache Cache
+			registryCache GitCache
 		)
 
 		it.Before(func() {
@@ -211,27 +683,65 @@ func testRegistryCache(t *testing.T, when spec.G, it spec.S) {
 			h.AssertNil(t, err)
 		})
 
-		when("root is empty string", func() {
+		it("keeps an existing cache of the registry", func() {
+			h.AssertNil(t, registryCache.Initialize())
+			marker := filepath.Join(registryCache.Root, "marker")
+			h.AssertNil(t, os.WriteFile(marker, []byte("kept"), 0600))
+
+			h.AssertNil(t, registryCache.Initialize())
+			h.AssertPathExists(t, marker)
+		})
+
+		when("cloning shallowly", func() {
 			it.Before(func() {
-				registryCache.Root = ""
+				registryCache, err = NewRegistryCache(logger, tmpDir, registryFixture, WithShallowClone())
+				h.AssertNil(t, err)
+			})
+
+			it("clones only the latest commit", func() {
+				h.AssertNil(t, registryCache.Initialize())
+
+				h.AssertGitHeadEq(t, registryFixture, registryCache.Root)
+				h.AssertPathExists(t, filepath.Join(registryCache.Root, ".git", "shallow"))
+			})
+
+			it("clones the entire history when the shallow cache is invalid", func() {
+				h.AssertNil(t, registryCache.Initialize())
+				// a shallow clone missing the commit it checked out
+				h.AssertNil(t, os.WriteFile(filepath.Join(registryCache.Root, ".git", "shallow"), []byte("0123456789012345678901234567890123456789\n"), 0600))
+				h.AssertNil(t, os.RemoveAll(filepath.Join(registryCache.Root, ".git", "objects")))
+				h.AssertNil(t, os.MkdirAll(filepath.Join(registryCache.Root, ".git", "objects"), 0755))
+
+				h.AssertNil(t, registryCache.Initialize())
+
+				h.AssertGitHeadEq(t, registryFixture, registryCache.Root)
+				h.AssertPathDoesNotExists(t, filepath.Join(registryCache.Root, ".git", "shallow"))
+			})
+		})
+
+		when("root can't be created", func() {
+			it.Before(func() {
+				h.SkipIf(t, runtime.GOOS == "windows", "creating symlinks requires privileges on windows")
+
+				registryCache.Root = danglingRoot()
 			})
 
 			it("fails to create registry cache", func() {
 				err = registryCache.Initialize()
 				h.AssertError(t, err, "creating registry cache")
 			})
+		})
 
-			it.After(func() {
-				h.AssertNil(t, os.RemoveAll(registryCache.RegistryDir))
+		when("url is empty string", func() {
+			it.Before(func() {
+				registryCache.Root = filepath.Join(tmpDir, "cache")
 			})
 
-			when("url is empty string", func() {
-				it("fails to clone cache", func() {
-					registryCache.url = &url.URL{}
+			it("fails to clone cache", func() {
+				registryCache.url = &url.URL{}
 
-					err = registryCache.Initialize()
-					h.AssertError(t, err, "cloning remote registry")
-				})
+				err = registryCache.Initialize()
+				h.AssertError(t, err, "cloning remote registry")
 			})
 		})
 	})
@@ -246,7 +756,7 @@ func testRegistryCache(t *testing.T, when spec.G, it spec.S) {
 		}
 
 		var (
-			registryCache Cache
+			registryCache GitCache
 			msg           = "test commit message"
 			username      = "supra08"
 		)
@@ -0,0 +1,74 @@
+package registry
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// SearchBuildpacks walks the index in the cache for the buildpacks whose id, i.e. namespace/name, contains term, and
+// returns them at their highest version that wasn't yanked, sorted by id. Pre-releases are only returned for buildpacks
+// that have no other versions, and buildpacks whose versions were all yanked aren't returned. The cache isn't refreshed.
+func SearchBuildpacks(cache Cache, term string) ([]Buildpack, error) {
+	ids, err := cache.IDs()
+	if err != nil {
+		return nil, err
+	}
+
+	term = strings.ToLower(term)
+	var buildpacks []Buildpack
+	for _, id := range ids {
+		if !strings.Contains(id, term) {
+			continue
+		}
+
+		entry, err := cacheEntry(cache, id)
+		if err != nil {
+			return nil, err
+		}
+
+		highest, ok := highestVersion(entry, false, false)
+		if !ok {
+			highest, ok = highestVersion(entry, false, true)
+		}
+		if ok {
+			buildpacks = append(buildpacks, highest)
+		}
+	}
+	return buildpacks, nil
+}
+
+// cacheEntry reads the entry of the buildpack id from the index of the cache
+func cacheEntry(cache Cache, id string) (Entry, error) {
+	ns, name, err := ParseNamespaceName(id)
+	if err != nil {
+		return Entry{}, err
+	}
+	if memoryCache, ok := cache.(*MemoryCache); ok {
+		return memoryCache.entries[id], nil
+	}
+	return readEntry(cache.Dir(), ns, name)
+}
+
+// highestVersion is the buildpack of the entry at its highest version, skipping yanked versions unless allowYanked and
+// pre-releases unless includePrereleases
+func highestVersion(entry Entry, allowYanked, includePrereleases bool) (Buildpack, bool) {
+	var (
+		highest Buildpack
+		found   bool
+	)
+	for _, bp := range entry.Buildpacks {
+		if bp.Yanked && !allowYanked {
+			continue
+		}
+		if !includePrereleases && semver.Prerelease(fmt.Sprintf("v%s", bp.Version)) != "" {
+			continue
+		}
+		if !found || semver.Compare(fmt.Sprintf("v%s", bp.Version), fmt.Sprintf("v%s", highest.Version)) > 0 {
+			highest = bp
+			found = true
+		}
+	}
+	return highest, found
+}
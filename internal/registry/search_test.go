@@ -0,0 +1,91 @@
+package registry
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestSearchBuildpacks(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "SearchBuildpacks", testSearchBuildpacks, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testSearchBuildpacks(t *testing.T, when spec.G, it spec.S) {
+	var (
+		tmpDir        string
+		outBuf        bytes.Buffer
+		registryCache GitCache
+	)
+
+	it.Before(func() {
+		tmpDir = t.TempDir()
+		registryFixture := h.CreateRegistryFixture(t, tmpDir, filepath.Join("..", "..", "testdata", "registry"))
+
+		var err error
+		registryCache, err = NewRegistryCache(logging.NewLogWithWriters(&outBuf, &outBuf), tmpDir, registryFixture)
+		h.AssertNil(t, err)
+		h.AssertNil(t, registryCache.Refresh())
+	})
+
+	when("#SearchBuildpacks", func() {
+		it("returns the buildpacks whose id contains the term at their highest version", func() {
+			buildpacks, err := SearchBuildpacks(&registryCache, "FO")
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(buildpacks), 1)
+			h.AssertEq(t, buildpacks[0].Name, "foo")
+			h.AssertEq(t, buildpacks[0].Version, "1.2.0")
+		})
+
+		it("returns all buildpacks sorted by id for an empty term", func() {
+			buildpacks, err := SearchBuildpacks(&registryCache, "")
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(buildpacks), 2)
+			h.AssertEq(t, buildpacks[0].Name, "foo")
+			h.AssertEq(t, buildpacks[1].Name, "java")
+		})
+
+		it("returns nothing when no id matches", func() {
+			buildpacks, err := SearchBuildpacks(&registryCache, "ruby")
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(buildpacks), 0)
+		})
+
+		it("skips yanked versions and buildpacks whose versions were all yanked", func() {
+			index, err := IndexPath(registryCache.Root, "example", "java")
+			h.AssertNil(t, err)
+			h.AssertNil(t, os.WriteFile(index, []byte(`{"ns":"example","name":"java","version":"1.0.0","yanked":true,"addr":"example.com/some/package@sha256:8c27fe111c11b722081701dfed3bd55e039b9ce92865473cf4cdfa918071c566"}`+"\n"), 0600))
+
+			buildpacks, err := SearchBuildpacks(&registryCache, "example")
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(buildpacks), 1)
+			h.AssertEq(t, buildpacks[0].Name, "foo")
+		})
+
+		it("returns pre-releases only for buildpacks without other versions", func() {
+			index, err := IndexPath(registryCache.Root, "example", "java")
+			h.AssertNil(t, err)
+			h.AssertNil(t, os.WriteFile(index, []byte(`{"ns":"example","name":"java","version":"2.0.0-rc.1","yanked":false,"addr":"example.com/some/package@sha256:8c27fe111c11b722081701dfed3bd55e039b9ce92865473cf4cdfa918071c566"}`+"\n"), 0600))
+			index, err = IndexPath(registryCache.Root, "example", "foo")
+			h.AssertNil(t, err)
+			contents, err := os.ReadFile(index)
+			h.AssertNil(t, err)
+			h.AssertNil(t, os.WriteFile(index, append(contents, []byte(`{"ns":"example","name":"foo","version":"2.0.0-rc.1","yanked":false,"addr":"example.com/some/package@sha256:8c27fe111c11b722081701dfed3bd55e039b9ce92865473cf4cdfa918071c566"}`+"\n")...), 0600))
+
+			buildpacks, err := SearchBuildpacks(&registryCache, "example")
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(buildpacks), 2)
+			h.AssertEq(t, buildpacks[0].Version, "1.2.0")
+			h.AssertEq(t, buildpacks[1].Version, "2.0.0-rc.1")
+		})
+	})
+}
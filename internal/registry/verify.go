@@ -0,0 +1,152 @@
+package registry
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// IndexProblem is a problem with a file of a registry index, or with one of its lines
+type IndexProblem struct {
+	// Path of the file, relative to the root of the index
+	Path string
+	// Line of the file the problem is on, 0 when the problem is with the file itself
+	Line    int
+	Message string
+}
+
+func (p IndexProblem) String() string {
+	if p.Line == 0 {
+		return fmt.Sprintf("%s: %s", p.Path, p.Message)
+	}
+	return fmt.Sprintf("%s:%d: %s", p.Path, p.Line, p.Message)
+}
+
+// IndexReport is the result of verifying a registry index
+type IndexReport struct {
+	Files    int
+	Entries  int
+	Problems []IndexProblem
+}
+
+// VerifyIndex walks the registry index in root and checks every line of every file: that it's a buildpack in JSON,
+// at a digest reference, with a semver version and in the file of its namespace and name, and that the file is at
+// the path of that namespace and name. All problems are reported rather than only the first.
+func VerifyIndex(root string) (IndexReport, error) {
+	var report IndexReport
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			// e.g. .git and .github
+			if path != root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		// files at the root, e.g. README.md and config.json, aren't buildpacks
+		if filepath.Dir(path) == root {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		report.Files++
+		problems, entries, err := verifyIndexFile(root, path, rel)
+		if err != nil {
+			return err
+		}
+		report.Entries += entries
+		report.Problems = append(report.Problems, problems...)
+		return nil
+	})
+	if err != nil {
+		return IndexReport{}, errors.Wrapf(err, "verifying index %s", style.Symbol(root))
+	}
+	return report, nil
+}
+
+// verifyIndexFile verifies the file at path, rel to the root of the index, returning its problems and how many
+// entries it has
+func verifyIndexFile(root, path, rel string) ([]IndexProblem, int, error) {
+	var problems []IndexProblem
+	problem := func(line int, format string, args ...interface{}) {
+		problems = append(problems, IndexProblem{Path: rel, Line: line, Message: fmt.Sprintf(format, args...)})
+	}
+
+	ns, name, found := strings.Cut(filepath.Base(path), "_")
+	if !found {
+		problem(0, "file name must be <namespace>_<name>")
+		ns, name = "", ""
+	} else if expected, err := IndexPath(root, ns, name); err != nil {
+		problem(0, "invalid file name: %s", err)
+		ns, name = "", ""
+	} else if expected != path {
+		expectedRel, _ := filepath.Rel(root, expected)
+		problem(0, "must be at %s", style.Symbol(filepath.ToSlash(expectedRel)))
+	}
+
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "opening %s", style.Symbol(rel))
+	}
+	defer file.Close()
+
+	var (
+		entries  int
+		versions = map[string]int{}
+		scanner  = bufio.NewScanner(file)
+	)
+	for line := 1; scanner.Scan(); line++ {
+		entries++
+
+		var bp Buildpack
+		if err := json.Unmarshal(scanner.Bytes(), &bp); err != nil {
+			problem(line, "invalid JSON: %s", err)
+			continue
+		}
+
+		if ns != "" && bp.Namespace != ns {
+			problem(line, "namespace %s doesn't match the namespace of the file %s", style.Symbol(bp.Namespace), style.Symbol(ns))
+		}
+		if name != "" && bp.Name != name {
+			problem(line, "name %s doesn't match the name of the file %s", style.Symbol(bp.Name), style.Symbol(name))
+		}
+
+		if !exactVersionRegexp.MatchString(bp.Version) {
+			problem(line, "version %s is not a semver version", style.Symbol(bp.Version))
+		} else if _, err := semver.NewVersion(bp.Version); err != nil {
+			problem(line, "version %s is not a semver version: %s", style.Symbol(bp.Version), err)
+		} else if previous, ok := versions[bp.Version]; ok {
+			problem(line, "version %s is already on line %d", style.Symbol(bp.Version), previous)
+		} else {
+			versions[bp.Version] = line
+		}
+
+		if err := Validate(bp); err != nil {
+			problem(line, "%s", strings.TrimPrefix(err.Error(), "invalid entry: "))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, errors.Wrapf(err, "reading %s", style.Symbol(rel))
+	}
+	if entries == 0 {
+		problem(0, "has no entries")
+	}
+
+	return problems, entries, nil
+}
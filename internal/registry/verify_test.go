@@ -0,0 +1,102 @@
+package registry_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/registry"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestVerifyIndex(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "VerifyIndex", testVerifyIndex, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testVerifyIndex(t *testing.T, when spec.G, it spec.S) {
+	const digest = "sha256:8c27fe111c11b722081701dfed3bd55e039b9ce92865473cf4cdfa918071c566"
+
+	var root string
+
+	writeFile := func(path string, lines ...string) {
+		full := filepath.Join(root, filepath.FromSlash(path))
+		h.AssertNil(t, os.MkdirAll(filepath.Dir(full), 0755))
+		h.AssertNil(t, os.WriteFile(full, []byte(strings.Join(lines, "\n")+"\n"), 0600))
+	}
+
+	messages := func(report registry.IndexReport) []string {
+		var problems []string
+		for _, problem := range report.Problems {
+			problems = append(problems, problem.String())
+		}
+		return problems
+	}
+
+	it.Before(func() {
+		root = t.TempDir()
+	})
+
+	it("reports no problems for a valid index", func() {
+		report, err := registry.VerifyIndex(filepath.Join("..", "..", "testdata", "registry"))
+		h.AssertNil(t, err)
+		h.AssertEq(t, len(report.Problems), 0)
+		h.AssertEq(t, report.Files, 2)
+	})
+
+	it("skips files at the root and hidden directories", func() {
+		writeFile("README.md", "# Registry")
+		writeFile(".github/workflows/verify.yml", "on: push")
+		writeFile("3/fo/example_foo", `{"ns":"example","name":"foo","version":"1.0.0","addr":"example.com/foo@`+digest+`"}`)
+
+		report, err := registry.VerifyIndex(root)
+		h.AssertNil(t, err)
+		h.AssertEq(t, len(report.Problems), 0)
+		h.AssertEq(t, report.Files, 1)
+		h.AssertEq(t, report.Entries, 1)
+	})
+
+	it("reports every problem of every line", func() {
+		writeFile("3/fo/example_foo",
+			`{"ns":"example","name":"foo","version":"1.0.0","addr":"example.com/foo@`+digest+`"}`,
+			`not json`,
+			`{"ns":"other","name":"foo","version":"1.0","addr":"example.com/foo:latest"}`,
+			`{"ns":"example","name":"foo","version":"1.0.0","addr":"example.com/foo@`+digest+`"}`,
+			`{"ns":"example","name":"bar","version":"2.0.0"}`,
+		)
+
+		report, err := registry.VerifyIndex(root)
+		h.AssertNil(t, err)
+		h.AssertEq(t, report.Entries, 5)
+
+		problems := messages(report)
+		h.AssertEq(t, len(problems), 7)
+		h.AssertContains(t, problems[0], "3/fo/example_foo:2: invalid JSON")
+		h.AssertEq(t, problems[1:], []string{
+			"3/fo/example_foo:3: namespace 'other' doesn't match the namespace of the file 'example'",
+			"3/fo/example_foo:3: version '1.0' is not a semver version",
+			"3/fo/example_foo:3: 'example.com/foo:latest' is not a digest reference",
+			"3/fo/example_foo:4: version '1.0.0' is already on line 1",
+			"3/fo/example_foo:5: name 'bar' doesn't match the name of the file 'foo'",
+			"3/fo/example_foo:5: address is a required field",
+		})
+	})
+
+	it("reports files at the wrong path or with an invalid name", func() {
+		writeFile("fo/o/example_foo", `{"ns":"example","name":"foo","version":"1.0.0","addr":"example.com/foo@`+digest+`"}`)
+		writeFile("3/fo/foo", `{"ns":"example","name":"foo","version":"1.0.0","addr":"example.com/foo@`+digest+`"}`)
+
+		report, err := registry.VerifyIndex(root)
+		h.AssertNil(t, err)
+		h.AssertEq(t, messages(report), []string{
+			"3/fo/foo: file name must be <namespace>_<name>",
+			"fo/o/example_foo: must be at '3/fo/example_foo'",
+		})
+	})
+}
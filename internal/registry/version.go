@@ -0,0 +1,55 @@
+package registry
+
+import (
+	"regexp"
+
+	"github.com/Masterminds/semver"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/errcode"
+)
+
+// exactVersionRegexp matches complete versions, which are located as is rather than as ranges
+var exactVersionRegexp = regexp.MustCompile(`^\d+\.\d+\.\d+(?:[-+].*)?$`)
+
+// isVersionRange is true when version is a semver range, e.g. ^1.2, ~2.0, 1.x or >=1.0 <2.0, rather than a version
+func isVersionRange(version string) bool {
+	if exactVersionRegexp.MatchString(version) {
+		return false
+	}
+	_, err := semver.NewConstraint(version)
+	return err == nil
+}
+
+// resolveVersionRange returns the buildpack of the entry at the highest version in versionRange, skipping yanked
+// versions unless allowYanked. Pre-releases are only in ranges that include a pre-release, e.g. ^2.0.0-rc.1.
+func resolveVersionRange(entry Entry, id, versionRange string, allowYanked bool) (Buildpack, error) {
+	constraint, err := semver.NewConstraint(versionRange)
+	if err != nil {
+		return Buildpack{}, errors.Wrapf(err, "parsing version range %s", style.Symbol(versionRange))
+	}
+
+	var (
+		resolved        Buildpack
+		resolvedVersion *semver.Version
+	)
+	for _, bp := range entry.Buildpacks {
+		if bp.Yanked && !allowYanked {
+			continue
+		}
+		version, err := semver.NewVersion(bp.Version)
+		if err != nil {
+			continue
+		}
+		if constraint.Check(version) && (resolvedVersion == nil || version.GreaterThan(resolvedVersion)) {
+			resolved = bp
+			resolvedVersion = version
+		}
+	}
+
+	if resolvedVersion == nil {
+		return Buildpack{}, errcode.Errorf(errcode.RegistryEntryMissing, "no version of buildpack %s matches %s", style.Symbol(id), style.Symbol(versionRange))
+	}
+	return resolved, nil
+}
@@ -0,0 +1,25 @@
+package sandbox
+
+import (
+	"context"
+	"os/exec"
+
+	"github.com/pkg/errors"
+)
+
+// mountScript mounts the dev, proc and sys filesystems of the host in the root filesystem, $0, in the mount namespace of
+// the sandbox and runs the command, "$@", in it
+const mountScript = `set -e
+for dir in dev proc sys; do mount --rbind "/$dir" "$0/$dir"; done
+exec chroot "$0" "$@"`
+
+func command(ctx context.Context, root string, env []string, name string, args ...string) (*exec.Cmd, error) {
+	unshare, err := exec.LookPath("unshare")
+	if err != nil {
+		return nil, errors.Wrap(err, "sandboxes require unshare of util-linux")
+	}
+
+	cmd := exec.CommandContext(ctx, unshare, append([]string{"--user", "--map-root-user", "--mount", "--", "/bin/sh", "-c", mountScript, root, name}, args...)...)
+	cmd.Env = env
+	return cmd, nil
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+
+package sandbox
+
+import (
+	"context"
+	"os/exec"
+	"runtime"
+
+	"github.com/pkg/errors"
+)
+
+func command(ctx context.Context, root string, env []string, name string, args ...string) (*exec.Cmd, error) {
+	return nil, errors.Errorf("sandboxes are only supported on linux, not %s", runtime.GOOS)
+}
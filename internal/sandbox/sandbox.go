@@ -0,0 +1,207 @@
+// Package sandbox runs commands in the root filesystem of an image extracted on the host, isolated in user and mount
+// namespaces rather than in a container, so that images can be built without a container daemon.
+package sandbox
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// mountPoints are the directories of the host mounted in the sandbox
+var mountPoints = []string{"dev", "proc", "sys"}
+
+// Sandbox is the root filesystem of an image extracted in a directory of the host
+type Sandbox struct {
+	Root string
+}
+
+// New extracts the filesystem of img to a new temporary directory
+func New(img v1.Image) (*Sandbox, error) {
+	root, err := os.MkdirTemp("", "pack.sandbox.")
+	if err != nil {
+		return nil, errors.Wrap(err, "creating sandbox directory")
+	}
+
+	sandbox := &Sandbox{Root: root}
+	if err := sandbox.extract(img); err != nil {
+		sandbox.Remove()
+		return nil, err
+	}
+	return sandbox, nil
+}
+
+func (s *Sandbox) extract(img v1.Image) error {
+	rc := mutate.Extract(img)
+	defer rc.Close()
+
+	if err := s.Add(rc); err != nil {
+		return errors.Wrap(err, "extracting image")
+	}
+	return s.prepare()
+}
+
+// Add extracts the tar stream r in the root filesystem of the sandbox. Entries can't be written outside of the root,
+// even through the symlinks of the filesystem, and devices, which need privileges, are skipped.
+func (s *Sandbox) Add(r io.Reader) error {
+	// directories are only given their mode once every entry is written, as it may not let the owner write in them,
+	// and the owner keeps access to them so that the sandbox can be removed
+	dirModes := map[string]os.FileMode{}
+
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		path, err := s.Path(header.Name)
+		if err != nil {
+			return err
+		}
+		if path == s.Root {
+			continue
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0700); err != nil {
+				return err
+			}
+			dirModes[path] = os.FileMode(header.Mode).Perm()
+		case tar.TypeReg:
+			if err := writeFile(path, tr, os.FileMode(header.Mode).Perm()); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err := replace(path, func() error { return os.Symlink(header.Linkname, path) }); err != nil {
+				return err
+			}
+		case tar.TypeLink:
+			target, err := s.Path(header.Linkname)
+			if err != nil {
+				return err
+			}
+			if err := replace(path, func() error { return os.Link(target, path) }); err != nil {
+				return err
+			}
+		}
+	}
+
+	for dir, mode := range dirModes {
+		if err := os.Chmod(dir, mode|0700); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Path is the path on the host of path in the sandbox, resolving its symlinks within the root of the sandbox
+func (s *Sandbox) Path(path string) (string, error) {
+	clean := filepath.Clean("/" + filepath.FromSlash(path))
+	if clean == string(filepath.Separator) {
+		return s.Root, nil
+	}
+
+	dir, err := securejoin.SecureJoin(s.Root, filepath.Dir(clean))
+	if err != nil {
+		return "", errors.Wrapf(err, "resolving %s in sandbox", style.Symbol(path))
+	}
+	// the last element isn't resolved, so that a symlink is replaced rather than followed
+	return filepath.Join(dir, filepath.Base(clean)), nil
+}
+
+// WriteFile writes a file in the sandbox, e.g. the env files of the platform directory of the lifecycle
+func (s *Sandbox) WriteFile(path string, data []byte, perm os.FileMode) error {
+	target, err := s.Path(path)
+	if err != nil {
+		return err
+	}
+	return writeFile(target, bytes.NewReader(data), perm)
+}
+
+// Command is the command running name with args in the sandbox, as the root user of a user namespace mapped to the user
+// running pack, with the dev, proc and sys filesystems of the host
+func (s *Sandbox) Command(ctx context.Context, env []string, name string, args ...string) (*exec.Cmd, error) {
+	return command(ctx, s.Root, env, name, args...)
+}
+
+// Remove removes the root filesystem of the sandbox
+func (s *Sandbox) Remove() error {
+	// the directories of the image may not let their owner write in them
+	filepath.WalkDir(s.Root, func(path string, d os.DirEntry, err error) error {
+		if err == nil && d.IsDir() {
+			os.Chmod(path, 0700)
+		}
+		return nil
+	})
+	return os.RemoveAll(s.Root)
+}
+
+// prepare makes the mount points real directories, so that mounting follows no symlink of the image, and gives the
+// sandbox the DNS configuration of the host
+func (s *Sandbox) prepare() error {
+	for _, dir := range mountPoints {
+		path := filepath.Join(s.Root, dir)
+		if info, err := os.Lstat(path); err == nil && !info.IsDir() {
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+		}
+		if err := os.MkdirAll(path, 0755); err != nil {
+			return err
+		}
+	}
+
+	resolvConf, err := os.ReadFile("/etc/resolv.conf")
+	if err != nil {
+		// e.g. on hosts without DNS configuration, where the image's own is kept
+		return nil
+	}
+	return s.WriteFile("/etc/resolv.conf", resolvConf, 0644)
+}
+
+// writeFile writes r to path, replacing what's at path, e.g. a symlink, rather than writing through it
+func writeFile(path string, r io.Reader, perm os.FileMode) error {
+	return replace(path, func() error {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, perm|0600)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		if _, err := io.Copy(file, r); err != nil {
+			return err
+		}
+		if err := file.Close(); err != nil {
+			return err
+		}
+		return os.Chmod(path, perm)
+	})
+}
+
+// replace removes what's at path, unless it's a directory, and then creates path with create
+func replace(path string, create func() error) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if info, err := os.Lstat(path); err == nil && !info.IsDir() {
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+	return create()
+}
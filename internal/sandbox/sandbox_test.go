@@ -0,0 +1,184 @@
+package sandbox_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/sandbox"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestSandbox(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Sandbox", testSandbox, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+type entry struct {
+	header  tar.Header
+	content string
+}
+
+func tarOf(t *testing.T, entries ...entry) []byte {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range entries {
+		header := e.header
+		header.Size = int64(len(e.content))
+		if header.Mode == 0 {
+			header.Mode = 0644
+		}
+		h.AssertNil(t, tw.WriteHeader(&header))
+		_, err := tw.Write([]byte(e.content))
+		h.AssertNil(t, err)
+	}
+	h.AssertNil(t, tw.Close())
+	return buf.Bytes()
+}
+
+func testSandbox(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject *sandbox.Sandbox
+		outside string
+	)
+
+	it.Before(func() {
+		h.SkipIf(t, runtime.GOOS == "windows", "sandboxes are only supported on linux")
+
+		root, err := os.MkdirTemp("", "sandbox-test")
+		h.AssertNil(t, err)
+		subject = &sandbox.Sandbox{Root: root}
+
+		outside, err = os.MkdirTemp("", "sandbox-test-outside")
+		h.AssertNil(t, err)
+	})
+
+	it.After(func() {
+		h.AssertNil(t, subject.Remove())
+		h.AssertNil(t, os.RemoveAll(outside))
+	})
+
+	when("#New", func() {
+		it("extracts the filesystem of the image, with the whiteouts of its layers", func() {
+			lower := tarOf(t,
+				entry{header: tar.Header{Name: "etc/", Typeflag: tar.TypeDir, Mode: 0755}},
+				entry{header: tar.Header{Name: "etc/kept", Typeflag: tar.TypeReg}, content: "kept"},
+				entry{header: tar.Header{Name: "etc/removed", Typeflag: tar.TypeReg}, content: "removed"},
+				entry{header: tar.Header{Name: "dev", Typeflag: tar.TypeSymlink, Linkname: "/somewhere"}},
+			)
+			upper := tarOf(t,
+				entry{header: tar.Header{Name: "etc/.wh.removed", Typeflag: tar.TypeReg}},
+				entry{header: tar.Header{Name: "etc/added", Typeflag: tar.TypeReg}, content: "added"},
+			)
+			var layers []tarball.Opener
+			for _, layer := range [][]byte{lower, upper} {
+				layer := layer
+				layers = append(layers, func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(layer)), nil })
+			}
+			img := empty.Image
+			for _, opener := range layers {
+				layer, err := tarball.LayerFromOpener(opener)
+				h.AssertNil(t, err)
+				img, err = mutate.AppendLayers(img, layer)
+				h.AssertNil(t, err)
+			}
+
+			extracted, err := sandbox.New(img)
+			h.AssertNil(t, err)
+			defer extracted.Remove()
+
+			h.AssertPathExists(t, filepath.Join(extracted.Root, "etc", "kept"))
+			h.AssertPathExists(t, filepath.Join(extracted.Root, "etc", "added"))
+			h.AssertPathDoesNotExists(t, filepath.Join(extracted.Root, "etc", "removed"))
+
+			// mount points are real directories rather than the symlinks of the image
+			info, err := os.Lstat(filepath.Join(extracted.Root, "dev"))
+			h.AssertNil(t, err)
+			h.AssertTrue(t, info.IsDir())
+		})
+	})
+
+	when("#Add", func() {
+		it("extracts files, directories and links", func() {
+			h.AssertNil(t, subject.Add(bytes.NewReader(tarOf(t,
+				entry{header: tar.Header{Name: "workspace/", Typeflag: tar.TypeDir, Mode: 0555}},
+				entry{header: tar.Header{Name: "workspace/app.sh", Typeflag: tar.TypeReg, Mode: 0755}, content: "echo hi"},
+				entry{header: tar.Header{Name: "workspace/link", Typeflag: tar.TypeSymlink, Linkname: "app.sh"}},
+				entry{header: tar.Header{Name: "workspace/hardlink", Typeflag: tar.TypeLink, Linkname: "workspace/app.sh"}},
+			))))
+
+			contents, err := os.ReadFile(filepath.Join(subject.Root, "workspace", "app.sh"))
+			h.AssertNil(t, err)
+			h.AssertEq(t, string(contents), "echo hi")
+
+			info, err := os.Stat(filepath.Join(subject.Root, "workspace", "app.sh"))
+			h.AssertNil(t, err)
+			h.AssertEq(t, info.Mode().Perm(), os.FileMode(0755))
+
+			link, err := os.Readlink(filepath.Join(subject.Root, "workspace", "link"))
+			h.AssertNil(t, err)
+			h.AssertEq(t, link, "app.sh")
+
+			contents, err = os.ReadFile(filepath.Join(subject.Root, "workspace", "hardlink"))
+			h.AssertNil(t, err)
+			h.AssertEq(t, string(contents), "echo hi")
+
+			// the owner keeps access to the directory
+			info, err = os.Stat(filepath.Join(subject.Root, "workspace"))
+			h.AssertNil(t, err)
+			h.AssertEq(t, info.Mode().Perm(), os.FileMode(0755))
+		})
+
+		it("keeps entries with relative paths within the root", func() {
+			h.AssertNil(t, subject.Add(bytes.NewReader(tarOf(t,
+				entry{header: tar.Header{Name: "../../escaped", Typeflag: tar.TypeReg}, content: "escaped"},
+			))))
+
+			h.AssertPathExists(t, filepath.Join(subject.Root, "escaped"))
+		})
+
+		it("doesn't write through symlinks out of the root", func() {
+			h.AssertNil(t, subject.Add(bytes.NewReader(tarOf(t,
+				entry{header: tar.Header{Name: "etc", Typeflag: tar.TypeSymlink, Linkname: outside}},
+				entry{header: tar.Header{Name: "etc/passwd", Typeflag: tar.TypeReg}, content: "root"},
+				entry{header: tar.Header{Name: "file", Typeflag: tar.TypeSymlink, Linkname: filepath.Join(outside, "file")}},
+				entry{header: tar.Header{Name: "file", Typeflag: tar.TypeReg}, content: "replaced"},
+			))))
+
+			h.AssertPathDoesNotExists(t, filepath.Join(outside, "passwd"))
+			h.AssertPathDoesNotExists(t, filepath.Join(outside, "file"))
+
+			contents, err := os.ReadFile(filepath.Join(subject.Root, "file"))
+			h.AssertNil(t, err)
+			h.AssertEq(t, string(contents), "replaced")
+		})
+	})
+
+	when("#Command", func() {
+		it("runs the command in the namespaces of the sandbox", func() {
+			h.SkipIf(t, runtime.GOOS != "linux", "sandboxes are only supported on linux")
+
+			cmd, err := subject.Command(context.TODO(), []string{"CNB_PLATFORM_API=0.12"}, "/cnb/lifecycle/creator", "-app", "/workspace")
+			if err != nil {
+				t.Skipf("unshare isn't available: %s", err)
+			}
+
+			h.AssertContains(t, cmd.Path, "unshare")
+			h.AssertSliceContains(t, cmd.Args, "--user", "--map-root-user", "--mount", subject.Root, "/cnb/lifecycle/creator", "-app", "/workspace")
+			h.AssertEq(t, cmd.Env, []string{"CNB_PLATFORM_API=0.12"})
+		})
+	})
+}
@@ -0,0 +1,96 @@
+package sbom
+
+import (
+	"sort"
+	"strings"
+)
+
+// Component is a component of a CycloneDX fragment or a package of an SPDX fragment
+type Component struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	PURL    string `json:"purl,omitempty"`
+}
+
+// Components returns the components of fragments, once per purl
+func Components(fragments []Fragment) []Component {
+	var (
+		components []Component
+		seen       = map[string]bool{}
+	)
+	add := func(c component) {
+		if c.purl != "" && seen[c.purl] {
+			return
+		}
+		seen[c.purl] = true
+		components = append(components, Component{Name: c.name, Version: c.version, PURL: c.purl})
+	}
+
+	for _, fragment := range fragments {
+		switch fragment.Format {
+		case FormatCycloneDX:
+			for _, c := range flattenComponents(objects(fragment.Doc["components"])) {
+				add(cycloneDXToComponent(c))
+			}
+		case FormatSPDX:
+			for _, p := range objects(fragment.Doc["packages"]) {
+				add(spdxComponent(p))
+			}
+		}
+	}
+	return components
+}
+
+// ComponentChange is a component added, removed or whose version changed between two SBOMs
+type ComponentChange struct {
+	Name string `json:"name"`
+
+	// Versions of the component, empty when it's missing from an SBOM
+	BaseVersion   string `json:"baseVersion,omitempty"`
+	TargetVersion string `json:"targetVersion,omitempty"`
+}
+
+// DiffComponents returns the changes from the components of base to the ones of target, sorted by name. Components
+// are matched by their purl without its version, or by their name when they have no purl.
+func DiffComponents(base, target []Component) []ComponentChange {
+	baseVersions := versionsByKey(base)
+	targetVersions := versionsByKey(target)
+
+	var changes []ComponentChange
+	for key, baseComponent := range baseVersions {
+		targetComponent, ok := targetVersions[key]
+		switch {
+		case !ok:
+			changes = append(changes, ComponentChange{Name: baseComponent.Name, BaseVersion: baseComponent.Version})
+		case targetComponent.Version != baseComponent.Version:
+			changes = append(changes, ComponentChange{Name: baseComponent.Name, BaseVersion: baseComponent.Version, TargetVersion: targetComponent.Version})
+		}
+	}
+	for key, targetComponent := range targetVersions {
+		if _, ok := baseVersions[key]; !ok {
+			changes = append(changes, ComponentChange{Name: targetComponent.Name, TargetVersion: targetComponent.Version})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Name != changes[j].Name {
+			return changes[i].Name < changes[j].Name
+		}
+		return changes[i].BaseVersion < changes[j].BaseVersion
+	})
+	return changes
+}
+
+func versionsByKey(components []Component) map[string]Component {
+	byKey := map[string]Component{}
+	for _, c := range components {
+		key := "name:" + c.Name
+		if c.PURL != "" {
+			// pkg:type/namespace/name@version?qualifiers#subpath
+			key, _, _ = strings.Cut(c.PURL, "@")
+			key, _, _ = strings.Cut(key, "?")
+		}
+		byKey[key] = c
+	}
+	return byKey
+}
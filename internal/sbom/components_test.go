@@ -0,0 +1,74 @@
+package sbom_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/sbom"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestComponents(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Components", testComponents, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testComponents(t *testing.T, when spec.G, it spec.S) {
+	fragment := func(format sbom.Format, contents string) sbom.Fragment {
+		f := sbom.Fragment{Format: format}
+		h.AssertNil(t, json.Unmarshal([]byte(contents), &f.Doc))
+		return f
+	}
+
+	when("#Components", func() {
+		it("returns the components of CycloneDX and SPDX fragments once per purl", func() {
+			components := sbom.Components([]sbom.Fragment{
+				fragment(sbom.FormatCycloneDX, cycloneDXFragment),
+				fragment(sbom.FormatSPDX, spdxFragment),
+			})
+
+			h.AssertEq(t, components, []sbom.Component{
+				{Name: "github.com/pkg/errors", Version: "v0.9.1", PURL: "pkg:golang/github.com/pkg/errors@v0.9.1"},
+				{Name: "go", Version: "1.22.0"},
+				{Name: "node", Version: "20.11.0", PURL: "pkg:generic/node@20.11.0"},
+			})
+		})
+	})
+
+	when("#DiffComponents", func() {
+		it("returns the added, removed and updated components sorted by name", func() {
+			base := []sbom.Component{
+				{Name: "github.com/pkg/errors", Version: "v0.9.1", PURL: "pkg:golang/github.com/pkg/errors@v0.9.1"},
+				{Name: "go", Version: "1.22.0"},
+				{Name: "node", Version: "20.11.0", PURL: "pkg:generic/node@20.11.0?arch=amd64"},
+			}
+			target := []sbom.Component{
+				{Name: "github.com/pkg/errors", Version: "v0.9.1", PURL: "pkg:golang/github.com/pkg/errors@v0.9.1"},
+				{Name: "go", Version: "1.23.1"},
+				{Name: "node", Version: "22.1.0", PURL: "pkg:generic/node@22.1.0?arch=amd64"},
+				{Name: "yarn", Version: "1.22.22", PURL: "pkg:npm/yarn@1.22.22"},
+			}
+
+			h.AssertEq(t, sbom.DiffComponents(base, target), []sbom.ComponentChange{
+				{Name: "go", BaseVersion: "1.22.0", TargetVersion: "1.23.1"},
+				{Name: "node", BaseVersion: "20.11.0", TargetVersion: "22.1.0"},
+				{Name: "yarn", TargetVersion: "1.22.22"},
+			})
+			h.AssertEq(t, sbom.DiffComponents(target, base), []sbom.ComponentChange{
+				{Name: "go", BaseVersion: "1.23.1", TargetVersion: "1.22.0"},
+				{Name: "node", BaseVersion: "22.1.0", TargetVersion: "20.11.0"},
+				{Name: "yarn", BaseVersion: "1.22.22"},
+			})
+		})
+
+		it("returns no change for the same components", func() {
+			components := []sbom.Component{{Name: "go", Version: "1.22.0"}}
+			h.AssertEq(t, len(sbom.DiffComponents(components, components)), 0)
+		})
+	})
+}
@@ -0,0 +1,428 @@
+// Package sbom merges the SBOM fragments written by buildpacks into a single document.
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// Format is a format of SBOM document
+type Format string
+
+const (
+	FormatCycloneDX Format = "cyclonedx"
+	FormatSPDX      Format = "spdx"
+)
+
+// fragmentFormats are the formats of SBOM fragments, keyed by the name of the files buildpacks write them to
+var fragmentFormats = map[string]Format{
+	"sbom.cdx.json":  FormatCycloneDX,
+	"sbom.spdx.json": FormatSPDX,
+}
+
+// ParseFormat parses the name of a format
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "cyclonedx", "cdx":
+		return FormatCycloneDX, nil
+	case "spdx":
+		return FormatSPDX, nil
+	}
+	return "", errors.Errorf("unknown SBOM format %s, must be one of %s or %s", style.Symbol(name), FormatCycloneDX, FormatSPDX)
+}
+
+// Fragment is an SBOM document written by a buildpack, e.g. for one of its layers
+type Fragment struct {
+	// Path of the fragment, relative to the SBOM directory it was read from
+	Path   string
+	Format Format
+	Doc    map[string]interface{}
+}
+
+// ReadFragments reads the CycloneDX and SPDX JSON fragments in dir, sorted by path. Fragments in other formats, like
+// Syft JSON, are skipped.
+func ReadFragments(dir string) ([]Fragment, error) {
+	var fragments []Fragment
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		format, ok := fragmentFormats[info.Name()]
+		if info.IsDir() || !ok {
+			return nil
+		}
+
+		contents, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		fragment := Fragment{Path: filepath.ToSlash(relPath), Format: format}
+		if err := json.Unmarshal(contents, &fragment.Doc); err != nil {
+			return errors.Wrapf(err, "parsing SBOM %s", style.Symbol(fragment.Path))
+		}
+		fragments = append(fragments, fragment)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(fragments, func(i, j int) bool {
+		return fragments[i].Path < fragments[j].Path
+	})
+	return fragments, nil
+}
+
+// Metadata describes the merged document
+type Metadata struct {
+	// Name of the image the SBOM describes
+	Name string
+
+	// ToolVersion is the version of pack
+	ToolVersion string
+
+	Time time.Time
+}
+
+// Merge merges fragments into a single document of the given format. Fragments of the other format are converted,
+// keeping the name, version, purl and licenses of their components or packages. Components or packages with the same
+// purl are only included once.
+func Merge(fragments []Fragment, format Format, metadata Metadata) ([]byte, error) {
+	var doc map[string]interface{}
+	switch format {
+	case FormatCycloneDX:
+		doc = mergeCycloneDX(fragments, metadata)
+	case FormatSPDX:
+		doc = mergeSPDX(fragments, metadata)
+	default:
+		return nil, errors.Errorf("unknown SBOM format %s", style.Symbol(string(format)))
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// component is the subset of a CycloneDX component or SPDX package kept when converting between formats
+type component struct {
+	name     string
+	version  string
+	purl     string
+	licenses string
+}
+
+func mergeCycloneDX(fragments []Fragment, metadata Metadata) map[string]interface{} {
+	var (
+		components   []interface{}
+		dependencies []interface{}
+		seen         = map[string]bool{}
+		specVersion  = "1.4"
+	)
+	add := func(c map[string]interface{}) {
+		key := componentKey(c)
+		if key != "" && seen[key] {
+			return
+		}
+		seen[key] = true
+		components = append(components, c)
+	}
+
+	for i, fragment := range fragments {
+		switch fragment.Format {
+		case FormatCycloneDX:
+			if version := stringValue(fragment.Doc, "specVersion"); newerSpecVersion(version, specVersion) {
+				specVersion = version
+			}
+			for _, c := range objects(fragment.Doc["components"]) {
+				add(c)
+			}
+			dependencies = append(dependencies, list(fragment.Doc["dependencies"])...)
+		case FormatSPDX:
+			for _, p := range objects(fragment.Doc["packages"]) {
+				c := spdxComponent(p)
+				bomRef := c.purl
+				if bomRef == "" {
+					bomRef = fmt.Sprintf("%d-%s", i, stringValue(p, "SPDXID"))
+				}
+				add(cycloneDXComponent(c, bomRef))
+			}
+		}
+	}
+
+	doc := map[string]interface{}{
+		"bomFormat":    "CycloneDX",
+		"specVersion":  specVersion,
+		"serialNumber": "urn:uuid:" + uuid.NewString(),
+		"version":      1,
+		"metadata": map[string]interface{}{
+			"timestamp": metadata.Time.UTC().Format(time.RFC3339),
+			"tools": []interface{}{
+				map[string]interface{}{"vendor": "Cloud Native Buildpacks", "name": "pack", "version": metadata.ToolVersion},
+			},
+			"component": map[string]interface{}{"type": "container", "name": metadata.Name, "bom-ref": metadata.Name},
+		},
+		"components": nonNil(components),
+	}
+	if len(dependencies) > 0 {
+		doc["dependencies"] = dependencies
+	}
+	return doc
+}
+
+func mergeSPDX(fragments []Fragment, metadata Metadata) map[string]interface{} {
+	const documentID = "SPDXRef-DOCUMENT"
+
+	var (
+		packages      []interface{}
+		files         []interface{}
+		relationships []interface{}
+		idsByPURL     = map[string]string{}
+		relationSeen  = map[string]bool{}
+	)
+	addRelationship := func(from, kind, to string) {
+		key := from + " " + kind + " " + to
+		if relationSeen[key] {
+			return
+		}
+		relationSeen[key] = true
+		relationships = append(relationships, map[string]interface{}{
+			"spdxElementId": from, "relationshipType": kind, "relatedSpdxElement": to,
+		})
+	}
+
+	for i, fragment := range fragments {
+		switch fragment.Format {
+		case FormatSPDX:
+			// element IDs are only unique within a document, so they're prefixed by the index of the fragment
+			ids := map[string]string{documentID: documentID}
+			rename := func(id string) string {
+				if renamed, ok := ids[id]; ok {
+					return renamed
+				}
+				if !strings.HasPrefix(id, "SPDXRef-") {
+					// NONE, NOASSERTION or a reference to another document
+					return id
+				}
+				return fmt.Sprintf("SPDXRef-%d-%s", i, strings.TrimPrefix(id, "SPDXRef-"))
+			}
+
+			for _, p := range objects(fragment.Doc["packages"]) {
+				id := stringValue(p, "SPDXID")
+				purl := spdxComponent(p).purl
+				if existing, ok := idsByPURL[purl]; ok && purl != "" {
+					ids[id] = existing
+					continue
+				}
+				ids[id] = rename(id)
+				if purl != "" {
+					idsByPURL[purl] = ids[id]
+				}
+
+				p["SPDXID"] = ids[id]
+				if hasFiles, ok := p["hasFiles"].([]interface{}); ok {
+					for j, fileID := range hasFiles {
+						hasFiles[j] = rename(fmt.Sprint(fileID))
+					}
+				}
+				packages = append(packages, p)
+			}
+			for _, f := range objects(fragment.Doc["files"]) {
+				f["SPDXID"] = rename(stringValue(f, "SPDXID"))
+				files = append(files, f)
+			}
+			for _, described := range list(fragment.Doc["documentDescribes"]) {
+				addRelationship(documentID, "DESCRIBES", rename(fmt.Sprint(described)))
+			}
+			for _, r := range objects(fragment.Doc["relationships"]) {
+				addRelationship(rename(stringValue(r, "spdxElementId")), stringValue(r, "relationshipType"), rename(stringValue(r, "relatedSpdxElement")))
+			}
+		case FormatCycloneDX:
+			for j, c := range flattenComponents(objects(fragment.Doc["components"])) {
+				converted := cycloneDXToComponent(c)
+				if _, ok := idsByPURL[converted.purl]; ok && converted.purl != "" {
+					continue
+				}
+				id := fmt.Sprintf("SPDXRef-%d-%s", i, spdxIDSuffix(stringValue(c, "bom-ref"), j))
+				if converted.purl != "" {
+					idsByPURL[converted.purl] = id
+				}
+				packages = append(packages, spdxPackage(converted, id))
+				addRelationship(documentID, "DESCRIBES", id)
+			}
+		}
+	}
+
+	doc := map[string]interface{}{
+		"spdxVersion":       "SPDX-2.3",
+		"dataLicense":       "CC0-1.0",
+		"SPDXID":            documentID,
+		"name":              metadata.Name,
+		"documentNamespace": fmt.Sprintf("https://buildpacks.io/spdxdocs/%s-%s", spdxIDSuffix(metadata.Name, 0), uuid.NewString()),
+		"creationInfo": map[string]interface{}{
+			"created":  metadata.Time.UTC().Format(time.RFC3339),
+			"creators": []interface{}{"Tool: pack-" + metadata.ToolVersion},
+		},
+		"packages":      nonNil(packages),
+		"relationships": nonNil(relationships),
+	}
+	if len(files) > 0 {
+		doc["files"] = files
+	}
+	return doc
+}
+
+func cycloneDXToComponent(c map[string]interface{}) component {
+	converted := component{
+		name:    stringValue(c, "name"),
+		version: stringValue(c, "version"),
+		purl:    stringValue(c, "purl"),
+	}
+
+	var licenses []string
+	for _, l := range objects(c["licenses"]) {
+		if expression := stringValue(l, "expression"); expression != "" {
+			licenses = append(licenses, expression)
+		} else if license, ok := l["license"].(map[string]interface{}); ok && stringValue(license, "id") != "" {
+			licenses = append(licenses, stringValue(license, "id"))
+		}
+	}
+	converted.licenses = strings.Join(licenses, " AND ")
+	return converted
+}
+
+func cycloneDXComponent(c component, bomRef string) map[string]interface{} {
+	converted := map[string]interface{}{"type": "library", "name": c.name, "bom-ref": bomRef}
+	if c.version != "" {
+		converted["version"] = c.version
+	}
+	if c.purl != "" {
+		converted["purl"] = c.purl
+	}
+	if c.licenses != "" {
+		converted["licenses"] = []interface{}{map[string]interface{}{"expression": c.licenses}}
+	}
+	return converted
+}
+
+func spdxComponent(p map[string]interface{}) component {
+	converted := component{
+		name:    stringValue(p, "name"),
+		version: stringValue(p, "versionInfo"),
+	}
+	for _, ref := range objects(p["externalRefs"]) {
+		if stringValue(ref, "referenceType") == "purl" {
+			converted.purl = stringValue(ref, "referenceLocator")
+			break
+		}
+	}
+	if license := stringValue(p, "licenseDeclared"); license != "NOASSERTION" && license != "NONE" {
+		converted.licenses = license
+	}
+	return converted
+}
+
+func spdxPackage(c component, id string) map[string]interface{} {
+	license := c.licenses
+	if license == "" {
+		license = "NOASSERTION"
+	}
+	p := map[string]interface{}{
+		"SPDXID":           id,
+		"name":             c.name,
+		"downloadLocation": "NOASSERTION",
+		"filesAnalyzed":    false,
+		"licenseConcluded": "NOASSERTION",
+		"licenseDeclared":  license,
+		"copyrightText":    "NOASSERTION",
+	}
+	if c.version != "" {
+		p["versionInfo"] = c.version
+	}
+	if c.purl != "" {
+		p["externalRefs"] = []interface{}{
+			map[string]interface{}{"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl", "referenceLocator": c.purl},
+		}
+	}
+	return p
+}
+
+func flattenComponents(components []map[string]interface{}) []map[string]interface{} {
+	var flattened []map[string]interface{}
+	for _, c := range components {
+		flattened = append(flattened, c)
+		flattened = append(flattened, flattenComponents(objects(c["components"]))...)
+	}
+	return flattened
+}
+
+// newerSpecVersion reports whether the <major>.<minor> version a is newer than b
+func newerSpecVersion(a, b string) bool {
+	var aMajor, aMinor, bMajor, bMinor int
+	if _, err := fmt.Sscanf(a, "%d.%d", &aMajor, &aMinor); err != nil {
+		return false
+	}
+	if _, err := fmt.Sscanf(b, "%d.%d", &bMajor, &bMinor); err != nil {
+		return true
+	}
+	return aMajor > bMajor || (aMajor == bMajor && aMinor > bMinor)
+}
+
+func componentKey(c map[string]interface{}) string {
+	for _, key := range []string{"purl", "bom-ref"} {
+		if value := stringValue(c, key); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+var invalidSPDXIDChars = regexp.MustCompile(`[^a-zA-Z0-9.-]+`)
+
+// spdxIDSuffix returns a valid suffix of SPDX element IDs derived from ref, or from index if ref is empty
+func spdxIDSuffix(ref string, index int) string {
+	if suffix := strings.Trim(invalidSPDXIDChars.ReplaceAllString(ref, "-"), "-"); suffix != "" {
+		return suffix
+	}
+	return fmt.Sprintf("Package-%d", index)
+}
+
+func objects(value interface{}) []map[string]interface{} {
+	var result []map[string]interface{}
+	for _, item := range list(value) {
+		if object, ok := item.(map[string]interface{}); ok {
+			result = append(result, object)
+		}
+	}
+	return result
+}
+
+func list(value interface{}) []interface{} {
+	values, _ := value.([]interface{})
+	return values
+}
+
+func stringValue(object map[string]interface{}, key string) string {
+	value, _ := object[key].(string)
+	return value
+}
+
+// nonNil returns an empty list rather than nil, so that it's encoded as [] instead of null
+func nonNil(values []interface{}) []interface{} {
+	if values == nil {
+		return []interface{}{}
+	}
+	return values
+}
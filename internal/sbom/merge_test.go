@@ -0,0 +1,169 @@
+package sbom_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/sbom"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestMerge(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Merge", testMerge, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+const cycloneDXFragment = `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.5",
+  "components": [
+    {
+      "type": "library",
+      "bom-ref": "pkg:golang/github.com/pkg/errors@v0.9.1",
+      "name": "github.com/pkg/errors",
+      "version": "v0.9.1",
+      "purl": "pkg:golang/github.com/pkg/errors@v0.9.1",
+      "licenses": [{"license": {"id": "BSD-2-Clause"}}]
+    },
+    {
+      "type": "application",
+      "bom-ref": "go",
+      "name": "go",
+      "version": "1.22.0"
+    }
+  ]
+}`
+
+const spdxFragment = `{
+  "spdxVersion": "SPDX-2.3",
+  "SPDXID": "SPDXRef-DOCUMENT",
+  "documentDescribes": ["SPDXRef-Package-node"],
+  "packages": [
+    {
+      "SPDXID": "SPDXRef-Package-node",
+      "name": "node",
+      "versionInfo": "20.11.0",
+      "licenseDeclared": "MIT",
+      "externalRefs": [{"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl", "referenceLocator": "pkg:generic/node@20.11.0"}]
+    },
+    {
+      "SPDXID": "SPDXRef-Package-errors",
+      "name": "github.com/pkg/errors",
+      "versionInfo": "v0.9.1",
+      "licenseDeclared": "NOASSERTION",
+      "externalRefs": [{"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl", "referenceLocator": "pkg:golang/github.com/pkg/errors@v0.9.1"}]
+    }
+  ],
+  "relationships": [
+    {"spdxElementId": "SPDXRef-Package-node", "relationshipType": "DEPENDS_ON", "relatedSpdxElement": "SPDXRef-Package-errors"},
+    {"spdxElementId": "SPDXRef-Package-node", "relationshipType": "DEPENDS_ON", "relatedSpdxElement": "NONE"}
+  ]
+}`
+
+func testMerge(t *testing.T, when spec.G, it spec.S) {
+	var (
+		fragments []sbom.Fragment
+		metadata  = sbom.Metadata{Name: "some/image", ToolVersion: "1.2.3", Time: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	)
+
+	it.Before(func() {
+		dir, err := os.MkdirTemp("", "sbom-merge")
+		h.AssertNil(t, err)
+		defer os.RemoveAll(dir)
+
+		h.AssertNil(t, os.MkdirAll(filepath.Join(dir, "example_go", "deps"), 0755))
+		h.AssertNil(t, os.MkdirAll(filepath.Join(dir, "example_node"), 0755))
+		h.AssertNil(t, os.WriteFile(filepath.Join(dir, "example_go", "deps", "sbom.cdx.json"), []byte(cycloneDXFragment), 0600))
+		h.AssertNil(t, os.WriteFile(filepath.Join(dir, "example_go", "deps", "sbom.syft.json"), []byte(`{}`), 0600))
+		h.AssertNil(t, os.WriteFile(filepath.Join(dir, "example_node", "sbom.spdx.json"), []byte(spdxFragment), 0600))
+
+		fragments, err = sbom.ReadFragments(dir)
+		h.AssertNil(t, err)
+	})
+
+	decode := func(data []byte) map[string]interface{} {
+		var doc map[string]interface{}
+		h.AssertNil(t, json.Unmarshal(data, &doc))
+		return doc
+	}
+
+	when("#ReadFragments", func() {
+		it("reads CycloneDX and SPDX fragments sorted by path", func() {
+			h.AssertEq(t, len(fragments), 2)
+			h.AssertEq(t, fragments[0].Path, "example_go/deps/sbom.cdx.json")
+			h.AssertEq(t, fragments[0].Format, sbom.FormatCycloneDX)
+			h.AssertEq(t, fragments[1].Path, "example_node/sbom.spdx.json")
+			h.AssertEq(t, fragments[1].Format, sbom.FormatSPDX)
+		})
+	})
+
+	when("#Merge", func() {
+		it("merges into a CycloneDX document", func() {
+			data, err := sbom.Merge(fragments, sbom.FormatCycloneDX, metadata)
+			h.AssertNil(t, err)
+
+			doc := decode(data)
+			h.AssertEq(t, doc["bomFormat"], "CycloneDX")
+			h.AssertEq(t, doc["specVersion"], "1.5")
+			h.AssertEq(t, doc["metadata"].(map[string]interface{})["timestamp"], "2024-01-02T03:04:05Z")
+
+			components := doc["components"].([]interface{})
+			h.AssertEq(t, len(components), 3)
+			node := components[2].(map[string]interface{})
+			h.AssertEq(t, node["name"], "node")
+			h.AssertEq(t, node["version"], "20.11.0")
+			h.AssertEq(t, node["purl"], "pkg:generic/node@20.11.0")
+			h.AssertEq(t, node["licenses"], []interface{}{map[string]interface{}{"expression": "MIT"}})
+		})
+
+		it("merges into an SPDX document", func() {
+			data, err := sbom.Merge(fragments, sbom.FormatSPDX, metadata)
+			h.AssertNil(t, err)
+
+			doc := decode(data)
+			h.AssertEq(t, doc["spdxVersion"], "SPDX-2.3")
+			h.AssertEq(t, doc["name"], "some/image")
+			h.AssertEq(t, doc["creationInfo"].(map[string]interface{})["creators"], []interface{}{"Tool: pack-1.2.3"})
+
+			var ids []string
+			for _, p := range doc["packages"].([]interface{}) {
+				ids = append(ids, p.(map[string]interface{})["SPDXID"].(string))
+			}
+			h.AssertEq(t, ids, []string{
+				"SPDXRef-0-pkg-golang-github.com-pkg-errors-v0.9.1",
+				"SPDXRef-0-go",
+				"SPDXRef-1-Package-node",
+			})
+
+			errorsPackage := doc["packages"].([]interface{})[0].(map[string]interface{})
+			h.AssertEq(t, errorsPackage["licenseDeclared"], "BSD-2-Clause")
+
+			h.AssertEq(t, doc["relationships"], []interface{}{
+				map[string]interface{}{"spdxElementId": "SPDXRef-DOCUMENT", "relationshipType": "DESCRIBES", "relatedSpdxElement": "SPDXRef-0-pkg-golang-github.com-pkg-errors-v0.9.1"},
+				map[string]interface{}{"spdxElementId": "SPDXRef-DOCUMENT", "relationshipType": "DESCRIBES", "relatedSpdxElement": "SPDXRef-0-go"},
+				map[string]interface{}{"spdxElementId": "SPDXRef-DOCUMENT", "relationshipType": "DESCRIBES", "relatedSpdxElement": "SPDXRef-1-Package-node"},
+				map[string]interface{}{"spdxElementId": "SPDXRef-1-Package-node", "relationshipType": "DEPENDS_ON", "relatedSpdxElement": "SPDXRef-0-pkg-golang-github.com-pkg-errors-v0.9.1"},
+				map[string]interface{}{"spdxElementId": "SPDXRef-1-Package-node", "relationshipType": "DEPENDS_ON", "relatedSpdxElement": "NONE"},
+			})
+		})
+	})
+
+	when("#ParseFormat", func() {
+		it("parses format names", func() {
+			format, err := sbom.ParseFormat("CDX")
+			h.AssertNil(t, err)
+			h.AssertEq(t, format, sbom.FormatCycloneDX)
+
+			_, err = sbom.ParseFormat("syft")
+			h.AssertError(t, err, "unknown SBOM format 'syft', must be one of cyclonedx or spdx")
+		})
+	})
+}
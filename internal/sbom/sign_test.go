@@ -0,0 +1,71 @@
+package sbom_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/sbom"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestSign(t *testing.T) {
+	spec.Run(t, "Sign", testSign, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testSign(t *testing.T, when spec.G, it spec.S) {
+	data := []byte(`{"bomFormat": "CycloneDX"}`)
+
+	encodePKCS8 := func(key interface{}) []byte {
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		h.AssertNil(t, err)
+		return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	}
+
+	decodeSignature := func(signature string) []byte {
+		decoded, err := base64.StdEncoding.DecodeString(signature)
+		h.AssertNil(t, err)
+		return decoded
+	}
+
+	when("#Sign", func() {
+		it("signs with an ECDSA key", func() {
+			key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			h.AssertNil(t, err)
+			der, err := x509.MarshalECPrivateKey(key)
+			h.AssertNil(t, err)
+
+			signature, err := sbom.Sign(data, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+			h.AssertNil(t, err)
+
+			digest := sha256.Sum256(data)
+			h.AssertTrue(t, ecdsa.VerifyASN1(&key.PublicKey, digest[:], decodeSignature(signature)))
+		})
+
+		it("signs with an Ed25519 key", func() {
+			public, private, err := ed25519.GenerateKey(rand.Reader)
+			h.AssertNil(t, err)
+
+			signature, err := sbom.Sign(data, encodePKCS8(private))
+			h.AssertNil(t, err)
+			h.AssertTrue(t, ed25519.Verify(public, data, decodeSignature(signature)))
+		})
+
+		it("fails without a PEM encoded private key", func() {
+			_, err := sbom.Sign(data, []byte("not a key"))
+			h.AssertError(t, err, "private key isn't PEM encoded")
+
+			_, err = sbom.Sign(data, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: []byte{}}))
+			h.AssertError(t, err, "unsupported PEM block PUBLIC KEY")
+		})
+	})
+}
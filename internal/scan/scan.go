@@ -0,0 +1,163 @@
+package scan
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+// Severity of a vulnerability, from the least to the most severe
+type Severity int
+
+const (
+	SeverityUnknown Severity = iota
+	SeverityNegligible
+	SeverityLow
+	SeverityMedium
+	SeverityHigh
+	SeverityCritical
+)
+
+var severityNames = []string{"unknown", "negligible", "low", "medium", "high", "critical"}
+
+func (s Severity) String() string {
+	return severityNames[s]
+}
+
+// ParseSeverity parses the name of a severity, ignoring case
+func ParseSeverity(name string) (Severity, error) {
+	for i, severityName := range severityNames {
+		if strings.EqualFold(name, severityName) {
+			return Severity(i), nil
+		}
+	}
+	return SeverityUnknown, errors.Errorf("unknown severity %s, must be one of %s", style.Symbol(name), strings.Join(severityNames, ", "))
+}
+
+// parseReportedSeverity parses a severity reported by a scanner, which may use names pack doesn't know of
+func parseReportedSeverity(name string) Severity {
+	severity, err := ParseSeverity(name)
+	if err != nil {
+		return SeverityUnknown
+	}
+	return severity
+}
+
+type Vulnerability struct {
+	ID       string
+	Package  string
+	Version  string
+	Severity Severity
+}
+
+// Source is the image to scan
+type Source struct {
+	// Image is the name of the image, or the path of its OCI layout when Layout is true
+	Image  string
+	Daemon bool
+	Layout bool
+}
+
+// Scanner runs a vulnerability scanner executable and reads its JSON report
+type Scanner interface {
+	// Args are the arguments of the executable to scan source and print a JSON report to stdout
+	Args(source Source) []string
+	Parse(report []byte) ([]Vulnerability, error)
+}
+
+// Scanners supported by pack, by name
+var Scanners = map[string]Scanner{
+	"grype": grype{},
+	"trivy": trivy{},
+}
+
+// ScannerNames returns the names of the supported scanners, sorted
+func ScannerNames() []string {
+	var names []string
+	for name := range Scanners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type Options struct {
+	// Scanner is the name of a supported scanner
+	Scanner string
+
+	// Command is the scanner executable, defaults to the name of the scanner looked up in PATH
+	Command string
+}
+
+// Run scans source and returns the vulnerabilities found, sorted from the most severe. Output of the scanner other
+// than its report is logged at debug level.
+func Run(ctx context.Context, logger logging.Logger, options Options, source Source) ([]Vulnerability, error) {
+	scanner, ok := Scanners[options.Scanner]
+	if !ok {
+		return nil, errors.Errorf("unknown scanner %s, must be one of %s", style.Symbol(options.Scanner), strings.Join(ScannerNames(), ", "))
+	}
+
+	command := options.Command
+	if command == "" {
+		command = options.Scanner
+	}
+	executable, err := exec.LookPath(command)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding scanner %s", style.Symbol(command))
+	}
+
+	var report bytes.Buffer
+	cmd := exec.CommandContext(ctx, executable, scanner.Args(source)...)
+	cmd.Stdout = &report
+	cmd.Stderr = logging.GetWriterForLevel(logger, logging.DebugLevel)
+	if err := cmd.Run(); err != nil {
+		return nil, errors.Wrapf(err, "running scanner %s", style.Symbol(command))
+	}
+
+	vulnerabilities, err := scanner.Parse(report.Bytes())
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading report of scanner %s", style.Symbol(options.Scanner))
+	}
+	sort.SliceStable(vulnerabilities, func(i, j int) bool {
+		return vulnerabilities[i].Severity > vulnerabilities[j].Severity
+	})
+	return vulnerabilities, nil
+}
+
+// Summary describes the number of vulnerabilities by severity, e.g. "1 critical, 3 low"
+func Summary(vulnerabilities []Vulnerability) string {
+	counts := map[Severity]int{}
+	for _, vulnerability := range vulnerabilities {
+		counts[vulnerability.Severity]++
+	}
+
+	var parts []string
+	for severity := SeverityCritical; severity >= SeverityUnknown; severity-- {
+		if counts[severity] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[severity], severity))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Check returns an error when any of the vulnerabilities is at least as severe as failOn
+func Check(vulnerabilities []Vulnerability, failOn Severity) error {
+	var count int
+	for _, vulnerability := range vulnerabilities {
+		if vulnerability.Severity >= failOn {
+			count++
+		}
+	}
+	if count > 0 {
+		return errors.Errorf("found %d vulnerabilities of severity %s or higher", count, failOn)
+	}
+	return nil
+}
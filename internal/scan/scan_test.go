@@ -0,0 +1,139 @@
+package scan_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/scan"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestScan(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Scan", testScan, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+const trivyReport = `{
+  "Results": [
+    {
+      "Target": "some/image (ubuntu 22.04)",
+      "Vulnerabilities": [
+        {"VulnerabilityID": "CVE-2024-0002", "PkgName": "libc6", "InstalledVersion": "2.35", "Severity": "LOW"},
+        {"VulnerabilityID": "CVE-2024-0001", "PkgName": "openssl", "InstalledVersion": "3.0.2", "Severity": "CRITICAL"}
+      ]
+    },
+    {
+      "Target": "app/go.mod"
+    }
+  ]
+}`
+
+func testScan(t *testing.T, when spec.G, it spec.S) {
+	var (
+		logger  logging.Logger
+		outBuf  bytes.Buffer
+		tmpDir  string
+		command string
+	)
+
+	it.Before(func() {
+		if runtime.GOOS == "windows" {
+			t.Skip("the fake scanner is written for sh")
+		}
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf, logging.WithVerbose())
+
+		var err error
+		tmpDir, err = os.MkdirTemp("", "scan")
+		h.AssertNil(t, err)
+
+		h.AssertNil(t, os.WriteFile(filepath.Join(tmpDir, "report.json"), []byte(trivyReport), 0600))
+		command = filepath.Join(tmpDir, "fake-trivy")
+		h.AssertNil(t, os.WriteFile(command, []byte(`#!/bin/sh
+echo "$@" > "$(dirname "$0")/args.out"
+echo "downloading vulnerability DB" >&2
+cat "$(dirname "$0")/report.json"
+`), 0700))
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.RemoveAll(tmpDir))
+	})
+
+	when("#Run", func() {
+		it("returns the vulnerabilities reported by the scanner, most severe first", func() {
+			vulnerabilities, err := scan.Run(context.Background(), logger, scan.Options{Scanner: "trivy", Command: command}, scan.Source{Image: "some/image"})
+			h.AssertNil(t, err)
+			h.AssertEq(t, vulnerabilities, []scan.Vulnerability{
+				{ID: "CVE-2024-0001", Package: "openssl", Version: "3.0.2", Severity: scan.SeverityCritical},
+				{ID: "CVE-2024-0002", Package: "libc6", Version: "2.35", Severity: scan.SeverityLow},
+			})
+			h.AssertContains(t, outBuf.String(), "downloading vulnerability DB")
+
+			args, err := os.ReadFile(filepath.Join(tmpDir, "args.out"))
+			h.AssertNil(t, err)
+			h.AssertEq(t, string(args), "image --format json --quiet --image-src remote some/image\n")
+		})
+
+		it("passes the layout of the image to the scanner", func() {
+			_, err := scan.Run(context.Background(), logger, scan.Options{Scanner: "trivy", Command: command}, scan.Source{Image: "/layout/image", Layout: true})
+			h.AssertNil(t, err)
+
+			args, err := os.ReadFile(filepath.Join(tmpDir, "args.out"))
+			h.AssertNil(t, err)
+			h.AssertEq(t, string(args), "image --format json --quiet --input /layout/image\n")
+		})
+
+		it("fails when the report can't be read", func() {
+			h.AssertNil(t, os.WriteFile(filepath.Join(tmpDir, "report.json"), []byte("not json"), 0600))
+
+			_, err := scan.Run(context.Background(), logger, scan.Options{Scanner: "trivy", Command: command}, scan.Source{Image: "some/image"})
+			h.AssertError(t, err, "reading report of scanner 'trivy'")
+		})
+
+		it("fails for unknown scanners", func() {
+			_, err := scan.Run(context.Background(), logger, scan.Options{Scanner: "clair"}, scan.Source{Image: "some/image"})
+			h.AssertError(t, err, "unknown scanner 'clair', must be one of grype, trivy")
+		})
+	})
+
+	when("#Summary", func() {
+		it("counts vulnerabilities by severity", func() {
+			h.AssertEq(t, scan.Summary([]scan.Vulnerability{
+				{Severity: scan.SeverityLow},
+				{Severity: scan.SeverityUnknown},
+				{Severity: scan.SeverityCritical},
+				{Severity: scan.SeverityLow},
+			}), "1 critical, 2 low, 1 unknown")
+		})
+	})
+
+	when("#Check", func() {
+		it("fails on vulnerabilities at least as severe as the threshold", func() {
+			vulnerabilities := []scan.Vulnerability{{Severity: scan.SeverityMedium}, {Severity: scan.SeverityHigh}}
+
+			h.AssertNil(t, scan.Check(vulnerabilities, scan.SeverityCritical))
+			h.AssertError(t, scan.Check(vulnerabilities, scan.SeverityMedium), "found 2 vulnerabilities of severity medium or higher")
+		})
+	})
+
+	when("#ParseSeverity", func() {
+		it("ignores case", func() {
+			severity, err := scan.ParseSeverity("High")
+			h.AssertNil(t, err)
+			h.AssertEq(t, severity, scan.SeverityHigh)
+
+			_, err = scan.ParseSeverity("severe")
+			h.AssertError(t, err, "unknown severity 'severe'")
+		})
+	})
+}
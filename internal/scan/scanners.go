@@ -0,0 +1,91 @@
+package scan
+
+import (
+	"encoding/json"
+)
+
+// grype is https://github.com/anchore/grype
+type grype struct{}
+
+func (grype) Args(source Source) []string {
+	scheme := "registry:"
+	switch {
+	case source.Layout:
+		scheme = "oci-dir:"
+	case source.Daemon:
+		scheme = "docker:"
+	}
+	return []string{scheme + source.Image, "--output", "json", "--quiet"}
+}
+
+func (grype) Parse(report []byte) ([]Vulnerability, error) {
+	var doc struct {
+		Matches []struct {
+			Vulnerability struct {
+				ID       string `json:"id"`
+				Severity string `json:"severity"`
+			} `json:"vulnerability"`
+			Artifact struct {
+				Name    string `json:"name"`
+				Version string `json:"version"`
+			} `json:"artifact"`
+		} `json:"matches"`
+	}
+	if err := json.Unmarshal(report, &doc); err != nil {
+		return nil, err
+	}
+
+	var vulnerabilities []Vulnerability
+	for _, match := range doc.Matches {
+		vulnerabilities = append(vulnerabilities, Vulnerability{
+			ID:       match.Vulnerability.ID,
+			Package:  match.Artifact.Name,
+			Version:  match.Artifact.Version,
+			Severity: parseReportedSeverity(match.Vulnerability.Severity),
+		})
+	}
+	return vulnerabilities, nil
+}
+
+// trivy is https://github.com/aquasecurity/trivy
+type trivy struct{}
+
+func (trivy) Args(source Source) []string {
+	args := []string{"image", "--format", "json", "--quiet"}
+	switch {
+	case source.Layout:
+		return append(args, "--input", source.Image)
+	case !source.Daemon:
+		args = append(args, "--image-src", "remote")
+	}
+	return append(args, source.Image)
+}
+
+func (trivy) Parse(report []byte) ([]Vulnerability, error) {
+	var doc struct {
+		Results []struct {
+			Vulnerabilities []struct {
+				VulnerabilityID  string `json:"VulnerabilityID"`
+				PkgName          string `json:"PkgName"`
+				InstalledVersion string `json:"InstalledVersion"`
+				Severity         string `json:"Severity"`
+			} `json:"Vulnerabilities"`
+		} `json:"Results"`
+	}
+	if err := json.Unmarshal(report, &doc); err != nil {
+		return nil, err
+	}
+
+	var vulnerabilities []Vulnerability
+	for _, result := range doc.Results {
+		for _, vulnerability := range result.Vulnerabilities {
+			vulnerabilities = append(vulnerabilities, Vulnerability{
+				ID:       vulnerability.VulnerabilityID,
+				Package:  vulnerability.PkgName,
+				Version:  vulnerability.InstalledVersion,
+				Severity: parseReportedSeverity(vulnerability.Severity),
+			})
+		}
+	}
+	return vulnerabilities, nil
+}
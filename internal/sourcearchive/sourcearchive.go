@@ -0,0 +1,304 @@
+// Package sourcearchive fetches the source of an app from the URL of an archive, such as a source distribution or
+// an artifact published by a release, extracting it to build it as a directory.
+package sourcearchive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+// Formats of the archives, by extension
+const (
+	formatTar   = "tar"
+	formatTarGz = "tar.gz"
+	formatZip   = "zip"
+)
+
+var extensions = map[string]string{
+	".tar.gz": formatTarGz,
+	".tgz":    formatTarGz,
+	".tar":    formatTar,
+	".zip":    formatZip,
+}
+
+// checksumPattern matches a SHA-256 checksum, with or without the sha256: prefix of digests
+var checksumPattern = regexp.MustCompile(`^(sha256:)?([0-9a-fA-F]{64})$`)
+
+// Source is an archive to fetch
+type Source struct {
+	// URL of the archive
+	URL string
+
+	// Checksum is the SHA-256 checksum the archive must have, if set
+	Checksum string
+
+	format string
+}
+
+// Parse parses path as the URL of an archive, an http or https URL ending with the extension of a tar, gzipped tar
+// or zip file, returning false when it isn't one
+func Parse(path string) (Source, bool) {
+	u, err := url.Parse(path)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return Source{}, false
+	}
+
+	for extension, format := range extensions {
+		if strings.HasSuffix(strings.ToLower(u.Path), extension) {
+			return Source{URL: path, format: format}, true
+		}
+	}
+	return Source{}, false
+}
+
+// ValidateChecksum returns an error when checksum isn't a SHA-256 checksum
+func ValidateChecksum(checksum string) error {
+	if !checksumPattern.MatchString(checksum) {
+		return errors.Errorf("invalid checksum %s, must be a SHA-256 checksum, optionally prefixed by 'sha256:'", style.Symbol(checksum))
+	}
+	return nil
+}
+
+// Options of the download of archives
+type Options struct {
+	// Client downloads the archive, defaults to http.DefaultClient
+	Client *http.Client
+}
+
+// Fetch downloads the archive of source and extracts it in dir, verifying its checksum first when set. It returns
+// the directory of the app, which is the only top-level directory of the archive when it has one, as source
+// distributions do, and dir otherwise.
+func Fetch(ctx context.Context, logger logging.Logger, options Options, source Source, dir string) (string, error) {
+	if source.Checksum != "" {
+		if err := ValidateChecksum(source.Checksum); err != nil {
+			return "", err
+		}
+	}
+	client := options.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	logger.Infof("Downloading %s", style.Symbol(source.URL))
+	file, err := os.CreateTemp("", "pack-source-archive-")
+	if err != nil {
+		return "", errors.Wrap(err, "creating file for source archive")
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	checksum, err := download(ctx, client, source.URL, file)
+	if err != nil {
+		return "", errors.Wrapf(err, "downloading %s", style.Symbol(source.URL))
+	}
+	if source.Checksum != "" {
+		expected := strings.ToLower(checksumPattern.FindStringSubmatch(source.Checksum)[2])
+		if checksum != expected {
+			return "", errors.Errorf("checksum of %s is %s, expected %s", style.Symbol(source.URL), style.Symbol("sha256:"+checksum), style.Symbol("sha256:"+expected))
+		}
+	} else {
+		logger.Debugf("Checksum of %s is %s", style.Symbol(source.URL), style.Symbol("sha256:"+checksum))
+	}
+
+	if err := extract(file, source.format, dir); err != nil {
+		return "", errors.Wrapf(err, "extracting %s", style.Symbol(source.URL))
+	}
+	return appDir(dir)
+}
+
+// download writes the contents at uri to file, returning their SHA-256 checksum
+func download(ctx context.Context, client *http.Client, uri string, file *os.File) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", errors.Errorf("unexpected status %s", resp.Status)
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(file, hash), resp.Body); err != nil {
+		return "", err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func extract(file *os.File, format, dir string) error {
+	switch format {
+	case formatZip:
+		return extractZip(file, dir)
+	case formatTarGz:
+		gzr, err := gzip.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer gzr.Close()
+		return extractTar(gzr, dir)
+	case formatTar:
+		return extractTar(file, dir)
+	default:
+		return fmt.Errorf("unknown archive format %s", format)
+	}
+}
+
+func extractTar(r io.Reader, dir string) error {
+	// links are created once all the other entries are written, so that no entry is written through a link
+	var links []*tar.Header
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		target, err := entryPath(dir, header.Name)
+		if err != nil {
+			return err
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeFile(target, os.FileMode(header.Mode).Perm(), tr); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if link := filepath.Join(filepath.Dir(filepath.FromSlash(header.Name)), header.Linkname); filepath.IsAbs(header.Linkname) || !filepath.IsLocal(link) {
+				return errors.Errorf("link %s points outside of the archive", style.Symbol(header.Name))
+			}
+			links = append(links, header)
+		}
+	}
+
+	for _, header := range links {
+		// the path of the link is checked again, as it may be in one of the links created before it
+		target, err := entryPath(dir, header.Name)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		if err := os.Symlink(header.Linkname, target); err != nil {
+			return errors.Wrapf(err, "creating link %s", style.Symbol(header.Name))
+		}
+	}
+	return nil
+}
+
+func extractZip(file *os.File, dir string) error {
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	zr, err := zip.NewReader(file, info.Size())
+	if err != nil {
+		return err
+	}
+
+	for _, f := range zr.File {
+		target, err := entryPath(dir, f.Name)
+		if err != nil {
+			return err
+		}
+		if f.FileInfo().IsDir() {
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		err = writeFile(target, f.Mode().Perm(), rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// entryPath is the path of the entry name of an archive in dir, which must not be outside of dir. As the lexical
+// check doesn't follow links, none of the existing components of the path may be a link either.
+func entryPath(dir, name string) (string, error) {
+	name = filepath.FromSlash(strings.TrimPrefix(name, "./"))
+	if name != "" && !filepath.IsLocal(name) {
+		return "", errors.Errorf("entry %s is outside of the archive", style.Symbol(name))
+	}
+
+	path := dir
+	for _, part := range strings.Split(name, string(filepath.Separator)) {
+		path = filepath.Join(path, part)
+		info, err := os.Lstat(path)
+		if os.IsNotExist(err) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return "", errors.Errorf("entry %s is in a link of the archive", style.Symbol(name))
+		}
+	}
+	return filepath.Join(dir, name), nil
+}
+
+func writeFile(path string, mode os.FileMode, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode|0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// appDir is the only top-level directory of dir, when it has no other entry, and dir otherwise
+func appDir(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		return filepath.Join(dir, entries[0].Name()), nil
+	}
+	return dir, nil
+}
@@ -0,0 +1,246 @@
+package sourcearchive_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/sourcearchive"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestSourceArchive(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "SourceArchive", testSourceArchive, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testSourceArchive(t *testing.T, when spec.G, it spec.S) {
+	when("#Parse", func() {
+		for _, path := range []string{
+			"https://example.com/source.tar.gz",
+			"https://example.com/source.tgz",
+			"http://example.com/source.tar",
+			"https://example.com/download/app-1.0.0.ZIP",
+			"https://example.com/source.tar.gz?token=abc",
+		} {
+			path := path
+			it("parses "+path, func() {
+				source, ok := sourcearchive.Parse(path)
+				h.AssertTrue(t, ok)
+				h.AssertEq(t, source.URL, path)
+			})
+		}
+
+		for _, path := range []string{"app.tar.gz", "/workspace/app.zip", "https://github.com/org/app.git", "file:///source.tar.gz", "https://example.com/source"} {
+			path := path
+			it("doesn't parse "+path, func() {
+				_, ok := sourcearchive.Parse(path)
+				h.AssertFalse(t, ok)
+			})
+		}
+	})
+
+	when("#ValidateChecksum", func() {
+		it("accepts SHA-256 checksums with or without prefix", func() {
+			checksum := hex.EncodeToString(make([]byte, 32))
+			h.AssertNil(t, sourcearchive.ValidateChecksum(checksum))
+			h.AssertNil(t, sourcearchive.ValidateChecksum("sha256:"+checksum))
+		})
+
+		it("errors for other checksums", func() {
+			h.AssertError(t, sourcearchive.ValidateChecksum("md5:abc"), "invalid checksum 'md5:abc'")
+		})
+	})
+
+	when("#Fetch", func() {
+		var (
+			logger   logging.Logger
+			outBuf   bytes.Buffer
+			server   *httptest.Server
+			archives map[string][]byte
+			dir      string
+		)
+
+		it.Before(func() {
+			logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+			archives = map[string][]byte{
+				"/app.tar.gz": tarGz(t, map[string]string{"app-1.0.0/app.txt": "tar", "app-1.0.0/src/main.go": "package main"}),
+				"/app.zip":    zipArchive(t, map[string]string{"app.txt": "zip", "project.toml": ""}),
+				"/evil.tar":   tarArchive(t, map[string]string{"../evil.txt": "evil"}),
+			}
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				contents, ok := archives[r.URL.Path]
+				if !ok {
+					http.NotFound(w, r)
+					return
+				}
+				w.Write(contents)
+			}))
+
+			var err error
+			dir, err = os.MkdirTemp("", "sourcearchive")
+			h.AssertNil(t, err)
+		})
+
+		it.After(func() {
+			server.Close()
+			h.AssertNil(t, os.RemoveAll(dir))
+		})
+
+		fetch := func(path, checksum string) (string, error) {
+			source, ok := sourcearchive.Parse(server.URL + path)
+			h.AssertTrue(t, ok)
+			source.Checksum = checksum
+			return sourcearchive.Fetch(context.Background(), logger, sourcearchive.Options{}, source, dir)
+		}
+
+		it("extracts a gzipped tar, building its only top-level directory", func() {
+			appDir, err := fetch("/app.tar.gz", "")
+			h.AssertNil(t, err)
+			h.AssertEq(t, appDir, filepath.Join(dir, "app-1.0.0"))
+			h.AssertPathExists(t, filepath.Join(appDir, "src", "main.go"))
+			h.AssertContains(t, outBuf.String(), "Downloading '"+server.URL+"/app.tar.gz'")
+		})
+
+		it("extracts a zip", func() {
+			appDir, err := fetch("/app.zip", "")
+			h.AssertNil(t, err)
+			h.AssertEq(t, appDir, dir)
+			h.AssertPathExists(t, filepath.Join(appDir, "project.toml"))
+		})
+
+		it("verifies the checksum", func() {
+			sum := sha256.Sum256(archives["/app.zip"])
+			_, err := fetch("/app.zip", "sha256:"+hex.EncodeToString(sum[:]))
+			h.AssertNil(t, err)
+		})
+
+		it("errors for another checksum", func() {
+			checksum := hex.EncodeToString(make([]byte, 32))
+			_, err := fetch("/app.zip", checksum)
+			h.AssertError(t, err, "expected 'sha256:"+checksum+"'")
+			h.AssertPathDoesNotExists(t, filepath.Join(dir, "app.txt"))
+		})
+
+		it("errors for entries outside of the archive", func() {
+			_, err := fetch("/evil.tar", "")
+			h.AssertError(t, err, "entry '../evil.txt' is outside of the archive")
+		})
+
+		when("the archive has links", func() {
+			link := func(name, target string) tarEntry {
+				return tarEntry{header: tar.Header{Name: name, Linkname: target, Typeflag: tar.TypeSymlink}}
+			}
+			file := func(name, contents string) tarEntry {
+				return tarEntry{header: tar.Header{Name: name, Mode: 0644, Size: int64(len(contents)), Typeflag: tar.TypeReg}, contents: contents}
+			}
+
+			it("creates the links inside the archive", func() {
+				archives["/links.tar"] = tarEntries(t, link("app/link.txt", "app.txt"), file("app/app.txt", "app"))
+
+				appDir, err := fetch("/links.tar", "")
+				h.AssertNil(t, err)
+				contents, err := os.ReadFile(filepath.Join(appDir, "link.txt"))
+				h.AssertNil(t, err)
+				h.AssertEq(t, string(contents), "app")
+			})
+
+			it("errors for links in a link", func() {
+				archives["/chain.tar"] = tarEntries(t, link("a/b/l", ".."), link("a/b/l/m", "../.."))
+
+				_, err := fetch("/chain.tar", "")
+				h.AssertError(t, err, "entry 'a/b/l/m' is in a link of the archive")
+			})
+
+			it("doesn't write files through a chain of links out of the archive", func() {
+				evil := filepath.Base(dir) + "-evil"
+				archives["/chain.tar"] = tarEntries(t, link("a/b/l", ".."), link("a/b/l/m", "../.."), file("a/b/l/m/"+evil, "evil"))
+
+				_, err := fetch("/chain.tar", "")
+				h.AssertError(t, err, "creating link 'a/b/l'")
+				h.AssertPathDoesNotExists(t, filepath.Join(filepath.Dir(dir), evil))
+			})
+		})
+
+		it("errors when the download fails", func() {
+			_, err := fetch("/missing.tgz", "")
+			h.AssertError(t, err, "unexpected status 404 Not Found")
+		})
+	})
+}
+
+func tarArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	writeTar(t, &buf, files)
+	return buf.Bytes()
+}
+
+func tarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	writeTar(t, gzw, files)
+	h.AssertNil(t, gzw.Close())
+	return buf.Bytes()
+}
+
+func writeTar(t *testing.T, w io.Writer, files map[string]string) {
+	t.Helper()
+	tw := tar.NewWriter(w)
+	for name, contents := range files {
+		h.AssertNil(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(contents)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(contents))
+		h.AssertNil(t, err)
+	}
+	h.AssertNil(t, tw.Close())
+}
+
+type tarEntry struct {
+	header   tar.Header
+	contents string
+}
+
+// tarEntries is a tar of entries, in their order
+func tarEntries(t *testing.T, entries ...tarEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, entry := range entries {
+		h.AssertNil(t, tw.WriteHeader(&entry.header))
+		_, err := tw.Write([]byte(entry.contents))
+		h.AssertNil(t, err)
+	}
+	h.AssertNil(t, tw.Close())
+	return buf.Bytes()
+}
+
+func zipArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, contents := range files {
+		w, err := zw.Create(name)
+		h.AssertNil(t, err)
+		_, err = w.Write([]byte(contents))
+		h.AssertNil(t, err)
+	}
+	h.AssertNil(t, zw.Close())
+	return buf.Bytes()
+}
@@ -0,0 +1,106 @@
+// Package sshagent forwards the SSH agent of the user to the containers of a build, so that buildpacks fetch private
+// dependencies, such as git repositories, with the keys of the user without the keys being in the build.
+package sshagent
+
+import (
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// DockerDesktopSocket is the socket that Docker Desktop forwards the SSH agent of the host to, in its VM, as the
+// sockets of macOS and the named pipes of Windows can't be mounted in containers
+const DockerDesktopSocket = "/run/host-services/ssh-auth.sock"
+
+// Proxy is a socket forwarding its connections to an SSH agent. Unlike the socket of the agent, which only its owner
+// can connect to, any user can connect to it, such as the user of the build containers.
+type Proxy struct {
+	// Socket is the path of the socket of the proxy
+	Socket string
+
+	dir      string
+	listener net.Listener
+	wg       sync.WaitGroup
+}
+
+// NewProxy starts a proxy of the agent listening on agentSocket
+func NewProxy(agentSocket string) (*Proxy, error) {
+	if _, err := os.Stat(agentSocket); err != nil {
+		return nil, errors.Wrapf(err, "finding SSH agent socket %s", style.Symbol(agentSocket))
+	}
+
+	dir, err := os.MkdirTemp("", "pack-ssh-agent-")
+	if err != nil {
+		return nil, errors.Wrap(err, "creating directory for SSH agent socket")
+	}
+	// MkdirTemp creates the directory only accessible to its owner, like the socket of the agent
+	if err := os.Chmod(dir, 0755); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	socket := filepath.Join(dir, "agent.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, errors.Wrap(err, "listening on SSH agent socket")
+	}
+	if err := os.Chmod(socket, 0666); err != nil {
+		listener.Close()
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	p := &Proxy{Socket: socket, dir: dir, listener: listener}
+	p.wg.Add(1)
+	go p.serve(agentSocket)
+	return p, nil
+}
+
+func (p *Proxy) serve(agentSocket string) {
+	defer p.wg.Done()
+	for {
+		conn, err := p.listener.Accept()
+		if err != nil {
+			return
+		}
+		go forward(conn, agentSocket)
+	}
+}
+
+// forward copies the messages of conn to a connection to the agent and back, until either one is closed
+func forward(conn net.Conn, agentSocket string) {
+	defer conn.Close()
+	agent, err := net.Dial("unix", agentSocket)
+	if err != nil {
+		return
+	}
+	defer agent.Close()
+
+	done := make(chan struct{}, 2)
+	go func() {
+		io.Copy(agent, conn)
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(conn, agent)
+		done <- struct{}{}
+	}()
+	<-done
+}
+
+// Close stops the proxy and removes its socket
+func (p *Proxy) Close() error {
+	err := p.listener.Close()
+	p.wg.Wait()
+	if rmErr := os.RemoveAll(p.dir); err == nil {
+		err = rmErr
+	}
+	return err
+}
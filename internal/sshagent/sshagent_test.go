@@ -0,0 +1,90 @@
+package sshagent_test
+
+import (
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/sshagent"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestSSHAgent(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "SSHAgent", testSSHAgent, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testSSHAgent(t *testing.T, when spec.G, it spec.S) {
+	when("#NewProxy", func() {
+		var (
+			tmpDir      string
+			agentSocket string
+			agent       net.Listener
+		)
+
+		it.Before(func() {
+			h.SkipIf(t, runtime.GOOS == "windows", "the SSH agent is only forwarded from unix sockets")
+
+			var err error
+			tmpDir, err = os.MkdirTemp("", "sshagent")
+			h.AssertNil(t, err)
+
+			// an agent echoing its requests
+			agentSocket = filepath.Join(tmpDir, "agent.sock")
+			agent, err = net.Listen("unix", agentSocket)
+			h.AssertNil(t, err)
+			go func() {
+				for {
+					conn, err := agent.Accept()
+					if err != nil {
+						return
+					}
+					go func() {
+						defer conn.Close()
+						io.Copy(conn, conn)
+					}()
+				}
+			}()
+		})
+
+		it.After(func() {
+			agent.Close()
+			h.AssertNil(t, os.RemoveAll(tmpDir))
+		})
+
+		it("forwards connections to the agent with a socket anyone can connect to", func() {
+			proxy, err := sshagent.NewProxy(agentSocket)
+			h.AssertNil(t, err)
+
+			info, err := os.Stat(proxy.Socket)
+			h.AssertNil(t, err)
+			h.AssertEq(t, info.Mode().Perm(), os.FileMode(0666))
+
+			conn, err := net.Dial("unix", proxy.Socket)
+			h.AssertNil(t, err)
+			_, err = conn.Write([]byte("request"))
+			h.AssertNil(t, err)
+			response := make([]byte, len("request"))
+			_, err = io.ReadFull(conn, response)
+			h.AssertNil(t, err)
+			h.AssertEq(t, string(response), "request")
+			h.AssertNil(t, conn.Close())
+
+			h.AssertNil(t, proxy.Close())
+			h.AssertPathDoesNotExists(t, proxy.Socket)
+		})
+
+		it("errors when the agent socket doesn't exist", func() {
+			_, err := sshagent.NewProxy(filepath.Join(tmpDir, "missing.sock"))
+			h.AssertError(t, err, "finding SSH agent socket")
+		})
+	})
+}
@@ -43,20 +43,24 @@ var SymbolF = func(format string, a ...interface{}) string {
 	return "'" + fmt.Sprintf(format, a...) + "'"
 }
 
-var Key = color.HiBlueString
+var Key = themes[DefaultTheme].key
 
-var Tip = color.New(color.FgGreen, color.Bold).SprintfFunc()
+var Tip = themes[DefaultTheme].tip
 
-var Warn = color.New(color.FgYellow, color.Bold).SprintfFunc()
+var Warn = themes[DefaultTheme].warn
 
-var Error = color.New(color.FgRed, color.Bold).SprintfFunc()
+var Error = themes[DefaultTheme].error
 
-var Step = func(format string, a ...interface{}) string {
-	return color.CyanString("===> "+format, a...)
-}
+var Step = stepFunc(themes[DefaultTheme].step)
+
+var Prefix = themes[DefaultTheme].prefix
+var Waiting = themes[DefaultTheme].waiting
+var Working = themes[DefaultTheme].working
+var Complete = themes[DefaultTheme].complete
+var ProgressBar = themes[DefaultTheme].progressBar
 
-var Prefix = color.CyanString
-var Waiting = color.HiBlackString
-var Working = color.HiBlueString
-var Complete = color.GreenString
-var ProgressBar = color.HiBlueString
+func stepFunc(step sprintf) sprintf {
+	return func(format string, a ...interface{}) string {
+		return step("===> "+format, a...)
+	}
+}
@@ -0,0 +1,105 @@
+package style
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/heroku/color"
+	"github.com/pkg/errors"
+)
+
+const (
+	DefaultTheme      = "default"
+	HighContrastTheme = "high-contrast"
+	MonochromeTheme   = "monochrome"
+)
+
+type sprintf = func(format string, a ...interface{}) string
+
+// theme defines how each kind of styled output is rendered
+type theme struct {
+	key, tip, warn, error, step, prefix, waiting, working, complete, progressBar sprintf
+}
+
+var themes = map[string]theme{
+	DefaultTheme: {
+		key:         color.HiBlueString,
+		tip:         color.New(color.FgGreen, color.Bold).SprintfFunc(),
+		warn:        color.New(color.FgYellow, color.Bold).SprintfFunc(),
+		error:       color.New(color.FgRed, color.Bold).SprintfFunc(),
+		step:        color.CyanString,
+		prefix:      color.CyanString,
+		waiting:     color.HiBlackString,
+		working:     color.HiBlueString,
+		complete:    color.GreenString,
+		progressBar: color.HiBlueString,
+	},
+	HighContrastTheme: {
+		key:         color.New(color.FgHiCyan, color.Bold).SprintfFunc(),
+		tip:         color.New(color.FgHiGreen, color.Bold).SprintfFunc(),
+		warn:        color.New(color.FgHiYellow, color.Bold).SprintfFunc(),
+		error:       color.New(color.FgHiRed, color.Bold).SprintfFunc(),
+		step:        color.New(color.FgHiWhite, color.Bold).SprintfFunc(),
+		prefix:      color.HiCyanString,
+		waiting:     color.WhiteString,
+		working:     color.HiCyanString,
+		complete:    color.New(color.FgHiGreen, color.Bold).SprintfFunc(),
+		progressBar: color.HiWhiteString,
+	},
+	MonochromeTheme: {
+		key:         color.New(color.Bold).SprintfFunc(),
+		tip:         color.New(color.Bold).SprintfFunc(),
+		warn:        color.New(color.Bold).SprintfFunc(),
+		error:       color.New(color.Bold).SprintfFunc(),
+		step:        color.New(color.Bold).SprintfFunc(),
+		prefix:      fmt.Sprintf,
+		waiting:     fmt.Sprintf,
+		working:     fmt.Sprintf,
+		complete:    fmt.Sprintf,
+		progressBar: fmt.Sprintf,
+	},
+}
+
+// Themes returns the names of the available color themes
+func Themes() []string {
+	var names []string
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetTheme changes the colors used by all styles to those of the named theme.
+// An empty name selects the default theme.
+func SetTheme(name string) error {
+	if name == "" {
+		name = DefaultTheme
+	}
+
+	if err := ValidateTheme(name); err != nil {
+		return err
+	}
+
+	t := themes[name]
+	Key = t.key
+	Tip = t.tip
+	Warn = t.warn
+	Error = t.error
+	Step = stepFunc(t.step)
+	Prefix = t.prefix
+	Waiting = t.waiting
+	Working = t.working
+	Complete = t.complete
+	ProgressBar = t.progressBar
+	return nil
+}
+
+// ValidateTheme returns an error if there is no color theme with the given name
+func ValidateTheme(name string) error {
+	if _, ok := themes[name]; !ok {
+		return errors.Errorf("unknown color theme %s, must be one of %s", Symbol(name), strings.Join(Themes(), ", "))
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package style_test
+
+import (
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/style"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestTheme(t *testing.T) {
+	color.Disable(false)
+	defer color.Disable(true)
+	spec.Run(t, "testTheme", testTheme, spec.Sequential(), spec.Report(report.Terminal{}))
+}
+
+func testTheme(t *testing.T, when spec.G, it spec.S) {
+	it.After(func() {
+		h.AssertNil(t, style.SetTheme(style.DefaultTheme))
+	})
+
+	when("#Themes", func() {
+		it("lists the available themes", func() {
+			h.AssertEq(t, style.Themes(), []string{"default", "high-contrast", "monochrome"})
+		})
+	})
+
+	when("#SetTheme", func() {
+		it("applies the monochrome theme", func() {
+			h.AssertNil(t, style.SetTheme(style.MonochromeTheme))
+			h.AssertEq(t, style.Symbol("Symbol"), "\x1b[1mSymbol\x1b[0m")
+			h.AssertEq(t, style.Step("DETECTING"), "\x1b[1m===> DETECTING\x1b[0m")
+			h.AssertEq(t, style.Complete("done"), "done")
+		})
+
+		it("applies the high-contrast theme", func() {
+			h.AssertNil(t, style.SetTheme(style.HighContrastTheme))
+			h.AssertEq(t, style.Symbol("Symbol"), "\x1b[96;1mSymbol\x1b[0m")
+		})
+
+		it("restores the default theme when no theme is given", func() {
+			h.AssertNil(t, style.SetTheme(style.MonochromeTheme))
+			h.AssertNil(t, style.SetTheme(""))
+			h.AssertEq(t, style.Symbol("Symbol"), "\x1b[94mSymbol\x1b[0m")
+		})
+
+		it("errors for an unknown theme", func() {
+			h.AssertError(t, style.SetTheme("neon"), "unknown color theme")
+		})
+	})
+}
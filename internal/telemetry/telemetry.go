@@ -0,0 +1,120 @@
+// Package telemetry reports anonymous usage data for users who opted in with `pack config telemetry`.
+//
+// Only the command path (e.g. "pack build"), its duration, the category of a failure, the pack version and
+// the OS/architecture are reported. Arguments, flag values, image names and paths are never included.
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"runtime"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// DefaultTimeout bounds how long reporting may delay the exit of a command
+const DefaultTimeout = 2 * time.Second
+
+// Event is the usage data reported for a single command
+type Event struct {
+	Command    string `json:"command"`
+	DurationMs int64  `json:"durationMs"`
+	Success    bool   `json:"success"`
+	ErrorCode  string `json:"errorCode,omitempty"`
+	ErrorName  string `json:"errorName,omitempty"`
+	Version    string `json:"version"`
+	OS         string `json:"os"`
+	Arch       string `json:"arch"`
+}
+
+// NewEvent creates an event for a command that ran for duration. errCode and errName are empty when the command succeeded.
+func NewEvent(command string, duration time.Duration, errCode, errName, version string) Event {
+	return Event{
+		Command:    command,
+		DurationMs: duration.Milliseconds(),
+		Success:    errCode == "",
+		ErrorCode:  errCode,
+		ErrorName:  errName,
+		Version:    version,
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+	}
+}
+
+// Enabled returns whether events should be reported. Setting DO_NOT_TRACK or PACK_NO_TELEMETRY to a
+// non-empty value disables telemetry regardless of the config.
+func Enabled(enabled bool, endpoint string, getenv func(string) string) bool {
+	if getenv("DO_NOT_TRACK") != "" || getenv("PACK_NO_TELEMETRY") != "" {
+		return false
+	}
+	return enabled && endpoint != ""
+}
+
+// ValidateEndpoint returns an error if endpoint is not an http or https URL
+func ValidateEndpoint(endpoint string) error {
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil || (endpointURL.Scheme != "http" && endpointURL.Scheme != "https") || endpointURL.Host == "" {
+		return errors.Errorf("invalid telemetry endpoint %s, must be an http or https URL", style.Symbol(endpoint))
+	}
+	return nil
+}
+
+// Reporter sends events to an endpoint
+type Reporter struct {
+	endpoint string
+	client   *http.Client
+}
+
+// ReporterOption configures a Reporter
+type ReporterOption func(*Reporter)
+
+// WithHTTPClient sets the client used to send events
+func WithHTTPClient(client *http.Client) ReporterOption {
+	return func(r *Reporter) {
+		r.client = client
+	}
+}
+
+// NewReporter creates a Reporter that posts events as JSON to endpoint
+func NewReporter(endpoint string, ops ...ReporterOption) *Reporter {
+	r := &Reporter{
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: DefaultTimeout},
+	}
+
+	for _, op := range ops {
+		op(r)
+	}
+	return r
+}
+
+// Report sends event to the endpoint
+func (r *Reporter) Report(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "encoding telemetry event")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "creating telemetry request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "sending telemetry event")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.Errorf("sending telemetry event: unexpected status %s", resp.Status)
+	}
+	return nil
+}
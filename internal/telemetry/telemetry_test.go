@@ -0,0 +1,105 @@
+package telemetry_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/telemetry"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestTelemetry(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Telemetry", testTelemetry, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testTelemetry(t *testing.T, when spec.G, it spec.S) {
+	when("#NewEvent", func() {
+		it("reports a successful command", func() {
+			event := telemetry.NewEvent("pack build", 1500*time.Millisecond, "", "", "1.2.3")
+
+			h.AssertEq(t, event, telemetry.Event{
+				Command:    "pack build",
+				DurationMs: 1500,
+				Success:    true,
+				Version:    "1.2.3",
+				OS:         runtime.GOOS,
+				Arch:       runtime.GOARCH,
+			})
+		})
+
+		it("reports the category of a failure", func() {
+			event := telemetry.NewEvent("pack build", time.Second, "PACK1001", "builder-not-trusted", "1.2.3")
+
+			h.AssertEq(t, event.Success, false)
+			h.AssertEq(t, event.ErrorCode, "PACK1001")
+			h.AssertEq(t, event.ErrorName, "builder-not-trusted")
+		})
+	})
+
+	when("#Enabled", func() {
+		env := func(vars map[string]string) func(string) string {
+			return func(key string) string { return vars[key] }
+		}
+
+		it("requires opting in and an endpoint", func() {
+			h.AssertTrue(t, telemetry.Enabled(true, "https://example.com", env(nil)))
+			h.AssertFalse(t, telemetry.Enabled(false, "https://example.com", env(nil)))
+			h.AssertFalse(t, telemetry.Enabled(true, "", env(nil)))
+		})
+
+		it("is disabled by DO_NOT_TRACK and PACK_NO_TELEMETRY", func() {
+			h.AssertFalse(t, telemetry.Enabled(true, "https://example.com", env(map[string]string{"DO_NOT_TRACK": "1"})))
+			h.AssertFalse(t, telemetry.Enabled(true, "https://example.com", env(map[string]string{"PACK_NO_TELEMETRY": "true"})))
+		})
+	})
+
+	when("#ValidateEndpoint", func() {
+		it("accepts http and https URLs", func() {
+			h.AssertNil(t, telemetry.ValidateEndpoint("https://telemetry.example.com/v1/events"))
+			h.AssertNil(t, telemetry.ValidateEndpoint("http://localhost:8080"))
+		})
+
+		it("rejects other values", func() {
+			h.AssertError(t, telemetry.ValidateEndpoint("ftp://example.com"), "invalid telemetry endpoint 'ftp://example.com', must be an http or https URL")
+			h.AssertError(t, telemetry.ValidateEndpoint("example.com"), "invalid telemetry endpoint 'example.com'")
+		})
+	})
+
+	when("#Report", func() {
+		it("posts the event as json", func() {
+			var received telemetry.Event
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				h.AssertEq(t, r.Method, http.MethodPost)
+				h.AssertEq(t, r.Header.Get("Content-Type"), "application/json")
+				h.AssertNil(t, json.NewDecoder(r.Body).Decode(&received))
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+
+			event := telemetry.NewEvent("pack build", time.Second, "", "", "1.2.3")
+			h.AssertNil(t, telemetry.NewReporter(server.URL).Report(context.TODO(), event))
+			h.AssertEq(t, received, event)
+		})
+
+		it("errors on an unexpected status", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			}))
+			defer server.Close()
+
+			err := telemetry.NewReporter(server.URL).Report(context.TODO(), telemetry.NewEvent("pack build", time.Second, "", "", "1.2.3"))
+			h.AssertError(t, err, "unexpected status 500 Internal Server Error")
+		})
+	})
+}
@@ -20,6 +20,45 @@ func IsTerminal(w io.Writer) (uintptr, bool) {
 	return InvalidFileDescriptor, false
 }
 
+// IsTerminalInput returns whether a reader, such as stdin, is a terminal
+func IsTerminalInput(r io.Reader) bool {
+	if f, ok := r.(hasDescriptor); ok {
+		return term.IsTerminal(int(f.Fd()))
+	}
+
+	return false
+}
+
 type hasDescriptor interface {
 	Fd() uintptr
 }
+
+// WantColor determines whether colored output should be written to w. In order of precedence:
+//   - the --force-color and --no-color flags,
+//   - the NO_COLOR environment variable, which disables color when set to any non-empty value (see https://no-color.org),
+//   - the CLICOLOR_FORCE environment variable, which forces color when set to any value other than "0",
+//   - whether w is a terminal.
+func WantColor(w io.Writer, noColor, forceColor bool, getenv func(string) string) bool {
+	switch {
+	case forceColor:
+		return true
+	case noColor:
+		return false
+	case getenv("NO_COLOR") != "":
+		return false
+	case getenv("CLICOLOR_FORCE") != "" && getenv("CLICOLOR_FORCE") != "0":
+		return true
+	}
+
+	_, isTerm := IsTerminal(w)
+	return isTerm
+}
+
+// Height returns the number of rows of the terminal with descriptor fd, or 0 when it's unknown
+func Height(fd uintptr) int {
+	_, height, err := term.GetSize(int(fd))
+	if err != nil {
+		return 0
+	}
+	return height
+}
@@ -31,4 +31,32 @@ func testTerm(t *testing.T, when spec.G, it spec.S) {
 			h.AssertEq(t, fd, term.InvalidFileDescriptor)
 		})
 	})
+
+	when("#WantColor", func() {
+		env := func(vars map[string]string) func(string) string {
+			return func(key string) string { return vars[key] }
+		}
+
+		it("is false for a writer that is not a terminal", func() {
+			h.AssertFalse(t, term.WantColor(&bytes.Buffer{}, false, false, env(nil)))
+		})
+
+		it("is true when color is forced", func() {
+			h.AssertTrue(t, term.WantColor(&bytes.Buffer{}, false, true, env(map[string]string{"NO_COLOR": "1"})))
+			h.AssertTrue(t, term.WantColor(&bytes.Buffer{}, true, true, env(nil)))
+		})
+
+		it("is false when color is disabled", func() {
+			h.AssertFalse(t, term.WantColor(&bytes.Buffer{}, true, false, env(map[string]string{"CLICOLOR_FORCE": "1"})))
+		})
+
+		it("honors NO_COLOR over CLICOLOR_FORCE", func() {
+			h.AssertFalse(t, term.WantColor(&bytes.Buffer{}, false, false, env(map[string]string{"NO_COLOR": "1", "CLICOLOR_FORCE": "1"})))
+		})
+
+		it("honors CLICOLOR_FORCE", func() {
+			h.AssertTrue(t, term.WantColor(&bytes.Buffer{}, false, false, env(map[string]string{"CLICOLOR_FORCE": "1"})))
+			h.AssertFalse(t, term.WantColor(&bytes.Buffer{}, false, false, env(map[string]string{"CLICOLOR_FORCE": "0"})))
+		})
+	})
 }
@@ -1,12 +1,18 @@
 package fakes
 
 import (
+	"sync"
+
 	"github.com/rivo/tview"
 )
 
 type App struct {
+	// mu guards the counts updated by the goroutines of a dashboard
+	mu sync.Mutex
+
 	SetRootCallCount int
 	DrawCallCount    int
+	StopCallCount    int
 
 	doneChan chan bool
 }
@@ -29,7 +35,9 @@ func (a *App) Draw() *tview.Application {
 
 func (a *App) QueueUpdateDraw(f func()) *tview.Application {
 	f()
+	a.mu.Lock()
 	a.DrawCallCount++
+	a.mu.Unlock()
 	return nil
 }
 
@@ -38,6 +46,16 @@ func (a *App) Run() error {
 	return nil
 }
 
+func (a *App) Stop() {
+	a.mu.Lock()
+	a.StopCallCount++
+	a.mu.Unlock()
+	select {
+	case a.doneChan <- true:
+	default:
+	}
+}
+
 func (a *App) StopRunning() {
 	a.doneChan <- true
 }
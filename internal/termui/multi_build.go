@@ -0,0 +1,305 @@
+package termui
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+var (
+	phaseHeaderMatcher = regexp.MustCompile(`===> ([A-Z]+)`)
+	phasePrefixMatcher = regexp.MustCompile(`^\[([a-z]+)\] `)
+)
+
+const (
+	statusPending   = "pending"
+	statusSucceeded = "succeeded"
+	statusFailed    = "failed"
+)
+
+type buildRow struct {
+	image    string
+	phase    string
+	started  time.Time
+	finished time.Time
+	lastLine string
+	logs     []string
+	err      error
+}
+
+// MultiBuild is a dashboard for several builds running at once. It shows one row per image with the
+// current lifecycle phase, the elapsed time and the last line logged. Selecting a row with enter shows
+// the complete log of that build and escape returns to the overview. The dashboard closes once all builds
+// are done, or earlier when quit with q or ctrl-c.
+type MultiBuild struct {
+	app   app
+	clock func() time.Time
+
+	mu      sync.Mutex
+	rows    []*buildRow
+	focused int
+	// redraws requests the dashboard to be redrawn while it runs, so that builds never wait for it
+	redraws chan struct{}
+
+	table   *tview.Table
+	logView *tview.TextView
+	pages   *tview.Pages
+}
+
+// NewMultiBuild creates a dashboard for the builds of the given images, shown by app
+func NewMultiBuild(app app, images []string) *MultiBuild {
+	return newMultiBuild(app, time.Now, images)
+}
+
+func newMultiBuild(app app, clock func() time.Time, images []string) *MultiBuild {
+	m := &MultiBuild{
+		app:     app,
+		clock:   clock,
+		focused: -1,
+		table:   tview.NewTable(),
+		logView: tview.NewTextView(),
+		pages:   tview.NewPages(),
+	}
+
+	for _, image := range images {
+		m.rows = append(m.rows, &buildRow{image: image, phase: statusPending})
+	}
+
+	m.table.
+		SetSelectable(true, false).
+		SetFixed(1, 0).
+		SetSelectedFunc(func(row, _ int) {
+			m.focus(row - 1)
+		})
+	m.table.
+		SetBorder(true).
+		SetTitleAlign(tview.AlignLeft).
+		SetTitle("| [::b]builds[::-] | enter: show log | q: quit, cancelling the builds left |").
+		SetBackgroundColor(backgroundColor)
+
+	m.logView.
+		SetDynamicColors(false).
+		SetScrollable(true).
+		SetBorder(true).
+		SetTitleAlign(tview.AlignLeft).
+		SetBackgroundColor(backgroundColor)
+	m.logView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			m.focus(-1)
+			return nil
+		}
+		return event
+	})
+
+	m.pages.
+		AddPage("overview", m.table, true, true).
+		AddPage("log", m.logView, true, false)
+	m.pages.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Rune() == 'q' {
+			m.app.Stop()
+			return nil
+		}
+		return event
+	})
+
+	m.render()
+	return m
+}
+
+// Start marks the build of image as started
+func (m *MultiBuild) Start(image string) {
+	m.update(image, func(row *buildRow) {
+		row.started = m.clock()
+		row.phase = "starting"
+	})
+}
+
+// Finish marks the build of image as finished, failed if err is not nil
+func (m *MultiBuild) Finish(image string, err error) {
+	m.update(image, func(row *buildRow) {
+		row.finished = m.clock()
+		row.err = err
+		if err != nil {
+			row.phase = statusFailed
+			row.lastLine = err.Error()
+			return
+		}
+		row.phase = statusSucceeded
+	})
+}
+
+// Writer returns a writer for the output of the build of image. The current phase is taken from
+// the lifecycle's "===> PHASE" headers and "[phase]" prefixes.
+func (m *MultiBuild) Writer(image string) io.Writer {
+	return &multiBuildWriter{dashboard: m, image: image}
+}
+
+// Logs returns the lines logged by the build of image
+func (m *MultiBuild) Logs(image string) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, row := range m.rows {
+		if row.image == image {
+			return append([]string{}, row.logs...)
+		}
+	}
+	return nil
+}
+
+// Run shows the dashboard in the foreground while builds runs in the background. It returns once the builds
+// are done, or when the dashboard is quit before, in which case the builds are left running.
+func (m *MultiBuild) Run(builds func()) error {
+	redraws := make(chan struct{}, 1)
+	stopped := make(chan struct{})
+	m.mu.Lock()
+	m.redraws = redraws
+	m.mu.Unlock()
+
+	go func() {
+		for {
+			select {
+			case <-redraws:
+				m.app.QueueUpdateDraw(m.render)
+			case <-stopped:
+				return
+			}
+		}
+	}()
+	go func() {
+		builds()
+		// stopped from the application's goroutine, which only runs once the application is started
+		m.app.QueueUpdateDraw(m.app.Stop)
+	}()
+
+	m.app.SetRoot(m.pages, true)
+	err := m.app.Run()
+	close(stopped)
+	return err
+}
+
+func (m *MultiBuild) handleLine(image, line string) {
+	m.update(image, func(row *buildRow) {
+		if match := phaseHeaderMatcher.FindStringSubmatch(line); match != nil {
+			row.phase = strings.ToLower(match[1])
+		} else if match := phasePrefixMatcher.FindStringSubmatch(line); match != nil {
+			row.phase = match[1]
+		}
+
+		if strings.TrimSpace(line) != "" {
+			row.lastLine = line
+		}
+		row.logs = append(row.logs, line)
+	})
+}
+
+func (m *MultiBuild) update(image string, f func(row *buildRow)) {
+	m.mu.Lock()
+	for _, row := range m.rows {
+		if row.image == image {
+			f(row)
+		}
+	}
+	redraws := m.redraws
+	m.mu.Unlock()
+
+	if redraws == nil {
+		m.app.QueueUpdateDraw(m.render)
+		return
+	}
+	select {
+	case redraws <- struct{}{}:
+	default:
+		// a redraw is already pending
+	}
+}
+
+func (m *MultiBuild) focus(index int) {
+	m.mu.Lock()
+	if index >= len(m.rows) {
+		index = -1
+	}
+	m.focused = index
+	m.mu.Unlock()
+
+	m.render()
+}
+
+// render redraws the dashboard, it must be called from the application's goroutine
+func (m *MultiBuild) render() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.table.Clear()
+	for col, header := range []string{"IMAGE", "PHASE", "ELAPSED", "LAST LOG LINE"} {
+		m.table.SetCell(0, col, tview.NewTableCell(header).SetSelectable(false).SetAttributes(tcell.AttrBold))
+	}
+	for i, row := range m.rows {
+		m.table.SetCell(i+1, 0, tview.NewTableCell(row.image))
+		m.table.SetCell(i+1, 1, tview.NewTableCell(row.phase).SetTextColor(phaseColor(row)))
+		m.table.SetCell(i+1, 2, tview.NewTableCell(m.elapsed(row)))
+		m.table.SetCell(i+1, 3, tview.NewTableCell(row.lastLine).SetExpansion(1))
+	}
+
+	if m.focused < 0 {
+		m.pages.SwitchToPage("overview")
+		return
+	}
+
+	row := m.rows[m.focused]
+	m.logView.SetTitle(fmt.Sprintf("| [::b]%s[::-] | %s | esc: back |", row.image, row.phase))
+	m.logView.SetText(strings.Join(row.logs, "\n"))
+	m.logView.ScrollToEnd()
+	m.pages.SwitchToPage("log")
+}
+
+func (m *MultiBuild) elapsed(row *buildRow) string {
+	switch {
+	case row.started.IsZero():
+		return "-"
+	case row.finished.IsZero():
+		return m.clock().Sub(row.started).Round(time.Second).String()
+	default:
+		return row.finished.Sub(row.started).Round(time.Second).String()
+	}
+}
+
+func phaseColor(row *buildRow) tcell.Color {
+	switch row.phase {
+	case statusPending:
+		return tcell.ColorGray
+	case statusSucceeded:
+		return tcell.ColorGreen
+	case statusFailed:
+		return tcell.ColorRed
+	default:
+		return tcell.ColorDodgerBlue
+	}
+}
+
+type multiBuildWriter struct {
+	dashboard *MultiBuild
+	image     string
+	buf       bytes.Buffer
+}
+
+func (w *multiBuildWriter) Write(p []byte) (int, error) {
+	w.buf.Write(p)
+	for {
+		line, err := w.buf.ReadString('\n')
+		if err != nil {
+			// keep the incomplete line until the rest of it is written
+			w.buf.Reset()
+			w.buf.WriteString(line)
+			return len(p), nil
+		}
+		w.dashboard.handleLine(w.image, strings.TrimRight(line, "\r\n"))
+	}
+}
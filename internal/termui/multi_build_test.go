@@ -0,0 +1,117 @@
+package termui
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/termui/fakes"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestMultiBuild(t *testing.T) {
+	spec.Run(t, "MultiBuild", testMultiBuild, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testMultiBuild(t *testing.T, when spec.G, it spec.S) {
+	var (
+		fakeApp   *fakes.App
+		now       time.Time
+		dashboard *MultiBuild
+	)
+
+	it.Before(func() {
+		fakeApp = fakes.NewApp()
+		now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		dashboard = newMultiBuild(fakeApp, func() time.Time { return now }, []string{"some/app-1", "some/app-2"})
+	})
+
+	cell := func(row, col int) string {
+		return dashboard.table.GetCell(row, col).Text
+	}
+
+	it("shows a pending row per image", func() {
+		h.AssertEq(t, dashboard.table.GetRowCount(), 3)
+		h.AssertEq(t, cell(1, 0), "some/app-1")
+		h.AssertEq(t, cell(1, 1), "pending")
+		h.AssertEq(t, cell(1, 2), "-")
+		h.AssertEq(t, cell(2, 0), "some/app-2")
+	})
+
+	it("tracks the phase, elapsed time and last line of each build", func() {
+		dashboard.Start("some/app-1")
+		w := dashboard.Writer("some/app-1")
+
+		fmt.Fprint(w, "===> DETECTING\n[detector] some/buildpack 1.0.0\n===> BUIL")
+		now = now.Add(5 * time.Second)
+		fmt.Fprint(w, "DING\nsome build output\n")
+
+		h.AssertEq(t, cell(1, 1), "building")
+		h.AssertEq(t, cell(1, 2), "5s")
+		h.AssertEq(t, cell(1, 3), "some build output")
+		h.AssertEq(t, cell(2, 1), "pending")
+
+		w = dashboard.Writer("some/app-2")
+		fmt.Fprint(w, "[analyzer] some analyzer output\n")
+		h.AssertEq(t, cell(2, 1), "analyzer")
+	})
+
+	it("shows the result of finished builds", func() {
+		dashboard.Start("some/app-1")
+		dashboard.Start("some/app-2")
+		now = now.Add(time.Minute)
+		dashboard.Finish("some/app-1", nil)
+		dashboard.Finish("some/app-2", errors.New("some error"))
+		now = now.Add(time.Minute)
+
+		h.AssertEq(t, cell(1, 1), "succeeded")
+		h.AssertEq(t, cell(1, 2), "1m0s")
+		h.AssertEq(t, cell(2, 1), "failed")
+		h.AssertEq(t, cell(2, 3), "some error")
+	})
+
+	it("shows the complete log of a focused build", func() {
+		fmt.Fprint(dashboard.Writer("some/app-2"), "first line\nsecond line\n")
+
+		dashboard.focus(1)
+		name, _ := dashboard.pages.GetFrontPage()
+		h.AssertEq(t, name, "log")
+		h.AssertEq(t, dashboard.logView.GetText(true), "first line\nsecond line")
+
+		dashboard.logView.GetInputCapture()(tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone))
+		name, _ = dashboard.pages.GetFrontPage()
+		h.AssertEq(t, name, "overview")
+	})
+
+	it("closes once all builds are done", func() {
+		builds := make(chan bool)
+		ran := make(chan error)
+		go func() { ran <- dashboard.Run(func() { <-builds }) }()
+
+		builds <- true
+		h.AssertNil(t, <-ran)
+		h.AssertEq(t, fakeApp.StopCallCount, 1)
+	})
+
+	it("quits on q while builds run", func() {
+		builds := make(chan bool)
+		defer close(builds)
+		ran := make(chan error)
+		go func() { ran <- dashboard.Run(func() { <-builds }) }()
+
+		dashboard.pages.GetInputCapture()(tcell.NewEventKey(tcell.KeyRune, 'q', tcell.ModNone))
+		h.AssertNil(t, <-ran)
+	})
+
+	it("returns the logs of a build", func() {
+		fmt.Fprint(dashboard.Writer("some/app-1"), "first line\nsecond line\n")
+
+		h.AssertEq(t, dashboard.Logs("some/app-1"), []string{"first line", "second line"})
+		h.AssertEq(t, len(dashboard.Logs("some/app-2")), 0)
+	})
+}
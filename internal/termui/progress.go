@@ -0,0 +1,403 @@
+package termui
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+)
+
+var (
+	ansiMatcher = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+	// the builder logs these at debug level around the build of each buildpack, and around the output of the buildpack
+	buildpackStartMatcher  = regexp.MustCompile(`^Running build for buildpack ([^@\s]+)@(\S+)`)
+	buildpackFinishMatcher = regexp.MustCompile(`^Finished running build for buildpack `)
+
+	groupMatcher       = regexp.MustCompile(`^(\d+) of \d+ buildpacks participating`)
+	groupEntryMatcher  = regexp.MustCompile(`^(\S+)\s+\S+$`)
+	exportLayerMatcher = regexp.MustCompile(`^(?:Adding|Reusing) layer '([^':]+):`)
+)
+
+// phaseNames are the lifecycle phases of the headers written by pack and the creator
+var phaseNames = map[string]string{
+	"ANALYZING": "analyzer",
+	"DETECTING": "detector",
+	"RESTORING": "restorer",
+	"EXTENDING": "extender",
+	"BUILDING":  "builder",
+	"EXPORTING": "exporter",
+}
+
+// milestones of the exporter after the layers of the buildpacks, in the order it reaches them
+var exportMilestones = [][]string{
+	{"Adding label "},
+	{"Saving "},
+	{"*** Images "},
+	{"Adding cache layer ", "Reusing cache layer ", "Layer cache not found"},
+}
+
+const (
+	progressInterval    = 100 * time.Millisecond
+	progressTailLines   = 4
+	progressNameWidth   = 42
+	progressBarWidth    = 20
+	defaultProgressWide = 120
+)
+
+type progressStep struct {
+	name       string
+	started    time.Time
+	finished   time.Time
+	failed     bool
+	logs       []string
+	buildpacks []*progressStep
+}
+
+// Progress shows the progress of a build in a terminal, like the output of BuildKit. Each lifecycle phase gets a line
+// with its elapsed time, followed by the last lines it logged while it runs. The builder lists the buildpacks it ran,
+// showing the output of the running one and collapsing it once done, and the exporter shows a progress bar. As the
+// output is redrawn in place, Progress must only write to a terminal. Once the build failed, the logs of the failed
+// phase or buildpack are written in full.
+//
+// The buildpacks are only listed when the lifecycle logs at debug level, as the builder doesn't log them otherwise.
+type Progress struct {
+	out   io.Writer
+	title string
+	clock func() time.Time
+	width int
+
+	mu        sync.Mutex
+	buf       bytes.Buffer
+	started   time.Time
+	steps     []*progressStep
+	buildpack *progressStep
+	capturing bool
+
+	// buildpacks of the detected group, in the order the exporter adds their layers
+	group     []string
+	groupLeft int
+
+	// milestones reached by the exporter, the layers of the buildpacks of the group then exportMilestones
+	exported int
+
+	drawn int
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+// NewProgress creates a Progress of the build of the image title, drawn to the terminal out
+func NewProgress(out io.Writer, title string) *Progress {
+	return newProgress(out, title, time.Now, terminalWidth(out))
+}
+
+func newProgress(out io.Writer, title string, clock func() time.Time, width int) *Progress {
+	return &Progress{
+		out:     out,
+		title:   title,
+		clock:   clock,
+		width:   width,
+		started: clock(),
+	}
+}
+
+// Start redraws the progress until Finish is called
+func (p *Progress) Start() {
+	p.stop = make(chan struct{})
+	p.done = make(chan struct{})
+	go func() {
+		defer close(p.done)
+
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+				p.draw()
+			}
+		}
+	}()
+}
+
+// Finish draws the progress of the finished build, failed when err isn't nil, followed by the logs of the failed step
+func (p *Progress) Finish(err error) {
+	if p.stop != nil {
+		close(p.stop)
+		<-p.done
+	}
+
+	p.mu.Lock()
+	now := p.clock()
+	var failed *progressStep
+	if step := p.current(); step != nil {
+		failed = p.finishStep(step, now, err != nil)
+		if p.buildpack != nil {
+			if bp := p.finishStep(p.buildpack, now, err != nil); err != nil && len(bp.logs) > 0 {
+				failed = bp
+			}
+			p.buildpack = nil
+		}
+	}
+	p.mu.Unlock()
+
+	p.draw()
+	if err == nil || failed == nil {
+		return
+	}
+
+	fmt.Fprintf(p.out, "\n=> logs of %s:\n", failed.name)
+	for _, line := range failed.logs {
+		fmt.Fprintln(p.out, line)
+	}
+}
+
+func (p *Progress) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.buf.Write(b)
+	for {
+		line, err := p.buf.ReadString('\n')
+		if err != nil {
+			// keep the incomplete line until the rest of it is written
+			p.buf.Reset()
+			p.buf.WriteString(line)
+			return len(b), nil
+		}
+		p.handleLine(ansiMatcher.ReplaceAllString(strings.TrimRight(line, "\r\n"), ""))
+	}
+}
+
+func (p *Progress) handleLine(line string) {
+	if match := phasePrefixMatcher.FindStringSubmatch(line); match != nil {
+		p.enter(match[1])
+		line = strings.TrimPrefix(line, match[0])
+	} else if match := phaseHeaderMatcher.FindStringSubmatch(line); match != nil {
+		name, ok := phaseNames[match[1]]
+		if !ok {
+			name = strings.ToLower(match[1])
+		}
+		p.enter(name)
+		return
+	}
+
+	step := p.current()
+	if step == nil {
+		// pack logs while pulling images and preparing the build, before the first phase
+		step = p.enter("preparing")
+	}
+	step.logs = append(step.logs, line)
+
+	switch step.name {
+	case "detector":
+		p.handleDetectorLine(line)
+	case "builder":
+		p.handleBuilderLine(step, line)
+	case "exporter":
+		p.handleExporterLine(line)
+	}
+}
+
+func (p *Progress) handleDetectorLine(line string) {
+	if match := groupMatcher.FindStringSubmatch(line); match != nil {
+		p.group = nil
+		p.groupLeft, _ = strconv.Atoi(match[1])
+		return
+	}
+	if p.groupLeft > 0 {
+		if match := groupEntryMatcher.FindStringSubmatch(line); match != nil {
+			p.group = append(p.group, match[1])
+			p.groupLeft--
+		}
+	}
+}
+
+func (p *Progress) handleBuilderLine(builder *progressStep, line string) {
+	now := p.clock()
+	switch {
+	case buildpackStartMatcher.MatchString(line):
+		match := buildpackStartMatcher.FindStringSubmatch(line)
+		if p.buildpack != nil {
+			p.finishStep(p.buildpack, now, false)
+		}
+		p.buildpack = &progressStep{name: match[1] + " " + match[2], started: now}
+		builder.buildpacks = append(builder.buildpacks, p.buildpack)
+	case buildpackFinishMatcher.MatchString(line):
+		if p.buildpack != nil {
+			p.finishStep(p.buildpack, now, false)
+			p.buildpack = nil
+		}
+		p.capturing = false
+	case line == "Running build command":
+		p.capturing = true
+	case line == "Processing layers":
+		p.capturing = false
+	case p.capturing && p.buildpack != nil:
+		p.buildpack.logs = append(p.buildpack.logs, line)
+	}
+}
+
+func (p *Progress) handleExporterLine(line string) {
+	if match := exportLayerMatcher.FindStringSubmatch(line); match != nil {
+		for i, id := range p.group {
+			if id == match[1] {
+				p.reach(i + 1)
+			}
+		}
+		return
+	}
+	for i, prefixes := range exportMilestones {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(line, prefix) {
+				p.reach(len(p.group) + i + 1)
+				return
+			}
+		}
+	}
+}
+
+// reach records the milestones reached by the exporter, which never go back
+func (p *Progress) reach(milestones int) {
+	if milestones > p.exported {
+		p.exported = milestones
+	}
+}
+
+// enter starts the phase name, unless it's the current one
+func (p *Progress) enter(name string) *progressStep {
+	if step := p.current(); step != nil {
+		if step.name == name {
+			return step
+		}
+		p.finishStep(step, p.clock(), false)
+	}
+	step := &progressStep{name: name, started: p.clock()}
+	p.steps = append(p.steps, step)
+	return step
+}
+
+func (p *Progress) current() *progressStep {
+	if len(p.steps) == 0 {
+		return nil
+	}
+	if step := p.steps[len(p.steps)-1]; step.finished.IsZero() {
+		return step
+	}
+	return nil
+}
+
+func (p *Progress) finishStep(step *progressStep, now time.Time, failed bool) *progressStep {
+	if step.finished.IsZero() {
+		step.finished = now
+		step.failed = failed
+	}
+	return step
+}
+
+// draw redraws the progress over the previous one
+func (p *Progress) draw() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	lines := p.render()
+	var out strings.Builder
+	if p.drawn > 0 {
+		// move to the first line drawn, and clear the previous progress
+		fmt.Fprintf(&out, "\x1b[%dA\x1b[J", p.drawn)
+	}
+	for _, line := range lines {
+		out.WriteString(truncate(line, p.width))
+		out.WriteString("\n")
+	}
+	p.drawn = len(lines)
+	_, _ = io.WriteString(p.out, out.String())
+}
+
+// render returns the lines of the progress
+func (p *Progress) render() []string {
+	now := p.clock()
+	lines := []string{fmt.Sprintf("[+] Building %s %s", p.title, formatElapsed(now.Sub(p.started)))}
+	for _, step := range p.steps {
+		name := step.name
+		if step.name == "exporter" {
+			name += " " + p.exportBar(step)
+		}
+		lines = append(lines, stepLine(" => ", name, step, now))
+
+		for _, bp := range step.buildpacks {
+			lines = append(lines, stepLine(" =>   ", bp.name, bp, now))
+			if bp.finished.IsZero() {
+				lines = append(lines, tail(bp.logs, "        ")...)
+			}
+		}
+		if step.finished.IsZero() && len(step.buildpacks) == 0 {
+			lines = append(lines, tail(step.logs, "      ")...)
+		}
+	}
+	return lines
+}
+
+// exportBar is a progress bar of the milestones reached by the exporter
+func (p *Progress) exportBar(step *progressStep) string {
+	total := len(p.group) + len(exportMilestones)
+	done := p.exported
+	if !step.finished.IsZero() && !step.failed {
+		done = total
+	}
+	filled := progressBarWidth * done / total
+	return fmt.Sprintf("[%s%s] %3d%%", strings.Repeat("=", filled), strings.Repeat(" ", progressBarWidth-filled), 100*done/total)
+}
+
+func stepLine(indent, name string, step *progressStep, now time.Time) string {
+	var status string
+	switch {
+	case step.failed:
+		status = "ERROR " + formatElapsed(step.finished.Sub(step.started))
+	case !step.finished.IsZero():
+		status = "DONE " + formatElapsed(step.finished.Sub(step.started))
+	default:
+		status = formatElapsed(now.Sub(step.started))
+	}
+	return fmt.Sprintf("%s%-*s %s", indent, progressNameWidth-len(indent), name, status)
+}
+
+// tail returns the last lines logged, indented
+func tail(logs []string, indent string) []string {
+	var lines []string
+	for i := len(logs) - 1; i >= 0 && len(lines) < progressTailLines; i-- {
+		if strings.TrimSpace(logs[i]) != "" {
+			lines = append([]string{indent + logs[i]}, lines...)
+		}
+	}
+	return lines
+}
+
+func formatElapsed(d time.Duration) string {
+	return fmt.Sprintf("%.1fs", d.Seconds())
+}
+
+// truncate cuts line to width runes, so that lines never wrap and the progress can be redrawn over
+func truncate(line string, width int) string {
+	runes := []rune(line)
+	if width <= 0 || len(runes) <= width {
+		return line
+	}
+	return string(runes[:width])
+}
+
+func terminalWidth(out io.Writer) int {
+	if f, ok := out.(interface{ Fd() uintptr }); ok {
+		if width, _, err := term.GetSize(int(f.Fd())); err == nil && width > 0 {
+			return width
+		}
+	}
+	return defaultProgressWide
+}
@@ -0,0 +1,126 @@
+package termui
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestProgress(t *testing.T) {
+	spec.Run(t, "Progress", testProgress, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testProgress(t *testing.T, when spec.G, it spec.S) {
+	var (
+		out      *bytes.Buffer
+		now      time.Time
+		progress *Progress
+	)
+
+	it.Before(func() {
+		out = &bytes.Buffer{}
+		now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		progress = newProgress(out, "some/app", func() time.Time { return now }, 80)
+	})
+
+	rendered := func() string {
+		progress.mu.Lock()
+		defer progress.mu.Unlock()
+		return strings.Join(progress.render(), "\n")
+	}
+
+	it("shows the elapsed time of each phase, and the last lines of the running one", func() {
+		fmt.Fprint(progress, "Pulling image some/builder\n\x1b[36m[analyzer]\x1b[0m some analyzer output\n")
+		now = now.Add(2 * time.Second)
+		fmt.Fprint(progress, "===> DETECTING\n[detector] some det")
+		now = now.Add(1500 * time.Millisecond)
+		fmt.Fprint(progress, "ector output\n")
+
+		h.AssertEq(t, rendered(), strings.Join([]string{
+			"[+] Building some/app 3.5s",
+			" => preparing                              DONE 0.0s",
+			" => analyzer                               DONE 2.0s",
+			" => detector                               1.5s",
+			"      some detector output",
+		}, "\n"))
+	})
+
+	it("shows the output of the running buildpack, and collapses the finished ones", func() {
+		fmt.Fprint(progress, "[builder] Running build for buildpack some/buildpack@1.0.0\n")
+		fmt.Fprint(progress, "[builder] Running build command\n[builder] some build output\n[builder] Processing layers\n")
+		now = now.Add(3 * time.Second)
+		fmt.Fprint(progress, "[builder] Finished running build for buildpack some/buildpack@1.0.0\n")
+		fmt.Fprint(progress, "[builder] Running build for buildpack some/other-buildpack@2.0.0\n")
+		fmt.Fprint(progress, "[builder] Running build command\n[builder] some other build output\n")
+		now = now.Add(time.Second)
+
+		h.AssertEq(t, rendered(), strings.Join([]string{
+			"[+] Building some/app 4.0s",
+			" => builder                                4.0s",
+			" =>   some/buildpack 1.0.0                 DONE 3.0s",
+			" =>   some/other-buildpack 2.0.0           1.0s",
+			"        some other build output",
+		}, "\n"))
+	})
+
+	it("shows the progress of the exporter", func() {
+		fmt.Fprint(progress, "[detector] 2 of 3 buildpacks participating\n[detector] some/buildpack 1.0.0\n[detector] some/other-buildpack 2.0.0\n")
+		fmt.Fprint(progress, "[exporter] Adding layer 'some/buildpack:some-layer'\n")
+		h.AssertContains(t, rendered(), " => exporter [===                 ]  16%")
+
+		fmt.Fprint(progress, "[exporter] Reusing layer 'some/other-buildpack:some-layer'\n[exporter] Adding label 'io.buildpacks.project.metadata'\n")
+		h.AssertContains(t, rendered(), " => exporter [==========          ]  50%")
+
+		fmt.Fprint(progress, "[exporter] Adding layer 'some/buildpack:some-other-layer'\n")
+		h.AssertContains(t, rendered(), " => exporter [==========          ]  50%")
+
+		progress.Finish(nil)
+		h.AssertContains(t, rendered(), " => exporter [====================] 100%   DONE")
+	})
+
+	it("redraws the progress over the previous one", func() {
+		fmt.Fprint(progress, "[analyzer] some analyzer output\n")
+		progress.draw()
+		out.Reset()
+
+		progress.draw()
+		h.AssertTrue(t, strings.HasPrefix(out.String(), "\x1b[3A\x1b[J[+] Building some/app"))
+	})
+
+	it("cuts lines to the width of the terminal", func() {
+		fmt.Fprintf(progress, "[analyzer] %s\n", strings.Repeat("a", 100))
+		progress.draw()
+
+		for _, line := range strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n") {
+			h.AssertTrue(t, len(line) <= 80)
+		}
+	})
+
+	when("the build fails", func() {
+		it("shows the logs of the failed buildpack", func() {
+			fmt.Fprint(progress, "[builder] Running build for buildpack some/buildpack@1.0.0\n")
+			fmt.Fprint(progress, "[builder] Running build command\n[builder] some build output\n[builder] some build error\n")
+			now = now.Add(time.Second)
+			progress.Finish(errors.New("some error"))
+
+			h.AssertContains(t, out.String(), " =>   some/buildpack 1.0.0                 ERROR 1.0s")
+			h.AssertContains(t, out.String(), "\n=> logs of some/buildpack 1.0.0:\nsome build output\nsome build error\n")
+		})
+
+		it("shows the logs of the failed phase", func() {
+			fmt.Fprint(progress, "[detector] some detector output\n[detector] ERROR: No buildpack groups passed detection.\n")
+			progress.Finish(errors.New("some error"))
+
+			h.AssertContains(t, out.String(), " => detector                               ERROR 0.0s")
+			h.AssertContains(t, out.String(), "\n=> logs of detector:\nsome detector output\nERROR: No buildpack groups passed detection.\n")
+		})
+	})
+}
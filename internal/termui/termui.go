@@ -27,6 +27,7 @@ type app interface {
 	Draw() *tview.Application
 	QueueUpdateDraw(f func()) *tview.Application
 	Run() error
+	Stop()
 }
 
 type buildr interface {
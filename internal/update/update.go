@@ -0,0 +1,336 @@
+// Package update finds the releases of pack published on a release channel, and replaces the running binary with the
+// binary of a release after verifying its checksum and, when a public key is configured, its signature.
+package update
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// DefaultMirror is the GitHub API of the pack repository. A mirror serves the same releases at <mirror>/releases.
+const DefaultMirror = "https://api.github.com/repos/buildpacks/pack"
+
+// DefaultTimeout bounds how long requests for the list of releases may take
+const DefaultTimeout = 5 * time.Second
+
+// Release channels
+const (
+	ChannelStable     = "stable"
+	ChannelPreRelease = "pre-release"
+)
+
+// ValidateChannel returns an error if channel isn't a known release channel
+func ValidateChannel(channel string) error {
+	if channel != ChannelStable && channel != ChannelPreRelease {
+		return errors.Errorf("unknown release channel %s, must be one of %s or %s", style.Symbol(channel), ChannelStable, ChannelPreRelease)
+	}
+	return nil
+}
+
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+type Release struct {
+	Tag        string  `json:"tag_name"`
+	Draft      bool    `json:"draft"`
+	Prerelease bool    `json:"prerelease"`
+	Assets     []Asset `json:"assets"`
+
+	Version *semver.Version `json:"-"`
+}
+
+// Newer returns whether the release is newer than the version of pack currently running. Versions that aren't
+// releases, e.g. development builds, are never considered up to date.
+func (r Release) Newer(current string) bool {
+	currentVersion, err := semver.NewVersion(current)
+	if err != nil {
+		return true
+	}
+	return r.Version.GreaterThan(currentVersion)
+}
+
+func (r Release) asset(name string) (Asset, bool) {
+	for _, asset := range r.Assets {
+		if asset.Name == name {
+			return asset, true
+		}
+	}
+	return Asset{}, false
+}
+
+// Updater finds and downloads releases from a mirror
+type Updater struct {
+	mirror    string
+	publicKey []byte
+	client    *http.Client
+}
+
+// UpdaterOption configures an Updater
+type UpdaterOption func(*Updater)
+
+// WithHTTPClient sets the client used to reach the mirror
+func WithHTTPClient(client *http.Client) UpdaterOption {
+	return func(u *Updater) {
+		u.client = client
+	}
+}
+
+// WithPublicKey requires downloaded archives to be signed by the private key of a PEM encoded public key
+func WithPublicKey(publicKey []byte) UpdaterOption {
+	return func(u *Updater) {
+		u.publicKey = publicKey
+	}
+}
+
+// NewUpdater creates an Updater for the releases of mirror, or of DefaultMirror when mirror is empty
+func NewUpdater(mirror string, ops ...UpdaterOption) *Updater {
+	if mirror == "" {
+		mirror = DefaultMirror
+	}
+	u := &Updater{
+		mirror: strings.TrimSuffix(mirror, "/"),
+		client: &http.Client{},
+	}
+
+	for _, op := range ops {
+		op(u)
+	}
+	return u
+}
+
+// Latest returns the most recent release of channel. Pre-releases are only part of the pre-release channel.
+func (u *Updater) Latest(ctx context.Context, channel string) (Release, error) {
+	if err := ValidateChannel(channel); err != nil {
+		return Release{}, err
+	}
+
+	body, err := u.get(ctx, u.mirror+"/releases")
+	if err != nil {
+		return Release{}, errors.Wrap(err, "listing releases")
+	}
+
+	var releases []Release
+	if err := json.Unmarshal(body, &releases); err != nil {
+		return Release{}, errors.Wrap(err, "reading releases")
+	}
+
+	var latest Release
+	for _, release := range releases {
+		if release.Draft || (release.Prerelease && channel == ChannelStable) {
+			continue
+		}
+		version, err := semver.NewVersion(release.Tag)
+		if err != nil {
+			continue
+		}
+		if latest.Version == nil || version.GreaterThan(latest.Version) {
+			release.Version = version
+			latest = release
+		}
+	}
+	if latest.Version == nil {
+		return Release{}, errors.Errorf("no %s release found at %s", channel, style.Symbol(u.mirror))
+	}
+	return latest, nil
+}
+
+// AssetName returns the name of the archive of a release for an OS and architecture, e.g. pack-v0.33.2-macos-arm64.tgz
+func AssetName(version *semver.Version, goos, goarch string) string {
+	platform := goos
+	if goos == "darwin" {
+		platform = "macos"
+	}
+	if goarch != "amd64" {
+		platform += "-" + goarch
+	}
+
+	ext := ".tgz"
+	if goos == "windows" {
+		ext = ".zip"
+	}
+	return fmt.Sprintf("pack-v%s-%s%s", version, platform, ext)
+}
+
+// Download returns the pack binary of a release for an OS and architecture, once the checksum of its archive, and
+// signature when a public key is set, are verified
+func (u *Updater) Download(ctx context.Context, release Release, goos, goarch string) ([]byte, error) {
+	name := AssetName(release.Version, goos, goarch)
+	archive, ok := release.asset(name)
+	if !ok {
+		return nil, errors.Errorf("release %s has no archive %s", style.Symbol(release.Tag), style.Symbol(name))
+	}
+	checksum, ok := release.asset(name + ".sha256")
+	if !ok {
+		return nil, errors.Errorf("release %s has no checksum for %s", style.Symbol(release.Tag), style.Symbol(name))
+	}
+
+	data, err := u.get(ctx, archive.URL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "downloading %s", style.Symbol(name))
+	}
+	expected, err := u.get(ctx, checksum.URL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "downloading checksum of %s", style.Symbol(name))
+	}
+	if err := verifyChecksum(data, string(expected)); err != nil {
+		return nil, errors.Wrapf(err, "verifying %s", style.Symbol(name))
+	}
+
+	if u.publicKey != nil {
+		signature, ok := release.asset(name + ".sig")
+		if !ok {
+			return nil, errors.Errorf("release %s has no signature for %s", style.Symbol(release.Tag), style.Symbol(name))
+		}
+		encoded, err := u.get(ctx, signature.URL)
+		if err != nil {
+			return nil, errors.Wrapf(err, "downloading signature of %s", style.Symbol(name))
+		}
+		if err := verifySignature(data, strings.TrimSpace(string(encoded)), u.publicKey); err != nil {
+			return nil, errors.Wrapf(err, "verifying %s", style.Symbol(name))
+		}
+	}
+
+	binary := "pack"
+	if goos == "windows" {
+		binary = "pack.exe"
+	}
+	return extract(data, name, binary)
+}
+
+func (u *Updater) get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, errors.Errorf("unexpected status %s from %s", resp.Status, style.Symbol(url))
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// verifyChecksum compares the SHA-256 digest of data to a checksum file in the format of sha256sum
+func verifyChecksum(data []byte, checksumFile string) error {
+	fields := strings.Fields(checksumFile)
+	if len(fields) == 0 {
+		return errors.New("checksum is empty")
+	}
+
+	digest := sha256.Sum256(data)
+	if actual := hex.EncodeToString(digest[:]); !strings.EqualFold(actual, fields[0]) {
+		return errors.Errorf("checksum mismatch, expected %s but got %s", fields[0], actual)
+	}
+	return nil
+}
+
+// extract returns the contents of the file named binary at the root of a tgz or zip archive
+func extract(archive []byte, name, binary string) ([]byte, error) {
+	if strings.HasSuffix(name, ".zip") {
+		reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading %s", style.Symbol(name))
+		}
+		for _, file := range reader.File {
+			if file.Name == binary {
+				rc, err := file.Open()
+				if err != nil {
+					return nil, err
+				}
+				defer rc.Close()
+				return io.ReadAll(rc)
+			}
+		}
+		return nil, errors.Errorf("%s has no %s", style.Symbol(name), style.Symbol(binary))
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %s", style.Symbol(name))
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil, errors.Errorf("%s has no %s", style.Symbol(name), style.Symbol(binary))
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading %s", style.Symbol(name))
+		}
+		if strings.TrimPrefix(header.Name, "./") == binary {
+			return io.ReadAll(tr)
+		}
+	}
+}
+
+// Replace replaces the executable at path with binary. The executable is moved aside before the new binary takes its
+// place, as the running executable can't be overwritten on Windows.
+func Replace(path string, binary []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	newPath := path + ".new"
+	if err := os.WriteFile(newPath, binary, info.Mode().Perm()|0700); err != nil {
+		return errors.Wrapf(err, "writing %s", style.Symbol(newPath))
+	}
+
+	oldPath := path + ".old"
+	_ = os.Remove(oldPath)
+	if err := os.Rename(path, oldPath); err != nil {
+		os.Remove(newPath)
+		return errors.Wrapf(err, "moving %s", style.Symbol(path))
+	}
+	if err := os.Rename(newPath, path); err != nil {
+		// put the previous executable back
+		_ = os.Rename(oldPath, path)
+		return errors.Wrapf(err, "replacing %s", style.Symbol(path))
+	}
+
+	// the previous executable is left behind on Windows while it's running
+	_ = os.Remove(oldPath)
+	return nil
+}
+
+// CheckDue returns whether the notice of a new version should check for releases again, as the last check is older
+// than a day. The time of the check is recorded in stateDir.
+func CheckDue(stateDir string, now time.Time) bool {
+	contents, err := os.ReadFile(filepath.Join(stateDir, "update-check"))
+	if err == nil {
+		if last, err := time.Parse(time.RFC3339, strings.TrimSpace(string(contents))); err == nil && now.Sub(last) < 24*time.Hour {
+			return false
+		}
+	}
+
+	_ = os.MkdirAll(stateDir, 0750)
+	_ = os.WriteFile(filepath.Join(stateDir, "update-check"), []byte(now.UTC().Format(time.RFC3339)), 0600)
+	return true
+}
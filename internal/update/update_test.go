@@ -0,0 +1,230 @@
+package update_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/update"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestUpdate(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Update", testUpdate, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testUpdate(t *testing.T, when spec.G, it spec.S) {
+	var (
+		server *httptest.Server
+		files  map[string][]byte
+	)
+
+	tgz := func(name, contents string) []byte {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gz)
+		h.AssertNil(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(contents))}))
+		_, err := tw.Write([]byte(contents))
+		h.AssertNil(t, err)
+		h.AssertNil(t, tw.Close())
+		h.AssertNil(t, gz.Close())
+		return buf.Bytes()
+	}
+
+	release := func(tag string, prerelease bool, assets ...string) map[string]interface{} {
+		var list []map[string]string
+		for _, asset := range assets {
+			list = append(list, map[string]string{"name": asset, "browser_download_url": server.URL + "/download/" + asset})
+		}
+		return map[string]interface{}{"tag_name": tag, "prerelease": prerelease, "assets": list}
+	}
+
+	it.Before(func() {
+		files = map[string][]byte{}
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			contents, ok := files[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(contents)
+		}))
+
+		archive := tgz("pack", "new pack binary")
+		digest := sha256.Sum256(archive)
+		files["/download/pack-v0.34.0-linux.tgz"] = archive
+		files["/download/pack-v0.34.0-linux.tgz.sha256"] = []byte(hex.EncodeToString(digest[:]) + "  pack-v0.34.0-linux.tgz\n")
+
+		releases, err := json.Marshal([]map[string]interface{}{
+			release("v0.35.0-rc1", true),
+			release("v0.34.0", false, "pack-v0.34.0-linux.tgz", "pack-v0.34.0-linux.tgz.sha256", "pack-v0.34.0-linux.tgz.sig"),
+			release("v0.33.2", false),
+			{"tag_name": "v0.36.0", "draft": true},
+		})
+		h.AssertNil(t, err)
+		files["/releases"] = releases
+	})
+
+	it.After(func() {
+		server.Close()
+	})
+
+	when("#Latest", func() {
+		it("returns the latest release of the stable channel", func() {
+			latest, err := update.NewUpdater(server.URL).Latest(context.Background(), update.ChannelStable)
+			h.AssertNil(t, err)
+			h.AssertEq(t, latest.Tag, "v0.34.0")
+			h.AssertTrue(t, latest.Newer("0.33.2+git-8d1b1e8.build-5623"))
+			h.AssertFalse(t, latest.Newer("0.34.0"))
+			h.AssertTrue(t, latest.Newer("dev"))
+		})
+
+		it("includes pre-releases in the pre-release channel", func() {
+			latest, err := update.NewUpdater(server.URL).Latest(context.Background(), update.ChannelPreRelease)
+			h.AssertNil(t, err)
+			h.AssertEq(t, latest.Tag, "v0.35.0-rc1")
+		})
+
+		it("fails for unknown channels", func() {
+			_, err := update.NewUpdater(server.URL).Latest(context.Background(), "nightly")
+			h.AssertError(t, err, "unknown release channel 'nightly', must be one of stable or pre-release")
+		})
+	})
+
+	when("#Download", func() {
+		it("returns the binary once its checksum is verified", func() {
+			updater := update.NewUpdater(server.URL)
+			latest, err := updater.Latest(context.Background(), update.ChannelStable)
+			h.AssertNil(t, err)
+
+			binary, err := updater.Download(context.Background(), latest, "linux", "amd64")
+			h.AssertNil(t, err)
+			h.AssertEq(t, string(binary), "new pack binary")
+		})
+
+		it("fails when the checksum doesn't match", func() {
+			files["/download/pack-v0.34.0-linux.tgz.sha256"] = []byte("0000  pack-v0.34.0-linux.tgz\n")
+			updater := update.NewUpdater(server.URL)
+			latest, err := updater.Latest(context.Background(), update.ChannelStable)
+			h.AssertNil(t, err)
+
+			_, err = updater.Download(context.Background(), latest, "linux", "amd64")
+			h.AssertError(t, err, "verifying 'pack-v0.34.0-linux.tgz': checksum mismatch")
+		})
+
+		it("fails when the release has no archive for the platform", func() {
+			updater := update.NewUpdater(server.URL)
+			latest, err := updater.Latest(context.Background(), update.ChannelStable)
+			h.AssertNil(t, err)
+
+			_, err = updater.Download(context.Background(), latest, "darwin", "arm64")
+			h.AssertError(t, err, "release 'v0.34.0' has no archive 'pack-v0.34.0-macos-arm64.tgz'")
+		})
+
+		when("a public key is set", func() {
+			var (
+				publicKeyPEM []byte
+				privateKey   ed25519.PrivateKey
+			)
+
+			it.Before(func() {
+				publicKey, private, err := ed25519.GenerateKey(rand.Reader)
+				h.AssertNil(t, err)
+				privateKey = private
+				der, err := x509.MarshalPKIXPublicKey(publicKey)
+				h.AssertNil(t, err)
+				publicKeyPEM = pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+			})
+
+			it("verifies the signature of the archive", func() {
+				signature := ed25519.Sign(privateKey, files["/download/pack-v0.34.0-linux.tgz"])
+				files["/download/pack-v0.34.0-linux.tgz.sig"] = []byte(base64.StdEncoding.EncodeToString(signature))
+
+				updater := update.NewUpdater(server.URL, update.WithPublicKey(publicKeyPEM))
+				latest, err := updater.Latest(context.Background(), update.ChannelStable)
+				h.AssertNil(t, err)
+
+				binary, err := updater.Download(context.Background(), latest, "linux", "amd64")
+				h.AssertNil(t, err)
+				h.AssertEq(t, string(binary), "new pack binary")
+			})
+
+			it("fails when the signature doesn't match", func() {
+				signature := ed25519.Sign(privateKey, []byte("something else"))
+				files["/download/pack-v0.34.0-linux.tgz.sig"] = []byte(base64.StdEncoding.EncodeToString(signature))
+
+				updater := update.NewUpdater(server.URL, update.WithPublicKey(publicKeyPEM))
+				latest, err := updater.Latest(context.Background(), update.ChannelStable)
+				h.AssertNil(t, err)
+
+				_, err = updater.Download(context.Background(), latest, "linux", "amd64")
+				h.AssertError(t, err, "verifying 'pack-v0.34.0-linux.tgz': signature doesn't match the public key")
+			})
+		})
+	})
+
+	when("#AssetName", func() {
+		it("names archives like the pack releases", func() {
+			version := semver.MustParse("0.34.0")
+			h.AssertEq(t, update.AssetName(version, "linux", "amd64"), "pack-v0.34.0-linux.tgz")
+			h.AssertEq(t, update.AssetName(version, "linux", "arm64"), "pack-v0.34.0-linux-arm64.tgz")
+			h.AssertEq(t, update.AssetName(version, "darwin", "arm64"), "pack-v0.34.0-macos-arm64.tgz")
+			h.AssertEq(t, update.AssetName(version, "windows", "amd64"), "pack-v0.34.0-windows.zip")
+		})
+	})
+
+	when("#Replace", func() {
+		it("replaces the executable", func() {
+			dir, err := os.MkdirTemp("", "update")
+			h.AssertNil(t, err)
+			defer os.RemoveAll(dir)
+
+			path := filepath.Join(dir, "pack")
+			h.AssertNil(t, os.WriteFile(path, []byte("old pack binary"), 0755))
+
+			h.AssertNil(t, update.Replace(path, []byte("new pack binary")))
+
+			contents, err := os.ReadFile(path)
+			h.AssertNil(t, err)
+			h.AssertEq(t, string(contents), "new pack binary")
+			h.AssertPathDoesNotExists(t, path+".old")
+			h.AssertPathDoesNotExists(t, path+".new")
+		})
+	})
+
+	when("#CheckDue", func() {
+		it("checks at most once a day", func() {
+			dir, err := os.MkdirTemp("", "update")
+			h.AssertNil(t, err)
+			defer os.RemoveAll(dir)
+
+			now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+			h.AssertTrue(t, update.CheckDue(dir, now))
+			h.AssertFalse(t, update.CheckDue(dir, now.Add(time.Hour)))
+			h.AssertTrue(t, update.CheckDue(dir, now.Add(25*time.Hour)))
+			h.AssertFalse(t, update.CheckDue(dir, now.Add(26*time.Hour)))
+		})
+	})
+}
@@ -0,0 +1,50 @@
+package update
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+
+	"github.com/pkg/errors"
+)
+
+// verifySignature verifies a base64 encoded signature of data with a PEM encoded public key. ECDSA and RSA keys
+// verify the SHA-256 digest of data, while Ed25519 keys verify data itself.
+func verifySignature(data []byte, signature string, publicKeyPEM []byte) error {
+	block, _ := pem.Decode(publicKeyPEM)
+	if block == nil {
+		return errors.New("public key isn't PEM encoded")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return errors.Wrap(err, "parsing public key")
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return errors.Wrap(err, "decoding signature")
+	}
+
+	digest := sha256.Sum256(data)
+	var valid bool
+	switch key := key.(type) {
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(key, data, decoded)
+	case *ecdsa.PublicKey:
+		valid = ecdsa.VerifyASN1(key, digest[:], decoded)
+	case *rsa.PublicKey:
+		valid = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], decoded) == nil
+	default:
+		return errors.Errorf("unsupported public key type %T", key)
+	}
+
+	if !valid {
+		return errors.New("signature doesn't match the public key")
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+// Package watch notices changes to the files of a directory by polling it, which works the same on every platform
+// and file system, including the bind mounts of virtual machines that don't propagate file system events.
+package watch
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultIgnore are the paths never watched
+var DefaultIgnore = []string{".git"}
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+	mode    fs.FileMode
+}
+
+// Snapshot is the state of the files of a directory, by path relative to the directory
+type Snapshot map[string]fileState
+
+// Take takes a snapshot of dir. Paths matching a pattern of ignore, either by their path relative to dir or by
+// their base name, are left out, along with their contents when they are directories.
+func Take(dir string, ignore []string) (Snapshot, error) {
+	snapshot := Snapshot{}
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		if ignored(rel, ignore) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		snapshot[rel] = fileState{modTime: info.ModTime(), size: info.Size(), mode: info.Mode()}
+		return nil
+	})
+	return snapshot, err
+}
+
+func ignored(rel string, ignore []string) bool {
+	base := rel[strings.LastIndex(rel, "/")+1:]
+	for _, pattern := range ignore {
+		pattern = strings.TrimSuffix(filepath.ToSlash(pattern), "/")
+		if matched, _ := filepath.Match(pattern, rel); matched {
+			return true
+		}
+		if matched, _ := filepath.Match(pattern, base); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Changes returns the paths added, removed or modified since s, sorted
+func (s Snapshot) Changes(since Snapshot) []string {
+	var changes []string
+	for path, state := range s {
+		if previous, ok := since[path]; !ok || previous != state {
+			changes = append(changes, path)
+		}
+	}
+	for path := range since {
+		if _, ok := s[path]; !ok {
+			changes = append(changes, path)
+		}
+	}
+	sort.Strings(changes)
+	return changes
+}
+
+// Wait polls dir every interval until its files change from since, and returns the new snapshot along with the
+// changed paths. Changes are only returned once a poll finds no further change, so that a burst of writes, e.g.
+// from a checkout or an editor saving several files, leads to a single rebuild.
+func Wait(ctx context.Context, dir string, ignore []string, interval time.Duration, since Snapshot) (Snapshot, []string, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var (
+		pending bool
+		last    = since
+	)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-ticker.C:
+		}
+
+		current, err := Take(dir, ignore)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(current.Changes(last)) > 0 {
+			pending = true
+			last = current
+			continue
+		}
+		if pending {
+			// files changed back during the burst need no rebuild
+			if changes := current.Changes(since); len(changes) > 0 {
+				return current, changes, nil
+			}
+			pending = false
+		}
+	}
+}
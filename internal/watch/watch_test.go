@@ -0,0 +1,92 @@
+package watch_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/watch"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestWatch(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Watch", testWatch, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testWatch(t *testing.T, when spec.G, it spec.S) {
+	var dir string
+
+	it.Before(func() {
+		dir = t.TempDir()
+		h.AssertNil(t, os.MkdirAll(filepath.Join(dir, "src"), 0755))
+		h.AssertNil(t, os.WriteFile(filepath.Join(dir, "src", "main.go"), []byte("package main"), 0600))
+		h.AssertNil(t, os.MkdirAll(filepath.Join(dir, ".git"), 0755))
+		h.AssertNil(t, os.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("ref"), 0600))
+	})
+
+	when("#Take", func() {
+		it("leaves out ignored paths", func() {
+			h.AssertNil(t, os.WriteFile(filepath.Join(dir, "app.log"), []byte("log"), 0600))
+
+			snapshot, err := watch.Take(dir, append(watch.DefaultIgnore, "*.log"))
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(snapshot), 2)
+			h.AssertEq(t, snapshot.Changes(watch.Snapshot{}), []string{"src", "src/main.go"})
+		})
+	})
+
+	when("#Changes", func() {
+		it("returns added, removed and modified paths", func() {
+			before, err := watch.Take(dir, watch.DefaultIgnore)
+			h.AssertNil(t, err)
+
+			h.AssertNil(t, os.WriteFile(filepath.Join(dir, "src", "main.go"), []byte("package main\n\nfunc main() {}"), 0600))
+			h.AssertNil(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("readme"), 0600))
+			after, err := watch.Take(dir, watch.DefaultIgnore)
+			h.AssertNil(t, err)
+			h.AssertEq(t, after.Changes(before), []string{"README.md", "src/main.go"})
+
+			h.AssertNil(t, os.Remove(filepath.Join(dir, "README.md")))
+			removed, err := watch.Take(dir, watch.DefaultIgnore)
+			h.AssertNil(t, err)
+			h.AssertEq(t, removed.Changes(after), []string{"README.md"})
+		})
+	})
+
+	when("#Wait", func() {
+		it("returns the changes", func() {
+			since, err := watch.Take(dir, watch.DefaultIgnore)
+			h.AssertNil(t, err)
+
+			go func() {
+				time.Sleep(20 * time.Millisecond)
+				_ = os.WriteFile(filepath.Join(dir, "src", "util.go"), []byte("package main"), 0600)
+				_ = os.WriteFile(filepath.Join(dir, ".git", "index"), []byte("index"), 0600)
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			_, changes, err := watch.Wait(ctx, dir, watch.DefaultIgnore, 5*time.Millisecond, since)
+			h.AssertNil(t, err)
+			h.AssertEq(t, changes, []string{"src", "src/util.go"})
+		})
+
+		it("stops when the context is done", func() {
+			since, err := watch.Take(dir, watch.DefaultIgnore)
+			h.AssertNil(t, err)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			_, _, err = watch.Wait(ctx, dir, watch.DefaultIgnore, 5*time.Millisecond, since)
+			h.AssertError(t, err, context.Canceled.Error())
+		})
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"sync"
 
 	"github.com/mitchellh/ioprogress"
 	"github.com/pkg/errors"
@@ -44,6 +45,9 @@ type downloader struct {
 	logger       Logger
 	baseCacheDir string
 	client       *http.Client
+
+	// locks holds a mutex per cache path, so that concurrent builds download a blob once
+	locks sync.Map
 }
 
 func NewDownloader(logger Logger, baseCacheDir string, opts ...DownloaderOption) Downloader {
@@ -110,6 +114,10 @@ func (d *downloader) handleHTTP(ctx context.Context, uri string) (string, error)
 
 	cachePath := filepath.Join(cacheDir, fmt.Sprintf("%x", sha256.Sum256([]byte(uri))))
 
+	lock, _ := d.locks.LoadOrStore(cachePath, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
 	etagFile := cachePath + ".etag"
 	etagExists, err := fileExists(etagFile)
 	if err != nil {
@@ -133,17 +141,25 @@ func (d *downloader) handleHTTP(ctx context.Context, uri string) (string, error)
 	}
 	defer reader.Close()
 
-	fh, err := os.Create(cachePath)
+	// write to a temporary file first so that other processes never read a partially downloaded blob
+	fh, err := os.CreateTemp(cacheDir, filepath.Base(cachePath)+".*.tmp")
 	if err != nil {
 		return "", errors.Wrapf(err, "create cache path %s", style.Symbol(cachePath))
 	}
-	defer fh.Close()
+	defer os.Remove(fh.Name())
 
 	_, err = io.Copy(fh, reader)
+	if closeErr := fh.Close(); err == nil {
+		err = closeErr
+	}
 	if err != nil {
 		return "", errors.Wrap(err, "writing cache")
 	}
 
+	if err = os.Rename(fh.Name(), cachePath); err != nil {
+		return "", errors.Wrap(err, "writing cache")
+	}
+
 	if err = os.WriteFile(etagFile, []byte(etag), 0744); err != nil {
 		return "", errors.Wrap(err, "writing etag")
 	}
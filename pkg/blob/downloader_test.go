@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/heroku/color"
@@ -133,6 +134,27 @@ func testDownloader(t *testing.T, when spec.G, it spec.S) {
 					h.AssertNil(t, err)
 					assertBlob(t, b)
 				})
+
+				it("downloads once when the same URI is requested concurrently", func() {
+					var wg sync.WaitGroup
+					blobs := make([]blob.Blob, 2)
+					errs := make([]error, 2)
+					for i := range blobs {
+						wg.Add(1)
+						go func(i int) {
+							defer wg.Done()
+							blobs[i], errs[i] = subject.Download(context.TODO(), uri)
+						}(i)
+					}
+					wg.Wait()
+
+					for i := range blobs {
+						h.AssertNil(t, errs[i])
+						assertBlob(t, blobs[i])
+					}
+					h.AssertEq(t, len(server.ReceivedRequests()), 2)
+					h.AssertEq(t, server.ReceivedRequests()[1].Header.Get("If-None-Match"), "A")
+				})
 			})
 
 			when("uri is invalid", func() {
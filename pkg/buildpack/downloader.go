@@ -7,6 +7,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/buildpacks/imgutil"
+	"github.com/google/go-containerregistry/pkg/name"
 
 	"github.com/buildpacks/pack/internal/layer"
 	"github.com/buildpacks/pack/internal/paths"
@@ -39,7 +40,24 @@ type Downloader interface {
 //go:generate mockgen -package testmocks -destination ../testmocks/mock_registry_resolver.go github.com/buildpacks/pack/pkg/buildpack RegistryResolver
 
 type RegistryResolver interface {
-	Resolve(registryName, bpURI string) (string, error)
+	Resolve(registryName, bpURI string, lookup RegistryLookup) (string, error)
+}
+
+// RegistryLookup configures how the buildpacks of registries are located
+type RegistryLookup struct {
+	// Offline locates buildpacks in the existing registry caches without refreshing them, for when there is no network
+	Offline bool
+
+	// ForceRefresh refreshes the registry caches even when they were refreshed within their refresh interval
+	ForceRefresh bool
+
+	// AllowYanked locates yanked versions of buildpacks, which are otherwise skipped when resolving the highest version
+	// and refused when requested explicitly
+	AllowYanked bool
+
+	// IncludePrereleases locates pre-release versions of buildpacks when resolving the highest version, which are
+	// otherwise skipped. Pre-releases requested explicitly are located either way.
+	IncludePrereleases bool
 }
 
 type buildpackDownloader struct {
@@ -80,6 +98,9 @@ type DownloadOptions struct {
 
 	// The OS/Architecture/Variant to download.
 	Target *dist.Target
+
+	// How registry buildpacks are located
+	RegistryLookup RegistryLookup
 }
 
 func (c *buildpackDownloader) Download(ctx context.Context, moduleURI string, opts DownloadOptions) (BuildModule, []BuildModule, error) {
@@ -116,15 +137,23 @@ func (c *buildpackDownloader) Download(ctx context.Context, moduleURI string, op
 		}
 	case RegistryLocator:
 		c.logger.Debugf("Downloading %s from registry: %s", kind, style.Symbol(moduleURI))
-		address, err := c.registryResolver.Resolve(opts.RegistryName, moduleURI)
+		address, err := c.registryResolver.Resolve(opts.RegistryName, moduleURI, opts.RegistryLookup)
 		if err != nil {
 			return nil, nil, errors.Wrapf(err, "locating in registry: %s", style.Symbol(moduleURI))
 		}
 
+		// the image fetched must be the image the registry resolved the module to, rather than whatever its
+		// repository serves when it's fetched
+		digest, err := name.NewDigest(address, name.WeakValidation)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "registry address %s of %s is not a digest reference", style.Symbol(address), style.Symbol(moduleURI))
+		}
+
 		mainBP, depBPs, err = extractPackaged(ctx, kind, address, c.imageFetcher, image.FetchOptions{
 			Daemon:     opts.Daemon,
 			PullPolicy: opts.PullPolicy,
 			Target:     opts.Target,
+			Digest:     digest.DigestStr(),
 		})
 		if err != nil {
 			return nil, nil, errors.Wrapf(err, "extracting from registry %s", style.Symbol(moduleURI))
@@ -103,11 +103,11 @@ func testBuildpackDownloader(t *testing.T, when spec.G, it spec.S) {
 		mockDockerClient.EXPECT().Info(context.TODO()).Return(system.Info{OSType: "linux"}, nil).AnyTimes()
 
 		mockRegistryResolver.EXPECT().
-			Resolve("some-registry", "urn:cnb:registry:example/foo@1.1.0").
+			Resolve("some-registry", "urn:cnb:registry:example/foo@1.1.0", gomock.Any()).
 			Return("example.com/some/package@sha256:74eb48882e835d8767f62940d453eb96ed2737de3a16573881dcea7dea769df7", nil).
 			AnyTimes()
 		mockRegistryResolver.EXPECT().
-			Resolve("some-registry", "example/foo@1.1.0").
+			Resolve("some-registry", "example/foo@1.1.0", gomock.Any()).
 			Return("example.com/some/package@sha256:74eb48882e835d8767f62940d453eb96ed2737de3a16573881dcea7dea769df7", nil).
 			AnyTimes()
 
@@ -137,6 +137,15 @@ func testBuildpackDownloader(t *testing.T, when spec.G, it spec.S) {
 			}).Return(packageImage, nil)
 		}
 
+		shouldFetchRegistryPackageImageWith := func(demon bool, pull image.PullPolicy, target *dist.Target) {
+			mockImageFetcher.EXPECT().Fetch(gomock.Any(), packageImage.Name(), image.FetchOptions{
+				Daemon:     demon,
+				PullPolicy: pull,
+				Target:     target,
+				Digest:     "sha256:74eb48882e835d8767f62940d453eb96ed2737de3a16573881dcea7dea769df7",
+			}).Return(packageImage, nil)
+		}
+
 		when("package image lives in cnb registry", func() {
 			it.Before(func() {
 				packageImage = createPackage("example.com/some/package@sha256:74eb48882e835d8767f62940d453eb96ed2737de3a16573881dcea7dea769df7")
@@ -151,7 +160,7 @@ func testBuildpackDownloader(t *testing.T, when spec.G, it spec.S) {
 						PullPolicy:   image.PullAlways,
 					}
 
-					shouldFetchPackageImageWith(true, image.PullAlways, &dist.Target{OS: "linux", Arch: "amd64"})
+					shouldFetchRegistryPackageImageWith(true, image.PullAlways, &dist.Target{OS: "linux", Arch: "amd64"})
 					mainBP, _, err := buildpackDownloader.Download(context.TODO(), "urn:cnb:registry:example/foo@1.1.0", downloadOptions)
 					h.AssertNil(t, err)
 					h.AssertEq(t, mainBP.Descriptor().Info().ID, "example/foo")
@@ -167,7 +176,7 @@ func testBuildpackDownloader(t *testing.T, when spec.G, it spec.S) {
 						PullPolicy:   image.PullAlways,
 					}
 
-					shouldFetchPackageImageWith(true, image.PullAlways, &dist.Target{OS: "linux"})
+					shouldFetchRegistryPackageImageWith(true, image.PullAlways, &dist.Target{OS: "linux"})
 					mainBP, _, err := buildpackDownloader.Download(context.TODO(), "example/foo@1.1.0", downloadOptions)
 					h.AssertNil(t, err)
 					h.AssertEq(t, mainBP.Descriptor().Info().ID, "example/foo")
@@ -367,7 +376,7 @@ func testBuildpackDownloader(t *testing.T, when spec.G, it spec.S) {
 			when("can't resolve buildpack in registry", func() {
 				it("errors", func() {
 					mockRegistryResolver.EXPECT().
-						Resolve("://bad-url", "urn:cnb:registry:fake").
+						Resolve("://bad-url", "urn:cnb:registry:fake", gomock.Any()).
 						Return("", errors.New("bad mhkay")).
 						AnyTimes()
 
@@ -377,10 +386,22 @@ func testBuildpackDownloader(t *testing.T, when spec.G, it spec.S) {
 				})
 			})
 
+			when("the registry address isn't a digest reference", func() {
+				it("errors", func() {
+					mockRegistryResolver.EXPECT().
+						Resolve("tagged-registry", "urn:cnb:registry:example/foo@1.1.0", gomock.Any()).
+						Return("example.com/some/package:1.1.0", nil)
+
+					downloadOptions.RegistryName = "tagged-registry"
+					_, _, err := buildpackDownloader.Download(context.TODO(), "urn:cnb:registry:example/foo@1.1.0", downloadOptions)
+					h.AssertError(t, err, "registry address 'example.com/some/package:1.1.0' of 'urn:cnb:registry:example/foo@1.1.0' is not a digest reference")
+				})
+			})
+
 			when("can't download image from registry", func() {
 				it("errors", func() {
 					packageImage := fakes.NewImage("example.com/some/package@sha256:74eb48882e835d8767f62940d453eb96ed2737de3a16573881dcea7dea769df7", "", nil)
-					mockImageFetcher.EXPECT().Fetch(gomock.Any(), packageImage.Name(), image.FetchOptions{Daemon: false, PullPolicy: image.PullAlways, Target: &dist.Target{OS: "linux"}}).Return(nil, errors.New("failed to pull"))
+					mockImageFetcher.EXPECT().Fetch(gomock.Any(), packageImage.Name(), image.FetchOptions{Daemon: false, PullPolicy: image.PullAlways, Target: &dist.Target{OS: "linux"}, Digest: "sha256:74eb48882e835d8767f62940d453eb96ed2737de3a16573881dcea7dea769df7"}).Return(nil, errors.New("failed to pull"))
 
 					downloadOptions.RegistryName = "some-registry"
 					_, _, err := buildpackDownloader.Download(context.TODO(), "urn:cnb:registry:example/foo@1.1.0", downloadOptions)
@@ -151,6 +151,22 @@ func testGetLocatorType(t *testing.T, when spec.G, it spec.S) {
 			locator:      "example/foo@1.0.0",
 			expectedType: buildpack.RegistryLocator,
 		},
+		{
+			locator:      "example/foo@^1.2",
+			expectedType: buildpack.RegistryLocator,
+		},
+		{
+			locator:      "example/foo@~2.0",
+			expectedType: buildpack.RegistryLocator,
+		},
+		{
+			locator:      "example/foo@1.x",
+			expectedType: buildpack.RegistryLocator,
+		},
+		{
+			locator:      "urn:cnb:registry:example/foo@>=1.0, <2.0",
+			expectedType: buildpack.RegistryLocator,
+		},
 		{
 			locator:      "example/registry-cnb",
 			expectedType: buildpack.RegistryLocator,
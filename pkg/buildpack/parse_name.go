@@ -33,6 +33,7 @@ func ParsePackageLocator(locator string) (imageName string) {
 }
 
 // ParseRegistryID parses a registry id (ie. `<namespace>/<name>@<version>`) into namespace, name and version components.
+// The version may also be a semver range, e.g. ^1.2 or ~2.0, which is returned as is.
 //
 // Supported formats:
 //   - <ns>/<name>[@<version>]
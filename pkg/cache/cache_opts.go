@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/pkg/errors"
 )
 
@@ -103,14 +104,18 @@ func (c *CacheOpts) Set(value string) error {
 			cache.Source = value
 		case "source":
 			cache.Source = value
+		case "type":
+		default:
+			return errors.Errorf("invalid cache field '%s', must be one of 'type', 'format', 'name' or 'source'", key)
 		}
 	}
 
-	err = sanitize(c)
-	if err != nil {
-		return err
+	// the flag is set once per type of cache, so only the cache of this type is sanitized
+	suffix := "build-cache"
+	if cache == &c.Launch {
+		suffix = "launch-cache"
 	}
-	return nil
+	return sanitize(cache, suffix)
 }
 
 func (c *CacheOpts) String() string {
@@ -132,29 +137,22 @@ func (c *CacheOpts) Type() string {
 	return "cache"
 }
 
-func sanitize(c *CacheOpts) error {
-	for _, v := range []CacheInfo{c.Build, c.Launch} {
-		// volume cache name can be auto-generated
-		if v.Format != CacheVolume && v.Source == "" {
-			return errors.Errorf("cache '%s' is required", v.SourceName())
-		}
-	}
-
-	var (
-		resolvedPath string
-		err          error
-	)
-	if c.Build.Format == CacheBind {
-		if resolvedPath, err = filepath.Abs(c.Build.Source); err != nil {
-			return errors.Wrap(err, "resolve absolute path")
+// sanitize validates the source of cache, resolving the directory of a bind cache to its subdirectory suffix
+func sanitize(cache *CacheInfo, suffix string) error {
+	switch {
+	// volume cache name can be auto-generated
+	case cache.Format != CacheVolume && cache.Source == "":
+		return errors.Errorf("cache '%s' is required", cache.SourceName())
+	case cache.Format == CacheImage:
+		if _, err := name.ParseReference(cache.Source, name.WeakValidation); err != nil {
+			return errors.Wrapf(err, "invalid cache image name '%s'", cache.Source)
 		}
-		c.Build.Source = filepath.Join(resolvedPath, "build-cache")
-	}
-	if c.Launch.Format == CacheBind {
-		if resolvedPath, err = filepath.Abs(c.Launch.Source); err != nil {
+	case cache.Format == CacheBind:
+		resolvedPath, err := filepath.Abs(cache.Source)
+		if err != nil {
 			return errors.Wrap(err, "resolve absolute path")
 		}
-		c.Launch.Source = filepath.Join(resolvedPath, "launch-cache")
+		cache.Source = filepath.Join(resolvedPath, suffix)
 	}
 	return nil
 }
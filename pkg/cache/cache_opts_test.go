@@ -3,6 +3,7 @@ package cache
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
@@ -135,6 +136,18 @@ func testCacheOpts(t *testing.T, when spec.G, it spec.S) {
 					output:     "invalid field '' must be a key=value pair",
 					shouldFail: true,
 				},
+				{
+					name:       "Unknown field",
+					input:      "type=build;format=image;image=io.test.io/myorg/my-cache:build",
+					output:     "invalid cache field 'image', must be one of 'type', 'format', 'name' or 'source'",
+					shouldFail: true,
+				},
+				{
+					name:       "Invalid image name",
+					input:      "type=build;format=image;name=io.test.io/MyOrg/my-cache:build",
+					output:     "invalid cache image name 'io.test.io/MyOrg/my-cache:build'",
+					shouldFail: true,
+				},
 			}
 
 			for _, testcase := range testcases {
@@ -286,6 +299,17 @@ func testCacheOpts(t *testing.T, when spec.G, it spec.S) {
 			}
 		})
 
+		it("resolves each cache once when the flag is set for both types", func() {
+			homeDir, err := os.UserHomeDir()
+			h.AssertNil(t, err)
+
+			var cacheFlags CacheOpts
+			h.AssertNil(t, cacheFlags.Set(fmt.Sprintf("type=build;format=bind;source=%s", filepath.Join(homeDir, "build"))))
+			h.AssertNil(t, cacheFlags.Set(fmt.Sprintf("type=launch;format=bind;source=%s", filepath.Join(homeDir, "launch"))))
+			h.AssertEq(t, cacheFlags.Build.Source, filepath.Join(homeDir, "build", "build-cache"))
+			h.AssertEq(t, cacheFlags.Launch.Source, filepath.Join(homeDir, "launch", "launch-cache"))
+		})
+
 		it("with missing options", func() {
 			successTestCases := []CacheOptTestCase{
 				{
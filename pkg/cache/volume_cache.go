@@ -127,6 +127,38 @@ func sanitizedRef(ref name.Reference) string {
 	return fmt.Sprintf("%s_%s", result, ref.Identifier())
 }
 
+// VolumeApp returns how the app image imageRef is named in the names of its cache volumes
+func VolumeApp(imageRef name.Reference) string {
+	return sanitizedRef(imageRef)
+}
+
+// ParseVolumeName returns the app image a cache volume is named after, as returned by VolumeApp, along with the
+// suffix naming the cache, such as build or launch. ok is false for volumes not named by NewVolumeCache.
+func ParseVolumeName(volume string) (app string, suffix string, ok bool) {
+	rest, ok := strings.CutPrefix(volume, "pack-cache-")
+	if !ok {
+		return "", "", false
+	}
+	dot := strings.LastIndex(rest, ".")
+	dash := strings.LastIndex(rest, "-")
+	if dot == -1 || dash == -1 || dash > dot || !isHex(rest[dash+1:dot], 12) {
+		return "", "", false
+	}
+	return rest[:dash], rest[dot+1:], true
+}
+
+func isHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return false
+		}
+	}
+	return true
+}
+
 var RunningInContainer = func() bool {
 	return proc.GetContainerRuntime(0, 0) != proc.RuntimeNotFound
 }
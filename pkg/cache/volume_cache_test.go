@@ -346,6 +346,28 @@ func testCache(t *testing.T, when spec.G, it spec.S) {
 		})
 	})
 
+	when("#ParseVolumeName", func() {
+		it("returns the app and suffix of the cache volumes of an app image", func() {
+			ref, err := name.ParseReference("my/repo:some-tag", name.WeakValidation)
+			h.AssertNil(t, err)
+			subject, err := cache.NewVolumeCache(ref, cache.CacheInfo{}, "build", dockerClient, logger)
+			h.AssertNil(t, err)
+
+			app, suffix, ok := cache.ParseVolumeName(subject.Name())
+			h.AssertTrue(t, ok)
+			h.AssertEq(t, app, cache.VolumeApp(ref))
+			h.AssertEq(t, app, "my_repo_some-tag")
+			h.AssertEq(t, suffix, "build")
+		})
+
+		it("returns false for other volumes", func() {
+			for _, volume := range []string{"my-volume", "pack-cache-my-volume", "pack-cache-my_repo_latest-nothex.build"} {
+				_, _, ok := cache.ParseVolumeName(volume)
+				h.AssertFalse(t, ok)
+			}
+		})
+	})
+
 	when("#Type", func() {
 		it("returns the cache type", func() {
 			ref, err := name.ParseReference("my/repo", name.WeakValidation)
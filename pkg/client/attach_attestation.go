@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// Media type of DSSE envelopes, the format of attestations
+const DSSEEnvelopeMediaType = "application/vnd.dsse.envelope.v1+json"
+
+// Annotation of attestations with the type of their predicate
+const PredicateTypeAnnotation = "in-toto.io/predicate-type"
+
+// AttachAttestationOptions configures AttachAttestation
+type AttachAttestationOptions struct {
+	// Envelope is the DSSE envelope of the attestation
+	Envelope []byte
+
+	// PredicateType is the type of the predicate of the attestation, e.g. https://slsa.dev/provenance/v1
+	PredicateType string
+}
+
+// AttachAttestation pushes an attestation about an image of a registry, given by digest, to the repository of the
+// image. The attestation refers to the image as its subject, so that it's listed by the referrers API of the registry.
+// It returns the digest of the attestation.
+func (c *Client) AttachAttestation(ctx context.Context, imageName string, opts AttachAttestationOptions) (string, error) {
+	ref, err := name.NewDigest(imageName, name.WeakValidation)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid image %s, a digest is required", style.Symbol(imageName))
+	}
+
+	remoteOpts := []remote.Option{remote.WithContext(ctx), remote.WithAuthFromKeychain(c.keychain)}
+	subject, err := remote.Head(ref, remoteOpts...)
+	if err != nil {
+		return "", errors.Wrapf(err, "fetching %s", style.Symbol(imageName))
+	}
+
+	layer := static.NewLayer(opts.Envelope, DSSEEnvelopeMediaType)
+	attestation, err := mutate.Append(mutate.MediaType(empty.Image, types.OCIManifestSchema1), mutate.Addendum{
+		Layer:       layer,
+		Annotations: map[string]string{PredicateTypeAnnotation: opts.PredicateType},
+	})
+	if err != nil {
+		return "", err
+	}
+	attestation = mutate.ConfigMediaType(attestation, types.OCIConfigJSON)
+	attestation = mutate.Annotations(attestation, map[string]string{PredicateTypeAnnotation: opts.PredicateType}).(v1.Image)
+	attestation = mutate.Subject(attestation, *subject).(v1.Image)
+
+	digest, err := attestation.Digest()
+	if err != nil {
+		return "", err
+	}
+	if err := remote.Write(ref.Context().Digest(digest.String()), attestation, remoteOpts...); err != nil {
+		return "", errors.Wrapf(err, "pushing attestation of %s", style.Symbol(imageName))
+	}
+	return digest.String(), nil
+}
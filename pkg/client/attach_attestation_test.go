@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestAttachAttestation(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "AttachAttestation", testAttachAttestation, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testAttachAttestation(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject  *Client
+		server   *httptest.Server
+		imageRef name.Digest
+	)
+
+	it.Before(func() {
+		server = httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+		subject = &Client{keychain: authn.DefaultKeychain}
+
+		img, err := random.Image(1024, 1)
+		h.AssertNil(t, err)
+		tag, err := name.NewTag(strings.TrimPrefix(server.URL, "http://") + "/some/app:latest")
+		h.AssertNil(t, err)
+		h.AssertNil(t, remote.Write(tag, img))
+
+		digest, err := img.Digest()
+		h.AssertNil(t, err)
+		imageRef = tag.Context().Digest(digest.String())
+	})
+
+	it.After(func() {
+		server.Close()
+	})
+
+	when("#AttachAttestation", func() {
+		it("pushes the attestation as a referrer of the image", func() {
+			digest, err := subject.AttachAttestation(context.TODO(), imageRef.Name(), AttachAttestationOptions{
+				Envelope:      []byte(`{"payloadType": "application/vnd.in-toto+json"}`),
+				PredicateType: "https://slsa.dev/provenance/v1",
+			})
+			h.AssertNil(t, err)
+
+			referrers, err := remote.Referrers(imageRef)
+			h.AssertNil(t, err)
+			manifest, err := referrers.IndexManifest()
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(manifest.Manifests), 1)
+			h.AssertEq(t, manifest.Manifests[0].Digest.String(), digest)
+
+			attestation, err := remote.Image(imageRef.Context().Digest(digest))
+			h.AssertNil(t, err)
+			attestationManifest, err := attestation.Manifest()
+			h.AssertNil(t, err)
+			h.AssertEq(t, attestationManifest.Annotations[PredicateTypeAnnotation], "https://slsa.dev/provenance/v1")
+			layers, err := attestation.Layers()
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(layers), 1)
+			mediaType, err := layers[0].MediaType()
+			h.AssertNil(t, err)
+			h.AssertEq(t, string(mediaType), DSSEEnvelopeMediaType)
+			rc, err := layers[0].Uncompressed()
+			h.AssertNil(t, err)
+			defer rc.Close()
+			contents, err := io.ReadAll(rc)
+			h.AssertNil(t, err)
+			h.AssertEq(t, string(contents), `{"payloadType": "application/vnd.in-toto+json"}`)
+		})
+
+		it("requires a digest", func() {
+			_, err := subject.AttachAttestation(context.TODO(), imageRef.Context().Tag("latest").Name(), AttachAttestationOptions{})
+			h.AssertError(t, err, "a digest is required")
+		})
+	})
+}
@@ -25,8 +25,8 @@ import (
 	"github.com/buildpacks/lifecycle/platform/files"
 	types "github.com/docker/docker/api/types/image"
 	"github.com/google/go-containerregistry/pkg/name"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
-	ignore "github.com/sabhiram/go-gitignore"
 
 	"github.com/buildpacks/pack/buildpackage"
 	"github.com/buildpacks/pack/internal/build"
@@ -43,8 +43,10 @@ import (
 	"github.com/buildpacks/pack/pkg/buildpack"
 	"github.com/buildpacks/pack/pkg/cache"
 	"github.com/buildpacks/pack/pkg/dist"
+	"github.com/buildpacks/pack/pkg/errcode"
 	"github.com/buildpacks/pack/pkg/image"
 	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/project"
 	projectTypes "github.com/buildpacks/pack/pkg/project/types"
 	v02 "github.com/buildpacks/pack/pkg/project/v02"
 )
@@ -96,6 +98,9 @@ type BuildOptions struct {
 	// add buildpacks to a build.
 	Registry string
 
+	// RegistryLookup configures how the buildpacks of the registry are located.
+	RegistryLookup buildpack.RegistryLookup
+
 	// AppPath is the path to application bits.
 	// If unset it defaults to current working directory.
 	AppPath string
@@ -105,7 +110,8 @@ type BuildOptions struct {
 	RunImage string
 
 	// Address of docker daemon exposed to build container
-	// e.g. tcp://example.com:1234, unix:///run/user/1000/podman/podman.sock
+	// e.g. tcp://example.com:1234, unix:///run/user/1000/podman/podman.sock,
+	// or PodmanDockerHost for the socket of Podman, wherever it is
 	DockerHost string
 
 	// Used to determine a run-image mirror if Run Image is empty.
@@ -137,6 +143,28 @@ type BuildOptions struct {
 	// Clear the build cache from previous builds.
 	ClearCache bool
 
+	// Clear only the volume or bind build cache, reusing the layers of the previous image.
+	ClearBuildCache bool
+
+	// Clear only the launch cache volume.
+	ClearLaunchCache bool
+
+	// IDs of the buildpacks whose entries of the volume or bind build cache are cleared, so that a single
+	// buildpack starts from a cold cache.
+	ClearBuildpackCaches []string
+
+	// Bytes the layers of the volume or bind build cache are pruned to before being restored, evicting the least
+	// recently created layers first. Zero doesn't limit the build cache.
+	CacheSizeLimit int64
+
+	// Compression of the layers of the published image, gzip by default. Images with zstd layers are published again
+	// after the lifecycle exported them, and keep their gzip layers when the registry rejects zstd ones.
+	LayerCompression LayerCompression
+
+	// Export the app image, then the build cache, rather than both at once. Layers are uploaded to a registry in
+	// parallel either way.
+	SerialExport bool
+
 	// Launch a terminal UI to depict the build process
 	Interactive bool
 
@@ -170,6 +198,9 @@ type BuildOptions struct {
 	// Strategy for updating local images before a build.
 	PullPolicy image.PullPolicy
 
+	// ImagePullPolicies override PullPolicy for the builder, run image or buildpacks, when set.
+	ImagePullPolicies ImagePullPolicies
+
 	// ProjectDescriptorBaseDir is the base directory to find relative resources referenced by the ProjectDescriptor
 	ProjectDescriptorBaseDir string
 
@@ -214,6 +245,12 @@ type BuildOptions struct {
 	// trusted
 	TrustExtraBuildpacks bool
 
+	// UseCreator, when set, runs all lifecycle phases in a single container with the creator (true), or each phase
+	// in its own container (false), regardless of whether the builder and buildpacks are trusted. The creator places
+	// registry credentials and the daemon socket on the builder's build image, so forcing it on an untrusted builder
+	// has the same risks as trusting the builder. When nil, the creator is used for trusted builders only.
+	UseCreator *bool
+
 	// Directory to output any SBOM artifacts
 	SBOMDestinationDir string
 
@@ -225,6 +262,65 @@ type BuildOptions struct {
 
 	// Configuration to export to OCI layout format
 	LayoutConfig *LayoutConfig
+
+	// Daemonless runs the creator of the builder in a sandbox on the host, in user and mount namespaces, rather than
+	// in containers of the daemon, so that no daemon is needed. Only supported on Linux, for published images built
+	// with the buildpacks of a trusted builder.
+	Daemonless bool
+
+	// DetectOnly only runs the detect phase, logging the buildpacks that passed detection and the build plan they
+	// resolved, without building the image. The detector logs at debug level unless LifecycleLogLevel is set, so that
+	// it logs why buildpacks failed detection.
+	DetectOnly bool
+
+	// Driver runs the lifecycle, DriverDocker when empty. With DriverKubernetes, the creator of the builder runs as a
+	// Job of a Kubernetes cluster, for published images built with the buildpacks of a trusted builder.
+	Driver string
+
+	// Kubernetes configures the cluster of builds with DriverKubernetes
+	Kubernetes KubernetesOptions
+
+	// Log level passed to every lifecycle phase, one of debug, info, warn or error.
+	// When empty, the lifecycle logs at debug level if the logger is verbose.
+	LifecycleLogLevel string
+
+	// Additional platform environment variables (e.g. CNB_EXPERIMENTAL_MODE) set
+	// on every lifecycle phase container. Intended for troubleshooting.
+	LifecycleEnv map[string]string
+
+	// Times the upload of a blob is retried when publishing the image, e.g. on unreliable links. When set, the image
+	// is exported to an OCI layout, which is then pushed to the registry by pack rather than by the exporter, and a
+	// failed push reports the layers that reached the registry, which aren't uploaded again. Doesn't apply to
+	// daemonless and kubernetes builds, which always publish from the exporter.
+	PushRetries int
+
+	// Filled with the key facts about the image once it was built successfully, if set.
+	Summary *BuildSummary
+
+	// CheckPlan is called with the resolved plan of the build before the lifecycle runs, if set.
+	// The build stops when it returns an error.
+	CheckPlan func(BuildPlan) error
+
+	// RecordSourceDigest records the source digest of the build, a digest of the app source and of the build
+	// configuration, in the io.buildpacks.project.metadata label of the image.
+	RecordSourceDigest bool
+
+	// SkipUnchanged skips the build when the previous image, or the image itself when no previous image is set,
+	// has the source digest of the build, tagging it with the image name and the additional tags of the build.
+	// Builds exporting an SBOM or a report, clearing the cache, exporting to OCI layout, only detecting or setting a
+	// creation time are never skipped.
+	SkipUnchanged bool
+
+	// Labels set on the config of the image, besides the ones set by the lifecycle
+	Labels map[string]string
+
+	// Annotations set on the manifest of the image. They're only kept by registries, so they're ignored
+	// unless the image is published.
+	Annotations map[string]string
+
+	// Logger used for this build instead of the client's logger, if set. Allows running several builds
+	// at once while keeping their output apart.
+	Logger logging.Logger
 }
 
 func (b *BuildOptions) Layout() bool {
@@ -234,6 +330,45 @@ func (b *BuildOptions) Layout() bool {
 	return false
 }
 
+// ImagePullPolicies are the pull policies of the images of a build that update at their own pace, such as a run
+// image always pulled for its security fixes and a builder pinned locally.
+type ImagePullPolicies struct {
+	// Builder is the pull policy of the builder and lifecycle images
+	Builder *image.PullPolicy
+
+	// RunImage is the pull policy of the run image
+	RunImage *image.PullPolicy
+
+	// Buildpacks is the pull policy of the buildpack and extension images
+	Buildpacks *image.PullPolicy
+}
+
+func (b *BuildOptions) builderPullPolicy() image.PullPolicy {
+	return pullPolicyOr(b.ImagePullPolicies.Builder, b.PullPolicy)
+}
+
+func (b *BuildOptions) runImagePullPolicy() image.PullPolicy {
+	return pullPolicyOr(b.ImagePullPolicies.RunImage, b.PullPolicy)
+}
+
+func (b *BuildOptions) buildpacksPullPolicy() image.PullPolicy {
+	return pullPolicyOr(b.ImagePullPolicies.Buildpacks, b.PullPolicy)
+}
+
+// pullsAlways is whether every image of the build is pulled
+func (b *BuildOptions) pullsAlways() bool {
+	return b.builderPullPolicy() == image.PullAlways &&
+		b.runImagePullPolicy() == image.PullAlways &&
+		b.buildpacksPullPolicy() == image.PullAlways
+}
+
+func pullPolicyOr(policy *image.PullPolicy, defaultPolicy image.PullPolicy) image.PullPolicy {
+	if policy != nil {
+		return *policy
+	}
+	return defaultPolicy
+}
+
 // ProxyConfig specifies proxy setting to be set as environment variables in a container.
 type ProxyConfig struct {
 	HTTPProxy  string // Used to set HTTP_PROXY env var.
@@ -245,11 +380,21 @@ type ProxyConfig struct {
 // occur within.
 type ContainerConfig struct {
 	// Configure network settings of the build containers.
-	// The value of Network is handed directly to the docker client.
+	// The value of Network is handed directly to the docker client, so it may be the name of a user-defined
+	// network, such as the network of a docker compose project.
 	// For valid values of this field see:
 	// https://docs.docker.com/network/#network-drivers
 	Network string
 
+	// ExtraHosts are added to /etc/hosts of the build containers, in the form host:ip, where ip may be
+	// host-gateway for the IP of the docker host, e.g. host.docker.internal:host-gateway.
+	ExtraHosts []string
+
+	// SSHAgent is the socket of the SSH agent forwarded to the containers running buildpacks, which find it
+	// with SSH_AUTH_SOCK, or the named pipe of the agent on Windows. The SSH agent isn't forwarded when empty.
+	// Docker Desktop forwards the agent of its host itself, so on macOS and Windows only whether it's set matters.
+	SSHAgent string
+
 	// Volumes are accessible during both detect build phases
 	// should have the form: /path/in/host:/path/in/container.
 	// For more about volume mounts, and their permissions see:
@@ -261,6 +406,32 @@ type ContainerConfig struct {
 	// - /layers
 	// - anything below /cnb/**
 	Volumes []string
+
+	// Memory limit in bytes of the containers running buildpacks, unlimited when 0.
+	Memory int64
+
+	// Number of CPUs available to the containers running buildpacks, e.g. 1.5, unlimited when 0.
+	CPUs float64
+
+	// Maximum number of processes in the containers running buildpacks, unlimited when 0.
+	PidsLimit int64
+
+	// Run the build containers with a read-only root filesystem, with a tmpfs mounted at /tmp.
+	ReadOnly bool
+
+	// Capabilities added to and dropped from the build containers, e.g. NET_ADMIN.
+	// NET_RAW is dropped unless added back.
+	CapAdd  []string
+	CapDrop []string
+
+	// Security options of the build containers, e.g. apparmor=some-profile, passed as is to the daemon, so
+	// seccomp profiles are given as their JSON. They override the defaults with the same key, such as
+	// no-new-privileges=true.
+	SecurityOpt []string
+
+	// Whether the daemon remaps user namespaces (userns-remap), in which case the ownership of the app and
+	// layers volumes is restored after copying the app. Detected from the daemon when nil.
+	UsernsRemap *bool
 }
 
 type LayoutConfig struct {
@@ -275,6 +446,10 @@ type LayoutConfig struct {
 
 	// Configure the OCI layout fetch mode to avoid saving layers on disk
 	Sparse bool
+
+	// Path of a tarball the OCI layout of the application image is written to once it's exported, e.g. to load it
+	// with `skopeo copy oci-archive:<path>`. Optional.
+	ArchivePath string
 }
 
 func (l *LayoutConfig) Enable() bool {
@@ -290,14 +465,58 @@ type layoutPathConfig struct {
 	targetRunImagePath      string
 }
 
+// projectMetadata is the metadata of the project of the build the lifecycle records in the image, with the source
+// digest of the build if any
+func (c *Client) projectMetadata(opts BuildOptions, digest string) files.ProjectMetadata {
+	projectMetadata := files.ProjectMetadata{}
+	if c.experimental {
+		version := opts.ProjectDescriptor.Project.Version
+		sourceURL := opts.ProjectDescriptor.Project.SourceURL
+		if version != "" || sourceURL != "" {
+			projectMetadata.Source = &files.ProjectSource{
+				Type:     "project",
+				Version:  map[string]interface{}{"declared": version},
+				Metadata: map[string]interface{}{"url": sourceURL},
+			}
+		} else {
+			projectMetadata.Source = v02.GitMetadata(opts.AppPath)
+		}
+	}
+
+	if digest != "" {
+		projectMetadata = withSourceDigest(projectMetadata, digest)
+	}
+	return projectMetadata
+}
+
 // Build configures settings for the build container(s) and lifecycle.
 // It then invokes the lifecycle to build an app image.
 // If any configuration is deemed invalid, or if any lifecycle phases fail,
 // an error will be returned and no image produced.
 func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 	var pathsConfig layoutPathConfig
+	started := time.Now()
+
+	if opts.Logger != nil {
+		withLogger := *c
+		withLogger.logger = opts.Logger
+		c = &withLogger
+	}
 
-	if RunningInContainer() && !(opts.PullPolicy == image.PullAlways) {
+	switch {
+	case opts.Driver != "" && opts.Driver != DriverDocker && opts.Driver != DriverKubernetes:
+		return errors.Errorf("unknown driver %s, must be %s or %s", style.Symbol(opts.Driver), style.Symbol(DriverDocker), style.Symbol(DriverKubernetes))
+	case opts.UseCreator != nil && !*opts.UseCreator && (opts.Daemonless || opts.Driver == DriverKubernetes):
+		return errors.New("daemonless and kubernetes builds always run the creator, so can't run the phases in separate containers")
+	case opts.Driver == DriverKubernetes && opts.Daemonless:
+		return errors.New("daemonless builds don't run in a cluster, so can't use the kubernetes driver")
+	case opts.Driver == DriverKubernetes:
+		return c.buildKubernetes(ctx, opts)
+	case opts.Daemonless:
+		return c.buildDaemonless(ctx, opts)
+	}
+
+	if RunningInContainer() && !opts.pullsAlways() {
 		c.logger.Warnf("Detected pack is running in a container; if using a shared docker host, failing to pull build inputs from a remote registry is insecure - " +
 			"other tenants may have compromised build inputs stored in the daemon." +
 			"This configuration is insecure and may become unsupported in the future." +
@@ -311,6 +530,25 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 	imgRegistry := imageRef.Context().RegistryStr()
 	imageName := imageRef.Name()
 
+	if err := validateImageMetadata(opts); err != nil {
+		return err
+	}
+
+	if err := validateClearCache(opts); err != nil {
+		return err
+	}
+
+	if err := validateLayerCompression(opts); err != nil {
+		return err
+	}
+
+	daemonHost, remoteDaemon := sshDaemonHost()
+	if remoteDaemon {
+		if err := validateRemoteDaemonBuild(c.logger, opts, daemonHost); err != nil {
+			return err
+		}
+	}
+
 	if opts.Layout() {
 		pathsConfig, err = c.processLayoutPath(opts.LayoutConfig.InputImage, opts.LayoutConfig.PreviousInputImage)
 		if err != nil {
@@ -322,6 +560,33 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 		}
 	}
 
+	// with push retries, the image is exported to an OCI layout in a temporary directory, which is then pushed showing
+	// the progress of its layers. Otherwise the exporter pushes the image, without reporting its progress.
+	pushLayout := opts.Publish && opts.PushRetries > 0
+	if opts.Publish && !pushLayout {
+		c.logger.Debug("The lifecycle pushes the image without reporting its progress, set the push retries for pack to push it showing the progress of its layers")
+	}
+	layoutRepoDir := ""
+	if opts.Layout() {
+		layoutRepoDir = opts.LayoutConfig.LayoutRepoDir
+	}
+	if pushLayout {
+		if opts.PreviousImage != "" {
+			return errors.New("push retries can't be used with a previous image, whose layers are only reused by the exporter when it publishes the image")
+		}
+		pushDir, err := os.MkdirTemp("", "pack.push.")
+		if err != nil {
+			return errors.Wrap(err, "creating directory of the OCI layout to push")
+		}
+		defer os.RemoveAll(pushDir)
+
+		pathsConfig, err = c.processLayoutPath(NewLayoutInputImageReference(filepath.Join(pushDir, "image"), imageName), nil)
+		if err != nil {
+			return err
+		}
+		layoutRepoDir = filepath.Join(pushDir, "repo")
+	}
+
 	appPath, err := c.processAppPath(opts.AppPath)
 	if err != nil {
 		return errors.Wrapf(err, "invalid app path '%s'", opts.AppPath)
@@ -334,22 +599,7 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 		return errors.Wrapf(err, "invalid builder '%s'", opts.Builder)
 	}
 
-	requestedTarget := func() *dist.Target {
-		if opts.Platform == "" {
-			return nil
-		}
-		parts := strings.Split(opts.Platform, "/")
-		switch len(parts) {
-		case 0:
-			return nil
-		case 1:
-			return &dist.Target{OS: parts[0]}
-		case 2:
-			return &dist.Target{OS: parts[0], Arch: parts[1]}
-		default:
-			return &dist.Target{OS: parts[0], Arch: parts[1], ArchVariant: parts[2]}
-		}
-	}()
+	requestedTarget := platformTarget(opts.Platform)
 
 	rawBuilderImage, err := c.imageFetcher.Fetch(
 		ctx,
@@ -357,10 +607,10 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 		image.FetchOptions{
 			Daemon:     true,
 			Target:     requestedTarget,
-			PullPolicy: opts.PullPolicy},
+			PullPolicy: opts.builderPullPolicy()},
 	)
 	if err != nil {
-		return errors.Wrapf(err, "failed to fetch builder image '%s'", builderRef.Name())
+		return errcode.Wrap(errcode.BuilderFetchFailed, errors.Wrapf(err, "failed to fetch builder image '%s'", builderRef.Name()))
 	}
 
 	var targetToUse *dist.Target
@@ -380,21 +630,22 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 
 	fetchOptions := image.FetchOptions{
 		Daemon:     !opts.Publish,
-		PullPolicy: opts.PullPolicy,
+		PullPolicy: opts.runImagePullPolicy(),
 		Target:     targetToUse,
 	}
 	runImageName := c.resolveRunImage(opts.RunImage, imgRegistry, builderRef.Context().RegistryStr(), bldr.DefaultRunImage(), opts.AdditionalMirrors, opts.Publish, fetchOptions)
 
-	if opts.Layout() {
+	if opts.Layout() || pushLayout {
 		targetRunImagePath, err := layout.ParseRefToPath(runImageName)
 		if err != nil {
 			return err
 		}
-		hostRunImagePath := filepath.Join(opts.LayoutConfig.LayoutRepoDir, targetRunImagePath)
+		hostRunImagePath := filepath.Join(layoutRepoDir, targetRunImagePath)
 		targetRunImagePath = filepath.Join(paths.RootDir, "layout-repo", targetRunImagePath)
 		fetchOptions.LayoutOption = image.LayoutOption{
-			Path:   hostRunImagePath,
-			Sparse: opts.LayoutConfig.Sparse,
+			Path: hostRunImagePath,
+			// the layers of the run image are pushed from the layout
+			Sparse: opts.Layout() && opts.LayoutConfig.Sparse,
 		}
 		fetchOptions.Daemon = false
 		pathsConfig.targetRunImagePath = targetRunImagePath
@@ -420,6 +671,31 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 		return err
 	}
 
+	if opts.CheckPlan != nil {
+		plan := BuildPlan{
+			Builder:    opts.Builder,
+			RunImage:   runImageName,
+			Image:      imageName,
+			Publish:    opts.Publish,
+			Buildpacks: buildPlanBuildpacks(bldr.Buildpacks(), fetchedBPs),
+		}
+		if err := opts.CheckPlan(plan); err != nil {
+			return err
+		}
+	}
+
+	var digest string
+	if opts.RecordSourceDigest || opts.SkipUnchanged {
+		if digest, err = c.buildSourceDigest(appPath, opts, rawBuilderImage, runImage, runImageName, append(fetchedBPs[:len(fetchedBPs):len(fetchedBPs)], fetchedExs...)); err != nil {
+			return errors.Wrap(err, "computing source digest")
+		}
+		c.logger.Debugf("Source digest of the build is %s", digest)
+
+		if skipped, err := c.skipUnchangedBuild(ctx, opts, imageName, digest, started); err != nil || skipped {
+			return err
+		}
+	}
+
 	// Default mode: if the TrustBuilder option is not set, trust the known trusted builders.
 	if opts.TrustBuilder == nil {
 		opts.TrustBuilder = builder.IsKnownTrustedBuilder
@@ -432,7 +708,7 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 	if !supportsPlatformAPI(builderPlatformAPIs) {
 		c.logger.Debugf("pack %s supports Platform API(s): %s", c.version, strings.Join(build.SupportedPlatformAPIVersions.AsStrings(), ", "))
 		c.logger.Debugf("Builder %s supports Platform API(s): %s", style.Symbol(opts.Builder), strings.Join(builderPlatformAPIs.AsStrings(), ", "))
-		return errors.Errorf("Builder %s is incompatible with this version of pack", style.Symbol(opts.Builder))
+		return errcode.Errorf(errcode.BuilderIncompatible, "Builder %s is incompatible with this version of pack", style.Symbol(opts.Builder))
 	}
 
 	// Get the platform API version to use
@@ -444,11 +720,16 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 	hasExtensions := func() bool {
 		return len(fetchedExs) != 0
 	}()
-	if hasExtensions {
+	switch {
+	case opts.UseCreator != nil:
+		useCreator, err = c.forceCreator(*opts.UseCreator, opts, lifecycleVersion, hasExtensions)
+		if err != nil {
+			return err
+		}
+	case hasExtensions:
 		c.logger.Warnf("Builder is trusted but additional modules were added; using the untrusted (5 phases) build flow")
 		useCreator = false
-	}
-	if hasAdditionalBuildpacks && !opts.TrustExtraBuildpacks {
+	case hasAdditionalBuildpacks && !opts.TrustExtraBuildpacks:
 		c.logger.Warnf("Builder is trusted but additional modules were added; using the untrusted (5 phases) build flow")
 		useCreator = false
 	}
@@ -469,7 +750,7 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 				lifecycleImageName,
 				image.FetchOptions{
 					Daemon:     true,
-					PullPolicy: opts.PullPolicy,
+					PullPolicy: opts.builderPullPolicy(),
 					Target:     targetToUse,
 				},
 			)
@@ -520,6 +801,12 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 	if err != nil {
 		return fmt.Errorf("finding latest supported Platform API: %w", err)
 	}
+	if pushLayout && usingPlatformAPI.LessThan("0.12") {
+		return errors.Errorf("push retries require Platform API 0.12 or later to export the image to an OCI layout, the builder supports %s", usingPlatformAPI)
+	}
+	if opts.CreationTime != nil && usingPlatformAPI.LessThan("0.9") {
+		c.logger.Warnf("The creation time of the image can't be set with Platform API %s, it requires Platform API 0.9 or later", usingPlatformAPI)
+	}
 	if usingPlatformAPI.LessThan("0.12") {
 		if err = c.validateMixins(fetchedBPs, bldr, runImageName, runMixins); err != nil {
 			return fmt.Errorf("validating stack mixins: %w", err)
@@ -535,6 +822,16 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 		buildEnvs[k] = v
 	}
 
+	sshAgentSocket, closeSSHAgent, err := c.sshAgentSocket(opts, remoteDaemon, targetToUse.OS)
+	if err != nil {
+		return err
+	}
+	defer closeSSHAgent()
+	if sshAgentSocket != "" {
+		// the lifecycle only gives buildpacks the platform env, not the env of its container
+		buildEnvs[sshAuthSockEnv] = build.SSHAgentSocketPath
+	}
+
 	origBuilderName := rawBuilderImage.Name()
 	ephemeralBuilder, err := c.createEphemeralBuilder(
 		rawBuilderImage,
@@ -560,12 +857,12 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 		if targetToUse.OS == "windows" {
 			return fmt.Errorf("builder contains image extensions which are not supported for Windows builds")
 		}
-		if !(opts.PullPolicy == image.PullAlways) {
-			return fmt.Errorf("pull policy must be 'always' when builder contains image extensions")
+		if opts.runImagePullPolicy() != image.PullAlways {
+			return fmt.Errorf("pull policy of the run image must be 'always' when builder contains image extensions")
 		}
 	}
 
-	if opts.Layout() {
+	if opts.Layout() || pushLayout {
 		opts.ContainerConfig.Volumes = appendLayoutVolumes(opts.ContainerConfig.Volumes, pathsConfig)
 	}
 
@@ -578,31 +875,38 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 		c.logger.Warn(warning)
 	}
 
-	fileFilter, err := getFileFilter(opts.ProjectDescriptor)
+	if targetToUse.OS == "windows" && len(opts.ClearBuildpackCaches) > 0 {
+		return errors.New("the cache of buildpacks can't be cleared for Windows containers")
+	}
+
+	if targetToUse.OS == "windows" && opts.CacheSizeLimit > 0 {
+		c.logger.Warn("Ignoring the size limit of the build cache, which isn't enforced for Windows containers")
+		opts.CacheSizeLimit = 0
+	}
+
+	if targetToUse.OS == "windows" && (opts.ContainerConfig.ReadOnly || len(opts.ContainerConfig.CapAdd) > 0 || len(opts.ContainerConfig.CapDrop) > 0 || len(opts.ContainerConfig.SecurityOpt) > 0) {
+		c.logger.Warn("Ignoring the read-only, capabilities and security options of the build containers, which Windows containers don't support")
+	}
+
+	usernsRemap := targetToUse.OS != "windows" && c.usernsRemapped(ctx, opts)
+
+	dockerHost, err := c.resolveDockerHost(ctx, opts.DockerHost)
 	if err != nil {
 		return err
 	}
 
-	runImageName, err = pname.TranslateRegistry(runImageName, c.registryMirrors, c.logger)
+	fileFilter, err := project.FileFilter(opts.ProjectDescriptor.Build)
 	if err != nil {
 		return err
 	}
 
-	projectMetadata := files.ProjectMetadata{}
-	if c.experimental {
-		version := opts.ProjectDescriptor.Project.Version
-		sourceURL := opts.ProjectDescriptor.Project.SourceURL
-		if version != "" || sourceURL != "" {
-			projectMetadata.Source = &files.ProjectSource{
-				Type:     "project",
-				Version:  map[string]interface{}{"declared": version},
-				Metadata: map[string]interface{}{"url": sourceURL},
-			}
-		} else {
-			projectMetadata.Source = v02.GitMetadata(opts.AppPath)
-		}
+	runImageName, err = pname.TranslateRegistry(runImageName, c.registryMirrors, c.logger)
+	if err != nil {
+		return err
 	}
 
+	projectMetadata := c.projectMetadata(opts, digest)
+
 	lifecycleOpts := build.LifecycleOptions{
 		AppPath:                  appPath,
 		Image:                    imageRef,
@@ -612,17 +916,23 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 		RunImage:                 runImageName,
 		ProjectMetadata:          projectMetadata,
 		ClearCache:               opts.ClearCache,
-		Publish:                  opts.Publish,
+		ClearBuildCache:          opts.ClearBuildCache,
+		ClearLaunchCache:         opts.ClearLaunchCache,
+		ClearBuildpackCaches:     opts.ClearBuildpackCaches,
+		CacheSizeLimit:           opts.CacheSizeLimit,
+		ParallelExport:           !opts.SerialExport,
+		Publish:                  opts.Publish && !pushLayout,
 		TrustBuilder:             opts.TrustBuilder(opts.Builder),
 		UseCreator:               useCreator,
 		UseCreatorWithExtensions: supportsCreatorWithExtensions(lifecycleVersion),
-		DockerHost:               opts.DockerHost,
+		DockerHost:               dockerHost,
 		Cache:                    opts.Cache,
 		CacheImage:               opts.CacheImage,
 		HTTPProxy:                proxyConfig.HTTPProxy,
 		HTTPSProxy:               proxyConfig.HTTPSProxy,
 		NoProxy:                  proxyConfig.NoProxy,
 		Network:                  opts.ContainerConfig.Network,
+		ExtraHosts:               opts.ContainerConfig.ExtraHosts,
 		AdditionalTags:           opts.AdditionalTags,
 		Volumes:                  processedVolumes,
 		DefaultProcessType:       opts.DefaultProcessType,
@@ -636,8 +946,37 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 		ReportDestinationDir:     opts.ReportDestinationDir,
 		SBOMDestinationDir:       opts.SBOMDestinationDir,
 		CreationTime:             opts.CreationTime,
-		Layout:                   opts.Layout(),
+		Layout:                   opts.Layout() || pushLayout,
 		Keychain:                 c.keychain,
+		LogLevel:                 opts.LifecycleLogLevel,
+		LifecycleEnv:             lifecycleEnv(opts.LifecycleEnv),
+		Memory:                   opts.ContainerConfig.Memory,
+		NanoCPUs:                 int64(opts.ContainerConfig.CPUs * 1e9),
+		PidsLimit:                opts.ContainerConfig.PidsLimit,
+		ReadOnly:                 opts.ContainerConfig.ReadOnly,
+		CapAdd:                   opts.ContainerConfig.CapAdd,
+		CapDrop:                  opts.ContainerConfig.CapDrop,
+		SecurityOpt:              opts.ContainerConfig.SecurityOpt,
+		UsernsRemap:              usernsRemap,
+		Logger:                   opts.Logger,
+		Platform:                 lifecyclePlatform(requestedTarget),
+		GzipApp:                  remoteDaemon,
+		SSHAgentSocket:           sshAgentSocket,
+	}
+
+	var (
+		cache  *cacheTracker
+		phases *phaseTracker
+	)
+	if pushLayout {
+		// the tags are pushed along with the image
+		lifecycleOpts.AdditionalTags = nil
+	}
+	if opts.Summary != nil {
+		cache = newCacheTracker()
+		phases = newPhaseTracker()
+		lifecycleOpts.OutputObserver = io.MultiWriter(cache, phases)
+		lifecycleOpts.PhaseObserver = phases.observe
 	}
 
 	switch {
@@ -647,7 +986,7 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 		lifecycleOpts.LifecycleImage = lifecycleOptsLifecycleImage
 		lifecycleOpts.LifecycleApis = lifecycleAPIs
 	case !opts.TrustBuilder(opts.Builder):
-		return errors.Errorf("Lifecycle %s does not have an associated lifecycle image. Builder must be trusted.", lifecycleVersion.String())
+		return errcode.Errorf(errcode.BuilderNotTrusted, "Lifecycle %s does not have an associated lifecycle image. Builder must be trusted.", lifecycleVersion.String())
 	}
 
 	lifecycleOpts.FetchRunImageWithLifecycleLayer = func(runImageName string) (string, error) {
@@ -797,12 +1136,84 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 		return ephemeralRunImageName, nil
 	}
 
+	if opts.DetectOnly {
+		detectDir, err := os.MkdirTemp("", "pack.detect")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(detectDir)
+
+		lifecycleOpts.DetectOnly = true
+		lifecycleOpts.DetectDestinationDir = detectDir
+		if lifecycleOpts.LogLevel == "" {
+			lifecycleOpts.LogLevel = "debug"
+		}
+		if err = c.lifecycleExecutor.Execute(ctx, lifecycleOpts); err != nil {
+			return fmt.Errorf("executing lifecycle: %w", err)
+		}
+		return c.logDetectResult(detectDir)
+	}
+
 	if err = c.lifecycleExecutor.Execute(ctx, lifecycleOpts); err != nil {
 		return fmt.Errorf("executing lifecycle: %w", err)
 	}
+
+	if pushLayout {
+		if err := c.pushLayoutImage(ctx, pathsConfig.hostImagePath, imageRef, opts.AdditionalTags, pushRetryBackoff(opts.PushRetries)); err != nil {
+			return err
+		}
+	}
+
+	if opts.ReportDestinationDir != "" {
+		if err := recordResourceLimits(filepath.Join(opts.ReportDestinationDir, "report.toml"), opts.ContainerConfig); err != nil {
+			return errors.Wrap(err, "recording resource limits in build report")
+		}
+	}
+
+	finished, err := c.finishImage(ctx, imageName, opts)
+	if err != nil {
+		return err
+	}
+	if finished != "" && opts.ReportDestinationDir != "" {
+		if err := recordFinishedImage(filepath.Join(opts.ReportDestinationDir, "report.toml"), finished, opts.Publish); err != nil {
+			return errors.Wrap(err, "recording the image in build report")
+		}
+	}
+
+	if opts.Layout() && opts.LayoutConfig.ArchivePath != "" {
+		if err := writeLayoutArchive(pathsConfig.hostImagePath, opts.LayoutConfig.ArchivePath); err != nil {
+			return errors.Wrapf(err, "writing OCI layout archive %s", style.Symbol(opts.LayoutConfig.ArchivePath))
+		}
+	}
+
+	if opts.Summary != nil {
+		opts.Summary.Builder = builderRef.Name()
+		opts.Summary.BuilderDigest = c.imageDigest(ctx, rawBuilderImage)
+		opts.Summary.RunImage = runImageName
+		opts.Summary.RunImageDigest = c.imageDigest(ctx, runImage)
+		opts.Summary.LifecycleVersion = lifecycleVersion.String()
+		opts.Summary.SourceDigest = digest
+
+		var layers dist.ModuleLayers
+		if _, err := dist.GetLabel(ephemeralBuilder.Image(), dist.BuildpackLayersLabel, &layers); err != nil {
+			c.logger.Debugf("Unable to read the buildpack layers of the builder: %s", err)
+		}
+		if err := c.summarize(ctx, opts.Summary, opts.Publish, imageRef, cache, phases, layers, started); err != nil {
+			c.logger.Debugf("Unable to summarize build: %s", err)
+		}
+	}
 	return c.logImageNameAndSha(ctx, opts.Publish, imageRef)
 }
 
+// lifecyclePlatform is the platform the lifecycle containers are created for when building for a requested target, so
+// that the daemon runs them for that target rather than its own platform, e.g. under emulation for linux/arm64 on amd64
+func lifecyclePlatform(target *dist.Target) *specs.Platform {
+	if target == nil {
+		return nil
+	}
+	return &specs.Platform{OS: target.OS, Architecture: target.Arch, Variant: target.ArchVariant}
+}
+
 func getTargetFromBuilder(builderImage imgutil.Image) (*dist.Target, error) {
 	builderOS, err := builderImage.OS()
 	if err != nil {
@@ -845,19 +1256,20 @@ func extractSupportedLifecycleApis(labels map[string]string) ([]string, error) {
 	return []string{}, nil
 }
 
-func getFileFilter(descriptor projectTypes.Descriptor) (func(string) bool, error) {
-	if len(descriptor.Build.Exclude) > 0 {
-		excludes := ignore.CompileIgnoreLines(descriptor.Build.Exclude...)
-		return func(fileName string) bool {
-			return !excludes.MatchesPath(fileName)
-		}, nil
+// platformTarget is the target of a platform such as linux/arm64/v8, nil when platform is empty
+func platformTarget(platform string) *dist.Target {
+	if platform == "" {
+		return nil
 	}
-	if len(descriptor.Build.Include) > 0 {
-		includes := ignore.CompileIgnoreLines(descriptor.Build.Include...)
-		return includes.MatchesPath, nil
+	parts := strings.Split(platform, "/")
+	switch len(parts) {
+	case 1:
+		return &dist.Target{OS: parts[0]}
+	case 2:
+		return &dist.Target{OS: parts[0], Arch: parts[1]}
+	default:
+		return &dist.Target{OS: parts[0], Arch: parts[1], ArchVariant: parts[2]}
 	}
-
-	return nil, nil
 }
 
 func supportsCreator(lifecycleVersion *builder.Version) bool {
@@ -866,10 +1278,40 @@ func supportsCreator(lifecycleVersion *builder.Version) bool {
 	return !lifecycleVersion.LessThan(semver.MustParse(minLifecycleVersionSupportingCreator))
 }
 
+// forceCreator validates that the creator can be used, or not, as set with BuildOptions.UseCreator, overriding whether
+// the builder and buildpacks are trusted.
+func (c *Client) forceCreator(useCreator bool, opts BuildOptions, lifecycleVersion *builder.Version, hasExtensions bool) (bool, error) {
+	if !useCreator {
+		c.logger.Debug("Running each lifecycle phase in a separate container, as the creator is disabled")
+		return false, nil
+	}
+
+	if !supportsCreator(lifecycleVersion) {
+		return false, errors.Errorf("lifecycle %s of builder %s doesn't support the creator, it must be at least %s", style.Symbol(lifecycleVersion.String()), style.Symbol(opts.Builder), style.Symbol(minLifecycleVersionSupportingCreator))
+	}
+	if hasExtensions && !supportsCreatorWithExtensions(lifecycleVersion) {
+		return false, errors.Errorf("lifecycle %s of builder %s doesn't support the creator with extensions, it must be at least %s", style.Symbol(lifecycleVersion.String()), style.Symbol(opts.Builder), style.Symbol(minLifecycleVersionSupportingCreatorWithExtensions))
+	}
+	if !opts.TrustBuilder(opts.Builder) {
+		c.logger.Warnf("Running the creator with untrusted builder %s, which gets access to registry credentials and the daemon", style.Symbol(opts.Builder))
+	}
+	return true, nil
+}
+
 func supportsCreatorWithExtensions(lifecycleVersion *builder.Version) bool {
 	return !lifecycleVersion.LessThan(semver.MustParse(minLifecycleVersionSupportingCreatorWithExtensions))
 }
 
+// lifecycleEnv converts env into KEY=VALUE pairs, sorted so the phase containers are configured deterministically.
+func lifecycleEnv(env map[string]string) []string {
+	var pairs []string
+	for k, v := range env {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return pairs
+}
+
 func supportsLifecycleImage(lifecycleVersion *builder.Version) bool {
 	return lifecycleVersion.Equal(builder.VersionMustParse(prevLifecycleVersionSupportingImage)) ||
 		!lifecycleVersion.LessThan(semver.MustParse(minLifecycleVersionSupportingImage))
@@ -1178,13 +1620,20 @@ func (c *Client) processProxyConfig(config *ProxyConfig) ProxyConfig {
 func (c *Client) processBuildpacks(ctx context.Context, builderBPs []dist.ModuleInfo, builderOrder dist.Order, stackID string, opts BuildOptions, targetToUse *dist.Target) (fetchedBPs []buildpack.BuildModule, nInlineBPs int, order dist.Order, err error) {
 	relativeBaseDir := opts.RelativeBaseDir
 	declaredBPs := opts.Buildpacks
+	targetOS := "linux"
+	if targetToUse != nil && targetToUse.OS != "" {
+		targetOS = targetToUse.OS
+	}
+	// buildpacks from --buildpack are never optional
+	optionalBPs := make([]bool, len(declaredBPs))
 
 	// Buildpacks from --buildpack override buildpacks from project descriptor
 	if len(declaredBPs) == 0 && len(opts.ProjectDescriptor.Build.Buildpacks) != 0 {
 		relativeBaseDir = opts.ProjectDescriptorBaseDir
+		c.logger.Debug("Using the buildpack group declared in the project descriptor instead of the builder's order")
 
 		for _, bp := range opts.ProjectDescriptor.Build.Buildpacks {
-			buildpackLocator, isInline, err := getBuildpackLocator(bp, stackID)
+			buildpackLocator, isInline, err := getBuildpackLocator(bp, stackID, opts.ProjectDescriptorBaseDir, targetOS)
 			if err != nil {
 				return nil, 0, nil, err
 			}
@@ -1192,11 +1641,13 @@ func (c *Client) processBuildpacks(ctx context.Context, builderBPs []dist.Module
 				nInlineBPs++
 			}
 			declaredBPs = append(declaredBPs, buildpackLocator)
+			optionalBPs = append(optionalBPs, bp.Optional)
 		}
 	}
 
+	downloads := c.prefetchModules(ctx, declaredBPs, relativeBaseDir, builderBPs, opts, buildpack.KindBuildpack, targetToUse)
 	order = dist.Order{{Group: []dist.ModuleRef{}}}
-	for _, bp := range declaredBPs {
+	for i, bp := range declaredBPs {
 		locatorType, err := buildpack.GetLocatorType(bp, relativeBaseDir, builderBPs)
 		if err != nil {
 			return nil, 0, nil, err
@@ -1221,22 +1672,24 @@ func (c *Client) processBuildpacks(ctx context.Context, builderBPs []dist.Module
 				order = newOrder
 			}
 		default:
-			newFetchedBPs, moduleInfo, err := c.fetchBuildpack(ctx, bp, relativeBaseDir, builderBPs, opts, buildpack.KindBuildpack, targetToUse)
+			newFetchedBPs, moduleInfo, err := c.fetchBuildpack(ctx, bp, relativeBaseDir, builderBPs, opts, buildpack.KindBuildpack, targetToUse, downloads)
 			if err != nil {
 				return fetchedBPs, 0, order, err
 			}
 			fetchedBPs = append(fetchedBPs, newFetchedBPs...)
-			order = appendBuildpackToOrder(order, *moduleInfo)
+			order = appendBuildpackToOrder(order, *moduleInfo, optionalBPs[i])
 		}
 	}
 
 	if (len(order) == 0 || len(order[0].Group) == 0) && len(builderOrder) > 0 {
 		preBuildpacks := opts.PreBuildpacks
 		postBuildpacks := opts.PostBuildpacks
+		optionalPreBPs := make([]bool, len(preBuildpacks))
+		optionalPostBPs := make([]bool, len(postBuildpacks))
 		// Pre-buildpacks from --pre-buildpack override pre-buildpacks from project descriptor
 		if len(preBuildpacks) == 0 && len(opts.ProjectDescriptor.Build.Pre.Buildpacks) > 0 {
 			for _, bp := range opts.ProjectDescriptor.Build.Pre.Buildpacks {
-				buildpackLocator, isInline, err := getBuildpackLocator(bp, stackID)
+				buildpackLocator, isInline, err := getBuildpackLocator(bp, stackID, opts.ProjectDescriptorBaseDir, targetOS)
 				if err != nil {
 					return nil, 0, nil, errors.Wrap(err, "get pre-buildpack locator")
 				}
@@ -1244,12 +1697,13 @@ func (c *Client) processBuildpacks(ctx context.Context, builderBPs []dist.Module
 					nInlineBPs++
 				}
 				preBuildpacks = append(preBuildpacks, buildpackLocator)
+				optionalPreBPs = append(optionalPreBPs, bp.Optional)
 			}
 		}
 		// Post-buildpacks from --post-buildpack override post-buildpacks from project descriptor
 		if len(postBuildpacks) == 0 && len(opts.ProjectDescriptor.Build.Post.Buildpacks) > 0 {
 			for _, bp := range opts.ProjectDescriptor.Build.Post.Buildpacks {
-				buildpackLocator, isInline, err := getBuildpackLocator(bp, stackID)
+				buildpackLocator, isInline, err := getBuildpackLocator(bp, stackID, opts.ProjectDescriptorBaseDir, targetOS)
 				if err != nil {
 					return nil, 0, nil, errors.Wrap(err, "get post-buildpack locator")
 				}
@@ -1257,27 +1711,29 @@ func (c *Client) processBuildpacks(ctx context.Context, builderBPs []dist.Module
 					nInlineBPs++
 				}
 				postBuildpacks = append(postBuildpacks, buildpackLocator)
+				optionalPostBPs = append(optionalPostBPs, bp.Optional)
 			}
 		}
 
 		if len(preBuildpacks) > 0 || len(postBuildpacks) > 0 {
+			downloads = c.prefetchModules(ctx, append(append([]string{}, preBuildpacks...), postBuildpacks...), relativeBaseDir, builderBPs, opts, buildpack.KindBuildpack, targetToUse)
 			order = builderOrder
-			for _, bp := range preBuildpacks {
-				newFetchedBPs, moduleInfo, err := c.fetchBuildpack(ctx, bp, relativeBaseDir, builderBPs, opts, buildpack.KindBuildpack, targetToUse)
+			for i, bp := range preBuildpacks {
+				newFetchedBPs, moduleInfo, err := c.fetchBuildpack(ctx, bp, relativeBaseDir, builderBPs, opts, buildpack.KindBuildpack, targetToUse, downloads)
 				if err != nil {
 					return fetchedBPs, 0, order, err
 				}
 				fetchedBPs = append(fetchedBPs, newFetchedBPs...)
-				order = prependBuildpackToOrder(order, *moduleInfo)
+				order = prependBuildpackToOrder(order, *moduleInfo, optionalPreBPs[i])
 			}
 
-			for _, bp := range postBuildpacks {
-				newFetchedBPs, moduleInfo, err := c.fetchBuildpack(ctx, bp, relativeBaseDir, builderBPs, opts, buildpack.KindBuildpack, targetToUse)
+			for i, bp := range postBuildpacks {
+				newFetchedBPs, moduleInfo, err := c.fetchBuildpack(ctx, bp, relativeBaseDir, builderBPs, opts, buildpack.KindBuildpack, targetToUse, downloads)
 				if err != nil {
 					return fetchedBPs, 0, order, err
 				}
 				fetchedBPs = append(fetchedBPs, newFetchedBPs...)
-				order = appendBuildpackToOrder(order, *moduleInfo)
+				order = appendBuildpackToOrder(order, *moduleInfo, optionalPostBPs[i])
 			}
 		}
 	}
@@ -1285,11 +1741,7 @@ func (c *Client) processBuildpacks(ctx context.Context, builderBPs []dist.Module
 	return fetchedBPs, nInlineBPs, order, nil
 }
 
-func (c *Client) fetchBuildpack(ctx context.Context, bp string, relativeBaseDir string, builderBPs []dist.ModuleInfo, opts BuildOptions, kind string, targetToUse *dist.Target) ([]buildpack.BuildModule, *dist.ModuleInfo, error) {
-	pullPolicy := opts.PullPolicy
-	publish := opts.Publish
-	registry := opts.Registry
-
+func (c *Client) fetchBuildpack(ctx context.Context, bp string, relativeBaseDir string, builderBPs []dist.ModuleInfo, opts BuildOptions, kind string, targetToUse *dist.Target, downloads map[string]*moduleDownload) ([]buildpack.BuildModule, *dist.ModuleInfo, error) {
 	locatorType, err := buildpack.GetLocatorType(bp, relativeBaseDir, builderBPs)
 	if err != nil {
 		return nil, nil, err
@@ -1305,17 +1757,16 @@ func (c *Client) fetchBuildpack(ctx context.Context, bp string, relativeBaseDir
 			Version: version,
 		}
 	default:
-		downloadOptions := buildpack.DownloadOptions{
-			RegistryName:    registry,
-			Target:          targetToUse,
-			RelativeBaseDir: relativeBaseDir,
-			Daemon:          !publish,
-			PullPolicy:      pullPolicy,
-		}
-		if kind == buildpack.KindExtension {
-			downloadOptions.ModuleKind = kind
+		downloadOptions := moduleDownloadOptions(relativeBaseDir, opts, kind, targetToUse)
+		var (
+			mainBP buildpack.BuildModule
+			depBPs []buildpack.BuildModule
+		)
+		if download, ok := downloads[bp]; ok {
+			mainBP, depBPs, err = download.main, download.deps, download.err
+		} else {
+			mainBP, depBPs, err = c.buildpackDownloader.Download(ctx, bp, downloadOptions)
 		}
-		mainBP, depBPs, err := c.buildpackDownloader.Download(ctx, bp, downloadOptions)
 		if err != nil {
 			return nil, nil, errors.Wrap(err, "downloading buildpack")
 		}
@@ -1336,6 +1787,47 @@ func (c *Client) fetchBuildpack(ctx context.Context, bp string, relativeBaseDir
 	return fetchedBPs, moduleInfo, nil
 }
 
+// moduleDownloadOptions are the options fetchBuildpack downloads a module of the given kind with
+func moduleDownloadOptions(relativeBaseDir string, opts BuildOptions, kind string, targetToUse *dist.Target) buildpack.DownloadOptions {
+	downloadOptions := buildpack.DownloadOptions{
+		RegistryName:    opts.Registry,
+		Target:          targetToUse,
+		RelativeBaseDir: relativeBaseDir,
+		Daemon:          !opts.Publish,
+		PullPolicy:      opts.buildpacksPullPolicy(),
+		RegistryLookup:  opts.RegistryLookup,
+	}
+	if kind == buildpack.KindExtension {
+		downloadOptions.ModuleKind = kind
+	}
+	return downloadOptions
+}
+
+// prefetchModules downloads the modules of the locators at once, for fetchBuildpack to take them from the returned
+// downloads rather than downloading them one at a time. The modules of the builder and the ones located by ID aren't
+// downloaded, and fetchBuildpack reports the locators that are invalid.
+func (c *Client) prefetchModules(ctx context.Context, locators []string, relativeBaseDir string, builderModules []dist.ModuleInfo, opts BuildOptions, kind string, targetToUse *dist.Target) map[string]*moduleDownload {
+	downloads := map[string]*moduleDownload{}
+	var pending []*moduleDownload
+	for _, locator := range locators {
+		locatorType, err := buildpack.GetLocatorType(locator, relativeBaseDir, builderModules)
+		if err != nil || locatorType == buildpack.FromBuilderLocator || locatorType == buildpack.IDLocator {
+			continue
+		}
+		if kind == buildpack.KindExtension && locatorType == buildpack.RegistryLocator {
+			continue
+		}
+		if _, ok := downloads[locator]; ok {
+			continue
+		}
+		download := &moduleDownload{uri: locator, opts: moduleDownloadOptions(relativeBaseDir, opts, kind, targetToUse)}
+		downloads[locator] = download
+		pending = append(pending, download)
+	}
+	c.downloadModules(ctx, pending)
+	return downloads
+}
+
 func (c *Client) fetchBuildpackDependencies(ctx context.Context, bp string, packageCfgPath string, downloadOptions buildpack.DownloadOptions) ([]buildpack.BuildModule, error) {
 	packageReader := buildpackage.NewConfigReader()
 	packageCfg, err := packageReader.Read(packageCfgPath)
@@ -1348,6 +1840,7 @@ func (c *Client) fetchBuildpackDependencies(ctx context.Context, bp string, pack
 				Daemon:          downloadOptions.Daemon,
 				PullPolicy:      downloadOptions.PullPolicy,
 				RelativeBaseDir: filepath.Join(bp, packageCfg.Buildpack.URI),
+				RegistryLookup:  downloadOptions.RegistryLookup,
 			})
 
 			if err != nil {
@@ -1361,14 +1854,14 @@ func (c *Client) fetchBuildpackDependencies(ctx context.Context, bp string, pack
 	return nil, err
 }
 
-func getBuildpackLocator(bp projectTypes.Buildpack, stackID string) (locator string, isInline bool, err error) {
+func getBuildpackLocator(bp projectTypes.Buildpack, stackID, baseDir, targetOS string) (locator string, isInline bool, err error) {
 	switch {
-	case bp.ID != "" && bp.Script.Inline != "" && bp.URI == "":
+	case bp.ID != "" && (bp.Script.Inline != "" || len(bp.Script.Files) > 0) && bp.URI == "":
 		if bp.Script.API == "" {
 			return "", false, errors.New("Missing API version for inline buildpack")
 		}
 
-		pathToInlineBuildpack, err := createInlineBuildpack(bp, stackID)
+		pathToInlineBuildpack, err := createInlineBuildpack(bp, stackID, baseDir, targetOS)
 		if err != nil {
 			return "", false, errors.Wrap(err, "Could not create temporary inline buildpack")
 		}
@@ -1384,12 +1877,12 @@ func getBuildpackLocator(bp projectTypes.Buildpack, stackID string) (locator str
 	}
 }
 
-func appendBuildpackToOrder(order dist.Order, bpInfo dist.ModuleInfo) (newOrder dist.Order) {
+func appendBuildpackToOrder(order dist.Order, bpInfo dist.ModuleInfo, optional bool) (newOrder dist.Order) {
 	for _, orderEntry := range order {
 		newEntry := orderEntry
 		newEntry.Group = append(newEntry.Group, dist.ModuleRef{
 			ModuleInfo: bpInfo,
-			Optional:   false,
+			Optional:   optional,
 		})
 		newOrder = append(newOrder, newEntry)
 	}
@@ -1397,12 +1890,12 @@ func appendBuildpackToOrder(order dist.Order, bpInfo dist.ModuleInfo) (newOrder
 	return newOrder
 }
 
-func prependBuildpackToOrder(order dist.Order, bpInfo dist.ModuleInfo) (newOrder dist.Order) {
+func prependBuildpackToOrder(order dist.Order, bpInfo dist.ModuleInfo, optional bool) (newOrder dist.Order) {
 	for _, orderEntry := range order {
 		newEntry := orderEntry
 		newGroup := []dist.ModuleRef{{
 			ModuleInfo: bpInfo,
-			Optional:   false,
+			Optional:   optional,
 		}}
 		newEntry.Group = append(newGroup, newEntry.Group...)
 		newOrder = append(newOrder, newEntry)
@@ -1415,6 +1908,7 @@ func (c *Client) processExtensions(ctx context.Context, builderExs []dist.Module
 	relativeBaseDir := opts.RelativeBaseDir
 	declaredExs := opts.Extensions
 
+	downloads := c.prefetchModules(ctx, declaredExs, relativeBaseDir, builderExs, opts, buildpack.KindExtension, targetToUse)
 	orderExtensions = dist.Order{{Group: []dist.ModuleRef{}}}
 	for _, ex := range declaredExs {
 		locatorType, err := buildpack.GetLocatorType(ex, relativeBaseDir, builderExs)
@@ -1428,12 +1922,12 @@ func (c *Client) processExtensions(ctx context.Context, builderExs []dist.Module
 		case buildpack.FromBuilderLocator:
 			return nil, nil, errors.New("from builder is not supported for extensions")
 		default:
-			newFetchedExs, moduleInfo, err := c.fetchBuildpack(ctx, ex, relativeBaseDir, builderExs, opts, buildpack.KindExtension, targetToUse)
+			newFetchedExs, moduleInfo, err := c.fetchBuildpack(ctx, ex, relativeBaseDir, builderExs, opts, buildpack.KindExtension, targetToUse, downloads)
 			if err != nil {
 				return fetchedExs, orderExtensions, err
 			}
 			fetchedExs = append(fetchedExs, newFetchedExs...)
-			orderExtensions = prependBuildpackToOrder(orderExtensions, *moduleInfo)
+			orderExtensions = prependBuildpackToOrder(orderExtensions, *moduleInfo, false)
 		}
 	}
 
@@ -1650,12 +2144,18 @@ func (c *Client) logImageNameAndSha(ctx context.Context, publish bool, imageRef
 		return fmt.Errorf("reading image sha: %w", err)
 	}
 
+	return c.writeImageNameAndSha(imageRef, id)
+}
+
+// writeImageNameAndSha writes '<image-name>@<digest>' as a single line to the logger's base writer.
+// It is used in quiet mode, where it is the only output a command produces on stdout.
+func (c *Client) writeImageNameAndSha(imageRef name.Reference, id imgutil.Identifier) error {
 	// Remove tag, if it exists, from the image name
 	imgName := strings.TrimSuffix(imageRef.String(), imageRef.Identifier())
 	imgNameAndSha := fmt.Sprintf("%s@%s\n", imgName, parseDigestFromImageID(id))
 
 	// Access the logger's Writer directly to bypass ReportSuccessfulQuietBuild mode
-	_, err = c.logger.Writer().Write([]byte(imgNameAndSha))
+	_, err := c.logger.Writer().Write([]byte(imgNameAndSha))
 	return err
 }
 
@@ -1672,7 +2172,17 @@ func parseDigestFromImageID(id imgutil.Identifier) string {
 	return fmt.Sprintf("sha256:%s", digest)
 }
 
-func createInlineBuildpack(bp projectTypes.Buildpack, stackID string) (string, error) {
+func createInlineBuildpack(bp projectTypes.Buildpack, stackID, baseDir, targetOS string) (string, error) {
+	script, err := inlineScript(bp.Script, baseDir)
+	if err != nil {
+		return "", err
+	}
+
+	bins, err := inlineBuildpackBins(bp.Script.Shell, script, targetOS)
+	if err != nil {
+		return "", err
+	}
+
 	pathToInlineBuilpack, err := os.MkdirTemp("", "inline-cnb")
 	if err != nil {
 		return pathToInlineBuilpack, err
@@ -1686,38 +2196,75 @@ func createInlineBuildpack(bp projectTypes.Buildpack, stackID string) (string, e
 		return pathToInlineBuilpack, err
 	}
 
-	shell := bp.Script.Shell
-	if shell == "" {
-		shell = "/bin/sh"
+	for name, contents := range bins {
+		if err = createBinScript(pathToInlineBuilpack, name, contents, nil); err != nil {
+			return pathToInlineBuilpack, err
+		}
 	}
 
-	binBuild := fmt.Sprintf(`#!%s
-
-%s
-`, shell, bp.Script.Inline)
-
-	binDetect := fmt.Sprintf(`#!%s
-
-exit 0
-`, shell)
+	return pathToInlineBuilpack, nil
+}
 
-	if err = createBinScript(pathToInlineBuilpack, "build", binBuild, nil); err != nil {
-		return pathToInlineBuilpack, err
+// inlineScript returns the inline script followed by the contents of the script files, relative to baseDir, in the
+// order they are declared
+func inlineScript(script projectTypes.Script, baseDir string) (string, error) {
+	parts := []string{}
+	if script.Inline != "" {
+		parts = append(parts, strings.TrimRight(script.Inline, "\n"))
 	}
-
-	if err = createBinScript(pathToInlineBuilpack, "build.bat", bp.Script.Inline, nil); err != nil {
-		return pathToInlineBuilpack, err
+	for _, file := range script.Files {
+		contents, err := os.ReadFile(filepath.Join(baseDir, filepath.FromSlash(file)))
+		if err != nil {
+			return "", errors.Wrapf(err, "reading script file %s", style.Symbol(file))
+		}
+		parts = append(parts, strings.TrimRight(string(contents), "\r\n"))
 	}
+	return strings.Join(parts, "\n"), nil
+}
 
-	if err = createBinScript(pathToInlineBuilpack, "detect", binDetect, nil); err != nil {
-		return pathToInlineBuilpack, err
+// inlineBuildpackBins returns the contents of the bin/ files of an inline buildpack, keyed by name, running script with
+// shell on targetOS. The detect executable always passes.
+func inlineBuildpackBins(shell, script, targetOS string) (map[string]string, error) {
+	if targetOS == "windows" {
+		switch shell {
+		case "", projectTypes.ShellCmd:
+			return map[string]string{
+				"build.bat":  fmt.Sprintf("@echo off\r\n%s\r\n", toCRLF(script)),
+				"detect.bat": "@exit /b 0\r\n",
+			}, nil
+		case projectTypes.ShellPowerShell:
+			return map[string]string{
+				"build.ps1":  toCRLF(script) + "\r\n",
+				"build.bat":  "@powershell -NoProfile -ExecutionPolicy Bypass -File \"%~dp0build.ps1\" %*\r\n",
+				"detect.bat": "@exit /b 0\r\n",
+			}, nil
+		default:
+			return nil, errors.Errorf("shell %s is not supported for Windows builds, use %s or %s", style.Symbol(shell), projectTypes.ShellCmd, projectTypes.ShellPowerShell)
+		}
 	}
 
-	if err = createBinScript(pathToInlineBuilpack, "detect.bat", bp.Script.Inline, nil); err != nil {
-		return pathToInlineBuilpack, err
+	var interpreter string
+	switch shell {
+	case "", projectTypes.ShellSh:
+		interpreter = "/bin/sh"
+	case projectTypes.ShellBash:
+		interpreter = "/usr/bin/env bash"
+	case projectTypes.ShellPowerShell:
+		interpreter = "/usr/bin/env pwsh"
+	case projectTypes.ShellCmd:
+		return nil, errors.Errorf("shell %s is only supported for Windows builds", style.Symbol(shell))
+	default:
+		interpreter = shell
 	}
 
-	return pathToInlineBuilpack, nil
+	return map[string]string{
+		"build":  fmt.Sprintf("#!%s\n\n%s\n", interpreter, script),
+		"detect": fmt.Sprintf("#!%s\n\nexit 0\n", interpreter),
+	}, nil
+}
+
+func toCRLF(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "\r\n", "\n"), "\n", "\r\n")
 }
 
 // fullImagePath parses the inputImageReference provided by the user and creates the directory
@@ -1737,6 +2284,25 @@ func fullImagePath(inputImageRef InputImageReference, create bool) (string, erro
 	return imagePath, nil
 }
 
+// writeLayoutArchive writes the OCI layout in dir to a tarball at path, with the oci-layout and index.json files of the
+// layout at its root
+func writeLayoutArchive(dir, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	tw := tar.NewWriter(file)
+	if err := archive.WriteDirToTar(tw, dir, "", 0, 0, -1, false, false, nil); err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
 // appendLayoutVolumes mount host volume into the build container, in the form '<host path>:<target path>[:<options>]'
 // the volumes mounted are:
 // - The path where the user wants the image to be exported in OCI layout format
@@ -0,0 +1,276 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/buildpacks/imgutil"
+	"github.com/buildpacks/lifecycle/auth"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/build"
+	"github.com/buildpacks/pack/internal/builder"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/errcode"
+	"github.com/buildpacks/pack/pkg/image"
+	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/project"
+)
+
+// Paths of the filesystem of the builder the creator runs in, when it doesn't run in a container of the daemon
+const (
+	creatorPath        = "/cnb/lifecycle/creator"
+	creatorAppDir      = "/workspace"
+	creatorCacheDir    = "/cache"
+	creatorPlatformDir = "/platform"
+
+	// creatorProjectMetadataPath is the path of the metadata of the project the creator records in the image
+	creatorProjectMetadataPath = creatorPlatformDir + "/project-metadata.toml"
+)
+
+// creatorBuild is a build that runs the creator of the builder without a daemon, e.g. in a sandbox or in a cluster
+type creatorBuild struct {
+	imageRef     name.Reference
+	builderRef   name.Reference
+	builderImage imgutil.Image
+	builder      *builder.Builder
+	appPath      string
+	fileFilter   func(string) bool
+
+	// env of the creator, other than that of the builder image and CNB_REGISTRY_AUTH
+	env []string
+
+	// registryAuth is the value of CNB_REGISTRY_AUTH, the credentials of the registries the creator reads and writes
+	registryAuth string
+
+	runImageName string
+
+	// sourceDigest is the source digest of the build, when recorded or compared
+	sourceDigest string
+
+	// projectMetadata is the content of the project-metadata.toml of the creator, when there's metadata to record
+	projectMetadata []byte
+}
+
+// prepareCreatorBuild validates the options of a build running the creator of the builder without a daemon, which
+// the mode of the build names in errors, and pulls the builder from its registry
+func (c *Client) prepareCreatorBuild(ctx context.Context, opts BuildOptions, mode string) (creatorBuild, error) {
+	if err := validateCreatorBuild(opts, mode); err != nil {
+		return creatorBuild{}, err
+	}
+	if err := validateImageMetadata(opts); err != nil {
+		return creatorBuild{}, err
+	}
+
+	imageRef, err := c.parseTagReference(opts.Image)
+	if err != nil {
+		return creatorBuild{}, errors.Wrapf(err, "invalid image name '%s'", opts.Image)
+	}
+
+	appPath, err := c.processAppPath(opts.AppPath)
+	if err != nil {
+		return creatorBuild{}, errors.Wrapf(err, "invalid app path '%s'", opts.AppPath)
+	}
+
+	builderRef, err := c.processBuilderName(opts.Builder)
+	if err != nil {
+		return creatorBuild{}, errors.Wrapf(err, "invalid builder '%s'", opts.Builder)
+	}
+
+	fetchOptions := image.FetchOptions{
+		Daemon:     false,
+		PullPolicy: image.PullAlways,
+		Target:     platformTarget(opts.Platform),
+	}
+	rawBuilderImage, err := c.imageFetcher.Fetch(ctx, builderRef.Name(), fetchOptions)
+	if err != nil {
+		return creatorBuild{}, errcode.Wrap(errcode.BuilderFetchFailed, errors.Wrapf(err, "failed to fetch builder image '%s'", builderRef.Name()))
+	}
+
+	bldr, err := c.getBuilder(rawBuilderImage)
+	if err != nil {
+		return creatorBuild{}, errors.Wrapf(err, "invalid builder %s", style.Symbol(opts.Builder))
+	}
+	if len(bldr.OrderExtensions()) > 0 {
+		return creatorBuild{}, errors.Errorf("builder %s has image extensions, which %s builds don't support", style.Symbol(opts.Builder), mode)
+	}
+	if !supportsCreator(bldr.LifecycleDescriptor().Info.Version) {
+		return creatorBuild{}, errors.Errorf("the lifecycle of builder %s has no creator, which %s builds require", style.Symbol(opts.Builder), mode)
+	}
+
+	var builderPlatformAPIs builder.APISet
+	builderPlatformAPIs = append(builderPlatformAPIs, bldr.LifecycleDescriptor().APIs.Platform.Deprecated...)
+	builderPlatformAPIs = append(builderPlatformAPIs, bldr.LifecycleDescriptor().APIs.Platform.Supported...)
+	platformAPI, err := build.FindLatestSupported(builderPlatformAPIs, nil)
+	if err != nil {
+		return creatorBuild{}, errcode.Errorf(errcode.BuilderIncompatible, "Builder %s is incompatible with this version of pack", style.Symbol(opts.Builder))
+	}
+
+	runImageName := c.resolveRunImage(opts.RunImage, imageRef.Context().RegistryStr(), builderRef.Context().RegistryStr(), bldr.DefaultRunImage(), opts.AdditionalMirrors, true, fetchOptions)
+	if runImageName == "" {
+		return creatorBuild{}, errors.Errorf("builder %s has no run image, provide one with --run-image", style.Symbol(opts.Builder))
+	}
+
+	fileFilter, err := project.FileFilter(opts.ProjectDescriptor.Build)
+	if err != nil {
+		return creatorBuild{}, err
+	}
+
+	registryAuth, err := auth.BuildEnvVar(c.keychain, imageRef.String(), runImageName, opts.CacheImage, opts.PreviousImage)
+	if err != nil {
+		return creatorBuild{}, err
+	}
+
+	env := []string{"CNB_PLATFORM_API=" + platformAPI.String()}
+	proxyConfig := c.processProxyConfig(opts.ProxyConfig)
+	for k, v := range map[string]string{"HTTP_PROXY": proxyConfig.HTTPProxy, "HTTPS_PROXY": proxyConfig.HTTPSProxy, "NO_PROXY": proxyConfig.NoProxy} {
+		if v != "" {
+			env = append(env, k+"="+v, strings.ToLower(k)+"="+v)
+		}
+	}
+	if opts.CreationTime != nil && platformAPI.AtLeast("0.9") {
+		env = append(env, "SOURCE_DATE_EPOCH="+strconv.Itoa(int(opts.CreationTime.Unix())))
+	}
+	if !opts.SerialExport {
+		env = append(env, "CNB_PARALLEL_EXPORT=true")
+	}
+	env = append(env, lifecycleEnv(opts.LifecycleEnv)...)
+
+	return creatorBuild{
+		imageRef:     imageRef,
+		builderRef:   builderRef,
+		builderImage: rawBuilderImage,
+		builder:      bldr,
+		appPath:      appPath,
+		fileFilter:   fileFilter,
+		env:          env,
+		registryAuth: registryAuth,
+		runImageName: runImageName,
+	}, nil
+}
+
+// checkCreatorBuild runs the steps every build runs before the lifecycle: the plan of the build is checked against
+// the policy and its source digest computed, the build being skipped when the previous image has the same digest.
+// It reports whether the build was skipped, or else records the metadata of the project in cb.
+func (c *Client) checkCreatorBuild(ctx context.Context, opts BuildOptions, cb *creatorBuild, started time.Time) (bool, error) {
+	if opts.CheckPlan != nil {
+		plan := BuildPlan{
+			Builder:  opts.Builder,
+			RunImage: cb.runImageName,
+			Image:    cb.imageRef.Name(),
+			Publish:  opts.Publish,
+			// creator builds only use the buildpacks of the builder
+			Buildpacks: buildPlanBuildpacks(cb.builder.Buildpacks(), nil),
+		}
+		if err := opts.CheckPlan(plan); err != nil {
+			return false, err
+		}
+	}
+
+	if opts.RecordSourceDigest || opts.SkipUnchanged {
+		runImage, err := c.imageFetcher.Fetch(ctx, cb.runImageName, image.FetchOptions{
+			Daemon:     false,
+			PullPolicy: image.PullAlways,
+			Target:     platformTarget(opts.Platform),
+		})
+		if err != nil {
+			return false, errors.Wrapf(err, "fetching run image %s", style.Symbol(cb.runImageName))
+		}
+		if cb.sourceDigest, err = c.buildSourceDigest(cb.appPath, opts, cb.builderImage, runImage, cb.runImageName, nil); err != nil {
+			return false, errors.Wrap(err, "computing source digest")
+		}
+		c.logger.Debugf("Source digest of the build is %s", cb.sourceDigest)
+
+		if skipped, err := c.skipUnchangedBuild(ctx, opts, cb.imageRef.Name(), cb.sourceDigest, started); err != nil || skipped {
+			return skipped, err
+		}
+	}
+
+	if metadata := c.projectMetadata(opts, cb.sourceDigest); metadata.Source != nil {
+		var buf bytes.Buffer
+		if err := toml.NewEncoder(&buf).Encode(metadata); err != nil {
+			return false, errors.Wrap(err, "encoding project metadata")
+		}
+		cb.projectMetadata = buf.Bytes()
+	}
+	return false, nil
+}
+
+// args are the arguments of the creator of cb
+func (cb creatorBuild) args(logger logging.Logger, opts BuildOptions) []string {
+	projectMetadataPath := ""
+	if cb.projectMetadata != nil {
+		projectMetadataPath = creatorProjectMetadataPath
+	}
+	return creatorArgs(logger, opts, cb.runImageName, cb.imageRef.String(), projectMetadataPath)
+}
+
+// validateCreatorBuild fails for options that need a daemon, or the containers of one
+func validateCreatorBuild(opts BuildOptions, mode string) error {
+	trustBuilder := opts.TrustBuilder
+	if trustBuilder == nil {
+		trustBuilder = builder.IsKnownTrustedBuilder
+	}
+
+	switch {
+	case !opts.Publish:
+		return errors.Errorf("%s builds must publish the image, as there's no daemon to save it to", mode)
+	case !trustBuilder(opts.Builder):
+		return errors.Errorf("%s builds give the registry credentials to the lifecycle of the builder, so builder %s must be trusted", mode, style.Symbol(opts.Builder))
+	case len(opts.Buildpacks) > 0 || len(opts.Extensions) > 0 || len(opts.PreBuildpacks) > 0 || len(opts.PostBuildpacks) > 0:
+		return errors.Errorf("%s builds only use the buildpacks of the builder", mode)
+	case opts.Layout():
+		return errors.Errorf("%s builds can't export to an OCI layout", mode)
+	case opts.Interactive:
+		return errors.Errorf("%s builds can't be interactive", mode)
+	case opts.DetectOnly:
+		return errors.Errorf("%s builds can't stop after detecting", mode)
+	case len(opts.ContainerConfig.Volumes) > 0 || opts.ContainerConfig.Network != "" || len(opts.ContainerConfig.ExtraHosts) > 0:
+		return errors.Errorf("%s builds have no containers to configure the volumes, network or hosts of", mode)
+	case opts.ClearBuildCache || opts.ClearLaunchCache || len(opts.ClearBuildpackCaches) > 0:
+		return errors.Errorf("%s builds cache in an image, which can only be cleared as a whole", mode)
+	case opts.ContainerConfig.SSHAgent != "":
+		return errors.Errorf("%s builds can't forward the SSH agent", mode)
+	}
+	return nil
+}
+
+// creatorArgs are the arguments of the creator, like those of the creator container of a trusted builder
+func creatorArgs(logger logging.Logger, opts BuildOptions, runImageName, imageName, projectMetadataPath string) []string {
+	var args []string
+	if opts.LifecycleLogLevel != "" {
+		args = append(args, "-log-level", opts.LifecycleLogLevel)
+	} else if logger.IsVerbose() {
+		args = append(args, "-log-level", "debug")
+	}
+
+	args = append(args,
+		"-app", creatorAppDir,
+		"-cache-dir", creatorCacheDir,
+		"-run-image", runImageName,
+	)
+	for _, tag := range opts.AdditionalTags {
+		args = append(args, "-tag", tag)
+	}
+	if opts.ClearCache {
+		args = append(args, "-skip-restore")
+	}
+	if opts.PreviousImage != "" {
+		args = append(args, "-previous-image", opts.PreviousImage)
+	}
+	if opts.DefaultProcessType != "" {
+		args = append(args, "-process-type", opts.DefaultProcessType)
+	}
+	if opts.CacheImage != "" {
+		args = append(args, "-cache-image", opts.CacheImage)
+	}
+	if projectMetadataPath != "" {
+		args = append(args, "-project-metadata", projectMetadataPath)
+	}
+	return append(args, imageName)
+}
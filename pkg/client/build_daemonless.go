@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"io"
+	"os"
+	"path"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/sandbox"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/archive"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+// buildDaemonless builds the image by running the creator of the builder in a sandbox with the filesystem of the
+// builder, pulled from its registry, rather than in a container of the daemon. The creator reads the run image and
+// publishes the image with go-containerregistry, so the build needs no daemon at all.
+func (c *Client) buildDaemonless(ctx context.Context, opts BuildOptions) error {
+	started := time.Now()
+
+	cb, err := c.prepareCreatorBuild(ctx, opts, "daemonless")
+	if err != nil {
+		return err
+	}
+	if skipped, err := c.checkCreatorBuild(ctx, opts, &cb, started); err != nil || skipped {
+		return err
+	}
+
+	c.logger.Debugf("Extracting builder %s to a sandbox", style.Symbol(cb.builderRef.Name()))
+	sb, err := sandbox.New(cb.builderImage.UnderlyingImage())
+	if err != nil {
+		return errors.Wrapf(err, "creating sandbox of builder %s", style.Symbol(cb.builderRef.Name()))
+	}
+	defer sb.Remove()
+
+	if err := addDaemonlessApp(sb, cb.appPath, cb.fileFilter); err != nil {
+		return errors.Wrapf(err, "copying app %s to the sandbox", style.Symbol(cb.appPath))
+	}
+	for k, v := range opts.Env {
+		if err := sb.WriteFile(path.Join(creatorPlatformDir, "env", k), []byte(v), 0644); err != nil {
+			return errors.Wrapf(err, "writing env var %s", style.Symbol(k))
+		}
+	}
+	if cb.projectMetadata != nil {
+		if err := sb.WriteFile(creatorProjectMetadataPath, cb.projectMetadata, 0644); err != nil {
+			return errors.Wrap(err, "writing project metadata")
+		}
+	}
+
+	configFile, err := cb.builderImage.UnderlyingImage().ConfigFile()
+	if err != nil {
+		return errors.Wrapf(err, "reading config of builder %s", style.Symbol(cb.builderRef.Name()))
+	}
+	env := append([]string{}, configFile.Config.Env...)
+	env = append(env,
+		// the creator runs as the root user of the sandbox, which is the user running pack on the host
+		"CNB_USER_ID=0",
+		"CNB_GROUP_ID=0",
+		"CNB_REGISTRY_AUTH="+cb.registryAuth,
+	)
+	env = append(env, cb.env...)
+
+	cmd, err := sb.Command(ctx, env, creatorPath, cb.args(c.logger, opts)...)
+	if err != nil {
+		return err
+	}
+	cmd.Stdout = logging.GetWriterForLevel(c.logger, logging.InfoLevel)
+	cmd.Stderr = logging.GetWriterForLevel(c.logger, logging.ErrorLevel)
+
+	c.logger.Infof("Running the creator of builder %s in a sandbox", style.Symbol(cb.builderRef.Name()))
+	if err := cmd.Run(); err != nil {
+		return errors.Wrap(err, "executing lifecycle")
+	}
+	_, err = c.finishImage(ctx, cb.imageRef.Name(), opts)
+	return err
+}
+
+// addDaemonlessApp copies the app directory or zip at appPath to the app directory of the sandbox
+func addDaemonlessApp(sb *sandbox.Sandbox, appPath string, fileFilter func(string) bool) error {
+	fi, err := os.Stat(appPath)
+	if err != nil {
+		return err
+	}
+
+	var rc io.ReadCloser
+	if fi.IsDir() {
+		rc = archive.ReadDirAsTar(appPath, creatorAppDir, 0, 0, -1, false, true, fileFilter)
+	} else {
+		rc = archive.ReadZipAsTar(appPath, creatorAppDir, 0, 0, -1, false, fileFilter)
+	}
+	defer rc.Close()
+	return sb.Add(rc)
+}
@@ -0,0 +1,29 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// detectResults are the files the detector writes that a build detecting only logs, with what they list
+var detectResults = []struct{ file, description string }{
+	{"group.toml", "Buildpacks that passed detection"},
+	{"plan.toml", "Build plan"},
+}
+
+// logDetectResult logs the group and plan the detector resolved, copied out of its container to dir
+func (c *Client) logDetectResult(dir string) error {
+	for _, result := range detectResults {
+		contents, err := os.ReadFile(filepath.Join(dir, result.file))
+		if err != nil {
+			return errors.Wrapf(err, "reading %s", result.file)
+		}
+
+		c.logger.Infof("\n%s (%s):", result.description, result.file)
+		c.logger.Info(strings.TrimSpace(string(contents)))
+	}
+	return nil
+}
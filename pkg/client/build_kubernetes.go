@@ -0,0 +1,192 @@
+package client
+
+import (
+	"archive/tar"
+	"context"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/kubernetes"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/archive"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+// Drivers that run the lifecycle of a build
+const (
+	// DriverDocker runs the lifecycle in containers of the daemon, the default
+	DriverDocker = "docker"
+
+	// DriverKubernetes runs the lifecycle as a Job of a Kubernetes cluster
+	DriverKubernetes = "kubernetes"
+)
+
+// KubernetesOptions configure the cluster of builds with DriverKubernetes, through the kubeconfig of kubectl
+type KubernetesOptions struct {
+	// Context of the kubeconfig, defaults to its current context
+	Context string
+
+	// Namespace the Job of the build runs in, defaults to the namespace of the context
+	Namespace string
+}
+
+// buildKubernetes builds the image by running the creator of the builder as a Job of a Kubernetes cluster, the way
+// kpack builds images, rather than in a container of the daemon. The app is copied to the pod of the Job, the logs of
+// the creator are streamed back and the Job is deleted once the image is published.
+func (c *Client) buildKubernetes(ctx context.Context, opts BuildOptions) error {
+	started := time.Now()
+
+	cb, err := c.prepareCreatorBuild(ctx, opts, "kubernetes")
+	if err != nil {
+		return err
+	}
+	if skipped, err := c.checkCreatorBuild(ctx, opts, &cb, started); err != nil || skipped {
+		return err
+	}
+
+	builderID, err := cb.builderImage.Identifier()
+	if err != nil {
+		return errors.Wrapf(err, "reading digest of builder %s", style.Symbol(cb.builderRef.Name()))
+	}
+
+	files, err := kubernetesFiles(cb.appPath, cb.fileFilter, cb.builder.UID(), cb.builder.GID(), opts.Env, cb.projectMetadata)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		files.Close()
+		os.Remove(files.Name())
+	}()
+
+	job := kubernetes.Job{
+		Name: "pack-build-" + randString(10),
+		// the builder is pinned to its digest, so that the cluster runs the builder that was validated
+		Image:     builderID.String(),
+		UID:       cb.builder.UID(),
+		GID:       cb.builder.GID(),
+		Command:   append([]string{creatorPath}, cb.args(c.logger, opts)...),
+		Env:       cb.env,
+		SecretEnv: map[string]string{"CNB_REGISTRY_AUTH": cb.registryAuth},
+		Files:     files,
+	}
+
+	c.logger.Infof("Running the creator of builder %s as job %s", style.Symbol(cb.builderRef.Name()), style.Symbol(job.Name))
+	result, err := kubernetes.Run(ctx, c.logger, kubernetes.Options{
+		Context:   opts.Kubernetes.Context,
+		Namespace: opts.Kubernetes.Namespace,
+	}, job)
+	if err != nil {
+		return errors.Wrap(err, "executing lifecycle")
+	}
+
+	rewritten, err := c.finishImage(ctx, cb.imageRef.Name(), opts)
+	if err != nil {
+		return err
+	}
+	if rewritten != "" {
+		result.Digest = rewritten[strings.LastIndex(rewritten, "@")+1:]
+	}
+
+	if result.Digest != "" {
+		if logging.IsQuiet(c.logger) {
+			imageName := strings.TrimSuffix(cb.imageRef.String(), ":"+cb.imageRef.Identifier())
+			if _, err := c.logger.Writer().Write([]byte(imageName + "@" + result.Digest + "\n")); err != nil {
+				return err
+			}
+		} else {
+			c.logger.Infof("Image %s has digest %s", style.Symbol(cb.imageRef.String()), style.Symbol(result.Digest))
+		}
+	}
+
+	if opts.Summary != nil {
+		*opts.Summary = BuildSummary{
+			Image:            cb.imageRef.String(),
+			Digest:           result.Digest,
+			Duration:         time.Since(started),
+			Started:          started,
+			Builder:          cb.builderRef.Name(),
+			RunImage:         cb.runImageName,
+			LifecycleVersion: cb.builder.LifecycleDescriptor().Info.Version.String(),
+			SourceDigest:     cb.sourceDigest,
+		}
+	}
+	return nil
+}
+
+// kubernetesFiles is the tar of the files of the Job of a build: the app directory or zip at appPath, owned by the
+// user of the builder, the platform env vars and the metadata of the project, if any
+func kubernetesFiles(appPath string, fileFilter func(string) bool, uid, gid int, env map[string]string, projectMetadata []byte) (*os.File, error) {
+	// the tar is written to a file rather than streamed, so that a broken app fails before the Job is created
+	f, err := os.CreateTemp("", "pack-kubernetes-files-*.tar")
+	if err != nil {
+		return nil, err
+	}
+	if err := writeKubernetesFiles(f, appPath, fileFilter, uid, gid, env, projectMetadata); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+	return f, nil
+}
+
+func writeKubernetesFiles(f *os.File, appPath string, fileFilter func(string) bool, uid, gid int, env map[string]string, projectMetadata []byte) error {
+	fi, err := os.Stat(appPath)
+	if err != nil {
+		return errors.Wrapf(err, "reading app %s", style.Symbol(appPath))
+	}
+
+	tw := tar.NewWriter(f)
+	if fi.IsDir() {
+		// the root isn't included, as the app directory is a volume the user of the builder doesn't own
+		err = archive.WriteDirToTar(tw, appPath, kubernetes.AppDir, uid, gid, -1, false, false, fileFilter)
+	} else {
+		err = archive.WriteZipToTar(tw, appPath, kubernetes.AppDir, uid, gid, -1, false, fileFilter)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "copying app %s", style.Symbol(appPath))
+	}
+
+	writeFile := func(name string, content []byte) error {
+		header := &tar.Header{
+			Name:     path.Join(kubernetes.PlatformDir, name),
+			Mode:     0644,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+			Uid:      uid,
+			Gid:      gid,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		_, err := tw.Write(content)
+		return err
+	}
+
+	var keys []string
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := writeFile(path.Join("env", k), []byte(env[k])); err != nil {
+			return errors.Wrapf(err, "writing env var %s", style.Symbol(k))
+		}
+	}
+	if projectMetadata != nil {
+		if err := writeFile(path.Base(creatorProjectMetadataPath), projectMetadata); err != nil {
+			return errors.Wrap(err, "writing project metadata")
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	_, err = f.Seek(0, io.SeekStart)
+	return err
+}
@@ -0,0 +1,50 @@
+package client
+
+import (
+	"github.com/buildpacks/pack/pkg/buildpack"
+	"github.com/buildpacks/pack/pkg/dist"
+)
+
+// BuildPlan holds what a build resolved to, once the builder, run image and buildpacks are known but before
+// the lifecycle runs
+type BuildPlan struct {
+	// Name of the builder image
+	Builder string `json:"builder"`
+
+	// Name of the run image the app image is based on
+	RunImage string `json:"runImage"`
+
+	// Name of the output image
+	Image string `json:"image"`
+
+	// Whether the output image is published to its registry
+	Publish bool `json:"publish"`
+
+	// Buildpacks of the builder and buildpacks added to it, as <id>@<version>
+	Buildpacks []string `json:"buildpacks"`
+}
+
+func buildPlanBuildpacks(builderBPs []dist.ModuleInfo, fetchedBPs []buildpack.BuildModule) []string {
+	var (
+		buildpacks []string
+		seen       = map[string]bool{}
+	)
+	add := func(info dist.ModuleInfo) {
+		ref := info.ID
+		if info.Version != "" {
+			ref += "@" + info.Version
+		}
+		if !seen[ref] {
+			seen[ref] = true
+			buildpacks = append(buildpacks, ref)
+		}
+	}
+
+	for _, info := range builderBPs {
+		add(info)
+	}
+	for _, module := range fetchedBPs {
+		add(module.Descriptor().Info())
+	}
+	return buildpacks
+}
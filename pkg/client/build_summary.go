@@ -0,0 +1,377 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/buildpacks/imgutil"
+	"github.com/buildpacks/imgutil/local"
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"github.com/buildpacks/pack/pkg/dist"
+	"github.com/buildpacks/pack/pkg/image"
+)
+
+// Cache status of a buildpack in a BuildSummary
+const (
+	CacheHit     = "hit"
+	CacheMiss    = "miss"
+	CachePartial = "partial"
+)
+
+// BuildSummary holds the key facts about a completed build
+type BuildSummary struct {
+	// Name of the built image
+	Image string
+
+	// Digest (or image ID when saved to the daemon) of the built image
+	Digest string
+
+	// Size of the image in bytes, 0 when unknown
+	Size int64
+
+	// Buildpacks that contributed to the image, in the order they ran
+	Buildpacks []BuildpackSummary
+
+	// Process types of the image
+	Processes []string
+
+	// Default process type of the image, if any
+	DefaultProcess string
+
+	// Total duration of the build
+	Duration time.Duration
+
+	// Lifecycle phases that ran, in the order they completed. The phases run by the creator are timed from its output.
+	Phases []PhaseSummary
+
+	// Time the build started
+	Started time.Time
+
+	// Builder image of the build, and its digest
+	Builder       string
+	BuilderDigest string
+
+	// Run image the app image is based on, and its digest
+	RunImage       string
+	RunImageDigest string
+
+	// Version of the lifecycle that ran the build
+	LifecycleVersion string
+
+	// Source digest of the build, when recorded
+	SourceDigest string
+
+	// Skipped is true when the build was skipped, since Image has the source digest of the build
+	Skipped bool
+}
+
+// BuildpackSummary describes a buildpack that contributed to a build
+type BuildpackSummary struct {
+	ID       string
+	Version  string
+	Homepage string
+
+	// One of CacheHit, CacheMiss or CachePartial, or empty when the buildpack has no cache layers
+	// or the lifecycle did not report on them
+	Cache string
+
+	// Digest of the layer of the buildpack in the builder, empty when unknown
+	Digest string
+
+	// Time the buildpack took to build, 0 when the lifecycle didn't log it, as it only does at debug level
+	Duration time.Duration
+}
+
+var cacheLayerMatcher = regexp.MustCompile(`(Reusing|Adding) cache layer '([^':]+):[^']*'`)
+
+// PhaseSummary describes a lifecycle phase of a build, such as detector or exporter
+type PhaseSummary struct {
+	Name     string
+	Duration time.Duration
+}
+
+// phaseHeaderMatcher matches the headers the creator writes when starting each phase, such as ===> DETECTING
+var phaseHeaderMatcher = regexp.MustCompile(`===> ([A-Z]+)`)
+
+// buildpackBuildMatcher matches the debug logs of the builder around the build of each buildpack
+var buildpackBuildMatcher = regexp.MustCompile(`(Running|Finished running) build for buildpack ([^@\s]+)@`)
+
+// creatorPhases are the phases of the headers of the creator
+var creatorPhases = map[string]string{
+	"ANALYZING": "analyzer",
+	"DETECTING": "detector",
+	"RESTORING": "restorer",
+	"EXTENDING": "extender",
+	"BUILDING":  "builder",
+	"EXPORTING": "exporter",
+}
+
+// phaseTracker times the lifecycle phases of a build. Phases running in their own container are timed by the
+// lifecycle, while the phases the creator runs in a single container are timed from the headers of its output. The
+// build of each buildpack is timed from the debug logs of the builder.
+type phaseTracker struct {
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	phases []PhaseSummary
+	now    func() time.Time
+
+	// phase of the creator in progress, and when it started
+	current string
+	started time.Time
+
+	// when the build of each buildpack started, and how long it took once finished
+	buildpackStarted   map[string]time.Time
+	buildpackDurations map[string]time.Duration
+}
+
+func newPhaseTracker() *phaseTracker {
+	return &phaseTracker{
+		now:                time.Now,
+		buildpackStarted:   map[string]time.Time{},
+		buildpackDurations: map[string]time.Duration{},
+	}
+}
+
+func (t *phaseTracker) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.buf.Write(p)
+	for {
+		line, err := t.buf.ReadString('\n')
+		if err != nil {
+			// keep the incomplete line until the rest of it is written
+			t.buf.Reset()
+			t.buf.WriteString(line)
+			return len(p), nil
+		}
+		t.handleLine(line)
+	}
+}
+
+func (t *phaseTracker) handleLine(line string) {
+	if match := buildpackBuildMatcher.FindStringSubmatch(line); match != nil {
+		if match[1] == "Running" {
+			t.buildpackStarted[match[2]] = t.now()
+		} else if started, ok := t.buildpackStarted[match[2]]; ok {
+			t.buildpackDurations[match[2]] += t.now().Sub(started)
+			delete(t.buildpackStarted, match[2])
+		}
+		return
+	}
+
+	match := phaseHeaderMatcher.FindStringSubmatch(line)
+	if match == nil {
+		return
+	}
+
+	now := t.now()
+	t.finishCurrent(now)
+	t.current = creatorPhases[match[1]]
+	if t.current == "" {
+		t.current = strings.ToLower(match[1])
+	}
+	t.started = now
+}
+
+func (t *phaseTracker) finishCurrent(now time.Time) {
+	if t.current != "" {
+		t.phases = append(t.phases, PhaseSummary{Name: t.current, Duration: now.Sub(t.started)})
+		t.current = ""
+	}
+}
+
+// observe records a phase container that ran. The creator is only recorded when none of its phases were timed.
+func (t *phaseTracker) observe(phase string, duration time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if phase == "creator" && t.current != "" {
+		t.finishCurrent(t.now())
+		return
+	}
+	// other phases don't write headers, so any header came from the lifecycle of another container
+	t.current = ""
+	t.phases = append(t.phases, PhaseSummary{Name: phase, Duration: duration})
+}
+
+// summary returns the phases that ran
+func (t *phaseTracker) summary() []PhaseSummary {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]PhaseSummary(nil), t.phases...)
+}
+
+// buildpackDuration returns the time the buildpack with the given ID took to build, 0 when unknown
+func (t *phaseTracker) buildpackDuration(id string) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.buildpackDurations[id]
+}
+
+// cacheTracker watches the lifecycle output for the cache layers reused or added by each buildpack
+type cacheTracker struct {
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	reused map[string]int
+	added  map[string]int
+}
+
+func newCacheTracker() *cacheTracker {
+	return &cacheTracker{
+		reused: map[string]int{},
+		added:  map[string]int{},
+	}
+}
+
+func (t *cacheTracker) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.buf.Write(p)
+	for {
+		line, err := t.buf.ReadString('\n')
+		if err != nil {
+			// keep the incomplete line until the rest of it is written
+			t.buf.Reset()
+			t.buf.WriteString(line)
+			return len(p), nil
+		}
+		t.handleLine(line)
+	}
+}
+
+func (t *cacheTracker) handleLine(line string) {
+	match := cacheLayerMatcher.FindStringSubmatch(line)
+	if match == nil {
+		return
+	}
+
+	if match[1] == "Reusing" {
+		t.reused[match[2]]++
+	} else {
+		t.added[match[2]]++
+	}
+}
+
+// status returns the cache status of the buildpack with the given ID
+func (t *cacheTracker) status(id string) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	reused, added := t.reused[id], t.added[id]
+	switch {
+	case reused > 0 && added > 0:
+		return CachePartial
+	case reused > 0:
+		return CacheHit
+	case added > 0:
+		return CacheMiss
+	default:
+		return ""
+	}
+}
+
+// summarize fills summary with the facts about the image built to imageRef. The digests of buildpacks are found in
+// layers, the buildpack layers of the builder.
+func (c *Client) summarize(ctx context.Context, summary *BuildSummary, publish bool, imageRef name.Reference, cache *cacheTracker, phases *phaseTracker, layers dist.ModuleLayers, started time.Time) error {
+	summary.Image = imageRef.Name()
+	summary.Started = started
+	summary.Phases = phases.summary()
+	defer func() {
+		summary.Duration = time.Since(started)
+	}()
+
+	img, err := c.imageFetcher.Fetch(ctx, imageRef.Name(), image.FetchOptions{Daemon: !publish, PullPolicy: image.PullNever})
+	if err != nil {
+		return err
+	}
+
+	id, err := img.Identifier()
+	if err != nil {
+		return err
+	}
+	summary.Digest = parseDigestFromImageID(id)
+	summary.Size = c.imageSize(ctx, publish, img)
+
+	info, err := c.InspectImage(imageRef.Name(), !publish)
+	if err != nil {
+		return err
+	}
+	if info == nil {
+		return nil
+	}
+
+	for _, bp := range info.Buildpacks {
+		summary.Buildpacks = append(summary.Buildpacks, BuildpackSummary{
+			ID:       bp.ID,
+			Version:  bp.Version,
+			Homepage: bp.Homepage,
+			Cache:    cache.status(bp.ID),
+			Digest:   layers[bp.ID][bp.Version].LayerDiffID,
+			Duration: phases.buildpackDuration(bp.ID),
+		})
+	}
+
+	if info.Processes.DefaultProcess != nil {
+		summary.DefaultProcess = info.Processes.DefaultProcess.Type
+		summary.Processes = append(summary.Processes, info.Processes.DefaultProcess.Type)
+	}
+	for _, process := range info.Processes.OtherProcesses {
+		summary.Processes = append(summary.Processes, process.Type)
+	}
+	sort.Strings(summary.Processes)
+
+	return nil
+}
+
+// imageDigest returns the digest of an image. For images of the daemon, the repo digest is preferred over the image ID,
+// as it identifies the image in its registry.
+func (c *Client) imageDigest(ctx context.Context, img imgutil.Image) string {
+	id, err := img.Identifier()
+	if err != nil {
+		return ""
+	}
+	if _, ok := id.(local.IDIdentifier); ok && c.docker != nil {
+		if inspect, _, err := c.docker.ImageInspectWithRaw(ctx, img.Name()); err == nil && len(inspect.RepoDigests) > 0 {
+			if _, digest, ok := strings.Cut(inspect.RepoDigests[0], "@"); ok {
+				return digest
+			}
+		}
+	}
+	return parseDigestFromImageID(id)
+}
+
+// imageSize returns the size of the image as reported by the daemon, or the sum of the compressed
+// layer sizes for a published image. It returns 0 when the size cannot be determined.
+func (c *Client) imageSize(ctx context.Context, publish bool, img imgutil.Image) int64 {
+	if !publish {
+		inspect, _, err := c.docker.ImageInspectWithRaw(ctx, img.Name())
+		if err != nil {
+			return 0
+		}
+		return inspect.Size
+	}
+
+	if img.UnderlyingImage() == nil {
+		return 0
+	}
+	manifest, err := img.UnderlyingImage().Manifest()
+	if err != nil {
+		return 0
+	}
+
+	size := manifest.Config.Size
+	for _, layer := range manifest.Layers {
+		size += layer.Size
+	}
+	return size
+}
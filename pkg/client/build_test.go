@@ -8,13 +8,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/BurntSushi/toml"
 	"github.com/Masterminds/semver"
 	"github.com/buildpacks/imgutil"
 	"github.com/buildpacks/imgutil/fakes"
@@ -23,9 +26,12 @@ import (
 	"github.com/buildpacks/lifecycle/api"
 	"github.com/buildpacks/lifecycle/platform/files"
 	dockerclient "github.com/docker/docker/client"
+	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/heroku/color"
 	"github.com/onsi/gomega/ghttp"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
 	"github.com/sclevine/spec"
 	"github.com/sclevine/spec/report"
 
@@ -34,8 +40,10 @@ import (
 	ifakes "github.com/buildpacks/pack/internal/fakes"
 	rg "github.com/buildpacks/pack/internal/registry"
 	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/archive"
 	"github.com/buildpacks/pack/pkg/blob"
 	"github.com/buildpacks/pack/pkg/buildpack"
+	"github.com/buildpacks/pack/pkg/cache"
 	"github.com/buildpacks/pack/pkg/dist"
 	"github.com/buildpacks/pack/pkg/image"
 	"github.com/buildpacks/pack/pkg/logging"
@@ -173,6 +181,457 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("resource limits", func() {
+			it("passes them to the lifecycle", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Builder:         defaultBuilderName,
+					Image:           "example.com/some/repo:tag",
+					ContainerConfig: ContainerConfig{Memory: 1024, CPUs: 0.5, PidsLimit: 64},
+				}))
+				h.AssertEq(t, fakeLifecycle.Opts.Memory, int64(1024))
+				h.AssertEq(t, fakeLifecycle.Opts.NanoCPUs, int64(500000000))
+				h.AssertEq(t, fakeLifecycle.Opts.PidsLimit, int64(64))
+			})
+		})
+
+		when("hardening options", func() {
+			it("passes them to the lifecycle", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Builder: defaultBuilderName,
+					Image:   "example.com/some/repo:tag",
+					ContainerConfig: ContainerConfig{
+						ReadOnly:    true,
+						CapAdd:      []string{"NET_RAW"},
+						CapDrop:     []string{"SYS_CHROOT"},
+						SecurityOpt: []string{"seccomp=profile.json"},
+					},
+				}))
+				h.AssertEq(t, fakeLifecycle.Opts.ReadOnly, true)
+				h.AssertEq(t, fakeLifecycle.Opts.CapAdd, []string{"NET_RAW"})
+				h.AssertEq(t, fakeLifecycle.Opts.CapDrop, []string{"SYS_CHROOT"})
+				h.AssertEq(t, fakeLifecycle.Opts.SecurityOpt, []string{"seccomp=profile.json"})
+			})
+
+			when("building for Windows", func() {
+				it("warns that they are ignored", func() {
+					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+						Builder:         defaultWindowsBuilderName,
+						Image:           "example.com/some/repo:tag",
+						ContainerConfig: ContainerConfig{ReadOnly: true},
+					}))
+					h.AssertContains(t, outBuf.String(), "Ignoring the read-only, capabilities and security options of the build containers")
+				})
+			})
+		})
+
+		when("user namespace remapping", func() {
+			it("restores the ownership of volumes when set", func() {
+				remap := true
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Builder:         defaultBuilderName,
+					Image:           "example.com/some/repo:tag",
+					ContainerConfig: ContainerConfig{UsernsRemap: &remap},
+					Cache: cache.CacheOpts{
+						Build: cache.CacheInfo{Format: cache.CacheBind, Source: "/some/cache"},
+					},
+				}))
+				h.AssertEq(t, fakeLifecycle.Opts.UsernsRemap, true)
+				h.AssertContains(t, outBuf.String(), "the bind-mounted cache '/some/cache' must be writable by the remapped user of the builder")
+			})
+
+			it("doesn't apply to Windows builds", func() {
+				remap := true
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Builder:         defaultWindowsBuilderName,
+					Image:           "example.com/some/repo:tag",
+					ContainerConfig: ContainerConfig{UsernsRemap: &remap},
+				}))
+				h.AssertEq(t, fakeLifecycle.Opts.UsernsRemap, false)
+			})
+		})
+
+		when("LifecycleLogLevel and LifecycleEnv options", func() {
+			it("passes them to the lifecycle", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Builder:           defaultBuilderName,
+					Image:             "example.com/some/repo:tag",
+					LifecycleLogLevel: "warn",
+					LifecycleEnv: map[string]string{
+						"SOME_KEY":              "some-value",
+						"CNB_EXPERIMENTAL_MODE": "warn",
+					},
+				}))
+				h.AssertEq(t, fakeLifecycle.Opts.LogLevel, "warn")
+				h.AssertEq(t, fakeLifecycle.Opts.LifecycleEnv, []string{"CNB_EXPERIMENTAL_MODE=warn", "SOME_KEY=some-value"})
+			})
+		})
+
+		when("Logger option", func() {
+			it("logs the build and passes the logger to the lifecycle", func() {
+				var buildOut bytes.Buffer
+				buildLogger := logging.NewLogWithWriters(&buildOut, &buildOut, logging.WithVerbose())
+
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Builder: defaultBuilderName,
+					Image:   "example.com/some/repo:tag",
+					Logger:  buildLogger,
+				}))
+				h.AssertTrue(t, fakeLifecycle.Opts.Logger == logging.Logger(buildLogger))
+				h.AssertNotEq(t, buildOut.String(), "")
+			})
+		})
+
+		when("Summary option", func() {
+			var builtImage *fakes.Image
+
+			it.Before(func() {
+				builtImage = fakes.NewImage("index.docker.io/some/app:latest", "", local.IDIdentifier{
+					ImageID: "363c754893f0efe22480b4359a5956cf3bd3ce22742fc576973c61348308c2e4",
+				})
+				h.AssertNil(t, builtImage.SetLabel("io.buildpacks.build.metadata", `{
+  "buildpacks": [
+    {"id": "some/buildpack", "version": "1.2.3", "homepage": "https://example.com/some/buildpack"},
+    {"id": "other/buildpack", "version": "4.5.6"}
+  ],
+  "processes": [
+    {"type": "worker", "command": "/worker"},
+    {"type": "web", "command": "/web"}
+  ]
+}`))
+				h.AssertNil(t, builtImage.SetEntrypoint("/cnb/process/web"))
+				fakeImageFetcher.LocalImages[builtImage.Name()] = builtImage
+			})
+
+			it.After(func() {
+				h.AssertNilE(t, builtImage.Cleanup())
+			})
+
+			it("fills the summary of the built image", func() {
+				var summary BuildSummary
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+					AppPath: filepath.Join("testdata", "some-app"),
+					Summary: &summary,
+				}))
+
+				h.AssertNotNil(t, fakeLifecycle.Opts.OutputObserver)
+				h.AssertTrue(t, fakeLifecycle.Opts.PhaseObserver != nil)
+				h.AssertEq(t, summary.Image, "index.docker.io/some/app:latest")
+				h.AssertEq(t, summary.Digest, "sha256:363c754893f0efe22480b4359a5956cf3bd3ce22742fc576973c61348308c2e4")
+				h.AssertEq(t, summary.Buildpacks, []BuildpackSummary{
+					{ID: "some/buildpack", Version: "1.2.3", Homepage: "https://example.com/some/buildpack"},
+					{ID: "other/buildpack", Version: "4.5.6"},
+				})
+				h.AssertEq(t, summary.Processes, []string{"web", "worker"})
+				h.AssertEq(t, summary.DefaultProcess, "web")
+				h.AssertTrue(t, summary.Duration > 0)
+				h.AssertEq(t, summary.Builder, defaultBuilderName)
+				h.AssertNotEq(t, summary.BuilderDigest, "")
+				h.AssertEq(t, summary.RunImage, defaultRunImageName)
+				h.AssertNotEq(t, summary.RunImageDigest, "")
+				h.AssertEq(t, summary.LifecycleVersion, builder.DefaultLifecycleVersion)
+				h.AssertFalse(t, summary.Started.IsZero())
+			})
+
+			it("does not observe the lifecycle output when not set", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+					AppPath: filepath.Join("testdata", "some-app"),
+				}))
+
+				h.AssertNil(t, fakeLifecycle.Opts.OutputObserver)
+				h.AssertTrue(t, fakeLifecycle.Opts.PhaseObserver == nil)
+			})
+
+			it("tracks the cache layers reused and added by each buildpack", func() {
+				tracker := newCacheTracker()
+				_, err := tracker.Write([]byte("[exporter] Reusing cache layer 'some/buildpack:deps'\n[exporter] Adding cache layer 'other/buildpack:"))
+				h.AssertNil(t, err)
+				_, err = tracker.Write([]byte("build'\n[exporter] Reusing cache layer 'third/buildpack:a'\n[exporter] Adding cache layer 'third/buildpack:b'\n"))
+				h.AssertNil(t, err)
+
+				h.AssertEq(t, tracker.status("some/buildpack"), CacheHit)
+				h.AssertEq(t, tracker.status("other/buildpack"), CacheMiss)
+				h.AssertEq(t, tracker.status("third/buildpack"), CachePartial)
+				h.AssertEq(t, tracker.status("unknown/buildpack"), "")
+			})
+
+			when("phases", func() {
+				var (
+					tracker *phaseTracker
+					now     time.Time
+				)
+
+				it.Before(func() {
+					tracker = newPhaseTracker()
+					now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+					tracker.now = func() time.Time {
+						return now
+					}
+				})
+
+				it("records the phases run in separate containers", func() {
+					tracker.observe("analyzer", time.Second)
+					tracker.observe("detector", 2*time.Second)
+					tracker.observe("exporter", 3*time.Second)
+
+					h.AssertEq(t, tracker.summary(), []PhaseSummary{
+						{Name: "analyzer", Duration: time.Second},
+						{Name: "detector", Duration: 2 * time.Second},
+						{Name: "exporter", Duration: 3 * time.Second},
+					})
+				})
+
+				it("times the phases of the creator from its headers", func() {
+					_, err := tracker.Write([]byte("[creator] ===> ANALYZING\n"))
+					h.AssertNil(t, err)
+					now = now.Add(time.Second)
+					_, err = tracker.Write([]byte("[creator] ===> DETE"))
+					h.AssertNil(t, err)
+					_, err = tracker.Write([]byte("CTING\n[creator] some/buildpack 1.2.3\n"))
+					h.AssertNil(t, err)
+					now = now.Add(2 * time.Second)
+					_, err = tracker.Write([]byte("[creator] ===> EXPORTING\n"))
+					h.AssertNil(t, err)
+					now = now.Add(3 * time.Second)
+					tracker.observe("creator", 7*time.Second)
+
+					h.AssertEq(t, tracker.summary(), []PhaseSummary{
+						{Name: "analyzer", Duration: time.Second},
+						{Name: "detector", Duration: 2 * time.Second},
+						{Name: "exporter", Duration: 3 * time.Second},
+					})
+				})
+
+				it("times the build of each buildpack from the debug logs of the builder", func() {
+					_, err := tracker.Write([]byte("[builder] Running build for buildpack some/buildpack@1.2.3\n"))
+					h.AssertNil(t, err)
+					now = now.Add(4 * time.Second)
+					_, err = tracker.Write([]byte("[builder] Finished running build for buildpack some/buildpack@1.2.3\n[builder] Running build for buildpack other/buildpack@4.5.6\n"))
+					h.AssertNil(t, err)
+
+					h.AssertEq(t, tracker.buildpackDuration("some/buildpack"), 4*time.Second)
+					h.AssertEq(t, tracker.buildpackDuration("other/buildpack"), time.Duration(0))
+				})
+
+				it("records the creator when it wrote no headers", func() {
+					tracker.observe("creator", 7*time.Second)
+
+					h.AssertEq(t, tracker.summary(), []PhaseSummary{{Name: "creator", Duration: 7 * time.Second}})
+				})
+			})
+		})
+
+		when("CheckPlan option", func() {
+			it("is called with the resolved plan of the build", func() {
+				var plan BuildPlan
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+					AppPath: filepath.Join("testdata", "some-app"),
+					CheckPlan: func(p BuildPlan) error {
+						plan = p
+						return nil
+					},
+				}))
+
+				h.AssertEq(t, plan, BuildPlan{
+					Builder:    defaultBuilderName,
+					RunImage:   defaultRunImageName,
+					Image:      "index.docker.io/some/app:latest",
+					Buildpacks: []string{"buildpack.1.id@buildpack.1.version", "buildpack.2.id@buildpack.2.version"},
+				})
+			})
+
+			it("stops the build when the plan is rejected", func() {
+				err := subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+					AppPath: filepath.Join("testdata", "some-app"),
+					CheckPlan: func(BuildPlan) error {
+						return errors.New("rejected")
+					},
+				})
+
+				h.AssertError(t, err, "rejected")
+				h.AssertNil(t, fakeLifecycle.Opts.Builder)
+			})
+		})
+
+		when("source digest options", func() {
+			var appDir string
+
+			it.Before(func() {
+				appDir = filepath.Join(tmpDir, "digest-app")
+				h.AssertNil(t, os.MkdirAll(appDir, 0755))
+				h.AssertNil(t, os.WriteFile(filepath.Join(appDir, "main.go"), []byte("package main"), 0600))
+			})
+
+			// recordedDigest builds the app and returns the source digest recorded in the project metadata
+			recordedDigest := func() string {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:              "some/app",
+					Builder:            defaultBuilderName,
+					AppPath:            appDir,
+					RecordSourceDigest: true,
+				}))
+				h.AssertNotNil(t, fakeLifecycle.Opts.ProjectMetadata.Source)
+				digest, ok := fakeLifecycle.Opts.ProjectMetadata.Source.Metadata["sourceDigest"].(string)
+				h.AssertTrue(t, ok)
+				return digest
+			}
+
+			// withPreviousImage adds the image that the app was built into, with digest as its source digest
+			withPreviousImage := func(digest string) {
+				previousImage := newLinuxImage("index.docker.io/some/app:latest", "", nil)
+				metadata, err := json.Marshal(files.ProjectMetadata{Source: &files.ProjectSource{
+					Type:     "directory",
+					Metadata: map[string]interface{}{"sourceDigest": digest},
+				}})
+				h.AssertNil(t, err)
+				h.AssertNil(t, previousImage.SetLabel("io.buildpacks.project.metadata", string(metadata)))
+				fakeImageFetcher.LocalImages[previousImage.Name()] = previousImage
+			}
+
+			it("records the source digest of the build", func() {
+				digest := recordedDigest()
+				h.AssertContains(t, digest, "sha256:")
+				h.AssertEq(t, recordedDigest(), digest)
+
+				h.AssertNil(t, os.WriteFile(filepath.Join(appDir, "main.go"), []byte("package main\n\nfunc main() {}"), 0600))
+				h.AssertNotEq(t, recordedDigest(), digest)
+			})
+
+			it("leaves out files that aren't added to the image", func() {
+				digest := recordedDigest()
+				h.AssertNil(t, os.WriteFile(filepath.Join(appDir, "notes.txt"), []byte("notes"), 0600))
+
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:              "some/app",
+					Builder:            defaultBuilderName,
+					AppPath:            appDir,
+					RecordSourceDigest: true,
+					ProjectDescriptor: projectTypes.Descriptor{
+						Build: projectTypes.Build{Exclude: []string{"notes.txt"}},
+					},
+				}))
+				excludedDigest := fakeLifecycle.Opts.ProjectMetadata.Source.Metadata["sourceDigest"]
+				h.AssertNotEq(t, excludedDigest, digest)
+
+				h.AssertNil(t, os.WriteFile(filepath.Join(appDir, "notes.txt"), []byte("more notes"), 0600))
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:              "some/app",
+					Builder:            defaultBuilderName,
+					AppPath:            appDir,
+					RecordSourceDigest: true,
+					ProjectDescriptor: projectTypes.Descriptor{
+						Build: projectTypes.Build{Exclude: []string{"notes.txt"}},
+					},
+				}))
+				h.AssertEq(t, fakeLifecycle.Opts.ProjectMetadata.Source.Metadata["sourceDigest"], excludedDigest)
+			})
+
+			it("skips the build when the image has the source digest of the build", func() {
+				withPreviousImage(recordedDigest())
+				*fakeLifecycle = ifakes.FakeLifecycle{}
+
+				var summary BuildSummary
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:         "some/app",
+					Builder:       defaultBuilderName,
+					AppPath:       appDir,
+					SkipUnchanged: true,
+					Summary:       &summary,
+				}))
+				h.AssertNil(t, fakeLifecycle.Opts.Builder)
+				h.AssertTrue(t, summary.Skipped)
+				h.AssertEq(t, summary.Image, "index.docker.io/some/app:latest")
+				h.AssertContains(t, outBuf.String(), "Skipping the build, 'index.docker.io/some/app:latest' was built from the same source and configuration")
+			})
+
+			it("builds when the source changed", func() {
+				withPreviousImage(recordedDigest())
+				*fakeLifecycle = ifakes.FakeLifecycle{}
+				h.AssertNil(t, os.WriteFile(filepath.Join(appDir, "main.go"), []byte("package main\n\nfunc main() {}"), 0600))
+
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:         "some/app",
+					Builder:       defaultBuilderName,
+					AppPath:       appDir,
+					SkipUnchanged: true,
+				}))
+				h.AssertNotNil(t, fakeLifecycle.Opts.Builder)
+			})
+
+			it("builds when a creation time is set", func() {
+				withPreviousImage(recordedDigest())
+				*fakeLifecycle = ifakes.FakeLifecycle{}
+
+				creationTime := time.Unix(1566172801, 0)
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:         "some/app",
+					Builder:       defaultBuilderName,
+					AppPath:       appDir,
+					SkipUnchanged: true,
+					CreationTime:  &creationTime,
+				}))
+				h.AssertNotNil(t, fakeLifecycle.Opts.Builder)
+			})
+
+			it("covers the options changing the image", func() {
+				digest := recordedDigest()
+
+				for _, opts := range []BuildOptions{
+					{LifecycleEnv: map[string]string{"CNB_EXPERIMENTAL_MODE": "warn"}},
+					{ContainerConfig: ContainerConfig{Network: "host"}},
+					{CacheImage: "some/cache"},
+				} {
+					opts.Image = "some/app"
+					opts.Builder = defaultBuilderName
+					opts.AppPath = appDir
+					opts.RecordSourceDigest = true
+					h.AssertNil(t, subject.Build(context.TODO(), opts))
+					h.AssertNotEq(t, fakeLifecycle.Opts.ProjectMetadata.Source.Metadata["sourceDigest"], digest)
+				}
+			})
+
+			it("covers the content of the buildpacks", func() {
+				buildpackDir := filepath.Join(tmpDir, "digest-buildpack")
+				h.AssertNil(t, os.MkdirAll(buildpackDir, 0755))
+				h.RecursiveCopy(t, filepath.Join("testdata", "buildpack"), buildpackDir)
+				builderImage := fakes.NewImage("some/builder", "", local.IDIdentifier{ImageID: "builder-id"})
+				runImage := fakes.NewImage("some/run", "", local.IDIdentifier{ImageID: "run-id"})
+				buildpackDigest := func() string {
+					bp, err := buildpack.FromBuildpackRootBlob(blob.NewBlob(buildpackDir), archive.DefaultTarWriterFactory(), nil)
+					h.AssertNil(t, err)
+					digest, err := subject.buildSourceDigest(appDir, BuildOptions{Buildpacks: []string{buildpackDir}}, builderImage, runImage, "some/run", []buildpack.BuildModule{bp})
+					h.AssertNil(t, err)
+					return digest
+				}
+
+				digest := buildpackDigest()
+				h.AssertEq(t, buildpackDigest(), digest)
+				h.AssertNil(t, os.WriteFile(filepath.Join(buildpackDir, "bin", "build"), []byte("#!/usr/bin/env bash\necho changed"), 0755))
+				h.AssertNotEq(t, buildpackDigest(), digest)
+			})
+
+			it("builds when the cache is cleared", func() {
+				withPreviousImage(recordedDigest())
+				*fakeLifecycle = ifakes.FakeLifecycle{}
+
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:         "some/app",
+					Builder:       defaultBuilderName,
+					AppPath:       appDir,
+					SkipUnchanged: true,
+					ClearCache:    true,
+				}))
+				h.AssertNotNil(t, fakeLifecycle.Opts.Builder)
+			})
+		})
+
 		when("Image option", func() {
 			it("is required", func() {
 				h.AssertError(t, subject.Build(context.TODO(), BuildOptions{
@@ -672,6 +1131,74 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("ClearBuildCache, ClearLaunchCache and ClearBuildpackCaches options", func() {
+			it("passes them through to lifecycle", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:                "some/app",
+					Builder:              defaultBuilderName,
+					ClearBuildCache:      true,
+					ClearLaunchCache:     true,
+					ClearBuildpackCaches: []string{"some-buildpack-id"},
+				}))
+				h.AssertEq(t, fakeLifecycle.Opts.ClearBuildCache, true)
+				h.AssertEq(t, fakeLifecycle.Opts.ClearLaunchCache, true)
+				h.AssertEq(t, fakeLifecycle.Opts.ClearBuildpackCaches, []string{"some-buildpack-id"})
+				h.AssertEq(t, fakeLifecycle.Opts.ClearCache, false)
+			})
+
+			it("errors when the build cache is an image", func() {
+				err := subject.Build(context.TODO(), BuildOptions{
+					Image:                "some/app",
+					Builder:              defaultBuilderName,
+					Publish:              true,
+					CacheImage:           "some/cache-image",
+					ClearBuildpackCaches: []string{"some-buildpack-id"},
+				})
+				h.AssertError(t, err, "the build cache must be a volume or bind to be cleared apart")
+			})
+		})
+
+		when("CacheSizeLimit option", func() {
+			it("passes it through to lifecycle", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:          "some/app",
+					Builder:        defaultBuilderName,
+					CacheSizeLimit: 1024,
+				}))
+				h.AssertEq(t, fakeLifecycle.Opts.CacheSizeLimit, int64(1024))
+			})
+		})
+
+		when("LayerCompression option", func() {
+			it("requires publishing the image for zstd layers", func() {
+				err := subject.Build(context.TODO(), BuildOptions{
+					Image:            "some/app",
+					Builder:          defaultBuilderName,
+					LayerCompression: ZstdCompression,
+				})
+				h.AssertError(t, err, "layers can only be compressed with zstd when publishing the image to a registry")
+			})
+		})
+
+		when("SerialExport option", func() {
+			it("exports in parallel by default", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+				}))
+				h.AssertEq(t, fakeLifecycle.Opts.ParallelExport, true)
+			})
+
+			it("exports serially when set", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:        "some/app",
+					Builder:      defaultBuilderName,
+					SerialExport: true,
+				}))
+				h.AssertEq(t, fakeLifecycle.Opts.ParallelExport, false)
+			})
+		})
+
 		when("ImageCache option", func() {
 			it("passes it through to lifecycle", func() {
 				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
@@ -1243,6 +1770,32 @@ api = "0.2"
 `)
 					})
 				})
+
+				it("overrides the builder order with the declared group, keeping optional flags", func() {
+					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+						Image:      "some/app",
+						Builder:    defaultBuilderName,
+						ClearCache: true,
+						ProjectDescriptor: projectTypes.Descriptor{
+							Build: projectTypes.Build{Buildpacks: []projectTypes.Buildpack{
+								{ID: "buildpack.2.id", Version: "buildpack.2.version", Optional: true},
+								{ID: "buildpack.1.id", Version: "buildpack.1.version"},
+							}},
+						},
+					}))
+
+					assertOrderEquals(`[[order]]
+
+  [[order.group]]
+    id = "buildpack.2.id"
+    version = "buildpack.2.version"
+    optional = true
+
+  [[order.group]]
+    id = "buildpack.1.id"
+    version = "buildpack.1.version"
+`)
+				})
 			})
 
 			when("buildpacks include URIs", func() {
@@ -1624,19 +2177,155 @@ api = "0.2"
 							ProjectDescriptorBaseDir: tmpDir,
 						})
 
-						h.AssertNil(t, err)
-						h.AssertEq(t, fakeLifecycle.Opts.Builder.Name(), defaultBuilderImage.Name())
-						bldr, err := builder.FromImage(defaultBuilderImage)
-						h.AssertNil(t, err)
-						h.AssertEq(t, bldr.Order(), dist.Order{
-							{Group: []dist.ModuleRef{
-								{ModuleInfo: dist.ModuleInfo{ID: "my/inline", Version: "1.0.0-my-version"}},
-							}},
+						h.AssertNil(t, err)
+						h.AssertEq(t, fakeLifecycle.Opts.Builder.Name(), defaultBuilderImage.Name())
+						bldr, err := builder.FromImage(defaultBuilderImage)
+						h.AssertNil(t, err)
+						h.AssertEq(t, bldr.Order(), dist.Order{
+							{Group: []dist.ModuleRef{
+								{ModuleInfo: dist.ModuleInfo{ID: "my/inline", Version: "1.0.0-my-version"}},
+							}},
+						})
+						h.AssertEq(t, bldr.Buildpacks(), []dist.ModuleInfo{
+							{ID: "buildpack.1.id", Version: "buildpack.1.version"},
+							{ID: "buildpack.2.id", Version: "buildpack.2.version"},
+							{ID: "my/inline", Version: "1.0.0-my-version"},
+						})
+					})
+
+					it("adds buildpacks from script files", func() {
+						h.AssertNil(t, os.MkdirAll(filepath.Join(tmpDir, "scripts"), 0755))
+						h.AssertNil(t, os.WriteFile(filepath.Join(tmpDir, "scripts", "build.sh"), []byte("touch foo.txt\n"), 0600))
+
+						err := subject.Build(context.TODO(), BuildOptions{
+							Image:      "some/app",
+							Builder:    defaultBuilderName,
+							ClearCache: true,
+							ProjectDescriptor: projectTypes.Descriptor{
+								Build: projectTypes.Build{
+									Buildpacks: []projectTypes.Buildpack{{
+										ID: "my/inline",
+										Script: projectTypes.Script{
+											API:   "0.4",
+											Shell: "bash",
+											Files: []string{"scripts/build.sh"},
+										},
+									}},
+								},
+							},
+							ProjectDescriptorBaseDir: tmpDir,
+						})
+
+						h.AssertNil(t, err)
+						bldr, err := builder.FromImage(defaultBuilderImage)
+						h.AssertNil(t, err)
+						h.AssertEq(t, bldr.Order(), dist.Order{
+							{Group: []dist.ModuleRef{
+								{ModuleInfo: dist.ModuleInfo{ID: "my/inline", Version: "0.0.0"}},
+							}},
+						})
+					})
+
+					it("fails if a script file is missing", func() {
+						err := subject.Build(context.TODO(), BuildOptions{
+							Image:      "some/app",
+							Builder:    defaultBuilderName,
+							ClearCache: true,
+							ProjectDescriptor: projectTypes.Descriptor{
+								Build: projectTypes.Build{
+									Buildpacks: []projectTypes.Buildpack{{
+										ID: "my/inline",
+										Script: projectTypes.Script{
+											API:   "0.4",
+											Files: []string{"missing.sh"},
+										},
+									}},
+								},
+							},
+							ProjectDescriptorBaseDir: tmpDir,
+						})
+
+						h.AssertError(t, err, "reading script file 'missing.sh'")
+					})
+
+					when("#createInlineBuildpack", func() {
+						readBin := func(path, name string) string {
+							contents, err := os.ReadFile(filepath.Join(path, "bin", name))
+							h.AssertNil(t, err)
+							return string(contents)
+						}
+
+						it("joins the inline script and the script files", func() {
+							h.AssertNil(t, os.WriteFile(filepath.Join(tmpDir, "a.sh"), []byte("echo a\n"), 0600))
+							h.AssertNil(t, os.WriteFile(filepath.Join(tmpDir, "b.sh"), []byte("echo b\n"), 0600))
+
+							path, err := createInlineBuildpack(projectTypes.Buildpack{
+								ID:     "my/inline",
+								Script: projectTypes.Script{API: "0.4", Inline: "set -e", Files: []string{"a.sh", "b.sh"}, Shell: "bash"},
+							}, "some.stack.id", tmpDir, "linux")
+							h.AssertNil(t, err)
+							defer os.RemoveAll(path)
+
+							h.AssertEq(t, readBin(path, "build"), "#!/usr/bin/env bash\n\nset -e\necho a\necho b\n")
+							h.AssertEq(t, readBin(path, "detect"), "#!/usr/bin/env bash\n\nexit 0\n")
+							h.AssertPathDoesNotExists(t, filepath.Join(path, "bin", "build.bat"))
+						})
+
+						it("defaults to sh and keeps absolute interpreter paths", func() {
+							path, err := createInlineBuildpack(projectTypes.Buildpack{
+								ID:     "my/inline",
+								Script: projectTypes.Script{API: "0.4", Inline: "echo hi"},
+							}, "some.stack.id", tmpDir, "linux")
+							h.AssertNil(t, err)
+							defer os.RemoveAll(path)
+							h.AssertEq(t, readBin(path, "build"), "#!/bin/sh\n\necho hi\n")
+
+							path, err = createInlineBuildpack(projectTypes.Buildpack{
+								ID:     "my/inline",
+								Script: projectTypes.Script{API: "0.4", Inline: "echo hi", Shell: "/bin/zsh"},
+							}, "some.stack.id", tmpDir, "linux")
+							h.AssertNil(t, err)
+							defer os.RemoveAll(path)
+							h.AssertEq(t, readBin(path, "build"), "#!/bin/zsh\n\necho hi\n")
+						})
+
+						it("generates batch files for Windows builds", func() {
+							path, err := createInlineBuildpack(projectTypes.Buildpack{
+								ID:     "my/inline",
+								Script: projectTypes.Script{API: "0.4", Inline: "echo a\necho b"},
+							}, "some.stack.id", tmpDir, "windows")
+							h.AssertNil(t, err)
+							defer os.RemoveAll(path)
+
+							h.AssertEq(t, readBin(path, "build.bat"), "@echo off\r\necho a\r\necho b\r\n")
+							h.AssertEq(t, readBin(path, "detect.bat"), "@exit /b 0\r\n")
+							h.AssertPathDoesNotExists(t, filepath.Join(path, "bin", "build"))
+						})
+
+						it("runs powershell scripts on Windows builds", func() {
+							path, err := createInlineBuildpack(projectTypes.Buildpack{
+								ID:     "my/inline",
+								Script: projectTypes.Script{API: "0.4", Inline: "Write-Host hi", Shell: "powershell"},
+							}, "some.stack.id", tmpDir, "windows")
+							h.AssertNil(t, err)
+							defer os.RemoveAll(path)
+
+							h.AssertEq(t, readBin(path, "build.ps1"), "Write-Host hi\r\n")
+							h.AssertContains(t, readBin(path, "build.bat"), `-File "%~dp0build.ps1"`)
 						})
-						h.AssertEq(t, bldr.Buildpacks(), []dist.ModuleInfo{
-							{ID: "buildpack.1.id", Version: "buildpack.1.version"},
-							{ID: "buildpack.2.id", Version: "buildpack.2.version"},
-							{ID: "my/inline", Version: "1.0.0-my-version"},
+
+						it("fails for shells not available on the target OS", func() {
+							_, err := createInlineBuildpack(projectTypes.Buildpack{
+								ID:     "my/inline",
+								Script: projectTypes.Script{API: "0.4", Inline: "echo hi", Shell: "bash"},
+							}, "some.stack.id", tmpDir, "windows")
+							h.AssertError(t, err, "shell 'bash' is not supported for Windows builds")
+
+							_, err = createInlineBuildpack(projectTypes.Buildpack{
+								ID:     "my/inline",
+								Script: projectTypes.Script{API: "0.4", Inline: "echo hi", Shell: "cmd"},
+							}, "some.stack.id", tmpDir, "linux")
+							h.AssertError(t, err, "shell 'cmd' is only supported for Windows builds")
 						})
 					})
 
@@ -1987,6 +2676,27 @@ api = "0.2"
 			})
 		})
 
+		when("Labels and Annotations options", func() {
+			it("fails for labels reserved by buildpacks", func() {
+				err := subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+					Labels:  map[string]string{"io.buildpacks.build.metadata": "{}"},
+				})
+				h.AssertError(t, err, "label 'io.buildpacks.build.metadata' is reserved")
+				h.AssertEq(t, fakeLifecycle.Opts.Image, nil)
+			})
+
+			it("fails for annotations without a name", func() {
+				err := subject.Build(context.TODO(), BuildOptions{
+					Image:       "some/app",
+					Builder:     defaultBuilderName,
+					Annotations: map[string]string{"": "some-value"},
+				})
+				h.AssertError(t, err, "annotations must have a name")
+			})
+		})
+
 		when("Publish option", func() {
 			var remoteRunImage, builderWithoutLifecycleImageOrCreator *fakes.Image
 
@@ -2075,6 +2785,86 @@ api = "0.2"
 					})
 				})
 
+				when("the creator is forced", func() {
+					it("uses the creator with an untrusted builder", func() {
+						useCreator := true
+						h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+							Image:        "some/app",
+							Builder:      defaultBuilderName,
+							Publish:      true,
+							TrustBuilder: func(string) bool { return false },
+							UseCreator:   &useCreator,
+						}))
+						h.AssertEq(t, fakeLifecycle.Opts.UseCreator, true)
+						h.AssertNil(t, fakeImageFetcher.FetchCalls[fakeLifecycleImage.Name()])
+						h.AssertContains(t, outBuf.String(), "Running the creator with untrusted builder")
+					})
+
+					it("uses the creator when additional buildpacks are untrusted", func() {
+						additionalBP := ifakes.CreateBuildpackTar(t, tmpDir, dist.BuildpackDescriptor{
+							WithAPI: api.MustParse("0.3"),
+							WithInfo: dist.ModuleInfo{
+								ID:      "buildpack.add.1.id",
+								Version: "buildpack.add.1.version",
+							},
+							WithStacks: []dist.Stack{{ID: defaultBuilderStackID}},
+							WithOrder:  nil,
+						})
+						useCreator := true
+
+						h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+							Image:        "some/app",
+							Builder:      defaultBuilderName,
+							Publish:      true,
+							TrustBuilder: func(string) bool { return true },
+							Buildpacks:   []string{additionalBP},
+							UseCreator:   &useCreator,
+						}))
+						h.AssertEq(t, fakeLifecycle.Opts.UseCreator, true)
+						h.AssertNotContains(t, outBuf.String(), "using the untrusted (5 phases) build flow")
+					})
+
+					it("errors when the lifecycle doesn't support the creator", func() {
+						useCreator := true
+						err := subject.Build(context.TODO(), BuildOptions{
+							Image:        "some/app",
+							Builder:      builderWithoutLifecycleImageOrCreator.Name(),
+							Publish:      true,
+							TrustBuilder: func(string) bool { return true },
+							UseCreator:   &useCreator,
+						})
+						h.AssertError(t, err, "doesn't support the creator, it must be at least '0.7.4'")
+					})
+				})
+
+				when("the creator is disabled", func() {
+					it("uses the 5 phases with the lifecycle image for a trusted builder", func() {
+						useCreator := false
+						h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+							Image:        "some/app",
+							Builder:      defaultBuilderName,
+							Publish:      true,
+							TrustBuilder: func(string) bool { return true },
+							UseCreator:   &useCreator,
+						}))
+						h.AssertEq(t, fakeLifecycle.Opts.UseCreator, false)
+						h.AssertEq(t, fakeLifecycle.Opts.TrustBuilder, true)
+						h.AssertContains(t, fakeLifecycle.Opts.LifecycleImage, "pack.local/lifecycle")
+					})
+
+					it("errors for daemonless builds", func() {
+						useCreator := false
+						err := subject.Build(context.TODO(), BuildOptions{
+							Image:      "some/app",
+							Builder:    defaultBuilderName,
+							Publish:    true,
+							Daemonless: true,
+							UseCreator: &useCreator,
+						})
+						h.AssertError(t, err, "daemonless and kubernetes builds always run the creator")
+					})
+				})
+
 				when("builder is trusted", func() {
 					when("lifecycle supports creator", func() {
 						it("uses the creator with the provided builder", func() {
@@ -2339,6 +3129,17 @@ api = "0.2"
 					h.AssertEq(t, args.PullPolicy, image.PullAlways)
 					h.AssertEq(t, args.Target.ValuesAsPlatform(), "linux/arm64")
 				})
+
+				it("creates the lifecycle containers for the provided platform", func() {
+					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+						Image:      "some/app",
+						Builder:    defaultBuilderName,
+						Platform:   "linux/arm64/v8",
+						PullPolicy: image.PullAlways,
+					}))
+
+					h.AssertEq(t, fakeLifecycle.Opts.Platform, &specs.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"})
+				})
 			})
 
 			when("not provided", func() {
@@ -2374,6 +3175,15 @@ api = "0.2"
 					h.AssertEq(t, args.PullPolicy, image.PullAlways)
 					h.AssertEq(t, args.Target.ValuesAsPlatform(), "linux/amd64")
 				})
+
+				it("creates the lifecycle containers for the platform of the daemon", func() {
+					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+						Image:   "some/app",
+						Builder: defaultBuilderName,
+					}))
+
+					h.AssertNil(t, fakeLifecycle.Opts.Platform)
+				})
 			})
 		})
 
@@ -2401,6 +3211,30 @@ api = "0.2"
 				})
 			})
 
+			when("per image", func() {
+				it("uses the pull policy of each image, defaulting to the pull policy of the build", func() {
+					fakePackage := makeFakePackage(t, tmpDir, defaultBuilderStackID)
+					fakeImageFetcher.LocalImages[fakePackage.Name()] = fakePackage
+					runImagePolicy, buildpacksPolicy := image.PullAlways, image.PullIfNotPresent
+
+					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+						Image:      "some/app",
+						Builder:    defaultBuilderName,
+						Buildpacks: []string{"example.com/some/package"},
+						PullPolicy: image.PullNever,
+						ImagePullPolicies: ImagePullPolicies{
+							RunImage:   &runImagePolicy,
+							Buildpacks: &buildpacksPolicy,
+						},
+					}))
+
+					h.AssertEq(t, fakeImageFetcher.FetchCalls[defaultBuilderName].PullPolicy, image.PullNever)
+					h.AssertEq(t, fakeImageFetcher.FetchCalls[fmt.Sprintf("%s:%s", cfg.DefaultLifecycleImageRepo, builder.DefaultLifecycleVersion)].PullPolicy, image.PullNever)
+					h.AssertEq(t, fakeImageFetcher.FetchCalls["default/run"].PullPolicy, image.PullAlways)
+					h.AssertEq(t, fakeImageFetcher.FetchCalls[fakePackage.Name()].PullPolicy, image.PullIfNotPresent)
+				})
+			})
+
 			when("containerized pack", func() {
 				it.Before(func() {
 					RunningInContainer = func() bool {
@@ -2431,6 +3265,20 @@ api = "0.2"
 						h.AssertContains(t, outBuf.String(), "failing to pull build inputs from a remote registry is insecure")
 					})
 				})
+
+				when("the pull policy of an image isn't always", func() {
+					it("warns", func() {
+						builderPolicy := image.PullNever
+						h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+							Image:             "some/app",
+							Builder:           defaultBuilderName,
+							PullPolicy:        image.PullAlways,
+							ImagePullPolicies: ImagePullPolicies{Builder: &builderPolicy},
+						}))
+
+						h.AssertContains(t, outBuf.String(), "failing to pull build inputs from a remote registry is insecure")
+					})
+				})
 			})
 
 			when("always", func() {
@@ -2526,6 +3374,79 @@ api = "0.2"
 			})
 		})
 
+		when("ExtraHosts option", func() {
+			it("passes the value through", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+					ContainerConfig: ContainerConfig{
+						Network:    "my-project_default",
+						ExtraHosts: []string{"host.docker.internal:host-gateway"},
+					},
+				}))
+				h.AssertEq(t, fakeLifecycle.Opts.ExtraHosts, []string{"host.docker.internal:host-gateway"})
+			})
+		})
+
+		when("SSHAgent option", func() {
+			var (
+				agentSocket string
+				agent       net.Listener
+			)
+
+			it.Before(func() {
+				h.SkipIf(t, runtime.GOOS != "linux", "the SSH agent is forwarded by Docker Desktop on macOS and Windows")
+
+				var err error
+				agentSocket = filepath.Join(tmpDir, "agent.sock")
+				agent, err = net.Listen("unix", agentSocket)
+				h.AssertNil(t, err)
+			})
+
+			it.After(func() {
+				if agent != nil {
+					agent.Close()
+				}
+			})
+
+			it("mounts a socket forwarding to the agent and sets SSH_AUTH_SOCK for the buildpacks", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:           "some/app",
+					Builder:         defaultBuilderName,
+					ContainerConfig: ContainerConfig{SSHAgent: agentSocket},
+				}))
+
+				socket := fakeLifecycle.Opts.SSHAgentSocket
+				h.AssertNotEq(t, socket, "")
+				h.AssertNotEq(t, socket, agentSocket)
+				h.AssertPathDoesNotExists(t, socket)
+
+				layerTar, err := defaultBuilderImage.FindLayerWithPath("/platform/env/SSH_AUTH_SOCK")
+				h.AssertNil(t, err)
+				h.AssertTarFileContents(t, layerTar, "/platform/env/SSH_AUTH_SOCK", "/run/pack/ssh-agent.sock")
+			})
+
+			it("errors when the agent socket doesn't exist", func() {
+				err := subject.Build(context.TODO(), BuildOptions{
+					Image:           "some/app",
+					Builder:         defaultBuilderName,
+					ContainerConfig: ContainerConfig{SSHAgent: filepath.Join(tmpDir, "missing.sock")},
+				})
+				h.AssertError(t, err, "forwarding the SSH agent")
+			})
+
+			it("errors when the daemon is reached over SSH", func() {
+				t.Setenv("DOCKER_HOST", "ssh://user@build-host")
+
+				err := subject.Build(context.TODO(), BuildOptions{
+					Image:           "some/app",
+					Builder:         defaultBuilderName,
+					ContainerConfig: ContainerConfig{SSHAgent: agentSocket},
+				})
+				h.AssertError(t, err, "the SSH agent can't be forwarded to a daemon reached over SSH")
+			})
+		})
+
 		when("Lifecycle option", func() {
 			when("Platform API", func() {
 				for _, supportedPlatformAPI := range []string{"0.3", "0.4"} {
@@ -3151,6 +4072,297 @@ api = "0.2"
 			})
 		})
 
+		when("daemonless option", func() {
+			trusted := func(string) bool { return true }
+
+			it("requires publishing the image", func() {
+				err := subject.Build(context.TODO(), BuildOptions{
+					Builder:      defaultBuilderName,
+					Image:        "example.com/some/repo:tag",
+					Daemonless:   true,
+					TrustBuilder: trusted,
+				})
+				h.AssertError(t, err, "daemonless builds must publish the image")
+				h.AssertNil(t, fakeLifecycle.Opts.Image)
+			})
+
+			it("requires a trusted builder", func() {
+				err := subject.Build(context.TODO(), BuildOptions{
+					Builder:      defaultBuilderName,
+					Image:        "example.com/some/repo:tag",
+					Publish:      true,
+					Daemonless:   true,
+					TrustBuilder: func(string) bool { return false },
+				})
+				h.AssertError(t, err, fmt.Sprintf("so builder '%s' must be trusted", defaultBuilderName))
+			})
+
+			it("only uses the buildpacks of the builder", func() {
+				err := subject.Build(context.TODO(), BuildOptions{
+					Builder:      defaultBuilderName,
+					Image:        "example.com/some/repo:tag",
+					Publish:      true,
+					Daemonless:   true,
+					TrustBuilder: trusted,
+					Buildpacks:   []string{"buildpack.1.id@buildpack.1.version"},
+				})
+				h.AssertError(t, err, "daemonless builds only use the buildpacks of the builder")
+			})
+
+			it("checks the plan of the build against the policy before running the creator", func() {
+				fakeImageFetcher.RemoteImages[defaultBuilderImage.Name()] = defaultBuilderImage
+				subject.keychain = authn.NewMultiKeychain()
+
+				err := subject.Build(context.TODO(), BuildOptions{
+					Builder:      defaultBuilderName,
+					Image:        "example.com/some/repo:tag",
+					Publish:      true,
+					Daemonless:   true,
+					TrustBuilder: trusted,
+					CheckPlan: func(plan BuildPlan) error {
+						return errors.Errorf("run image %s is denied by the policy", style.Symbol(plan.RunImage))
+					},
+				})
+				h.AssertError(t, err, fmt.Sprintf("run image '%s' is denied by the policy", defaultRunImageName))
+			})
+
+			it("passes the options of the build to the creator", func() {
+				args := creatorArgs(logging.NewSimpleLogger(&bytes.Buffer{}), BuildOptions{
+					AdditionalTags:    []string{"example.com/some/repo:other"},
+					ClearCache:        true,
+					CacheImage:        "example.com/some/cache",
+					LifecycleLogLevel: "warn",
+				}, "example.com/some/run", "example.com/some/repo:tag", "/platform/project-metadata.toml")
+
+				h.AssertEq(t, args, []string{
+					"-log-level", "warn",
+					"-app", "/workspace",
+					"-cache-dir", "/cache",
+					"-run-image", "example.com/some/run",
+					"-tag", "example.com/some/repo:other",
+					"-skip-restore",
+					"-cache-image", "example.com/some/cache",
+					"-project-metadata", "/platform/project-metadata.toml",
+					"example.com/some/repo:tag",
+				})
+			})
+		})
+
+		when("creation time option", func() {
+			var creationTime = time.Unix(1566172801, 0).UTC()
+
+			it("passes it to the lifecycle", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Builder:      defaultBuilderName,
+					Image:        "example.com/some/repo:tag",
+					CreationTime: &creationTime,
+				}))
+				h.AssertEq(t, *fakeLifecycle.Opts.CreationTime, creationTime)
+				h.AssertNotContains(t, outBuf.String(), "The creation time of the image can't be set")
+			})
+
+			when("platform API < 0.9", func() {
+				it.Before(func() {
+					setAPIs(t, defaultBuilderImage, []string{"0.8"}, []string{"0.8"})
+				})
+
+				it("warns that it can't be set", func() {
+					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+						Builder:      defaultBuilderName,
+						Image:        "example.com/some/repo:tag",
+						CreationTime: &creationTime,
+					}))
+					h.AssertContains(t, outBuf.String(), "Warning: The creation time of the image can't be set with Platform API 0.8, it requires Platform API 0.9 or later")
+				})
+			})
+		})
+
+		when("detect only option", func() {
+			it("only runs the detect phase and logs the group and plan", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Builder:    defaultBuilderName,
+					Image:      "example.com/some/repo:tag",
+					DetectOnly: true,
+				}))
+
+				h.AssertEq(t, fakeLifecycle.Opts.DetectOnly, true)
+				h.AssertNotEq(t, fakeLifecycle.Opts.DetectDestinationDir, "")
+				h.AssertPathDoesNotExists(t, fakeLifecycle.Opts.DetectDestinationDir)
+				h.AssertContains(t, outBuf.String(), "Buildpacks that passed detection (group.toml):\n[[group]]\n  id = \"buildpack.1.id\"")
+				h.AssertContains(t, outBuf.String(), "Build plan (plan.toml):\n[[entries]]")
+			})
+
+			it("runs the detector at debug level, so that it logs why buildpacks failed detection", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Builder:    defaultBuilderName,
+					Image:      "example.com/some/repo:tag",
+					DetectOnly: true,
+				}))
+				h.AssertEq(t, fakeLifecycle.Opts.LogLevel, "debug")
+			})
+
+			it("runs the detector at the lifecycle log level, when set", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Builder:           defaultBuilderName,
+					Image:             "example.com/some/repo:tag",
+					DetectOnly:        true,
+					LifecycleLogLevel: "info",
+				}))
+				h.AssertEq(t, fakeLifecycle.Opts.LogLevel, "info")
+			})
+
+			it("can't be daemonless", func() {
+				err := subject.Build(context.TODO(), BuildOptions{
+					Builder:      defaultBuilderName,
+					Image:        "example.com/some/repo:tag",
+					Publish:      true,
+					Daemonless:   true,
+					DetectOnly:   true,
+					TrustBuilder: func(string) bool { return true },
+				})
+				h.AssertError(t, err, "daemonless builds can't stop after detecting")
+			})
+		})
+
+		when("kubernetes driver", func() {
+			trusted := func(string) bool { return true }
+
+			it("requires publishing the image", func() {
+				err := subject.Build(context.TODO(), BuildOptions{
+					Builder:      defaultBuilderName,
+					Image:        "example.com/some/repo:tag",
+					Driver:       DriverKubernetes,
+					TrustBuilder: trusted,
+				})
+				h.AssertError(t, err, "kubernetes builds must publish the image")
+				h.AssertNil(t, fakeLifecycle.Opts.Image)
+			})
+
+			it("can't be daemonless", func() {
+				err := subject.Build(context.TODO(), BuildOptions{
+					Builder:      defaultBuilderName,
+					Image:        "example.com/some/repo:tag",
+					Publish:      true,
+					Driver:       DriverKubernetes,
+					Daemonless:   true,
+					TrustBuilder: trusted,
+				})
+				h.AssertError(t, err, "daemonless builds don't run in a cluster")
+			})
+
+			it("fails for an unknown driver", func() {
+				err := subject.Build(context.TODO(), BuildOptions{
+					Builder: defaultBuilderName,
+					Image:   "example.com/some/repo:tag",
+					Driver:  "nomad",
+				})
+				h.AssertError(t, err, "unknown driver 'nomad'")
+			})
+
+			it("copies the app and the platform env vars to the job, owned by the user of the builder", func() {
+				appDir, err := os.MkdirTemp("", "kubernetes-app")
+				h.AssertNil(t, err)
+				defer os.RemoveAll(appDir)
+				h.AssertNil(t, os.WriteFile(filepath.Join(appDir, "app.txt"), []byte("some-app"), 0644))
+
+				files, err := kubernetesFiles(appDir, nil, 1000, 1001, map[string]string{"BP_SOME_VAR": "some-value"}, []byte("some-metadata"))
+				h.AssertNil(t, err)
+				defer os.Remove(files.Name())
+				h.AssertNil(t, files.Close())
+
+				h.AssertOnTarEntry(t, files.Name(), "/workspace/app.txt",
+					h.ContentEquals("some-app"),
+					h.HasOwnerAndGroup(1000, 1001),
+				)
+				h.AssertOnTarEntry(t, files.Name(), "/platform/env/BP_SOME_VAR",
+					h.ContentEquals("some-value"),
+					h.HasOwnerAndGroup(1000, 1001),
+				)
+				h.AssertOnTarEntry(t, files.Name(), "/platform/project-metadata.toml",
+					h.ContentEquals("some-metadata"),
+				)
+			})
+
+			it("checks the plan of the build against the policy before running the job", func() {
+				fakeImageFetcher.RemoteImages[defaultBuilderImage.Name()] = defaultBuilderImage
+				subject.keychain = authn.NewMultiKeychain()
+				var checked BuildPlan
+
+				err := subject.Build(context.TODO(), BuildOptions{
+					Builder:      defaultBuilderName,
+					Image:        "example.com/some/repo:tag",
+					Publish:      true,
+					Driver:       DriverKubernetes,
+					TrustBuilder: trusted,
+					CheckPlan: func(plan BuildPlan) error {
+						checked = plan
+						return errors.New("builder 'example.com/default/builder:tag' is denied by the policy")
+					},
+				})
+				h.AssertError(t, err, "denied by the policy")
+				h.AssertEq(t, checked.Builder, defaultBuilderName)
+				h.AssertEq(t, checked.RunImage, defaultRunImageName)
+				h.AssertEq(t, checked.Image, "example.com/some/repo:tag")
+				h.AssertEq(t, checked.Publish, true)
+				h.AssertEq(t, len(checked.Buildpacks) > 0, true)
+			})
+
+			it("validates the labels of the build before running the job", func() {
+				fakeImageFetcher.RemoteImages[defaultBuilderImage.Name()] = defaultBuilderImage
+
+				err := subject.Build(context.TODO(), BuildOptions{
+					Builder:      defaultBuilderName,
+					Image:        "example.com/some/repo:tag",
+					Publish:      true,
+					Driver:       DriverKubernetes,
+					TrustBuilder: trusted,
+					Labels:       map[string]string{"io.buildpacks.some-label": "some-value"},
+				})
+				h.AssertError(t, err, "label 'io.buildpacks.some-label' is reserved")
+			})
+		})
+
+		when("the daemon is reached over SSH", func() {
+			var dockerHost string
+
+			it.Before(func() {
+				dockerHost = os.Getenv("DOCKER_HOST")
+				os.Setenv("DOCKER_HOST", "ssh://user@build-host")
+			})
+
+			it.After(func() {
+				os.Setenv("DOCKER_HOST", dockerHost)
+			})
+
+			it("gzips the app copied to the daemon", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Builder: defaultBuilderName,
+					Image:   "example.com/some/repo:tag",
+				}))
+				h.AssertEq(t, fakeLifecycle.Opts.GzipApp, true)
+			})
+
+			it("errors for a bind-mounted cache, which is on this host", func() {
+				err := subject.Build(context.TODO(), BuildOptions{
+					Builder: defaultBuilderName,
+					Image:   "example.com/some/repo:tag",
+					Cache: cache.CacheOpts{
+						Build: cache.CacheInfo{Format: cache.CacheBind, Source: "/some/cache"},
+					},
+				})
+				h.AssertError(t, err, "the bind-mounted cache '/some/cache' is a directory of this host, which the daemon at 'ssh://user@build-host' can't mount")
+			})
+
+			it("warns that volumes are mounted from the host of the daemon", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Builder:         defaultBuilderName,
+					Image:           "example.com/some/repo:tag",
+					ContainerConfig: ContainerConfig{Volumes: []string{"/some/dir:/workspace/dir"}},
+				}))
+				h.AssertContains(t, outBuf.String(), "volumes are mounted from its host rather than this one")
+			})
+		})
+
 		when("report destination dir option", func() {
 			it("passthroughs to lifecycle", func() {
 				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
@@ -3160,6 +4372,40 @@ api = "0.2"
 				}))
 				h.AssertEq(t, fakeLifecycle.Opts.ReportDestinationDir, "a-destination-dir")
 			})
+
+			when("resource limits are set", func() {
+				it("records them in the report", func() {
+					reportDir := filepath.Join(tmpDir, "report")
+					h.AssertNil(t, os.MkdirAll(reportDir, 0755))
+					h.AssertNil(t, os.WriteFile(filepath.Join(reportDir, "report.toml"), []byte("[image]\n  tags = [\"example.com/some/repo:tag\"]\n"), 0644))
+
+					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+						Builder:              defaultBuilderName,
+						Image:                "example.com/some/repo:tag",
+						ReportDestinationDir: reportDir,
+						ContainerConfig:      ContainerConfig{Memory: 1024 * 1024 * 1024, CPUs: 1.5, PidsLimit: 512},
+					}))
+
+					var report struct {
+						Image struct {
+							Tags []string `toml:"tags"`
+						} `toml:"image"`
+						Pack struct {
+							Resources struct {
+								Memory    int64   `toml:"memory"`
+								CPUs      float64 `toml:"cpus"`
+								PidsLimit int64   `toml:"pids-limit"`
+							} `toml:"resources"`
+						} `toml:"pack"`
+					}
+					_, err := toml.DecodeFile(filepath.Join(reportDir, "report.toml"), &report)
+					h.AssertNil(t, err)
+					h.AssertEq(t, report.Image.Tags, []string{"example.com/some/repo:tag"})
+					h.AssertEq(t, report.Pack.Resources.Memory, int64(1024*1024*1024))
+					h.AssertEq(t, report.Pack.Resources.CPUs, 1.5)
+					h.AssertEq(t, report.Pack.Resources.PidsLimit, int64(512))
+				})
+			})
 		})
 
 		when("there are extensions", func() {
@@ -3319,6 +4565,24 @@ api = "0.2"
 					h.AssertSliceContainsMatch(t, fakeLifecycle.Opts.Volumes, hostImagePath, hostPreviousImagePath, hostRunImagePath)
 				})
 			})
+
+			when("an archive path is provided", func() {
+				it("writes the OCI layout of the image to a tarball", func() {
+					h.AssertNil(t, os.MkdirAll(hostImagePath, 0755))
+					h.AssertNil(t, os.WriteFile(filepath.Join(hostImagePath, "oci-layout"), []byte(`{"imageLayoutVersion":"1.0.0"}`), 0644))
+					h.AssertNil(t, os.WriteFile(filepath.Join(hostImagePath, "index.json"), []byte(`{}`), 0644))
+					layoutConfig.ArchivePath = filepath.Join(tmpDir, "my-app.tar")
+
+					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+						Image:        inputImageReference.Name(),
+						Builder:      defaultBuilderName,
+						LayoutConfig: layoutConfig,
+					}))
+
+					h.AssertOnTarEntry(t, layoutConfig.ArchivePath, "oci-layout", h.ContentEquals(`{"imageLayoutVersion":"1.0.0"}`))
+					h.AssertOnTarEntry(t, layoutConfig.ArchivePath, "index.json", h.ContentEquals(`{}`))
+				})
+			})
 		})
 	})
 }
@@ -0,0 +1,372 @@
+package client
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/registry"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/archive"
+	"github.com/buildpacks/pack/pkg/buildpack"
+	"github.com/buildpacks/pack/pkg/image"
+)
+
+// Entries of a bundle archive
+const (
+	bundleManifestEntry = "bundle.json"
+	bundleImagesEntry   = "images.tar"
+	bundleRegistryDir   = "registry"
+)
+
+// CreateBundleOptions configures CreateBundle
+type CreateBundleOptions struct {
+	// Builder image to bundle
+	Builder string
+
+	// Run image to bundle, defaults to the run image of the builder
+	RunImage string
+
+	// Lifecycle image to bundle for untrusted builds, defaults to the lifecycle image of the version of the
+	// lifecycle of the builder
+	LifecycleImage string
+
+	// Buildpacks to bundle, either from a buildpack registry or packaged as images
+	Buildpacks []string
+
+	// Name of the buildpack registry that registry buildpacks come from, the default registry when empty.
+	// A snapshot of its index is bundled when registry buildpacks are.
+	Registry string
+
+	// How the buildpacks of the registry are located
+	RegistryLookup buildpack.RegistryLookup
+
+	// Pull policy of the images to bundle
+	PullPolicy image.PullPolicy
+}
+
+// Bundle describes the contents of a bundle archive
+type Bundle struct {
+	Created        time.Time         `json:"created"`
+	Builder        string            `json:"builder"`
+	RunImage       string            `json:"runImage"`
+	LifecycleImage string            `json:"lifecycleImage"`
+	Buildpacks     []BundleBuildpack `json:"buildpacks,omitempty"`
+
+	// URL of the buildpack registry whose index snapshot is bundled, empty without a snapshot
+	RegistryURL string `json:"registryURL,omitempty"`
+
+	// Every image of the bundle
+	Images []string `json:"images"`
+}
+
+// BundleBuildpack is a buildpack of a bundle, and the image it's packaged in
+type BundleBuildpack struct {
+	Locator string `json:"locator"`
+	Image   string `json:"image"`
+}
+
+// CreateBundle writes an archive to path holding everything builds need to run without network access: the builder,
+// run and lifecycle images, the images of buildpacks, and a snapshot of the buildpack registry index.
+func (c *Client) CreateBundle(ctx context.Context, path string, opts CreateBundleOptions) (Bundle, error) {
+	fetchOptions := image.FetchOptions{Daemon: true, PullPolicy: opts.PullPolicy}
+
+	builderImage, err := c.imageFetcher.Fetch(ctx, opts.Builder, fetchOptions)
+	if err != nil {
+		return Bundle{}, errors.Wrapf(err, "fetching builder %s", style.Symbol(opts.Builder))
+	}
+	bldr, err := c.getBuilder(builderImage)
+	if err != nil {
+		return Bundle{}, errors.Wrapf(err, "invalid builder %s", style.Symbol(opts.Builder))
+	}
+
+	bundle := Bundle{
+		Created:        time.Now().UTC(),
+		Builder:        opts.Builder,
+		RunImage:       opts.RunImage,
+		LifecycleImage: opts.LifecycleImage,
+	}
+	if bundle.RunImage == "" {
+		bundle.RunImage = bldr.DefaultRunImage().Image
+	}
+	if bundle.RunImage == "" {
+		return Bundle{}, errors.Errorf("builder %s has no run image, set one", style.Symbol(opts.Builder))
+	}
+	if bundle.LifecycleImage == "" {
+		bundle.LifecycleImage = fmt.Sprintf("%s:%s", config.DefaultLifecycleImageRepo, bldr.LifecycleDescriptor().Info.Version.String())
+	}
+
+	var registryLocators []string
+	for _, locator := range opts.Buildpacks {
+		locatorType, err := buildpack.GetLocatorType(locator, "", nil)
+		if err != nil {
+			return Bundle{}, err
+		}
+
+		switch locatorType {
+		case buildpack.RegistryLocator:
+			registryLocators = append(registryLocators, locator)
+		case buildpack.PackageLocator:
+		default:
+			return Bundle{}, errors.Errorf("buildpack %s can't be bundled, only buildpacks of a registry or packaged as images can", style.Symbol(locator))
+		}
+	}
+
+	// registry buildpacks are located at once, so that the registry is only refreshed once
+	var registryCache registry.Cache
+	registryImages := map[string]string{}
+	if len(registryLocators) > 0 {
+		if registryCache, err = getRegistry(c.logger, opts.Registry, c.registryOptions.with(opts.RegistryLookup)); err != nil {
+			return Bundle{}, err
+		}
+		located, err := registryCache.LocateBuildpacks(registryLocators)
+		if err != nil {
+			return Bundle{}, errors.Wrap(err, "locating buildpacks")
+		}
+		for _, registryBuildpack := range located {
+			if registryBuildpack.Err != nil {
+				return Bundle{}, errors.Wrapf(registryBuildpack.Err, "locating buildpack %s", style.Symbol(registryBuildpack.ID))
+			}
+			registryImages[registryBuildpack.ID] = registryBuildpack.Buildpack.Address
+		}
+	}
+
+	for _, locator := range opts.Buildpacks {
+		imageName, ok := registryImages[locator]
+		if !ok {
+			imageName = buildpack.ParsePackageLocator(locator)
+		}
+		bundle.Buildpacks = append(bundle.Buildpacks, BundleBuildpack{Locator: locator, Image: imageName})
+	}
+
+	bundle.Images = []string{bldr.Name(), bundle.RunImage, bundle.LifecycleImage}
+	for _, imageName := range bundle.Images[1:] {
+		if _, err := c.imageFetcher.Fetch(ctx, imageName, fetchOptions); err != nil {
+			return Bundle{}, errors.Wrapf(err, "fetching %s", style.Symbol(imageName))
+		}
+	}
+	for _, bp := range bundle.Buildpacks {
+		if _, err := c.imageFetcher.Fetch(ctx, bp.Image, fetchOptions); err != nil {
+			return Bundle{}, errors.Wrapf(err, "fetching buildpack %s", style.Symbol(bp.Locator))
+		}
+		bundle.Images = append(bundle.Images, bp.Image)
+	}
+
+	registryRoot := ""
+	// an in-memory index has no directory to snapshot
+	if registryCache != nil && registryCache.Dir() != "" {
+		registryRoot = registryCache.Dir()
+		bundle.RegistryURL = registryCache.URL()
+	}
+
+	if err := c.writeBundle(ctx, path, bundle, registryRoot); err != nil {
+		return Bundle{}, errors.Wrapf(err, "writing bundle %s", style.Symbol(path))
+	}
+	return bundle, nil
+}
+
+func (c *Client) writeBundle(ctx context.Context, path string, bundle Bundle, registryRoot string) error {
+	// the size of the saved images must be known before they are added to the bundle
+	images, err := os.CreateTemp("", "pack.bundle.images.")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(images.Name())
+	defer images.Close()
+
+	saved, err := c.docker.ImageSave(ctx, bundle.Images)
+	if err != nil {
+		return errors.Wrap(err, "saving images")
+	}
+	defer saved.Close()
+	size, err := io.Copy(images, saved)
+	if err != nil {
+		return errors.Wrap(err, "saving images")
+	}
+	if _, err := images.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	manifest, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	gw := gzip.NewWriter(file)
+	tw := tar.NewWriter(gw)
+
+	if err := tw.WriteHeader(&tar.Header{Name: bundleManifestEntry, Mode: 0644, Size: int64(len(manifest)), ModTime: bundle.Created}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(manifest); err != nil {
+		return err
+	}
+
+	if err := tw.WriteHeader(&tar.Header{Name: bundleImagesEntry, Mode: 0644, Size: size, ModTime: bundle.Created}); err != nil {
+		return err
+	}
+	if _, err := io.Copy(tw, images); err != nil {
+		return err
+	}
+
+	if registryRoot != "" {
+		if err := archive.WriteDirToTar(tw, registryRoot, bundleRegistryDir, 0, 0, -1, false, false, nil); err != nil {
+			return errors.Wrap(err, "adding registry index")
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// ApplyBundle loads the images of a bundle archive into the docker daemon, and restores the snapshot of its buildpack
+// registry index in the pack home, so that builds with the bundled builder and buildpacks need no network access.
+func (c *Client) ApplyBundle(ctx context.Context, path string) (Bundle, error) {
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return Bundle{}, err
+	}
+	defer file.Close()
+
+	gr, err := gzip.NewReader(file)
+	if err != nil {
+		return Bundle{}, errors.Wrapf(err, "reading bundle %s", style.Symbol(path))
+	}
+	defer gr.Close()
+
+	var (
+		bundle      Bundle
+		hasManifest bool
+		registryDir string
+	)
+	defer func() {
+		if registryDir != "" {
+			os.RemoveAll(registryDir)
+		}
+	}()
+
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Bundle{}, errors.Wrapf(err, "reading bundle %s", style.Symbol(path))
+		}
+
+		switch {
+		case header.Name == bundleManifestEntry:
+			if err := json.NewDecoder(tr).Decode(&bundle); err != nil {
+				return Bundle{}, errors.Wrap(err, "reading bundle manifest")
+			}
+			hasManifest = true
+		case header.Name == bundleImagesEntry:
+			c.logger.Info("Loading images")
+			resp, err := c.docker.ImageLoad(ctx, tr, true)
+			if err != nil {
+				return Bundle{}, errors.Wrap(err, "loading images")
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		case strings.HasPrefix(header.Name, bundleRegistryDir+"/"):
+			if registryDir == "" {
+				if registryDir, err = newRegistryIndexDir(); err != nil {
+					return Bundle{}, err
+				}
+			}
+			if err := extractBundleEntry(tr, header, registryDir, strings.TrimPrefix(header.Name, bundleRegistryDir+"/")); err != nil {
+				return Bundle{}, err
+			}
+		}
+	}
+
+	if !hasManifest {
+		return Bundle{}, errors.Errorf("%s is not a bundle, it has no %s", style.Symbol(path), bundleManifestEntry)
+	}
+
+	if registryDir != "" && bundle.RegistryURL != "" {
+		if err := c.restoreRegistryIndex(registryDir, bundle.RegistryURL); err != nil {
+			return Bundle{}, errors.Wrap(err, "restoring registry index")
+		}
+	}
+	return bundle, nil
+}
+
+// newRegistryIndexDir creates the directory that the registry index of a bundle, or of a registry cache archive, is
+// extracted to. It's in the pack home, so that it can be moved in place of the registry cache once complete.
+func newRegistryIndexDir() (string, error) {
+	home, err := config.PackHome()
+	if err != nil {
+		return "", err
+	}
+	if err := config.MkdirAll(home); err != nil {
+		return "", err
+	}
+	return os.MkdirTemp(home, "registry")
+}
+
+// restoreRegistryIndex replaces the registry cache of registryURL in the pack home with the snapshot in dir
+func (c *Client) restoreRegistryIndex(dir, registryURL string) error {
+	home, err := config.PackHome()
+	if err != nil {
+		return err
+	}
+
+	registryCache, err := registry.NewCache(c.logger, home, registryURL)
+	if err != nil {
+		return err
+	}
+	if _, ok := registryCache.(*registry.DirCache); ok {
+		// the index of a registry in a local directory is the user's own, it's never replaced
+		c.logger.Warnf("Registry %s is a local directory, not replacing its index with the index of the bundle", style.Symbol(registryURL))
+		return os.RemoveAll(dir)
+	}
+	if err := os.RemoveAll(registryCache.Dir()); err != nil {
+		return err
+	}
+	return os.Rename(dir, registryCache.Dir())
+}
+
+func extractBundleEntry(tr io.Reader, header *tar.Header, dir, name string) error {
+	// cleaning the name as an absolute path keeps entries such as ../file within dir
+	target := filepath.Join(dir, filepath.FromSlash(path.Clean("/"+name)))
+
+	switch header.Typeflag {
+	case tar.TypeDir:
+		return os.MkdirAll(target, 0755)
+	case tar.TypeReg:
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		file, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, os.FileMode(header.Mode).Perm())
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		_, err = io.Copy(file, tr)
+		return err
+	}
+	return nil
+}
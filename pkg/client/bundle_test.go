@@ -0,0 +1,203 @@
+package client
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/buildpacks/imgutil/fakes"
+	"github.com/docker/docker/api/types"
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/builder"
+	ifakes "github.com/buildpacks/pack/internal/fakes"
+	"github.com/buildpacks/pack/internal/registry"
+	"github.com/buildpacks/pack/pkg/image"
+	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/testmocks"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestBundle(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Bundle", testBundle, spec.Sequential(), spec.Report(report.Terminal{}))
+}
+
+func testBundle(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject          *Client
+		fakeImageFetcher *ifakes.FakeImageFetcher
+		mockDockerClient *testmocks.MockCommonAPIClient
+		mockController   *gomock.Controller
+		out              bytes.Buffer
+		tmpDir           string
+		bundlePath       string
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		fakeImageFetcher = ifakes.NewFakeImageFetcher()
+		mockDockerClient = testmocks.NewMockCommonAPIClient(mockController)
+
+		var err error
+		subject, err = NewClient(WithLogger(logging.NewLogWithWriters(&out, &out)), WithFetcher(fakeImageFetcher), WithDockerClient(mockDockerClient))
+		h.AssertNil(t, err)
+
+		tmpDir = t.TempDir()
+		bundlePath = filepath.Join(tmpDir, "bundle.tgz")
+		t.Setenv("PACK_HOME", filepath.Join(tmpDir, "pack-home"))
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#CreateBundle", func() {
+		it.Before(func() {
+			builderImage := newFakeBuilderImage(t, tmpDir, "example.com/some/builder", "some.stack.id", "example.com/some/run", builder.DefaultLifecycleVersion, newLinuxImage)
+			fakeImageFetcher.LocalImages[builderImage.Name()] = builderImage
+			for _, name := range []string{"example.com/some/run", "buildpacksio/lifecycle:" + builder.DefaultLifecycleVersion, "example.com/some/buildpack"} {
+				fakeImageFetcher.LocalImages[name] = fakes.NewImage(name, "", nil)
+			}
+		})
+
+		it("writes the images of the builder, run image, lifecycle and buildpacks to the bundle", func() {
+			mockDockerClient.EXPECT().
+				ImageSave(gomock.Any(), []string{"example.com/some/builder", "example.com/some/run", "buildpacksio/lifecycle:" + builder.DefaultLifecycleVersion, "example.com/some/buildpack"}).
+				Return(io.NopCloser(strings.NewReader("saved images")), nil)
+
+			bundle, err := subject.CreateBundle(context.TODO(), bundlePath, CreateBundleOptions{
+				Builder:    "example.com/some/builder",
+				Buildpacks: []string{"docker://example.com/some/buildpack"},
+				PullPolicy: image.PullIfNotPresent,
+			})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, bundle.RunImage, "example.com/some/run")
+			h.AssertEq(t, bundle.LifecycleImage, "buildpacksio/lifecycle:"+builder.DefaultLifecycleVersion)
+			h.AssertEq(t, bundle.Buildpacks, []BundleBuildpack{{Locator: "docker://example.com/some/buildpack", Image: "example.com/some/buildpack"}})
+			h.AssertEq(t, bundle.RegistryURL, "")
+
+			contents := readBundle(t, bundlePath)
+			h.AssertContains(t, contents[bundleManifestEntry], `"builder": "example.com/some/builder"`)
+			h.AssertEq(t, contents[bundleImagesEntry], "saved images")
+		})
+
+		it("rejects buildpacks that aren't images", func() {
+			_, err := subject.CreateBundle(context.TODO(), bundlePath, CreateBundleOptions{
+				Builder:    "example.com/some/builder",
+				Buildpacks: []string{"https://example.com/buildpack.tgz"},
+				PullPolicy: image.PullIfNotPresent,
+			})
+			h.AssertError(t, err, "buildpack 'https://example.com/buildpack.tgz' can't be bundled")
+		})
+	})
+
+	when("#ApplyBundle", func() {
+		it("loads the images and restores the registry index", func() {
+			writeBundle(t, bundlePath, map[string]string{
+				bundleManifestEntry:             `{"builder": "example.com/some/builder", "registryURL": "https://example.com/registry-index", "images": ["example.com/some/builder"]}`,
+				bundleImagesEntry:               "saved images",
+				"registry/so/me/some_buildpack": `{"ns": "some", "name": "buildpack"}`,
+			})
+
+			mockDockerClient.EXPECT().
+				ImageLoad(gomock.Any(), gomock.Any(), true).
+				DoAndReturn(func(_ context.Context, input io.Reader, _ bool) (types.ImageLoadResponse, error) {
+					loaded, err := io.ReadAll(input)
+					h.AssertNil(t, err)
+					h.AssertEq(t, string(loaded), "saved images")
+					return types.ImageLoadResponse{Body: io.NopCloser(strings.NewReader(""))}, nil
+				})
+
+			bundle, err := subject.ApplyBundle(context.TODO(), bundlePath)
+			h.AssertNil(t, err)
+			h.AssertEq(t, bundle.Builder, "example.com/some/builder")
+
+			registryCache, err := registry.NewRegistryCache(logging.NewSimpleLogger(io.Discard), filepath.Join(tmpDir, "pack-home"), "https://example.com/registry-index")
+			h.AssertNil(t, err)
+			entry, err := os.ReadFile(filepath.Join(registryCache.Root, "so", "me", "some_buildpack"))
+			h.AssertNil(t, err)
+			h.AssertEq(t, string(entry), `{"ns": "some", "name": "buildpack"}`)
+		})
+
+		it("keeps entries within the registry index", func() {
+			writeBundle(t, bundlePath, map[string]string{
+				bundleManifestEntry:   `{"builder": "example.com/some/builder", "registryURL": "https://example.com/registry-index"}`,
+				"registry/../../evil": "evil",
+			})
+
+			_, err := subject.ApplyBundle(context.TODO(), bundlePath)
+			h.AssertNil(t, err)
+			h.AssertPathDoesNotExists(t, filepath.Join(tmpDir, "evil"))
+			h.AssertPathDoesNotExists(t, filepath.Join(tmpDir, "pack-home", "evil"))
+		})
+
+		it("fails for archives that aren't bundles", func() {
+			writeBundle(t, bundlePath, map[string]string{"other": "contents"})
+
+			_, err := subject.ApplyBundle(context.TODO(), bundlePath)
+			h.AssertError(t, err, "is not a bundle")
+		})
+	})
+}
+
+func writeBundle(t *testing.T, path string, entries map[string]string) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	// the manifest comes first, as in bundles created by pack
+	names := []string{bundleManifestEntry}
+	for name := range entries {
+		if name != bundleManifestEntry {
+			names = append(names, name)
+		}
+	}
+	for _, name := range names {
+		contents, ok := entries[name]
+		if !ok {
+			continue
+		}
+		h.AssertNil(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(contents)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(contents))
+		h.AssertNil(t, err)
+	}
+	h.AssertNil(t, tw.Close())
+	h.AssertNil(t, gw.Close())
+	h.AssertNil(t, os.WriteFile(path, buf.Bytes(), 0600))
+}
+
+func readBundle(t *testing.T, path string) map[string]string {
+	t.Helper()
+
+	file, err := os.Open(path)
+	h.AssertNil(t, err)
+	defer file.Close()
+	gr, err := gzip.NewReader(file)
+	h.AssertNil(t, err)
+
+	contents := map[string]string{}
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return contents
+		}
+		h.AssertNil(t, err)
+		data, err := io.ReadAll(tr)
+		h.AssertNil(t, err)
+		contents[header.Name] = string(data)
+	}
+}
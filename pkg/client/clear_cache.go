@@ -0,0 +1,19 @@
+package client
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/pkg/cache"
+)
+
+// validateClearCache fails for the options clearing the build cache, or the entries of buildpacks in it, when the
+// build cache is an image, which ClearCache skips as a whole rather than clearing it
+func validateClearCache(opts BuildOptions) error {
+	if !opts.ClearBuildCache && len(opts.ClearBuildpackCaches) == 0 {
+		return nil
+	}
+	if opts.CacheImage != "" || opts.Cache.Build.Format == cache.CacheImage {
+		return errors.New("the build cache must be a volume or bind to be cleared apart, a cache image can only be skipped as a whole")
+	}
+	return nil
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/buildpacks/pack"
 	"github.com/buildpacks/pack/internal/build"
 	iconfig "github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/lifecycle"
 	"github.com/buildpacks/pack/internal/style"
 	"github.com/buildpacks/pack/pkg/blob"
 	"github.com/buildpacks/pack/pkg/buildpack"
@@ -127,8 +128,14 @@ type Client struct {
 	buildpackDownloader BuildpackDownloader
 
 	experimental    bool
+	registryOptions registryOptions
 	registryMirrors map[string]string
+	downloadWorkers int
 	version         string
+
+	// lifecycleDownloader downloads the archives of released lifecycle versions, the downloader when nil
+	lifecycleDownloader BlobDownloader
+	lifecycleMirror     string
 }
 
 // Option is a type of function that mutate settings on the client.
@@ -203,6 +210,38 @@ func WithExperimental(experimental bool) Option {
 	}
 }
 
+// WithOffline sets whether buildpacks are located in the existing registry caches without refreshing them, for
+// when there is no network.
+func WithOffline(offline bool) Option {
+	return func(c *Client) {
+		c.registryOptions.offline = offline
+	}
+}
+
+// WithForceRefresh sets whether the registry caches are refreshed even when they were refreshed within their
+// refresh interval.
+func WithForceRefresh(forceRefresh bool) Option {
+	return func(c *Client) {
+		c.registryOptions.forceRefresh = forceRefresh
+	}
+}
+
+// WithAllowYanked sets whether yanked versions of registry buildpacks are located, rather than skipped when
+// resolving the highest version and refused when requested explicitly.
+func WithAllowYanked(allowYanked bool) Option {
+	return func(c *Client) {
+		c.registryOptions.allowYanked = allowYanked
+	}
+}
+
+// WithIncludePrereleases sets whether pre-release versions of registry buildpacks are located when resolving the
+// highest version, rather than skipped. Pre-releases requested explicitly are located either way.
+func WithIncludePrereleases(includePrereleases bool) Option {
+	return func(c *Client) {
+		c.registryOptions.includePrereleases = includePrereleases
+	}
+}
+
 // WithRegistryMirrors sets mirrors to pull images from.
 func WithRegistryMirrors(registryMirrors map[string]string) Option {
 	return func(c *Client) {
@@ -210,6 +249,22 @@ func WithRegistryMirrors(registryMirrors map[string]string) Option {
 	}
 }
 
+// WithDownloadWorkers sets how many buildpacks and extensions are downloaded at once, DefaultDownloadWorkers when
+// less than 1.
+func WithDownloadWorkers(workers int) Option {
+	return func(c *Client) {
+		c.downloadWorkers = workers
+	}
+}
+
+// WithLifecycleMirror sets where the archives of released lifecycle versions are downloaded from, serving them like
+// lifecycle.DefaultMirror does, including their checksums.
+func WithLifecycleMirror(mirror string) Option {
+	return func(c *Client) {
+		c.lifecycleMirror = mirror
+	}
+}
+
 // WithKeychain sets keychain of credentials to image registries
 func WithKeychain(keychain authn.Keychain) Option {
 	return func(c *Client) {
@@ -251,6 +306,8 @@ func NewClient(opts ...Option) (*Client, error) {
 			return nil, errors.Wrap(err, "getting pack home")
 		}
 		client.downloader = blob.NewDownloader(client.logger, filepath.Join(packHome, "download-cache"))
+		// lifecycle versions are downloaded through the downloader supplied in place of the default one, if any
+		client.lifecycleDownloader = lifecycle.NewCache(client.logger, filepath.Join(packHome, "lifecycle-cache"))
 	}
 
 	if client.imageFetcher == nil {
@@ -282,7 +339,8 @@ func NewClient(opts ...Option) (*Client, error) {
 			client.imageFetcher,
 			client.downloader,
 			&registryResolver{
-				logger: client.logger,
+				logger:  client.logger,
+				options: client.registryOptions,
 			},
 		)
 	}
@@ -293,11 +351,12 @@ func NewClient(opts ...Option) (*Client, error) {
 }
 
 type registryResolver struct {
-	logger logging.Logger
+	logger  logging.Logger
+	options registryOptions
 }
 
-func (r *registryResolver) Resolve(registryName, bpName string) (string, error) {
-	cache, err := getRegistry(r.logger, registryName)
+func (r *registryResolver) Resolve(registryName, bpName string, lookup buildpack.RegistryLookup) (string, error) {
+	cache, err := getRegistry(r.logger, registryName, r.options.with(lookup))
 	if err != nil {
 		return "", errors.Wrapf(err, "lookup registry %s", style.Symbol(registryName))
 	}
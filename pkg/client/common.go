@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/buildpacks/imgutil"
 	"github.com/google/go-containerregistry/pkg/name"
@@ -12,6 +15,8 @@ import (
 	"github.com/buildpacks/pack/internal/config"
 	"github.com/buildpacks/pack/internal/registry"
 	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/buildpack"
+	"github.com/buildpacks/pack/pkg/errcode"
 	"github.com/buildpacks/pack/pkg/image"
 	"github.com/buildpacks/pack/pkg/logging"
 )
@@ -77,32 +82,159 @@ func (c *Client) resolveRunImage(runImage, imgRegistry, bldrRegistry string, run
 	return runImageName
 }
 
-func getRegistry(logger logging.Logger, registryName string) (registry.Cache, error) {
+// registryTokenEnvPrefix prefixes the environment variable of the token authenticating to a private registry, e.g.
+// PACK_REGISTRY_TOKEN_MY_REGISTRY for my-registry, which takes precedence over the token of the registry in the config
+const registryTokenEnvPrefix = "PACK_REGISTRY_TOKEN_"
+
+// registryOptions configure how buildpacks are located in the registry caches
+type registryOptions struct {
+	// offline uses the existing caches without refreshing them
+	offline bool
+
+	// forceRefresh refreshes the caches even when they were refreshed within their refresh interval
+	forceRefresh bool
+
+	// allowYanked locates yanked versions of buildpacks rather than skipping them
+	allowYanked bool
+
+	// includePrereleases locates pre-release versions of buildpacks as their highest version rather than skipping them
+	includePrereleases bool
+
+	// index is the in-memory index buildpacks are located in rather than the registry caches, when not nil
+	index []RegistryIndexEntry
+}
+
+// with returns the options along with those of a lookup, which enables the options it sets
+func (o registryOptions) with(lookup buildpack.RegistryLookup) registryOptions {
+	o.offline = o.offline || lookup.Offline
+	o.forceRefresh = o.forceRefresh || lookup.ForceRefresh
+	o.allowYanked = o.allowYanked || lookup.AllowYanked
+	o.includePrereleases = o.includePrereleases || lookup.IncludePrereleases
+	return o
+}
+
+func getRegistry(logger logging.Logger, registryName string, opts registryOptions) (registry.Cache, error) {
+	if opts.index != nil {
+		return memoryRegistry(logger, registryName, opts)
+	}
+
 	home, err := config.PackHome()
 	if err != nil {
-		return registry.Cache{}, err
+		return nil, err
 	}
 
 	if err := config.MkdirAll(home); err != nil {
-		return registry.Cache{}, err
+		return nil, err
 	}
 
 	cfg, err := getConfig()
 	if err != nil {
-		return registry.Cache{}, err
+		return nil, err
 	}
 
+	refreshInterval, err := registryRefreshInterval(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	reg, err := registryConfig(cfg, registryName)
+	if err != nil {
+		return nil, err
+	}
+
+	maxAge, err := registryCacheMaxAge(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	cache, err := registry.NewCache(logger, home, reg.URL,
+		registry.WithShallowClone(),
+		registry.WithToken(registryToken(reg)),
+		registry.WithMirrors(reg.Mirrors...),
+		registry.WithProxy(cfg.RegistryProxy),
+		registry.WithOffline(opts.offline),
+		registry.WithForceRefresh(opts.forceRefresh),
+		registry.WithRefreshInterval(refreshInterval),
+		registry.WithAllowYanked(opts.allowYanked),
+		registry.WithIncludePrereleases(opts.includePrereleases),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// caches are only pruned online, as they can't be created again offline
+	if !opts.offline {
+		registry.AutoPruneCaches(logger, home, maxAge, cache.Dir())
+	}
+	return cache, nil
+}
+
+// registryToken is the token authenticating to reg, its environment variable taking precedence over its configured
+// token. The official registry is public and never sent a token from the environment.
+func registryToken(reg config.Registry) string {
+	if reg.Name == config.OfficialRegistryName {
+		return reg.Token
+	}
+	if envToken := os.Getenv(registryTokenEnv(reg.Name)); envToken != "" {
+		return envToken
+	}
+	return reg.Token
+}
+
+// registryTokenEnv is the environment variable of the token of the registry named name, which is upper-cased with
+// the characters other than letters and digits replaced by underscores
+func registryTokenEnv(name string) string {
+	return registryTokenEnvPrefix + strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// registryConfig is the registry buildpacks are located in, which is the given registry when one is selected,
+// else the default registry of the config, else the official registry
+func registryConfig(cfg config.Config, registryName string) (config.Registry, error) {
 	if registryName == "" {
-		return registry.NewDefaultRegistryCache(logger, home)
+		registryName = cfg.DefaultRegistryName
+	}
+	if registryName == "" {
+		registryName = config.OfficialRegistryName
 	}
 
 	for _, reg := range config.GetRegistries(cfg) {
 		if reg.Name == registryName {
-			return registry.NewRegistryCache(logger, home, reg.URL)
+			return reg, nil
 		}
 	}
+	return config.Registry{}, errcode.Errorf(errcode.RegistryNotDefined, "registry %s is not defined in your config file", style.Symbol(registryName))
+}
 
-	return registry.Cache{}, fmt.Errorf("registry %s is not defined in your config file", style.Symbol(registryName))
+// registryCacheMaxAge is how long a registry cache may go unused before it's removed automatically, as configured
+func registryCacheMaxAge(cfg config.Config) (time.Duration, error) {
+	if cfg.RegistryCacheMaxAge == "" {
+		return registry.DefaultCacheMaxAge, nil
+	}
+	maxAge, err := time.ParseDuration(cfg.RegistryCacheMaxAge)
+	if err != nil {
+		return 0, fmt.Errorf("invalid registry cache max age %s in your config file: %w", style.Symbol(cfg.RegistryCacheMaxAge), err)
+	}
+	return maxAge, nil
+}
+
+// registryRefreshInterval is how long after a refresh the registry caches aren't refreshed again, as configured
+func registryRefreshInterval(cfg config.Config) (time.Duration, error) {
+	if cfg.RegistryRefreshInterval == "" {
+		return registry.DefaultRefreshInterval, nil
+	}
+	interval, err := time.ParseDuration(cfg.RegistryRefreshInterval)
+	if err != nil {
+		return 0, fmt.Errorf("invalid registry refresh interval %s in your config file: %w", style.Symbol(cfg.RegistryRefreshInterval), err)
+	}
+	return interval, nil
 }
 
 func getConfig() (config.Config, error) {
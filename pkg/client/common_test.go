@@ -12,6 +12,7 @@ import (
 	"github.com/sclevine/spec/report"
 
 	"github.com/buildpacks/pack/internal/builder"
+	"github.com/buildpacks/pack/internal/config"
 	"github.com/buildpacks/pack/pkg/image"
 	"github.com/buildpacks/pack/pkg/logging"
 	"github.com/buildpacks/pack/pkg/testmocks"
@@ -25,6 +26,27 @@ func TestCommon(t *testing.T) {
 }
 
 func testCommon(t *testing.T, when spec.G, it spec.S) {
+	when("#registryToken", func() {
+		it("reads the token of the registry from its environment variable", func() {
+			t.Setenv("PACK_REGISTRY_TOKEN_MY_REGISTRY_2", "env-token")
+
+			h.AssertEq(t, registryToken(config.Registry{Name: "my-registry.2", Token: "config-token"}), "env-token")
+		})
+
+		it("doesn't read the tokens of other registries", func() {
+			t.Setenv("PACK_REGISTRY_TOKEN_OTHER_REGISTRY", "env-token")
+
+			h.AssertEq(t, registryToken(config.Registry{Name: "my-registry", Token: "config-token"}), "config-token")
+			h.AssertEq(t, registryToken(config.Registry{Name: "my-registry"}), "")
+		})
+
+		it("never sends a token from the environment to the official registry", func() {
+			t.Setenv("PACK_REGISTRY_TOKEN_OFFICIAL", "env-token")
+
+			h.AssertEq(t, registryToken(config.Registry{Name: config.OfficialRegistryName}), "")
+		})
+	})
+
 	when("#resolveRunImage", func() {
 		var (
 			subject         *Client
@@ -14,6 +14,7 @@ import (
 
 	pubbldr "github.com/buildpacks/pack/builder"
 	"github.com/buildpacks/pack/internal/builder"
+	"github.com/buildpacks/pack/internal/lifecycle"
 	"github.com/buildpacks/pack/internal/paths"
 	"github.com/buildpacks/pack/internal/style"
 	"github.com/buildpacks/pack/pkg/buildpack"
@@ -46,6 +47,9 @@ type CreateBuilderOptions struct {
 	// Buildpack registry name. Defines where all registry buildpacks will be pulled from.
 	Registry string
 
+	// How registry buildpacks are located.
+	RegistryLookup buildpack.RegistryLookup
+
 	// Strategy for updating images before a build.
 	PullPolicy image.PullPolicy
 
@@ -54,6 +58,9 @@ type CreateBuilderOptions struct {
 
 	// Target platforms to build builder images for
 	Targets []dist.Target
+
+	// Compression of the layers of the published builder, gzip by default.
+	LayerCompression LayerCompression
 }
 
 // CreateBuilder creates and saves a builder image to a registry with the provided options.
@@ -94,6 +101,11 @@ func (c *Client) CreateBuilder(ctx context.Context, opts CreateBuilderOptions) e
 }
 
 func (c *Client) createBuilderTarget(ctx context.Context, opts CreateBuilderOptions, target *dist.Target, multiArch bool) (string, error) {
+	if target != nil {
+		// use the build image and buildpacks of the architecture of the target
+		opts.Config = opts.Config.ForTarget(*target)
+	}
+
 	if err := c.validateConfig(ctx, opts, target); err != nil {
 		return "", err
 	}
@@ -125,6 +137,16 @@ func (c *Client) createBuilderTarget(ctx context.Context, opts CreateBuilderOpti
 		return "", err
 	}
 
+	if opts.Publish {
+		compressed, err := c.compressPublishedImage(ctx, opts.BuilderName, nil, opts.LayerCompression)
+		if err != nil {
+			return "", err
+		}
+		if compressed != "" {
+			return compressed, nil
+		}
+	}
+
 	if multiArch {
 		// We need to keep the identifier to create the image index
 		id, err := bldr.Image().Identifier()
@@ -228,6 +250,16 @@ func (c *Client) createBaseBuilder(ctx context.Context, opts CreateBuilderOption
 		return nil, errors.Wrap(err, "lookup image OS")
 	}
 
+	if target != nil && target.Arch != "" && architecture != "" && architecture != target.Arch {
+		return nil, errors.Errorf(
+			"build image %s is for architecture %s rather than %s, configure the build image of the architecture in %s",
+			style.Symbol(opts.Config.Build.Image),
+			style.Symbol(architecture),
+			style.Symbol(target.Arch),
+			style.Symbol("[[architectures]]"),
+		)
+	}
+
 	if os == "windows" && !c.experimental {
 		return nil, NewExperimentError("Windows containers support is currently experimental.")
 	}
@@ -263,6 +295,7 @@ func (c *Client) fetchLifecycle(ctx context.Context, config pubbldr.LifecycleCon
 
 	var uri string
 	var err error
+	downloader := c.downloader
 	switch {
 	case config.Version != "":
 		v, err := semver.NewVersion(config.Version)
@@ -271,6 +304,7 @@ func (c *Client) fetchLifecycle(ctx context.Context, config pubbldr.LifecycleCon
 		}
 
 		uri = c.uriFromLifecycleVersion(*v, os, architecture)
+		downloader = c.releasedLifecycleDownloader()
 	case config.URI != "":
 		uri, err = paths.FilePathToURI(config.URI, relativeBaseDir)
 		if err != nil {
@@ -278,9 +312,10 @@ func (c *Client) fetchLifecycle(ctx context.Context, config pubbldr.LifecycleCon
 		}
 	default:
 		uri = c.uriFromLifecycleVersion(*semver.MustParse(builder.DefaultLifecycleVersion), os, architecture)
+		downloader = c.releasedLifecycleDownloader()
 	}
 
-	blob, err := c.downloader.Download(ctx, uri)
+	blob, err := downloader.Download(ctx, uri)
 	if err != nil {
 		return nil, errors.Wrap(err, "downloading lifecycle")
 	}
@@ -294,25 +329,18 @@ func (c *Client) fetchLifecycle(ctx context.Context, config pubbldr.LifecycleCon
 }
 
 func (c *Client) addBuildpacksToBuilder(ctx context.Context, opts CreateBuilderOptions, bldr *builder.Builder) error {
-	for _, b := range opts.Config.Buildpacks {
-		if err := c.addConfig(ctx, buildpack.KindBuildpack, b, opts, bldr); err != nil {
-			return err
-		}
-	}
-	return nil
+	return c.addModulesToBuilder(ctx, buildpack.KindBuildpack, opts.Config.Buildpacks, opts, bldr)
 }
 
 func (c *Client) addExtensionsToBuilder(ctx context.Context, opts CreateBuilderOptions, bldr *builder.Builder) error {
-	for _, e := range opts.Config.Extensions {
-		if err := c.addConfig(ctx, buildpack.KindExtension, e, opts, bldr); err != nil {
-			return err
-		}
-	}
-	return nil
+	return c.addModulesToBuilder(ctx, buildpack.KindExtension, opts.Config.Extensions, opts, bldr)
 }
 
-func (c *Client) addConfig(ctx context.Context, kind string, config pubbldr.ModuleConfig, opts CreateBuilderOptions, bldr *builder.Builder) error {
-	c.logger.Debugf("Looking up %s %s", kind, style.Symbol(config.DisplayString()))
+// addModulesToBuilder downloads the modules of configs at once, then adds them to the builder in their order
+func (c *Client) addModulesToBuilder(ctx context.Context, kind string, configs []pubbldr.ModuleConfig, opts CreateBuilderOptions, bldr *builder.Builder) error {
+	if len(configs) == 0 {
+		return nil
+	}
 
 	builderOS, err := bldr.Image().OS()
 	if err != nil {
@@ -324,21 +352,38 @@ func (c *Client) addConfig(ctx context.Context, kind string, config pubbldr.Modu
 	}
 
 	target := &dist.Target{OS: builderOS, Arch: builderArch}
-	c.logger.Debugf("Downloading buildpack for platform: %s", target.ValuesAsPlatform())
-
-	mainBP, depBPs, err := c.buildpackDownloader.Download(ctx, config.URI, buildpack.DownloadOptions{
-		Daemon:          !opts.Publish,
-		ImageName:       config.ImageName,
-		ModuleKind:      kind,
-		PullPolicy:      opts.PullPolicy,
-		RegistryName:    opts.Registry,
-		RelativeBaseDir: opts.RelativeBaseDir,
-		Target:          target,
-	})
-	if err != nil {
-		return errors.Wrapf(err, "downloading %s", kind)
+	c.logger.Debugf("Downloading %ss for platform: %s", kind, target.ValuesAsPlatform())
+
+	downloads := make([]*moduleDownload, len(configs))
+	for i, config := range configs {
+		c.logger.Debugf("Looking up %s %s", kind, style.Symbol(config.DisplayString()))
+		downloads[i] = &moduleDownload{uri: config.URI, opts: buildpack.DownloadOptions{
+			Daemon:          !opts.Publish,
+			ImageName:       config.ImageName,
+			ModuleKind:      kind,
+			PullPolicy:      opts.PullPolicy,
+			RegistryName:    opts.Registry,
+			RelativeBaseDir: opts.RelativeBaseDir,
+			Target:          target,
+			RegistryLookup:  opts.RegistryLookup,
+		}}
+	}
+	c.downloadModules(ctx, downloads)
+
+	for i, config := range configs {
+		if err := c.addConfig(kind, config, downloads[i], bldr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Client) addConfig(kind string, config pubbldr.ModuleConfig, download *moduleDownload, bldr *builder.Builder) error {
+	if download.err != nil {
+		return errors.Wrapf(download.err, "downloading %s", kind)
 	}
-	err = validateModule(kind, mainBP, config.URI, config.ID, config.Version)
+	mainBP, depBPs := download.main, download.deps
+	err := validateModule(kind, mainBP, config.URI, config.ID, config.Version)
 	if err != nil {
 		return errors.Wrapf(err, "invalid %s", kind)
 	}
@@ -424,7 +469,7 @@ func (c *Client) uriFromLifecycleVersion(version semver.Version, os string, arch
 	arch := "x86-64"
 
 	if os == "windows" {
-		return fmt.Sprintf("https://github.com/buildpacks/lifecycle/releases/download/v%s/lifecycle-v%s+windows.%s.tgz", version.String(), version.String(), arch)
+		return lifecycle.ArchiveURL(c.lifecycleMirror, version.String(), "windows", arch)
 	}
 
 	if builder.SupportedLinuxArchitecture(architecture) {
@@ -434,5 +479,14 @@ func (c *Client) uriFromLifecycleVersion(version semver.Version, os string, arch
 		c.logger.Warnf("failed to find a lifecycle binary for requested architecture %s, defaulting to %s", style.Symbol(architecture), style.Symbol(arch))
 	}
 
-	return fmt.Sprintf("https://github.com/buildpacks/lifecycle/releases/download/v%s/lifecycle-v%s+linux.%s.tgz", version.String(), version.String(), arch)
+	return lifecycle.ArchiveURL(c.lifecycleMirror, version.String(), "linux", arch)
+}
+
+// releasedLifecycleDownloader downloads the archives of released lifecycle versions, verifying and caching them
+// unless a downloader was supplied to the client
+func (c *Client) releasedLifecycleDownloader() BlobDownloader {
+	if c.lifecycleDownloader != nil {
+		return c.lifecycleDownloader
+	}
+	return c.downloader
 }
@@ -11,6 +11,7 @@ import (
 
 	"github.com/buildpacks/imgutil/fakes"
 	"github.com/buildpacks/lifecycle/api"
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/system"
 	"github.com/golang/mock/gomock"
 	"github.com/heroku/color"
@@ -548,6 +549,34 @@ func testCreateBuilder(t *testing.T, when spec.G, it spec.S) {
 				h.AssertNil(t, err)
 			})
 
+			it("should download from the lifecycle mirror", func() {
+				prepareFetcherWithBuildImage()
+				prepareFetcherWithRunImages()
+				opts.Config.Lifecycle.URI = ""
+				opts.Config.Lifecycle.Version = "3.4.5"
+
+				subject, err := client.NewClient(
+					client.WithLogger(logger),
+					client.WithDownloader(mockDownloader),
+					client.WithImageFactory(mockImageFactory),
+					client.WithFetcher(mockImageFetcher),
+					client.WithDockerClient(mockDockerClient),
+					client.WithBuildpackDownloader(mockBuildpackDownloader),
+					client.WithLifecycleMirror("https://artifacts.example.com/lifecycle/"),
+				)
+				h.AssertNil(t, err)
+
+				mockDownloader.EXPECT().Download(
+					gomock.Any(),
+					"https://artifacts.example.com/lifecycle/v3.4.5/lifecycle-v3.4.5+linux.x86-64.tgz",
+				).Return(
+					blob.NewBlob(filepath.Join("testdata", "lifecycle", "platform-0.4")), nil,
+				)
+
+				err = subject.CreateBuilder(context.TODO(), opts)
+				h.AssertNil(t, err)
+			})
+
 			when("windows", func() {
 				it("should download from predetermined uri", func() {
 					opts.Config.Extensions = nil      // TODO: downloading extensions doesn't work yet; to be implemented in https://github.com/buildpacks/pack/issues/1489
@@ -1000,6 +1029,48 @@ func testCreateBuilder(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("targets are configured", func() {
+			it.Before(func() {
+				opts.Targets = []dist.Target{{OS: "linux", Arch: "arm64"}}
+				mockDockerClient.EXPECT().ServerVersion(gomock.Any()).Return(types.Version{Os: "linux", Arch: "arm64"}, nil).AnyTimes()
+			})
+
+			it("uses the build image and buildpacks of the architecture of the target", func() {
+				fakeArmBuildImage := fakes.NewImage("some/build-image-arm64", "", nil)
+				h.AssertNil(t, fakeArmBuildImage.SetLabel("io.buildpacks.stack.id", "some.stack.id"))
+				h.AssertNil(t, fakeArmBuildImage.SetLabel("io.buildpacks.stack.mixins", `["mixinX", "build:mixinY"]`))
+				h.AssertNil(t, fakeArmBuildImage.SetEnv("CNB_USER_ID", "1234"))
+				h.AssertNil(t, fakeArmBuildImage.SetEnv("CNB_GROUP_ID", "4321"))
+				h.AssertNil(t, fakeArmBuildImage.SetArchitecture("arm64"))
+				mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/build-image-arm64", gomock.Any()).Return(fakeArmBuildImage, nil)
+				prepareFetcherWithRunImages()
+				armBuildpack, err := buildpack.FromBuildpackRootBlob(blob.NewBlob(filepath.Join("testdata", "buildpack")), archive.DefaultTarWriterFactory(), nil)
+				h.AssertNil(t, err)
+				mockBuildpackDownloader.EXPECT().Download(gomock.Any(), "https://example.fake/bp-one-arm64.tgz", gomock.Any()).Return(armBuildpack, nil, nil)
+
+				opts.Config.Architectures = []pubbldr.ArchitectureConfig{{
+					Arch:       "arm64",
+					BuildImage: "some/build-image-arm64",
+					Buildpacks: []pubbldr.ModuleConfig{{
+						ModuleInfo: dist.ModuleInfo{ID: "bp.one"},
+						ImageOrURI: dist.ImageOrURI{BuildpackURI: dist.BuildpackURI{URI: "https://example.fake/bp-one-arm64.tgz"}},
+					}},
+				}}
+
+				h.AssertNil(t, subject.CreateBuilder(context.TODO(), opts))
+				h.AssertEq(t, fakeArmBuildImage.IsSaved(), true)
+				h.AssertEq(t, fakeBuildImage.IsSaved(), false)
+			})
+
+			it("fails when the build image is for another architecture", func() {
+				prepareFetcherWithBuildImage()
+				prepareFetcherWithRunImages()
+
+				err := subject.CreateBuilder(context.TODO(), opts)
+				h.AssertError(t, err, "build image 'some/build-image' is for architecture 'amd64' rather than 'arm64'")
+			})
+		})
+
 		when("flatten option is set", func() {
 			/*       1
 			 *    /    \
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/system"
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/pkg/errors"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/testmocks"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestDebugBundle(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "DebugBundle", testDebugBundle, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testDebugBundle(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject          *Client
+		mockDockerClient *testmocks.MockCommonAPIClient
+		mockController   *gomock.Controller
+		out              bytes.Buffer
+		tmpDir           string
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockDockerClient = testmocks.NewMockCommonAPIClient(mockController)
+
+		var err error
+		subject, err = NewClient(WithLogger(logging.NewLogWithWriters(&out, &out)), WithDockerClient(mockDockerClient))
+		h.AssertNil(t, err)
+
+		tmpDir, err = os.MkdirTemp("", "pack.debug.bundle.test.")
+		h.AssertNil(t, err)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+		os.RemoveAll(tmpDir)
+	})
+
+	when("#CollectDebugBundle", func() {
+		it("collects the version of pack, daemon info, config and logs", func() {
+			mockDockerClient.EXPECT().ServerVersion(gomock.Any()).Return(types.Version{Version: "24.0.7", APIVersion: "1.43"}, nil)
+			mockDockerClient.EXPECT().Info(gomock.Any()).Return(system.Info{OSType: "linux", Name: "some-host", NCPU: 4}, nil)
+
+			logDir := filepath.Join(tmpDir, "logs")
+			h.AssertNil(t, os.MkdirAll(logDir, 0755))
+			h.AssertNil(t, os.WriteFile(filepath.Join(logDir, "pack-20260101T000000000.log"), []byte("pack build\nlogin with password=hunter22\n"), 0600))
+
+			path := filepath.Join(tmpDir, "bundle.tar.gz")
+			names, err := subject.CollectDebugBundle(context.TODO(), DebugBundleOptions{
+				Path:   path,
+				Config: []byte("default-builder-image = \"some/builder\"\n"),
+				LogDir: logDir,
+			})
+			h.AssertNil(t, err)
+			h.AssertEq(t, names, []string{"config.toml", "daemon.json", "logs/pack-20260101T000000000.log", "pack.json"})
+
+			contents := readBundle(t, path)
+			h.AssertContains(t, contents["daemon.json"], `"version": "24.0.7"`)
+			h.AssertContains(t, contents["daemon.json"], `"cpus": 4`)
+			h.AssertNotContains(t, contents["daemon.json"], "some-host")
+			h.AssertContains(t, contents["pack.json"], `"supportedPlatformAPIs"`)
+			h.AssertContains(t, contents["logs/pack-20260101T000000000.log"], "login with password=[REDACTED]")
+		})
+
+		it("collects the options, plan, output and error of a failed build, redacting its env", func() {
+			mockDockerClient.EXPECT().ServerVersion(gomock.Any()).Return(types.Version{}, errors.New("daemon unreachable"))
+
+			path := filepath.Join(tmpDir, "bundle.tar.gz")
+			names, err := subject.CollectDebugBundle(context.TODO(), DebugBundleOptions{
+				Path: path,
+				Build: &BuildOptions{
+					Image:   "some/app",
+					Builder: "some/builder",
+					Env:     map[string]string{"DATABASE_URL": "postgres://app:s3cr3t-pw@db:5432/app", "FLAG": "on"},
+				},
+				Plan:     &BuildPlan{Builder: "some/builder", RunImage: "some/run"},
+				BuildLog: []byte("===> BUILDING\nconnecting to postgres://app:s3cr3t-pw@db:5432/app\nGITHUB_TOKEN: ghp_abc123\n"),
+				Err:      errors.New("failed with status code: 51"),
+			})
+			h.AssertNil(t, err)
+			h.AssertEq(t, names, []string{"build.json", "build.log", "daemon.json", "error.txt", "pack.json", "plan.json"})
+
+			contents := readBundle(t, path)
+			h.AssertContains(t, contents["daemon.json"], `"error": "daemon unreachable"`)
+			h.AssertContains(t, contents["build.json"], `"builder": "some/builder"`)
+			h.AssertContains(t, contents["build.json"], `"DATABASE_URL"`)
+			h.AssertNotContains(t, contents["build.json"], "s3cr3t")
+			h.AssertContains(t, contents["plan.json"], `"runImage": "some/run"`)
+			h.AssertContains(t, contents["build.log"], "connecting to [REDACTED]")
+			h.AssertContains(t, contents["build.log"], "GITHUB_TOKEN: [REDACTED]")
+			h.AssertEq(t, contents["error.txt"], "failed with status code: 51\n")
+		})
+	})
+}
@@ -0,0 +1,278 @@
+package client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/buildpacks/imgutil"
+	"github.com/buildpacks/lifecycle/buildpack"
+	"github.com/buildpacks/lifecycle/platform"
+	"github.com/buildpacks/lifecycle/platform/files"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/sbom"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/dist"
+	"github.com/buildpacks/pack/pkg/image"
+)
+
+// Status of a layer in an ImageDiff
+const (
+	LayerAdded     = "added"
+	LayerRemoved   = "removed"
+	LayerChanged   = "changed"
+	LayerUnchanged = "unchanged"
+)
+
+// DiffImagesOptions configures DiffImages
+type DiffImagesOptions struct {
+	// Daemon is true to read the images from the docker daemon, false to read them from a registry
+	Daemon bool
+}
+
+// ImageDiff is the difference between two app images built by buildpacks
+type ImageDiff struct {
+	Base   string `json:"base"`
+	Target string `json:"target"`
+
+	// Sizes of the images in bytes, 0 when unknown
+	BaseSize   int64 `json:"baseSize"`
+	TargetSize int64 `json:"targetSize"`
+
+	// Run images of the images, and whether their layers differ
+	BaseRunImage    string `json:"baseRunImage"`
+	TargetRunImage  string `json:"targetRunImage"`
+	RunImageChanged bool   `json:"runImageChanged"`
+
+	// AppChanged is true when the layers of the app source code differ
+	AppChanged bool `json:"appChanged"`
+
+	// Buildpacks that contributed layers to either image, in the order of the target image followed by the ones
+	// only contributing to the base image
+	Buildpacks []BuildpackDiff `json:"buildpacks"`
+
+	// Dependencies added, removed or updated according to the SBOMs of the images, sorted by name
+	Dependencies []DependencyDiff `json:"dependencies"`
+
+	// DependenciesKnown is false when either image has no SBOM, so that Dependencies is empty
+	DependenciesKnown bool `json:"dependenciesKnown"`
+}
+
+// BuildpackDiff is the difference between the layers a buildpack contributed to two images
+type BuildpackDiff struct {
+	ID string `json:"id"`
+
+	// Versions of the buildpack, empty when it didn't contribute to an image
+	BaseVersion   string `json:"baseVersion,omitempty"`
+	TargetVersion string `json:"targetVersion,omitempty"`
+
+	// Layers of the buildpack, sorted by name
+	Layers []LayerDiff `json:"layers"`
+}
+
+// LayerDiff is the difference between a layer of two images
+type LayerDiff struct {
+	Name string `json:"name"`
+
+	// One of LayerAdded, LayerRemoved, LayerChanged or LayerUnchanged
+	Status string `json:"status"`
+
+	// Compressed sizes of the layer in bytes, 0 when unknown or missing from an image. Sizes are only known for
+	// images read from a registry.
+	BaseSize   int64 `json:"baseSize"`
+	TargetSize int64 `json:"targetSize"`
+}
+
+// DependencyDiff is a dependency added, removed or updated between two images
+type DependencyDiff struct {
+	Name string `json:"name"`
+
+	// Versions of the dependency, empty when it's missing from an image
+	BaseVersion   string `json:"baseVersion,omitempty"`
+	TargetVersion string `json:"targetVersion,omitempty"`
+}
+
+// DiffImages compares the layers contributed by each buildpack to two app images, the dependencies listed by their
+// SBOMs and their sizes.
+func (c *Client) DiffImages(ctx context.Context, base, target string, opts DiffImagesOptions) (*ImageDiff, error) {
+	baseImage, baseMetadata, err := c.fetchAppImage(ctx, base, opts.Daemon)
+	if err != nil {
+		return nil, err
+	}
+	targetImage, targetMetadata, err := c.fetchAppImage(ctx, target, opts.Daemon)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &ImageDiff{
+		Base:            base,
+		Target:          target,
+		BaseSize:        c.imageSize(ctx, !opts.Daemon, baseImage),
+		TargetSize:      c.imageSize(ctx, !opts.Daemon, targetImage),
+		BaseRunImage:    baseMetadata.RunImage.Reference,
+		TargetRunImage:  targetMetadata.RunImage.Reference,
+		RunImageChanged: baseMetadata.RunImage.TopLayer != targetMetadata.RunImage.TopLayer,
+		AppChanged:      !sameLayers(baseMetadata.App, targetMetadata.App),
+		Buildpacks:      diffBuildpackLayers(baseImage, targetImage, baseMetadata.Buildpacks, targetMetadata.Buildpacks, !opts.Daemon),
+	}
+
+	baseComponents, err := c.sbomComponents(baseImage, base)
+	if err != nil {
+		return nil, err
+	}
+	targetComponents, err := c.sbomComponents(targetImage, target)
+	if err != nil {
+		return nil, err
+	}
+	if baseComponents != nil && targetComponents != nil {
+		diff.DependenciesKnown = true
+		for _, change := range sbom.DiffComponents(baseComponents, targetComponents) {
+			diff.Dependencies = append(diff.Dependencies, DependencyDiff(change))
+		}
+	}
+	return diff, nil
+}
+
+func (c *Client) fetchAppImage(ctx context.Context, name string, daemon bool) (imgutil.Image, files.LayersMetadata, error) {
+	img, err := c.imageFetcher.Fetch(ctx, name, image.FetchOptions{Daemon: daemon, PullPolicy: image.PullNever})
+	if err != nil {
+		return nil, files.LayersMetadata{}, err
+	}
+
+	var metadata files.LayersMetadata
+	ok, err := dist.GetLabel(img, platform.LifecycleMetadataLabel, &metadata)
+	if err != nil {
+		return nil, files.LayersMetadata{}, err
+	}
+	if !ok {
+		return nil, files.LayersMetadata{}, errors.Errorf("%s wasn't built by buildpacks, it has no %s label", style.Symbol(name), style.Symbol(platform.LifecycleMetadataLabel))
+	}
+	return img, metadata, nil
+}
+
+func diffBuildpackLayers(baseImage, targetImage imgutil.Image, base, target []buildpack.LayersMetadata, remote bool) []BuildpackDiff {
+	baseByID := map[string]buildpack.LayersMetadata{}
+	for _, bp := range base {
+		baseByID[bp.ID] = bp
+	}
+
+	var (
+		diffs []BuildpackDiff
+		seen  = map[string]bool{}
+	)
+	for _, targetBuildpack := range target {
+		seen[targetBuildpack.ID] = true
+		baseBuildpack := baseByID[targetBuildpack.ID]
+		diffs = append(diffs, BuildpackDiff{
+			ID:            targetBuildpack.ID,
+			BaseVersion:   baseBuildpack.Version,
+			TargetVersion: targetBuildpack.Version,
+			Layers:        diffLayers(baseImage, targetImage, baseBuildpack.Layers, targetBuildpack.Layers, remote),
+		})
+	}
+	for _, baseBuildpack := range base {
+		if seen[baseBuildpack.ID] {
+			continue
+		}
+		diffs = append(diffs, BuildpackDiff{
+			ID:          baseBuildpack.ID,
+			BaseVersion: baseBuildpack.Version,
+			Layers:      diffLayers(baseImage, targetImage, baseBuildpack.Layers, nil, remote),
+		})
+	}
+	return diffs
+}
+
+func diffLayers(baseImage, targetImage imgutil.Image, base, target map[string]buildpack.LayerMetadata, remote bool) []LayerDiff {
+	var diffs []LayerDiff
+	for name, targetLayer := range target {
+		diff := LayerDiff{Name: name, Status: LayerAdded, TargetSize: layerSize(targetImage, targetLayer.SHA, remote)}
+		if baseLayer, ok := base[name]; ok {
+			diff.BaseSize = layerSize(baseImage, baseLayer.SHA, remote)
+			diff.Status = LayerChanged
+			if baseLayer.SHA == targetLayer.SHA {
+				diff.Status = LayerUnchanged
+			}
+		}
+		diffs = append(diffs, diff)
+	}
+	for name, baseLayer := range base {
+		if _, ok := target[name]; !ok {
+			diffs = append(diffs, LayerDiff{Name: name, Status: LayerRemoved, BaseSize: layerSize(baseImage, baseLayer.SHA, remote)})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Name < diffs[j].Name
+	})
+	return diffs
+}
+
+// layerSize returns the compressed size of the layer of img with the given diff ID, or 0 when it's unknown. Sizes
+// of images of the docker daemon are left unknown, since reading their layers means exporting the whole image.
+func layerSize(img imgutil.Image, diffID string, remote bool) int64 {
+	if !remote || diffID == "" || img.UnderlyingImage() == nil {
+		return 0
+	}
+	hash, err := v1.NewHash(diffID)
+	if err != nil {
+		return 0
+	}
+	layer, err := img.UnderlyingImage().LayerByDiffID(hash)
+	if err != nil {
+		return 0
+	}
+	size, err := layer.Size()
+	if err != nil {
+		return 0
+	}
+	return size
+}
+
+func sameLayers(a, b []files.LayerMetadata) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].SHA != b[i].SHA {
+			return false
+		}
+	}
+	return true
+}
+
+// sbomComponents returns the components listed by the SBOMs of the launch layers of img, or nil when it has none
+func (c *Client) sbomComponents(img imgutil.Image, name string) ([]sbom.Component, error) {
+	var sbomMD sbomMetadata
+	if _, err := dist.GetLabel(img, platform.LifecycleMetadataLabel, &sbomMD); err != nil {
+		return nil, err
+	}
+	if sbomMD.isMissing() {
+		c.logger.Debugf("%s has no SBoM, its dependencies aren't compared", style.Symbol(name))
+		return nil, nil
+	}
+
+	tmpDir, err := os.MkdirTemp("", "pack.image.diff.")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := extractImageSBOM(img, name, tmpDir); err != nil {
+		return nil, err
+	}
+	launchDir := filepath.Join(tmpDir, "layers", "sbom", "launch")
+	if _, err := os.Stat(launchDir); err != nil {
+		c.logger.Debugf("%s has no SBoM of launch layers, its dependencies aren't compared", style.Symbol(name))
+		return nil, nil
+	}
+
+	fragments, err := sbom.ReadFragments(launchDir)
+	if err != nil {
+		return nil, err
+	}
+	return append([]sbom.Component{}, sbom.Components(fragments)...), nil
+}
@@ -0,0 +1,171 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/archive"
+	"github.com/buildpacks/pack/pkg/image"
+	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/testmocks"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestDiffImages(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "DiffImages", testDiffImages, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testDiffImages(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject          *Client
+		mockImageFetcher *testmocks.MockImageFetcher
+		mockDockerClient *testmocks.MockCommonAPIClient
+		mockController   *gomock.Controller
+		out              bytes.Buffer
+		tmpDir           string
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockImageFetcher = testmocks.NewMockImageFetcher(mockController)
+		mockDockerClient = testmocks.NewMockCommonAPIClient(mockController)
+
+		var err error
+		subject, err = NewClient(WithLogger(logging.NewLogWithWriters(&out, &out)), WithFetcher(mockImageFetcher), WithDockerClient(mockDockerClient))
+		h.AssertNil(t, err)
+
+		tmpDir, err = os.MkdirTemp("", "pack.diff.images.test.")
+		h.AssertNil(t, err)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+		os.RemoveAll(tmpDir)
+	})
+
+	const baseMetadata = `{
+  "app": [{"sha": "sha256:app-1"}],
+  "runImage": {"topLayer": "sha256:run-1", "reference": "run-image@sha256:1"},
+  "buildpacks": [
+    {"key": "example/java", "version": "1.0.0", "layers": {
+      "jre": {"sha": "sha256:jre-17"},
+      "helper": {"sha": "sha256:helper-1"},
+      "agent": {"sha": "sha256:agent-1"}
+    }},
+    {"key": "example/procfile", "version": "2.0.0", "layers": {}}
+  ]
+}`
+	const targetMetadata = `{
+  "app": [{"sha": "sha256:app-2"}],
+  "runImage": {"topLayer": "sha256:run-1", "reference": "run-image@sha256:1"},
+  "buildpacks": [
+    {"key": "example/java", "version": "1.1.0", "layers": {
+      "jre": {"sha": "sha256:jre-21"},
+      "helper": {"sha": "sha256:helper-1"},
+      "cds": {"sha": "sha256:cds-1"}
+    }}
+  ]
+}`
+
+	// withSBOM adds an SBOM layer to metadata and img, listing the given version of the JRE
+	withSBOM := func(img *testmocks.MockImage, metadata, jreVersion string) string {
+		tarPath := filepath.Join(tmpDir, img.Name()+".tar")
+		h.AssertNil(t, os.MkdirAll(filepath.Dir(tarPath), 0755))
+		h.AssertNil(t, archive.CreateSingleFileTar(tarPath, "layers/sbom/launch/example_java/jre/sbom.cdx.json", fmt.Sprintf(`{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.4",
+  "components": [{"type": "library", "name": "jre", "version": "%s", "purl": "pkg:generic/jre@%s"}]
+}`, jreVersion, jreVersion)))
+
+		data, err := os.ReadFile(tarPath)
+		h.AssertNil(t, err)
+		sum := sha256.Sum256(data)
+		diffID := "sha256:" + hex.EncodeToString(sum[:])
+		h.AssertNil(t, img.AddLayerWithDiffID(tarPath, diffID))
+		return metadata[:len(metadata)-1] + fmt.Sprintf(`, "sbom": {"sha": "%s"}}`, diffID)
+	}
+
+	expectImage := func(name string, metadata string, jreVersion string, size int64) {
+		img := testmocks.NewImage(name, "", nil)
+		if jreVersion != "" {
+			metadata = withSBOM(img, metadata, jreVersion)
+		}
+		h.AssertNil(t, img.SetLabel("io.buildpacks.lifecycle.metadata", metadata))
+
+		mockImageFetcher.EXPECT().Fetch(gomock.Any(), name, image.FetchOptions{Daemon: true, PullPolicy: image.PullNever}).Return(img, nil)
+		mockDockerClient.EXPECT().ImageInspectWithRaw(gomock.Any(), name).Return(types.ImageInspect{Size: size}, nil, nil)
+	}
+
+	when("both images have SBoMs", func() {
+		it("compares their buildpack layers, dependencies and sizes", func() {
+			expectImage("some/app:1", baseMetadata, "17.0.1", 200)
+			expectImage("some/app:2", targetMetadata, "21.0.2", 250)
+
+			diff, err := subject.DiffImages(context.TODO(), "some/app:1", "some/app:2", DiffImagesOptions{Daemon: true})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, diff.BaseSize, int64(200))
+			h.AssertEq(t, diff.TargetSize, int64(250))
+			h.AssertEq(t, diff.RunImageChanged, false)
+			h.AssertEq(t, diff.TargetRunImage, "run-image@sha256:1")
+			h.AssertEq(t, diff.AppChanged, true)
+
+			h.AssertEq(t, diff.Buildpacks, []BuildpackDiff{
+				{
+					ID:            "example/java",
+					BaseVersion:   "1.0.0",
+					TargetVersion: "1.1.0",
+					Layers: []LayerDiff{
+						{Name: "agent", Status: LayerRemoved},
+						{Name: "cds", Status: LayerAdded},
+						{Name: "helper", Status: LayerUnchanged},
+						{Name: "jre", Status: LayerChanged},
+					},
+				},
+				{ID: "example/procfile", BaseVersion: "2.0.0"},
+			})
+
+			h.AssertEq(t, diff.DependenciesKnown, true)
+			h.AssertEq(t, diff.Dependencies, []DependencyDiff{
+				{Name: "jre", BaseVersion: "17.0.1", TargetVersion: "21.0.2"},
+			})
+		})
+	})
+
+	when("an image has no SBoM", func() {
+		it("doesn't compare dependencies", func() {
+			expectImage("some/app:1", baseMetadata, "", 200)
+			expectImage("some/app:2", targetMetadata, "21.0.2", 250)
+
+			diff, err := subject.DiffImages(context.TODO(), "some/app:1", "some/app:2", DiffImagesOptions{Daemon: true})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, diff.DependenciesKnown, false)
+			h.AssertEq(t, len(diff.Dependencies), 0)
+			h.AssertEq(t, len(diff.Buildpacks), 2)
+		})
+	})
+
+	when("an image wasn't built by buildpacks", func() {
+		it("returns an error", func() {
+			mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/app:1", gomock.Any()).Return(testmocks.NewImage("some/app:1", "", nil), nil)
+
+			_, err := subject.DiffImages(context.TODO(), "some/app:1", "some/app:2", DiffImagesOptions{Daemon: true})
+			h.AssertError(t, err, "'some/app:1' wasn't built by buildpacks")
+		})
+	})
+}
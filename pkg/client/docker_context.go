@@ -57,6 +57,16 @@ type dockerConfigMetadata struct {
 
 func ProcessDockerContext(logger logging.Logger) error {
 	dockerHost := os.Getenv(dockerHostEnvVar)
+	if dockerHost == PodmanDockerHost {
+		socket, err := PodmanSocket()
+		if err != nil {
+			return errors.Wrapf(err, "resolving '%s=%s'", dockerHostEnvVar, PodmanDockerHost)
+		}
+		os.Setenv(dockerHostEnvVar, socket)
+		logger.Debugf("using the Podman socket '%s'", socket)
+		return nil
+	}
+
 	if dockerHost == "" {
 		dockerConfigDir, err := configDir()
 		if err != nil {
@@ -71,6 +81,10 @@ func ProcessDockerContext(logger logging.Logger) error {
 
 		if skip(configuration) {
 			logger.Debug("docker context is default or empty, skipping it")
+			if socket, ok := detectPodmanSocket(); ok {
+				os.Setenv(dockerHostEnvVar, socket)
+				logger.Debugf("no docker socket at '%s', using the Podman socket '%s'", defaultDockerSocket, socket)
+			}
 			return nil
 		}
 
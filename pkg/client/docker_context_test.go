@@ -2,8 +2,11 @@ package client_test
 
 import (
 	"bytes"
+	"fmt"
+	"net"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 
@@ -50,6 +53,87 @@ func testProcessDockerContext(t *testing.T, when spec.G, it spec.S) {
 		})
 	})
 
+	when("Podman", func() {
+		var (
+			runtimeDir string
+			listener   net.Listener
+		)
+
+		it.Before(func() {
+			h.SkipIf(t, runtime.GOOS == "windows", "Podman sockets are unix sockets")
+
+			listener = nil
+			var err error
+			runtimeDir, err = os.MkdirTemp("", "podman-runtime")
+			h.AssertNil(t, err)
+			os.Setenv("XDG_RUNTIME_DIR", runtimeDir)
+			os.Setenv("CONTAINER_HOST", "")
+		})
+
+		it.After(func() {
+			if listener != nil {
+				listener.Close()
+			}
+			os.Unsetenv("XDG_RUNTIME_DIR")
+			os.Unsetenv("CONTAINER_HOST")
+			h.AssertNil(t, os.RemoveAll(runtimeDir))
+		})
+
+		listen := func() string {
+			socket := filepath.Join(runtimeDir, "podman", "podman.sock")
+			h.AssertNil(t, os.MkdirAll(filepath.Dir(socket), 0700))
+			var err error
+			listener, err = net.Listen("unix", socket)
+			h.AssertNil(t, err)
+			return socket
+		}
+
+		when("env DOCKER_HOST is podman", func() {
+			it.Before(func() {
+				os.Setenv("DOCKER_HOST", "podman")
+			})
+
+			it("uses the rootless Podman socket of the user", func() {
+				socket := listen()
+
+				h.AssertNil(t, client.ProcessDockerContext(logger))
+				h.AssertEq(t, os.Getenv("DOCKER_HOST"), "unix://"+socket)
+				h.AssertContains(t, outBuf.String(), fmt.Sprintf("using the Podman socket 'unix://%s'", socket))
+			})
+
+			it("uses CONTAINER_HOST when it's set", func() {
+				os.Setenv("CONTAINER_HOST", "ssh://core@localhost:2222/run/podman/podman.sock")
+
+				h.AssertNil(t, client.ProcessDockerContext(logger))
+				h.AssertEq(t, os.Getenv("DOCKER_HOST"), "ssh://core@localhost:2222/run/podman/podman.sock")
+			})
+
+			it("errors when there's no Podman socket", func() {
+				h.SkipIf(t, isSocket("/run/podman/podman.sock"), "the host runs rootful Podman")
+
+				err := client.ProcessDockerContext(logger)
+				h.AssertError(t, err, "resolving 'DOCKER_HOST=podman'")
+				h.AssertError(t, err, "no Podman socket at")
+			})
+		})
+
+		when("env DOCKER_HOST is empty and there's no docker socket", func() {
+			it.Before(func() {
+				h.SkipIf(t, isSocket("/var/run/docker.sock"), "the host has a docker socket")
+				os.Setenv("DOCKER_HOST", "")
+				setDockerConfig(t, happyCase, "default-context")
+			})
+
+			it("detects the Podman socket", func() {
+				socket := listen()
+
+				h.AssertNil(t, client.ProcessDockerContext(logger))
+				h.AssertEq(t, os.Getenv("DOCKER_HOST"), "unix://"+socket)
+				h.AssertContains(t, outBuf.String(), fmt.Sprintf("no docker socket at '/var/run/docker.sock', using the Podman socket 'unix://%s'", socket))
+			})
+		})
+	})
+
 	when("env DOCKER_HOST is empty", func() {
 		it.Before(func() {
 			os.Setenv("DOCKER_HOST", "")
@@ -200,3 +284,8 @@ func setDockerConfig(t *testing.T, test, context string) {
 	err = os.Setenv("DOCKER_CONFIG", contextDir)
 	h.AssertNil(t, err)
 }
+
+func isSocket(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode()&os.ModeSocket != 0
+}
@@ -0,0 +1,250 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types/system"
+	"github.com/dustin/go-humanize"
+
+	"github.com/buildpacks/pack/internal/build"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/image"
+)
+
+// DiagnosticStatus is the outcome of a single environment check
+type DiagnosticStatus string
+
+const (
+	DiagnosticOK      DiagnosticStatus = "ok"
+	DiagnosticWarning DiagnosticStatus = "warning"
+	DiagnosticFailed  DiagnosticStatus = "failed"
+)
+
+// MinFreeDiskSpace is the free disk space below which the doctor warns, builds easily need a few GB
+// for the builder, run image, cache and app layers
+const MinFreeDiskSpace = 2 * 1024 * 1024 * 1024
+
+// Diagnostic is the result of a single environment check
+type Diagnostic struct {
+	// Name of the check, e.g. "Docker daemon"
+	Name string
+
+	Status DiagnosticStatus
+
+	// What the check found
+	Message string
+
+	// An actionable suggestion to resolve a warning or failure, empty when the check passed
+	Fix string
+}
+
+// DoctorOptions configures the environment checks of Doctor
+type DoctorOptions struct {
+	// Builder that should be compatible with this version of pack, usually the default builder
+	DefaultBuilder string
+
+	// Builders whose registries should be reachable
+	TrustedBuilders []string
+
+	// Directories pack writes to that need free disk space, e.g. the pack home and temp directory
+	Directories []string
+
+	// Proxy settings to check, read from the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables when nil
+	ProxyConfig *ProxyConfig
+}
+
+// Doctor checks the local environment for common problems: daemon reachability, platform API compatibility
+// of the default builder, free disk space, proxy settings, experimental features and reachability of trusted builders.
+func (c *Client) Doctor(ctx context.Context, opts DoctorOptions) []Diagnostic {
+	var diagnostics []Diagnostic
+
+	daemonInfo, daemonDiagnostic := c.checkDaemon(ctx)
+	diagnostics = append(diagnostics, daemonDiagnostic)
+
+	if opts.DefaultBuilder != "" {
+		diagnostics = append(diagnostics, c.checkPlatformAPI(opts.DefaultBuilder, daemonInfo != nil))
+	}
+
+	dirs := opts.Directories
+	if daemonInfo != nil && daemonInfo.DockerRootDir != "" {
+		// only meaningful when the daemon runs on this machine
+		if _, err := os.Stat(daemonInfo.DockerRootDir); err == nil {
+			dirs = append(dirs, daemonInfo.DockerRootDir)
+		}
+	}
+	for _, dir := range dirs {
+		diagnostics = append(diagnostics, checkDiskSpace(dir))
+	}
+
+	diagnostics = append(diagnostics, checkProxy(c.processProxyConfig(opts.ProxyConfig), daemonInfo))
+	diagnostics = append(diagnostics, c.checkExperimental())
+
+	for _, builderName := range opts.TrustedBuilders {
+		diagnostics = append(diagnostics, c.checkBuilderReachable(builderName))
+	}
+
+	return diagnostics
+}
+
+func (c *Client) checkDaemon(ctx context.Context) (*system.Info, Diagnostic) {
+	diagnostic := Diagnostic{Name: "Docker daemon"}
+
+	version, err := c.docker.ServerVersion(ctx)
+	if err != nil {
+		diagnostic.Status = DiagnosticFailed
+		diagnostic.Message = fmt.Sprintf("unable to reach the daemon: %s", err)
+		diagnostic.Fix = "Start Docker (or Podman with its Docker-compatible socket) or point DOCKER_HOST at a running daemon"
+		return nil, diagnostic
+	}
+
+	diagnostic.Status = DiagnosticOK
+	diagnostic.Message = fmt.Sprintf("version %s (API %s, %s/%s)", version.Version, version.APIVersion, version.Os, version.Arch)
+	if isPodmanVersion(version) {
+		diagnostic.Message = "Podman " + diagnostic.Message
+	}
+
+	info, err := c.docker.Info(ctx)
+	if err != nil {
+		return nil, diagnostic
+	}
+	return &info, diagnostic
+}
+
+func (c *Client) checkPlatformAPI(builderName string, daemon bool) Diagnostic {
+	diagnostic := Diagnostic{Name: fmt.Sprintf("Platform API of %s", builderName)}
+
+	var (
+		info *BuilderInfo
+		err  error
+	)
+	if daemon {
+		info, err = c.InspectBuilder(builderName, true)
+	}
+	if err == nil && info == nil {
+		info, err = c.InspectBuilder(builderName, false)
+	}
+	if err != nil || info == nil {
+		diagnostic.Status = DiagnosticWarning
+		diagnostic.Message = "unable to inspect the builder"
+		if err != nil {
+			diagnostic.Message = fmt.Sprintf("unable to inspect the builder: %s", err)
+		}
+		diagnostic.Fix = fmt.Sprintf("Check that %s exists and is accessible, or select another builder with `pack config default-builder`", style.Symbol(builderName))
+		return diagnostic
+	}
+
+	platformAPIs := info.Lifecycle.APIs.Platform
+	version, err := build.FindLatestSupported(append(platformAPIs.Deprecated, platformAPIs.Supported...), nil)
+	if err != nil {
+		diagnostic.Status = DiagnosticFailed
+		diagnostic.Message = fmt.Sprintf("the builder supports Platform APIs %s, pack supports %s",
+			strings.Join(platformAPIs.Supported.AsStrings(), ", "), strings.Join(build.SupportedPlatformAPIVersions.AsStrings(), ", "))
+		diagnostic.Fix = "Upgrade pack or use a builder with a lifecycle supported by this version of pack"
+		return diagnostic
+	}
+
+	diagnostic.Status = DiagnosticOK
+	diagnostic.Message = fmt.Sprintf("builds will use Platform API %s", version.String())
+	return diagnostic
+}
+
+func checkDiskSpace(dir string) Diagnostic {
+	diagnostic := Diagnostic{Name: fmt.Sprintf("Disk space in %s", dir)}
+
+	free, err := freeDiskSpace(dir)
+	if err != nil {
+		diagnostic.Status = DiagnosticWarning
+		diagnostic.Message = fmt.Sprintf("unable to determine free disk space: %s", err)
+		return diagnostic
+	}
+
+	diagnostic.Message = fmt.Sprintf("%s free", humanize.Bytes(free))
+	if free < MinFreeDiskSpace {
+		diagnostic.Status = DiagnosticWarning
+		diagnostic.Fix = "Free up disk space, e.g. remove unused images with `docker image prune` and old build caches"
+		return diagnostic
+	}
+
+	diagnostic.Status = DiagnosticOK
+	return diagnostic
+}
+
+func checkProxy(proxyConfig ProxyConfig, daemonInfo *system.Info) Diagnostic {
+	diagnostic := Diagnostic{Name: "Proxy settings", Status: DiagnosticOK}
+
+	proxies := map[string]string{
+		"HTTP_PROXY":  proxyConfig.HTTPProxy,
+		"HTTPS_PROXY": proxyConfig.HTTPSProxy,
+	}
+
+	var (
+		configured []string
+		invalid    []string
+	)
+	for env, value := range proxies {
+		if value == "" {
+			continue
+		}
+
+		proxyURL, err := url.Parse(value)
+		if err != nil || proxyURL.Host == "" {
+			invalid = append(invalid, env)
+			continue
+		}
+		configured = append(configured, fmt.Sprintf("%s=%s", env, proxyURL.Redacted()))
+	}
+	sort.Strings(configured)
+	sort.Strings(invalid)
+
+	switch {
+	case len(invalid) > 0:
+		diagnostic.Status = DiagnosticFailed
+		diagnostic.Message = fmt.Sprintf("%s is not a valid URL", strings.Join(invalid, ", "))
+		diagnostic.Fix = "Set the proxy to a URL such as http://proxy.example.com:3128"
+	case len(configured) == 0:
+		diagnostic.Message = "no proxy configured"
+	default:
+		diagnostic.Message = strings.Join(configured, ", ")
+		if proxyConfig.NoProxy != "" {
+			diagnostic.Message += ", NO_PROXY=" + proxyConfig.NoProxy
+		}
+
+		if daemonInfo != nil && daemonInfo.HTTPProxy == "" && daemonInfo.HTTPSProxy == "" {
+			diagnostic.Status = DiagnosticWarning
+			diagnostic.Fix = "The Docker daemon has no proxy configured, images pulled by the daemon may fail to download. Configure the proxy for the daemon as well"
+		}
+	}
+
+	return diagnostic
+}
+
+func (c *Client) checkExperimental() Diagnostic {
+	diagnostic := Diagnostic{Name: "Experimental features", Status: DiagnosticOK}
+	if c.experimental {
+		diagnostic.Message = "enabled"
+		return diagnostic
+	}
+
+	diagnostic.Message = "disabled, enable them with `pack config experimental true`"
+	return diagnostic
+}
+
+func (c *Client) checkBuilderReachable(builderName string) Diagnostic {
+	diagnostic := Diagnostic{Name: fmt.Sprintf("Trusted builder %s", builderName)}
+
+	if !c.imageFetcher.CheckReadAccess(builderName, image.FetchOptions{Daemon: false}) {
+		diagnostic.Status = DiagnosticWarning
+		diagnostic.Message = "unable to reach the builder in its registry"
+		diagnostic.Fix = "Check your network and registry credentials (e.g. `docker login`), or untrust the builder with `pack config trusted-builders remove`"
+		return diagnostic
+	}
+
+	diagnostic.Status = DiagnosticOK
+	diagnostic.Message = "reachable"
+	return diagnostic
+}
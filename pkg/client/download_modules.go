@@ -0,0 +1,43 @@
+package client
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/buildpacks/pack/pkg/buildpack"
+)
+
+// DefaultDownloadWorkers is how many buildpacks and extensions are downloaded at once, unless set with
+// WithDownloadWorkers
+const DefaultDownloadWorkers = 4
+
+// moduleDownload is a buildpack or extension to download along with its dependencies, and the result of downloading it
+type moduleDownload struct {
+	uri  string
+	opts buildpack.DownloadOptions
+
+	main buildpack.BuildModule
+	deps []buildpack.BuildModule
+	err  error
+}
+
+// downloadModules downloads the modules, c.downloadWorkers at a time. A module failing to download doesn't stop the
+// others, so that the callers report the first failure in the order of the modules rather than in the order they
+// failed in.
+func (c *Client) downloadModules(ctx context.Context, downloads []*moduleDownload) {
+	workers := c.downloadWorkers
+	if workers < 1 {
+		workers = DefaultDownloadWorkers
+	}
+
+	var group errgroup.Group
+	group.SetLimit(workers)
+	for _, download := range downloads {
+		group.Go(func() error {
+			download.main, download.deps, download.err = c.buildpackDownloader.Download(ctx, download.uri, download.opts)
+			return nil
+		})
+	}
+	_ = group.Wait()
+}
@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/heroku/color"
+	"github.com/pkg/errors"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/buildpack"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestDownloadModules(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "DownloadModules", testDownloadModules, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+// countingDownloader fails for the URI "fail" and records how many downloads ran at once
+type countingDownloader struct {
+	mu        sync.Mutex
+	running   int
+	maxActive int
+}
+
+func (d *countingDownloader) Download(ctx context.Context, uri string, opts buildpack.DownloadOptions) (buildpack.BuildModule, []buildpack.BuildModule, error) {
+	d.mu.Lock()
+	d.running++
+	d.maxActive = max(d.maxActive, d.running)
+	d.mu.Unlock()
+
+	time.Sleep(50 * time.Millisecond)
+
+	d.mu.Lock()
+	d.running--
+	d.mu.Unlock()
+	if uri == "fail" {
+		return nil, nil, errors.New("download failed")
+	}
+	return nil, nil, nil
+}
+
+func testDownloadModules(t *testing.T, when spec.G, it spec.S) {
+	when("#downloadModules", func() {
+		it("downloads the modules with at most the given number of workers", func() {
+			downloader := &countingDownloader{}
+			subject := &Client{buildpackDownloader: downloader}
+			WithDownloadWorkers(2)(subject)
+
+			downloads := []*moduleDownload{{uri: "some-uri"}, {uri: "fail"}, {uri: "other-uri"}, {uri: "another-uri"}}
+			subject.downloadModules(context.TODO(), downloads)
+
+			h.AssertTrue(t, downloader.maxActive > 1 && downloader.maxActive <= 2)
+			h.AssertNil(t, downloads[0].err)
+			h.AssertError(t, downloads[1].err, "download failed")
+			h.AssertNil(t, downloads[2].err)
+			h.AssertNil(t, downloads[3].err)
+		})
+
+		it("defaults to DefaultDownloadWorkers", func() {
+			downloader := &countingDownloader{}
+			subject := &Client{buildpackDownloader: downloader}
+
+			var downloads []*moduleDownload
+			for i := 0; i < DefaultDownloadWorkers*2; i++ {
+				downloads = append(downloads, &moduleDownload{uri: "some-uri"})
+			}
+			subject.downloadModules(context.TODO(), downloads)
+
+			h.AssertTrue(t, downloader.maxActive > 1 && downloader.maxActive <= DefaultDownloadWorkers)
+		})
+	})
+}
@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 
+	"github.com/buildpacks/imgutil"
 	"github.com/buildpacks/lifecycle/layers"
 	"github.com/buildpacks/lifecycle/platform"
 	"github.com/buildpacks/lifecycle/platform/files"
@@ -37,7 +38,12 @@ const (
 // It reads the SBOM metadata of an image then
 // pulls the corresponding diffId, if it exists
 func (c *Client) DownloadSBOM(name string, options DownloadSBOMOptions) error {
-	img, err := c.imageFetcher.Fetch(context.Background(), name, image.FetchOptions{Daemon: options.Daemon, PullPolicy: image.PullNever})
+	return c.extractSBOM(name, options.Daemon, options.DestinationDir)
+}
+
+// extractSBOM extracts the SBOM layer of an image to dir
+func (c *Client) extractSBOM(name string, daemon bool, dir string) error {
+	img, err := c.imageFetcher.Fetch(context.Background(), name, image.FetchOptions{Daemon: daemon, PullPolicy: image.PullNever})
 	if err != nil {
 		if errors.Cause(err) == image.ErrNotFound {
 			c.logger.Warnf("if the image is saved on a registry run with the flag '--remote', for example: 'pack sbom download --remote %s'", name)
@@ -45,7 +51,11 @@ func (c *Client) DownloadSBOM(name string, options DownloadSBOMOptions) error {
 		}
 		return err
 	}
+	return extractImageSBOM(img, name, dir)
+}
 
+// extractImageSBOM extracts the SBOM layer of img, named name, to dir
+func extractImageSBOM(img imgutil.Image, name string, dir string) error {
 	var sbomMD sbomMetadata
 	if _, err := dist.GetLabel(img, platform.LifecycleMetadataLabel, &sbomMD); err != nil {
 		return err
@@ -61,5 +71,5 @@ func (c *Client) DownloadSBOM(name string, options DownloadSBOMOptions) error {
 	}
 	defer rc.Close()
 
-	return layers.Extract(rc, options.DestinationDir)
+	return layers.Extract(rc, dir)
 }
@@ -1,5 +1,7 @@
 package client
 
+import "github.com/buildpacks/pack/pkg/errcode"
+
 // ExperimentError denotes that an experimental feature was trying to be used without experimental features enabled.
 type ExperimentError struct {
 	msg string
@@ -13,6 +15,10 @@ func (ee ExperimentError) Error() string {
 	return ee.msg
 }
 
+func (ee ExperimentError) ErrorCode() errcode.Code {
+	return errcode.ExperimentalFeature
+}
+
 // SoftError is an error that is not intended to be displayed.
 type SoftError struct{}
 
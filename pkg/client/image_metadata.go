@@ -0,0 +1,164 @@
+package client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/buildpacks/imgutil"
+	"github.com/buildpacks/imgutil/local"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// reservedLabelPrefix is the prefix of the labels set by the lifecycle and pack, which builds can't override
+const reservedLabelPrefix = "io.buildpacks."
+
+func validateImageMetadata(opts BuildOptions) error {
+	for key := range opts.Labels {
+		if key == "" {
+			return errors.New("labels must have a name")
+		}
+		if strings.HasPrefix(key, reservedLabelPrefix) {
+			return errors.Errorf("label %s is reserved, labels starting with %s are set by buildpacks", style.Symbol(key), style.Symbol(reservedLabelPrefix))
+		}
+	}
+	for key := range opts.Annotations {
+		if key == "" {
+			return errors.New("annotations must have a name")
+		}
+	}
+	if (len(opts.Labels) > 0 || len(opts.Annotations) > 0) && opts.Layout() {
+		return errors.New("labels and annotations can't be set on images exported to OCI layout")
+	}
+	return nil
+}
+
+// finishImage sets the labels and annotations of opts on the image exported by the lifecycle, and compresses its layers
+// with zstd when opts asks for it, writing the image once. It returns the identifier of the rewritten image, its
+// reference by digest when published or its ID in the daemon otherwise, empty when the image is unchanged.
+func (c *Client) finishImage(ctx context.Context, imageName string, opts BuildOptions) (string, error) {
+	if opts.Publish {
+		return c.rewritePublishedImage(ctx, imageName, opts.AdditionalTags, c.imageMetadataEdit(opts), opts.LayerCompression)
+	}
+	if len(opts.Labels) == 0 && len(opts.Annotations) == 0 {
+		return "", nil
+	}
+	id, err := c.setImageMetadata(imageName, opts)
+	return id, errors.Wrap(err, "setting labels and annotations")
+}
+
+// imageMetadataEdit sets the labels and annotations of opts on a published image, it is nil when there are none
+func (c *Client) imageMetadataEdit(opts BuildOptions) func(v1.Image) (v1.Image, error) {
+	if len(opts.Labels) == 0 && len(opts.Annotations) == 0 {
+		return nil
+	}
+	return func(img v1.Image) (v1.Image, error) {
+		if len(opts.Labels) > 0 {
+			configFile, err := img.ConfigFile()
+			if err != nil {
+				return nil, err
+			}
+			configFile = configFile.DeepCopy()
+			if configFile.Config.Labels == nil {
+				configFile.Config.Labels = map[string]string{}
+			}
+			for _, key := range sortedKeys(opts.Labels) {
+				c.logger.Debugf("Setting label %s", style.Symbol(key))
+				configFile.Config.Labels[key] = opts.Labels[key]
+			}
+			if img, err = mutate.ConfigFile(img, configFile); err != nil {
+				return nil, errors.Wrap(err, "setting labels")
+			}
+		}
+		if len(opts.Annotations) > 0 {
+			img = mutate.Annotations(img, opts.Annotations).(v1.Image)
+		}
+		return img, nil
+	}
+}
+
+// setImageMetadata sets the labels of opts on the image exported by the lifecycle to the daemon, and saves it under its
+// name and additional tags, returning its new ID. The creation time of the image is kept.
+func (c *Client) setImageMetadata(imageName string, opts BuildOptions) (string, error) {
+	img, err := local.NewImage(imageName, c.docker, imgutil.FromBaseImage(imageName))
+	if err != nil {
+		return "", err
+	}
+	createdAt, err := img.CreatedAt()
+	if err != nil {
+		return "", err
+	}
+	// imgutil sets the creation time of images when saving them
+	if img, err = local.NewImage(imageName, c.docker, imgutil.FromBaseImage(imageName), imgutil.WithCreatedAt(createdAt), imgutil.WithHistory()); err != nil {
+		return "", err
+	}
+
+	for _, key := range sortedKeys(opts.Labels) {
+		c.logger.Debugf("Setting label %s", style.Symbol(key))
+		if err := img.SetLabel(key, opts.Labels[key]); err != nil {
+			return "", err
+		}
+	}
+	if len(opts.Annotations) > 0 {
+		c.logger.Warn("Annotations are only kept by registries, they aren't set on images of the docker daemon")
+	}
+	if err := img.Save(opts.AdditionalTags...); err != nil {
+		return "", err
+	}
+	id, err := img.Identifier()
+	if err != nil {
+		return "", err
+	}
+	return id.String(), nil
+}
+
+// recordFinishedImage records the identifier returned by finishImage in the report at path, in place of the one of the
+// image the lifecycle exported. The size of the manifest of a rewritten published image isn't recorded anymore.
+func recordFinishedImage(path, identifier string, publish bool) error {
+	var report map[string]interface{}
+	if _, err := toml.DecodeFile(path, &report); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	image, ok := report["image"].(map[string]interface{})
+	if !ok {
+		image = map[string]interface{}{}
+		report["image"] = image
+	}
+	if publish {
+		image["digest"] = identifier[strings.LastIndex(identifier, "@")+1:]
+		delete(image, "manifest-size")
+	} else {
+		image["image-id"] = identifier
+	}
+
+	file, err := os.Create(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := toml.NewEncoder(file).Encode(report); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
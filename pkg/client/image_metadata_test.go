@@ -0,0 +1,193 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestImageMetadata(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "ImageMetadata", testImageMetadata, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testImageMetadata(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject        *Client
+		out            bytes.Buffer
+		server         *httptest.Server
+		rejectZstd     bool
+		manifestWrites atomic.Int32
+		tag            name.Tag
+		originalDigest string
+	)
+
+	it.Before(func() {
+		rejectZstd = false
+		manifestWrites.Store(0)
+		reg := registry.New(registry.Logger(log.New(io.Discard, "", 0)))
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPut && strings.Contains(r.URL.Path, "/manifests/") {
+				body, _ := io.ReadAll(r.Body)
+				if rejectZstd && strings.Contains(string(body), string(types.OCILayerZStd)) {
+					http.Error(w, "unsupported media type", http.StatusBadRequest)
+					return
+				}
+				manifestWrites.Add(1)
+				r.Body = io.NopCloser(bytes.NewReader(body))
+			}
+			reg.ServeHTTP(w, r)
+		}))
+		subject = &Client{keychain: authn.DefaultKeychain, logger: logging.NewLogWithWriters(&out, &out)}
+
+		img, err := random.Image(1024, 2)
+		h.AssertNil(t, err)
+		tag, err = name.NewTag(strings.TrimPrefix(server.URL, "http://") + "/some/app:latest")
+		h.AssertNil(t, err)
+		h.AssertNil(t, remote.Write(tag, img))
+		digest, err := img.Digest()
+		h.AssertNil(t, err)
+		originalDigest = digest.String()
+		manifestWrites.Store(0)
+	})
+
+	it.After(func() {
+		server.Close()
+	})
+
+	when("#finishImage", func() {
+		var opts BuildOptions
+
+		it.Before(func() {
+			opts = BuildOptions{
+				Publish:          true,
+				AdditionalTags:   []string{tag.Context().Tag("other").Name()},
+				Labels:           map[string]string{"org.opencontainers.image.vendor": "some-vendor"},
+				Annotations:      map[string]string{"org.opencontainers.image.source": "https://example.com/app"},
+				LayerCompression: ZstdCompression,
+			}
+		})
+
+		it("writes the labels, annotations and zstd layers of the published image at once", func() {
+			finished, err := subject.finishImage(context.TODO(), tag.Name(), opts)
+			h.AssertNil(t, err)
+			// one manifest for the image and one for its additional tag
+			h.AssertEq(t, manifestWrites.Load(), int32(2))
+
+			for _, ref := range []name.Reference{tag, tag.Context().Tag("other")} {
+				img, err := remote.Image(ref)
+				h.AssertNil(t, err)
+				digest, err := img.Digest()
+				h.AssertNil(t, err)
+				h.AssertEq(t, finished, tag.Context().Digest(digest.String()).String())
+
+				config, err := img.ConfigFile()
+				h.AssertNil(t, err)
+				h.AssertEq(t, config.Config.Labels["org.opencontainers.image.vendor"], "some-vendor")
+				manifest, err := img.Manifest()
+				h.AssertNil(t, err)
+				h.AssertEq(t, manifest.Annotations["org.opencontainers.image.source"], "https://example.com/app")
+				h.AssertEq(t, manifest.Layers[0].MediaType, types.OCILayerZStd)
+			}
+		})
+
+		it("writes the labels and annotations once when the registry rejects zstd layers", func() {
+			rejectZstd = true
+
+			finished, err := subject.finishImage(context.TODO(), tag.Name(), opts)
+			h.AssertNil(t, err)
+			h.AssertContains(t, out.String(), "Keeping the gzip layers of")
+			h.AssertEq(t, manifestWrites.Load(), int32(2))
+
+			img, err := remote.Image(tag)
+			h.AssertNil(t, err)
+			digest, err := img.Digest()
+			h.AssertNil(t, err)
+			h.AssertNotEq(t, digest.String(), originalDigest)
+			h.AssertEq(t, finished, tag.Context().Digest(digest.String()).String())
+			config, err := img.ConfigFile()
+			h.AssertNil(t, err)
+			h.AssertEq(t, config.Config.Labels["org.opencontainers.image.vendor"], "some-vendor")
+		})
+
+		it("doesn't write the image without labels, annotations or zstd layers", func() {
+			finished, err := subject.finishImage(context.TODO(), tag.Name(), BuildOptions{Publish: true})
+			h.AssertNil(t, err)
+			h.AssertEq(t, finished, "")
+			h.AssertEq(t, manifestWrites.Load(), int32(0))
+		})
+	})
+
+	when("#recordFinishedImage", func() {
+		var reportPath string
+
+		it.Before(func() {
+			reportPath = filepath.Join(t.TempDir(), "report.toml")
+			h.AssertNil(t, os.WriteFile(reportPath, []byte(`[image]
+tags = ["some/app:latest"]
+digest = "sha256:exported"
+manifest-size = 1234
+
+[pack.resources]
+memory = 1024
+`), 0600))
+		})
+
+		it("records the digest of the published image", func() {
+			h.AssertNil(t, recordFinishedImage(reportPath, "some/app@sha256:rewritten", true))
+
+			var recorded struct {
+				Image struct {
+					Tags         []string `toml:"tags"`
+					Digest       string   `toml:"digest"`
+					ManifestSize int64    `toml:"manifest-size"`
+				} `toml:"image"`
+			}
+			_, err := toml.DecodeFile(reportPath, &recorded)
+			h.AssertNil(t, err)
+			h.AssertEq(t, recorded.Image.Tags, []string{"some/app:latest"})
+			h.AssertEq(t, recorded.Image.Digest, "sha256:rewritten")
+			h.AssertEq(t, recorded.Image.ManifestSize, int64(0))
+
+			contents, err := os.ReadFile(reportPath)
+			h.AssertNil(t, err)
+			h.AssertContains(t, string(contents), "memory = 1024")
+		})
+
+		it("records the ID of the image of the daemon", func() {
+			h.AssertNil(t, recordFinishedImage(reportPath, "sha256:some-id", false))
+
+			contents, err := os.ReadFile(reportPath)
+			h.AssertNil(t, err)
+			h.AssertContains(t, string(contents), `image-id = "sha256:some-id"`)
+			h.AssertContains(t, string(contents), `digest = "sha256:exported"`)
+		})
+
+		it("does nothing without a report", func() {
+			h.AssertNil(t, recordFinishedImage(filepath.Join(t.TempDir(), "report.toml"), "sha256:some-id", false))
+		})
+	})
+}
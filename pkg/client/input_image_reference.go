@@ -21,6 +21,9 @@ type defaultInputImageReference struct {
 
 type layoutInputImageReference struct {
 	name string
+
+	// imageName is the name of the image when name is only the path of the layout, see NewLayoutInputImageReference
+	imageName string
 }
 
 func ParseInputImageReference(input string) InputImageReference {
@@ -35,6 +38,15 @@ func ParseInputImageReference(input string) InputImageReference {
 	}
 }
 
+// NewLayoutInputImageReference is the reference of the image imageName exported to the OCI layout in dir, e.g. for
+// `pack build <image-name> --output oci:<dir>`, rather than to the daemon or a registry
+func NewLayoutInputImageReference(dir, imageName string) InputImageReference {
+	return &layoutInputImageReference{
+		name:      dir,
+		imageName: imageName,
+	}
+}
+
 func (d *defaultInputImageReference) Name() string {
 	return d.name
 }
@@ -48,6 +60,9 @@ func (d *defaultInputImageReference) FullName() (string, error) {
 }
 
 func (l *layoutInputImageReference) Name() string {
+	if l.imageName != "" {
+		return l.imageName
+	}
 	return filepath.Base(l.name)
 }
 
@@ -61,7 +76,10 @@ func (l *layoutInputImageReference) FullName() (string, error) {
 		err           error
 	)
 
-	path := parsePath(l.name)
+	path := l.name
+	if l.imageName == "" {
+		path = parsePath(l.name)
+	}
 
 	if fullImagePath, err = filepath.EvalSymlinks(path); err != nil {
 		if !os.IsNotExist(err) {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 
 	"github.com/heroku/color"
@@ -47,6 +48,22 @@ func testInputImageReference(t *testing.T, when spec.G, it spec.S) {
 		})
 	})
 
+	when("#NewLayoutInputImageReference", func() {
+		it("is the image name in the OCI layout of the directory", func() {
+			h.SkipIf(t, runtime.GOOS == "windows", "colons aren't allowed in paths on windows")
+
+			reference := NewLayoutInputImageReference(filepath.Join("some", "output:dir"), "my-app:1.0")
+			h.AssertTrue(t, reference.Layout())
+			h.AssertEq(t, reference.Name(), "my-app:1.0")
+
+			fullPath, err := reference.FullName()
+			h.AssertNil(t, err)
+			currentWorkingDir, err := os.Getwd()
+			h.AssertNil(t, err)
+			h.AssertEq(t, fullPath, filepath.Join(currentWorkingDir, "some", "output:dir"))
+		})
+	})
+
 	when("#FullName", func() {
 		when("oci layout image reference is provided", func() {
 			when("not absolute path provided", func() {
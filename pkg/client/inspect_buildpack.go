@@ -19,12 +19,25 @@ type BuildpackInfo struct {
 	Order             dist.Order
 	BuildpackLayers   dist.ModuleLayers
 	Location          buildpack.LocatorType
+
+	// Registry is the entry of the buildpack in its registry, only set for buildpacks located in a registry
+	Registry *RegistryBuildpackInfo
+}
+
+// RegistryBuildpackInfo is the entry of a buildpack in a registry, describing it without pulling its image
+type RegistryBuildpackInfo struct {
+	Address     string
+	Description string
+	Licenses    []string
+	Stacks      []string
 }
 
 type InspectBuildpackOptions struct {
 	BuildpackName string
 	Daemon        bool
 	Registry      string
+	// RegistryLookup configures how the buildpacks of the registry are located.
+	RegistryLookup buildpack.RegistryLookup
 }
 
 type ImgWrapper struct {
@@ -42,10 +55,11 @@ func (c *Client) InspectBuildpack(opts InspectBuildpackOptions) (*BuildpackInfo,
 	}
 	var layersMd dist.ModuleLayers
 	var buildpackMd buildpack.Metadata
+	var registryInfo *RegistryBuildpackInfo
 
 	switch locatorType {
 	case buildpack.RegistryLocator:
-		buildpackMd, layersMd, err = metadataFromRegistry(c, opts.BuildpackName, opts.Registry)
+		buildpackMd, layersMd, registryInfo, err = metadataFromRegistry(c, opts.BuildpackName, opts.Registry, opts.RegistryLookup)
 	case buildpack.PackageLocator:
 		buildpackMd, layersMd, err = metadataFromImage(c, opts.BuildpackName, opts.Daemon)
 	case buildpack.URILocator:
@@ -63,24 +77,30 @@ func (c *Client) InspectBuildpack(opts InspectBuildpackOptions) (*BuildpackInfo,
 		Order:             extractOrder(buildpackMd),
 		Buildpacks:        extractBuildpacks(layersMd),
 		Location:          locatorType,
+		Registry:          registryInfo,
 	}, nil
 }
 
-func metadataFromRegistry(client *Client, name, registry string) (buildpackMd buildpack.Metadata, layersMd dist.ModuleLayers, err error) {
-	registryCache, err := getRegistry(client.logger, registry)
+func metadataFromRegistry(client *Client, name, registry string, lookup buildpack.RegistryLookup) (buildpackMd buildpack.Metadata, layersMd dist.ModuleLayers, registryInfo *RegistryBuildpackInfo, err error) {
+	registryCache, err := getRegistry(client.logger, registry, client.registryOptions.with(lookup))
 	if err != nil {
-		return buildpack.Metadata{}, dist.ModuleLayers{}, fmt.Errorf("invalid registry %s: %q", registry, err)
+		return buildpack.Metadata{}, dist.ModuleLayers{}, nil, fmt.Errorf("invalid registry %s: %q", registry, err)
 	}
 
 	registryBp, err := registryCache.LocateBuildpack(name)
 	if err != nil {
-		return buildpack.Metadata{}, dist.ModuleLayers{}, fmt.Errorf("unable to find %s in registry: %q", style.Symbol(name), err)
+		return buildpack.Metadata{}, dist.ModuleLayers{}, nil, fmt.Errorf("unable to find %s in registry: %q", style.Symbol(name), err)
 	}
 	buildpackMd, layersMd, err = metadataFromImage(client, registryBp.Address, false)
 	if err != nil {
-		return buildpack.Metadata{}, dist.ModuleLayers{}, fmt.Errorf("error pulling registry specified image: %s", err)
+		return buildpack.Metadata{}, dist.ModuleLayers{}, nil, fmt.Errorf("error pulling registry specified image: %s", err)
 	}
-	return buildpackMd, layersMd, nil
+	return buildpackMd, layersMd, &RegistryBuildpackInfo{
+		Address:     registryBp.Address,
+		Description: registryBp.Description,
+		Licenses:    registryBp.Licenses,
+		Stacks:      registryBp.Stacks,
+	}, nil
 }
 
 func metadataFromArchive(downloader BlobDownloader, path string) (buildpackMd buildpack.Metadata, layersMd dist.ModuleLayers, err error) {
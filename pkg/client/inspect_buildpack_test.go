@@ -360,6 +360,12 @@ func testInspectBuildpack(t *testing.T, when spec.G, it spec.S) {
 			var configPath string
 			it.Before(func() {
 				expectedInfo.Location = buildpack.RegistryLocator
+				expectedInfo.Registry = &client.RegistryBuildpackInfo{
+					Address:     "example.com/some/package@sha256:8c27fe111c11b722081701dfed3bd55e039b9ce92865473cf4cdfa918071c566",
+					Description: "Builds Java applications",
+					Licenses:    []string{"Apache-2.0"},
+					Stacks:      []string{"io.buildpacks.stacks.jammy"},
+				}
 
 				registryFixture = h.CreateRegistryFixture(t, tmpDir, filepath.Join("testdata", "registry"))
 				packHome := filepath.Join(tmpDir, "packHome")
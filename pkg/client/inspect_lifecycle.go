@@ -0,0 +1,180 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/buildpacks/lifecycle/api"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/build"
+	"github.com/buildpacks/pack/internal/builder"
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// InspectLifecycleOptions configures InspectLifecycle
+type InspectLifecycleOptions struct {
+	// Builder whose lifecycle is inspected
+	Builder string
+
+	// Daemon looks for the builder in the docker daemon before the registry
+	Daemon bool
+}
+
+// LifecycleCompatibility describes the lifecycle of a builder, and whether this version of pack and the buildpacks
+// of the builder are compatible with it
+type LifecycleCompatibility struct {
+	// Builder whose lifecycle was inspected
+	Builder string `json:"builder"`
+
+	// Version of the lifecycle of the builder
+	LifecycleVersion string `json:"lifecycleVersion"`
+
+	// Platform and Buildpack APIs supported by the lifecycle
+	PlatformAPIs  builder.APIVersions `json:"platformApis"`
+	BuildpackAPIs builder.APIVersions `json:"buildpackApis"`
+
+	// Version of pack, and the Platform APIs it supports
+	PackVersion      string   `json:"packVersion"`
+	PackPlatformAPIs []string `json:"packPlatformApis"`
+
+	// Platform API builds with the builder use, empty when there's none that both pack and the lifecycle support
+	PlatformAPI string `json:"platformApi"`
+
+	// Buildpacks of the builder, with the Buildpack API they implement
+	Buildpacks []BuildpackCompatibility `json:"buildpacks"`
+
+	// Incompatibilities found, and the outcome of the checks that passed
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+// BuildpackCompatibility describes whether the lifecycle supports the Buildpack API of a buildpack
+type BuildpackCompatibility struct {
+	ID      string           `json:"id"`
+	Version string           `json:"version"`
+	API     string           `json:"api"`
+	Status  DiagnosticStatus `json:"status"`
+}
+
+// Compatible is false when builds with the builder fail, as pack or one of its buildpacks isn't supported by the
+// lifecycle
+func (l LifecycleCompatibility) Compatible() bool {
+	for _, diagnostic := range l.Diagnostics {
+		if diagnostic.Status == DiagnosticFailed {
+			return false
+		}
+	}
+	return true
+}
+
+// InspectLifecycle compares the Platform and Buildpack APIs supported by the lifecycle of a builder with those of
+// this version of pack and of the buildpacks of the builder, so that incompatibilities are found before building.
+func (c *Client) InspectLifecycle(ctx context.Context, opts InspectLifecycleOptions) (*LifecycleCompatibility, error) {
+	var (
+		info *BuilderInfo
+		err  error
+	)
+	if opts.Daemon {
+		info, err = c.InspectBuilder(opts.Builder, true)
+	}
+	if err == nil && info == nil {
+		info, err = c.InspectBuilder(opts.Builder, false)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "inspecting builder %s", style.Symbol(opts.Builder))
+	}
+	if info == nil {
+		return nil, errors.Errorf("builder %s not found", style.Symbol(opts.Builder))
+	}
+
+	compatibility := &LifecycleCompatibility{
+		Builder:          opts.Builder,
+		PlatformAPIs:     info.Lifecycle.APIs.Platform,
+		BuildpackAPIs:    info.Lifecycle.APIs.Buildpack,
+		PackVersion:      c.version,
+		PackPlatformAPIs: build.SupportedPlatformAPIVersions.AsStrings(),
+	}
+	if info.Lifecycle.Info.Version != nil {
+		compatibility.LifecycleVersion = info.Lifecycle.Info.Version.String()
+	}
+
+	compatibility.Diagnostics = append(compatibility.Diagnostics, checkLifecyclePlatformAPI(compatibility))
+
+	// deprecated APIs are still supported, though lifecycles don't always list them as such
+	lifecycleBuildpackAPIs := api.APIs{
+		Supported:  append(append(api.List{}, info.Lifecycle.APIs.Buildpack.Deprecated...), info.Lifecycle.APIs.Buildpack.Supported...),
+		Deprecated: api.List(info.Lifecycle.APIs.Buildpack.Deprecated),
+	}
+	var unsupported, deprecated []string
+	for id, versions := range info.BuildpackLayers {
+		for version, layer := range versions {
+			buildpack := BuildpackCompatibility{ID: id, Version: version, Status: DiagnosticOK}
+			if layer.API != nil {
+				buildpack.API = layer.API.String()
+				switch {
+				case !lifecycleBuildpackAPIs.IsSupported(layer.API):
+					buildpack.Status = DiagnosticFailed
+					unsupported = append(unsupported, fmt.Sprintf("%s@%s (%s)", id, version, buildpack.API))
+				case lifecycleBuildpackAPIs.IsDeprecated(layer.API):
+					buildpack.Status = DiagnosticWarning
+					deprecated = append(deprecated, fmt.Sprintf("%s@%s (%s)", id, version, buildpack.API))
+				}
+			}
+			compatibility.Buildpacks = append(compatibility.Buildpacks, buildpack)
+		}
+	}
+	sort.Slice(compatibility.Buildpacks, func(i, j int) bool {
+		if compatibility.Buildpacks[i].ID != compatibility.Buildpacks[j].ID {
+			return compatibility.Buildpacks[i].ID < compatibility.Buildpacks[j].ID
+		}
+		return compatibility.Buildpacks[i].Version < compatibility.Buildpacks[j].Version
+	})
+	sort.Strings(unsupported)
+	sort.Strings(deprecated)
+
+	buildpackDiagnostic := Diagnostic{Name: "Buildpack API", Status: DiagnosticOK, Message: fmt.Sprintf("the lifecycle supports the Buildpack APIs of all %d buildpacks", len(compatibility.Buildpacks))}
+	switch {
+	case len(unsupported) > 0:
+		buildpackDiagnostic.Status = DiagnosticFailed
+		buildpackDiagnostic.Message = fmt.Sprintf("the lifecycle doesn't support the Buildpack API of %s", strings.Join(unsupported, ", "))
+		buildpackDiagnostic.Fix = "Recreate the builder with a lifecycle supporting these Buildpack APIs, or with newer versions of the buildpacks"
+	case len(deprecated) > 0:
+		buildpackDiagnostic.Status = DiagnosticWarning
+		buildpackDiagnostic.Message = fmt.Sprintf("the lifecycle deprecates the Buildpack API of %s", strings.Join(deprecated, ", "))
+		buildpackDiagnostic.Fix = "Upgrade the buildpacks before a lifecycle drops support for their Buildpack API"
+	}
+	compatibility.Diagnostics = append(compatibility.Diagnostics, buildpackDiagnostic)
+
+	return compatibility, nil
+}
+
+func checkLifecyclePlatformAPI(compatibility *LifecycleCompatibility) Diagnostic {
+	diagnostic := Diagnostic{Name: "Platform API"}
+
+	lifecycleAPIs := append(append(builder.APISet{}, compatibility.PlatformAPIs.Deprecated...), compatibility.PlatformAPIs.Supported...)
+	version, err := build.FindLatestSupported(lifecycleAPIs, nil)
+	if err != nil {
+		diagnostic.Status = DiagnosticFailed
+		diagnostic.Message = fmt.Sprintf("the lifecycle supports Platform APIs %s, pack supports %s",
+			strings.Join(lifecycleAPIs.AsStrings(), ", "), strings.Join(compatibility.PackPlatformAPIs, ", "))
+		diagnostic.Fix = "Use a builder with a lifecycle supported by this version of pack"
+		if earliest := lifecycleAPIs.Earliest(); earliest != nil && earliest.Compare(build.SupportedPlatformAPIVersions.Latest()) > 0 {
+			diagnostic.Fix = "Upgrade pack, the lifecycle of the builder is newer than this version of pack supports"
+		}
+		return diagnostic
+	}
+
+	compatibility.PlatformAPI = version.String()
+	diagnostic.Status = DiagnosticOK
+	diagnostic.Message = fmt.Sprintf("builds will use Platform API %s", version.String())
+	for _, deprecated := range compatibility.PlatformAPIs.Deprecated {
+		if deprecated.Equal(version) {
+			diagnostic.Status = DiagnosticWarning
+			diagnostic.Message += ", which the lifecycle deprecates"
+			diagnostic.Fix = "Upgrade pack to use a newer Platform API"
+		}
+	}
+	return diagnostic
+}
@@ -0,0 +1,130 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/buildpacks/imgutil/fakes"
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/pkg/errors"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/image"
+	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/testmocks"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestInspectLifecycle(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "InspectLifecycle", testInspectLifecycle, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testInspectLifecycle(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject          *Client
+		mockImageFetcher *testmocks.MockImageFetcher
+		mockController   *gomock.Controller
+		builderImage     *fakes.Image
+		out              bytes.Buffer
+	)
+
+	setLifecycle := func(platformAPIs, buildpackAPIs string) {
+		h.AssertNil(t, builderImage.SetLabel("io.buildpacks.builder.metadata",
+			`{"lifecycle": {"version": "0.20.1", "apis": {"platform": `+platformAPIs+`, "buildpack": `+buildpackAPIs+`}}}`))
+	}
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockImageFetcher = testmocks.NewMockImageFetcher(mockController)
+
+		subject = &Client{
+			logger:       logging.NewLogWithWriters(&out, &out),
+			imageFetcher: mockImageFetcher,
+			version:      "0.36.0",
+		}
+
+		builderImage = fakes.NewImage("some/builder", "", nil)
+		h.AssertNil(t, builderImage.SetLabel("io.buildpacks.stack.id", "test.stack.id"))
+		h.AssertNil(t, builderImage.SetLabel("io.buildpacks.buildpack.layers", `{
+  "some/bp": {"1.0.0": {"api": "0.10", "layerDiffID": "sha256:one"}},
+  "some/old-bp": {"0.1.0": {"api": "0.2", "layerDiffID": "sha256:two"}}
+}`))
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("the builder is in the daemon", func() {
+		it.Before(func() {
+			mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/builder", image.FetchOptions{Daemon: true, PullPolicy: image.PullNever}).Return(builderImage, nil)
+		})
+
+		it("negotiates the Platform API and checks the Buildpack API of every buildpack", func() {
+			setLifecycle(`{"deprecated": [], "supported": ["0.12", "0.13", "0.14"]}`, `{"deprecated": ["0.2"], "supported": ["0.7", "0.10"]}`)
+
+			compatibility, err := subject.InspectLifecycle(context.TODO(), InspectLifecycleOptions{Builder: "some/builder", Daemon: true})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, compatibility.LifecycleVersion, "0.20.1")
+			h.AssertEq(t, compatibility.PackVersion, "0.36.0")
+			h.AssertEq(t, compatibility.PlatformAPI, "0.13")
+			h.AssertEq(t, compatibility.Buildpacks, []BuildpackCompatibility{
+				{ID: "some/bp", Version: "1.0.0", API: "0.10", Status: DiagnosticOK},
+				{ID: "some/old-bp", Version: "0.1.0", API: "0.2", Status: DiagnosticWarning},
+			})
+			h.AssertEq(t, compatibility.Diagnostics[0].Status, DiagnosticOK)
+			h.AssertEq(t, compatibility.Diagnostics[0].Message, "builds will use Platform API 0.13")
+			h.AssertEq(t, compatibility.Diagnostics[1].Status, DiagnosticWarning)
+			h.AssertContains(t, compatibility.Diagnostics[1].Message, "some/old-bp@0.1.0 (0.2)")
+			h.AssertEq(t, compatibility.Compatible(), true)
+		})
+
+		it("fails when the lifecycle is newer than pack supports", func() {
+			setLifecycle(`{"deprecated": [], "supported": ["0.20", "0.21"]}`, `{"deprecated": [], "supported": ["0.2", "0.10"]}`)
+
+			compatibility, err := subject.InspectLifecycle(context.TODO(), InspectLifecycleOptions{Builder: "some/builder", Daemon: true})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, compatibility.PlatformAPI, "")
+			h.AssertEq(t, compatibility.Diagnostics[0].Status, DiagnosticFailed)
+			h.AssertContains(t, compatibility.Diagnostics[0].Fix, "Upgrade pack")
+			h.AssertEq(t, compatibility.Compatible(), false)
+		})
+
+		it("fails when a buildpack implements a Buildpack API the lifecycle doesn't support", func() {
+			setLifecycle(`{"deprecated": [], "supported": ["0.13"]}`, `{"deprecated": [], "supported": ["0.7", "0.10"]}`)
+
+			compatibility, err := subject.InspectLifecycle(context.TODO(), InspectLifecycleOptions{Builder: "some/builder", Daemon: true})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, compatibility.Diagnostics[1].Status, DiagnosticFailed)
+			h.AssertEq(t, compatibility.Diagnostics[1].Message, "the lifecycle doesn't support the Buildpack API of some/old-bp@0.1.0 (0.2)")
+			h.AssertEq(t, compatibility.Compatible(), false)
+		})
+	})
+
+	when("the builder isn't in the daemon", func() {
+		it("inspects the builder in the registry", func() {
+			setLifecycle(`{"deprecated": [], "supported": ["0.13"]}`, `{"deprecated": [], "supported": ["0.2", "0.10"]}`)
+			mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/builder", image.FetchOptions{Daemon: true, PullPolicy: image.PullNever}).Return(nil, errors.Wrap(image.ErrNotFound, "some-error"))
+			mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/builder", image.FetchOptions{Daemon: false, PullPolicy: image.PullNever}).Return(builderImage, nil)
+
+			compatibility, err := subject.InspectLifecycle(context.TODO(), InspectLifecycleOptions{Builder: "some/builder", Daemon: true})
+			h.AssertNil(t, err)
+			h.AssertEq(t, compatibility.Compatible(), true)
+		})
+
+		it("errors when the registry doesn't have it either", func() {
+			mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/builder", image.FetchOptions{Daemon: false, PullPolicy: image.PullNever}).Return(nil, errors.Wrap(image.ErrNotFound, "some-error"))
+
+			_, err := subject.InspectLifecycle(context.TODO(), InspectLifecycleOptions{Builder: "some/builder"})
+			h.AssertError(t, err, "builder 'some/builder' not found")
+		})
+	})
+}
@@ -0,0 +1,193 @@
+package client
+
+import (
+	"context"
+
+	"github.com/google/go-containerregistry/pkg/compression"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// LayerCompression is the algorithm compressing the layers of published images
+type LayerCompression string
+
+const (
+	// GzipCompression compresses layers with gzip, which every registry and container runtime supports
+	GzipCompression LayerCompression = "gzip"
+
+	// ZstdCompression compresses layers with zstd, which decompresses faster than gzip. The images are published with
+	// OCI media types, as the Docker ones have no zstd layers.
+	ZstdCompression LayerCompression = "zstd"
+)
+
+// ParseLayerCompression parses the compression of the layers of published images, gzip when empty
+func ParseLayerCompression(value string) (LayerCompression, error) {
+	switch LayerCompression(value) {
+	case "", GzipCompression:
+		return GzipCompression, nil
+	case ZstdCompression:
+		return ZstdCompression, nil
+	}
+	return "", errors.Errorf("invalid layer compression %s, must be %s or %s", style.Symbol(value), style.Symbol(string(GzipCompression)), style.Symbol(string(ZstdCompression)))
+}
+
+// validateLayerCompression fails for zstd layers when the image isn't published, as they're only compressed with zstd
+// in registries
+func validateLayerCompression(opts BuildOptions) error {
+	if opts.LayerCompression == ZstdCompression && !opts.Publish {
+		return errors.New("layers can only be compressed with zstd when publishing the image to a registry")
+	}
+	return nil
+}
+
+// compressPublishedImage publishes again the image published as imageName, and its additional tags, with its layers
+// compressed with zstd when layerCompression asks for it. It returns the reference by digest of the image with zstd
+// layers, empty when the image is unchanged.
+func (c *Client) compressPublishedImage(ctx context.Context, imageName string, additionalTags []string, layerCompression LayerCompression) (string, error) {
+	return c.rewritePublishedImage(ctx, imageName, additionalTags, nil, layerCompression)
+}
+
+// rewritePublishedImage publishes again the image published as imageName, and its additional tags, changed by edit when
+// it isn't nil and with its layers compressed with zstd when layerCompression asks for it, in a single write of each
+// manifest. The image keeps its gzip layers when the registry rejects zstd ones, or when some of its layers are
+// non-distributable and can't be pushed again. It returns the reference by digest of the rewritten image, empty when
+// the image is unchanged.
+func (c *Client) rewritePublishedImage(ctx context.Context, imageName string, additionalTags []string, edit func(v1.Image) (v1.Image, error), layerCompression LayerCompression) (string, error) {
+	if edit == nil && layerCompression != ZstdCompression {
+		return "", nil
+	}
+
+	ref, err := name.ParseReference(imageName, name.WeakValidation)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid image name %s", style.Symbol(imageName))
+	}
+	remoteOpts := []remote.Option{remote.WithContext(ctx), remote.WithAuthFromKeychain(c.keychain)}
+	img, err := remote.Image(ref, remoteOpts...)
+	if err != nil {
+		return "", errors.Wrapf(err, "fetching %s", style.Symbol(imageName))
+	}
+	if edit != nil {
+		if img, err = edit(img); err != nil {
+			return "", err
+		}
+	}
+
+	written := false
+	if layerCompression == ZstdCompression {
+		if written, img, err = c.writeZstdImage(ctx, ref, img, remoteOpts); err != nil {
+			return "", err
+		}
+	}
+	if !written {
+		if edit == nil {
+			return "", nil
+		}
+		if err := remote.Write(ref, img, remoteOpts...); err != nil {
+			return "", errors.Wrapf(err, "publishing %s", style.Symbol(imageName))
+		}
+	}
+	for _, tag := range additionalTags {
+		tagRef, err := name.ParseReference(tag, name.WeakValidation)
+		if err != nil {
+			return "", errors.Wrapf(err, "invalid tag %s", style.Symbol(tag))
+		}
+		// tags may be in other repositories, which lack the rewritten layers
+		if err := remote.Write(tagRef, img, remoteOpts...); err != nil {
+			return "", errors.Wrapf(err, "publishing %s", style.Symbol(tag))
+		}
+	}
+
+	digest, err := img.Digest()
+	if err != nil {
+		return "", err
+	}
+	return ref.Context().Digest(digest.String()).String(), nil
+}
+
+// writeZstdImage publishes img as ref with its layers compressed with zstd, returning whether it was written along with
+// the written image. It isn't written, without failing, when img has non-distributable layers or the registry rejects
+// zstd layers.
+func (c *Client) writeZstdImage(ctx context.Context, ref name.Reference, img v1.Image, remoteOpts []remote.Option) (bool, v1.Image, error) {
+	zstdImg, err := zstdImage(img)
+	if err != nil {
+		return false, img, errors.Wrapf(err, "compressing the layers of %s with zstd", style.Symbol(ref.String()))
+	}
+	if zstdImg == nil {
+		c.logger.Warnf("Keeping the gzip layers of %s, as it has non-distributable layers", style.Symbol(ref.String()))
+		return false, img, nil
+	}
+
+	c.logger.Debugf("Compressing the layers of %s with zstd", style.Symbol(ref.String()))
+	layers, err := zstdImg.Layers()
+	if err != nil {
+		return false, img, err
+	}
+	if _, err = c.writeLayers(ref, layers, remoteOpts); err == nil {
+		err = remote.Write(ref, zstdImg, remoteOpts...)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return false, img, ctx.Err()
+		}
+		c.logger.Warnf("Keeping the gzip layers of %s, as the registry rejected zstd ones: %s", style.Symbol(ref.String()), err)
+		return false, img, nil
+	}
+	return true, zstdImg, nil
+}
+
+// zstdImage is img with its layers compressed with zstd, nil when it has non-distributable layers. Its config, and so
+// the diff IDs of its layers, are kept.
+func zstdImage(img v1.Image) (v1.Image, error) {
+	manifest, err := img.Manifest()
+	if err != nil {
+		return nil, err
+	}
+	configFile, err := img.ConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	layers, err := img.Layers()
+	if err != nil {
+		return nil, err
+	}
+
+	additions := make([]mutate.Addendum, 0, len(layers))
+	for _, layer := range layers {
+		mediaType, err := layer.MediaType()
+		if err != nil {
+			return nil, err
+		}
+		if !mediaType.IsDistributable() {
+			return nil, nil
+		}
+		if mediaType != types.OCILayerZStd {
+			// layers reused from previous images may have zstd content under a gzip media type, decompressing them
+			// detects their actual compression
+			if layer, err = tarball.LayerFromOpener(layer.Uncompressed, tarball.WithCompression(compression.ZStd), tarball.WithMediaType(types.OCILayerZStd)); err != nil {
+				return nil, err
+			}
+		}
+		additions = append(additions, mutate.Addendum{Layer: layer, MediaType: types.OCILayerZStd})
+	}
+
+	zstdImg, err := mutate.Append(mutate.MediaType(empty.Image, types.OCIManifestSchema1), additions...)
+	if err != nil {
+		return nil, err
+	}
+	if zstdImg, err = mutate.ConfigFile(zstdImg, configFile); err != nil {
+		return nil, err
+	}
+	zstdImg = mutate.ConfigMediaType(zstdImg, types.OCIConfigJSON)
+	if len(manifest.Annotations) > 0 {
+		zstdImg = mutate.Annotations(zstdImg, manifest.Annotations).(v1.Image)
+	}
+	return zstdImg, nil
+}
@@ -0,0 +1,153 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestLayerCompression(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "LayerCompression", testLayerCompression, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testLayerCompression(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject        *Client
+		out            bytes.Buffer
+		server         *httptest.Server
+		rejectZstd     bool
+		tag            name.Tag
+		originalDigest string
+	)
+
+	it.Before(func() {
+		rejectZstd = false
+		reg := registry.New(registry.Logger(log.New(io.Discard, "", 0)))
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if rejectZstd && r.Method == http.MethodPut && strings.Contains(r.URL.Path, "/manifests/") {
+				body, _ := io.ReadAll(r.Body)
+				if strings.Contains(string(body), string(types.OCILayerZStd)) {
+					http.Error(w, "unsupported media type", http.StatusBadRequest)
+					return
+				}
+				r.Body = io.NopCloser(bytes.NewReader(body))
+			}
+			reg.ServeHTTP(w, r)
+		}))
+		subject = &Client{keychain: authn.DefaultKeychain, logger: logging.NewLogWithWriters(&out, &out)}
+
+		img, err := random.Image(1024, 2)
+		h.AssertNil(t, err)
+		tag, err = name.NewTag(strings.TrimPrefix(server.URL, "http://") + "/some/app:latest")
+		h.AssertNil(t, err)
+		h.AssertNil(t, remote.Write(tag, img))
+		digest, err := img.Digest()
+		h.AssertNil(t, err)
+		originalDigest = digest.String()
+	})
+
+	it.After(func() {
+		server.Close()
+	})
+
+	when("#ParseLayerCompression", func() {
+		it("defaults to gzip", func() {
+			compression, err := ParseLayerCompression("")
+			h.AssertNil(t, err)
+			h.AssertEq(t, compression, GzipCompression)
+		})
+
+		it("parses zstd", func() {
+			compression, err := ParseLayerCompression("zstd")
+			h.AssertNil(t, err)
+			h.AssertEq(t, compression, ZstdCompression)
+		})
+
+		it("errors for unknown compressions", func() {
+			_, err := ParseLayerCompression("lz4")
+			h.AssertError(t, err, "invalid layer compression 'lz4'")
+		})
+	})
+
+	when("#compressPublishedImage", func() {
+		it("publishes the image with zstd layers and the same diff IDs", func() {
+			before, err := remote.Image(tag)
+			h.AssertNil(t, err)
+			beforeConfig, err := before.ConfigFile()
+			h.AssertNil(t, err)
+
+			compressed, err := subject.compressPublishedImage(context.TODO(), tag.Name(), []string{tag.Context().Tag("other").Name()}, ZstdCompression)
+			h.AssertNil(t, err)
+
+			for _, ref := range []name.Reference{tag, tag.Context().Tag("other")} {
+				img, err := remote.Image(ref)
+				h.AssertNil(t, err)
+				digest, err := img.Digest()
+				h.AssertNil(t, err)
+				h.AssertEq(t, compressed, tag.Context().Digest(digest.String()).String())
+
+				mediaType, err := img.MediaType()
+				h.AssertNil(t, err)
+				h.AssertEq(t, mediaType, types.OCIManifestSchema1)
+				layers, err := img.Layers()
+				h.AssertNil(t, err)
+				h.AssertEq(t, len(layers), 2)
+				for _, layer := range layers {
+					layerType, err := layer.MediaType()
+					h.AssertNil(t, err)
+					h.AssertEq(t, layerType, types.OCILayerZStd)
+				}
+				config, err := img.ConfigFile()
+				h.AssertNil(t, err)
+				h.AssertEq(t, config.RootFS.DiffIDs, beforeConfig.RootFS.DiffIDs)
+			}
+		})
+
+		it("keeps gzip layers when the registry rejects zstd ones", func() {
+			rejectZstd = true
+
+			compressed, err := subject.compressPublishedImage(context.TODO(), tag.Name(), nil, ZstdCompression)
+			h.AssertNil(t, err)
+			h.AssertEq(t, compressed, "")
+			h.AssertContains(t, out.String(), "Keeping the gzip layers of")
+
+			img, err := remote.Image(tag)
+			h.AssertNil(t, err)
+			digest, err := img.Digest()
+			h.AssertNil(t, err)
+			h.AssertEq(t, digest.String(), originalDigest)
+		})
+
+		it("doesn't change the image with gzip compression", func() {
+			compressed, err := subject.compressPublishedImage(context.TODO(), tag.Name(), nil, GzipCompression)
+			h.AssertNil(t, err)
+			h.AssertEq(t, compressed, "")
+
+			img, err := remote.Image(tag)
+			h.AssertNil(t, err)
+			digest, err := img.Digest()
+			h.AssertNil(t, err)
+			h.AssertEq(t, digest.String(), originalDigest)
+		})
+	})
+}
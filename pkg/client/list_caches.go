@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/cache"
+)
+
+// ListCachesOptions configures ListCaches
+type ListCachesOptions struct {
+	// Apps only lists the cache volumes of these app images, when set
+	Apps []string
+}
+
+// CacheVolume is a volume of the build or launch cache of an app image
+type CacheVolume struct {
+	// Name of the volume
+	Name string
+
+	// App is the app image the volume is named after, as in the names of its cache volumes
+	App string
+
+	// Type of the cache, such as build or launch
+	Type string
+
+	// Created is when the volume was created, zero when unknown
+	Created time.Time
+
+	// Size in bytes, 0 when unknown
+	Size int64
+
+	// InUse is whether containers, such as the ones of a running build, use the volume
+	InUse bool
+}
+
+// ListCaches returns the cache volumes of app images, sorted by app and type
+func (c *Client) ListCaches(ctx context.Context, opts ListCachesOptions) ([]CacheVolume, error) {
+	apps, err := volumeApps(opts.Apps)
+	if err != nil {
+		return nil, err
+	}
+
+	usage, err := c.docker.DiskUsage(ctx, types.DiskUsageOptions{Types: []types.DiskUsageObject{types.VolumeObject}})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing docker volumes")
+	}
+
+	var volumes []CacheVolume
+	for _, volume := range usage.Volumes {
+		if volume == nil {
+			continue
+		}
+		app, cacheType, ok := cache.ParseVolumeName(volume.Name)
+		if !ok || (len(apps) > 0 && !apps[app]) {
+			continue
+		}
+		cacheVolume := CacheVolume{Name: volume.Name, App: app, Type: cacheType}
+		if volume.UsageData != nil {
+			cacheVolume.InUse = volume.UsageData.RefCount > 0
+			if volume.UsageData.Size > 0 {
+				cacheVolume.Size = volume.UsageData.Size
+			}
+		}
+		if created, err := time.Parse(time.RFC3339, volume.CreatedAt); err == nil {
+			cacheVolume.Created = created
+		}
+		volumes = append(volumes, cacheVolume)
+	}
+
+	sort.SliceStable(volumes, func(i, j int) bool {
+		if volumes[i].App != volumes[j].App {
+			return volumes[i].App < volumes[j].App
+		}
+		return volumes[i].Type < volumes[j].Type
+	})
+	return volumes, nil
+}
+
+// volumeApps returns how the app images are named in the names of their cache volumes
+func volumeApps(images []string) (map[string]bool, error) {
+	apps := map[string]bool{}
+	for _, image := range images {
+		ref, err := name.ParseReference(image, name.WeakValidation)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid app image %s", style.Symbol(image))
+		}
+		apps[cache.VolumeApp(ref)] = true
+	}
+	return apps, nil
+}
@@ -0,0 +1,90 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/testmocks"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestListCaches(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "ListCaches", testListCaches, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testListCaches(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject          *Client
+		mockDockerClient *testmocks.MockCommonAPIClient
+		mockController   *gomock.Controller
+		out              bytes.Buffer
+		dayAgo           = time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockDockerClient = testmocks.NewMockCommonAPIClient(mockController)
+
+		var err error
+		subject, err = NewClient(WithLogger(logging.NewLogWithWriters(&out, &out)), WithDockerClient(mockDockerClient))
+		h.AssertNil(t, err)
+
+		mockDockerClient.EXPECT().DiskUsage(gomock.Any(), gomock.Any()).Return(types.DiskUsage{
+			Volumes: []*volume.Volume{
+				{Name: "pack-cache-some_app_latest-0123456789ab.launch", UsageData: &volume.UsageData{Size: -1, RefCount: 1}},
+				{Name: "pack-cache-some_app_latest-0123456789ab.build", CreatedAt: dayAgo.Format(time.RFC3339), UsageData: &volume.UsageData{Size: 500}},
+				{Name: "pack-cache-other_app_v1-456789abcdef.build", UsageData: &volume.UsageData{Size: 100}},
+				{Name: "pack-cache-custom-volume", UsageData: &volume.UsageData{Size: 100}},
+				{Name: "some-volume", UsageData: &volume.UsageData{Size: 100}},
+			},
+		}, nil).AnyTimes()
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#ListCaches", func() {
+		it("lists the cache volumes of app images by app and type", func() {
+			volumes, err := subject.ListCaches(context.TODO(), ListCachesOptions{})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, len(volumes), 3)
+			h.AssertEq(t, volumes[0].Name, "pack-cache-other_app_v1-456789abcdef.build")
+			h.AssertEq(t, volumes[1], CacheVolume{
+				Name:    "pack-cache-some_app_latest-0123456789ab.build",
+				App:     "some_app_latest",
+				Type:    "build",
+				Created: volumes[1].Created,
+				Size:    500,
+			})
+			h.AssertTrue(t, volumes[1].Created.Equal(dayAgo))
+			h.AssertEq(t, volumes[2], CacheVolume{
+				Name:  "pack-cache-some_app_latest-0123456789ab.launch",
+				App:   "some_app_latest",
+				Type:  "launch",
+				InUse: true,
+			})
+		})
+
+		it("only lists the cache volumes of the given apps", func() {
+			volumes, err := subject.ListCaches(context.TODO(), ListCachesOptions{Apps: []string{"other/app:v1"}})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, len(volumes), 1)
+			h.AssertEq(t, volumes[0].Name, "pack-cache-other_app_v1-456789abcdef.build")
+		})
+	})
+}
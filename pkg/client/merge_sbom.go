@@ -0,0 +1,58 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/sbom"
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// MergeSBOMOptions configures MergeSBOM
+type MergeSBOMOptions struct {
+	// Daemon is true to read the image from the docker daemon, false to read it from a registry
+	Daemon bool
+
+	// Format of the merged SBOM, either cyclonedx or spdx
+	Format string
+}
+
+// MergeSBOM merges the CycloneDX and SPDX SBOMs that buildpacks added to the launch layers of an image into a single
+// JSON document of the requested format.
+func (c *Client) MergeSBOM(name string, options MergeSBOMOptions) ([]byte, error) {
+	format, err := sbom.ParseFormat(options.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "pack.sbom.merge.")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := c.extractSBOM(name, options.Daemon, tmpDir); err != nil {
+		return nil, err
+	}
+
+	launchDir := filepath.Join(tmpDir, "layers", "sbom", "launch")
+	if _, err := os.Stat(launchDir); err != nil {
+		return nil, errors.Errorf("could not find the SBoM of launch layers on %s", style.Symbol(name))
+	}
+
+	fragments, err := sbom.ReadFragments(launchDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(fragments) == 0 {
+		return nil, errors.Errorf("could not find CycloneDX or SPDX SBoMs on %s", style.Symbol(name))
+	}
+	for _, fragment := range fragments {
+		c.logger.Debugf("Merging %s SBoM %s", fragment.Format, style.Symbol(fragment.Path))
+	}
+
+	return sbom.Merge(fragments, format, sbom.Metadata{Name: name, ToolVersion: c.version, Time: time.Now()})
+}
@@ -0,0 +1,109 @@
+package client
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/archive"
+	"github.com/buildpacks/pack/pkg/image"
+	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/testmocks"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestMergeSBOM(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "MergeSBOM", testMergeSBOM, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testMergeSBOM(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject          *Client
+		mockImageFetcher *testmocks.MockImageFetcher
+		mockDockerClient *testmocks.MockCommonAPIClient
+		mockController   *gomock.Controller
+		out              bytes.Buffer
+		tmpFile          string
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockImageFetcher = testmocks.NewMockImageFetcher(mockController)
+		mockDockerClient = testmocks.NewMockCommonAPIClient(mockController)
+
+		var err error
+		subject, err = NewClient(WithLogger(logging.NewLogWithWriters(&out, &out)), WithFetcher(mockImageFetcher), WithDockerClient(mockDockerClient))
+		h.AssertNil(t, err)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+		os.RemoveAll(tmpFile)
+	})
+
+	expectImageWithSBOM := func(path, contents string) {
+		f, err := os.CreateTemp("", "pack.merge.sbom.test.")
+		h.AssertNil(t, err)
+		h.AssertNil(t, f.Close())
+		tmpFile = f.Name()
+		h.AssertNil(t, archive.CreateSingleFileTar(tmpFile, path, contents))
+
+		data, err := os.ReadFile(tmpFile)
+		h.AssertNil(t, err)
+		sum := sha256.Sum256(data)
+		shasum := hex.EncodeToString(sum[:])
+
+		mockImage := testmocks.NewImage("some/image", "", nil)
+		mockImage.AddLayerWithDiffID(tmpFile, fmt.Sprintf("sha256:%s", shasum))
+		h.AssertNil(t, mockImage.SetLabel("io.buildpacks.lifecycle.metadata", fmt.Sprintf(`{"sbom": {"sha": "sha256:%s"}}`, shasum)))
+
+		mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/image", image.FetchOptions{Daemon: true, PullPolicy: image.PullNever}).Return(mockImage, nil)
+	}
+
+	when("the image has SBoMs for its launch layers", func() {
+		it("merges them in the requested format", func() {
+			expectImageWithSBOM("layers/sbom/launch/example_bp/sbom.cdx.json", `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.4",
+  "components": [{"type": "library", "name": "some-lib", "version": "1.0.0", "purl": "pkg:generic/some-lib@1.0.0"}]
+}`)
+
+			doc, err := subject.MergeSBOM("some/image", MergeSBOMOptions{Daemon: true, Format: "spdx"})
+			h.AssertNil(t, err)
+
+			var merged map[string]interface{}
+			h.AssertNil(t, json.Unmarshal(doc, &merged))
+			h.AssertEq(t, merged["spdxVersion"], "SPDX-2.3")
+			packages := merged["packages"].([]interface{})
+			h.AssertEq(t, len(packages), 1)
+			h.AssertEq(t, packages[0].(map[string]interface{})["name"], "some-lib")
+		})
+	})
+
+	when("the image has no CycloneDX or SPDX SBoMs", func() {
+		it("returns an error", func() {
+			expectImageWithSBOM("layers/sbom/launch/example_bp/sbom.syft.json", `{}`)
+
+			_, err := subject.MergeSBOM("some/image", MergeSBOMOptions{Daemon: true, Format: "cyclonedx"})
+			h.AssertError(t, err, "could not find CycloneDX or SPDX SBoMs on 'some/image'")
+		})
+	})
+
+	when("the format is unknown", func() {
+		it("returns an error", func() {
+			_, err := subject.MergeSBOM("some/image", MergeSBOMOptions{Daemon: true, Format: "syft"})
+			h.AssertError(t, err, "unknown SBOM format 'syft'")
+		})
+	})
+}
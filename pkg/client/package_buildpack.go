@@ -54,6 +54,9 @@ type PackageBuildpackOptions struct {
 	// add buildpacks to a package.
 	Registry string
 
+	// How registry buildpacks are located.
+	RegistryLookup buildpack.RegistryLookup
+
 	// Flatten layers
 	Flatten bool
 
@@ -65,6 +68,9 @@ type PackageBuildpackOptions struct {
 
 	// Target platforms to build packages for
 	Targets []dist.Target
+
+	// Compression of the layers of the published package, gzip by default.
+	LayerCompression LayerCompression
 }
 
 // PackageBuildpack packages buildpack(s) into either an image or file.
@@ -147,7 +153,8 @@ func (c *Client) packageBuildpackTarget(ctx context.Context, opts PackageBuildpa
 
 	platform := target.ValuesAsPlatform()
 
-	for _, dep := range opts.Config.Dependencies {
+	downloads := make([]*moduleDownload, len(opts.Config.Dependencies))
+	for i, dep := range opts.Config.Dependencies {
 		if multiArch {
 			locatorType, err := buildpack.GetLocatorType(dep.URI, opts.RelativeBaseDir, []dist.ModuleInfo{})
 			if err != nil {
@@ -159,20 +166,27 @@ func (c *Client) packageBuildpackTarget(ctx context.Context, opts PackageBuildpa
 			}
 		}
 
-		c.logger.Debugf("Downloading buildpack dependency for platform %s", platform)
-		mainBP, deps, err := c.buildpackDownloader.Download(ctx, dep.URI, buildpack.DownloadOptions{
+		downloads[i] = &moduleDownload{uri: dep.URI, opts: buildpack.DownloadOptions{
 			RegistryName:    opts.Registry,
 			RelativeBaseDir: opts.RelativeBaseDir,
 			ImageName:       dep.ImageName,
 			Daemon:          !opts.Publish,
 			PullPolicy:      opts.PullPolicy,
 			Target:          &target,
-		})
-		if err != nil {
+			RegistryLookup:  opts.RegistryLookup,
+		}}
+	}
+
+	if len(downloads) > 0 {
+		c.logger.Debugf("Downloading buildpack dependencies for platform %s", platform)
+	}
+	c.downloadModules(ctx, downloads)
+	for i, dep := range opts.Config.Dependencies {
+		if err := downloads[i].err; err != nil {
 			return digest, errors.Wrapf(err, "packaging dependencies (uri=%s,image=%s)", style.Symbol(dep.URI), style.Symbol(dep.ImageName))
 		}
 
-		packageBuilder.AddDependencies(mainBP, deps)
+		packageBuilder.AddDependencies(downloads[i].main, downloads[i].deps)
 	}
 
 	switch opts.Format {
@@ -196,6 +210,15 @@ func (c *Client) packageBuildpackTarget(ctx context.Context, opts PackageBuildpa
 		if err != nil {
 			return digest, errors.Wrapf(err, "saving image")
 		}
+		if opts.Publish {
+			compressed, err := c.compressPublishedImage(ctx, opts.Name, nil, opts.LayerCompression)
+			if err != nil {
+				return digest, err
+			}
+			if compressed != "" {
+				return compressed, nil
+			}
+		}
 		if multiArch {
 			// We need to keep the identifier to create the image index
 			id, err := img.Identifier()
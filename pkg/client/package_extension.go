@@ -55,8 +55,13 @@ func (c *Client) PackageExtension(ctx context.Context, opts PackageBuildpackOpti
 	case FormatFile:
 		return packageBuilder.SaveAsFile(opts.Name, target, map[string]string{})
 	case FormatImage:
-		_, err = packageBuilder.SaveAsImage(opts.Name, opts.Publish, target, map[string]string{})
-		return errors.Wrapf(err, "saving image")
+		if _, err = packageBuilder.SaveAsImage(opts.Name, opts.Publish, target, map[string]string{}); err != nil {
+			return errors.Wrapf(err, "saving image")
+		}
+		if opts.Publish {
+			_, err = c.compressPublishedImage(ctx, opts.Name, nil, opts.LayerCompression)
+		}
+		return err
 	default:
 		return errors.Errorf("unknown format: %s", style.Symbol(opts.Format))
 	}
@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// PodmanDockerHost is the value of DOCKER_HOST, or of the docker host of a build, selecting the Docker-compatible
+// socket of Podman wherever it is, e.g. DOCKER_HOST=podman pack build ... or pack build --docker-host=podman ...
+const PodmanDockerHost = "podman"
+
+const (
+	containerHostEnvVar = "CONTAINER_HOST"
+	xdgRuntimeDirEnvVar = "XDG_RUNTIME_DIR"
+
+	defaultDockerSocket = "/var/run/docker.sock"
+	rootfulPodmanSocket = "/run/podman/podman.sock"
+
+	// podmanEngineComponent is the name of the component of the server version of the compat API of Podman
+	podmanEngineComponent = "Podman Engine"
+)
+
+// PodmanSocket is the address of the Docker-compatible socket of Podman: CONTAINER_HOST when it's set, otherwise the
+// socket of rootless Podman in the runtime directory of the user or, failing that, the socket of the system service
+// of rootful Podman.
+func PodmanSocket() (string, error) {
+	if host := os.Getenv(containerHostEnvVar); host != "" {
+		return host, nil
+	}
+
+	var candidates []string
+	if dir := os.Getenv(xdgRuntimeDirEnvVar); dir != "" {
+		candidates = append(candidates, filepath.Join(dir, "podman", "podman.sock"))
+	}
+	candidates = append(candidates, rootfulPodmanSocket)
+	for _, socket := range candidates {
+		if isSocket(socket) {
+			return "unix://" + socket, nil
+		}
+	}
+	var symbols []string
+	for _, socket := range candidates {
+		symbols = append(symbols, style.Symbol(socket))
+	}
+	return "", errors.Errorf("no Podman socket at %s, start it with 'systemctl --user start podman.socket' or set %s", strings.Join(symbols, " or "), containerHostEnvVar)
+}
+
+// detectPodmanSocket is the Podman socket to use when DOCKER_HOST isn't set and there's no Docker socket, so that
+// pack works on hosts that only have Podman without pointing DOCKER_HOST at it
+func detectPodmanSocket() (string, bool) {
+	if runtime.GOOS == "windows" || isSocket(defaultDockerSocket) {
+		return "", false
+	}
+	socket, err := PodmanSocket()
+	if err != nil {
+		return "", false
+	}
+	return socket, true
+}
+
+// resolveDockerHost is the docker host exposed to the build containers, with PodmanDockerHost resolved to the Podman
+// socket, which pack must be using too so that the images it reads are those the lifecycle sees
+func (c *Client) resolveDockerHost(ctx context.Context, dockerHost string) (string, error) {
+	if dockerHost != PodmanDockerHost {
+		return dockerHost, nil
+	}
+
+	socket, err := PodmanSocket()
+	if err != nil {
+		return "", errors.Wrapf(err, "resolving docker host %s", style.Symbol(PodmanDockerHost))
+	}
+	if !c.isPodman(ctx) {
+		return "", errors.Errorf("docker host %s requires pack to use Podman as well, set %s=%s", style.Symbol(PodmanDockerHost), dockerHostEnvVar, PodmanDockerHost)
+	}
+	return socket, nil
+}
+
+// isPodman reports whether the daemon is Podman, through its Docker-compatible API
+func (c *Client) isPodman(ctx context.Context) bool {
+	version, err := c.docker.ServerVersion(ctx)
+	if err != nil {
+		return false
+	}
+	return isPodmanVersion(version)
+}
+
+func isPodmanVersion(version types.Version) bool {
+	for _, component := range version.Components {
+		if component.Name == podmanEngineComponent {
+			return true
+		}
+	}
+	return false
+}
+
+func isSocket(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode()&os.ModeSocket != 0
+}
@@ -0,0 +1,101 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/system"
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/testmocks"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestPodman(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Podman", testPodman, spec.Report(report.Terminal{}))
+}
+
+func testPodman(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject        *Client
+		mockController *gomock.Controller
+		mockDocker     *testmocks.MockCommonAPIClient
+		out            bytes.Buffer
+
+		podmanVersion = types.Version{Components: []types.ComponentVersion{{Name: "Podman Engine", Version: "5.2.0"}}}
+		dockerVersion = types.Version{Components: []types.ComponentVersion{{Name: "Engine", Version: "24.0.7"}}}
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockDocker = testmocks.NewMockCommonAPIClient(mockController)
+
+		subject = &Client{
+			logger: logging.NewLogWithWriters(&out, &out, logging.WithVerbose()),
+			docker: mockDocker,
+		}
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#resolveDockerHost", func() {
+		it.Before(func() {
+			os.Setenv("CONTAINER_HOST", "unix:///run/user/1000/podman/podman.sock")
+		})
+
+		it.After(func() {
+			os.Unsetenv("CONTAINER_HOST")
+		})
+
+		it("keeps other docker hosts", func() {
+			dockerHost, err := subject.resolveDockerHost(context.TODO(), "inherit")
+			h.AssertNil(t, err)
+			h.AssertEq(t, dockerHost, "inherit")
+		})
+
+		it("resolves the Podman socket", func() {
+			mockDocker.EXPECT().ServerVersion(gomock.Any()).Return(podmanVersion, nil)
+
+			dockerHost, err := subject.resolveDockerHost(context.TODO(), "podman")
+			h.AssertNil(t, err)
+			h.AssertEq(t, dockerHost, "unix:///run/user/1000/podman/podman.sock")
+		})
+
+		it("errors when pack doesn't use Podman", func() {
+			mockDocker.EXPECT().ServerVersion(gomock.Any()).Return(dockerVersion, nil)
+
+			_, err := subject.resolveDockerHost(context.TODO(), "podman")
+			h.AssertError(t, err, "docker host 'podman' requires pack to use Podman as well, set DOCKER_HOST=podman")
+		})
+	})
+
+	when("#usernsRemapped", func() {
+		rootless := system.Info{SecurityOptions: []string{"name=seccomp,profile=default", "name=rootless"}}
+
+		it("restores the ownership of volumes for rootless Podman", func() {
+			mockDocker.EXPECT().Info(gomock.Any()).Return(rootless, nil)
+			mockDocker.EXPECT().ServerVersion(gomock.Any()).Return(podmanVersion, nil)
+
+			h.AssertTrue(t, subject.usernsRemapped(context.TODO(), BuildOptions{}))
+			h.AssertContains(t, out.String(), "The daemon is rootless Podman")
+		})
+
+		it("doesn't for rootless Docker", func() {
+			mockDocker.EXPECT().Info(gomock.Any()).Return(rootless, nil)
+			mockDocker.EXPECT().ServerVersion(gomock.Any()).Return(dockerVersion, nil)
+
+			h.AssertFalse(t, subject.usernsRemapped(context.TODO(), BuildOptions{}))
+		})
+	})
+}
@@ -0,0 +1,322 @@
+package client
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	dimage "github.com/docker/docker/api/types/image"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pkg/errors"
+
+	internalConfig "github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/cache"
+)
+
+// Kinds of resources created by pack that Prune removes
+const (
+	// PruneCache is the kind of the volumes of the build and launch caches named after app images
+	PruneCache = "cache"
+
+	// PruneBuilder is the kind of the ephemeral builders and run images of builds
+	PruneBuilder = "builder"
+
+	// PruneLifecycle is the kind of the ephemeral lifecycle images of builds, and of the lifecycle images that lost
+	// their tag to a newer pull
+	PruneLifecycle = "lifecycle"
+
+	// PruneTemp is the kind of the temporary directories of builds and packages, holding OCI layouts and scratch files
+	PruneTemp = "temp"
+)
+
+// PruneKinds are the kinds of resources that Prune removes
+var PruneKinds = []string{PruneCache, PruneBuilder, PruneLifecycle, PruneTemp}
+
+// tempDirPrefixes are the prefixes of the temporary directories created by pack
+var tempDirPrefixes = []string{
+	"pack.tmp",
+	"package-buildpack",
+	"extension-buildpack",
+	"create-builder-scratch",
+	"create-lifecycle-scratch",
+	"extend-run-image-scratch",
+	"inline-cnb",
+	"pack-report",
+	"pack.sbom.merge.",
+	"pack.image.diff.",
+}
+
+// PruneOptions configures Prune
+type PruneOptions struct {
+	// Kinds of resources to remove, all of PruneKinds when empty
+	Kinds []string
+
+	// OlderThan only removes resources created at least this long ago, when set. Resources of unknown age are kept.
+	OlderThan time.Duration
+
+	// MinSize only removes resources of at least this size in bytes, when set. Resources of unknown size are kept.
+	MinSize int64
+
+	// Apps only removes the cache volumes of these app images, when set
+	Apps []string
+
+	// DryRun lists the resources that would be removed, without removing them
+	DryRun bool
+
+	// LifecycleImage is the lifecycle image configured for builds, whose untagged images are removed along with the
+	// ones of the default lifecycle image
+	LifecycleImage string
+
+	// TempDir is the directory temporary directories are looked for in, defaults to os.TempDir()
+	TempDir string
+}
+
+// PrunedResource is a resource removed by Prune, or that would be removed in a dry run
+type PrunedResource struct {
+	// Kind is one of PruneKinds
+	Kind string
+
+	// Name of the volume, image or directory
+	Name string
+
+	// Created is when the resource was created, or last modified for directories; zero when unknown
+	Created time.Time
+
+	// Size in bytes, 0 when unknown
+	Size int64
+
+	// Err is the error removing the resource, if any
+	Err error
+}
+
+// Prune removes the cache volumes, ephemeral images, untagged lifecycle images and temporary directories left by
+// builds, which are otherwise kept until removed by hand. Volumes and images used by containers are kept.
+func (c *Client) Prune(ctx context.Context, opts PruneOptions) ([]PrunedResource, error) {
+	kinds := map[string]bool{}
+	for _, kind := range opts.Kinds {
+		if !contains(PruneKinds, kind) {
+			return nil, errors.Errorf("unknown kind of resource %s, must be one of %s", style.Symbol(kind), strings.Join(PruneKinds, ", "))
+		}
+		kinds[kind] = true
+	}
+	apps, err := volumeApps(opts.Apps)
+	if err != nil {
+		return nil, err
+	}
+	if len(kinds) == 0 {
+		for _, kind := range PruneKinds {
+			kinds[kind] = true
+		}
+	}
+	if opts.TempDir == "" {
+		opts.TempDir = os.TempDir()
+	}
+
+	var candidates []PrunedResource
+	if kinds[PruneCache] || kinds[PruneBuilder] || kinds[PruneLifecycle] {
+		usage, err := c.docker.DiskUsage(ctx, types.DiskUsageOptions{Types: []types.DiskUsageObject{types.ImageObject, types.VolumeObject}})
+		if err != nil {
+			return nil, errors.Wrap(err, "listing docker images and volumes")
+		}
+		if kinds[PruneCache] {
+			candidates = append(candidates, cacheVolumes(usage, apps)...)
+		}
+		candidates = append(candidates, ephemeralImages(usage, kinds, lifecycleRepos(opts.LifecycleImage))...)
+	}
+	if kinds[PruneTemp] {
+		dirs, err := tempDirs(opts.TempDir)
+		if err != nil {
+			return nil, errors.Wrap(err, "listing temporary directories")
+		}
+		candidates = append(candidates, dirs...)
+	}
+
+	var pruned []PrunedResource
+	now := time.Now()
+	for _, resource := range candidates {
+		if opts.OlderThan > 0 && (resource.Created.IsZero() || now.Sub(resource.Created) < opts.OlderThan) {
+			continue
+		}
+		if opts.MinSize > 0 && resource.Size < opts.MinSize {
+			continue
+		}
+		if !opts.DryRun {
+			resource.Err = c.removeResource(ctx, resource, opts.TempDir)
+		}
+		pruned = append(pruned, resource)
+	}
+
+	sort.SliceStable(pruned, func(i, j int) bool {
+		if pruned[i].Kind != pruned[j].Kind {
+			return indexOf(PruneKinds, pruned[i].Kind) < indexOf(PruneKinds, pruned[j].Kind)
+		}
+		return pruned[i].Name < pruned[j].Name
+	})
+	return pruned, nil
+}
+
+func cacheVolumes(usage types.DiskUsage, apps map[string]bool) []PrunedResource {
+	var resources []PrunedResource
+	for _, volume := range usage.Volumes {
+		if volume == nil || !strings.HasPrefix(volume.Name, "pack-cache-") {
+			continue
+		}
+		if app, _, _ := cache.ParseVolumeName(volume.Name); len(apps) > 0 && !apps[app] {
+			continue
+		}
+		resource := PrunedResource{Kind: PruneCache, Name: volume.Name}
+		if volume.UsageData != nil {
+			if volume.UsageData.RefCount > 0 {
+				continue
+			}
+			if volume.UsageData.Size > 0 {
+				resource.Size = volume.UsageData.Size
+			}
+		}
+		if created, err := time.Parse(time.RFC3339, volume.CreatedAt); err == nil {
+			resource.Created = created
+		}
+		resources = append(resources, resource)
+	}
+	return resources
+}
+
+func ephemeralImages(usage types.DiskUsage, kinds map[string]bool, lifecycleRepos []string) []PrunedResource {
+	var resources []PrunedResource
+	for _, img := range usage.Images {
+		if img == nil || img.Containers > 0 {
+			continue
+		}
+		kind, imageName := ephemeralImageKind(img, lifecycleRepos)
+		if kind == "" || !kinds[kind] {
+			continue
+		}
+
+		resource := PrunedResource{Kind: kind, Name: imageName, Size: img.Size}
+		if img.Created > 0 {
+			resource.Created = time.Unix(img.Created, 0)
+		}
+		resources = append(resources, resource)
+	}
+	return resources
+}
+
+// ephemeralImageKind returns the kind of an image created by pack along with its name, or an empty kind for any other
+// image
+func ephemeralImageKind(img *dimage.Summary, lifecycleRepos []string) (string, string) {
+	var tagged bool
+	for _, tag := range img.RepoTags {
+		switch {
+		case strings.HasPrefix(tag, "pack.local/builder/"), strings.HasPrefix(tag, "pack.local/run-image/"):
+			return PruneBuilder, tag
+		case strings.HasPrefix(tag, "pack.local/lifecycle/"):
+			return PruneLifecycle, tag
+		case tag != "<none>:<none>":
+			tagged = true
+		}
+	}
+	if tagged {
+		return "", ""
+	}
+
+	for _, digest := range img.RepoDigests {
+		ref, err := name.ParseReference(digest, name.WeakValidation)
+		if err != nil {
+			continue
+		}
+		if contains(lifecycleRepos, ref.Context().Name()) {
+			return PruneLifecycle, img.ID
+		}
+	}
+	return "", ""
+}
+
+// lifecycleRepos returns the normalized repositories of the default lifecycle image and of lifecycleImage
+func lifecycleRepos(lifecycleImage string) []string {
+	var repos []string
+	for _, imageName := range []string{internalConfig.DefaultLifecycleImageRepo, lifecycleImage} {
+		if imageName == "" {
+			continue
+		}
+		if ref, err := name.ParseReference(imageName, name.WeakValidation); err == nil {
+			repos = append(repos, ref.Context().Name())
+		}
+	}
+	return repos
+}
+
+func tempDirs(tempDir string) ([]PrunedResource, error) {
+	entries, err := os.ReadDir(tempDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []PrunedResource
+	for _, entry := range entries {
+		if !entry.IsDir() || !hasTempDirPrefix(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		resources = append(resources, PrunedResource{
+			Kind:    PruneTemp,
+			Name:    entry.Name(),
+			Created: info.ModTime(),
+			Size:    dirSize(filepath.Join(tempDir, entry.Name())),
+		})
+	}
+	return resources, nil
+}
+
+func hasTempDirPrefix(dir string) bool {
+	for _, prefix := range tempDirPrefixes {
+		if strings.HasPrefix(dir, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// dirSize returns the size of the files of dir, skipping the ones that can't be read
+func dirSize(dir string) int64 {
+	var size int64
+	_ = filepath.WalkDir(dir, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info, err := entry.Info(); err == nil && info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
+
+func (c *Client) removeResource(ctx context.Context, resource PrunedResource, tempDir string) error {
+	switch resource.Kind {
+	case PruneCache:
+		return c.docker.VolumeRemove(ctx, resource.Name, false)
+	case PruneTemp:
+		return os.RemoveAll(filepath.Join(tempDir, resource.Name))
+	default:
+		_, err := c.docker.ImageRemove(ctx, resource.Name, dimage.RemoveOptions{PruneChildren: true})
+		return err
+	}
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,171 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	dimage "github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/pkg/errors"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/testmocks"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestPrune(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Prune", testPrune, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testPrune(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject          *Client
+		mockDockerClient *testmocks.MockCommonAPIClient
+		mockController   *gomock.Controller
+		out              bytes.Buffer
+		tmpDir           string
+		dayAgo           = time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+		minuteAgo        = time.Now().Add(-time.Minute).Truncate(time.Second)
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockDockerClient = testmocks.NewMockCommonAPIClient(mockController)
+
+		var err error
+		subject, err = NewClient(WithLogger(logging.NewLogWithWriters(&out, &out)), WithDockerClient(mockDockerClient))
+		h.AssertNil(t, err)
+
+		tmpDir, err = os.MkdirTemp("", "pack.prune.test.")
+		h.AssertNil(t, err)
+		for _, dir := range []string{"package-buildpack123", "some-other-dir"} {
+			h.AssertNil(t, os.MkdirAll(filepath.Join(tmpDir, dir, "oci-layout"), 0755))
+			h.AssertNil(t, os.WriteFile(filepath.Join(tmpDir, dir, "oci-layout", "index.json"), []byte("{}"), 0600))
+			h.AssertNil(t, os.Chtimes(filepath.Join(tmpDir, dir), dayAgo, dayAgo))
+		}
+
+		mockDockerClient.EXPECT().DiskUsage(gomock.Any(), gomock.Any()).Return(types.DiskUsage{
+			Images: []*dimage.Summary{
+				{ID: "sha256:builder", RepoTags: []string{"pack.local/builder/abc:latest"}, Size: 300, Created: dayAgo.Unix()},
+				{ID: "sha256:lifecycle", RepoTags: []string{"pack.local/lifecycle/def:latest"}, Size: 20, Created: minuteAgo.Unix()},
+				{ID: "sha256:old-lifecycle", RepoTags: []string{"<none>:<none>"}, RepoDigests: []string{"buildpacksio/lifecycle@sha256:0123456789012345678901234567890123456789012345678901234567890123"}, Size: 10, Created: dayAgo.Unix()},
+				{ID: "sha256:lifecycle-tagged", RepoTags: []string{"buildpacksio/lifecycle:0.19.0"}, Size: 10, Created: dayAgo.Unix()},
+				{ID: "sha256:used-builder", RepoTags: []string{"pack.local/builder/ghi:latest"}, Size: 300, Containers: 1},
+				{ID: "sha256:app", RepoTags: []string{"some/app:latest"}, Size: 100, Created: dayAgo.Unix()},
+			},
+			Volumes: []*volume.Volume{
+				{Name: "pack-cache-some_app_latest-0123456789ab.build", CreatedAt: dayAgo.Format(time.RFC3339), UsageData: &volume.UsageData{Size: 500}},
+				{Name: "pack-cache-some_app_latest-0123456789ab.launch", CreatedAt: minuteAgo.Format(time.RFC3339), UsageData: &volume.UsageData{Size: -1}},
+				{Name: "pack-cache-other_app_latest-456789abcdef.build", UsageData: &volume.UsageData{Size: 100, RefCount: 1}},
+				{Name: "some-volume", UsageData: &volume.UsageData{Size: 100}},
+			},
+		}, nil).AnyTimes()
+	})
+
+	it.After(func() {
+		mockController.Finish()
+		os.RemoveAll(tmpDir)
+	})
+
+	names := func(resources []PrunedResource) []string {
+		var result []string
+		for _, resource := range resources {
+			result = append(result, resource.Kind+" "+resource.Name)
+		}
+		return result
+	}
+
+	when("#Prune", func() {
+		it("lists the resources created by pack in a dry run", func() {
+			resources, err := subject.Prune(context.TODO(), PruneOptions{DryRun: true, TempDir: tmpDir})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, names(resources), []string{
+				"cache pack-cache-some_app_latest-0123456789ab.build",
+				"cache pack-cache-some_app_latest-0123456789ab.launch",
+				"builder pack.local/builder/abc:latest",
+				"lifecycle pack.local/lifecycle/def:latest",
+				"lifecycle sha256:old-lifecycle",
+				"temp package-buildpack123",
+			})
+			h.AssertEq(t, resources[0].Size, int64(500))
+			h.AssertEq(t, resources[0].Created.Equal(dayAgo), true)
+			h.AssertEq(t, resources[1].Size, int64(0))
+			h.AssertEq(t, resources[5].Size, int64(2))
+			h.AssertPathExists(t, filepath.Join(tmpDir, "package-buildpack123"))
+		})
+
+		it("removes the resources matching the filters", func() {
+			mockDockerClient.EXPECT().VolumeRemove(gomock.Any(), "pack-cache-some_app_latest-0123456789ab.build", false).Return(nil)
+			mockDockerClient.EXPECT().ImageRemove(gomock.Any(), "pack.local/builder/abc:latest", dimage.RemoveOptions{PruneChildren: true}).Return(nil, nil)
+
+			resources, err := subject.Prune(context.TODO(), PruneOptions{
+				Kinds:     []string{PruneCache, PruneBuilder},
+				OlderThan: time.Hour,
+				MinSize:   100,
+				TempDir:   tmpDir,
+			})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, names(resources), []string{
+				"cache pack-cache-some_app_latest-0123456789ab.build",
+				"builder pack.local/builder/abc:latest",
+			})
+		})
+
+		it("removes temporary directories", func() {
+			resources, err := subject.Prune(context.TODO(), PruneOptions{Kinds: []string{PruneTemp}, TempDir: tmpDir})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, names(resources), []string{"temp package-buildpack123"})
+			h.AssertPathDoesNotExists(t, filepath.Join(tmpDir, "package-buildpack123"))
+			h.AssertPathExists(t, filepath.Join(tmpDir, "some-other-dir"))
+		})
+
+		it("reports resources that couldn't be removed", func() {
+			mockDockerClient.EXPECT().ImageRemove(gomock.Any(), "sha256:old-lifecycle", gomock.Any()).Return(nil, errors.New("image is being used"))
+
+			resources, err := subject.Prune(context.TODO(), PruneOptions{Kinds: []string{PruneLifecycle}, OlderThan: time.Hour, TempDir: tmpDir})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, len(resources), 1)
+			h.AssertError(t, resources[0].Err, "image is being used")
+		})
+
+		it("only removes the cache volumes of the given apps", func() {
+			resources, err := subject.Prune(context.TODO(), PruneOptions{
+				Kinds:   []string{PruneCache},
+				Apps:    []string{"some/app"},
+				DryRun:  true,
+				TempDir: tmpDir,
+			})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, names(resources), []string{
+				"cache pack-cache-some_app_latest-0123456789ab.build",
+				"cache pack-cache-some_app_latest-0123456789ab.launch",
+			})
+		})
+
+		it("fails for an invalid app", func() {
+			_, err := subject.Prune(context.TODO(), PruneOptions{Apps: []string{"Some/App"}})
+			h.AssertError(t, err, "invalid app image 'Some/App'")
+		})
+
+		it("fails for an unknown kind", func() {
+			_, err := subject.Prune(context.TODO(), PruneOptions{Kinds: []string{"network"}})
+			h.AssertError(t, err, "unknown kind of resource 'network', must be one of cache, builder, lifecycle, temp")
+		})
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/pkg/errors"
 
 	"github.com/buildpacks/pack/internal/style"
@@ -16,8 +17,10 @@ import (
 type PullBuildpackOptions struct {
 	// URI of the buildpack to retrieve.
 	URI string
-	// RegistryName to search for buildpacks from.
+	// RegistryName to search for buildpacks from, the default registry of the config when empty.
 	RegistryName string
+	// RegistryLookup configures how the buildpacks of the registry are located.
+	RegistryLookup buildpack.RegistryLookup
 	// RelativeBaseDir to resolve relative assests from.
 	RelativeBaseDir string
 }
@@ -40,7 +43,7 @@ func (c *Client) PullBuildpack(ctx context.Context, opts PullBuildpackOptions) e
 		}
 	case buildpack.RegistryLocator:
 		c.logger.Debugf("Pulling buildpack from registry: %s", style.Symbol(opts.URI))
-		registryCache, err := getRegistry(c.logger, opts.RegistryName)
+		registryCache, err := getRegistry(c.logger, opts.RegistryName, c.registryOptions.with(opts.RegistryLookup))
 
 		if err != nil {
 			return errors.Wrapf(err, "invalid registry '%s'", opts.RegistryName)
@@ -51,7 +54,12 @@ func (c *Client) PullBuildpack(ctx context.Context, opts PullBuildpackOptions) e
 			return errors.Wrapf(err, "locating in registry %s", style.Symbol(opts.URI))
 		}
 
-		_, err = c.imageFetcher.Fetch(ctx, registryBp.Address, image.FetchOptions{Daemon: true, PullPolicy: image.PullAlways})
+		digest, err := name.NewDigest(registryBp.Address, name.WeakValidation)
+		if err != nil {
+			return errors.Wrapf(err, "parsing address of %s", style.Symbol(opts.URI))
+		}
+
+		_, err = c.imageFetcher.Fetch(ctx, registryBp.Address, image.FetchOptions{Daemon: true, PullPolicy: image.PullAlways, Digest: digest.DigestStr()})
 		if err != nil {
 			return errors.Wrapf(err, "fetching image %s", style.Symbol(opts.URI))
 		}
@@ -17,6 +17,7 @@ import (
 
 	cfg "github.com/buildpacks/pack/internal/config"
 	"github.com/buildpacks/pack/internal/registry"
+	"github.com/buildpacks/pack/pkg/buildpack"
 	"github.com/buildpacks/pack/pkg/client"
 	"github.com/buildpacks/pack/pkg/image"
 	"github.com/buildpacks/pack/pkg/logging"
@@ -72,6 +73,18 @@ func testPullBuildpack(t *testing.T, when spec.G, it spec.S) {
 			h.AssertError(t, err, "locating in registry")
 		})
 
+		it("should fail if the default registry isn't defined", func() {
+			packHome := t.TempDir()
+			h.AssertNil(t, os.Setenv("PACK_HOME", packHome))
+			defer os.Unsetenv("PACK_HOME")
+			h.AssertNil(t, cfg.Write(cfg.Config{DefaultRegistryName: "missing-registry"}, filepath.Join(packHome, "config.toml")))
+
+			err := subject.PullBuildpack(context.TODO(), client.PullBuildpackOptions{
+				URI: "urn:cnb:registry:example/foo@1.1.0",
+			})
+			h.AssertError(t, err, "registry 'missing-registry' is not defined in your config file")
+		})
+
 		it("should fail if it's a URI type", func() {
 			err := subject.PullBuildpack(context.TODO(), client.PullBuildpackOptions{
 				URI: "file://some-file",
@@ -105,6 +118,7 @@ func testPullBuildpack(t *testing.T, when spec.G, it spec.S) {
 			tmpDir          string
 			registryFixture string
 			packHome        string
+			configPath      string
 		)
 
 		it.Before(func() {
@@ -122,11 +136,15 @@ func testPullBuildpack(t *testing.T, when spec.G, it spec.S) {
 			packageImage := fakes.NewImage("example.com/some/package@sha256:74eb48882e835d8767f62940d453eb96ed2737de3a16573881dcea7dea769df7", "", nil)
 			packageImage.SetLabel("io.buildpacks.buildpackage.metadata", `{}`)
 			packageImage.SetLabel("io.buildpacks.buildpack.layers", `{}`)
-			mockImageFetcher.EXPECT().Fetch(gomock.Any(), packageImage.Name(), image.FetchOptions{Daemon: true, PullPolicy: image.PullAlways}).Return(packageImage, nil)
+			mockImageFetcher.EXPECT().Fetch(gomock.Any(), packageImage.Name(), image.FetchOptions{
+				Daemon:     true,
+				PullPolicy: image.PullAlways,
+				Digest:     "sha256:74eb48882e835d8767f62940d453eb96ed2737de3a16573881dcea7dea769df7",
+			}).Return(packageImage, nil)
 
 			packHome := filepath.Join(tmpDir, "packHome")
 			h.AssertNil(t, os.Setenv("PACK_HOME", packHome))
-			configPath := filepath.Join(packHome, "config.toml")
+			configPath = filepath.Join(packHome, "config.toml")
 			h.AssertNil(t, cfg.Write(cfg.Config{
 				Registries: []cfg.Registry{
 					{
@@ -152,5 +170,37 @@ func testPullBuildpack(t *testing.T, when spec.G, it spec.S) {
 				RegistryName: "some-registry",
 			}))
 		})
+
+		it("locates the buildpack in the default registry when none is selected", func() {
+			h.AssertNil(t, cfg.Write(cfg.Config{
+				DefaultRegistryName: "some-registry",
+				Registries: []cfg.Registry{
+					{
+						Name: "some-registry",
+						Type: "github",
+						URL:  registryFixture,
+					},
+				},
+			}, configPath))
+
+			h.AssertNil(t, subject.PullBuildpack(context.TODO(), client.PullBuildpackOptions{
+				URI: "urn:cnb:registry:example/foo@1.1.0",
+			}))
+		})
+
+		it("locates the buildpack in the existing cache when offline", func() {
+			h.AssertNil(t, subject.PullBuildpack(context.TODO(), client.PullBuildpackOptions{
+				URI:          "example/foo@1.1.0",
+				RegistryName: "some-registry",
+			}))
+
+			mockImageFetcher.EXPECT().Fetch(gomock.Any(), gomock.Any(), gomock.Any()).Return(fakes.NewImage("some/package", "", nil), nil)
+			h.AssertNil(t, subject.PullBuildpack(context.TODO(), client.PullBuildpackOptions{
+				URI:            "example/foo@1.1.0",
+				RegistryName:   "some-registry",
+				RegistryLookup: buildpack.RegistryLookup{Offline: true},
+			}))
+			h.AssertContains(t, out.String(), "Using the registry cache without refreshing it as pack is offline")
+		})
 	})
 }
@@ -0,0 +1,151 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/progress"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+// pushRetryDelay is the delay before the upload of a blob is first retried, which triples on each retry
+const pushRetryDelay = 2 * time.Second
+
+// pushRetryBackoff is the backoff of blob uploads retried up to retries times
+func pushRetryBackoff(retries int) remote.Backoff {
+	return remote.Backoff{Duration: pushRetryDelay, Factor: 3.0, Jitter: 0.1, Steps: retries + 1}
+}
+
+// pushLayoutImage pushes the image of the OCI layout at dir as ref and as its additional tags. Its layers are uploaded
+// one after the other, the upload of each blob being retried with backoff when it fails, e.g. on unreliable links.
+// Blobs the registry already has aren't uploaded again, so that pushing again resumes a failed push, whose error
+// reports the layers that reached the registry. The progress of the upload of each layer is shown as it's pushed.
+func (c *Client) pushLayoutImage(ctx context.Context, dir string, ref name.Reference, additionalTags []string, backoff remote.Backoff) error {
+	img, err := layoutImage(dir)
+	if err != nil {
+		return errors.Wrapf(err, "reading OCI layout %s", style.Symbol(dir))
+	}
+	layers, err := img.Layers()
+	if err != nil {
+		return err
+	}
+
+	remoteOpts := []remote.Option{remote.WithContext(ctx), remote.WithAuthFromKeychain(c.keychain), remote.WithRetryBackoff(backoff)}
+	pushed, err := c.writeLayers(ref, layers, remoteOpts)
+	if err != nil {
+		return pushError(ref, err, pushed, len(layers))
+	}
+
+	if err := remote.Write(ref, img, remoteOpts...); err != nil {
+		return pushError(ref, err, pushed, len(layers))
+	}
+	for _, tag := range additionalTags {
+		tagRef, err := name.ParseReference(tag, name.WeakValidation)
+		if err != nil {
+			return errors.Wrapf(err, "invalid tag %s", style.Symbol(tag))
+		}
+		// tags may be in other repositories, which lack the layers
+		if err := remote.Write(tagRef, img, remoteOpts...); err != nil {
+			return errors.Wrapf(err, "pushing %s", style.Symbol(tag))
+		}
+	}
+	return nil
+}
+
+// writeLayers uploads layers to the repository of ref one after the other, showing the progress of each upload. It
+// returns the digests of the layers that reached the registry.
+func (c *Client) writeLayers(ref name.Reference, layers []v1.Layer, remoteOpts []remote.Option) ([]string, error) {
+	display := c.newPushDisplay(ref.Name(), len(layers))
+	var pushed []string
+	for i, layer := range layers {
+		digest, err := layer.Digest()
+		if err != nil {
+			return pushed, err
+		}
+		size, err := layer.Size()
+		if err != nil {
+			return pushed, err
+		}
+		err = withPushProgress(display, shortDigest(digest.String()), size, func(updates chan<- v1.Update) error {
+			return remote.WriteLayer(ref.Context(), layer, append(remoteOpts, remote.WithProgress(updates))...)
+		})
+		if err != nil {
+			return pushed, err
+		}
+		pushed = append(pushed, digest.String())
+		c.logger.Debugf("Pushed layer %d of %d of %s, %s", i+1, len(layers), style.Symbol(ref.Name()), digest)
+	}
+	return pushed, display.Done()
+}
+
+// newPushDisplay returns the display of the progress of the push of the image name with the given number of layers
+func (c *Client) newPushDisplay(name string, layers int) *progress.PushDisplay {
+	out, isTerm, opts := progress.Output(logging.GetWriterForLevel(c.logger, logging.InfoLevel))
+	return progress.NewPushDisplay(out, isTerm, name, layers, opts...)
+}
+
+// withPushProgress runs push, showing the updates it reports as the progress of the layer id of the given size
+func withPushProgress(display *progress.PushDisplay, id string, size int64, push func(updates chan<- v1.Update) error) error {
+	updates := make(chan v1.Update, 100)
+	displayed := make(chan error, 1)
+	go func() {
+		displayed <- display.Layer(id, size, updates)
+	}()
+
+	err := push(updates)
+	displayErr := <-displayed
+	if err != nil {
+		return err
+	}
+	return displayErr
+}
+
+// pushError is the error of a failed push of ref, reporting the layers that reached the registry
+func pushError(ref name.Reference, err error, pushed []string, layers int) error {
+	if len(pushed) == 0 {
+		return errors.Wrapf(err, "pushing %s, none of its %d layers reached the registry", style.Symbol(ref.Name()), layers)
+	}
+	short := make([]string, len(pushed))
+	for i, digest := range pushed {
+		short[i] = shortDigest(digest)
+	}
+	return errors.Wrapf(err, "pushing %s, %d of its %d layers reached the registry and won't be uploaded again when pushing again (%s)",
+		style.Symbol(ref.Name()), len(pushed), layers, strings.Join(short, ", "))
+}
+
+// shortDigest is the first 12 characters of the hex of a digest, as listed by docker
+func shortDigest(digest string) string {
+	_, hex, _ := strings.Cut(digest, ":")
+	if len(hex) > 12 {
+		return hex[:12]
+	}
+	return hex
+}
+
+// layoutImage is the only image of the OCI layout at dir
+func layoutImage(dir string) (v1.Image, error) {
+	path, err := layout.FromPath(dir)
+	if err != nil {
+		return nil, err
+	}
+	index, err := path.ImageIndex()
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := index.IndexManifest()
+	if err != nil {
+		return nil, err
+	}
+	if len(manifest.Manifests) != 1 {
+		return nil, errors.Errorf("expected a single image, found %d", len(manifest.Manifests))
+	}
+	return index.Image(manifest.Manifests[0].Digest)
+}
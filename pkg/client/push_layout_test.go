@@ -0,0 +1,150 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestPushLayout(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "PushLayout", testPushLayout, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testPushLayout(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject *Client
+		out     bytes.Buffer
+		server  *httptest.Server
+		dir     string
+		img     v1.Image
+		tag     name.Tag
+		backoff = remote.Backoff{Duration: time.Millisecond, Factor: 1, Steps: 3}
+
+		mu sync.Mutex
+		// failures are the number of times the upload of the blob of a digest fails, by closing the connection
+		failures map[string]int
+	)
+
+	it.Before(func() {
+		failures = map[string]int{}
+		reg := registry.New(registry.Logger(log.New(io.Discard, "", 0)))
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPut && strings.Contains(r.URL.Path, "/blobs/uploads/") {
+				mu.Lock()
+				digest := r.URL.Query().Get("digest")
+				fail := failures[digest] > 0
+				if fail {
+					failures[digest]--
+				}
+				mu.Unlock()
+				if fail {
+					conn, _, err := w.(http.Hijacker).Hijack()
+					h.AssertNil(t, err)
+					conn.Close()
+					return
+				}
+			}
+			reg.ServeHTTP(w, r)
+		}))
+		subject = &Client{keychain: authn.DefaultKeychain, logger: logging.NewLogWithWriters(&out, &out)}
+
+		var err error
+		img, err = random.Image(1024, 2)
+		h.AssertNil(t, err)
+		dir, err = os.MkdirTemp("", "push-layout")
+		h.AssertNil(t, err)
+		path, err := layout.Write(dir, empty.Index)
+		h.AssertNil(t, err)
+		h.AssertNil(t, path.AppendImage(img))
+
+		tag, err = name.NewTag(strings.TrimPrefix(server.URL, "http://") + "/some/app:latest")
+		h.AssertNil(t, err)
+	})
+
+	it.After(func() {
+		server.Close()
+		h.AssertNil(t, os.RemoveAll(dir))
+	})
+
+	layerDigest := func(i int) string {
+		layers, err := img.Layers()
+		h.AssertNil(t, err)
+		digest, err := layers[i].Digest()
+		h.AssertNil(t, err)
+		return digest.String()
+	}
+
+	assertPushed := func(ref name.Reference) {
+		t.Helper()
+		pushed, err := remote.Image(ref)
+		h.AssertNil(t, err)
+		digest, err := pushed.Digest()
+		h.AssertNil(t, err)
+		expected, err := img.Digest()
+		h.AssertNil(t, err)
+		h.AssertEq(t, digest, expected)
+	}
+
+	when("#pushLayoutImage", func() {
+		it("pushes the image of the layout and its tags", func() {
+			other := tag.Context().Tag("other")
+			h.AssertNil(t, subject.pushLayoutImage(context.TODO(), dir, tag, []string{other.Name()}, backoff))
+
+			assertPushed(tag)
+			assertPushed(other)
+			h.AssertContains(t, out.String(), "Pushed '"+tag.Name()+"': 2/2 layers pushed")
+		})
+
+		it("retries failed blob uploads", func() {
+			failures[layerDigest(1)] = 2
+
+			h.AssertNil(t, subject.pushLayoutImage(context.TODO(), dir, tag, nil, backoff))
+			assertPushed(tag)
+		})
+
+		it("reports the layers that reached the registry when out of retries", func() {
+			failures[layerDigest(1)] = 100
+
+			err := subject.pushLayoutImage(context.TODO(), dir, tag, nil, backoff)
+			h.AssertError(t, err, "pushing '"+tag.Name()+"', 1 of its 2 layers reached the registry and won't be uploaded again when pushing again ("+shortDigest(layerDigest(0))+")")
+
+			mu.Lock()
+			failures = map[string]int{}
+			mu.Unlock()
+			h.AssertNil(t, subject.pushLayoutImage(context.TODO(), dir, tag, nil, backoff))
+			assertPushed(tag)
+		})
+
+		it("reports when no layer reached the registry", func() {
+			failures[layerDigest(0)] = 100
+
+			err := subject.pushLayoutImage(context.TODO(), dir, tag, nil, backoff)
+			h.AssertError(t, err, "none of its 2 layers reached the registry")
+		})
+	})
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/buildpacks/pack/internal/style"
 	"github.com/buildpacks/pack/pkg/dist"
 	"github.com/buildpacks/pack/pkg/image"
+	"github.com/buildpacks/pack/pkg/logging"
 )
 
 // RebaseOptions is a configuration struct that controls image rebase behavior.
@@ -137,6 +138,11 @@ func (c *Client) Rebase(ctx context.Context, opts RebaseOptions) error {
 	}
 
 	c.logger.Infof("Rebased Image: %s", style.Symbol(appImageIdentifier.String()))
+	if logging.IsQuiet(c.logger) {
+		if err := c.writeImageNameAndSha(imageRef, appImageIdentifier); err != nil {
+			return err
+		}
+	}
 
 	if opts.ReportDestinationDir != "" {
 		reportPath := filepath.Join(opts.ReportDestinationDir, "report.toml")
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/buildpacks/imgutil/fakes"
+	"github.com/buildpacks/imgutil/local"
 	"github.com/buildpacks/lifecycle/auth"
 	"github.com/heroku/color"
 	"github.com/sclevine/spec"
@@ -302,6 +303,33 @@ func testRebase(t *testing.T, when spec.G, it spec.S) {
 				})
 			})
 		})
+
+		when("quiet mode", func() {
+			var fakeQuietAppImage *fakes.Image
+
+			it.Before(func() {
+				fakeQuietAppImage = fakes.NewImage("quiet/app", "", local.IDIdentifier{ImageID: "sha256:some-image-id"})
+				h.AssertNil(t, fakeQuietAppImage.SetLabel("io.buildpacks.lifecycle.metadata",
+					`{"stack":{"runImage":{"image":"some/run"}}}`))
+				h.AssertNil(t, fakeQuietAppImage.SetLabel("io.buildpacks.stack.id", "io.buildpacks.stacks.jammy"))
+				fakeImageFetcher.LocalImages["quiet/app"] = fakeQuietAppImage
+
+				fakeLogger := logging.NewLogWithWriters(&out, &out)
+				fakeLogger.WantQuiet(true)
+				subject.logger = fakeLogger
+			})
+
+			it.After(func() {
+				h.AssertNilE(t, fakeQuietAppImage.Cleanup())
+			})
+
+			it("only prints the rebased image reference", func() {
+				h.AssertNil(t, subject.Rebase(context.TODO(), RebaseOptions{
+					RepoName: "quiet/app",
+				}))
+				h.AssertEq(t, out.String(), "quiet/app@sha256:some-image-id\n")
+			})
+		})
 	})
 }
 
@@ -3,11 +3,15 @@ package client
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/url"
+	"os"
 	"runtime"
 	"strings"
 
+	"github.com/buildpacks/pack/internal/config"
 	"github.com/buildpacks/pack/internal/registry"
+	"github.com/buildpacks/pack/internal/style"
 	"github.com/buildpacks/pack/pkg/buildpack"
 	"github.com/buildpacks/pack/pkg/dist"
 	"github.com/buildpacks/pack/pkg/image"
@@ -20,8 +24,16 @@ type RegisterBuildpackOptions struct {
 	Type      string
 	URL       string
 	Name      string
+
+	// PullRequest opens a pull request adding the buildpack to the index of a github registry, through the GitHub
+	// API, rather than opening the form of an issue in the browser
+	PullRequest bool
 }
 
+// githubTokenEnv is the environment variable of the token opening pull requests against github registries, when
+// no registry token is set
+const githubTokenEnv = "GITHUB_TOKEN"
+
 // RegisterBuildpack updates the Buildpack Registry with to include a new buildpack specified in
 // the opts argument
 func (c *Client) RegisterBuildpack(ctx context.Context, opts RegisterBuildpackOptions) error {
@@ -53,7 +65,9 @@ func (c *Client) RegisterBuildpack(ctx context.Context, opts RegisterBuildpackOp
 		Yanked:    false,
 	}
 
-	if opts.Type == "github" {
+	if opts.Type == "github" && opts.PullRequest {
+		return c.openRegistryPullRequest(ctx, opts, buildpack)
+	} else if opts.Type == "github" {
 		issueURL, err := registry.GetIssueURL(opts.URL)
 		if err != nil {
 			return err
@@ -77,7 +91,7 @@ func (c *Client) RegisterBuildpack(ctx context.Context, opts RegisterBuildpackOp
 
 		return cmd.Start()
 	} else if opts.Type == "git" {
-		registryCache, err := getRegistry(c.logger, opts.Name)
+		registryCache, err := getRegistry(c.logger, opts.Name, c.registryOptions)
 		if err != nil {
 			return err
 		}
@@ -87,7 +101,19 @@ func (c *Client) RegisterBuildpack(ctx context.Context, opts RegisterBuildpackOp
 			return err
 		}
 
-		if err := registry.GitCommit(buildpack, username, registryCache); err != nil {
+		var gitCache *registry.GitCache
+		switch cache := registryCache.(type) {
+		case *registry.GitCache:
+			gitCache = cache
+		case *registry.DirCache:
+			return fmt.Errorf("registry %s is a local directory, buildpacks are registered in it by adding them to its index", style.Symbol(registryCache.URL()))
+		case *registry.MemoryCache:
+			return fmt.Errorf("registry %s is an in-memory index, buildpacks can only be registered in registries served as git repositories", style.Symbol(registryCache.URL()))
+		default:
+			return fmt.Errorf("registry %s is served over HTTP, buildpacks can only be registered in registries served as git repositories", style.Symbol(registryCache.URL()))
+		}
+
+		if err := registry.GitCommit(buildpack, username, *gitCache); err != nil {
 			return err
 		}
 	}
@@ -95,6 +121,38 @@ func (c *Client) RegisterBuildpack(ctx context.Context, opts RegisterBuildpackOp
 	return nil
 }
 
+// openRegistryPullRequest opens a pull request adding the buildpack to the index of the github registry of opts
+func (c *Client) openRegistryPullRequest(ctx context.Context, opts RegisterBuildpackOptions, buildpack registry.Buildpack) error {
+	cfg, err := getConfig()
+	if err != nil {
+		return err
+	}
+
+	reg, err := registryConfig(cfg, opts.Name)
+	if err != nil {
+		return err
+	}
+
+	token := registryToken(reg)
+	if token == "" {
+		token = os.Getenv(githubTokenEnv)
+	}
+	if token == "" {
+		if reg.Name == config.OfficialRegistryName {
+			return fmt.Errorf("opening a pull request requires a GitHub token, set %s", style.Symbol(githubTokenEnv))
+		}
+		return fmt.Errorf("opening a pull request requires a GitHub token, set %s or %s", style.Symbol(registryTokenEnv(reg.Name)), style.Symbol(githubTokenEnv))
+	}
+
+	pullRequestURL, err := registry.OpenGithubPullRequest(ctx, opts.URL, token, buildpack)
+	if err != nil {
+		return err
+	}
+
+	c.logger.Infof("Opened pull request %s", style.Symbol(pullRequestURL))
+	return nil
+}
+
 func parseUsernameFromURL(url string) (string, error) {
 	parts := strings.Split(url, "/")
 	if len(parts) < 3 {
@@ -0,0 +1,172 @@
+package client
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/registry"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/archive"
+	"github.com/buildpacks/pack/pkg/buildpack"
+)
+
+// Entries of a registry cache archive
+const (
+	registryCacheManifestEntry = "registry-cache.json"
+	registryCacheIndexDir      = "index"
+)
+
+// ExportRegistryCacheOptions configures ExportRegistryCache
+type ExportRegistryCacheOptions struct {
+	// Name of the buildpack registry whose cache is exported, the default registry when empty
+	Registry string
+
+	// How the buildpacks of the registry are located
+	RegistryLookup buildpack.RegistryLookup
+}
+
+// RegistryCacheArchive describes the contents of a registry cache archive
+type RegistryCacheArchive struct {
+	Exported time.Time `json:"exported"`
+
+	// URL of the buildpack registry whose cache is archived
+	RegistryURL string `json:"registryURL"`
+}
+
+// ExportRegistryCache refreshes the cache of a buildpack registry, unless offline, and writes it to an archive at path,
+// including the metadata of its clone, so that ImportRegistryCache can restore it on a host without network access.
+func (c *Client) ExportRegistryCache(path string, opts ExportRegistryCacheOptions) (RegistryCacheArchive, error) {
+	registryOpts := c.registryOptions.with(opts.RegistryLookup)
+	registryCache, err := getRegistry(c.logger, opts.Registry, registryOpts)
+	if err != nil {
+		return RegistryCacheArchive{}, err
+	}
+	switch registryCache.(type) {
+	case *registry.DirCache:
+		return RegistryCacheArchive{}, errors.Errorf("registry %s is a local directory, copy the directory instead", style.Symbol(registryCache.URL()))
+	case *registry.MemoryCache:
+		return RegistryCacheArchive{}, errors.Errorf("registry %s is an in-memory index, it has no cache to export", style.Symbol(registryCache.URL()))
+	}
+
+	if registryOpts.offline {
+		if err := registry.RequireCache(c.logger, registryCache); err != nil {
+			return RegistryCacheArchive{}, err
+		}
+	} else if err := registryCache.Refresh(); err != nil {
+		return RegistryCacheArchive{}, errors.Wrap(err, "refreshing registry cache")
+	}
+
+	cacheArchive := RegistryCacheArchive{Exported: time.Now().UTC(), RegistryURL: registryCache.URL()}
+	if err := writeRegistryCacheArchive(path, cacheArchive, registryCache.Dir()); err != nil {
+		return RegistryCacheArchive{}, errors.Wrapf(err, "writing registry cache archive %s", style.Symbol(path))
+	}
+	return cacheArchive, nil
+}
+
+func writeRegistryCacheArchive(path string, cacheArchive RegistryCacheArchive, root string) error {
+	manifest, err := json.MarshalIndent(cacheArchive, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	gw := gzip.NewWriter(file)
+	tw := tar.NewWriter(gw)
+
+	if err := tw.WriteHeader(&tar.Header{Name: registryCacheManifestEntry, Mode: 0644, Size: int64(len(manifest)), ModTime: cacheArchive.Exported}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(manifest); err != nil {
+		return err
+	}
+	if err := archive.WriteDirToTar(tw, root, registryCacheIndexDir, 0, 0, -1, false, false, nil); err != nil {
+		return errors.Wrap(err, "adding registry index")
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// ImportRegistryCache replaces the cache of the buildpack registry of an archive written by ExportRegistryCache in the
+// pack home with the cache in the archive.
+func (c *Client) ImportRegistryCache(path string) (RegistryCacheArchive, error) {
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return RegistryCacheArchive{}, err
+	}
+	defer file.Close()
+
+	gr, err := gzip.NewReader(file)
+	if err != nil {
+		return RegistryCacheArchive{}, errors.Wrapf(err, "reading registry cache archive %s", style.Symbol(path))
+	}
+	defer gr.Close()
+
+	var (
+		cacheArchive RegistryCacheArchive
+		hasManifest  bool
+		indexDir     string
+	)
+	defer func() {
+		if indexDir != "" {
+			os.RemoveAll(indexDir)
+		}
+	}()
+
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return RegistryCacheArchive{}, errors.Wrapf(err, "reading registry cache archive %s", style.Symbol(path))
+		}
+
+		switch {
+		case header.Name == registryCacheManifestEntry:
+			if err := json.NewDecoder(tr).Decode(&cacheArchive); err != nil {
+				return RegistryCacheArchive{}, errors.Wrap(err, "reading registry cache manifest")
+			}
+			hasManifest = true
+		case strings.HasPrefix(header.Name, registryCacheIndexDir+"/"):
+			if indexDir == "" {
+				if indexDir, err = newRegistryIndexDir(); err != nil {
+					return RegistryCacheArchive{}, err
+				}
+			}
+			if err := extractBundleEntry(tr, header, indexDir, strings.TrimPrefix(header.Name, registryCacheIndexDir+"/")); err != nil {
+				return RegistryCacheArchive{}, err
+			}
+		}
+	}
+
+	if !hasManifest || cacheArchive.RegistryURL == "" {
+		return RegistryCacheArchive{}, errors.Errorf("%s is not a registry cache archive, it has no %s", style.Symbol(path), registryCacheManifestEntry)
+	}
+	if indexDir == "" {
+		return RegistryCacheArchive{}, errors.Errorf("registry cache archive %s has no index", style.Symbol(path))
+	}
+
+	if err := c.restoreRegistryIndex(indexDir, cacheArchive.RegistryURL); err != nil {
+		return RegistryCacheArchive{}, errors.Wrap(err, "restoring registry cache")
+	}
+	return cacheArchive, nil
+}
@@ -0,0 +1,105 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	cfg "github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/registry"
+	"github.com/buildpacks/pack/pkg/buildpack"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestRegistryCacheArchive(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "RegistryCacheArchive", testRegistryCacheArchive, spec.Report(report.Terminal{}))
+}
+
+func testRegistryCacheArchive(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject         *client.Client
+		out             bytes.Buffer
+		tmpDir          string
+		registryFixture string
+		archivePath     string
+	)
+
+	usePackHome := func(packHome string) {
+		h.AssertNil(t, os.Setenv("PACK_HOME", packHome))
+		h.AssertNil(t, cfg.Write(cfg.Config{
+			Registries: []cfg.Registry{
+				{
+					Name: "some-registry",
+					Type: "github",
+					URL:  registryFixture,
+				},
+			},
+		}, filepath.Join(packHome, "config.toml")))
+	}
+
+	it.Before(func() {
+		tmpDir = t.TempDir()
+		registryFixture = h.CreateRegistryFixture(t, tmpDir, filepath.Join("testdata", "registry"))
+		archivePath = filepath.Join(tmpDir, "registry-cache.tgz")
+		usePackHome(filepath.Join(tmpDir, "connected-home"))
+
+		var err error
+		subject, err = client.NewClient(client.WithLogger(logging.NewLogWithWriters(&out, &out)))
+		h.AssertNil(t, err)
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.Unsetenv("PACK_HOME"))
+	})
+
+	it("imports an exported registry cache on another host", func() {
+		exported, err := subject.ExportRegistryCache(archivePath, client.ExportRegistryCacheOptions{Registry: "some-registry"})
+		h.AssertNil(t, err)
+		h.AssertEq(t, exported.RegistryURL, registryFixture)
+
+		isolatedHome := filepath.Join(tmpDir, "isolated-home")
+		usePackHome(isolatedHome)
+		imported, err := subject.ImportRegistryCache(archivePath)
+		h.AssertNil(t, err)
+		h.AssertEq(t, imported.RegistryURL, registryFixture)
+
+		registryCache, err := registry.NewRegistryCache(logging.NewSimpleLogger(io.Discard), isolatedHome, registryFixture)
+		h.AssertNil(t, err)
+		h.AssertPathExists(t, filepath.Join(registryCache.Root, ".git", "HEAD"))
+
+		buildpacks, err := subject.SearchBuildpacks(context.TODO(), client.SearchBuildpacksOptions{
+			Term:           "foo",
+			Registry:       "some-registry",
+			RegistryLookup: buildpack.RegistryLookup{Offline: true},
+		})
+		h.AssertNil(t, err)
+		h.AssertEq(t, len(buildpacks), 1)
+		h.AssertEq(t, buildpacks[0].ID, "example/foo")
+	})
+
+	it("fails offline when the registry cache doesn't exist yet", func() {
+		_, err := subject.ExportRegistryCache(archivePath, client.ExportRegistryCacheOptions{
+			Registry:       "some-registry",
+			RegistryLookup: buildpack.RegistryLookup{Offline: true},
+		})
+		h.AssertError(t, err, "doesn't exist yet, it's created by running without --offline")
+	})
+
+	it("fails for archives that aren't registry cache archives", func() {
+		h.AssertNil(t, os.WriteFile(archivePath, []byte("not an archive"), 0600))
+
+		_, err := subject.ImportRegistryCache(archivePath)
+		h.AssertError(t, err, "reading registry cache archive")
+	})
+}
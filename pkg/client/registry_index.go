@@ -0,0 +1,57 @@
+package client
+
+import (
+	"github.com/buildpacks/pack/internal/registry"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+// RegistryIndexEntry is a buildpack of the in-memory registry index set with WithRegistryIndex
+type RegistryIndexEntry struct {
+	// ID of the buildpack, i.e. namespace/name
+	ID      string
+	Version string
+
+	// Address of the image the buildpack is packaged in, a digest reference
+	Address string
+	Yanked  bool
+}
+
+// WithRegistryIndex locates registry buildpacks in an in-memory index of entries, whatever their registry, rather than
+// in registry caches, so that the tests and ephemeral CI jobs of tools embedding pack neither reach a registry nor
+// write to disk.
+func WithRegistryIndex(entries []RegistryIndexEntry) Option {
+	return func(c *Client) {
+		c.registryOptions.index = append([]RegistryIndexEntry{}, entries...)
+	}
+}
+
+// memoryRegistry is the cache of the in-memory registry index, named after the registry it stands in for
+func memoryRegistry(logger logging.Logger, registryName string, opts registryOptions) (registry.Cache, error) {
+	if registryName == "" {
+		registryName = "default"
+	}
+
+	var buildpacks []registry.Buildpack
+	for _, entry := range opts.index {
+		ns, name, err := registry.ParseNamespaceName(entry.ID)
+		if err != nil {
+			return nil, err
+		}
+		buildpacks = append(buildpacks, registry.Buildpack{
+			Namespace: ns,
+			Name:      name,
+			Version:   entry.Version,
+			Address:   entry.Address,
+			Yanked:    entry.Yanked,
+		})
+	}
+
+	cache, err := registry.NewMemoryCache(logger, "memory://"+registryName, buildpacks,
+		registry.WithAllowYanked(opts.allowYanked),
+		registry.WithIncludePrereleases(opts.includePrereleases),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &cache, nil
+}
@@ -0,0 +1,36 @@
+package client
+
+import (
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/cache"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+// sshDaemonHost is the address of the daemon when it's reached over SSH, e.g. ssh://user@build-host, so that the
+// lifecycle containers run on another host than pack
+func sshDaemonHost() (string, bool) {
+	dockerHost := os.Getenv(dockerHostEnvVar)
+	return dockerHost, strings.HasPrefix(dockerHost, "ssh://")
+}
+
+// validateRemoteDaemonBuild fails for the options of a build mounting directories of this host, which a daemon on
+// another host can't, and warns that volumes are mounted from the host of the daemon
+func validateRemoteDaemonBuild(logger logging.Logger, opts BuildOptions, daemonHost string) error {
+	if opts.Layout() {
+		return errors.Errorf("exporting to an OCI layout mounts a directory of this host, which the daemon at %s can't, as it runs on another host", style.Symbol(daemonHost))
+	}
+	for _, cacheInfo := range []cache.CacheInfo{opts.Cache.Build, opts.Cache.Launch} {
+		if cacheInfo.Format == cache.CacheBind {
+			return errors.Errorf("the bind-mounted cache %s is a directory of this host, which the daemon at %s can't mount, as it runs on another host", style.Symbol(cacheInfo.Source), style.Symbol(daemonHost))
+		}
+	}
+	if len(opts.ContainerConfig.Volumes) > 0 {
+		logger.Warnf("The daemon at %s runs on another host, volumes are mounted from its host rather than this one", style.Symbol(daemonHost))
+	}
+	return nil
+}
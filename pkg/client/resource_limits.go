@@ -0,0 +1,45 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+)
+
+// resourceLimitsReport is the section of report.toml recording the resource limits of the build
+type resourceLimitsReport struct {
+	Pack struct {
+		Resources resourceLimits `toml:"resources"`
+	} `toml:"pack"`
+}
+
+type resourceLimits struct {
+	Memory    int64   `toml:"memory,omitempty"`
+	CPUs      float64 `toml:"cpus,omitempty"`
+	PidsLimit int64   `toml:"pids-limit,omitempty"`
+}
+
+// recordResourceLimits appends the resource limits of the build containers to the report at path, if there are any
+func recordResourceLimits(path string, config ContainerConfig) error {
+	if config.Memory <= 0 && config.CPUs <= 0 && config.PidsLimit <= 0 {
+		return nil
+	}
+
+	var report resourceLimitsReport
+	report.Pack.Resources = resourceLimits{Memory: config.Memory, CPUs: config.CPUs, PidsLimit: config.PidsLimit}
+
+	file, err := os.OpenFile(filepath.Clean(path), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString("\n"); err != nil {
+		return err
+	}
+	if err := toml.NewEncoder(file).Encode(report); err != nil {
+		return err
+	}
+	return file.Close()
+}
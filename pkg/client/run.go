@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	dcontainer "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/docker/go-connections/nat"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/container"
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// RunOptions configures Run
+type RunOptions struct {
+	// Image to run, from the docker daemon
+	Image string
+
+	// Ports to publish, in the form '<host port>:<container port>', or '<port>' to publish a port on the same host
+	// port
+	Ports []string
+
+	// Environment variables of the app
+	Env map[string]string
+
+	// Writers that the output of the app is streamed to
+	Out, ErrOut io.Writer
+}
+
+// Run runs an app image in a container of the docker daemon, streaming its output, until the app exits or ctx is
+// done. The container is removed either way. Run returns no error when ctx is done, since the app was stopped on
+// purpose.
+func (c *Client) Run(ctx context.Context, opts RunOptions) error {
+	var ports []string
+	for _, port := range opts.Ports {
+		if !strings.Contains(port, ":") {
+			port = port + ":" + port
+		}
+		ports = append(ports, port)
+	}
+	exposedPorts, portBindings, err := nat.ParsePortSpecs(ports)
+	if err != nil {
+		return errors.Wrap(err, "parsing ports")
+	}
+
+	var env []string
+	for key, value := range opts.Env {
+		env = append(env, fmt.Sprintf("%s=%s", key, value))
+	}
+	sort.Strings(env)
+
+	ctr, err := c.docker.ContainerCreate(ctx,
+		&dcontainer.Config{
+			Image:        opts.Image,
+			Env:          env,
+			ExposedPorts: exposedPorts,
+		},
+		&dcontainer.HostConfig{
+			PortBindings: portBindings,
+		},
+		nil, nil, "",
+	)
+	if err != nil {
+		return errors.Wrapf(err, "creating container of %s", style.Symbol(opts.Image))
+	}
+	defer c.docker.ContainerRemove(context.Background(), ctr.ID, dcontainer.RemoveOptions{Force: true})
+
+	err = container.RunWithHandler(ctx, c.docker, ctr.ID, func(bodyChan <-chan dcontainer.WaitResponse, errChan <-chan error, reader io.Reader) error {
+		copied := make(chan struct{})
+		go func() {
+			_, _ = stdcopy.StdCopy(opts.Out, opts.ErrOut, reader)
+			close(copied)
+		}()
+
+		select {
+		case body := <-bodyChan:
+			// the output of an app that exits is complete once the stream ends
+			<-copied
+			if body.StatusCode != 0 {
+				return errors.Errorf("app exited with status code %d", body.StatusCode)
+			}
+			return nil
+		case err := <-errChan:
+			return err
+		}
+	})
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
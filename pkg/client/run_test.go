@@ -0,0 +1,112 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	dcontainer "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/docker/go-connections/nat"
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/testmocks"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestRun(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Run", testRun, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testRun(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject          *Client
+		mockDockerClient *testmocks.MockCommonAPIClient
+		mockController   *gomock.Controller
+		out, errOut      bytes.Buffer
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockDockerClient = testmocks.NewMockCommonAPIClient(mockController)
+
+		var err error
+		subject, err = NewClient(WithLogger(logging.NewLogWithWriters(&out, &out)), WithDockerClient(mockDockerClient))
+		h.AssertNil(t, err)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	// expectRun expects the app to be started, to print output and to exit with statusCode
+	expectRun := func(output string, statusCode int64) {
+		var stream bytes.Buffer
+		_, err := stdcopy.NewStdWriter(&stream, stdcopy.Stdout).Write([]byte(output))
+		h.AssertNil(t, err)
+
+		conn, peer := net.Pipe()
+		it.After(func() { peer.Close() })
+
+		bodyChan := make(chan dcontainer.WaitResponse, 1)
+		bodyChan <- dcontainer.WaitResponse{StatusCode: statusCode}
+		mockDockerClient.EXPECT().ContainerWait(gomock.Any(), "some-container", dcontainer.WaitConditionNextExit).
+			Return(bodyChan, make(chan error))
+		mockDockerClient.EXPECT().ContainerAttach(gomock.Any(), "some-container", gomock.Any()).
+			Return(types.HijackedResponse{Conn: conn, Reader: bufio.NewReader(&stream)}, nil)
+		mockDockerClient.EXPECT().ContainerStart(gomock.Any(), "some-container", gomock.Any()).Return(nil)
+		mockDockerClient.EXPECT().ContainerRemove(gomock.Any(), "some-container", dcontainer.RemoveOptions{Force: true}).Return(nil)
+	}
+
+	it("runs the app with its ports and env, and streams its output", func() {
+		mockDockerClient.EXPECT().ContainerCreate(gomock.Any(), gomock.Any(), gomock.Any(), nil, nil, "").
+			DoAndReturn(func(_ context.Context, config *dcontainer.Config, hostConfig *dcontainer.HostConfig, _, _ interface{}, _ string) (dcontainer.CreateResponse, error) {
+				h.AssertEq(t, config.Image, "some/app")
+				h.AssertEq(t, config.Env, []string{"A=1", "B=2"})
+				h.AssertEq(t, config.ExposedPorts, nat.PortSet{"8080/tcp": {}, "9090/tcp": {}})
+				h.AssertEq(t, hostConfig.PortBindings, nat.PortMap{
+					"8080/tcp": {{HostPort: "8080"}},
+					"9090/tcp": {{HostPort: "19090"}},
+				})
+				return dcontainer.CreateResponse{ID: "some-container"}, nil
+			})
+		expectRun("listening on 8080\n", 0)
+
+		err := subject.Run(context.Background(), RunOptions{
+			Image:  "some/app",
+			Ports:  []string{"8080", "19090:9090"},
+			Env:    map[string]string{"B": "2", "A": "1"},
+			Out:    &out,
+			ErrOut: &errOut,
+		})
+		h.AssertNil(t, err)
+		h.AssertContains(t, out.String(), "listening on 8080")
+	})
+
+	when("the app fails", func() {
+		it("errors with its status code", func() {
+			mockDockerClient.EXPECT().ContainerCreate(gomock.Any(), gomock.Any(), gomock.Any(), nil, nil, "").
+				Return(dcontainer.CreateResponse{ID: "some-container"}, nil)
+			expectRun("panic\n", 2)
+
+			err := subject.Run(context.Background(), RunOptions{Image: "some/app", Out: &out, ErrOut: &errOut})
+			h.AssertError(t, err, "app exited with status code 2")
+		})
+	})
+
+	when("a port is invalid", func() {
+		it("errors", func() {
+			err := subject.Run(context.Background(), RunOptions{Image: "some/app", Ports: []string{"http"}})
+			h.AssertError(t, err, "parsing ports")
+		})
+	})
+}
@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/registry"
+	"github.com/buildpacks/pack/pkg/buildpack"
+)
+
+// SearchBuildpacksOptions define the buildpacks to search a registry for
+type SearchBuildpacksOptions struct {
+	// Term is matched against the ids of the buildpacks, i.e. <namespace>/<name>. All buildpacks match an empty term.
+	Term string
+
+	// Registry is the name of the buildpack registry to search, the default registry of the config when empty.
+	Registry string
+
+	// RegistryLookup configures how the buildpacks of the registry are located.
+	RegistryLookup buildpack.RegistryLookup
+}
+
+// RegistryBuildpack is a buildpack of a buildpack registry, at its highest version that wasn't yanked
+type RegistryBuildpack struct {
+	ID      string `json:"id"`
+	Version string `json:"version"`
+	Address string `json:"address"`
+}
+
+// SearchBuildpacks refreshes the cache of a buildpack registry, unless offline, and returns the buildpacks whose id
+// contains the term of opts, sorted by id. As the index of a registry served over HTTP can't be listed, only the
+// buildpacks located in it before are searched.
+func (c *Client) SearchBuildpacks(ctx context.Context, opts SearchBuildpacksOptions) ([]RegistryBuildpack, error) {
+	registryOpts := c.registryOptions.with(opts.RegistryLookup)
+	registryCache, err := getRegistry(c.logger, opts.Registry, registryOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	if registryOpts.offline {
+		if err := registry.RequireCache(c.logger, registryCache); err != nil {
+			return nil, err
+		}
+	} else if err := registryCache.Refresh(); err != nil {
+		if _, statErr := os.Stat(registryCache.Dir()); statErr != nil {
+			return nil, errors.Wrap(err, "refreshing registry cache")
+		}
+		c.logger.Warnf("Unable to refresh the registry cache, searching the cached index: %s", err)
+	}
+
+	buildpacks, err := registry.SearchBuildpacks(registryCache, opts.Term)
+	if err != nil {
+		return nil, errors.Wrap(err, "searching registry cache")
+	}
+
+	results := []RegistryBuildpack{}
+	for _, bp := range buildpacks {
+		results = append(results, RegistryBuildpack{
+			ID:      bp.Namespace + "/" + bp.Name,
+			Version: bp.Version,
+			Address: bp.Address,
+		})
+	}
+	return results, nil
+}
@@ -0,0 +1,103 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	cfg "github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/buildpack"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestSearchBuildpacks(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "SearchBuildpacks", testSearchBuildpacks, spec.Report(report.Terminal{}))
+}
+
+func testSearchBuildpacks(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject *client.Client
+		out     bytes.Buffer
+	)
+
+	it.Before(func() {
+		tmpDir := t.TempDir()
+		registryFixture := h.CreateRegistryFixture(t, tmpDir, filepath.Join("testdata", "registry"))
+
+		packHome := filepath.Join(tmpDir, "packHome")
+		h.AssertNil(t, os.Setenv("PACK_HOME", packHome))
+		h.AssertNil(t, cfg.Write(cfg.Config{
+			Registries: []cfg.Registry{
+				{
+					Name: "some-registry",
+					Type: "github",
+					URL:  registryFixture,
+				},
+			},
+		}, filepath.Join(packHome, "config.toml")))
+
+		var err error
+		subject, err = client.NewClient(client.WithLogger(logging.NewLogWithWriters(&out, &out)))
+		h.AssertNil(t, err)
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.Unsetenv("PACK_HOME"))
+	})
+
+	it("returns the buildpacks of the registry matching the term", func() {
+		buildpacks, err := subject.SearchBuildpacks(context.TODO(), client.SearchBuildpacksOptions{
+			Term:     "foo",
+			Registry: "some-registry",
+		})
+		h.AssertNil(t, err)
+		h.AssertEq(t, buildpacks, []client.RegistryBuildpack{
+			{
+				ID:      "example/foo",
+				Version: "1.2.0",
+				Address: "example.com/some/package@sha256:2560f05307e8de9d830f144d09556e19dd1eb7d928aee900ed02208ae9727e7a",
+			},
+		})
+	})
+
+	it("searches the in-memory registry index without a registry cache", func() {
+		var err error
+		subject, err = client.NewClient(client.WithLogger(logging.NewLogWithWriters(&out, &out)), client.WithOffline(true), client.WithRegistryIndex([]client.RegistryIndexEntry{
+			{ID: "example/foo", Version: "2.0.0", Address: "example.com/foo@sha256:2560f05307e8de9d830f144d09556e19dd1eb7d928aee900ed02208ae9727e7a"},
+			{ID: "example/bar", Version: "1.0.0", Address: "example.com/bar@sha256:2560f05307e8de9d830f144d09556e19dd1eb7d928aee900ed02208ae9727e7a"},
+		}))
+		h.AssertNil(t, err)
+
+		buildpacks, err := subject.SearchBuildpacks(context.TODO(), client.SearchBuildpacksOptions{
+			Term:     "foo",
+			Registry: "some-registry",
+		})
+		h.AssertNil(t, err)
+		h.AssertEq(t, buildpacks, []client.RegistryBuildpack{
+			{
+				ID:      "example/foo",
+				Version: "2.0.0",
+				Address: "example.com/foo@sha256:2560f05307e8de9d830f144d09556e19dd1eb7d928aee900ed02208ae9727e7a",
+			},
+		})
+	})
+
+	it("fails offline when the registry cache doesn't exist yet", func() {
+		_, err := subject.SearchBuildpacks(context.TODO(), client.SearchBuildpacksOptions{
+			Term:           "foo",
+			Registry:       "some-registry",
+			RegistryLookup: buildpack.RegistryLookup{Offline: true},
+		})
+		h.AssertError(t, err, "doesn't exist yet, it's created by running without --offline")
+	})
+}
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/buildpacks/lifecycle/platform"
+	"github.com/buildpacks/lifecycle/platform/files"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/dist"
+	"github.com/buildpacks/pack/pkg/image"
+)
+
+// SetDefaultProcessOptions configures SetDefaultProcess
+type SetDefaultProcessOptions struct {
+	// Image whose default process is set
+	Image string
+
+	// ProcessType is the type of the process to run by default, one of the processes of the image
+	ProcessType string
+
+	// Publish sets the default process of the image in its registry, rather than in the docker daemon
+	Publish bool
+}
+
+// SetDefaultProcess changes the process an app image runs by default, without rebuilding it. The entrypoint is
+// pointed at the launcher of the process, which the lifecycle adds to images for each of their processes.
+func (c *Client) SetDefaultProcess(ctx context.Context, opts SetDefaultProcessOptions) error {
+	if opts.ProcessType == "" {
+		return errors.New("process type must be specified")
+	}
+
+	img, err := c.imageFetcher.Fetch(ctx, opts.Image, image.FetchOptions{Daemon: !opts.Publish, PullPolicy: image.PullNever})
+	if err != nil {
+		if errors.Cause(err) == image.ErrNotFound {
+			return errors.Errorf("image %s does not exist", style.Symbol(opts.Image))
+		}
+		return err
+	}
+
+	var buildMD files.BuildMetadata
+	if ok, err := dist.GetLabel(img, platform.BuildMetadataLabel, &buildMD); err != nil {
+		return err
+	} else if !ok {
+		return errors.Errorf("image %s wasn't built with buildpacks, it has no label %s", style.Symbol(opts.Image), style.Symbol(platform.BuildMetadataLabel))
+	}
+
+	var types []string
+	for _, proc := range buildMD.Processes {
+		types = append(types, proc.Type)
+	}
+	if !contains(types, opts.ProcessType) {
+		if len(types) == 0 {
+			return errors.Errorf("image %s has no processes", style.Symbol(opts.Image))
+		}
+		return errors.Errorf("image %s has no process %s, must be one of %s", style.Symbol(opts.Image), style.Symbol(opts.ProcessType), strings.Join(types, ", "))
+	}
+
+	platformAPI, err := img.Env(platformAPIEnv)
+	if err != nil {
+		return errors.Wrap(err, "reading platform api")
+	}
+	if platformAPI == "" {
+		platformAPI = fallbackPlatformAPI
+	}
+	platformAPIVersion, err := semver.NewVersion(platformAPI)
+	if err != nil {
+		return errors.Wrap(err, "parsing platform api version")
+	}
+
+	if platformAPIVersion.LessThan(semver.MustParse("0.4")) {
+		// the launcher of images built with older platform APIs reads the default process from the env
+		if err := img.SetEnv(cnbProcessEnv, opts.ProcessType); err != nil {
+			return errors.Wrap(err, "setting env")
+		}
+	} else {
+		imageOS, err := img.OS()
+		if err != nil {
+			return errors.Wrap(err, "getting image OS")
+		}
+		entrypoint := entrypointPrefix + opts.ProcessType
+		if imageOS == "windows" {
+			entrypoint = windowsEntrypointPrefix + opts.ProcessType + ".exe"
+		}
+		if err := img.SetEntrypoint(entrypoint); err != nil {
+			return errors.Wrap(err, "setting entrypoint")
+		}
+		// the lifecycle leaves the command empty, so that arguments go to the process
+		if err := img.SetCmd(); err != nil {
+			return errors.Wrap(err, "setting cmd")
+		}
+	}
+
+	c.logger.Debugf("Saving %s", style.Symbol(img.Name()))
+	return img.Save()
+}
@@ -0,0 +1,112 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/image"
+	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/testmocks"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestSetDefaultProcess(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "SetDefaultProcess", testSetDefaultProcess, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testSetDefaultProcess(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject          *Client
+		mockImageFetcher *testmocks.MockImageFetcher
+		mockController   *gomock.Controller
+		mockImage        *testmocks.MockImage
+		out              bytes.Buffer
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockImageFetcher = testmocks.NewMockImageFetcher(mockController)
+
+		var err error
+		subject, err = NewClient(WithLogger(logging.NewLogWithWriters(&out, &out)), WithFetcher(mockImageFetcher))
+		h.AssertNil(t, err)
+
+		mockImage = testmocks.NewImage("some/app", "", nil)
+		h.AssertNil(t, mockImage.SetLabel("io.buildpacks.build.metadata", `{
+  "processes": [
+    {"type": "web", "command": "./web", "buildpackID": "some/buildpack"},
+    {"type": "worker", "command": "./worker", "buildpackID": "some/buildpack"}
+  ]
+}`))
+		h.AssertNil(t, mockImage.SetEnv("CNB_PLATFORM_API", "0.12"))
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#SetDefaultProcess", func() {
+		it("points the entrypoint of a daemon image at the process", func() {
+			mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/app", image.FetchOptions{Daemon: true, PullPolicy: image.PullNever}).Return(mockImage, nil)
+
+			h.AssertNil(t, subject.SetDefaultProcess(context.TODO(), SetDefaultProcessOptions{Image: "some/app", ProcessType: "worker"}))
+
+			entrypoint, err := mockImage.Image.Entrypoint()
+			h.AssertNil(t, err)
+			h.AssertEq(t, entrypoint, []string{"/cnb/process/worker"})
+			h.AssertEq(t, mockImage.IsSaved(), true)
+		})
+
+		it("sets the default process of a remote image", func() {
+			mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/app", image.FetchOptions{Daemon: false, PullPolicy: image.PullNever}).Return(mockImage, nil)
+
+			h.AssertNil(t, subject.SetDefaultProcess(context.TODO(), SetDefaultProcessOptions{Image: "some/app", ProcessType: "worker", Publish: true}))
+			h.AssertEq(t, mockImage.IsSaved(), true)
+		})
+
+		it("uses the launcher of the process on windows images", func() {
+			h.AssertNil(t, mockImage.SetOS("windows"))
+			mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/app", gomock.Any()).Return(mockImage, nil)
+
+			h.AssertNil(t, subject.SetDefaultProcess(context.TODO(), SetDefaultProcessOptions{Image: "some/app", ProcessType: "worker"}))
+
+			entrypoint, err := mockImage.Image.Entrypoint()
+			h.AssertNil(t, err)
+			h.AssertEq(t, entrypoint, []string{`c:\cnb\process\worker.exe`})
+		})
+
+		it("sets the process env of images built with platform API older than 0.4", func() {
+			h.AssertNil(t, mockImage.SetEnv("CNB_PLATFORM_API", "0.3"))
+			mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/app", gomock.Any()).Return(mockImage, nil)
+
+			h.AssertNil(t, subject.SetDefaultProcess(context.TODO(), SetDefaultProcessOptions{Image: "some/app", ProcessType: "worker"}))
+
+			processType, err := mockImage.Env("CNB_PROCESS_TYPE")
+			h.AssertNil(t, err)
+			h.AssertEq(t, processType, "worker")
+		})
+
+		it("fails for a process the image doesn't have", func() {
+			mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/app", gomock.Any()).Return(mockImage, nil)
+
+			err := subject.SetDefaultProcess(context.TODO(), SetDefaultProcessOptions{Image: "some/app", ProcessType: "cron"})
+			h.AssertError(t, err, "image 'some/app' has no process 'cron', must be one of web, worker")
+			h.AssertEq(t, mockImage.IsSaved(), false)
+		})
+
+		it("fails for an image that doesn't exist", func() {
+			mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/app", gomock.Any()).Return(nil, image.ErrNotFound)
+
+			err := subject.SetDefaultProcess(context.TODO(), SetDefaultProcessOptions{Image: "some/app", ProcessType: "worker"})
+			h.AssertError(t, err, "image 'some/app' does not exist")
+		})
+	})
+}
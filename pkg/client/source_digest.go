@@ -0,0 +1,316 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/buildpacks/imgutil"
+	"github.com/buildpacks/lifecycle/platform"
+	"github.com/buildpacks/lifecycle/platform/files"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/buildpack"
+	"github.com/buildpacks/pack/pkg/cache"
+	"github.com/buildpacks/pack/pkg/dist"
+	"github.com/buildpacks/pack/pkg/image"
+	"github.com/buildpacks/pack/pkg/project"
+	projectTypes "github.com/buildpacks/pack/pkg/project/types"
+)
+
+// sourceDigestKey is the key of the source metadata of the io.buildpacks.project.metadata label of images holding
+// their source digest
+const sourceDigestKey = "sourceDigest"
+
+// sourceDigestInputs is the configuration of a build covered by its source digest, along with the app source.
+// Builds with the same source digest produce the same image, as long as the buildpacks behave the same.
+type sourceDigestInputs struct {
+	Builder            string               `json:"builder"`
+	RunImage           string               `json:"runImage"`
+	LifecycleImage     string               `json:"lifecycleImage,omitempty"`
+	Platform           string               `json:"platform,omitempty"`
+	Buildpacks         []string             `json:"buildpacks,omitempty"`
+	PreBuildpacks      []string             `json:"preBuildpacks,omitempty"`
+	PostBuildpacks     []string             `json:"postBuildpacks,omitempty"`
+	Extensions         []string             `json:"extensions,omitempty"`
+	Modules            []string             `json:"modules,omitempty"`
+	Env                map[string]string    `json:"env,omitempty"`
+	LifecycleEnv       map[string]string    `json:"lifecycleEnv,omitempty"`
+	Labels             map[string]string    `json:"labels,omitempty"`
+	Annotations        map[string]string    `json:"annotations,omitempty"`
+	Build              projectTypes.Build   `json:"build"`
+	DefaultProcessType string               `json:"defaultProcessType,omitempty"`
+	Volumes            []string             `json:"volumes,omitempty"`
+	Network            string               `json:"network,omitempty"`
+	ExtraHosts         []string             `json:"extraHosts,omitempty"`
+	SSHAgent           bool                 `json:"sshAgent,omitempty"`
+	Cache              cache.CacheOpts      `json:"cache"`
+	CacheImage         string               `json:"cacheImage,omitempty"`
+	LayerCompression   LayerCompression     `json:"layerCompression,omitempty"`
+	Workspace          string               `json:"workspace,omitempty"`
+	GroupID            int                  `json:"gid"`
+	UserID             int                  `json:"uid"`
+	Publish            bool                 `json:"publish"`
+	Project            projectTypes.Project `json:"project"`
+}
+
+// sourceDigest computes the digest of the configuration of a build and of the files of its app that are added to
+// the image, as filtered by fileFilter. Modification times are left out, so that fresh checkouts of the same
+// source have the same digest.
+func sourceDigest(appPath string, fileFilter func(string) bool, inputs sourceDigestInputs) (string, error) {
+	h := sha256.New()
+	if err := json.NewEncoder(h).Encode(inputs); err != nil {
+		return "", err
+	}
+
+	info, err := os.Stat(appPath)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		// zip files are added as a whole
+		if err := hashFile(h, appPath); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("sha256:%x", h.Sum(nil)), nil
+	}
+
+	err = filepath.WalkDir(appPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(appPath, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." || (fileFilter != nil && !fileFilter(rel)) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "%s\x00%s\x00", filepath.ToSlash(rel), info.Mode())
+		switch {
+		case info.Mode()&fs.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(h, "%s\x00", target)
+		case info.Mode().IsRegular():
+			if err := hashFile(h, path); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("sha256:%x", h.Sum(nil)), nil
+}
+
+func hashFile(h hash.Hash, path string) error {
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	size, err := io.Copy(h, file)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(h, "\x00%d\x00", size)
+	return nil
+}
+
+// previousSourceDigest returns the source digest recorded in the image named imageName, or an empty string when the
+// image or its digest can't be found
+func (c *Client) previousSourceDigest(ctx context.Context, imageName string, publish bool) string {
+	img, err := c.imageFetcher.Fetch(ctx, imageName, image.FetchOptions{Daemon: !publish, PullPolicy: image.PullNever})
+	if err != nil {
+		return ""
+	}
+
+	var metadata files.ProjectMetadata
+	if _, err := dist.GetLabel(img, platform.ProjectMetadataLabel, &metadata); err != nil || metadata.Source == nil {
+		return ""
+	}
+	digest, _ := metadata.Source.Metadata[sourceDigestKey].(string)
+	return digest
+}
+
+// withSourceDigest records digest in the source metadata of a project
+func withSourceDigest(metadata files.ProjectMetadata, digest string) files.ProjectMetadata {
+	source := files.ProjectSource{Type: "directory"}
+	if metadata.Source != nil {
+		source = *metadata.Source
+	}
+
+	sourceMetadata := map[string]interface{}{}
+	for key, value := range source.Metadata {
+		sourceMetadata[key] = value
+	}
+	sourceMetadata[sourceDigestKey] = digest
+	source.Metadata = sourceMetadata
+
+	metadata.Source = &source
+	return metadata
+}
+
+// canSkipBuild reports whether a build has no outputs besides its image, so that it can be skipped. Builds with a
+// creation time are never skipped, as the image is expected to be created at that time.
+func canSkipBuild(opts BuildOptions) bool {
+	return opts.SBOMDestinationDir == "" && opts.ReportDestinationDir == "" && !opts.ClearCache && !opts.ClearBuildCache && !opts.ClearLaunchCache && len(opts.ClearBuildpackCaches) == 0 && !opts.Layout() && !opts.Interactive && !opts.DetectOnly && opts.CreationTime == nil
+}
+
+// skipUnchangedBuild skips the build of imageName when asked to and the previous image, or the image itself when no
+// previous image is set, has the source digest of the build. It reports whether the build was skipped.
+func (c *Client) skipUnchangedBuild(ctx context.Context, opts BuildOptions, imageName, digest string, started time.Time) (bool, error) {
+	if !opts.SkipUnchanged || !canSkipBuild(opts) {
+		return false, nil
+	}
+
+	previousImage := imageName
+	if opts.PreviousImage != "" {
+		previousImage = opts.PreviousImage
+	}
+	if c.previousSourceDigest(ctx, previousImage, opts.Publish) != digest {
+		return false, nil
+	}
+
+	c.logger.Infof("Skipping the build, %s was built from the same source and configuration", style.Symbol(previousImage))
+	if err := c.tagUnchangedImage(ctx, previousImage, imageName, opts.AdditionalTags, opts.Publish); err != nil {
+		return false, err
+	}
+	if opts.Summary != nil {
+		*opts.Summary = BuildSummary{Image: previousImage, Skipped: true, SourceDigest: digest, Started: started}
+	}
+	return true, nil
+}
+
+// tagUnchangedImage tags the image of a skipped build, imageName, with the image name of the build, when the previous
+// image was another one, and with its additional tags
+func (c *Client) tagUnchangedImage(ctx context.Context, imageName, buildImageName string, additionalTags []string, publish bool) error {
+	var tags []string
+	if buildImageName != imageName {
+		tags = append(tags, buildImageName)
+	}
+	tags = append(tags, additionalTags...)
+	if len(tags) == 0 {
+		return nil
+	}
+
+	if !publish {
+		for _, tag := range tags {
+			if err := c.docker.ImageTag(ctx, imageName, tag); err != nil {
+				return errors.Wrapf(err, "tagging %s as %s", style.Symbol(imageName), style.Symbol(tag))
+			}
+		}
+		return nil
+	}
+
+	ref, err := name.ParseReference(imageName, name.WeakValidation)
+	if err != nil {
+		return errors.Wrapf(err, "invalid image name %s", style.Symbol(imageName))
+	}
+	remoteOpts := []remote.Option{remote.WithContext(ctx), remote.WithAuthFromKeychain(c.keychain)}
+	img, err := remote.Image(ref, remoteOpts...)
+	if err != nil {
+		return errors.Wrapf(err, "fetching %s", style.Symbol(imageName))
+	}
+	for _, tag := range tags {
+		tagRef, err := name.ParseReference(tag, name.WeakValidation)
+		if err != nil {
+			return errors.Wrapf(err, "invalid tag %s", style.Symbol(tag))
+		}
+		// tags may be in other repositories, which lack the layers of the image
+		if err := remote.Write(tagRef, img, remoteOpts...); err != nil {
+			return errors.Wrapf(err, "tagging %s as %s", style.Symbol(imageName), style.Symbol(tag))
+		}
+	}
+	return nil
+}
+
+// buildSourceDigest is the source digest of a build, covering the images it runs on, the content of the buildpacks
+// and extensions added to the builder as resolved for the build, and the options that change the image
+func (c *Client) buildSourceDigest(appPath string, opts BuildOptions, builderImage, runImage imgutil.Image, runImageName string, modules []buildpack.BuildModule) (string, error) {
+	builderID, err := builderImage.Identifier()
+	if err != nil {
+		return "", err
+	}
+	runImageID, err := runImage.Identifier()
+	if err != nil {
+		return "", err
+	}
+	fileFilter, err := project.FileFilter(opts.ProjectDescriptor.Build)
+	if err != nil {
+		return "", err
+	}
+	var moduleDigests []string
+	for _, module := range modules {
+		digest, err := moduleDigest(module)
+		if err != nil {
+			return "", errors.Wrapf(err, "computing digest of %s", style.Symbol(module.Descriptor().Info().FullName()))
+		}
+		moduleDigests = append(moduleDigests, fmt.Sprintf("%s@%s", module.Descriptor().Info().FullName(), digest))
+	}
+
+	return sourceDigest(appPath, fileFilter, sourceDigestInputs{
+		Builder:            fmt.Sprintf("%s@%s", builderImage.Name(), builderID),
+		RunImage:           fmt.Sprintf("%s@%s", runImageName, runImageID),
+		LifecycleImage:     opts.LifecycleImage,
+		Platform:           opts.Platform,
+		Buildpacks:         opts.Buildpacks,
+		PreBuildpacks:      opts.PreBuildpacks,
+		PostBuildpacks:     opts.PostBuildpacks,
+		Extensions:         opts.Extensions,
+		Modules:            moduleDigests,
+		Env:                opts.Env,
+		LifecycleEnv:       opts.LifecycleEnv,
+		Labels:             opts.Labels,
+		Annotations:        opts.Annotations,
+		Build:              opts.ProjectDescriptor.Build,
+		DefaultProcessType: opts.DefaultProcessType,
+		Volumes:            opts.ContainerConfig.Volumes,
+		Network:            opts.ContainerConfig.Network,
+		ExtraHosts:         opts.ContainerConfig.ExtraHosts,
+		SSHAgent:           opts.ContainerConfig.SSHAgent != "",
+		Cache:              opts.Cache,
+		CacheImage:         opts.CacheImage,
+		LayerCompression:   opts.LayerCompression,
+		Workspace:          opts.Workspace,
+		GroupID:            opts.GroupID,
+		UserID:             opts.UserID,
+		Publish:            opts.Publish,
+		Project:            opts.ProjectDescriptor.Project,
+	})
+}
+
+// moduleDigest is the digest of the tar of a buildpack or extension
+func moduleDigest(module buildpack.BuildModule) (string, error) {
+	reader, err := module.Open()
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, reader); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("sha256:%x", h.Sum(nil)), nil
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestSourceDigest(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "SourceDigest", testSourceDigest, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testSourceDigest(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject *Client
+		out     bytes.Buffer
+		server  *httptest.Server
+		tag     name.Tag
+		digest  v1.Hash
+	)
+
+	it.Before(func() {
+		server = httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+		subject = &Client{keychain: authn.DefaultKeychain, logger: logging.NewLogWithWriters(&out, &out)}
+
+		img, err := random.Image(1024, 1)
+		h.AssertNil(t, err)
+		tag, err = name.NewTag(strings.TrimPrefix(server.URL, "http://") + "/some/app:latest")
+		h.AssertNil(t, err)
+		h.AssertNil(t, remote.Write(tag, img))
+		digest, err = img.Digest()
+		h.AssertNil(t, err)
+	})
+
+	it.After(func() {
+		server.Close()
+	})
+
+	when("#tagUnchangedImage", func() {
+		it("tags the published image with the image name of the build and its additional tags", func() {
+			previous := tag.Context().Tag("previous")
+			h.AssertNil(t, remote.Tag(previous, mustGet(t, tag)))
+			other := tag.Context().Registry.Repo("other", "app").Tag("v1")
+
+			h.AssertNil(t, subject.tagUnchangedImage(context.TODO(), previous.Name(), tag.Context().Tag("next").Name(), []string{other.Name()}, true))
+
+			for _, ref := range []name.Reference{tag.Context().Tag("next"), other} {
+				desc, err := remote.Get(ref)
+				h.AssertNil(t, err)
+				h.AssertEq(t, desc.Digest, digest)
+			}
+		})
+
+		it("does nothing without other tags", func() {
+			h.AssertNil(t, subject.tagUnchangedImage(context.TODO(), tag.Name(), tag.Name(), nil, true))
+		})
+	})
+}
+
+func mustGet(t *testing.T, ref name.Reference) *remote.Descriptor {
+	t.Helper()
+	desc, err := remote.Get(ref)
+	h.AssertNil(t, err)
+	return desc
+}
@@ -0,0 +1,38 @@
+package client
+
+import (
+	"runtime"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/sshagent"
+	"github.com/buildpacks/pack/internal/style"
+)
+
+const sshAuthSockEnv = "SSH_AUTH_SOCK"
+
+// sshAgentSocket is the socket of the daemon's host to mount in the containers running buildpacks for them to reach
+// the SSH agent of opts, empty when the agent isn't forwarded. The returned func stops forwarding the agent.
+func (c *Client) sshAgentSocket(opts BuildOptions, remoteDaemon bool, targetOS string) (string, func(), error) {
+	agent := opts.ContainerConfig.SSHAgent
+	switch {
+	case agent == "":
+		return "", func() {}, nil
+	case remoteDaemon:
+		return "", nil, errors.New("the SSH agent can't be forwarded to a daemon reached over SSH, as it runs on another host")
+	case targetOS == "windows":
+		return "", nil, errors.New("the SSH agent can't be forwarded to Windows containers")
+	case runtime.GOOS != "linux":
+		// the named pipes of Windows and the sockets of macOS can't be mounted from the VM of Docker Desktop
+		c.logger.Debugf("Forwarding the SSH agent with the socket %s of Docker Desktop", style.Symbol(sshagent.DockerDesktopSocket))
+		return sshagent.DockerDesktopSocket, func() {}, nil
+	}
+
+	// the socket of the agent is only accessible to its owner, not the user of the builder
+	proxy, err := sshagent.NewProxy(agent)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "forwarding the SSH agent")
+	}
+	c.logger.Debugf("Forwarding the SSH agent %s with the socket %s", style.Symbol(agent), style.Symbol(proxy.Socket))
+	return proxy.Socket, func() { _ = proxy.Close() }, nil
+}
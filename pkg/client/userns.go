@@ -0,0 +1,51 @@
+package client
+
+import (
+	"context"
+	"strings"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/cache"
+)
+
+// usernsRemapped reports whether the daemon remaps user namespaces, as set by the build options or, when they
+// don't say, as reported by the daemon, which rootless Podman always does. Bind-mounted caches aren't remapped by the daemon, so a warning is logged
+// for them.
+func (c *Client) usernsRemapped(ctx context.Context, opts BuildOptions) bool {
+	remapped := false
+	if opts.ContainerConfig.UsernsRemap != nil {
+		remapped = *opts.ContainerConfig.UsernsRemap
+	} else {
+		info, err := c.docker.Info(ctx)
+		if err != nil {
+			c.logger.Debugf("Unable to detect whether the daemon remaps user namespaces: %s", err)
+			return false
+		}
+		rootless := false
+		for _, option := range info.SecurityOptions {
+			if strings.Contains(option, "name=userns") {
+				remapped = true
+			}
+			if strings.Contains(option, "name=rootless") {
+				rootless = true
+			}
+		}
+		if remapped {
+			c.logger.Debug("The daemon remaps user namespaces, restoring the ownership of volumes after copying the app")
+		} else if rootless && c.isPodman(ctx) {
+			// rootless Podman maps the users of the containers to the subordinate ids of the user running it, so
+			// volumes are owned like those of a daemon remapping user namespaces
+			c.logger.Debug("The daemon is rootless Podman, restoring the ownership of volumes after copying the app")
+			remapped = true
+		}
+	}
+
+	if remapped {
+		for _, cacheInfo := range []cache.CacheInfo{opts.Cache.Build, opts.Cache.Launch} {
+			if cacheInfo.Format == cache.CacheBind {
+				c.logger.Warnf("The daemon remaps user namespaces, the bind-mounted cache %s must be writable by the remapped user of the builder", style.Symbol(cacheInfo.Source))
+			}
+		}
+	}
+	return remapped
+}
@@ -0,0 +1,128 @@
+// Package errcode assigns stable identifiers and exit codes to categories of failures,
+// so that automation can react to specific failures without matching on error messages.
+package errcode
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// Code is a stable error identifier of the form PACKxxxx. The first digit denotes the category of the failure.
+type Code string
+
+const (
+	Unknown Code = "PACK0001"
+
+	BuilderNotTrusted      Code = "PACK1001"
+	BuilderIncompatible    Code = "PACK1002"
+	BuilderFetchFailed     Code = "PACK1003"
+	RegistryNotDefined     Code = "PACK2001"
+	RegistryInvalidID      Code = "PACK2002"
+	RegistryEntryMissing   Code = "PACK2003"
+	RegistryAuthRequired   Code = "PACK2004"
+	RegistryEntryYanked    Code = "PACK2005"
+	RegistryDigestMismatch Code = "PACK2006"
+	ExperimentalFeature    Code = "PACK3001"
+	WarningsAsErrors       Code = "PACK3002"
+)
+
+// Exit codes returned by the pack CLI for each category of failure.
+// 1 is used for failures without a more specific category and 2 is reserved for soft errors.
+const (
+	ExitUnknown  = 1
+	ExitBuilder  = 10
+	ExitRegistry = 11
+	ExitUsage    = 12
+)
+
+var names = map[Code]string{
+	Unknown:                "unknown",
+	BuilderNotTrusted:      "builder-not-trusted",
+	BuilderIncompatible:    "builder-incompatible",
+	BuilderFetchFailed:     "builder-fetch-failed",
+	RegistryNotDefined:     "registry-not-defined",
+	RegistryInvalidID:      "registry-invalid-id",
+	RegistryEntryMissing:   "registry-entry-not-found",
+	RegistryAuthRequired:   "registry-auth-required",
+	RegistryEntryYanked:    "registry-entry-yanked",
+	RegistryDigestMismatch: "registry-digest-mismatch",
+	ExperimentalFeature:    "experimental-feature-disabled",
+	WarningsAsErrors:       "warnings-as-errors",
+}
+
+// Name returns a short human-readable identifier for the code, e.g. builder-not-trusted
+func (c Code) Name() string {
+	if name, ok := names[c]; ok {
+		return name
+	}
+	return names[Unknown]
+}
+
+// ExitCode returns the process exit code for the category of the code
+func (c Code) ExitCode() int {
+	if len(c) != len(Unknown) {
+		return ExitUnknown
+	}
+
+	switch c[4] {
+	case '1':
+		return ExitBuilder
+	case '2':
+		return ExitRegistry
+	case '3':
+		return ExitUsage
+	default:
+		return ExitUnknown
+	}
+}
+
+// Coder is implemented by errors that carry an error code
+type Coder interface {
+	ErrorCode() Code
+}
+
+// Error is an error annotated with a Code
+type Error struct {
+	Code Code
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Cause allows github.com/pkg/errors.Cause to find the underlying error
+func (e *Error) Cause() error {
+	return e.Err
+}
+
+func (e *Error) ErrorCode() Code {
+	return e.Code
+}
+
+// Wrap annotates err with code. It returns nil if err is nil.
+func Wrap(code Code, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Code: code, Err: err}
+}
+
+// Errorf returns a new error annotated with code
+func Errorf(code Code, format string, args ...interface{}) error {
+	return &Error{Code: code, Err: fmt.Errorf(format, args...)}
+}
+
+// Of returns the code of the first error in err's chain that carries one, or Unknown
+func Of(err error) Code {
+	var coder Coder
+	if errors.As(err, &coder) {
+		return coder.ErrorCode()
+	}
+	return Unknown
+}
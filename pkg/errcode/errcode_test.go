@@ -0,0 +1,62 @@
+package errcode_test
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/errcode"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestErrcode(t *testing.T) {
+	spec.Run(t, "Errcode", testErrcode, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testErrcode(t *testing.T, when spec.G, it spec.S) {
+	when("#Of", func() {
+		it("returns the code of a wrapped error", func() {
+			err := errors.Wrap(errcode.Errorf(errcode.RegistryEntryMissing, "no entries for buildpack: %s", "example/foo"), "locating buildpack")
+			h.AssertEq(t, errcode.Of(err), errcode.RegistryEntryMissing)
+			h.AssertEq(t, err.Error(), "locating buildpack: no entries for buildpack: example/foo")
+		})
+
+		it("returns Unknown for errors without a code", func() {
+			h.AssertEq(t, errcode.Of(errors.New("some error")), errcode.Unknown)
+		})
+
+		it("keeps the underlying error as the cause", func() {
+			cause := errors.New("some error")
+			h.AssertTrue(t, errors.Cause(errcode.Wrap(errcode.BuilderNotTrusted, cause)) == cause)
+		})
+	})
+
+	when("#Wrap", func() {
+		it("returns nil for a nil error", func() {
+			h.AssertNil(t, errcode.Wrap(errcode.BuilderNotTrusted, nil))
+		})
+	})
+
+	when("#ExitCode", func() {
+		it("is derived from the category of the code", func() {
+			h.AssertEq(t, errcode.BuilderNotTrusted.ExitCode(), errcode.ExitBuilder)
+			h.AssertEq(t, errcode.RegistryEntryMissing.ExitCode(), errcode.ExitRegistry)
+			h.AssertEq(t, errcode.ExperimentalFeature.ExitCode(), errcode.ExitUsage)
+			h.AssertEq(t, errcode.Unknown.ExitCode(), errcode.ExitUnknown)
+			h.AssertEq(t, errcode.Code("bogus").ExitCode(), errcode.ExitUnknown)
+		})
+	})
+
+	when("#Name", func() {
+		it("returns the identifier of the code", func() {
+			h.AssertEq(t, errcode.BuilderNotTrusted.Name(), "builder-not-trusted")
+			h.AssertEq(t, errcode.RegistryEntryMissing.Name(), "registry-entry-not-found")
+			h.AssertEq(t, errcode.RegistryAuthRequired.Name(), "registry-auth-required")
+			h.AssertEq(t, errcode.RegistryEntryYanked.Name(), "registry-entry-yanked")
+			h.AssertEq(t, errcode.RegistryDigestMismatch.Name(), "registry-digest-mismatch")
+			h.AssertEq(t, errcode.Code("bogus").Name(), "unknown")
+		})
+	})
+}
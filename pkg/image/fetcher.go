@@ -17,14 +17,17 @@ import (
 	"github.com/buildpacks/lifecycle/auth"
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/client"
-	"github.com/docker/docker/pkg/jsonmessage"
 	"github.com/google/go-containerregistry/pkg/authn"
+	gname "github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	ggcrremote "github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/pkg/errors"
 
 	pname "github.com/buildpacks/pack/internal/name"
+	"github.com/buildpacks/pack/internal/progress"
 	"github.com/buildpacks/pack/internal/style"
-	"github.com/buildpacks/pack/internal/term"
 	"github.com/buildpacks/pack/pkg/dist"
+	"github.com/buildpacks/pack/pkg/errcode"
 	"github.com/buildpacks/pack/pkg/logging"
 )
 
@@ -67,6 +70,10 @@ type FetchOptions struct {
 	Target       *dist.Target
 	PullPolicy   PullPolicy
 	LayoutOption LayoutOption
+
+	// Digest is the digest the image must have, e.g. the digest a buildpack registry resolved a buildpack to. The image
+	// fetched from a multi-platform image has the digest of its platform, so the image index must have the digest.
+	Digest string
 }
 
 func NewFetcher(logger logging.Logger, docker DockerClient, opts ...FetcherOption) *Fetcher {
@@ -91,6 +98,19 @@ func (f *Fetcher) Fetch(ctx context.Context, name string, options FetchOptions)
 		return nil, err
 	}
 
+	img, err := f.fetch(ctx, name, options)
+	if err != nil || options.Digest == "" {
+		return img, err
+	}
+
+	if err := f.verifyDigest(ctx, name, img, options); err != nil {
+		return nil, err
+	}
+	return img, nil
+}
+
+func (f *Fetcher) fetch(ctx context.Context, name string, options FetchOptions) (imgutil.Image, error) {
+	var err error
 	if (options.LayoutOption != LayoutOption{}) {
 		return f.fetchLayoutImage(name, options.LayoutOption)
 	}
@@ -102,10 +122,16 @@ func (f *Fetcher) Fetch(ctx context.Context, name string, options FetchOptions)
 	switch options.PullPolicy {
 	case PullNever:
 		img, err := f.fetchDaemonImage(name)
+		if err == nil && !matchesTarget(img, options.Target) {
+			return nil, errors.Errorf("image %s on the daemon is for platform %s, not the requested platform %s", style.Symbol(name), style.Symbol(imagePlatform(img)), style.Symbol(options.Target.ValuesAsPlatform()))
+		}
 		return img, err
 	case PullIfNotPresent:
 		img, err := f.fetchDaemonImage(name)
-		if err == nil || !errors.Is(err, ErrNotFound) {
+		if err == nil && !matchesTarget(img, options.Target) {
+			// e.g. the amd64 image of the host is on the daemon while building for arm64 with --platform
+			f.logger.Debugf("Image %s on the daemon is for platform %s", style.Symbol(name), style.Symbol(imagePlatform(img)))
+		} else if err == nil || !errors.Is(err, ErrNotFound) {
 			return img, err
 		}
 	}
@@ -136,6 +162,118 @@ func (f *Fetcher) Fetch(ctx context.Context, name string, options FetchOptions)
 	return f.fetchDaemonImage(name)
 }
 
+// matchesTarget is true when the os, architecture and variant of img are those of target, ignoring what either leaves empty
+func matchesTarget(img imgutil.Image, target *dist.Target) bool {
+	if target == nil {
+		return true
+	}
+	os, err := img.OS()
+	if err != nil {
+		return true
+	}
+	arch, err := img.Architecture()
+	if err != nil {
+		return true
+	}
+	variant, err := img.Variant()
+	if err != nil {
+		return true
+	}
+	return matches(os, target.OS) && matches(arch, target.Arch) && matches(variant, target.ArchVariant)
+}
+
+func matches(actual, expected string) bool {
+	return actual == "" || expected == "" || actual == expected
+}
+
+// imagePlatform is the platform of img, e.g. linux/arm64/v8
+func imagePlatform(img imgutil.Image) string {
+	os, _ := img.OS()
+	arch, _ := img.Architecture()
+	variant, _ := img.Variant()
+	target := dist.Target{OS: os, Arch: arch, ArchVariant: variant}
+	return target.ValuesAsPlatform()
+}
+
+// verifyDigest fails when the image fetched as name doesn't have the digest of options, unless it is the image of a
+// platform of the image index that has the digest
+func (f *Fetcher) verifyDigest(ctx context.Context, name string, img imgutil.Image, options FetchOptions) error {
+	expected, err := v1.NewHash(options.Digest)
+	if err != nil {
+		return errors.Wrapf(err, "parsing digest %s", style.Symbol(options.Digest))
+	}
+
+	digests, err := f.imageDigests(ctx, name, img, options)
+	if err != nil {
+		return errors.Wrapf(err, "reading digest of image %s", style.Symbol(name))
+	}
+	for _, digest := range digests {
+		if digest == expected.String() {
+			return nil
+		}
+	}
+
+	// the daemon records the digest of the index of the images it pulled from multi-platform images, unlike registries
+	if !options.Daemon && f.indexContains(ctx, name, expected, digests) {
+		return nil
+	}
+
+	return errcode.Errorf(errcode.RegistryDigestMismatch, "image %s has digest %s rather than the expected %s",
+		style.Symbol(name), style.Symbol(strings.Join(digests, ", ")), style.Symbol(expected.String()))
+}
+
+// imageDigests are the digests of the manifest of img: of the manifests it was pulled by for daemon images
+func (f *Fetcher) imageDigests(ctx context.Context, name string, img imgutil.Image, options FetchOptions) ([]string, error) {
+	if options.Daemon && (options.LayoutOption == LayoutOption{}) {
+		inspect, _, err := f.docker.ImageInspectWithRaw(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+
+		var digests []string
+		for _, repoDigest := range inspect.RepoDigests {
+			if _, digest, found := strings.Cut(repoDigest, "@"); found {
+				digests = append(digests, digest)
+			}
+		}
+		return digests, nil
+	}
+
+	identifier, err := img.Identifier()
+	if err != nil {
+		return nil, err
+	}
+	id := identifier.String()
+	return []string{id[strings.LastIndex(id, "@")+1:]}, nil
+}
+
+// indexContains is true when the repository of name has an image index with the digest expected, which lists an image
+// with one of digests
+func (f *Fetcher) indexContains(ctx context.Context, name string, expected v1.Hash, digests []string) bool {
+	ref, err := gname.ParseReference(name, gname.WeakValidation)
+	if err != nil {
+		return false
+	}
+
+	index, err := ggcrremote.Index(ref.Context().Digest(expected.String()), ggcrremote.WithAuthFromKeychain(f.keychain), ggcrremote.WithContext(ctx))
+	if err != nil {
+		return false
+	}
+	manifest, err := index.IndexManifest()
+	if err != nil {
+		return false
+	}
+
+	for _, descriptor := range manifest.Manifests {
+		for _, digest := range digests {
+			if descriptor.Digest.String() == digest {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (f *Fetcher) CheckReadAccess(repo string, options FetchOptions) bool {
 	if !options.Daemon || options.PullPolicy == PullAlways {
 		return f.checkRemoteReadAccess(repo)
@@ -252,11 +390,8 @@ func (f *Fetcher) pullImage(ctx context.Context, imageID string, platform string
 		return err
 	}
 
-	writer := logging.GetWriterForLevel(f.logger, logging.InfoLevel)
-	termFd, isTerm := term.IsTerminal(writer)
-
-	err = jsonmessage.DisplayJSONMessagesStream(rc, &colorizedWriter{writer}, termFd, isTerm, nil)
-	if err != nil {
+	writer, isTerm, displayOpts := progress.Output(logging.GetWriterForLevel(f.logger, logging.InfoLevel))
+	if err = progress.NewPullDisplay(writer, isTerm, imageID, displayOpts...).Display(rc); err != nil {
 		return err
 	}
 
@@ -280,28 +415,3 @@ func (f *Fetcher) registryAuth(ref string) (string, error) {
 
 	return base64.StdEncoding.EncodeToString(dataJSON), nil
 }
-
-type colorizedWriter struct {
-	writer io.Writer
-}
-
-type colorFunc = func(string, ...interface{}) string
-
-func (w *colorizedWriter) Write(p []byte) (n int, err error) {
-	msg := string(p)
-	colorizers := map[string]colorFunc{
-		"Waiting":           style.Waiting,
-		"Pulling fs layer":  style.Waiting,
-		"Downloading":       style.Working,
-		"Download complete": style.Working,
-		"Extracting":        style.Working,
-		"Pull complete":     style.Complete,
-		"Already exists":    style.Complete,
-		"=":                 style.ProgressBar,
-		">":                 style.ProgressBar,
-	}
-	for pattern, colorize := range colorizers {
-		msg = strings.ReplaceAll(msg, pattern, colorize(pattern))
-	}
-	return w.writer.Write([]byte(msg))
-}
@@ -4,9 +4,13 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"log"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/buildpacks/imgutil"
@@ -16,12 +20,20 @@ import (
 	"github.com/docker/docker/client"
 	"github.com/golang/mock/gomock"
 	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	ggcrremote "github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/heroku/color"
 	"github.com/pkg/errors"
 	"github.com/sclevine/spec"
 	"github.com/sclevine/spec/report"
 
 	"github.com/buildpacks/pack/pkg/dist"
+	"github.com/buildpacks/pack/pkg/errcode"
 	"github.com/buildpacks/pack/pkg/image"
 	"github.com/buildpacks/pack/pkg/logging"
 	"github.com/buildpacks/pack/pkg/testmocks"
@@ -180,6 +192,13 @@ func testFetcher(t *testing.T, when spec.G, it spec.S) {
 						_, err := imageFetcher.Fetch(context.TODO(), repoName, image.FetchOptions{Daemon: true, PullPolicy: image.PullNever})
 						h.AssertNil(t, err)
 					})
+
+					when("the local image is for another platform", func() {
+						it("returns an error", func() {
+							_, err := imageFetcher.Fetch(context.TODO(), repoName, image.FetchOptions{Daemon: true, PullPolicy: image.PullNever, Target: &dist.Target{OS: "linux", Arch: "some-other-arch"}})
+							h.AssertError(t, err, "not the requested platform 'linux/some-other-arch'")
+						})
+					})
 				})
 
 				when("there is no local image", func() {
@@ -338,6 +357,14 @@ func testFetcher(t *testing.T, when spec.G, it spec.S) {
 							h.AssertEq(t, fetchedImgLabel, localImgLabel)
 							h.AssertNotEq(t, fetchedImgLabel, remoteImgLabel)
 						})
+
+						when("the local image is for another platform", func() {
+							it("pulls the image for the platform", func() {
+								_, err := imageFetcher.Fetch(context.TODO(), repoName, image.FetchOptions{Daemon: true, PullPolicy: image.PullIfNotPresent, Target: &dist.Target{OS: "linux", Arch: "some-other-arch"}})
+								h.AssertNotNil(t, err)
+								h.AssertContains(t, outBuf.String(), fmt.Sprintf("Pulling image '%s' with platform 'linux/some-other-arch'", repoName))
+							})
+						})
 					})
 
 					when("there is no local image", func() {
@@ -557,3 +584,71 @@ func testFetcher(t *testing.T, when spec.G, it spec.S) {
 		})
 	})
 }
+
+func TestFetcherDigest(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "FetcherDigest", testFetcherDigest, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testFetcherDigest(t *testing.T, when spec.G, it spec.S) {
+	var (
+		server       *httptest.Server
+		imageFetcher *image.Fetcher
+		repo         name.Repository
+		imageDigest  v1.Hash
+	)
+
+	it.Before(func() {
+		server = httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+		imageFetcher = image.NewFetcher(logging.NewSimpleLogger(io.Discard), nil)
+
+		var err error
+		repo, err = name.NewRepository(strings.TrimPrefix(server.URL, "http://") + "/some/package")
+		h.AssertNil(t, err)
+
+		img, err := random.Image(1024, 1)
+		h.AssertNil(t, err)
+		h.AssertNil(t, ggcrremote.Write(repo.Tag("latest"), img))
+		imageDigest, err = img.Digest()
+		h.AssertNil(t, err)
+	})
+
+	it.After(func() {
+		server.Close()
+	})
+
+	it("fetches the image with the digest", func() {
+		_, err := imageFetcher.Fetch(context.TODO(), repo.Digest(imageDigest.String()).Name(), image.FetchOptions{Digest: imageDigest.String()})
+		h.AssertNil(t, err)
+	})
+
+	it("fails when the image has another digest", func() {
+		other, err := random.Image(1024, 1)
+		h.AssertNil(t, err)
+		otherDigest, err := other.Digest()
+		h.AssertNil(t, err)
+
+		_, err = imageFetcher.Fetch(context.TODO(), repo.Tag("latest").Name(), image.FetchOptions{Digest: otherDigest.String()})
+		h.AssertError(t, err, fmt.Sprintf("has digest '%s' rather than the expected '%s'", imageDigest, otherDigest))
+		h.AssertEq(t, errcode.Of(err), errcode.RegistryDigestMismatch)
+	})
+
+	it("fetches the image of a platform of the image index with the digest", func() {
+		img, err := random.Image(1024, 1)
+		h.AssertNil(t, err)
+		index := mutate.AppendManifests(empty.Index, mutate.IndexAddendum{
+			Add:        img,
+			Descriptor: v1.Descriptor{Platform: &v1.Platform{OS: "linux", Architecture: "amd64"}},
+		})
+		h.AssertNil(t, ggcrremote.WriteIndex(repo.Tag("multi-platform"), index))
+		indexDigest, err := index.Digest()
+		h.AssertNil(t, err)
+
+		_, err = imageFetcher.Fetch(context.TODO(), repo.Digest(indexDigest.String()).Name(), image.FetchOptions{
+			Target: &dist.Target{OS: "linux", Arch: "amd64"},
+			Digest: indexDigest.String(),
+		})
+		h.AssertNil(t, err)
+	})
+}
@@ -2,6 +2,7 @@
 package logging
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"regexp"
@@ -30,6 +31,10 @@ const (
 
 var colorCodeMatcher = regexp.MustCompile(`\x1b\[[0-9;]*m`)
 
+// warningLineMatcher matches the warnings written as lines of output, such as those of the lifecycle phases
+// (e.g. "[detector] Warning: ...")
+var warningLineMatcher = regexp.MustCompile(`(?m)^(?:\[[\w-]+\] )?Warning: `)
+
 var _ Logger = (*LogWithWriters)(nil)
 
 // LogWithWriters is a logger used with the pack CLI, allowing users to print logs for various levels, including Info, Debug and Error
@@ -40,6 +45,14 @@ type LogWithWriters struct {
 	clock    func() time.Time
 	out      io.Writer
 	errOut   io.Writer
+	logFile  io.Writer
+	warnings int
+	// parent, when set, counts the warnings of the logger as well
+	parent warningReporter
+}
+
+type warningReporter interface {
+	addWarnings(n int)
 }
 
 // NewLogWithWriters creates a logger to be used with pack CLI.
@@ -76,35 +89,95 @@ func WithVerbose() func(writers *LogWithWriters) {
 	}
 }
 
+// WithParent is an option used to initialize a LogWithWriters whose warnings are also counted by parent, such as the
+// logger of one of the builds of a command, whose warnings count towards those of the command
+func WithParent(parent Logger) func(writers *LogWithWriters) {
+	return func(logger *LogWithWriters) {
+		if p, ok := parent.(warningReporter); ok {
+			logger.parent = p
+		}
+	}
+}
+
 // HandleLog handles log events, printing entries appropriately
 func (lw *LogWithWriters) HandleLog(e *log.Entry) error {
 	lw.Lock()
 	defer lw.Unlock()
 
-	writer := lw.WriterForLevel(Level(e.Level))
+	if e.Level == log.WarnLevel {
+		lw.countWarnings(1)
+	}
+
+	writer := lw.writerForLevel(Level(e.Level), false)
 	_, err := fmt.Fprint(writer, appendMissingLineFeed(fmt.Sprintf("%s%s", formatLevel(e.Level), e.Message)))
 
 	return err
 }
 
-// WriterForLevel returns a Writer for the given Level
+// WriterForLevel returns a Writer for the given Level.
+//
+// The warnings written to it as lines of output are counted along with the logged ones.
 func (lw *LogWithWriters) WriterForLevel(level Level) io.Writer {
+	return lw.writerForLevel(level, true)
+}
+
+func (lw *LogWithWriters) writerForLevel(level Level, countWarnings bool) io.Writer {
 	if lw.Level > log.Level(level) {
 		return io.Discard
 	}
 
-	if level == ErrorLevel {
-		return newLogWriter(lw.errOut, lw.clock, lw.wantTime)
+	out := lw.out
+	// In quiet mode, warnings are sent to the error writer so that the standard writer only
+	// carries the final output of a command (e.g. an image reference) and can be piped safely.
+	if level == ErrorLevel || (level == WarnLevel && lw.Level == quietLevel) {
+		out = lw.errOut
+	}
+
+	writer := lw.newLogWriter(out, lw.wantTime)
+	if countWarnings {
+		writer.onWarnings = lw.addWarnings
 	}
+	return writer
+}
+
+func (lw *LogWithWriters) addWarnings(n int) {
+	lw.Lock()
+	defer lw.Unlock()
 
-	return newLogWriter(lw.out, lw.clock, lw.wantTime)
+	lw.countWarnings(n)
+}
+
+func (lw *LogWithWriters) countWarnings(n int) {
+	lw.warnings += n
+	if lw.parent != nil {
+		lw.parent.addWarnings(n)
+	}
 }
 
 // Writer returns the base Writer for the LogWithWriters
 func (lw *LogWithWriters) Writer() io.Writer {
+	if lw.logFile != nil {
+		return lw.newLogWriter(lw.out, false)
+	}
 	return lw.out
 }
 
+// WantLogFile additionally writes all output, timestamped and without colors, to w
+func (lw *LogWithWriters) WantLogFile(w io.Writer) {
+	lw.logFile = w
+}
+
+// LogFile returns the writer set by WantLogFile, if any
+func (lw *LogWithWriters) LogFile() io.Writer {
+	return lw.logFile
+}
+
+func (lw *LogWithWriters) newLogWriter(out io.Writer, wantTime bool) *logWriter {
+	writer := newLogWriter(out, lw.clock, wantTime)
+	writer.logFile = lw.logFile
+	return writer
+}
+
 // WantTime turns timestamps on in log entries
 func (lw *LogWithWriters) WantTime(f bool) {
 	lw.wantTime = f
@@ -124,6 +197,14 @@ func (lw *LogWithWriters) WantVerbose(f bool) {
 	}
 }
 
+// WarningCount returns the number of warnings logged so far, including those written to the writers for a level
+func (lw *LogWithWriters) WarningCount() int {
+	lw.Lock()
+	defer lw.Unlock()
+
+	return lw.warnings
+}
+
 // IsVerbose returns whether verbose logging is on
 func (lw *LogWithWriters) IsVerbose() bool {
 	return lw.Level == log.DebugLevel
@@ -156,6 +237,13 @@ type logWriter struct {
 	clock       func() time.Time
 	wantTime    bool
 	wantNoColor bool
+	logFile     io.Writer
+	// onWarnings, when set, is called with the number of warnings in the lines written
+	onWarnings func(int)
+	// midLine is whether the last write didn't end its line, the rest of which is not stamped again
+	midLine bool
+	// pending is the start of the line the warnings are looked for in once it ends
+	pending []byte
 }
 
 func newLogWriter(writer io.Writer, clock func() time.Time, wantTime bool) *logWriter {
@@ -168,7 +256,8 @@ func newLogWriter(writer io.Writer, clock func() time.Time, wantTime bool) *logW
 	}
 }
 
-// Write writes a message prepended by the time to the set io.Writer
+// Write writes a message to the set io.Writer, each of its lines prepended by the time. A line written over several
+// calls is prepended once, when it starts.
 func (lw *logWriter) Write(buf []byte) (n int, err error) {
 	lw.Lock()
 	defer lw.Unlock()
@@ -177,16 +266,51 @@ func (lw *logWriter) Write(buf []byte) (n int, err error) {
 	if lw.wantNoColor {
 		buf = stripColor(buf)
 	}
-
-	prefix := ""
-	if lw.wantTime {
-		prefix = fmt.Sprintf("%s ", lw.clock().Format(timeFmt))
+	lw.countWarnings(buf)
+
+	var out, logFile bytes.Buffer
+	for len(buf) > 0 {
+		end := bytes.IndexByte(buf, lineFeed) + 1
+		if end == 0 {
+			end = len(buf)
+		}
+		line := buf[:end]
+		buf = buf[end:]
+
+		if !lw.midLine {
+			stamp := lw.clock().Format(timeFmt) + " "
+			if lw.wantTime {
+				out.WriteString(stamp)
+			}
+			logFile.WriteString(stamp)
+		}
+		out.Write(line)
+		logFile.Write(stripColor(line))
+		lw.midLine = line[len(line)-1] != lineFeed
 	}
 
-	_, err = fmt.Fprintf(lw.out, "%s%s", prefix, buf)
+	_, err = lw.out.Write(out.Bytes())
+	if lw.logFile != nil {
+		// the log file is best effort, failing to write to it must not fail the command
+		_, _ = lw.logFile.Write(logFile.Bytes())
+	}
 	return length, err
 }
 
+// countWarnings reports the warnings in the lines ended by buf
+func (lw *logWriter) countWarnings(buf []byte) {
+	if lw.onWarnings == nil {
+		return
+	}
+
+	lines := append(lw.pending, stripColor(buf)...)
+	end := bytes.LastIndexByte(lines, lineFeed) + 1
+	lw.pending = append([]byte(nil), lines[end:]...)
+	if n := len(warningLineMatcher.FindAll(lines[:end], -1)); n > 0 {
+		lw.onWarnings(n)
+	}
+}
+
 // Writer returns the base Writer for the logWriter
 func (lw *logWriter) Writer() io.Writer {
 	return lw.out
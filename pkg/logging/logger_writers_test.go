@@ -1,6 +1,7 @@
 package logging_test
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"testing"
@@ -116,6 +117,14 @@ func testLogWithWriters(t *testing.T, when spec.G, it spec.S) {
 				// The writer doesn't prepend the level
 				h.AssertEq(t, fErr(), "2019/05/15 01:01:01.000000 test\n")
 			})
+
+			it("time is logged once per line", func() {
+				logger.WantTime(true)
+				writer := logger.WriterForLevel(logging.InfoLevel)
+				writer.Write([]byte("first\nsec"))
+				writer.Write([]byte("ond\nthird\n"))
+				h.AssertEq(t, fOut(), testTime+" first\n"+testTime+" second\n"+testTime+" third\n")
+			})
 		})
 	})
 
@@ -145,11 +154,12 @@ func testLogWithWriters(t *testing.T, when spec.G, it spec.S) {
 			h.AssertNotContains(t, output, "infof\n")
 		})
 
-		it("logs warnings to standard writer", func() {
+		it("logs warnings to error writer", func() {
 			logger.Warn("warn_")
 			logger.Warnf("warnf")
 
-			output := fOut()
+			h.AssertNotContains(t, fOut(), "warn_")
+			output := fErr()
 			h.AssertContains(t, output, "warn_\n")
 			h.AssertContains(t, output, "warnf\n")
 		})
@@ -210,6 +220,90 @@ func testLogWithWriters(t *testing.T, when spec.G, it spec.S) {
 		})
 	})
 
+	when("a log file is wanted", func() {
+		var logFile bytes.Buffer
+
+		it.Before(func() {
+			logFile.Reset()
+			logger.WantLogFile(&logFile)
+		})
+
+		it("writes all output to the log file with timestamps and without colors", func() {
+			logger.Info(color.HiBlueString("info_"))
+			logger.Error("error_")
+			fmt.Fprintln(logger.Writer(), "direct_")
+
+			output := fOut()
+			h.AssertContains(t, output, "info_")
+			h.AssertContains(t, output, "direct_")
+			h.AssertContains(t, fErr(), "error_")
+			h.AssertEq(t, logFile.String(), testTime+" info_\n"+testTime+" ERROR: error_\n"+testTime+" direct_\n")
+		})
+
+		it("timestamps each line written to the log file", func() {
+			writer := logger.WriterForLevel(logging.InfoLevel)
+			writer.Write([]byte("[detector] first\n[detector] sec"))
+			writer.Write([]byte("ond\n"))
+
+			h.AssertEq(t, logFile.String(), testTime+" [detector] first\n"+testTime+" [detector] second\n")
+		})
+
+		it("does not write messages below the log level", func() {
+			logger.Debug("debug_")
+
+			h.AssertEq(t, logFile.String(), "")
+		})
+	})
+
+	it("counts the warnings logged", func() {
+		logger.Info("info_")
+		logger.Warn("warn_")
+		logger.Warnf("warnf")
+		logger.Error("error_")
+
+		h.AssertEq(t, logger.WarningCount(), 2)
+		h.AssertEq(t, logging.WarningCount(logger), 2)
+	})
+
+	it("counts the warnings written as lines of output", func() {
+		fmt.Fprint(logger.WriterForLevel(logging.InfoLevel), "[detector] Warning: platform API 0.9 is deprecated\n[detector] ======== Results ========\n")
+		fmt.Fprintln(logger.WriterForLevel(logging.ErrorLevel), color.YellowString("Warning: ")+"the stack mixins don't match")
+		fmt.Fprintln(logger.WriterForLevel(logging.InfoLevel), "a line mentioning a Warning: that isn't one")
+
+		h.AssertEq(t, logger.WarningCount(), 2)
+	})
+
+	it("counts the warnings of lines written over several writes once", func() {
+		writer := logger.WriterForLevel(logging.InfoLevel)
+		fmt.Fprint(writer, "[detector] Warn")
+		fmt.Fprint(writer, "ing: platform API 0.9 is deprecated\n")
+
+		h.AssertEq(t, logger.WarningCount(), 1)
+	})
+
+	when("the logger has a parent", func() {
+		var child *logging.LogWithWriters
+
+		it.Before(func() {
+			child = logging.NewLogWithWriters(logging.GetUncountedWriterForLevel(logger, logging.InfoLevel), logging.GetUncountedWriterForLevel(logger, logging.ErrorLevel), logging.WithParent(logger))
+		})
+
+		it("counts its warnings towards those of the parent", func() {
+			child.Warn("warn_")
+			fmt.Fprintln(child.WriterForLevel(logging.InfoLevel), "[detector] Warning: platform API 0.9 is deprecated")
+			logger.Warn("warn_")
+
+			h.AssertEq(t, child.WarningCount(), 2)
+			h.AssertEq(t, logger.WarningCount(), 3)
+		})
+
+		it("counts its warnings once when writing to the parent", func() {
+			fmt.Fprintln(child.WriterForLevel(logging.InfoLevel), "Warning: the stack mixins don't match")
+
+			h.AssertEq(t, logger.WarningCount(), 1)
+		})
+	})
+
 	it("will convert an empty string to a line feed", func() {
 		logger.Info("")
 		expected := "\n"
@@ -50,6 +50,32 @@ func GetWriterForLevel(logger Logger, level Level) io.Writer {
 	return logger.Writer()
 }
 
+// GetUncountedWriterForLevel retrieves the appropriate Writer for the log level provided, like GetWriterForLevel,
+// without counting the warnings written to it. It is meant for the output of a logger created WithParent logger,
+// which counts its own warnings.
+func GetUncountedWriterForLevel(logger Logger, level Level) io.Writer {
+	if lw, ok := logger.(*LogWithWriters); ok {
+		return lw.writerForLevel(level, false)
+	}
+
+	return GetWriterForLevel(logger, level)
+}
+
+type warningCounter interface {
+	WarningCount() int
+}
+
+// WarningCount returns the number of warnings logged so far, or 0 if the logger doesn't keep count.
+//
+// See warningCounter
+func WarningCount(logger Logger) int {
+	if c, ok := logger.(warningCounter); ok {
+		return c.WarningCount()
+	}
+
+	return 0
+}
+
 // IsQuiet defines whether a pack logger is set to quiet mode
 func IsQuiet(logger Logger) bool {
 	if writer := GetWriterForLevel(logger, InfoLevel); writer == io.Discard {
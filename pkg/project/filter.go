@@ -0,0 +1,140 @@
+package project
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/project/types"
+)
+
+// FileFilter returns a function reporting whether a path relative to the app directory should be added to the app
+// image, based on the include or exclude patterns of build. It returns nil if neither are defined.
+//
+// Patterns follow .gitignore syntax:
+//   - blank lines and lines starting with '#' are ignored
+//   - a leading '!' negates a pattern, and the last pattern matching a path wins
+//   - a pattern containing a '/' (other than a trailing one) is anchored to the app directory, otherwise it matches
+//     a file or directory name at any depth
+//   - '*', '?' and '[...]' match within a single path segment, while '**' matches any number of directories
+//   - a pattern matching a directory also matches everything inside it
+//
+// As with .gitignore, wildcards match dotfiles too (e.g. '*.pem' matches '.secret.pem'), which are kept or left out
+// explicitly with patterns naming them (e.g. '!.env').
+func FileFilter(build types.Build) (func(string) bool, error) {
+	lines, exclude := build.Include, false
+	if len(build.Exclude) > 0 {
+		lines, exclude = build.Exclude, true
+	}
+	if len(lines) == 0 {
+		return nil, nil
+	}
+
+	patterns, err := compilePatterns(lines)
+	if err != nil {
+		return nil, err
+	}
+	return func(fileName string) bool {
+		return patterns.matches(filepath.ToSlash(fileName)) != exclude
+	}, nil
+}
+
+type pattern struct {
+	segments []string
+	negate   bool
+	anchored bool
+}
+
+type patternList []pattern
+
+func compilePatterns(lines []string) (patternList, error) {
+	var patterns patternList
+	for _, line := range lines {
+		p, ok := compilePattern(line)
+		if !ok {
+			continue
+		}
+		for _, segment := range p.segments {
+			if _, err := path.Match(segment, ""); err != nil {
+				return nil, errors.Wrapf(err, "invalid pattern %s", style.Symbol(line))
+			}
+		}
+		patterns = append(patterns, p)
+	}
+	return patterns, nil
+}
+
+func compilePattern(line string) (pattern, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return pattern{}, false
+	}
+
+	var p pattern
+	if strings.HasPrefix(line, "!") {
+		p.negate = true
+		line = line[1:]
+	}
+
+	line = strings.TrimSuffix(line, "/")
+	p.anchored = strings.Contains(line, "/") || line == "**"
+	line = strings.TrimPrefix(line, "/")
+	if line == "" {
+		return pattern{}, false
+	}
+	p.segments = strings.Split(line, "/")
+	return p, true
+}
+
+// matches reports whether the last pattern matching the path, or one of its parent directories, is not negated
+func (l patternList) matches(name string) bool {
+	segments := strings.Split(strings.TrimPrefix(name, "./"), "/")
+	matched := false
+	for _, p := range l {
+		if p.matches(segments) {
+			matched = !p.negate
+		}
+	}
+	return matched
+}
+
+func (p pattern) matches(segments []string) bool {
+	for i := 1; i <= len(segments); i++ {
+		if p.anchored {
+			if matchSegments(p.segments, segments[:i]) {
+				return true
+			}
+		} else if matchSegment(p.segments[0], segments[i-1]) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchSegments(patterns, names []string) bool {
+	if len(patterns) == 0 {
+		return len(names) == 0
+	}
+
+	if patterns[0] == "**" {
+		for i := 0; i <= len(names); i++ {
+			if matchSegments(patterns[1:], names[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(names) == 0 || !matchSegment(patterns[0], names[0]) {
+		return false
+	}
+	return matchSegments(patterns[1:], names[1:])
+}
+
+func matchSegment(pattern, name string) bool {
+	matched, _ := path.Match(pattern, name)
+	return matched
+}
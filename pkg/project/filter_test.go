@@ -0,0 +1,136 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/project/types"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestFileFilter(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "FileFilter", testFileFilter, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testFileFilter(t *testing.T, when spec.G, it spec.S) {
+	assertFiltered := func(filter func(string) bool, included []string, excluded []string) {
+		t.Helper()
+		for _, name := range included {
+			h.AssertTrue(t, filter(name))
+		}
+		for _, name := range excluded {
+			h.AssertFalse(t, filter(name))
+		}
+	}
+
+	when("#FileFilter", func() {
+		it("returns nil without include or exclude patterns", func() {
+			filter, err := FileFilter(types.Build{})
+			h.AssertNil(t, err)
+			h.AssertTrue(t, filter == nil)
+		})
+
+		it("errors on an invalid pattern", func() {
+			_, err := FileFilter(types.Build{Exclude: []string{"[z-a"}})
+			h.AssertError(t, err, "invalid pattern '[z-a'")
+		})
+
+		when("exclude", func() {
+			it("matches unanchored patterns at any depth", func() {
+				filter, err := FileFilter(types.Build{Exclude: []string{"*.log", "node_modules"}})
+				h.AssertNil(t, err)
+
+				assertFiltered(filter,
+					[]string{"main.go", "logs/main.go"},
+					[]string{"app.log", "logs/app.log", "node_modules", "node_modules/pkg/index.js", "web/node_modules/pkg/index.js"},
+				)
+			})
+
+			it("anchors patterns containing a slash", func() {
+				filter, err := FileFilter(types.Build{Exclude: []string{"/build", "docs/*.md"}})
+				h.AssertNil(t, err)
+
+				assertFiltered(filter,
+					[]string{"src/build/main.go", "docs/api/README.md", "README.md"},
+					[]string{"build", "build/out.bin", "docs/README.md"},
+				)
+			})
+
+			it("matches any number of directories with **", func() {
+				filter, err := FileFilter(types.Build{Exclude: []string{"**/testdata", "src/**/*.tmp"}})
+				h.AssertNil(t, err)
+
+				assertFiltered(filter,
+					[]string{"src/main.go", "main.tmp"},
+					[]string{"testdata/fixture.json", "pkg/parser/testdata/fixture.json", "src/a.tmp", "src/a/b/c.tmp"},
+				)
+			})
+
+			it("re-includes negated paths, with the last matching pattern winning", func() {
+				filter, err := FileFilter(types.Build{Exclude: []string{"config/", "!config/app.yml", "config/app.yml.bak"}})
+				h.AssertNil(t, err)
+
+				assertFiltered(filter,
+					[]string{"config/app.yml"},
+					[]string{"config/secrets.yml", "config/app.yml.bak"},
+				)
+			})
+
+			// the dotfiles are matched by wildcards as they were by go-gitignore
+			it("matches dotfiles with wildcards", func() {
+				filter, err := FileFilter(types.Build{Exclude: []string{"*.pem", "**/*.log", ".*", "!.buildpacks"}})
+				h.AssertNil(t, err)
+
+				assertFiltered(filter,
+					[]string{"main.go", "a.key", ".buildpacks"},
+					[]string{".secret.pem", "certs/.secret.pem", ".cache/app.log", ".cache/.app.log", "a/.b/c.log", ".env", "a/.env"},
+				)
+			})
+
+			it("matches dotfiles with a catch-all pattern", func() {
+				filter, err := FileFilter(types.Build{Exclude: []string{"*", "!*.go", ".env"}})
+				h.AssertNil(t, err)
+
+				assertFiltered(filter,
+					[]string{"main.go"},
+					[]string{"README.md", ".gitignore", ".env", ".git/config"},
+				)
+			})
+
+			it("ignores blank lines and comments", func() {
+				filter, err := FileFilter(types.Build{Exclude: []string{"", "# a comment", "*.log"}})
+				h.AssertNil(t, err)
+
+				assertFiltered(filter, []string{"# a comment", "main.go"}, []string{"app.log"})
+			})
+		})
+
+		when("include", func() {
+			it("only includes matching paths", func() {
+				filter, err := FileFilter(types.Build{Include: []string{"src", "go.mod", "!**/*_test.go"}})
+				h.AssertNil(t, err)
+
+				assertFiltered(filter,
+					[]string{"src", "src/main.go", "go.mod"},
+					[]string{"README.md", "src/main_test.go"},
+				)
+			})
+
+			it("includes dotfiles with wildcards, unless left out explicitly", func() {
+				filter, err := FileFilter(types.Build{Include: []string{"**", "!.git"}})
+				h.AssertNil(t, err)
+
+				assertFiltered(filter,
+					[]string{"main.go", "src/main.go", ".buildpacks/env", ".env"},
+					[]string{".git", ".git/config"},
+				)
+			})
+		})
+	})
+}
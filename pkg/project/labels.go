@@ -0,0 +1,77 @@
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/buildpacks/pack/pkg/project/types"
+)
+
+// Labels returns the labels of images built from a descriptor, nil if it has none. The project information is
+// mapped to the OCI image annotation keys (e.g. its name to 'org.opencontainers.image.title'), while the project
+// metadata is passed through as is, nested tables being joined with dots, e.g.
+//
+//	[_.metadata.org.example]
+//	team = "payments"
+//
+// is the label 'org.example.team=payments'. Values other than strings are formatted as JSON. Metadata overrides the
+// labels of the project information.
+func Labels(descriptor types.Descriptor) map[string]string {
+	labels := map[string]string{}
+	set := func(name, value string) {
+		if value != "" {
+			labels[name] = value
+		}
+	}
+
+	p := descriptor.Project
+	set("org.opencontainers.image.title", p.Name)
+	set("org.opencontainers.image.version", p.Version)
+	set("org.opencontainers.image.authors", strings.Join(p.Authors, ", "))
+	set("org.opencontainers.image.documentation", p.DocumentationURL)
+	set("org.opencontainers.image.source", p.SourceURL)
+	var licenses []string
+	for _, license := range p.Licenses {
+		if license.Type != "" {
+			licenses = append(licenses, license.Type)
+		}
+	}
+	set("org.opencontainers.image.licenses", strings.Join(licenses, " AND "))
+
+	walkMetadata(nil, descriptor.Metadata, func(path []string, value string) {
+		labels[strings.Join(path, ".")] = value
+	})
+
+	if len(labels) == 0 {
+		return nil
+	}
+	return labels
+}
+
+// walkMetadata calls fn with the path and formatted value of each value of a metadata table, in the order of the keys
+func walkMetadata(path []string, value interface{}, fn func(path []string, value string)) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			walkMetadata(append(path[:len(path):len(path)], key), v[key], fn)
+		}
+	case string:
+		fn(path, v)
+	case time.Time:
+		fn(path, v.Format(time.RFC3339))
+	default:
+		if formatted, err := json.Marshal(v); err == nil {
+			fn(path, string(formatted))
+		} else {
+			fn(path, fmt.Sprint(v))
+		}
+	}
+}
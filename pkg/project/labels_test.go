@@ -0,0 +1,82 @@
+package project
+
+import (
+	"testing"
+	"time"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/project/types"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestLabels(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "Labels", testLabels, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testLabels(t *testing.T, when spec.G, it spec.S) {
+	when("#Labels", func() {
+		it("returns nil without project information or metadata", func() {
+			h.AssertTrue(t, Labels(types.Descriptor{}) == nil)
+		})
+
+		it("maps the project information to the OCI annotation keys", func() {
+			labels := Labels(types.Descriptor{
+				Project: types.Project{
+					ID:               "com.example.app",
+					Name:             "Some App",
+					Version:          "1.2.3",
+					Authors:          []string{"Some Author", "Other Author"},
+					DocumentationURL: "https://example.com/docs",
+					SourceURL:        "https://example.com/source",
+					Licenses: []types.License{
+						{Type: "MIT"},
+						{URI: "https://example.com/license"},
+						{Type: "Apache-2.0"},
+					},
+				},
+			})
+
+			h.AssertEq(t, labels, map[string]string{
+				"org.opencontainers.image.title":         "Some App",
+				"org.opencontainers.image.version":       "1.2.3",
+				"org.opencontainers.image.authors":       "Some Author, Other Author",
+				"org.opencontainers.image.documentation": "https://example.com/docs",
+				"org.opencontainers.image.source":        "https://example.com/source",
+				"org.opencontainers.image.licenses":      "MIT AND Apache-2.0",
+			})
+		})
+
+		it("passes the metadata through, joining nested tables with dots", func() {
+			labels := Labels(types.Descriptor{
+				Project: types.Project{Name: "Some App"},
+				Metadata: map[string]interface{}{
+					"org.opencontainers.image.title": "Overridden App",
+					"org": map[string]interface{}{
+						"example": map[string]interface{}{
+							"team":     "payments",
+							"replicas": int64(3),
+							"public":   true,
+							"regions":  []interface{}{"eu", "us"},
+							"reviewed": time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+						},
+					},
+				},
+			})
+
+			h.AssertEq(t, labels, map[string]string{
+				"org.opencontainers.image.title": "Overridden App",
+				"org.example.team":               "payments",
+				"org.example.replicas":           "3",
+				"org.example.public":             "true",
+				"org.example.regions":            `["eu","us"]`,
+				"org.example.reviewed":           "2024-01-01T00:00:00Z",
+			})
+		})
+	})
+}
@@ -0,0 +1,99 @@
+package project
+
+import (
+	"fmt"
+	"strings"
+)
+
+// keyLines maps keys of a TOML document to the line they are declared on, so that validation errors can
+// point at the offending line. Tables are keyed by their name (e.g. "io.buildpacks"), elements of arrays of
+// tables by their name and index (e.g. "io.buildpacks.group[1]") and keys by their full path
+// (e.g. "io.buildpacks.group[1].uri").
+type keyLines map[string]int
+
+func newKeyLines(contents string) keyLines {
+	lines := keyLines{}
+	arrayLengths := map[string]int{}
+	table := ""
+
+	// resolve qualifies the parent tables of a name with the index of the latest element of the
+	// arrays of tables they refer to, e.g. "_.licenses.extra" becomes "_.licenses[0].extra"
+	resolve := func(name string) string {
+		parts := strings.Split(name, ".")
+		resolved := ""
+		for i, part := range parts {
+			resolved = join(resolved, part)
+			if length, ok := arrayLengths[resolved]; ok && i < len(parts)-1 {
+				resolved = fmt.Sprintf("%s[%d]", resolved, length-1)
+			}
+		}
+		return resolved
+	}
+
+	for i, line := range strings.Split(contents, "\n") {
+		line = stripComment(strings.TrimSpace(line))
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "[["):
+			name := resolve(normalizeKey(strings.TrimSuffix(strings.TrimPrefix(line, "[["), "]]")))
+			table = fmt.Sprintf("%s[%d]", name, arrayLengths[name])
+			arrayLengths[name]++
+			lines.add(table, i+1)
+		case strings.HasPrefix(line, "["):
+			table = resolve(normalizeKey(strings.TrimSuffix(strings.TrimPrefix(line, "["), "]")))
+			lines.add(table, i+1)
+		default:
+			if key, _, found := strings.Cut(line, "="); found {
+				lines.add(join(table, normalizeKey(key)), i+1)
+			}
+		}
+	}
+	return lines
+}
+
+// locate returns the first of keys that is declared along with its line, or the first of keys and 0 if none of them
+// are. Parts of keys containing dots must be quoted, e.g. 'io.buildpacks.labels."org.example.team"'.
+func (l keyLines) locate(keys ...string) (string, int) {
+	for _, key := range keys {
+		// keys are stored without the quotes around their parts
+		if line, ok := l[strings.ReplaceAll(key, `"`, "")]; ok {
+			return key, line
+		}
+	}
+	if len(keys) == 0 {
+		return "", 0
+	}
+	return keys[0], 0
+}
+
+func (l keyLines) add(key string, line int) {
+	if _, ok := l[key]; !ok {
+		l[key] = line
+	}
+}
+
+func join(table, key string) string {
+	if table == "" {
+		return key
+	}
+	return table + "." + key
+}
+
+// normalizeKey removes whitespace and quotes around the parts of a dotted key
+func normalizeKey(key string) string {
+	parts := strings.Split(strings.TrimSpace(key), ".")
+	for i, part := range parts {
+		parts[i] = strings.Trim(strings.TrimSpace(part), `"'`)
+	}
+	return strings.Join(parts, ".")
+}
+
+// stripComment removes a trailing comment from a line. Values containing '#' are rare in project.toml
+// and only used to find line numbers, so quoted '#' characters are not treated specially.
+func stripComment(line string) string {
+	if i := strings.Index(line, "#"); i >= 0 {
+		return strings.TrimSpace(line[:i])
+	}
+	return line
+}
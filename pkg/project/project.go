@@ -3,12 +3,17 @@ package project
 import (
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/BurntSushi/toml"
+	"github.com/buildpacks/lifecycle/api"
 	"github.com/pkg/errors"
 
+	"github.com/buildpacks/pack/internal/scan"
+	"github.com/buildpacks/pack/internal/style"
 	"github.com/buildpacks/pack/pkg/logging"
 	"github.com/buildpacks/pack/pkg/project/types"
 	v01 "github.com/buildpacks/pack/pkg/project/v01"
@@ -40,19 +45,25 @@ func ReadProjectDescriptor(pathToFile string, logger logging.Logger) (types.Desc
 		} `toml:"_"`
 	}
 
-	_, err = toml.Decode(string(projectTomlContents), &versionDescriptor)
+	versionMetaData, err := toml.Decode(string(projectTomlContents), &versionDescriptor)
 	if err != nil {
 		return types.Descriptor{}, errors.Wrapf(err, "parsing schema version")
 	}
 
 	version := versionDescriptor.Project.Version
-	if version == "" {
+	switch {
+	case version != "":
+	case versionMetaData.IsDefined("_") && !versionMetaData.IsDefined("project"):
+		// the [_] table was introduced with schema version 0.2
+		logger.Warn("No schema version declared in project.toml, detected schema version 0.2 from the [_] table")
+		version = "0.2"
+	default:
 		logger.Warn("No schema version declared in project.toml, defaulting to schema version 0.1")
 		version = "0.1"
 	}
 
 	if _, ok := parsers[version]; !ok {
-		return types.Descriptor{}, fmt.Errorf("unknown project descriptor schema version %s", version)
+		return types.Descriptor{}, unknownVersionError(version)
 	}
 
 	descriptor, tomlMetaData, err := parsers[version](string(projectTomlContents))
@@ -62,7 +73,27 @@ func ReadProjectDescriptor(pathToFile string, logger logging.Logger) (types.Desc
 
 	warnIfTomlContainsKeysNotSupportedBySchema(version, tomlMetaData, logger)
 
-	return descriptor, validate(descriptor)
+	return descriptor, validate(descriptor, keysBySchema[version], newKeyLines(string(projectTomlContents)))
+}
+
+// unknownVersionError explains that a schema version is not supported, suggesting to upgrade pack when it is newer than the latest supported one
+func unknownVersionError(version string) error {
+	latest := latestSchemaVersion()
+	if requested, err := api.NewVersion(version); err == nil && requested.Compare(latest) > 0 {
+		return fmt.Errorf("unknown project descriptor schema version %s, the latest version supported by this version of pack is %s. Please upgrade pack", version, latest)
+	}
+	return fmt.Errorf("unknown project descriptor schema version %s", version)
+}
+
+func latestSchemaVersion() *api.Version {
+	var latest *api.Version
+	for version := range parsers {
+		parsed := api.MustParse(version)
+		if latest == nil || parsed.Compare(latest) > 0 {
+			latest = parsed
+		}
+	}
+	return latest
 }
 
 func warnIfTomlContainsKeysNotSupportedBySchema(schemaVersion string, tomlMetaData toml.MetaData, logger logging.Logger) {
@@ -95,27 +126,294 @@ func unsupportedKey(keyName, schemaVersion string) bool {
 	return true
 }
 
-func validate(p types.Descriptor) error {
+// schemaKeys are the keys of the validated values in a schema version, used to find the line of invalid values
+type schemaKeys struct {
+	include     string
+	exclude     string
+	licenses    string
+	buildpacks  string
+	pre         string
+	post        string
+	env         []string
+	profiles    string
+	apps        string
+	hooks       string
+	scan        string
+	labels      string
+	annotations string
+}
+
+var keysBySchema = map[string]schemaKeys{
+	"0.1": {
+		include:     "build.include",
+		exclude:     "build.exclude",
+		licenses:    "project.licenses",
+		buildpacks:  "build.buildpacks",
+		env:         []string{"build.env"},
+		profiles:    "build.profiles",
+		apps:        "build.apps",
+		hooks:       "build.hooks",
+		scan:        "build.scan",
+		labels:      "build.labels",
+		annotations: "build.annotations",
+	},
+	"0.2": {
+		include:     "io.buildpacks.include",
+		exclude:     "io.buildpacks.exclude",
+		licenses:    "_.licenses",
+		buildpacks:  "io.buildpacks.group",
+		pre:         "io.buildpacks.pre.group",
+		post:        "io.buildpacks.post.group",
+		env:         []string{"io.buildpacks.build.env", "io.buildpacks.env.build"},
+		profiles:    "io.buildpacks.profiles",
+		apps:        "io.buildpacks.apps",
+		hooks:       "io.buildpacks.hooks",
+		scan:        "io.buildpacks.scan",
+		labels:      "io.buildpacks.labels",
+		annotations: "io.buildpacks.annotations",
+	},
+}
+
+// ValidationError is a project descriptor that does not conform to its schema
+type ValidationError struct {
+	Message string
+
+	// Key of the invalid value, e.g. "io.buildpacks.group[1].uri", empty when unknown
+	Key string
+
+	// Line of project.toml the invalid value is declared on, 0 when unknown
+	Line int
+}
+
+func (e *ValidationError) Error() string {
+	message := e.Message
+	if e.Key != "" {
+		message = e.Key + ": " + message
+	}
+	if e.Line == 0 {
+		return "project.toml: " + message
+	}
+	return fmt.Sprintf("project.toml: %s (line %d)", message, e.Line)
+}
+
+func validateScript(script types.Script) error {
+	switch script.Shell {
+	case "", types.ShellSh, types.ShellBash, types.ShellPowerShell, types.ShellCmd:
+	default:
+		if !path.IsAbs(script.Shell) && !filepath.IsAbs(script.Shell) {
+			return errors.Errorf("unsupported script shell %s, must be one of %s, %s, %s, %s or an absolute path",
+				style.Symbol(script.Shell), types.ShellSh, types.ShellBash, types.ShellPowerShell, types.ShellCmd)
+		}
+	}
+
+	for _, file := range script.Files {
+		if !filepath.IsLocal(filepath.FromSlash(file)) {
+			return errors.Errorf("script file %s must be relative to the project descriptor", style.Symbol(file))
+		}
+	}
+	return nil
+}
+
+func validate(p types.Descriptor, keys schemaKeys, lines keyLines) error {
+	// invalid points at the first of keys declared in project.toml, from the most specific to the least
+	invalid := func(message string, candidates ...string) error {
+		key, line := lines.locate(candidates...)
+		return &ValidationError{Message: message, Key: key, Line: line}
+	}
+
 	if p.Build.Exclude != nil && p.Build.Include != nil {
-		return errors.New("project.toml: cannot have both include and exclude defined")
+		return invalid("cannot have both include and exclude defined", keys.include)
+	}
+
+	patternLists := []struct {
+		key      string
+		patterns []string
+	}{
+		{keys.include, p.Build.Include},
+		{keys.exclude, p.Build.Exclude},
+	}
+	for _, list := range patternLists {
+		if _, err := compilePatterns(list.patterns); err != nil {
+			return invalid(err.Error(), list.key)
+		}
 	}
 
-	if len(p.Project.Licenses) > 0 {
-		for _, license := range p.Project.Licenses {
-			if license.Type == "" && license.URI == "" {
-				return errors.New("project.toml: must have a type or uri defined for each license")
+	for i, license := range p.Project.Licenses {
+		if license.Type == "" && license.URI == "" {
+			return invalid("must have a type or uri defined for each license", indexed(keys.licenses, i), keys.licenses)
+		}
+	}
+
+	groups := []struct {
+		key        string
+		buildpacks []types.Buildpack
+	}{
+		{keys.buildpacks, p.Build.Buildpacks},
+		{keys.pre, p.Build.Pre.Buildpacks},
+		{keys.post, p.Build.Post.Buildpacks},
+	}
+	for _, group := range groups {
+		for i, bp := range group.buildpacks {
+			key := indexed(group.key, i)
+			if bp.ID == "" && bp.URI == "" {
+				return invalid("buildpacks must have an id or url defined", key, group.key)
+			}
+			if bp.URI != "" && bp.Version != "" {
+				return invalid("buildpacks cannot have both uri and version defined", key, group.key)
+			}
+			if err := validateScript(bp.Script); err != nil {
+				return invalid(err.Error(), join(key, "script"), key, group.key)
 			}
 		}
 	}
 
-	for _, bp := range p.Build.Buildpacks {
-		if bp.ID == "" && bp.URI == "" {
-			return errors.New("project.toml: buildpacks must have an id or url defined")
+	for i, env := range p.Build.Env {
+		if env.Name == "" {
+			var candidates []string
+			for _, key := range keys.env {
+				candidates = append(candidates, indexed(key, i))
+			}
+			return invalid("build env vars must have a name defined", candidates...)
 		}
-		if bp.URI != "" && bp.Version != "" {
-			return errors.New("project.toml: buildpacks cannot have both uri and version defined")
+	}
+
+	hookStages := []struct {
+		key   string
+		hooks []types.Hook
+	}{
+		{join(keys.hooks, "pre-build"), p.Build.Hooks.PreBuild},
+		{join(keys.hooks, "post-build"), p.Build.Hooks.PostBuild},
+	}
+	for _, stage := range hookStages {
+		for i, hook := range stage.hooks {
+			if strings.TrimSpace(hook.Command) == "" {
+				return invalid("hooks must have a command defined", indexed(stage.key, i), stage.key)
+			}
+		}
+	}
+
+	if scanner := p.Build.Scan.Scanner; scanner != "" {
+		if _, ok := scan.Scanners[scanner]; !ok {
+			return invalid(
+				fmt.Sprintf("unknown scanner %s, must be one of %s", style.Symbol(scanner), strings.Join(scan.ScannerNames(), ", ")),
+				join(keys.scan, "scanner"), keys.scan,
+			)
+		}
+	}
+	if p.Build.Scan.FailOn != "" {
+		if _, err := scan.ParseSeverity(p.Build.Scan.FailOn); err != nil {
+			return invalid(err.Error(), join(keys.scan, "fail-on"), keys.scan)
+		}
+	}
+
+	// the labels reserved by buildpacks, including those the metadata is passed through to, are rejected by the build
+	if _, ok := p.Build.Labels[""]; ok {
+		return invalid("labels must have a name defined", keys.labels)
+	}
+	if _, ok := p.Build.Annotations[""]; ok {
+		return invalid("annotations must have a name defined", keys.annotations)
+	}
+
+	images := map[string]bool{}
+	for i, app := range p.Build.Apps {
+		key := indexed(keys.apps, i)
+		switch {
+		case app.Path == "":
+			return invalid("apps must have a path defined", key)
+		case !filepath.IsLocal(filepath.FromSlash(app.Path)):
+			return invalid(fmt.Sprintf("app path %s must be relative to the project descriptor", style.Symbol(app.Path)), join(key, "path"), key)
+		case app.Image == "":
+			return invalid("apps must have an image defined", key)
+		case images[app.Image]:
+			return invalid(fmt.Sprintf("image %s is declared by more than one app", style.Symbol(app.Image)), join(key, "image"), key)
+		}
+		images[app.Image] = true
+
+		for j, env := range app.Env {
+			if env.Name == "" {
+				return invalid("build env vars of apps must have a name defined", indexed(join(key, "env"), j), key)
+			}
+		}
+	}
+
+	for _, name := range ProfileNames(p) {
+		key := join(keys.profiles, name)
+		if name == "" {
+			return invalid("profiles must have a name defined", key)
+		}
+		for i, env := range p.Build.Profiles[name].Env {
+			if env.Name == "" {
+				return invalid(
+					fmt.Sprintf("build env vars of profile %s must have a name defined", style.Symbol(name)),
+					indexed(join(key, "env"), i), key,
+				)
+			}
 		}
 	}
 
 	return nil
 }
+
+func indexed(key string, index int) string {
+	return fmt.Sprintf("%s[%d]", key, index)
+}
+
+// ProfileNames returns the sorted names of the profiles defined in a project descriptor
+func ProfileNames(descriptor types.Descriptor) []string {
+	var names []string
+	for name := range descriptor.Build.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WithProfile returns the descriptor with the build configuration of the named profile applied. Env vars of the
+// profile override the ones of the same name declared in the base configuration. An empty name returns the descriptor
+// unchanged.
+func WithProfile(descriptor types.Descriptor, name string) (types.Descriptor, error) {
+	if name == "" {
+		return descriptor, nil
+	}
+
+	profile, ok := descriptor.Build.Profiles[name]
+	if !ok {
+		if len(descriptor.Build.Profiles) == 0 {
+			return types.Descriptor{}, errors.Errorf("profile %s is not defined, the project descriptor doesn't declare any profiles", style.Symbol(name))
+		}
+		return types.Descriptor{}, errors.Errorf("profile %s is not defined, available profiles are: %s", style.Symbol(name), strings.Join(ProfileNames(descriptor), ", "))
+	}
+
+	descriptor.Build.Env = overrideEnv(descriptor.Build.Env, profile.Env)
+	return descriptor, nil
+}
+
+// ForApp returns the descriptor used to build one of the apps it declares. The builder and env vars of the app
+// override the ones of the descriptor.
+func ForApp(descriptor types.Descriptor, app types.App) types.Descriptor {
+	descriptor.Build.Apps = nil
+	descriptor.Build.Env = overrideEnv(descriptor.Build.Env, app.Env)
+	if app.Builder != "" {
+		descriptor.Build.Builder = app.Builder
+	}
+	return descriptor
+}
+
+// overrideEnv returns the env vars of base that are not overridden, followed by overrides
+func overrideEnv(base, overrides []types.EnvVar) []types.EnvVar {
+	if len(overrides) == 0 {
+		return base
+	}
+
+	var env []types.EnvVar
+	overridden := map[string]bool{}
+	for _, envVar := range overrides {
+		overridden[envVar.Name] = true
+	}
+	for _, envVar := range base {
+		if !overridden[envVar.Name] {
+			env = append(env, envVar)
+		}
+	}
+	return append(env, overrides...)
+}
@@ -8,10 +8,12 @@ import (
 
 	"github.com/buildpacks/lifecycle/api"
 	"github.com/heroku/color"
+	"github.com/pkg/errors"
 	"github.com/sclevine/spec"
 	"github.com/sclevine/spec/report"
 
 	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/project/types"
 	h "github.com/buildpacks/pack/testhelpers"
 )
 
@@ -57,6 +59,7 @@ id = "example/lua"
 version = "1.0"
 [[io.buildpacks.group]]
 uri = "https://example.com/buildpack"
+optional = true
 [[io.buildpacks.build.env]]
 name = "JAVA_OPTS"
 value = "-Xmx300m"
@@ -106,6 +109,10 @@ value = "this-should-get-overridden-because-its-deprecated"
 					expected, projectDescriptor.Build.Buildpacks[1].URI)
 			}
 
+			if projectDescriptor.Build.Buildpacks[0].Optional || !projectDescriptor.Build.Buildpacks[1].Optional {
+				t.Fatalf("Expected only the second buildpack to be optional, got %#v", projectDescriptor.Build.Buildpacks)
+			}
+
 			expected = "https://example.com/buildpack/pre"
 			if projectDescriptor.Build.Pre.Buildpacks[0].URI != expected {
 				t.Fatalf("Expected\n-----\n%#v\n-----\nbut got\n-----\n%#v\n",
@@ -470,6 +477,487 @@ buzz = ["a", "b", "c"]
 			// Assert we only warn
 			h.AssertContains(t, readStdout(), "Warning: The following keys declared in project.toml are not supported in schema version 0.2:\nWarning: - _.versions\nWarning: - _.licenses.foo\nWarning: - io.buildpacks.build.foo\nWarning: - io.buildpacks.build.foo.name\nWarning: The above keys will be ignored. If this is not intentional, try updating your schema version.\n")
 		})
+
+		it("should parse all project information of a v0.2 project.toml file", func() {
+			projectToml := `
+[_]
+schema-version = "0.2"
+id = "io.example.app"
+name = "Example"
+version = "1.0.0"
+authors = ["someone", "someone else"]
+documentation-url = "https://example.com/docs"
+source-url = "https://example.com/source"
+`
+			tmpProjectToml, err := createTmpProjectTomlFile(projectToml)
+			h.AssertNil(t, err)
+
+			projectDescriptor, err := ReadProjectDescriptor(tmpProjectToml.Name(), logger)
+			h.AssertNil(t, err)
+			h.AssertEq(t, projectDescriptor.Project.ID, "io.example.app")
+			h.AssertEq(t, projectDescriptor.Project.Name, "Example")
+			h.AssertEq(t, projectDescriptor.Project.Version, "1.0.0")
+			h.AssertEq(t, projectDescriptor.Project.Authors, []string{"someone", "someone else"})
+			h.AssertEq(t, projectDescriptor.Project.DocumentationURL, "https://example.com/docs")
+			h.AssertEq(t, projectDescriptor.Project.SourceURL, "https://example.com/source")
+		})
+
+		it("should detect schema version 0.2 from the [_] table when no schema version is declared", func() {
+			projectToml := `
+[_]
+name = "no version"
+
+[[io.buildpacks.group]]
+id = "example/lua"
+`
+			tmpProjectToml, err := createTmpProjectTomlFile(projectToml)
+			h.AssertNil(t, err)
+
+			projectDescriptor, err := ReadProjectDescriptor(tmpProjectToml.Name(), logger)
+			h.AssertNil(t, err)
+			h.AssertEq(t, projectDescriptor.SchemaVersion.String(), "0.2")
+			h.AssertEq(t, projectDescriptor.Build.Buildpacks[0].ID, "example/lua")
+			h.AssertContains(t, readStdout(), "Warning: No schema version declared in project.toml, detected schema version 0.2 from the [_] table\n")
+		})
+
+		it("should suggest upgrading pack for a newer schema version", func() {
+			tmpProjectToml, err := createTmpProjectTomlFile("[_]\nschema-version = \"0.9\"\n")
+			h.AssertNil(t, err)
+
+			_, err = ReadProjectDescriptor(tmpProjectToml.Name(), logger)
+			h.AssertError(t, err, "unknown project descriptor schema version 0.9, the latest version supported by this version of pack is 0.2. Please upgrade pack")
+		})
+
+		it("should fail for an unknown schema version", func() {
+			tmpProjectToml, err := createTmpProjectTomlFile("[_]\nschema-version = \"latest\"\n")
+			h.AssertNil(t, err)
+
+			_, err = ReadProjectDescriptor(tmpProjectToml.Name(), logger)
+			h.AssertError(t, err, "unknown project descriptor schema version latest")
+		})
+
+		when("validation fails", func() {
+			it("should report the line of the invalid buildpack in a v0.2 project.toml file", func() {
+				projectToml := `[_]
+schema-version = "0.2"
+
+[[io.buildpacks.group]]
+id = "example/lua"
+
+[[io.buildpacks.group]]
+uri = "https://example.com/buildpack"
+version = "1.2.3"
+`
+				tmpProjectToml, err := createTmpProjectTomlFile(projectToml)
+				h.AssertNil(t, err)
+
+				_, err = ReadProjectDescriptor(tmpProjectToml.Name(), logger)
+				h.AssertError(t, err, "project.toml: io.buildpacks.group[1]: buildpacks cannot have both uri and version defined (line 7)")
+
+				var validationErr *ValidationError
+				h.AssertTrue(t, errors.As(err, &validationErr))
+				h.AssertEq(t, validationErr.Key, "io.buildpacks.group[1]")
+				h.AssertEq(t, validationErr.Line, 7)
+			})
+
+			it("should report the line of the invalid buildpack in a pre group", func() {
+				projectToml := `[_]
+schema-version = "0.2"
+[[io.buildpacks.pre.group]]
+version = "1.2.3"
+`
+				tmpProjectToml, err := createTmpProjectTomlFile(projectToml)
+				h.AssertNil(t, err)
+
+				_, err = ReadProjectDescriptor(tmpProjectToml.Name(), logger)
+				h.AssertError(t, err, "project.toml: io.buildpacks.pre.group[0]: buildpacks must have an id or url defined (line 3)")
+			})
+
+			it("should report the line of the invalid license in a v0.1 project.toml file", func() {
+				projectToml := `[project]
+name = "licenses"
+
+[[project.licenses]]
+type = "MIT"
+
+[[project.licenses]]
+# neither type nor uri
+`
+				tmpProjectToml, err := createTmpProjectTomlFile(projectToml)
+				h.AssertNil(t, err)
+
+				_, err = ReadProjectDescriptor(tmpProjectToml.Name(), logger)
+				h.AssertError(t, err, "project.toml: project.licenses[1]: must have a type or uri defined for each license (line 7)")
+			})
+
+			it("should report the line of include when both include and exclude are defined", func() {
+				projectToml := `[_]
+schema-version = "0.2"
+
+[io.buildpacks]
+exclude = ["*.jar"]
+include = ["*.jpg"]
+`
+				tmpProjectToml, err := createTmpProjectTomlFile(projectToml)
+				h.AssertNil(t, err)
+
+				_, err = ReadProjectDescriptor(tmpProjectToml.Name(), logger)
+				h.AssertError(t, err, "project.toml: io.buildpacks.include: cannot have both include and exclude defined (line 6)")
+			})
+
+			it("should report the line of an invalid exclude pattern", func() {
+				projectToml := `[_]
+schema-version = "0.2"
+
+[io.buildpacks]
+exclude = ["*.log", "[z-a"]
+`
+				tmpProjectToml, err := createTmpProjectTomlFile(projectToml)
+				h.AssertNil(t, err)
+
+				_, err = ReadProjectDescriptor(tmpProjectToml.Name(), logger)
+				h.AssertError(t, err, "project.toml: io.buildpacks.exclude: invalid pattern '[z-a'")
+				h.AssertError(t, err, "(line 5)")
+			})
+
+			it("should require a name for build env vars", func() {
+				projectToml := `[_]
+schema-version = "0.2"
+
+[[io.buildpacks.build.env]]
+name = "JAVA_OPTS"
+value = "-Xmx300m"
+
+[[io.buildpacks.build.env]]
+value = "no name"
+`
+				tmpProjectToml, err := createTmpProjectTomlFile(projectToml)
+				h.AssertNil(t, err)
+
+				_, err = ReadProjectDescriptor(tmpProjectToml.Name(), logger)
+				h.AssertError(t, err, "project.toml: io.buildpacks.build.env[1]: build env vars must have a name defined (line 8)")
+			})
+
+			it("should report the line of an invalid app", func() {
+				projectToml := `[_]
+schema-version = "0.2"
+
+[[io.buildpacks.apps]]
+path = "services/api"
+image = "registry.example.com/api"
+
+[[io.buildpacks.apps]]
+path = "services/web"
+`
+				tmpProjectToml, err := createTmpProjectTomlFile(projectToml)
+				h.AssertNil(t, err)
+
+				_, err = ReadProjectDescriptor(tmpProjectToml.Name(), logger)
+				h.AssertError(t, err, "project.toml: io.buildpacks.apps[1]: apps must have an image defined (line 8)")
+			})
+
+			it("should require app paths to be relative to the descriptor", func() {
+				projectToml := `[_]
+schema-version = "0.2"
+
+[[io.buildpacks.apps]]
+path = "../other-repo"
+image = "registry.example.com/api"
+`
+				tmpProjectToml, err := createTmpProjectTomlFile(projectToml)
+				h.AssertNil(t, err)
+
+				_, err = ReadProjectDescriptor(tmpProjectToml.Name(), logger)
+				h.AssertError(t, err, "project.toml: io.buildpacks.apps[0].path: app path '../other-repo' must be relative to the project descriptor (line 5)")
+			})
+
+			it("should require distinct images for apps", func() {
+				projectToml := `[_]
+schema-version = "0.2"
+
+[[io.buildpacks.apps]]
+path = "services/api"
+image = "registry.example.com/app"
+
+[[io.buildpacks.apps]]
+path = "services/web"
+image = "registry.example.com/app"
+`
+				tmpProjectToml, err := createTmpProjectTomlFile(projectToml)
+				h.AssertNil(t, err)
+
+				_, err = ReadProjectDescriptor(tmpProjectToml.Name(), logger)
+				h.AssertError(t, err, "project.toml: io.buildpacks.apps[1].image: image 'registry.example.com/app' is declared by more than one app (line 10)")
+			})
+
+			it("should require a name for build env vars of profiles", func() {
+				projectToml := `[_]
+schema-version = "0.2"
+
+[[io.buildpacks.profiles.dev.env]]
+name = "LOG_LEVEL"
+value = "debug"
+
+[[io.buildpacks.profiles.dev.env]]
+value = "no name"
+`
+				tmpProjectToml, err := createTmpProjectTomlFile(projectToml)
+				h.AssertNil(t, err)
+
+				_, err = ReadProjectDescriptor(tmpProjectToml.Name(), logger)
+				h.AssertError(t, err, "project.toml: io.buildpacks.profiles.dev.env[1]: build env vars of profile 'dev' must have a name defined (line 8)")
+			})
+
+			it("should reject unsupported script shells", func() {
+				projectToml := `[_]
+schema-version = "0.2"
+
+[[io.buildpacks.group]]
+id = "example/inline"
+
+[io.buildpacks.group.script]
+api = "0.10"
+shell = "fish"
+inline = "echo hi"
+`
+				tmpProjectToml, err := createTmpProjectTomlFile(projectToml)
+				h.AssertNil(t, err)
+
+				_, err = ReadProjectDescriptor(tmpProjectToml.Name(), logger)
+				h.AssertError(t, err, "project.toml: io.buildpacks.group[0].script: unsupported script shell 'fish'")
+				h.AssertError(t, err, "(line 7)")
+			})
+
+			it("should require script files to be relative", func() {
+				projectToml := `[_]
+schema-version = "0.2"
+
+[[io.buildpacks.group]]
+id = "example/inline"
+
+[io.buildpacks.group.script]
+api = "0.10"
+files = ["../outside.sh"]
+`
+				tmpProjectToml, err := createTmpProjectTomlFile(projectToml)
+				h.AssertNil(t, err)
+
+				_, err = ReadProjectDescriptor(tmpProjectToml.Name(), logger)
+				h.AssertError(t, err, "project.toml: io.buildpacks.group[0].script: script file '../outside.sh' must be relative to the project descriptor (line 7)")
+			})
+
+			it("should require a command for hooks", func() {
+				projectToml := `[_]
+schema-version = "0.2"
+
+[[io.buildpacks.hooks.pre-build]]
+command = "make generate"
+
+[[io.buildpacks.hooks.post-build]]
+`
+				tmpProjectToml, err := createTmpProjectTomlFile(projectToml)
+				h.AssertNil(t, err)
+
+				_, err = ReadProjectDescriptor(tmpProjectToml.Name(), logger)
+				h.AssertError(t, err, "project.toml: io.buildpacks.hooks.post-build[0]: hooks must have a command defined (line 7)")
+			})
+
+			it("should require a known scanner and severity", func() {
+				projectToml := `[_]
+schema-version = "0.2"
+
+[io.buildpacks.scan]
+scanner = "grype"
+fail-on = "severe"
+`
+				tmpProjectToml, err := createTmpProjectTomlFile(projectToml)
+				h.AssertNil(t, err)
+
+				_, err = ReadProjectDescriptor(tmpProjectToml.Name(), logger)
+				h.AssertError(t, err, "project.toml: io.buildpacks.scan.fail-on: unknown severity 'severe', must be one of unknown, negligible, low, medium, high, critical (line 6)")
+
+				tmpProjectToml, err = createTmpProjectTomlFile(`[_]
+schema-version = "0.2"
+
+[io.buildpacks.scan]
+scanner = "clair"
+`)
+				h.AssertNil(t, err)
+
+				_, err = ReadProjectDescriptor(tmpProjectToml.Name(), logger)
+				h.AssertError(t, err, "project.toml: io.buildpacks.scan.scanner: unknown scanner 'clair', must be one of grype, trivy (line 5)")
+			})
+		})
+
+		it("should parse hooks", func() {
+			projectToml := `[_]
+schema-version = "0.2"
+
+[[io.buildpacks.hooks.pre-build]]
+command = "make generate"
+
+[[io.buildpacks.hooks.post-build]]
+command = "./scripts/scan.sh"
+`
+			tmpProjectToml, err := createTmpProjectTomlFile(projectToml)
+			h.AssertNil(t, err)
+
+			projectDescriptor, err := ReadProjectDescriptor(tmpProjectToml.Name(), logger)
+			h.AssertNil(t, err)
+			h.AssertEq(t, projectDescriptor.Build.Hooks, types.Hooks{
+				PreBuild:  []types.Hook{{Command: "make generate"}},
+				PostBuild: []types.Hook{{Command: "./scripts/scan.sh"}},
+			})
+		})
+
+		it("should parse labels and annotations", func() {
+			projectToml := `[_]
+schema-version = "0.2"
+
+[io.buildpacks.labels]
+"org.opencontainers.image.vendor" = "some-vendor"
+
+[io.buildpacks.annotations]
+"org.opencontainers.image.url" = "https://example.com"
+`
+			tmpProjectToml, err := createTmpProjectTomlFile(projectToml)
+			h.AssertNil(t, err)
+
+			projectDescriptor, err := ReadProjectDescriptor(tmpProjectToml.Name(), logger)
+			h.AssertNil(t, err)
+			h.AssertEq(t, projectDescriptor.Build.Labels, map[string]string{"org.opencontainers.image.vendor": "some-vendor"})
+			h.AssertEq(t, projectDescriptor.Build.Annotations, map[string]string{"org.opencontainers.image.url": "https://example.com"})
+		})
+
+		it("should parse apps", func() {
+			projectToml := `[_]
+schema-version = "0.2"
+
+[[io.buildpacks.apps]]
+path = "services/api"
+image = "registry.example.com/api"
+builder = "example/builder"
+
+[[io.buildpacks.apps.env]]
+name = "PORT"
+value = "8080"
+
+[[io.buildpacks.apps]]
+path = "services/web"
+image = "registry.example.com/web"
+`
+			tmpProjectToml, err := createTmpProjectTomlFile(projectToml)
+			h.AssertNil(t, err)
+
+			projectDescriptor, err := ReadProjectDescriptor(tmpProjectToml.Name(), logger)
+			h.AssertNil(t, err)
+			h.AssertEq(t, projectDescriptor.Build.Apps, []types.App{
+				{Path: "services/api", Image: "registry.example.com/api", Builder: "example/builder", Env: []types.EnvVar{{Name: "PORT", Value: "8080"}}},
+				{Path: "services/web", Image: "registry.example.com/web"},
+			})
+		})
+
+		it("should parse profiles", func() {
+			projectToml := `[_]
+schema-version = "0.2"
+
+[[io.buildpacks.profiles.dev.env]]
+name = "LOG_LEVEL"
+value = "debug"
+
+[[io.buildpacks.profiles.prod.env]]
+name = "LOG_LEVEL"
+value = "warn"
+`
+			tmpProjectToml, err := createTmpProjectTomlFile(projectToml)
+			h.AssertNil(t, err)
+
+			projectDescriptor, err := ReadProjectDescriptor(tmpProjectToml.Name(), logger)
+			h.AssertNil(t, err)
+			h.AssertEq(t, ProfileNames(projectDescriptor), []string{"dev", "prod"})
+			h.AssertEq(t, projectDescriptor.Build.Profiles["prod"].Env, []types.EnvVar{{Name: "LOG_LEVEL", Value: "warn"}})
+		})
+
+		it("should parse profiles in a v0.1 project.toml file", func() {
+			projectToml := `[project]
+name = "profiles"
+
+[[build.profiles.dev.env]]
+name = "LOG_LEVEL"
+value = "debug"
+`
+			tmpProjectToml, err := createTmpProjectTomlFile(projectToml)
+			h.AssertNil(t, err)
+
+			projectDescriptor, err := ReadProjectDescriptor(tmpProjectToml.Name(), logger)
+			h.AssertNil(t, err)
+			h.AssertEq(t, projectDescriptor.Build.Profiles["dev"].Env, []types.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}})
+		})
+	})
+
+	when("#ForApp", func() {
+		it("overrides the builder and env vars of the descriptor with the ones of the app", func() {
+			descriptor := types.Descriptor{
+				Build: types.Build{
+					Builder: "example/builder",
+					Env:     []types.EnvVar{{Name: "PORT", Value: "80"}, {Name: "REGION", Value: "eu"}},
+					Apps:    []types.App{{Path: "api", Image: "api"}},
+				},
+			}
+
+			forApp := ForApp(descriptor, types.App{Path: "api", Image: "api", Builder: "other/builder", Env: []types.EnvVar{{Name: "PORT", Value: "8080"}}})
+			h.AssertEq(t, forApp.Build.Builder, "other/builder")
+			h.AssertEq(t, forApp.Build.Env, []types.EnvVar{{Name: "REGION", Value: "eu"}, {Name: "PORT", Value: "8080"}})
+			h.AssertEq(t, len(forApp.Build.Apps), 0)
+
+			forApp = ForApp(descriptor, types.App{Path: "web", Image: "web"})
+			h.AssertEq(t, forApp.Build.Builder, "example/builder")
+			h.AssertEq(t, forApp.Build.Env, descriptor.Build.Env)
+		})
+	})
+
+	when("#WithProfile", func() {
+		var descriptor types.Descriptor
+
+		it.Before(func() {
+			descriptor = types.Descriptor{
+				Build: types.Build{
+					Env: []types.EnvVar{
+						{Name: "LOG_LEVEL", Value: "info"},
+						{Name: "REGION", Value: "eu"},
+					},
+					Profiles: map[string]types.Profile{
+						"dev":  {Env: []types.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}, {Name: "DEBUG", Value: "true"}}},
+						"prod": {},
+					},
+				},
+			}
+		})
+
+		it("returns the descriptor unchanged without a profile", func() {
+			withProfile, err := WithProfile(descriptor, "")
+			h.AssertNil(t, err)
+			h.AssertEq(t, withProfile.Build, descriptor.Build)
+		})
+
+		it("overrides env vars of the base configuration with the ones of the profile", func() {
+			withProfile, err := WithProfile(descriptor, "dev")
+			h.AssertNil(t, err)
+			h.AssertEq(t, withProfile.Build.Env, []types.EnvVar{
+				{Name: "REGION", Value: "eu"},
+				{Name: "LOG_LEVEL", Value: "debug"},
+				{Name: "DEBUG", Value: "true"},
+			})
+			h.AssertEq(t, descriptor.Build.Env[0].Value, "info")
+		})
+
+		it("errors if the profile is not defined", func() {
+			_, err := WithProfile(descriptor, "staging")
+			h.AssertError(t, err, "profile 'staging' is not defined, available profiles are: dev, prod")
+
+			_, err = WithProfile(types.Descriptor{}, "staging")
+			h.AssertError(t, err, "profile 'staging' is not defined, the project descriptor doesn't declare any profiles")
+		})
 	})
 }
 
@@ -0,0 +1,260 @@
+package project
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/pkg/project/types"
+)
+
+// Scaffold is a starter project descriptor for an app, derived from its source
+type Scaffold struct {
+	ID string
+
+	// Languages detected from the files at the root of the app, e.g. "Go" for a go.mod
+	Languages []string
+
+	// Dockerfile of the app, if any, whose ENV instructions are used as build env vars
+	Dockerfile string
+
+	Builder string
+	Env     []types.EnvVar
+	Exclude []string
+}
+
+type language struct {
+	name    string
+	markers []string
+	exclude []string
+}
+
+// languages are detected from marker files, which may be glob patterns
+var languages = []language{
+	{name: "Go", markers: []string{"go.mod"}},
+	{name: "Node.js", markers: []string{"package.json"}, exclude: []string{"node_modules"}},
+	{name: "Python", markers: []string{"requirements.txt", "pyproject.toml", "Pipfile", "setup.py"}, exclude: []string{"__pycache__", ".venv"}},
+	{name: "Java", markers: []string{"pom.xml", "build.gradle", "build.gradle.kts"}, exclude: []string{"target", "build"}},
+	{name: "Ruby", markers: []string{"Gemfile"}, exclude: []string{".bundle"}},
+	{name: "PHP", markers: []string{"composer.json"}, exclude: []string{"vendor"}},
+	{name: ".NET", markers: []string{"*.csproj", "*.fsproj", "*.sln"}, exclude: []string{"bin", "obj"}},
+	{name: "Rust", markers: []string{"Cargo.toml"}, exclude: []string{"target"}},
+}
+
+// NewScaffold inspects the files at the root of appPath to detect the languages of the app and its Dockerfile. Paths
+// ignored by a .dockerignore file are excluded as well.
+func NewScaffold(appPath string) (Scaffold, error) {
+	absPath, err := filepath.Abs(appPath)
+	if err != nil {
+		return Scaffold{}, err
+	}
+	if info, err := os.Stat(absPath); err != nil || !info.IsDir() {
+		return Scaffold{}, errors.Errorf("app path %s is not a directory", appPath)
+	}
+
+	scaffold := Scaffold{ID: scaffoldID(filepath.Base(absPath))}
+	excluded := map[string]bool{}
+	addExclude := func(patterns ...string) {
+		for _, pattern := range patterns {
+			if !excluded[pattern] {
+				excluded[pattern] = true
+				scaffold.Exclude = append(scaffold.Exclude, pattern)
+			}
+		}
+	}
+
+	for _, lang := range languages {
+		for _, marker := range lang.markers {
+			if matches, _ := filepath.Glob(filepath.Join(absPath, marker)); len(matches) > 0 {
+				scaffold.Languages = append(scaffold.Languages, lang.name)
+				addExclude(lang.exclude...)
+				break
+			}
+		}
+	}
+
+	for _, name := range []string{"Dockerfile", "Containerfile"} {
+		if _, err := os.Stat(filepath.Join(absPath, name)); err != nil {
+			continue
+		}
+		scaffold.Dockerfile = name
+		if scaffold.Env, err = dockerfileEnv(filepath.Join(absPath, name)); err != nil {
+			return Scaffold{}, errors.Wrapf(err, "reading %s", name)
+		}
+		addExclude(name)
+		break
+	}
+
+	patterns, err := readLines(filepath.Join(absPath, ".dockerignore"))
+	if err != nil {
+		return Scaffold{}, errors.Wrap(err, "reading .dockerignore")
+	}
+	if patterns != nil {
+		addExclude(".dockerignore")
+		for _, pattern := range patterns {
+			// only keep patterns that are valid in project.toml as well
+			if _, err := compilePatterns([]string{pattern}); err == nil {
+				addExclude(pattern)
+			}
+		}
+	}
+
+	return scaffold, nil
+}
+
+var invalidIDChars = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+func scaffoldID(name string) string {
+	id := strings.Trim(invalidIDChars.ReplaceAllString(strings.ToLower(name), "-"), "-.")
+	if id == "" {
+		return "app"
+	}
+	return id
+}
+
+// readLines returns the non-empty lines of a file that aren't comments, or nil if it doesn't exist
+func readLines(path string) ([]string, error) {
+	file, err := os.Open(filepath.Clean(path))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	lines := []string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// dockerfileEnv returns the variables set by the ENV instructions of a Dockerfile. Values referencing other variables
+// can't be resolved without building the image, so they are skipped.
+func dockerfileEnv(path string) ([]types.EnvVar, error) {
+	lines, err := readLines(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var env []types.EnvVar
+	for _, line := range lines {
+		instruction, args, _ := strings.Cut(line, " ")
+		if !strings.EqualFold(instruction, "ENV") || strings.HasSuffix(args, "\\") {
+			continue
+		}
+
+		words := splitWords(strings.TrimSpace(args))
+		if len(words) == 0 {
+			continue
+		}
+
+		var pairs [][2]string
+		if name, value, found := strings.Cut(words[0], "="); found {
+			pairs = append(pairs, [2]string{name, value})
+			for _, word := range words[1:] {
+				name, value, _ := strings.Cut(word, "=")
+				pairs = append(pairs, [2]string{name, value})
+			}
+		} else {
+			// legacy ENV <key> <value> form
+			pairs = append(pairs, [2]string{words[0], strings.Join(words[1:], " ")})
+		}
+
+		for _, pair := range pairs {
+			if pair[0] != "" && !strings.Contains(pair[1], "$") {
+				env = overrideEnv(env, []types.EnvVar{{Name: pair[0], Value: pair[1]}})
+			}
+		}
+	}
+	return env, nil
+}
+
+// splitWords splits s on whitespace, keeping quoted strings together and removing their quotes
+func splitWords(s string) []string {
+	var (
+		words   []string
+		current strings.Builder
+		quote   rune
+		inWord  bool
+	)
+	for _, r := range s {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			current.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, current.String())
+	}
+	return words
+}
+
+// ProjectToml renders the scaffold as a project.toml using schema version 0.2
+func (s Scaffold) ProjectToml() string {
+	var b strings.Builder
+	if len(s.Languages) > 0 {
+		fmt.Fprintf(&b, "# Generated by `pack project init` for a %s app.\n", strings.Join(s.Languages, ", "))
+	} else {
+		b.WriteString("# Generated by `pack project init`.\n")
+	}
+	b.WriteString("# See https://buildpacks.io/docs/reference/config/project-descriptor/ for all options.\n\n")
+
+	b.WriteString("[_]\n")
+	b.WriteString("schema-version = \"0.2\"\n")
+	fmt.Fprintf(&b, "id = %s\n", strconv.Quote(s.ID))
+	fmt.Fprintf(&b, "name = %s\n", strconv.Quote(s.ID))
+
+	b.WriteString("\n[io.buildpacks]\n")
+	if s.Builder != "" {
+		fmt.Fprintf(&b, "builder = %s\n", strconv.Quote(s.Builder))
+	} else {
+		b.WriteString("# builder = \"<builder image>\"\n")
+	}
+	if len(s.Exclude) > 0 {
+		b.WriteString("exclude = [\n")
+		for _, pattern := range s.Exclude {
+			fmt.Fprintf(&b, "  %s,\n", strconv.Quote(pattern))
+		}
+		b.WriteString("]\n")
+	}
+
+	if len(s.Env) > 0 {
+		if s.Dockerfile != "" {
+			fmt.Fprintf(&b, "\n# Set by the ENV instructions of the %s\n", s.Dockerfile)
+		}
+		for i, env := range s.Env {
+			if i > 0 || s.Dockerfile == "" {
+				b.WriteString("\n")
+			}
+			b.WriteString("[[io.buildpacks.build.env]]\n")
+			fmt.Fprintf(&b, "name = %s\n", strconv.Quote(env.Name))
+			fmt.Fprintf(&b, "value = %s\n", strconv.Quote(env.Value))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,109 @@
+package project
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/project/types"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestScaffold(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "Scaffold", testScaffold, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testScaffold(t *testing.T, when spec.G, it spec.S) {
+	var appDir string
+
+	it.Before(func() {
+		tmpDir, err := os.MkdirTemp("", "scaffold")
+		h.AssertNil(t, err)
+		appDir = filepath.Join(tmpDir, "My App")
+		h.AssertNil(t, os.Mkdir(appDir, 0755))
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.RemoveAll(filepath.Dir(appDir)))
+	})
+
+	writeFile := func(name, contents string) {
+		h.AssertNil(t, os.WriteFile(filepath.Join(appDir, name), []byte(contents), 0600))
+	}
+
+	when("#NewScaffold", func() {
+		it("detects languages from marker files", func() {
+			writeFile("package.json", "{}")
+			writeFile("app.csproj", "<Project/>")
+
+			scaffold, err := NewScaffold(appDir)
+			h.AssertNil(t, err)
+			h.AssertEq(t, scaffold.ID, "my-app")
+			h.AssertEq(t, scaffold.Languages, []string{"Node.js", ".NET"})
+			h.AssertEq(t, scaffold.Exclude, []string{"node_modules", "bin", "obj"})
+			h.AssertEq(t, scaffold.Dockerfile, "")
+		})
+
+		it("uses the env of a Dockerfile and the patterns of a .dockerignore", func() {
+			writeFile("Dockerfile", `FROM golang
+ENV PORT=8080 GREETING="hello world"
+env LEGACY some value
+ENV PATH=$PATH:/app/bin
+ENV PORT=9090
+RUN go build
+`)
+			writeFile(".dockerignore", "# comment\n*.md\n!README.md\nnode_modules\n[z-a\n")
+			writeFile("package.json", "{}")
+
+			scaffold, err := NewScaffold(appDir)
+			h.AssertNil(t, err)
+			h.AssertEq(t, scaffold.Dockerfile, "Dockerfile")
+			h.AssertEq(t, scaffold.Env, []types.EnvVar{
+				{Name: "GREETING", Value: "hello world"},
+				{Name: "LEGACY", Value: "some value"},
+				{Name: "PORT", Value: "9090"},
+			})
+			h.AssertEq(t, scaffold.Exclude, []string{"node_modules", "Dockerfile", ".dockerignore", "*.md", "!README.md"})
+		})
+
+		it("fails if the app path isn't a directory", func() {
+			_, err := NewScaffold(filepath.Join(appDir, "missing"))
+			h.AssertError(t, err, "is not a directory")
+		})
+	})
+
+	when("#ProjectToml", func() {
+		it("renders a valid project descriptor", func() {
+			scaffold := Scaffold{
+				ID:         "my-app",
+				Languages:  []string{"Go"},
+				Dockerfile: "Dockerfile",
+				Builder:    "example/builder",
+				Env:        []types.EnvVar{{Name: "GREETING", Value: `say "hi"`}, {Name: "PORT", Value: "8080"}},
+				Exclude:    []string{"Dockerfile", "*.md"},
+			}
+			writeFile("project.toml", scaffold.ProjectToml())
+
+			descriptor, err := ReadProjectDescriptor(filepath.Join(appDir, "project.toml"), logging.NewLogWithWriters(io.Discard, io.Discard))
+			h.AssertNil(t, err)
+			h.AssertEq(t, descriptor.SchemaVersion.String(), "0.2")
+			h.AssertEq(t, descriptor.Project.ID, "my-app")
+			h.AssertEq(t, descriptor.Build.Builder, "example/builder")
+			h.AssertEq(t, descriptor.Build.Exclude, []string{"Dockerfile", "*.md"})
+			h.AssertEq(t, descriptor.Build.Env, scaffold.Env)
+		})
+
+		it("leaves the builder commented out when there is none", func() {
+			h.AssertContains(t, Scaffold{ID: "my-app"}.ProjectToml(), "# builder = \"<builder image>\"")
+		})
+	})
+}
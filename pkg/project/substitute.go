@@ -0,0 +1,217 @@
+package project
+
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/project/types"
+)
+
+// variablePattern matches ${env:NAME}, ${env:NAME:-default} and ${git:KEY}, as well as the escaped $${...} form
+var variablePattern = regexp.MustCompile(`\$?\$\{([a-z]+):([^}:]*)(:-([^}]*))?\}`)
+
+// Substitute resolves the variables in the values of the descriptor that are used at build time: the builder, the
+// project version and source URL, build env values, label and annotation values and the image, builder and env values
+// of apps.
+//
+// Supported variables are:
+//   - ${env:NAME}, the value of an environment variable, which must be set unless a default is given with
+//     ${env:NAME:-default}
+//   - ${git:sha}, ${git:short-sha}, ${git:branch} and ${git:tag}, resolved from the git repository containing dir.
+//     A default can be given as well, e.g. ${git:tag:-latest} when HEAD isn't tagged
+//
+// A variable is escaped with an additional '$', e.g. $${env:NAME} resolves to ${env:NAME}.
+func Substitute(descriptor types.Descriptor, dir string) (types.Descriptor, error) {
+	return substitute(descriptor, &substituter{dir: dir, lookupEnv: os.LookupEnv})
+}
+
+// SubstituteWithoutEnv resolves the variables of the descriptor like Substitute, without reading the environment, for
+// descriptors that aren't trusted with its values, e.g. the ones of remote sources. Env variables resolve to their
+// default, and error without one.
+func SubstituteWithoutEnv(descriptor types.Descriptor, dir string) (types.Descriptor, error) {
+	return substitute(descriptor, &substituter{dir: dir})
+}
+
+func substitute(descriptor types.Descriptor, s *substituter) (types.Descriptor, error) {
+
+	descriptor.Build.Builder = s.resolve(descriptor.Build.Builder)
+	descriptor.Project.Version = s.resolve(descriptor.Project.Version)
+	descriptor.Project.SourceURL = s.resolve(descriptor.Project.SourceURL)
+	descriptor.Build.Env = s.resolveEnv(descriptor.Build.Env)
+	descriptor.Build.Labels = s.resolveValues(descriptor.Build.Labels)
+	descriptor.Build.Annotations = s.resolveValues(descriptor.Build.Annotations)
+
+	if len(descriptor.Build.Profiles) > 0 {
+		profiles := map[string]types.Profile{}
+		for name, profile := range descriptor.Build.Profiles {
+			profile.Env = s.resolveEnv(profile.Env)
+			profiles[name] = profile
+		}
+		descriptor.Build.Profiles = profiles
+	}
+
+	if len(descriptor.Build.Apps) > 0 {
+		apps := make([]types.App, len(descriptor.Build.Apps))
+		for i, app := range descriptor.Build.Apps {
+			app.Image = s.resolve(app.Image)
+			app.Builder = s.resolve(app.Builder)
+			app.Env = s.resolveEnv(app.Env)
+			apps[i] = app
+		}
+		descriptor.Build.Apps = apps
+	}
+
+	if s.err != nil {
+		return types.Descriptor{}, errors.Wrap(s.err, "substituting project descriptor variables")
+	}
+	return descriptor, nil
+}
+
+type substituter struct {
+	dir string
+	// lookupEnv is nil when the environment isn't read
+	lookupEnv func(string) (string, bool)
+	git       map[string]string
+	err       error
+}
+
+func (s *substituter) resolveEnv(env []types.EnvVar) []types.EnvVar {
+	if len(env) == 0 {
+		return env
+	}
+	resolved := make([]types.EnvVar, len(env))
+	for i, envVar := range env {
+		resolved[i] = types.EnvVar{Name: envVar.Name, Value: s.resolve(envVar.Value)}
+	}
+	return resolved
+}
+
+func (s *substituter) resolveValues(values map[string]string) map[string]string {
+	if len(values) == 0 {
+		return values
+	}
+	resolved := make(map[string]string, len(values))
+	for key, value := range values {
+		resolved[key] = s.resolve(value)
+	}
+	return resolved
+}
+
+// resolve substitutes the variables of value, recording the first error
+func (s *substituter) resolve(value string) string {
+	return variablePattern.ReplaceAllStringFunc(value, func(match string) string {
+		if strings.HasPrefix(match, "$$") {
+			return match[1:]
+		}
+		if s.err != nil {
+			return match
+		}
+
+		groups := variablePattern.FindStringSubmatch(match)
+		source, name, hasDefault, defaultValue := groups[1], groups[2], groups[3] != "", groups[4]
+		switch source {
+		case "env":
+			if s.lookupEnv == nil {
+				if hasDefault {
+					return defaultValue
+				}
+				s.err = errors.Errorf("environment variable %s referenced by %s is not read for this project descriptor", style.Symbol(name), style.Symbol(match))
+				break
+			}
+			if resolved, ok := s.lookupEnv(name); ok {
+				return resolved
+			}
+			if hasDefault {
+				return defaultValue
+			}
+			s.err = errors.Errorf("environment variable %s referenced by %s is not set", style.Symbol(name), style.Symbol(match))
+		case "git":
+			resolved, err := s.gitValue(name)
+			if err == nil {
+				return resolved
+			}
+			if hasDefault {
+				return defaultValue
+			}
+			s.err = errors.Wrapf(err, "resolving %s", style.Symbol(match))
+		default:
+			s.err = errors.Errorf("unknown variable source %s in %s, must be one of env or git", style.Symbol(source), style.Symbol(match))
+		}
+		return match
+	})
+}
+
+func (s *substituter) gitValue(key string) (string, error) {
+	if s.git == nil {
+		values, err := gitValues(s.dir)
+		if err != nil {
+			return "", err
+		}
+		s.git = values
+	}
+
+	value, ok := s.git[key]
+	switch {
+	case !ok:
+		return "", errors.Errorf("unknown git variable %s, must be one of sha, short-sha, branch or tag", style.Symbol(key))
+	case value == "":
+		return "", errors.Errorf("no git %s found for HEAD", key)
+	}
+	return value, nil
+}
+
+// gitValues returns the git variables of the repository containing dir
+func gitValues(dir string) (map[string]string, error) {
+	repo, err := git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, errors.Wrapf(err, "opening git repository at %s", style.Symbol(dir))
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, errors.Wrap(err, "reading git HEAD")
+	}
+
+	sha := head.Hash().String()
+	values := map[string]string{
+		"sha":       sha,
+		"short-sha": sha[:7],
+		"branch":    "",
+		"tag":       "",
+	}
+	if head.Name().IsBranch() {
+		values["branch"] = head.Name().Short()
+	}
+
+	tags, err := repo.Tags()
+	if err != nil {
+		return nil, errors.Wrap(err, "reading git tags")
+	}
+	var headTags []string
+	_ = tags.ForEach(func(ref *plumbing.Reference) error {
+		target := ref.Hash()
+		if tag, err := repo.TagObject(target); err == nil {
+			target = tag.Target
+		}
+		if target == head.Hash() {
+			headTags = append(headTags, ref.Name().Short())
+		}
+		return nil
+	})
+	if len(headTags) > 0 {
+		// the greatest of several tags is used, so that the result doesn't depend on the order they are listed in
+		latest := headTags[0]
+		for _, tag := range headTags[1:] {
+			if tag > latest {
+				latest = tag
+			}
+		}
+		values["tag"] = latest
+	}
+	return values, nil
+}
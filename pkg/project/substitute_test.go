@@ -0,0 +1,192 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/project/types"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestSubstitute(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "Substitute", testSubstitute, spec.Sequential(), spec.Report(report.Terminal{}))
+}
+
+func testSubstitute(t *testing.T, when spec.G, it spec.S) {
+	var dir string
+
+	it.Before(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "substitute")
+		h.AssertNil(t, err)
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.RemoveAll(dir))
+	})
+
+	commit := func(repo *git.Repository) plumbing.Hash {
+		worktree, err := repo.Worktree()
+		h.AssertNil(t, err)
+		h.AssertNil(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte(time.Now().String()), 0600))
+		_, err = worktree.Add("file.txt")
+		h.AssertNil(t, err)
+
+		signature := &object.Signature{Name: "Test Author", Email: "testauthor@test.com", When: time.Now()}
+		hash, err := worktree.Commit("test commit", &git.CommitOptions{Author: signature, Committer: signature})
+		h.AssertNil(t, err)
+		return hash
+	}
+
+	when("#Substitute", func() {
+		it("resolves env variables", func() {
+			t.Setenv("PACK_TEST_REGISTRY", "registry.example.com")
+			t.Setenv("PACK_TEST_LEVEL", "debug")
+
+			descriptor, err := Substitute(types.Descriptor{
+				Build: types.Build{
+					Builder: "${env:PACK_TEST_REGISTRY}/builder",
+					Env:     []types.EnvVar{{Name: "LOG_LEVEL", Value: "${env:PACK_TEST_LEVEL}"}},
+					Profiles: map[string]types.Profile{
+						"dev": {Env: []types.EnvVar{{Name: "REGION", Value: "${env:PACK_TEST_REGION:-eu}"}}},
+					},
+					Apps:        []types.App{{Path: "api", Image: "${env:PACK_TEST_REGISTRY}/api"}},
+					Labels:      map[string]string{"org.opencontainers.image.source": "https://${env:PACK_TEST_REGISTRY}/app"},
+					Annotations: map[string]string{"com.example.level": "${env:PACK_TEST_LEVEL}"},
+				},
+			}, dir)
+			h.AssertNil(t, err)
+			h.AssertEq(t, descriptor.Build.Builder, "registry.example.com/builder")
+			h.AssertEq(t, descriptor.Build.Env, []types.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}})
+			h.AssertEq(t, descriptor.Build.Profiles["dev"].Env, []types.EnvVar{{Name: "REGION", Value: "eu"}})
+			h.AssertEq(t, descriptor.Build.Apps[0].Image, "registry.example.com/api")
+			h.AssertEq(t, descriptor.Build.Labels, map[string]string{"org.opencontainers.image.source": "https://registry.example.com/app"})
+			h.AssertEq(t, descriptor.Build.Annotations, map[string]string{"com.example.level": "debug"})
+		})
+
+		it("errors on unset env variables without a default", func() {
+			_, err := Substitute(types.Descriptor{
+				Build: types.Build{Builder: "${env:PACK_TEST_UNSET}/builder"},
+			}, dir)
+			h.AssertError(t, err, "environment variable 'PACK_TEST_UNSET' referenced by '${env:PACK_TEST_UNSET}' is not set")
+		})
+
+		it("keeps escaped variables", func() {
+			descriptor, err := Substitute(types.Descriptor{
+				Build: types.Build{Env: []types.EnvVar{{Name: "TEMPLATE", Value: "$${env:HOME}"}}},
+			}, dir)
+			h.AssertNil(t, err)
+			h.AssertEq(t, descriptor.Build.Env[0].Value, "${env:HOME}")
+		})
+
+		it("errors on unknown variable sources", func() {
+			_, err := Substitute(types.Descriptor{
+				Project: types.Project{Version: "${vault:secret}"},
+			}, dir)
+			h.AssertError(t, err, "unknown variable source 'vault'")
+		})
+
+		when("without reading the environment", func() {
+			it("resolves env variables to their defaults without reading the environment", func() {
+				t.Setenv("PACK_TEST_TOKEN", "secret")
+
+				descriptor, err := SubstituteWithoutEnv(types.Descriptor{
+					Build: types.Build{Env: []types.EnvVar{{Name: "TOKEN", Value: "${env:PACK_TEST_TOKEN:-none}"}}},
+				}, dir)
+				h.AssertNil(t, err)
+				h.AssertEq(t, descriptor.Build.Env, []types.EnvVar{{Name: "TOKEN", Value: "none"}})
+			})
+
+			it("errors on env variables without a default", func() {
+				t.Setenv("PACK_TEST_TOKEN", "secret")
+
+				_, err := SubstituteWithoutEnv(types.Descriptor{
+					Build: types.Build{Env: []types.EnvVar{{Name: "TOKEN", Value: "${env:PACK_TEST_TOKEN}"}}},
+				}, dir)
+				h.AssertError(t, err, "environment variable 'PACK_TEST_TOKEN' referenced by '${env:PACK_TEST_TOKEN}' is not read for this project descriptor")
+			})
+		})
+
+		when("in a git repository", func() {
+			var (
+				repo *git.Repository
+				head plumbing.Hash
+			)
+
+			it.Before(func() {
+				var err error
+				repo, err = git.PlainInit(dir, false)
+				h.AssertNil(t, err)
+				head = commit(repo)
+				h.AssertNil(t, os.MkdirAll(filepath.Join(dir, "services", "api"), 0755))
+			})
+
+			it("resolves git variables from a subdirectory", func() {
+				_, err := repo.CreateTag("v1.0.0", head, nil)
+				h.AssertNil(t, err)
+				_, err = repo.CreateTag("v1.1.0", head, &git.CreateTagOptions{
+					Message: "release",
+					Tagger:  &object.Signature{Name: "Test Tagger", Email: "testtagger@test.com", When: time.Now()},
+				})
+				h.AssertNil(t, err)
+
+				descriptor, err := Substitute(types.Descriptor{
+					Project: types.Project{Version: "${git:tag}"},
+					Build: types.Build{
+						Apps: []types.App{{Path: "api", Image: "example/api:${git:short-sha}"}},
+						Env: []types.EnvVar{
+							{Name: "COMMIT", Value: "${git:sha}"},
+							{Name: "BRANCH", Value: "${git:branch}"},
+						},
+					},
+				}, filepath.Join(dir, "services", "api"))
+				h.AssertNil(t, err)
+				h.AssertEq(t, descriptor.Project.Version, "v1.1.0")
+				h.AssertEq(t, descriptor.Build.Apps[0].Image, "example/api:"+head.String()[:7])
+				h.AssertEq(t, descriptor.Build.Env, []types.EnvVar{
+					{Name: "COMMIT", Value: head.String()},
+					{Name: "BRANCH", Value: "master"},
+				})
+			})
+
+			it("uses the default when HEAD isn't tagged", func() {
+				descriptor, err := Substitute(types.Descriptor{
+					Project: types.Project{Version: "${git:tag:-dev}"},
+				}, dir)
+				h.AssertNil(t, err)
+				h.AssertEq(t, descriptor.Project.Version, "dev")
+
+				_, err = Substitute(types.Descriptor{
+					Project: types.Project{Version: "${git:tag}"},
+				}, dir)
+				h.AssertError(t, err, "no git tag found for HEAD")
+			})
+
+			it("errors on unknown git variables", func() {
+				_, err := Substitute(types.Descriptor{
+					Project: types.Project{Version: "${git:author}"},
+				}, dir)
+				h.AssertError(t, err, "unknown git variable 'author'")
+			})
+		})
+
+		it("errors on git variables outside of a git repository", func() {
+			_, err := Substitute(types.Descriptor{
+				Project: types.Project{Version: "${git:sha}"},
+			}, dir)
+			h.AssertError(t, err, "opening git repository")
+		})
+	})
+}
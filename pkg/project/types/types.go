@@ -4,17 +4,27 @@ import (
 	"github.com/buildpacks/lifecycle/api"
 )
 
+// Shells that inline buildpack scripts can be run with. An absolute path to an interpreter is accepted as well.
+const (
+	ShellSh         = "sh"
+	ShellBash       = "bash"
+	ShellPowerShell = "powershell"
+	ShellCmd        = "cmd"
+)
+
 type Script struct {
-	API    string `toml:"api"`
-	Inline string `toml:"inline"`
-	Shell  string `toml:"shell"`
+	API    string   `toml:"api"`
+	Inline string   `toml:"inline"`
+	Files  []string `toml:"files"`
+	Shell  string   `toml:"shell"`
 }
 
 type Buildpack struct {
-	ID      string `toml:"id"`
-	Version string `toml:"version"`
-	URI     string `toml:"uri"`
-	Script  Script `toml:"script"`
+	ID       string `toml:"id"`
+	Version  string `toml:"version"`
+	URI      string `toml:"uri"`
+	Script   Script `toml:"script"`
+	Optional bool   `toml:"optional"`
 }
 
 type EnvVar struct {
@@ -30,6 +40,54 @@ type Build struct {
 	Builder    string      `toml:"builder"`
 	Pre        GroupAddition
 	Post       GroupAddition
+	Profiles   map[string]Profile `toml:"profiles"`
+	Apps       []App              `toml:"apps"`
+	Hooks      Hooks              `toml:"hooks"`
+	Scan       Scan               `toml:"scan"`
+
+	// Labels are set on the config of the image, Annotations on its manifest when published
+	Labels      map[string]string `toml:"labels"`
+	Annotations map[string]string `toml:"annotations"`
+}
+
+// Hooks are commands run on the host, through its shell, from the directory of the descriptor
+type Hooks struct {
+	// PreBuild hooks run before the app source is sent to the lifecycle
+	PreBuild []Hook `toml:"pre-build"`
+
+	// PostBuild hooks run after a successful build
+	PostBuild []Hook `toml:"post-build"`
+}
+
+type Hook struct {
+	Command string `toml:"command"`
+}
+
+// Scan configures the vulnerability scan of the image after a successful build. Values that are set take precedence
+// over the scan configuration of pack.
+type Scan struct {
+	// Scanner is the name of the scanner, grype or trivy
+	Scanner string `toml:"scanner"`
+
+	// Command is the path of the scanner executable, when it isn't the name of the scanner in PATH
+	Command string `toml:"command"`
+
+	// FailOn is the lowest severity of vulnerabilities that fails the build, vulnerabilities are only reported when empty
+	FailOn string `toml:"fail-on"`
+}
+
+// App is one of several applications built from the same descriptor, e.g. in a monorepo
+type App struct {
+	// Path of the application source, relative to the descriptor
+	Path    string   `toml:"path"`
+	Image   string   `toml:"image"`
+	Builder string   `toml:"builder"`
+	Env     []EnvVar `toml:"env"`
+}
+
+// Profile is a named set of build configuration, e.g. "dev" or "prod", that is applied on top of the base configuration
+type Profile struct {
+	Env []EnvVar `toml:"env"`
 }
 
 type Project struct {
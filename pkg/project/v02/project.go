@@ -16,6 +16,14 @@ type Buildpacks struct {
 	Builder string              `toml:"builder"`
 	Pre     types.GroupAddition `toml:"pre"`
 	Post    types.GroupAddition `toml:"post"`
+
+	Profiles map[string]types.Profile `toml:"profiles"`
+	Apps     []types.App              `toml:"apps"`
+	Hooks    types.Hooks              `toml:"hooks"`
+	Scan     types.Scan               `toml:"scan"`
+
+	Labels      map[string]string `toml:"labels"`
+	Annotations map[string]string `toml:"annotations"`
 }
 
 type Build struct {
@@ -63,17 +71,28 @@ func NewDescriptor(projectTomlContents string) (types.Descriptor, toml.MetaData,
 
 	return types.Descriptor{
 		Project: types.Project{
-			Name:     versionedDescriptor.Project.Name,
-			Licenses: versionedDescriptor.Project.Licenses,
+			ID:               versionedDescriptor.Project.ID,
+			Name:             versionedDescriptor.Project.Name,
+			Version:          versionedDescriptor.Project.Version,
+			Authors:          versionedDescriptor.Project.Authors,
+			DocumentationURL: versionedDescriptor.Project.DocumentationURL,
+			SourceURL:        versionedDescriptor.Project.SourceURL,
+			Licenses:         versionedDescriptor.Project.Licenses,
 		},
 		Build: types.Build{
-			Include:    versionedDescriptor.IO.Buildpacks.Include,
-			Exclude:    versionedDescriptor.IO.Buildpacks.Exclude,
-			Buildpacks: versionedDescriptor.IO.Buildpacks.Group,
-			Env:        env,
-			Builder:    versionedDescriptor.IO.Buildpacks.Builder,
-			Pre:        versionedDescriptor.IO.Buildpacks.Pre,
-			Post:       versionedDescriptor.IO.Buildpacks.Post,
+			Include:     versionedDescriptor.IO.Buildpacks.Include,
+			Exclude:     versionedDescriptor.IO.Buildpacks.Exclude,
+			Buildpacks:  versionedDescriptor.IO.Buildpacks.Group,
+			Env:         env,
+			Builder:     versionedDescriptor.IO.Buildpacks.Builder,
+			Pre:         versionedDescriptor.IO.Buildpacks.Pre,
+			Post:        versionedDescriptor.IO.Buildpacks.Post,
+			Profiles:    versionedDescriptor.IO.Buildpacks.Profiles,
+			Apps:        versionedDescriptor.IO.Buildpacks.Apps,
+			Hooks:       versionedDescriptor.IO.Buildpacks.Hooks,
+			Scan:        versionedDescriptor.IO.Buildpacks.Scan,
+			Labels:      versionedDescriptor.IO.Buildpacks.Labels,
+			Annotations: versionedDescriptor.IO.Buildpacks.Annotations,
 		},
 		Metadata:      versionedDescriptor.Project.Metadata,
 		SchemaVersion: api.MustParse("0.2"),
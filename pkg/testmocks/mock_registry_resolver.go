@@ -7,6 +7,7 @@ package testmocks
 import (
 	reflect "reflect"
 
+	buildpack "github.com/buildpacks/pack/pkg/buildpack"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -34,16 +35,16 @@ func (m *MockRegistryResolver) EXPECT() *MockRegistryResolverMockRecorder {
 }
 
 // Resolve mocks base method.
-func (m *MockRegistryResolver) Resolve(arg0, arg1 string) (string, error) {
+func (m *MockRegistryResolver) Resolve(arg0, arg1 string, arg2 buildpack.RegistryLookup) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Resolve", arg0, arg1)
+	ret := m.ctrl.Call(m, "Resolve", arg0, arg1, arg2)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Resolve indicates an expected call of Resolve.
-func (mr *MockRegistryResolverMockRecorder) Resolve(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockRegistryResolverMockRecorder) Resolve(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockRegistryResolver)(nil).Resolve), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockRegistryResolver)(nil).Resolve), arg0, arg1, arg2)
 }